@@ -0,0 +1,145 @@
+package batchinference
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llmos-ai/llmos-operator/cmd/common"
+	apidatasetversion "github.com/llmos-ai/llmos-operator/pkg/api/datasetversion"
+	"github.com/llmos-ai/llmos-operator/pkg/batchinference"
+	"github.com/llmos-ai/llmos-operator/pkg/config"
+)
+
+const inputFileName = "input.jsonl"
+
+var (
+	name      string
+	endpoint  string
+	model     string
+	workDir   string
+	shardSize int
+)
+
+func NewBatchInference() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batchinference",
+		Short: "Run the model service of a batch inference job over the records of the input dataset version",
+		RunE:  run,
+	}
+
+	cmd.PersistentFlags().StringVar(&name, "name", "", "namespace/name of the batch inference job")
+	cmd.PersistentFlags().StringVar(&endpoint, "endpoint", "", "base url of the model service, e.g., http://modelservice-foo.default.svc:8000")
+	cmd.PersistentFlags().StringVar(&model, "model", "", "served model name, the first served model is used if not specified")
+	cmd.PersistentFlags().StringVar(&workDir, "work-dir", os.TempDir(), "directory to download the input file to")
+	cmd.PersistentFlags().IntVar(&shardSize, "shard-size", batchinference.DefaultShardSize, "number of records written to each output file")
+
+	_ = cmd.MarkPersistentFlagRequired("name")
+	_ = cmd.MarkPersistentFlagRequired("endpoint")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	config.InitLogs(config.CommonOptions{
+		Debug:     viper.GetBool("debug"),
+		Trace:     viper.GetBool("trace"),
+		LogFormat: viper.GetString("log_format"),
+	})
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tmp := strings.Split(name, "/")
+	if len(tmp) != 2 {
+		return fmt.Errorf("invalid batch inference job name: %s", name)
+	}
+	namespace, jobName := tmp[0], tmp[1]
+
+	c, err := common.NewClient(viper.GetString("kubeconfig"))
+	if err != nil {
+		return err
+	}
+	bij, err := c.LLMInterface.BatchInferenceJob().Get(namespace, jobName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get batch inference job %s: %w", name, err)
+	}
+	if bij.Status.OutputDatasetVersion == "" {
+		return fmt.Errorf("output dataset version of batch inference job %s is not created", name)
+	}
+
+	var temperature *float64
+	if bij.Spec.Temperature != "" {
+		t, err := strconv.ParseFloat(bij.Spec.Temperature, 64)
+		if err != nil {
+			return fmt.Errorf("invalid temperature %s: %w", bij.Spec.Temperature, err)
+		}
+		temperature = &t
+	}
+
+	mgr := c.RegistryManager()
+	inputRegistry, inputRootPath, err := apidatasetversion.GetDatasetVersionRegistryAndRootPath(c.GetDatasetVersion,
+		namespace, bij.Spec.Input.DatasetVersion)
+	if err != nil {
+		return err
+	}
+	inputBackend, err := mgr.NewBackendFromRegistry(ctx, inputRegistry)
+	if err != nil {
+		return fmt.Errorf("failed to create backend of input: %w", err)
+	}
+	outputRegistry, outputRootPath, err := apidatasetversion.GetDatasetVersionRegistryAndRootPath(
+		c.GetDatasetVersion, namespace, bij.Status.OutputDatasetVersion)
+	if err != nil {
+		return err
+	}
+	outputBackend, err := mgr.NewBackendFromRegistry(ctx, outputRegistry)
+	if err != nil {
+		return fmt.Errorf("failed to create backend of output: %w", err)
+	}
+
+	inputFile := filepath.Join(workDir, inputFileName)
+	f, err := os.Create(inputFile)
+	if err != nil {
+		return err
+	}
+	src := path.Join(inputRootPath, bij.Spec.Input.File)
+	logrus.Infof("downloading input file %s", src)
+	err = inputBackend.Download(ctx, src, f)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to download input file %s: %w", src, err)
+	}
+
+	return batchinference.Run(ctx, batchinference.Options{
+		Endpoint:       endpoint,
+		Model:          model,
+		InputFile:      inputFile,
+		OutputDir:      outputRootPath,
+		PromptTemplate: bij.Spec.Input.PromptTemplate,
+		PromptField:    bij.Spec.Input.PromptField,
+		SystemPrompt:   bij.Spec.Input.SystemPrompt,
+		OutputField:    bij.Spec.Output.OutputField,
+		MaxRecords:     bij.Spec.Input.MaxRecords,
+		Concurrency:    int(bij.Spec.Concurrency),
+		MaxRetries:     int(bij.Spec.MaxRetries),
+		MaxTokens:      int(bij.Spec.MaxTokens),
+		Temperature:    temperature,
+		RequestTimeout: bij.Spec.RequestTimeout.Duration,
+		ShardSize:      shardSize,
+	}, outputBackend, &batchinference.StatusReporter{
+		Client:    c.LLMInterface.BatchInferenceJob(),
+		Namespace: namespace,
+		Name:      jobName,
+	})
+}
@@ -0,0 +1,93 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/benchmark"
+	"github.com/llmos-ai/llmos-operator/pkg/config"
+)
+
+var (
+	endpoint          string
+	model             string
+	benchmarkType     string
+	datasetDir        string
+	promptField       string
+	referenceField    string
+	maxPrompts        int
+	concurrencyLevels []int
+	duration          time.Duration
+	maxTokens         int
+	requestTimeout    time.Duration
+	reportPath        string
+)
+
+func NewBenchmark() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Benchmark the OpenAI compatible API of a model service",
+		RunE:  run,
+	}
+
+	cmd.PersistentFlags().StringVar(&endpoint, "endpoint", "", "base url of the model service, e.g., http://modelservice-foo.default.svc:8000")
+	cmd.PersistentFlags().StringVar(&model, "model", "", "served model name, the first served model is used if not specified")
+	cmd.PersistentFlags().StringVar(&benchmarkType, "type", string(mlv1.ModelBenchmarkTypePerformance), fmt.Sprintf("benchmark type (%s or %s)", mlv1.ModelBenchmarkTypePerformance, mlv1.ModelBenchmarkTypeAccuracy))
+	cmd.PersistentFlags().StringVar(&datasetDir, "dataset-dir", "", "directory of the json or jsonl files of the prompts")
+	cmd.PersistentFlags().StringVar(&promptField, "prompt-field", "prompt", "field of the prompts in the dataset records")
+	cmd.PersistentFlags().StringVar(&referenceField, "reference-field", "", "field of the reference answers in the dataset records")
+	cmd.PersistentFlags().IntVar(&maxPrompts, "max-prompts", 0, "maximum number of prompts to use, 0 means all prompts")
+	cmd.PersistentFlags().IntSliceVar(&concurrencyLevels, "concurrency", []int{1}, "numbers of concurrent requests")
+	cmd.PersistentFlags().DurationVar(&duration, "duration", time.Minute, "duration of each performance benchmark run")
+	cmd.PersistentFlags().IntVar(&maxTokens, "max-tokens", 256, "maximum number of tokens to generate for each request")
+	cmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 10*time.Minute, "timeout of each request")
+	cmd.PersistentFlags().StringVar(&reportPath, "report-path", benchmark.DefaultReportPath, "file path to write the benchmark report")
+
+	_ = cmd.MarkPersistentFlagRequired("endpoint")
+	_ = cmd.MarkPersistentFlagRequired("dataset-dir")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	opts := config.CommonOptions{
+		Debug:     viper.GetBool("debug"),
+		Trace:     viper.GetBool("trace"),
+		LogFormat: viper.GetString("log_format"),
+	}
+	config.InitLogs(opts)
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	report, err := benchmark.Run(ctx, benchmark.Options{
+		Endpoint:          endpoint,
+		Model:             model,
+		Type:              mlv1.ModelBenchmarkType(benchmarkType),
+		DatasetDir:        datasetDir,
+		PromptField:       promptField,
+		ReferenceField:    referenceField,
+		MaxPrompts:        maxPrompts,
+		ConcurrencyLevels: concurrencyLevels,
+		Duration:          duration,
+		MaxTokens:         maxTokens,
+		RequestTimeout:    requestTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run benchmark: %w", err)
+	}
+
+	if err = benchmark.WriteReport(reportPath, report); err != nil {
+		return err
+	}
+	logrus.Infof("benchmark report is written to %s", reportPath)
+	return nil
+}
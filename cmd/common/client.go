@@ -0,0 +1,85 @@
+package common
+
+import (
+	"fmt"
+
+	ctlcore "github.com/rancher/wrangler/v3/pkg/generated/controllers/core"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	ctlagent "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/agent.llmos.ai"
+	ctlagentv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/agent.llmos.ai/v1"
+	ctlmgmt "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
+	ctlml "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	pkgreg "github.com/llmos-ai/llmos-operator/pkg/registry"
+	"github.com/llmos-ai/llmos-operator/pkg/server"
+)
+
+// Client is the kubernetes client shared by the commands running in the jobs and sidecars of the operator, e.g.,
+// the downloader, the fine-tuning and the batch inference jobs
+type Client struct {
+	AgentInterface      ctlagentv1.Interface
+	LLMInterface        ctlmlv1.Interface
+	CoreInterface       ctlcorev1.Interface
+	ManagementInterface ctlmgmtv1.Interface
+}
+
+func NewClient(kubeConfig string) (*Client, error) {
+	clientConfig, err := server.GetConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
+	}
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get REST config: %w", err)
+	}
+
+	agent, err := ctlagent.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent client: %w", err)
+	}
+	llm, err := ctlml.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ml client: %w", err)
+	}
+	core, err := ctlcore.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create core client: %w", err)
+	}
+	mgmt, err := ctlmgmt.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create management client: %w", err)
+	}
+
+	return &Client{
+		AgentInterface:      agent.Agent().V1(),
+		LLMInterface:        llm.Ml().V1(),
+		CoreInterface:       core.Core().V1(),
+		ManagementInterface: mgmt.Management().V1(),
+	}, nil
+}
+
+// RegistryManager returns the manager creating the backends of the registries with the client
+func (c *Client) RegistryManager() *pkgreg.Manager {
+	return pkgreg.NewManager(c.GetSecret, c.GetRegistry)
+}
+
+func (c *Client) GetModel(namespace, name string) (*mlv1.Model, error) {
+	return c.LLMInterface.Model().Get(namespace, name, metav1.GetOptions{})
+}
+
+func (c *Client) GetDatasetVersion(namespace, name string) (*mlv1.DatasetVersion, error) {
+	return c.LLMInterface.DatasetVersion().Get(namespace, name, metav1.GetOptions{})
+}
+
+func (c *Client) GetRegistry(name string) (*mlv1.Registry, error) {
+	return c.LLMInterface.Registry().Get(name, metav1.GetOptions{})
+}
+
+func (c *Client) GetSecret(namespace, name string) (*corev1.Secret, error) {
+	return c.CoreInterface.Secret().Get(namespace, name, metav1.GetOptions{})
+}
@@ -0,0 +1,80 @@
+package datacollection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llmos-ai/llmos-operator/cmd/common"
+	"github.com/llmos-ai/llmos-operator/pkg/config"
+	"github.com/llmos-ai/llmos-operator/pkg/datacollection"
+)
+
+var (
+	name           string
+	sources        []string
+	credentialsDir string
+	allowedCIDRs   string
+)
+
+func NewDataCollection() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "datacollection",
+		Short: "Sync the sources of a data collection into its registry",
+		RunE:  run,
+	}
+
+	cmd.PersistentFlags().StringVar(&name, "name", "", "namespace/name of the data collection")
+	cmd.PersistentFlags().StringSliceVar(&sources, "sources", nil, "names of the sources to sync")
+	cmd.PersistentFlags().StringVar(&credentialsDir, "credentials-dir", "/credentials", "directory of the credential secrets mounted by the source names")
+	cmd.PersistentFlags().StringVar(&allowedCIDRs, "allowed-cidrs", "", "comma separated private networks the sources can connect to")
+
+	_ = cmd.MarkPersistentFlagRequired("name")
+	_ = cmd.MarkPersistentFlagRequired("sources")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	config.InitLogs(config.CommonOptions{
+		Debug:     viper.GetBool("debug"),
+		Trace:     viper.GetBool("trace"),
+		LogFormat: viper.GetString("log_format"),
+	})
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := datacollection.SetAllowedCIDRs(allowedCIDRs); err != nil {
+		return err
+	}
+
+	tmp := strings.Split(name, "/")
+	if len(tmp) != 2 {
+		return fmt.Errorf("invalid data collection name: %s", name)
+	}
+	namespace, dcName := tmp[0], tmp[1]
+
+	c, err := common.NewClient(viper.GetString("kubeconfig"))
+	if err != nil {
+		return err
+	}
+	dc, err := c.AgentInterface.DataCollection().Get(namespace, dcName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get data collection %s: %w", name, err)
+	}
+
+	mgr := c.RegistryManager()
+	b, err := mgr.NewBackendFromRegistry(ctx, dc.Spec.Registry)
+	if err != nil {
+		return fmt.Errorf("failed to create backend from registry %s: %w", dc.Spec.Registry, err)
+	}
+
+	return datacollection.SyncSources(ctx, c.AgentInterface.DataCollection(), dc, sources, credentialsDir, b)
+}
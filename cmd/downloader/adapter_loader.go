@@ -0,0 +1,67 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/llmos-ai/llmos-operator/pkg/adapterloader"
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/config"
+)
+
+var (
+	adapterConfigDir string
+	adapterOutputDir string
+	adapterEndpoint  string
+	syncInterval     time.Duration
+)
+
+func NewAdapterLoader() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "load-adapters",
+		Short: "Download the LoRA adapters of a model service and load them into the vLLM server at runtime",
+		RunE:  runAdapterLoader,
+	}
+
+	cmd.PersistentFlags().StringVar(&adapterConfigDir, "config-dir", "", "Directory of the mounted adapters configMap")
+	cmd.PersistentFlags().StringVar(&adapterOutputDir, "output-dir", "", "Directory to save the adapters, which is shared with the vLLM server")
+	cmd.PersistentFlags().StringVar(&adapterEndpoint, "endpoint", "http://localhost:8000", "base url of the vLLM server")
+	cmd.PersistentFlags().DurationVar(&syncInterval, "interval", 10*time.Second, "Interval to sync the adapters")
+	cmd.PersistentFlags().IntVar(&threadness, "threadness", 3, "Number of threads during download files")
+
+	_ = cmd.MarkPersistentFlagRequired("config-dir")
+	_ = cmd.MarkPersistentFlagRequired("output-dir")
+
+	return cmd
+}
+
+func runAdapterLoader(cmd *cobra.Command, _ []string) error {
+	config.InitLogs(config.CommonOptions{
+		Debug:     viper.GetBool("debug"),
+		Trace:     viper.GetBool("trace"),
+		LogFormat: viper.GetString("log_format"),
+	})
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c, err := newClient(viper.GetString("kubeconfig"))
+	if err != nil {
+		return fmt.Errorf("failed to create downloader: %w", err)
+	}
+
+	logrus.Infof("Loading adapters of %s into %s", adapterConfigDir, adapterEndpoint)
+	loader := adapterloader.NewLoader(adapterConfigDir, adapterOutputDir, adapterEndpoint,
+		func(ctx context.Context, model, outputDir string) error {
+			return c.Download(ctx, "", model, outputDir, threadness, mlv1.ModelResourceName)
+		})
+	loader.Run(ctx, syncInterval)
+	return nil
+}
@@ -5,18 +5,16 @@ import (
 	"fmt"
 	"strings"
 
-	ctlcore "github.com/rancher/wrangler/v3/pkg/generated/controllers/core"
-	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
-	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/llmos-ai/llmos-operator/cmd/common"
 	apidatasetversion "github.com/llmos-ai/llmos-operator/pkg/api/datasetversion"
 	apimodel "github.com/llmos-ai/llmos-operator/pkg/api/model"
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
-	ctlml "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai"
-	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
-	pkgreg "github.com/llmos-ai/llmos-operator/pkg/registry"
-	"github.com/llmos-ai/llmos-operator/pkg/server"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/dataset"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
 )
 
 const (
@@ -26,38 +24,17 @@ const (
 	modelScopeRegistry  = "modelscope"
 )
 
+// client downloads the models and dataset versions from their registries
 type client struct {
-	LLMInterface  ctlmlv1.Interface
-	CoreInterface ctlcorev1.Interface
+	*common.Client
 }
 
 func newClient(kubeConfig string) (*client, error) {
-	// Initialize Kubernetes client
-	clientConfig, err := server.GetConfig(kubeConfig)
+	c, err := common.NewClient(kubeConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
+		return nil, err
 	}
-
-	// Get REST config for dynamic client
-	restConfig, err := clientConfig.ClientConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get REST config: %w", err)
-	}
-
-	llm, err := ctlml.NewFactoryFromConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create ml client: %w", err)
-	}
-
-	core, err := ctlcore.NewFactoryFromConfig(restConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create core client: %w", err)
-	}
-
-	return &client{
-		LLMInterface:  llm.Ml().V1(),
-		CoreInterface: core.Core().V1(),
-	}, nil
+	return &client{Client: c}, nil
 }
 
 func (c *client) Download(ctx context.Context, registry, resourceName, outputDir string, threadness int, resourceType string) error {
@@ -72,24 +49,30 @@ func (c *client) Download(ctx context.Context, registry, resourceName, outputDir
 	namespace, name := tmp[0], tmp[1]
 
 	var reg, rootPath string
+	var manifest *mlv1.DatasetManifest
 	var err error
 
 	switch resourceType {
 	case mlv1.ModelResourceName:
-		reg, rootPath, err = apimodel.GetModelRegistryAndRootPath(c.getModel, namespace, name)
+		reg, rootPath, err = apimodel.GetModelRegistryAndRootPath(c.GetModel, namespace, name)
 		if err != nil {
 			return fmt.Errorf("failed to get registry and root path of model %s/%s: %w", namespace, name, err)
 		}
 	case mlv1.DatasetVersionResourceName:
-		reg, rootPath, err = apidatasetversion.GetDatasetVersionRegistryAndRootPath(c.getDatasetVersion, namespace, name)
+		reg, rootPath, err = apidatasetversion.GetDatasetVersionRegistryAndRootPath(c.GetDatasetVersion, namespace, name)
 		if err != nil {
 			return fmt.Errorf("failed to get registry and root path of dataset version %s/%s: %w", namespace, name, err)
 		}
+		dv, err := c.GetDatasetVersion(namespace, name)
+		if err != nil {
+			return fmt.Errorf("failed to get dataset version %s/%s: %w", namespace, name, err)
+		}
+		manifest = dv.Status.Manifest
 	default:
 		return fmt.Errorf("unsupported resource type: %s", resourceType)
 	}
 
-	b, err := pkgreg.NewManager(c.getSecret, c.getRegistry).NewBackendFromRegistry(ctx, reg)
+	b, err := c.RegistryManager().NewBackendFromRegistry(ctx, reg)
 	if err != nil {
 		return fmt.Errorf("failed to create backend: %w", err)
 	}
@@ -97,21 +80,55 @@ func (c *client) Download(ctx context.Context, registry, resourceName, outputDir
 	if threadness <= 0 {
 		threadness = defaultThreadness
 	}
-	return b.IncrementalDownload(ctx, rootPath, outputDir, threadness)
-}
+	if err = b.IncrementalDownload(ctx, rootPath, outputDir, threadness); err != nil {
+		return err
+	}
 
-func (c *client) getModel(namespace, name string) (*mlv1.Model, error) {
-	return c.LLMInterface.Model().Get(namespace, name, metav1.GetOptions{})
+	if manifest != nil {
+		if err = c.verifyManifest(namespace, name, outputDir, manifest); err != nil {
+			return fmt.Errorf("failed to verify dataset version %s/%s: %w", namespace, name, err)
+		}
+	}
+	return nil
 }
 
-func (c *client) getRegistry(name string) (*mlv1.Registry, error) {
-	return c.LLMInterface.Registry().Get(name, metav1.GetOptions{})
-}
+// verifyManifest checks the downloaded files match the manifest of the published dataset version, and the
+// signature of the manifest is made by the cluster signing key if the manifest is signed or signing is enabled
+func (c *client) verifyManifest(namespace, name, outputDir string, manifest *mlv1.DatasetManifest) error {
+	signingEnabled, err := c.isSigningEnabled()
+	if err != nil {
+		return err
+	}
+	if signingEnabled || manifest.Signature != "" {
+		cm, err := c.CoreInterface.ConfigMap().Get(constant.SystemNamespaceName,
+			constant.DatasetSigningPublicKeyConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get public key: %w", err)
+		}
+		publicKey, err := dataset.ParsePublicKey([]byte(cm.Data[dataset.PublicKeyName]))
+		if err != nil {
+			return err
+		}
+		if err = dataset.VerifyManifest(manifest, namespace, name, publicKey); err != nil {
+			return err
+		}
+	}
 
-func (c *client) getDatasetVersion(namespace, name string) (*mlv1.DatasetVersion, error) {
-	return c.LLMInterface.DatasetVersion().Get(namespace, name, metav1.GetOptions{})
+	return dataset.VerifyFiles(outputDir, manifest)
 }
 
-func (c *client) getSecret(namespace, name string) (*corev1.Secret, error) {
-	return c.CoreInterface.Secret().Get(namespace, name, metav1.GetOptions{})
+// isSigningEnabled returns whether the manifests of the dataset versions must be signed, so that the signature
+// can't be stripped from the manifest to skip the verification
+func (c *client) isSigningEnabled() (bool, error) {
+	s, err := c.ManagementInterface.Setting().Get(settings.DatasetVersionSigningEnabledName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return settings.DatasetVersionSigningEnabled.Default == constant.TrueStr, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get setting %s: %w", settings.DatasetVersionSigningEnabledName, err)
+	}
+	value := s.Value
+	if value == "" {
+		value = s.Default
+	}
+	return value == constant.TrueStr, nil
 }
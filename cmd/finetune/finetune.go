@@ -0,0 +1,134 @@
+package finetune
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llmos-ai/llmos-operator/cmd/common"
+	apimodel "github.com/llmos-ai/llmos-operator/pkg/api/model"
+	"github.com/llmos-ai/llmos-operator/pkg/config"
+	"github.com/llmos-ai/llmos-operator/pkg/finetune"
+)
+
+var (
+	name           string
+	metricsFile    string
+	outputDir      string
+	reportInterval time.Duration
+)
+
+func NewFineTune() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "finetune",
+		Short: "Report the metrics and upload the output of a fine-tuning job",
+	}
+
+	cmd.PersistentFlags().StringVar(&name, "name", "", "namespace/name of the fine-tuning job")
+	cmd.PersistentFlags().StringVar(&metricsFile, "metrics-file", "", "json lines file of the training metrics written by the trainer")
+	_ = cmd.MarkPersistentFlagRequired("name")
+	_ = cmd.MarkPersistentFlagRequired("metrics-file")
+
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report the training metrics to the status of the fine-tuning job until terminated",
+		RunE:  runReport,
+	}
+	reportCmd.Flags().DurationVar(&reportInterval, "interval", 10*time.Second, "interval to report the metrics")
+
+	uploadCmd := &cobra.Command{
+		Use:   "upload",
+		Short: "Upload the output of the trainer to the output model of the fine-tuning job",
+		RunE:  runUpload,
+	}
+	uploadCmd.Flags().StringVar(&outputDir, "output-dir", "", "output directory of the trainer")
+	_ = uploadCmd.MarkFlagRequired("output-dir")
+
+	cmd.AddCommand(reportCmd, uploadCmd)
+	return cmd
+}
+
+func setup(cmd *cobra.Command) (context.Context, *common.Client, string, string, error) {
+	config.InitLogs(config.CommonOptions{
+		Debug:     viper.GetBool("debug"),
+		Trace:     viper.GetBool("trace"),
+		LogFormat: viper.GetString("log_format"),
+	})
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tmp := strings.Split(name, "/")
+	if len(tmp) != 2 {
+		return nil, nil, "", "", fmt.Errorf("invalid fine-tuning job name: %s", name)
+	}
+
+	c, err := common.NewClient(viper.GetString("kubeconfig"))
+	if err != nil {
+		return nil, nil, "", "", err
+	}
+	return ctx, c, tmp[0], tmp[1], nil
+}
+
+// runReport runs as a sidecar of the trainer, the metrics are reported once more when it's terminated after
+// the trainer exits
+func runReport(cmd *cobra.Command, _ []string) error {
+	ctx, c, namespace, jobName, err := setup(cmd)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return finetune.ReportMetrics(c.LLMInterface.FineTuneJob(), namespace, jobName, metricsFile)
+		case <-ticker.C:
+			if err := finetune.ReportMetrics(c.LLMInterface.FineTuneJob(), namespace, jobName,
+				metricsFile); err != nil {
+				logrus.Warnf("failed to report metrics of fine-tuning job %s: %v", name, err)
+			}
+		}
+	}
+}
+
+func runUpload(cmd *cobra.Command, _ []string) error {
+	ctx, c, namespace, jobName, err := setup(cmd)
+	if err != nil {
+		return err
+	}
+
+	// the final metrics may be written after the last report of the sidecar
+	if err = finetune.ReportMetrics(c.LLMInterface.FineTuneJob(), namespace, jobName, metricsFile); err != nil {
+		logrus.Warnf("failed to report metrics of fine-tuning job %s: %v", name, err)
+	}
+
+	ftj, err := c.LLMInterface.FineTuneJob().Get(namespace, jobName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get fine-tuning job %s: %w", name, err)
+	}
+	modelName := ftj.Status.OutputModel
+	if modelName == "" {
+		return fmt.Errorf("output model of fine-tuning job %s is not created", name)
+	}
+
+	reg, rootPath, err := apimodel.GetModelRegistryAndRootPath(c.GetModel, namespace, modelName)
+	if err != nil {
+		return fmt.Errorf("failed to get registry and root path of model %s/%s: %w", namespace, modelName, err)
+	}
+	b, err := c.RegistryManager().NewBackendFromRegistry(ctx, reg)
+	if err != nil {
+		return fmt.Errorf("failed to create backend: %w", err)
+	}
+
+	return finetune.Upload(ctx, b, outputDir, rootPath)
+}
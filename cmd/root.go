@@ -5,7 +5,11 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/llmos-ai/llmos-operator/cmd/apiserver"
+	"github.com/llmos-ai/llmos-operator/cmd/batchinference"
+	"github.com/llmos-ai/llmos-operator/cmd/benchmark"
+	"github.com/llmos-ai/llmos-operator/cmd/datacollection"
 	"github.com/llmos-ai/llmos-operator/cmd/downloader"
+	"github.com/llmos-ai/llmos-operator/cmd/finetune"
 	"github.com/llmos-ai/llmos-operator/cmd/version"
 	wServer "github.com/llmos-ai/llmos-operator/cmd/webhook"
 	"github.com/llmos-ai/llmos-operator/pkg/config"
@@ -39,6 +43,11 @@ func New() *cobra.Command {
 		wServer.NewWebhookServer(),
 		version.NewVersion(),
 		downloader.NewDownloader(),
+		downloader.NewAdapterLoader(),
+		benchmark.NewBenchmark(),
+		finetune.NewFineTune(),
+		batchinference.NewBatchInference(),
+		datacollection.NewDataCollection(),
 	)
 	rootCmd.SilenceUsage = true
 	rootCmd.InitDefaultHelpCmd()
@@ -2,9 +2,11 @@
 set -e
 
 # Unified entrypoint script for llmos-operator
-# Can be used for apiserver, webhook, or downloader modes
+# Can be used for apiserver, webhook, downloader, load-adapters, benchmark, finetune, batchinference or
+# datacollection modes
 # Usage:
-#   - Set LLMOS_MODE environment variable (apiserver, webhook, download)
+#   - Set LLMOS_MODE environment variable (apiserver, webhook, download, load-adapters, benchmark, finetune,
+#     batchinference, datacollection)
 #   - Or pass mode as first argument
 #   - Defaults to apiserver if no mode specified
 
@@ -12,7 +14,7 @@ set -e
 MODE="${LLMOS_MODE:-${1:-apiserver}}"
 
 # Shift arguments if mode was passed as first argument
-if [ "$1" = "apiserver" ] || [ "$1" = "webhook" ] || [ "$1" = "download" ]; then
+if [ "$1" = "apiserver" ] || [ "$1" = "webhook" ] || [ "$1" = "download" ] || [ "$1" = "load-adapters" ] || [ "$1" = "benchmark" ] || [ "$1" = "finetune" ] || [ "$1" = "batchinference" ] || [ "$1" = "datacollection" ]; then
     shift
 fi
 
@@ -26,8 +28,23 @@ case "$MODE" in
     "download")
         exec tini -- llmos-operator download "${@}"
         ;;
+    "load-adapters")
+        exec tini -- llmos-operator load-adapters "${@}"
+        ;;
+    "benchmark")
+        exec tini -- llmos-operator benchmark "${@}"
+        ;;
+    "finetune")
+        exec tini -- llmos-operator finetune "${@}"
+        ;;
+    "batchinference")
+        exec tini -- llmos-operator batchinference "${@}"
+        ;;
+    "datacollection")
+        exec tini -- llmos-operator datacollection "${@}"
+        ;;
     *)
-        echo "Error: Invalid mode '$MODE'. Valid modes are: apiserver, webhook, download"
+        echo "Error: Invalid mode '$MODE'. Valid modes are: apiserver, webhook, download, load-adapters, benchmark, finetune, batchinference, datacollection"
         exit 1
         ;;
 esac
\ No newline at end of file
@@ -0,0 +1,240 @@
+package adapterloader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	modelsPath        = "/v1/models"
+	loadAdapterPath   = "/v1/load_lora_adapter"
+	unloadAdapterPath = "/v1/unload_lora_adapter"
+
+	// stateFileName records the models of the loaded adapters in the output directory, so that the adapters are
+	// not reloaded if the loader restarts
+	stateFileName = ".loaded-adapters.json"
+
+	requestTimeout = 30 * time.Second
+)
+
+// DownloadFunc downloads the model of the namespace/name to the output directory
+type DownloadFunc func(ctx context.Context, model, outputDir string) error
+
+// Loader downloads the LoRA adapters listed in the config directory and loads them into the vLLM server at
+// runtime, the adapters removed from the config are unloaded. The config directory is the mounted configMap,
+// whose keys are the adapter names and values are the namespaced names of the adapter models.
+type Loader struct {
+	ConfigDir string
+	OutputDir string
+	Endpoint  string
+	Download  DownloadFunc
+
+	httpClient *http.Client
+}
+
+func NewLoader(configDir, outputDir, endpoint string, download DownloadFunc) *Loader {
+	return &Loader{
+		ConfigDir:  configDir,
+		OutputDir:  outputDir,
+		Endpoint:   strings.TrimSuffix(endpoint, "/"),
+		Download:   download,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Run syncs the adapters periodically until the context is done
+func (l *Loader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := l.Sync(ctx); err != nil {
+			logrus.Warnf("failed to sync adapters: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync loads the configured adapters that are not served, and unloads the served adapters that are removed from
+// the config or whose models are changed
+func (l *Loader) Sync(ctx context.Context) error {
+	desired, err := readConfig(l.ConfigDir)
+	if err != nil {
+		return err
+	}
+	served, err := l.servedAdapters(ctx)
+	if err != nil {
+		// the server is not started yet, e.g., it's still loading the base model
+		logrus.Debugf("failed to list the served adapters: %v", err)
+		return nil
+	}
+	loaded := l.readState()
+
+	var errs []error
+	for _, name := range sets.List(served) {
+		if model, ok := desired[name]; ok && loaded[name] == model {
+			continue
+		}
+		logrus.Infof("unloading adapter %s", name)
+		if err = l.post(ctx, unloadAdapterPath, map[string]string{"lora_name": name}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unload adapter %s: %w", name, err))
+			continue
+		}
+		served.Delete(name)
+		delete(loaded, name)
+		if err = os.RemoveAll(l.adapterPath(name)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove adapter %s: %w", name, err))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(desired)) {
+		if served.Has(name) {
+			continue
+		}
+		model := desired[name]
+		logrus.Infof("loading adapter %s of model %s", name, model)
+		if err = l.Download(ctx, model, l.adapterPath(name)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to download adapter %s: %w", name, err))
+			continue
+		}
+		if err = l.post(ctx, loadAdapterPath, map[string]string{
+			"lora_name": name,
+			"lora_path": l.adapterPath(name),
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to load adapter %s: %w", name, err))
+			continue
+		}
+		loaded[name] = model
+	}
+
+	if err = l.writeState(loaded); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (l *Loader) adapterPath(name string) string {
+	return filepath.Join(l.OutputDir, name)
+}
+
+// readConfig returns the adapter models by the adapter names, the hidden files are the internals of the mounted
+// configMap and are skipped
+func readConfig(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && os.IsNotExist(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read adapter config: %w", err)
+	}
+
+	adapters := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read adapter %s: %w", entry.Name(), err)
+		}
+		adapters[entry.Name()] = strings.TrimSpace(string(content))
+	}
+	return adapters, nil
+}
+
+// servedAdapters returns the names of the LoRA adapters served by the vLLM server, which are the models with parents
+func (l *Loader) servedAdapters(ctx context.Context) (sets.Set[string], error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.Endpoint+modelsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list models: %s", resp.Status)
+	}
+
+	models := struct {
+		Data []struct {
+			ID     string  `json:"id"`
+			Parent *string `json:"parent"`
+		} `json:"data"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return nil, fmt.Errorf("failed to decode models: %w", err)
+	}
+	adapters := sets.New[string]()
+	for _, model := range models.Data {
+		if model.Parent != nil && *model.Parent != "" {
+			adapters.Insert(model.ID)
+		}
+	}
+	return adapters, nil
+}
+
+func (l *Loader) post(ctx context.Context, path string, body map[string]string) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.Endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("request failed with %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func (l *Loader) readState() map[string]string {
+	loaded := map[string]string{}
+	data, err := os.ReadFile(filepath.Join(l.OutputDir, stateFileName))
+	if err != nil {
+		return loaded
+	}
+	if err = json.Unmarshal(data, &loaded); err != nil {
+		logrus.Warnf("failed to parse the loaded adapters, the served adapters will be reloaded: %v", err)
+		return map[string]string{}
+	}
+	return loaded
+}
+
+func (l *Loader) writeState(loaded map[string]string) error {
+	data, err := json.Marshal(loaded)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(l.OutputDir, stateFileName), data, 0o600); err != nil {
+		return fmt.Errorf("failed to record the loaded adapters: %w", err)
+	}
+	return nil
+}
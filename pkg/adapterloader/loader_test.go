@@ -0,0 +1,133 @@
+package adapterloader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// fakeServer serves the models, load and unload APIs of vLLM
+type fakeServer struct {
+	mu       sync.Mutex
+	adapters map[string]string
+}
+
+func (s *fakeServer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body := map[string]string{}
+	if req.Method == http.MethodPost {
+		_ = json.NewDecoder(req.Body).Decode(&body)
+	}
+	switch req.URL.Path {
+	case modelsPath:
+		parent := "base"
+		type model struct {
+			ID     string  `json:"id"`
+			Parent *string `json:"parent"`
+		}
+		models := []model{{ID: "base"}}
+		for name := range s.adapters {
+			models = append(models, model{ID: name, Parent: &parent})
+		}
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{"data": models})
+	case loadAdapterPath:
+		s.adapters[body["lora_name"]] = body["lora_path"]
+	case unloadAdapterPath:
+		delete(s.adapters, body["lora_name"])
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestLoaderSync(t *testing.T) {
+	configDir, outputDir := t.TempDir(), t.TempDir()
+	writeConfig := func(adapters map[string]string) {
+		entries, _ := os.ReadDir(configDir)
+		for _, entry := range entries {
+			_ = os.Remove(filepath.Join(configDir, entry.Name()))
+		}
+		for name, model := range adapters {
+			if err := os.WriteFile(filepath.Join(configDir, name), []byte(model), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	server := &fakeServer{adapters: map[string]string{}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	var downloads []string
+	loader := NewLoader(configDir, outputDir, ts.URL, func(_ context.Context, model, dir string) error {
+		downloads = append(downloads, model)
+		return os.MkdirAll(dir, 0o700)
+	})
+
+	writeConfig(map[string]string{"sql": "team-a/sql-lora", "chat": "team-a/chat-lora"})
+	if err := loader.Sync(context.Background()); err != nil {
+		t.Fatalf("Expected the adapters to be synced, got %v", err)
+	}
+	expected := map[string]string{
+		"sql":  filepath.Join(outputDir, "sql"),
+		"chat": filepath.Join(outputDir, "chat"),
+	}
+	if !reflect.DeepEqual(server.adapters, expected) {
+		t.Errorf("Expected adapters %v to be loaded, got %v", expected, server.adapters)
+	}
+
+	// the loaded adapters are not downloaded again
+	downloads = nil
+	if err := loader.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(downloads) != 0 {
+		t.Errorf("Expected no downloads of the loaded adapters, got %v", downloads)
+	}
+
+	// the removed adapters are unloaded and the adapters of the changed models are reloaded
+	writeConfig(map[string]string{"sql": "team-a/sql-lora-v2"})
+	if err := loader.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(server.adapters, map[string]string{"sql": filepath.Join(outputDir, "sql")}) {
+		t.Errorf("Expected only adapter sql to be loaded, got %v", server.adapters)
+	}
+	if !reflect.DeepEqual(downloads, []string{"team-a/sql-lora-v2"}) {
+		t.Errorf("Expected the changed model to be downloaded, got %v", downloads)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "chat")); !os.IsNotExist(err) {
+		t.Errorf("Expected the removed adapter to be deleted, got %v", err)
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	dir := t.TempDir()
+	// the mounted configMap keeps the data in the hidden directories
+	if err := os.MkdirAll(filepath.Join(dir, "..data"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sql"), []byte("team-a/sql-lora\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	adapters, err := readConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(adapters, map[string]string{"sql": "team-a/sql-lora"}) {
+		t.Errorf("Expected adapter sql, got %v", adapters)
+	}
+
+	adapters, err = readConfig(filepath.Join(dir, "missing"))
+	if err != nil || len(adapters) != 0 {
+		t.Errorf("Expected no adapters of the missing config, got %v, %v", adapters, err)
+	}
+}
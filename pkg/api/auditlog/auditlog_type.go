@@ -0,0 +1,26 @@
+package auditlog
+
+import "time"
+
+type AuditLog struct {
+	// ID of the audit record.
+	ID string `json:"id,omitempty"`
+	// UserId is the user made the request.
+	UserId string `json:"userId,omitempty"`
+	// TokenName is the name of the session token or API key the request is authenticated by.
+	TokenName string `json:"tokenName,omitempty"`
+	// Verb is the verb or the action of the request, e.g., create, delete or upload.
+	Verb      string `json:"verb,omitempty"`
+	Resource  string `json:"resource,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Path      string `json:"path,omitempty"`
+	// StatusCode is the response status code of the request.
+	StatusCode int `json:"statusCode,omitempty"`
+	// LatencyMs is the time the request took in milliseconds.
+	LatencyMs int64     `json:"latencyMs,omitempty"`
+	SourceIP  string    `json:"sourceIP,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
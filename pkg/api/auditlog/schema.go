@@ -0,0 +1,26 @@
+package auditlog
+
+import (
+	"net/http"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/steve/pkg/server"
+
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+const auditLogTypeName = "auditlog"
+
+func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
+	schemas := server.BaseSchemas
+	schemas.InternalSchemas.TypeName(auditLogTypeName, AuditLog{})
+	schemas.MustImportAndCustomize(AuditLog{}, func(schema *types.APISchema) {
+		// the audit logs are read-only
+		schema.CollectionMethods = []string{http.MethodGet}
+		schema.ResourceMethods = []string{http.MethodGet}
+		schema.Store = &Store{
+			mgmt: scaled.Management,
+		}
+	})
+	return nil
+}
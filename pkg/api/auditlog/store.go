@@ -0,0 +1,145 @@
+package auditlog
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/apiserver/pkg/store/empty"
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	entv1 "github.com/llmos-ai/llmos-operator/pkg/generated/ent"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/auditlog"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/predicate"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+	"github.com/llmos-ai/llmos-operator/pkg/utils"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// Store is the read-only store of the audit records, only the admins are allowed to query them
+type Store struct {
+	empty.Store
+	mgmt *config.Management
+}
+
+func toAPIObject(r *entv1.AuditLog) types.APIObject {
+	return types.APIObject{
+		Type:   auditLogTypeName,
+		ID:     r.ID.String(),
+		Object: r,
+	}
+}
+
+func (s *Store) ByID(apiOp *types.APIRequest, _ *types.APISchema, id string) (types.APIObject, error) {
+	client, err := s.getClient(apiOp)
+	if err != nil {
+		return types.APIObject{}, err
+	}
+
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return types.APIObject{}, apierror.NewAPIError(validation.NotFound, fmt.Sprintf("audit log %s not found", id))
+	}
+	record, err := client.AuditLog.Get(apiOp.Context(), uid)
+	if err != nil {
+		if entv1.IsNotFound(err) {
+			return types.APIObject{}, apierror.NewAPIError(validation.NotFound, fmt.Sprintf("audit log %s not found", id))
+		}
+		return types.APIObject{}, fmt.Errorf("failed to get audit log %s: %w", id, err)
+	}
+	return toAPIObject(record), nil
+}
+
+// List returns the latest audit records matching the query parameters user, verb, resource, namespace, since and
+// until, the since and until are RFC3339 times, and the number of the records is limited by the limit parameter
+func (s *Store) List(apiOp *types.APIRequest, _ *types.APISchema) (types.APIObjectList, error) {
+	client, err := s.getClient(apiOp)
+	if err != nil {
+		return types.APIObjectList{}, err
+	}
+
+	predicates, limit, err := parseQuery(apiOp.Request.URL.Query())
+	if err != nil {
+		return types.APIObjectList{}, apierror.NewAPIError(validation.InvalidFormat, err.Error())
+	}
+	records, err := client.AuditLog.Query().
+		Where(predicates...).
+		Order(auditlog.ByCreatedAt(sql.OrderDesc())).
+		Limit(limit).
+		All(apiOp.Context())
+	if err != nil {
+		return types.APIObjectList{}, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+
+	objs := make([]types.APIObject, 0, len(records))
+	for _, r := range records {
+		objs = append(objs, toAPIObject(r))
+	}
+	return types.APIObjectList{
+		Objects: objs,
+	}, nil
+}
+
+func (s *Store) getClient(apiOp *types.APIRequest) (*entv1.Client, error) {
+	userInfo, ok := apiOp.GetUserInfo()
+	if !ok || !utils.ArrayStringContains(userInfo.GetGroups(), constant.AdminRole) {
+		return nil, apierror.NewAPIError(validation.PermissionDenied, "only admins are allowed to query the audit logs")
+	}
+
+	client := s.mgmt.GetEntClient()
+	if settings.DatabaseURL.Get() == "" || client == nil {
+		return nil, apierror.NewAPIError(validation.ServerError, "database of the audit logs is not configured")
+	}
+	return client, nil
+}
+
+func parseQuery(query url.Values) ([]predicate.AuditLog, int, error) {
+	predicates := make([]predicate.AuditLog, 0)
+	if v := query.Get("user"); v != "" {
+		predicates = append(predicates, auditlog.UserId(v))
+	}
+	if v := query.Get("verb"); v != "" {
+		predicates = append(predicates, auditlog.Verb(v))
+	}
+	if v := query.Get("resource"); v != "" {
+		predicates = append(predicates, auditlog.Resource(v))
+	}
+	if v := query.Get("namespace"); v != "" {
+		predicates = append(predicates, auditlog.Namespace(v))
+	}
+	if v := query.Get("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid since %s: %w", v, err)
+		}
+		predicates = append(predicates, auditlog.CreatedAtGTE(since))
+	}
+	if v := query.Get("until"); v != "" {
+		until, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid until %s: %w", v, err)
+		}
+		predicates = append(predicates, auditlog.CreatedAtLT(until))
+	}
+
+	limit := defaultLimit
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, 0, fmt.Errorf("invalid limit %s", v)
+		}
+		limit = min(n, maxLimit)
+	}
+	return predicates, limit, nil
+}
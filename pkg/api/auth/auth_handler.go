@@ -1,21 +1,30 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rancher/apiserver/pkg/apierror"
 	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
 	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/auth"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/mfa"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers/ldap"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers/oidc"
 	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
 	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/server/config"
 	"github.com/llmos-ai/llmos-operator/pkg/settings"
 	"github.com/llmos-ai/llmos-operator/pkg/utils"
@@ -25,33 +34,68 @@ const (
 	actionQuery      = "action"
 	loginActionName  = "login"
 	logoutActionName = "logout"
+	// verifyMFAActionName completes the login challenge of the users enabled MFA
+	verifyMFAActionName = "verifymfa"
 
 	UserNotActiveErrMsg = "User is not activated"
 )
 
+var tooManyRequests = validation.ErrorCode{Code: "TooManyRequests", Status: http.StatusTooManyRequests}
+
 type LoginRequest struct {
 	Username     string `json:"username" binding:"required"`
 	Password     string `json:"password" binding:"required"`
 	ResponseType string `json:"responseType"`
+	// AuthProvider is the password provider authenticating the user, e.g., ldap, defaults to the local users
+	AuthProvider string `json:"authProvider"`
 }
 
 type LoginResponse struct {
 	UserId       string `json:"userId"`
 	AuthProvider string `json:"authProvider"`
 	Token        string `json:"token"`
+	// MustChangePassword is true if the user must change the password before using the API
+	MustChangePassword bool `json:"mustChangePassword,omitempty"`
+	// MustEnrollMFA is true if the user must enroll MFA before using the API
+	MustEnrollMFA bool `json:"mustEnrollMFA,omitempty"`
+	// MFARequired is true if the MFA code is required to complete the login, the token is issued by the verifyMFA
+	// action with the challenge instead
+	MFARequired bool   `json:"mfaRequired,omitempty"`
+	Challenge   string `json:"challenge,omitempty"`
+}
+
+type VerifyMFARequest struct {
+	Challenge    string `json:"challenge" binding:"required"`
+	Code         string `json:"code" binding:"required"`
+	ResponseType string `json:"responseType"`
 }
 
 type Handler struct {
-	manager    *tokens.Manager
-	middleware *auth.Middleware
+	userClient  ctlmgmtv1.UserClient
+	manager     *tokens.Manager
+	mfa         *mfa.Manager
+	middleware  *auth.Middleware
+	provisioner *providers.Provisioner
+	providers   map[string]providers.Provider
 }
 
 func NewAuthHandler(scaled *config.Scaled) *Handler {
 	middleware := auth.NewMiddleware(scaled)
 	manager := tokens.NewManager(scaled)
+	secrets := scaled.CoreFactory.Core().V1().Secret()
+	oidcProvider := oidc.NewProvider(secrets)
+	ldapProvider := ldap.NewProvider(secrets)
+	users := scaled.MgmtFactory.Management().V1().User()
 	return &Handler{
-		manager:    manager,
-		middleware: middleware,
+		userClient:  users,
+		manager:     manager,
+		mfa:         mfa.NewManager(secrets, users),
+		middleware:  middleware,
+		provisioner: providers.NewProvisioner(scaled.MgmtFactory),
+		providers: map[string]providers.Provider{
+			oidcProvider.Name(): oidcProvider,
+			ldapProvider.Name(): ldapProvider,
+		},
 	}
 }
 
@@ -78,36 +122,45 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		userId, tokenResp, err := h.login(&input)
+		user, err := h.login(r.Context(), &input)
 		if err != nil {
-			header := http.StatusInternalServerError
-			var e *apierror.APIError
-			if errors.As(err, &e) {
-				header = e.Code.Status
-			}
-			utils.ResponseErrorMsg(rw, header, e.Message)
+			responseLoginError(rw, err)
 			return
 		}
 
-		if input.ResponseType == "cookie" {
-			tokenCookie := &http.Cookie{
-				Name:     tokens.CookieName,
-				Value:    tokenResp,
-				Path:     "/",
-				Secure:   true,
-				HttpOnly: true,
+		// the session token of the users enabled MFA is issued once the challenge is completed with the MFA code
+		if tokens.IsLocalUser(user) && user.Status.MFAEnabled {
+			challenge, err := h.mfa.NewChallenge(user)
+			if err != nil {
+				responseLoginError(rw, apierror.NewAPIError(validation.ServerError,
+					fmt.Sprintf("failed to create MFA challenge, %s", err.Error())))
+				return
 			}
+			utils.ResponseOKWithBody(rw, &LoginResponse{
+				UserId:       user.Name,
+				AuthProvider: loginProviderName(&input),
+				MFARequired:  true,
+				Challenge:    challenge,
+			})
+			return
+		}
+
+		h.issueToken(rw, r, user, loginProviderName(&input), input.ResponseType)
+		return
+	case verifyMFAActionName:
+		var input VerifyMFARequest
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			utils.ResponseError(rw, http.StatusBadRequest, fmt.Errorf("failed to decode request body, %s", err.Error()))
+			return
+		}
 
-			http.SetCookie(rw, tokenCookie)
-			utils.ResponseOKWithBody(rw, "login success")
+		user, err := h.verifyMFA(&input, time.Now())
+		if err != nil {
+			responseLoginError(rw, err)
 			return
 		}
 
-		utils.ResponseOKWithBody(rw, &LoginResponse{
-			UserId:       userId,
-			AuthProvider: tokens.LocalProviderName,
-			Token:        tokenResp,
-		})
+		h.issueToken(rw, r, user, tokens.LocalProviderName, input.ResponseType)
 		return
 	default:
 		rw.WriteHeader(http.StatusBadRequest)
@@ -116,19 +169,48 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h *Handler) login(input *LoginRequest) (string, string, error) {
-	user, err := h.userLogin(input)
-	if err != nil {
-		return "", "", err
+func responseLoginError(rw http.ResponseWriter, err error) {
+	header := http.StatusInternalServerError
+	msg := err.Error()
+	var e *apierror.APIError
+	if errors.As(err, &e) {
+		header = e.Code.Status
+		msg = e.Message
 	}
+	utils.ResponseErrorMsg(rw, header, msg)
+}
 
-	token, err := h.generateToken(user.Name)
+// issueToken generates the session token of the authenticated user and responds it either in the cookie or the body
+func (h *Handler) issueToken(rw http.ResponseWriter, r *http.Request, user *mgmtv1.User, providerName,
+	responseType string) {
+	token, err := h.generateToken(r, user.Name, providerName)
 	if err != nil {
-		return "", "", apierror.NewAPIError(validation.ServerError,
-			fmt.Sprintf("failed to generate token, %s", err.Error()))
+		responseLoginError(rw, apierror.NewAPIError(validation.ServerError,
+			fmt.Sprintf("failed to generate token, %s", err.Error())))
+		return
 	}
 
-	return user.Name, token, nil
+	if responseType == "cookie" {
+		setSessionCookie(rw, token)
+		utils.ResponseOKWithBody(rw, "login success")
+		return
+	}
+
+	utils.ResponseOKWithBody(rw, &LoginResponse{
+		UserId:             user.Name,
+		AuthProvider:       providerName,
+		Token:              token,
+		MustChangePassword: tokens.PasswordChangeRequired(user),
+		MustEnrollMFA:      mfa.EnrollmentRequired(user),
+	})
+}
+
+func (h *Handler) login(ctx context.Context, input *LoginRequest) (*mgmtv1.User, error) {
+	providerName := loginProviderName(input)
+	if providerName == tokens.LocalProviderName {
+		return h.userLogin(input)
+	}
+	return h.providerLogin(ctx, providerName, input)
 }
 
 func (h *Handler) userLogin(input *LoginRequest) (*mgmtv1.User, error) {
@@ -145,12 +227,158 @@ func (h *Handler) userLogin(input *LoginRequest) (*mgmtv1.User, error) {
 		return nil, err
 	}
 
+	// the users of the external auth providers have no local passwords
+	if !tokens.IsLocalUser(user) {
+		return nil, apierror.NewAPIError(validation.Unauthorized, "authentication failed")
+	}
+
+	return h.verifyPassword(user.Name, pwd, time.Now())
+}
+
+// verifyPassword checks the password of the local user. The failed attempts are reset by the valid password unless
+// MFA is enabled, whose attempts are reset once the MFA code is verified, so that the password can't reset the
+// count of the invalid MFA codes
+func (h *Handler) verifyPassword(name, pwd string, now time.Time) (*mgmtv1.User, error) {
+	// the lockout is checked against the latest user rather than the cached one, which lags behind the failed
+	// attempts recorded by the concurrent logins
+	user, err := h.userClient.Get(name, metav1.GetOptions{})
+	if err != nil {
+		logrus.Debugf("failed to get user %s, %s", name, err.Error())
+		return nil, apierror.NewAPIError(validation.Unauthorized, "authentication failed")
+	}
+
+	if err = checkLoginRetry(user, now); err != nil {
+		return nil, err
+	}
+
 	if !tokens.CheckPasswordHash(user.Spec.Password, pwd) {
+		if err = h.recordFailedLogin(user, now); err != nil {
+			return nil, err
+		}
 		return nil, apierror.NewAPIError(validation.Unauthorized, "authentication failed")
 	}
 
+	if user.Status.MFAEnabled {
+		return user, nil
+	}
+	return h.resetFailedLogins(user)
+}
+
+// verifyMFA completes the login challenge of the user with the TOTP or recovery code, the failed login attempts
+// are only reset once the code is verified
+func (h *Handler) verifyMFA(input *VerifyMFARequest, now time.Time) (*mgmtv1.User, error) {
+	name, key := mfa.SplitChallenge(input.Challenge)
+	if name == "" || key == "" {
+		return nil, apierror.NewAPIError(validation.Unauthorized, mfa.ErrInvalidChallenge.Error())
+	}
+
+	user, err := h.userClient.Get(name, metav1.GetOptions{})
+	if err != nil {
+		logrus.Debugf("failed to get user %s, %s", name, err.Error())
+		return nil, apierror.NewAPIError(validation.Unauthorized, mfa.ErrInvalidChallenge.Error())
+	}
+
+	if err = checkUserIsActive(user); err != nil {
+		return nil, err
+	}
+
+	if err = checkLoginRetry(user, now); err != nil {
+		return nil, err
+	}
+
+	if err = h.mfa.CompleteChallenge(user, key, input.Code); err != nil {
+		switch {
+		case errors.Is(err, mfa.ErrInvalidCode):
+			if recordErr := h.recordFailedLogin(user, now); recordErr != nil {
+				return nil, recordErr
+			}
+			return nil, apierror.NewAPIError(validation.Unauthorized, err.Error())
+		case errors.Is(err, mfa.ErrInvalidChallenge), errors.Is(err, mfa.ErrNotEnrolled):
+			return nil, apierror.NewAPIError(validation.Unauthorized, err.Error())
+		default:
+			return nil, apierror.NewAPIError(validation.ServerError,
+				fmt.Sprintf("failed to verify MFA code, %s", err.Error()))
+		}
+	}
+
+	return h.resetFailedLogins(user)
+}
+
+// checkLoginRetry rejects the login of the locked out user or the retry within the delay after the failed attempt
+func checkLoginRetry(user *mgmtv1.User, now time.Time) error {
+	if retryAfter := tokens.LoginRetryAfter(user, now); !retryAfter.IsZero() {
+		return apierror.NewAPIError(tooManyRequests, fmt.Sprintf("too many failed login attempts, retry in %d seconds",
+			int(retryAfter.Sub(now).Seconds())+1))
+	}
+	return nil
+}
+
+// recordFailedLogin counts the failed attempt on the latest user with the conflicts retried, so that the concurrent
+// attempts can't overwrite each other's count, and the login fails closed if the attempt can't be recorded
+func (h *Handler) recordFailedLogin(user *mgmtv1.User, now time.Time) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := h.userClient.Get(user.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		_, err = h.userClient.UpdateStatus(tokens.RecordFailedLogin(latest, now))
+		return err
+	})
+	if err != nil {
+		logrus.Errorf("failed to record failed login of user %s, %s", user.Spec.Username, err.Error())
+		return apierror.NewAPIError(validation.ServerError, "failed to record the failed login")
+	}
+	return nil
+}
+
+func (h *Handler) resetFailedLogins(user *mgmtv1.User) (*mgmtv1.User, error) {
+	if user.Status.FailedLoginAttempts == 0 && user.Status.LockedUntil == nil {
+		return user, nil
+	}
+	user, err := h.userClient.UpdateStatus(tokens.ResetFailedLogins(user))
+	if err != nil {
+		return nil, apierror.NewAPIError(validation.ServerError,
+			fmt.Sprintf("failed to reset failed logins, %s", err.Error()))
+	}
 	return user, nil
 }
+
+// providerLogin authenticates the user by the password provider and provisions the user of the principal
+func (h *Handler) providerLogin(ctx context.Context, providerName string, input *LoginRequest) (*mgmtv1.User, error) {
+	provider, ok := h.providers[providerName].(providers.PasswordProvider)
+	if !ok || !provider.Enabled() {
+		return nil, apierror.NewAPIError(validation.NotFound, fmt.Sprintf("auth provider %s is not enabled", providerName))
+	}
+
+	principal, err := provider.Authenticate(ctx, input.Username, input.Password)
+	if err != nil {
+		logrus.Debugf("failed to authenticate user %s by auth provider %s, %s", input.Username, providerName,
+			err.Error())
+		if errors.Is(err, providers.ErrInvalidCredentials) || errors.Is(err, providers.ErrGroupNotAllowed) {
+			return nil, apierror.NewAPIError(validation.Unauthorized, "authentication failed")
+		}
+		return nil, apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to authenticate user, %s", err))
+	}
+
+	user, err := h.provisioner.Provision(principal, provider.GroupMappings())
+	if err != nil {
+		return nil, apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to provision user, %s", err))
+	}
+
+	if !user.Spec.Active {
+		return nil, apierror.NewAPIError(validation.Unauthorized, UserNotActiveErrMsg)
+	}
+
+	return user, nil
+}
+
+func loginProviderName(input *LoginRequest) string {
+	if input.AuthProvider == "" {
+		return tokens.LocalProviderName
+	}
+	return strings.ToLower(input.AuthProvider)
+}
+
 func checkUserIsActive(user *mgmtv1.User) error {
 	if user == nil {
 		return apierror.NewAPIError(validation.Unauthorized, "user object is nil")
@@ -162,7 +390,8 @@ func checkUserIsActive(user *mgmtv1.User) error {
 
 	return nil
 }
-func (h *Handler) generateToken(userId string) (string, error) {
+
+func (h *Handler) generateToken(r *http.Request, userId, authProvider string) (string, error) {
 	authTimeout := settings.AuthUserSessionMaxTTLMinutes.Get()
 	ttl, err := strconv.ParseInt(authTimeout, 10, 64)
 	if err != nil {
@@ -170,10 +399,20 @@ func (h *Handler) generateToken(userId string) (string, error) {
 		ttl = 720
 	}
 
-	token, tokenStr, err := h.manager.NewLoginToken(userId, ttl*60) // convert ttl to seconds
+	token, tokenStr, err := h.manager.NewLoginToken(userId, authProvider, ttl*60, r) // convert ttl to seconds
 	if err != nil {
 		return "", err
 	}
 
 	return fmt.Sprintf("%s:%s", token.Name, tokenStr), nil
 }
+
+func setSessionCookie(rw http.ResponseWriter, token string) {
+	http.SetCookie(rw, &http.Cookie{
+		Name:     tokens.CookieName,
+		Value:    token,
+		Path:     "/",
+		Secure:   true,
+		HttpOnly: true,
+	})
+}
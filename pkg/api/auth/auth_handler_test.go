@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/mfa"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/clientset/versioned/fake"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+	"github.com/llmos-ai/llmos-operator/pkg/utils/fakeclients"
+)
+
+const (
+	testUserName = "user-abc"
+	testPassword = "Password1!"
+)
+
+func setSetting(t *testing.T, setting settings.Setting, value string) {
+	old := setting.Get()
+	require.NoError(t, setting.Set(value))
+	t.Cleanup(func() { _ = setting.Set(old) })
+}
+
+func newMFAHandler(t *testing.T) (*Handler, string) {
+	hash, err := tokens.HashPassword(testPassword)
+	require.NoError(t, err)
+	totpSecret, err := mfa.GenerateSecret()
+	require.NoError(t, err)
+
+	clientset := fake.NewSimpleClientset(&mgmtv1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: testUserName},
+		Spec: mgmtv1.UserSpec{
+			Username: "alice",
+			Password: hash,
+		},
+		Status: mgmtv1.UserStatus{
+			IsActive:   true,
+			MFAEnabled: true,
+		},
+	})
+	k8sClientset := k8sfake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "user-mfa-" + testUserName,
+			Namespace: constant.SystemNamespaceName,
+		},
+		Data: map[string][]byte{"totpSecret": []byte(totpSecret)},
+	})
+
+	users := fakeclients.User(clientset.ManagementV1().Users)
+	return &Handler{
+		userClient: users,
+		mfa:        mfa.NewManager(fakeclients.SecretClient(k8sClientset.CoreV1().Secrets), users),
+	}, totpSecret
+}
+
+func assertStatus(t *testing.T, expected int, err error) {
+	t.Helper()
+	var e *apierror.APIError
+	require.ErrorAs(t, err, &e)
+	assert.Equal(t, expected, e.Code.Status)
+}
+
+func TestMFALoginLockout(t *testing.T) {
+	setSetting(t, settings.AuthLoginMaxFailedAttempts, "5")
+	setSetting(t, settings.AuthLoginLockoutMinutes, "15")
+
+	h, totpSecret := newMFAHandler(t)
+	now := time.Now()
+	// each attempt is made after the retry delay of the previous failed attempt
+	attempt := 0
+	next := func() time.Time {
+		attempt++
+		return now.Add(time.Duration(attempt) * time.Minute)
+	}
+	login := func() string {
+		user, err := h.verifyPassword(testUserName, testPassword, next())
+		require.NoError(t, err)
+		challenge, err := h.mfa.NewChallenge(user)
+		require.NoError(t, err)
+		return challenge
+	}
+	failedAttempts := func() int {
+		user, err := h.userClient.Get(testUserName, metav1.GetOptions{})
+		require.NoError(t, err)
+		return user.Status.FailedLoginAttempts
+	}
+
+	challenge := login()
+	for i := 0; i < 2; i++ {
+		_, err := h.verifyMFA(&VerifyMFARequest{Challenge: challenge, Code: "000000"}, next())
+		assertStatus(t, http.StatusUnauthorized, err)
+	}
+	assert.Equal(t, 2, failedAttempts())
+
+	// the valid password doesn't reset the invalid MFA codes
+	challenge = login()
+	assert.Equal(t, 2, failedAttempts())
+
+	for i := 0; i < 3; i++ {
+		_, err := h.verifyMFA(&VerifyMFARequest{Challenge: challenge, Code: "000000"}, next())
+		assertStatus(t, http.StatusUnauthorized, err)
+	}
+
+	// the user is locked out, even with the valid password
+	_, err := h.verifyPassword(testUserName, testPassword, next())
+	assertStatus(t, http.StatusTooManyRequests, err)
+
+	// the challenge is burned after the max invalid codes, even if the valid code is used
+	user, err := h.userClient.Get(testUserName, metav1.GetOptions{})
+	require.NoError(t, err)
+	code, err := mfa.GenerateCode(totpSecret, time.Now())
+	require.NoError(t, err)
+	_, key := mfa.SplitChallenge(challenge)
+	assert.ErrorIs(t, h.mfa.CompleteChallenge(user, key, code), mfa.ErrInvalidChallenge)
+}
+
+func TestMFALoginResetsFailedAttempts(t *testing.T) {
+	setSetting(t, settings.AuthLoginMaxFailedAttempts, "5")
+
+	h, totpSecret := newMFAHandler(t)
+	now := time.Now()
+
+	_, err := h.verifyPassword(testUserName, "wrong", now)
+	assertStatus(t, http.StatusUnauthorized, err)
+
+	user, err := h.verifyPassword(testUserName, testPassword, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, user.Status.FailedLoginAttempts)
+
+	challenge, err := h.mfa.NewChallenge(user)
+	require.NoError(t, err)
+	code, err := mfa.GenerateCode(totpSecret, time.Now())
+	require.NoError(t, err)
+	user, err = h.verifyMFA(&VerifyMFARequest{Challenge: challenge, Code: code}, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 0, user.Status.FailedLoginAttempts)
+}
@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+	"github.com/llmos-ai/llmos-operator/pkg/utils"
+)
+
+const (
+	providerVar          = "provider"
+	redirectQuery        = "redirect"
+	loginStateCookie     = "L_AUTH_STATE"
+	loginStateMaxAge     = 600
+	defaultRedirect      = "/dashboard/"
+	loginFailedPath      = "/dashboard/auth/login"
+	providerTypeLocal    = "local"
+	providerTypeRedirect = "redirect"
+	providerTypePassword = "password"
+)
+
+type AuthProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Type        string `json:"type"`
+	LoginURL    string `json:"loginUrl,omitempty"`
+}
+
+// ListProviders returns the enabled auth providers shown on the login page
+func (h *Handler) ListProviders(rw http.ResponseWriter, _ *http.Request) {
+	result := []AuthProvider{
+		{
+			Name:        tokens.LocalProviderName,
+			DisplayName: "Local",
+			Type:        providerTypeLocal,
+		},
+	}
+	for name, provider := range h.providers {
+		if !provider.Enabled() {
+			continue
+		}
+		p := AuthProvider{
+			Name:        name,
+			DisplayName: provider.DisplayName(),
+		}
+		if _, ok := provider.(providers.RedirectProvider); ok {
+			p.Type = providerTypeRedirect
+			p.LoginURL = fmt.Sprintf("/v1-public/auth/%s/login", name)
+		} else if _, ok = provider.(providers.PasswordProvider); ok {
+			p.Type = providerTypePassword
+		}
+		result = append(result, p)
+	}
+
+	utils.ResponseOKWithBody(rw, result)
+}
+
+// ProviderLogin redirects the user to the identity provider, the state, nonce and PKCE verifier of the login are
+// kept in a short-lived cookie and checked by the callback
+func (h *Handler) ProviderLogin(rw http.ResponseWriter, r *http.Request) {
+	provider, err := h.getRedirectProvider(r)
+	if err != nil {
+		utils.ResponseError(rw, http.StatusNotFound, err)
+		return
+	}
+
+	state := &providers.LoginState{
+		State:    oauth2.GenerateVerifier(),
+		Nonce:    oauth2.GenerateVerifier(),
+		Verifier: oauth2.GenerateVerifier(),
+		Redirect: sanitizeRedirect(r.URL.Query().Get(redirectQuery)),
+	}
+	authCodeURL, err := provider.AuthCodeURL(r.Context(), state, callbackURL(r, provider.Name()))
+	if err != nil {
+		logrus.Errorf("failed to get auth code url of provider %s: %v", provider.Name(), err)
+		utils.ResponseError(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		utils.ResponseError(rw, http.StatusInternalServerError, err)
+		return
+	}
+	http.SetCookie(rw, &http.Cookie{
+		Name:     loginStateCookie,
+		Value:    base64.RawURLEncoding.EncodeToString(value),
+		Path:     "/v1-public/auth/",
+		MaxAge:   loginStateMaxAge,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(rw, r, authCodeURL, http.StatusFound)
+}
+
+// ProviderCallback exchanges the authorization code for the principal, provisions the user and logs it in
+func (h *Handler) ProviderCallback(rw http.ResponseWriter, r *http.Request) {
+	provider, err := h.getRedirectProvider(r)
+	if err != nil {
+		utils.ResponseError(rw, http.StatusNotFound, err)
+		return
+	}
+
+	state, err := getLoginState(r)
+	// the state cookie is one-time
+	http.SetCookie(rw, &http.Cookie{
+		Name:     loginStateCookie,
+		Path:     "/v1-public/auth/",
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+	})
+	if err != nil {
+		redirectLoginFailed(rw, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	if e := query.Get("error"); e != "" {
+		redirectLoginFailed(rw, r, fmt.Errorf("%s: %s", e, query.Get("error_description")))
+		return
+	}
+	if query.Get("state") != state.State {
+		redirectLoginFailed(rw, r, errors.New("state doesn't match"))
+		return
+	}
+
+	principal, err := provider.Exchange(r.Context(), query.Get("code"), state, callbackURL(r, provider.Name()))
+	if err != nil {
+		redirectLoginFailed(rw, r, err)
+		return
+	}
+
+	user, err := h.provisioner.Provision(principal, provider.GroupMappings())
+	if err != nil {
+		redirectLoginFailed(rw, r, err)
+		return
+	}
+	if !user.Spec.Active {
+		redirectLoginFailed(rw, r, errors.New(UserNotActiveErrMsg))
+		return
+	}
+
+	token, err := h.generateToken(r, user.Name, provider.Name())
+	if err != nil {
+		redirectLoginFailed(rw, r, fmt.Errorf("failed to generate token, %w", err))
+		return
+	}
+
+	setSessionCookie(rw, token)
+	http.Redirect(rw, r, state.Redirect, http.StatusFound)
+}
+
+func (h *Handler) getRedirectProvider(r *http.Request) (providers.RedirectProvider, error) {
+	name := mux.Vars(r)[providerVar]
+	provider, ok := h.providers[name].(providers.RedirectProvider)
+	if !ok || !provider.Enabled() {
+		return nil, fmt.Errorf("auth provider %s is not found or not enabled", name)
+	}
+	return provider, nil
+}
+
+func getLoginState(r *http.Request) (*providers.LoginState, error) {
+	cookie, err := r.Cookie(loginStateCookie)
+	if err != nil {
+		return nil, errors.New("login state is missing or expired")
+	}
+	value, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return nil, errors.New("invalid login state")
+	}
+	state := &providers.LoginState{}
+	if err = json.Unmarshal(value, state); err != nil || state.State == "" {
+		return nil, errors.New("invalid login state")
+	}
+	return state, nil
+}
+
+// callbackURL returns the redirect uri registered in the identity provider, which is built from the server-url
+// setting or the request if the setting is empty
+func callbackURL(r *http.Request, provider string) string {
+	base := strings.TrimSuffix(settings.ServerURL.Get(), "/")
+	if base == "" {
+		scheme := "https"
+		if r.TLS == nil && r.Header.Get("X-Forwarded-Proto") == "http" {
+			scheme = "http"
+		}
+		base = scheme + "://" + r.Host
+	}
+	return fmt.Sprintf("%s/v1-public/auth/%s/callback", base, provider)
+}
+
+// sanitizeRedirect only allows the local paths to avoid the open redirects
+func sanitizeRedirect(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.Contains(redirect, "\\") {
+		return defaultRedirect
+	}
+	return redirect
+}
+
+func redirectLoginFailed(rw http.ResponseWriter, r *http.Request, err error) {
+	logrus.Warnf("failed to log in with auth provider %s: %v", mux.Vars(r)[providerVar], err)
+	http.Redirect(rw, r, loginFailedPath+"?error="+url.QueryEscape(err.Error()), http.StatusFound)
+}
@@ -50,6 +50,9 @@ type RemoveInput DownloadInput
 
 type PostHook func(req *http.Request, b backend.Backend) error
 
+// PreHook is called before the action is done, the action is rejected if it returns an error
+type PreHook func(req *http.Request) error
+
 type CreateDirectoryInput struct {
 	TargetDirectory string `json:"targetDirectory"`
 }
@@ -84,6 +87,7 @@ type BaseHandler struct {
 	RegistryManager        *registry.Manager
 	GetRegistryAndRootPath func(namespace, name string) (string, string, error)
 
+	PreHooks  map[string]PreHook
 	PostHooks map[string]PostHook
 }
 
@@ -116,6 +120,12 @@ func (h BaseHandler) doPost(rw http.ResponseWriter, req *http.Request, vars map[
 	action := vars["action"]
 	namespace, name := vars["namespace"], vars["name"]
 
+	if hook, ok := h.PreHooks[action]; ok {
+		if err := hook(req); err != nil {
+			return err
+		}
+	}
+
 	switch action {
 	case ActionUpload:
 		return h.upload(rw, req, namespace, name)
@@ -464,6 +474,12 @@ func (h BaseHandler) generatePresignedURL(rw http.ResponseWriter, req *http.Requ
 	if !isValidPath(input.ObjectName) {
 		return apierror.NewAPIError(validation.InvalidBodyContent, "Invalid object name")
 	}
+	// the presigned upload URL is checked as an upload
+	if hook, ok := h.PreHooks[ActionUpload]; ok && input.Operation == "upload" {
+		if err := hook(req); err != nil {
+			return err
+		}
+	}
 
 	b, rootPath, err := h.getBackendAndRootPath(namespace, name)
 	if err != nil {
@@ -7,10 +7,10 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cr "github.com/llmos-ai/llmos-operator/pkg/api/common/registry"
 	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/datacollection"
 	ctlagentv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/agent.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/registry"
 	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
@@ -61,24 +61,11 @@ func (h Handler) SyncFiles(req *http.Request, b backend.Backend) error {
 		return fmt.Errorf("get datacollection %s/%s failed: %w", namespace, name, err)
 	}
 	// Use req.Context(), not h.Ctx. I don't know why.
-	files, err := b.List(req.Context(), dc.Status.RootPath, true, true)
+	fileInfos, err := datacollection.ListFiles(req.Context(), b, dc.Status.RootPath)
 	if err != nil {
 		return fmt.Errorf("failed to list files in %s from registry %s: %w", dc.Spec.Registry, dc.Status.RootPath, err)
 	}
 
-	fileInfos := make([]agentv1.FileInfo, 0, len(files))
-	for _, f := range files {
-		fileInfos = append(fileInfos, agentv1.FileInfo{
-			UID:          f.UID,
-			Name:         f.Name,
-			Path:         f.Path,
-			Size:         f.Size,
-			LastModified: metav1.NewTime(f.LastModified),
-			ContentType:  f.ContentType,
-			ETag:         f.ETag,
-		})
-	}
-
 	if reflect.DeepEqual(dc.Status.Files, fileInfos) {
 		return nil
 	}
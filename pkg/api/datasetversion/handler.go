@@ -1,26 +1,57 @@
 package datasetversion
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	"github.com/sirupsen/logrus"
 
 	cr "github.com/llmos-ai/llmos-operator/pkg/api/common/registry"
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/dataset"
 	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/registry"
+	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
 	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+	"github.com/llmos-ai/llmos-operator/pkg/utils"
 )
 
+const ActionPreview = "preview"
+
+type PreviewInput struct {
+	// Split is the split to preview, default to train
+	Split string `json:"split,omitempty"`
+	// Limit is the number of rows to preview, default to 10 and at most 100
+	Limit int `json:"limit,omitempty"`
+}
+
+type PreviewOutput struct {
+	Split    string                   `json:"split"`
+	Features []mlv1.DatasetFeature    `json:"features,omitempty"`
+	Rows     []map[string]interface{} `json:"rows"`
+}
+
 type DatasetVersionGetter func(namespace, name string) (*mlv1.DatasetVersion, error)
 
 type Handler struct {
-	dvCache ctlmlv1.DatasetVersionCache
+	dvCache  ctlmlv1.DatasetVersionCache
+	dvClient ctlmlv1.DatasetVersionClient
 
 	cr.BaseHandler
 }
 
 func NewHandler(scaled *config.Scaled) Handler {
+	dvs := scaled.Management.LLMFactory.Ml().V1().DatasetVersion()
 	h := Handler{
-		dvCache: scaled.Management.LLMFactory.Ml().V1().DatasetVersion().Cache(),
+		dvCache:  dvs.Cache(),
+		dvClient: dvs,
 	}
 
 	registryCache := scaled.Management.LLMFactory.Ml().V1().Registry().Cache()
@@ -30,12 +61,127 @@ func NewHandler(scaled *config.Scaled) Handler {
 		Ctx:                    scaled.Ctx,
 		GetRegistryAndRootPath: h.GetRegistryAndRootPath,
 		RegistryManager:        registry.NewManager(secretCache.Get, registryCache.Get),
-		PostHooks:              make(map[string]cr.PostHook),
+		PreHooks: map[string]cr.PreHook{
+			cr.ActionUpload:          h.CheckNotFrozen,
+			cr.ActionRemove:          h.CheckNotFrozen,
+			cr.ActionCreateDirectory: h.CheckNotFrozen,
+		},
+		PostHooks: map[string]cr.PostHook{
+			cr.ActionUpload:    h.FilesChanged,
+			cr.ActionRemove:    h.FilesChanged,
+			cr.ActionSyncFiles: h.FilesChanged,
+		},
 	}
 
 	return h
 }
 
+// CheckNotFrozen rejects the actions changing the files of a published dataset version
+func (h Handler) CheckNotFrozen(req *http.Request) error {
+	vars := utils.EncodeVars(mux.Vars(req))
+	namespace, name := vars["namespace"], vars["name"]
+
+	dv, err := h.dvCache.Get(namespace, name)
+	if err != nil {
+		return apierror.NewAPIError(validation.NotFound,
+			fmt.Sprintf("get datasetversion %s/%s failed: %v", namespace, name, err))
+	}
+	if dataset.IsFrozen(dv) {
+		return apierror.NewAPIError(validation.InvalidState,
+			fmt.Sprintf("datasetversion %s/%s is published and its files can't be changed", namespace, name))
+	}
+
+	return nil
+}
+
+// FilesChanged annotates the dataset version to trigger the controller to inspect the files again
+func (h Handler) FilesChanged(req *http.Request, _ backend.Backend) error {
+	vars := utils.EncodeVars(mux.Vars(req))
+	namespace, name := vars["namespace"], vars["name"]
+
+	dv, err := h.dvCache.Get(namespace, name)
+	if err != nil {
+		return fmt.Errorf("get datasetversion %s/%s failed: %w", namespace, name, err)
+	}
+	dvCopy := dv.DeepCopy()
+	if dvCopy.Annotations == nil {
+		dvCopy.Annotations = make(map[string]string)
+	}
+	dvCopy.Annotations[constant.AnnotationDatasetFilesChangedAt] = time.Now().UTC().Format(time.RFC3339Nano)
+	if _, err = h.dvClient.Update(dvCopy); err != nil {
+		return fmt.Errorf("update datasetversion %s/%s failed: %w", namespace, name, err)
+	}
+
+	return nil
+}
+
+// Preview responds the first rows of a split of the dataset version
+func (h Handler) Preview(rw http.ResponseWriter, req *http.Request) {
+	output, err := h.preview(req)
+	if err != nil {
+		logrus.Errorf("preview datasetversion failed: %v", err)
+		var e *apierror.APIError
+		if errors.As(err, &e) {
+			utils.ResponseAPIError(rw, e.Code.Status, e)
+		} else {
+			utils.ResponseError(rw, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	utils.ResponseOKWithBody(rw, output)
+}
+
+func (h Handler) preview(req *http.Request) (*PreviewOutput, error) {
+	if req.Method != http.MethodPost {
+		return nil, apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("Unsupported method %s", req.Method))
+	}
+	vars := utils.EncodeVars(mux.Vars(req))
+	namespace, name := vars["namespace"], vars["name"]
+
+	input := &PreviewInput{}
+	if req.ContentLength != 0 {
+		if err := json.NewDecoder(req.Body).Decode(input); err != nil {
+			return nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
+		}
+	}
+	if input.Split == "" {
+		input.Split = dataset.SplitTrain
+	}
+	if input.Limit < 0 || input.Limit > dataset.MaxPreviewRows {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent,
+			fmt.Sprintf("limit must be between 0 and %d", dataset.MaxPreviewRows))
+	}
+
+	dv, err := h.dvCache.Get(namespace, name)
+	if err != nil {
+		return nil, apierror.NewAPIError(validation.NotFound,
+			fmt.Sprintf("get datasetversion %s/%s failed: %v", namespace, name, err))
+	}
+	if !mlv1.Ready.IsTrue(dv) {
+		return nil, apierror.NewAPIError(validation.InvalidState,
+			fmt.Sprintf("datasetversion %s/%s is not ready", namespace, name))
+	}
+
+	b, err := h.RegistryManager.NewBackendFromRegistry(req.Context(), dv.Status.Registry)
+	if err != nil {
+		return nil, fmt.Errorf(registry.ErrCreateBackendClient, err)
+	}
+	files, err := b.List(req.Context(), dv.Status.RootPath, true, true)
+	if err != nil {
+		return nil, fmt.Errorf("list files of datasetversion %s/%s failed: %w", namespace, name, err)
+	}
+	rows, err := dataset.Preview(req.Context(), b, dv.Status.RootPath, files, input.Split, input.Limit)
+	if err != nil {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent, err.Error())
+	}
+
+	output := &PreviewOutput{Split: input.Split, Rows: rows}
+	if dv.Status.Schema != nil {
+		output.Features = dv.Status.Schema.Features
+	}
+	return output, nil
+}
+
 func (h Handler) GetRegistryAndRootPath(namespace, name string) (string, string, error) {
 	return GetDatasetVersionRegistryAndRootPath(h.dvCache.Get, namespace, name)
 }
@@ -19,12 +19,22 @@ func Formatter(request *types.APIRequest, resource *types.RawResource) {
 	if request.AccessControl.CanUpdate(request, resource.APIObject, resource.Schema) != nil {
 		return
 	}
-	resource.AddAction(request, cr.ActionUpload)
+	// the files of the published dataset version can't be changed
+	if !isFrozen(resource) {
+		resource.AddAction(request, cr.ActionUpload)
+		resource.AddAction(request, cr.ActionRemove)
+		resource.AddAction(request, cr.ActionCreateDirectory)
+	}
 	resource.AddAction(request, cr.ActionDownload)
 	resource.AddAction(request, cr.ActionList)
-	resource.AddAction(request, cr.ActionRemove)
-	resource.AddAction(request, cr.ActionCreateDirectory)
 	resource.AddAction(request, cr.ActionGeneratePresignedURL)
+	resource.AddAction(request, cr.ActionSyncFiles)
+	resource.AddAction(request, ActionPreview)
+}
+
+func isFrozen(resource *types.RawResource) bool {
+	data := resource.APIObject.Data()
+	return data.Bool("spec", "publish") || data.Map("status", "manifest") != nil
 }
 
 func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
@@ -36,6 +46,7 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
 	server.BaseSchemas.MustImportAndCustomize(cr.RemoveInput{}, nil)
 	server.BaseSchemas.MustImportAndCustomize(cr.CreateDirectoryInput{}, nil)
 	server.BaseSchemas.MustImportAndCustomize(cr.GeneratePresignedURLInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(PreviewInput{}, nil)
 
 	customizeFunc := func(s *types.APISchema) {
 		s.Formatter = Formatter
@@ -58,6 +69,10 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
 			cr.ActionGeneratePresignedURL: {
 				Input: "generatePresignedURLInput",
 			},
+			cr.ActionSyncFiles: {},
+			ActionPreview: {
+				Input: "previewInput",
+			},
 		}
 		s.ActionHandlers = map[string]http.Handler{
 			cr.ActionUpload:               h,
@@ -66,6 +81,8 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
 			cr.ActionRemove:               h,
 			cr.ActionCreateDirectory:      h,
 			cr.ActionGeneratePresignedURL: h,
+			cr.ActionSyncFiles:            h,
+			ActionPreview:                 http.HandlerFunc(h.Preview),
 		}
 	}
 
@@ -0,0 +1,179 @@
+package lineage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	"github.com/sirupsen/logrus"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/indexeres"
+	"github.com/llmos-ai/llmos-operator/pkg/lineage"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+	"github.com/llmos-ai/llmos-operator/pkg/utils"
+)
+
+var kinds = []string{
+	lineage.KindDatasetVersion,
+	lineage.KindModel,
+	lineage.KindLocalModelVersion,
+	lineage.KindModelService,
+}
+
+// kindSchemaIDs are the schemas of the kinds, which are used to check whether the objects of the graph can be read
+var kindSchemaIDs = map[string]string{
+	lineage.KindDatasetVersion:    "ml.llmos.ai.datasetversion",
+	lineage.KindModel:             "ml.llmos.ai.model",
+	lineage.KindLocalModelVersion: "ml.llmos.ai.localmodelversion",
+	lineage.KindModelService:      "ml.llmos.ai.modelservice",
+}
+
+type GraphInput struct {
+	// Kind is one of DatasetVersion, Model, LocalModelVersion and ModelService
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Direction is one of upstream, downstream and both, default to both
+	Direction string `json:"direction,omitempty"`
+	// Depth is the max number of edges walked from the object, default to 5 and at most 20
+	Depth int `json:"depth,omitempty"`
+}
+
+type Handler struct {
+	lineageEdgeCache ctlmlv1.LineageEdgeCache
+}
+
+func NewHandler(scaled *config.Scaled) Handler {
+	return Handler{
+		lineageEdgeCache: scaled.Management.LLMFactory.Ml().V1().LineageEdge().Cache(),
+	}
+}
+
+// ServeHTTP responds the lineage graph of the object
+func (h Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	graph, err := h.graph(req)
+	if err != nil {
+		logrus.Errorf("get lineage graph failed: %v", err)
+		var e *apierror.APIError
+		if errors.As(err, &e) {
+			utils.ResponseAPIError(rw, e.Code.Status, e)
+		} else {
+			utils.ResponseError(rw, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	utils.ResponseOKWithBody(rw, graph)
+}
+
+func (h Handler) graph(req *http.Request) (*lineage.Graph, error) {
+	return h.graphOf(req, h.edgesTo, h.edgesFrom)
+}
+
+func (h Handler) graphOf(req *http.Request, upstream, downstream lineage.EdgeGetter) (*lineage.Graph, error) {
+	if req.Method != http.MethodPost {
+		return nil, apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("Unsupported method %s", req.Method))
+	}
+
+	input := &GraphInput{}
+	if err := json.NewDecoder(req.Body).Decode(input); err != nil {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
+	}
+	if err := validateGraphInput(input); err != nil {
+		return nil, err
+	}
+
+	root := mlv1.LineageObjectReference{
+		Kind:      input.Kind,
+		Namespace: input.Namespace,
+		Name:      input.Name,
+	}
+
+	// the edges are read from the cache of the operator, so the access of the user is checked here
+	apiOp := types.GetAPIContext(req.Context())
+	if apiOp == nil || apiOp.AccessControl == nil ||
+		apiOp.AccessControl.CanDo(apiOp, lineageEdgeSchemaID, "list", root.Namespace, "") != nil {
+		return nil, apierror.NewAPIError(validation.PermissionDenied,
+			fmt.Sprintf("not allowed to list lineage edges in namespace %s", root.Namespace))
+	}
+	canRead := newReadChecker(apiOp)
+	if !canRead(root) {
+		return nil, apierror.NewAPIError(validation.PermissionDenied,
+			fmt.Sprintf("not allowed to get %s %s/%s", root.Kind, root.Namespace, root.Name))
+	}
+
+	return lineage.BuildGraph(root, input.Direction, input.Depth,
+		readableEdges(upstream, canRead), readableEdges(downstream, canRead))
+}
+
+func (h Handler) edgesTo(key string) ([]*mlv1.LineageEdge, error) {
+	return h.lineageEdgeCache.GetByIndex(indexeres.LineageEdgeTargetIndex, key)
+}
+
+func (h Handler) edgesFrom(key string) ([]*mlv1.LineageEdge, error) {
+	return h.lineageEdgeCache.GetByIndex(indexeres.LineageEdgeSourceIndex, key)
+}
+
+// newReadChecker returns whether the user of the request can get the object, the results are cached for the
+// request since an object may be linked by many edges
+func newReadChecker(apiOp *types.APIRequest) func(ref mlv1.LineageObjectReference) bool {
+	checked := map[string]bool{}
+	return func(ref mlv1.LineageObjectReference) bool {
+		key := lineage.Key(ref)
+		if allowed, ok := checked[key]; ok {
+			return allowed
+		}
+		schemaID, ok := kindSchemaIDs[ref.Kind]
+		allowed := ok && apiOp.AccessControl.CanDo(apiOp, schemaID, "get", ref.Namespace, ref.Name) == nil
+		checked[key] = allowed
+		return allowed
+	}
+}
+
+// readableEdges drops the edges linking the objects the user can't read, so that the objects of the other
+// namespaces aren't leaked by the graph
+func readableEdges(getEdges lineage.EdgeGetter, canRead func(ref mlv1.LineageObjectReference) bool) lineage.EdgeGetter {
+	return func(key string) ([]*mlv1.LineageEdge, error) {
+		edges, err := getEdges(key)
+		if err != nil {
+			return nil, err
+		}
+		readable := make([]*mlv1.LineageEdge, 0, len(edges))
+		for _, e := range edges {
+			if canRead(e.Spec.Source) && canRead(e.Spec.Target) {
+				readable = append(readable, e)
+			}
+		}
+		return readable, nil
+	}
+}
+
+func validateGraphInput(input *GraphInput) error {
+	if !slices.Contains(kinds, input.Kind) {
+		return apierror.NewAPIError(validation.InvalidBodyContent,
+			fmt.Sprintf("kind must be one of %v", kinds))
+	}
+	if input.Namespace == "" || input.Name == "" {
+		return apierror.NewAPIError(validation.InvalidBodyContent, "namespace and name are required")
+	}
+	if input.Direction == "" {
+		input.Direction = lineage.DirectionBoth
+	}
+	if input.Direction != lineage.DirectionUpstream && input.Direction != lineage.DirectionDownstream &&
+		input.Direction != lineage.DirectionBoth {
+		return apierror.NewAPIError(validation.InvalidBodyContent,
+			fmt.Sprintf("direction must be one of %s, %s and %s",
+				lineage.DirectionUpstream, lineage.DirectionDownstream, lineage.DirectionBoth))
+	}
+	if input.Depth < 0 || input.Depth > lineage.MaxDepth {
+		return apierror.NewAPIError(validation.InvalidBodyContent,
+			fmt.Sprintf("depth must be between 0 and %d", lineage.MaxDepth))
+	}
+	return nil
+}
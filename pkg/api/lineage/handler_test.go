@@ -0,0 +1,74 @@
+package lineage
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/lineage"
+)
+
+// fakeAccessControl allows the user to do anything in the namespaces only
+type fakeAccessControl struct {
+	types.AccessControl
+	namespaces map[string]bool
+}
+
+func (a fakeAccessControl) CanDo(_ *types.APIRequest, _, _, namespace, _ string) error {
+	if !a.namespaces[namespace] {
+		return fmt.Errorf("forbidden")
+	}
+	return nil
+}
+
+func newGraphRequest(body string, namespaces ...string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/v1/ml.llmos.ai.lineageedges?action=graph",
+		bytes.NewBufferString(body))
+	allowed := map[string]bool{}
+	for _, ns := range namespaces {
+		allowed[ns] = true
+	}
+	return types.StoreAPIContext(&types.APIRequest{
+		Request:       req,
+		AccessControl: fakeAccessControl{namespaces: allowed},
+	}).Request
+}
+
+func TestGraphAccessControl(t *testing.T) {
+	ref := func(kind, namespace, name string) mlv1.LineageObjectReference {
+		return mlv1.LineageObjectReference{Kind: kind, Namespace: namespace, Name: name}
+	}
+	model := ref(lineage.KindModel, "team-a", "qwen-ft")
+	edges := []*mlv1.LineageEdge{
+		{Spec: mlv1.LineageEdgeSpec{Type: mlv1.LineageEdgeTypeTrain,
+			Source: ref(lineage.KindDatasetVersion, "team-a", "news-v1"), Target: model}},
+		{Spec: mlv1.LineageEdgeSpec{Type: mlv1.LineageEdgeTypeTrain,
+			Source: ref(lineage.KindDatasetVersion, "team-b", "secret-v1"), Target: model}},
+	}
+	h := Handler{}
+	upstream := func(key string) ([]*mlv1.LineageEdge, error) {
+		if key == lineage.Key(model) {
+			return edges, nil
+		}
+		return nil, nil
+	}
+	body := `{"kind": "Model", "namespace": "team-a", "name": "qwen-ft", "direction": "upstream"}`
+
+	// the caller can't read the namespace of the root object
+	_, err := h.graphOf(newGraphRequest(body, "team-b"), upstream, upstream)
+	assert.Error(t, err)
+
+	// the dataset version of the other namespace is dropped
+	graph, err := h.graphOf(newGraphRequest(body, "team-a"), upstream, upstream)
+	require.NoError(t, err)
+	assert.Equal(t, []mlv1.LineageObjectReference{ref(lineage.KindDatasetVersion, "team-a", "news-v1"), model},
+		graph.Nodes)
+	assert.Len(t, graph.Edges, 1)
+}
@@ -0,0 +1,47 @@
+package lineage
+
+import (
+	"net/http"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/steve/pkg/schema"
+	"github.com/rancher/steve/pkg/server"
+	"github.com/rancher/wrangler/v3/pkg/schemas"
+
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+const (
+	lineageEdgeSchemaID = "ml.llmos.ai.lineageedge"
+	ActionGraph         = "graph"
+)
+
+func CollectionFormatter(request *types.APIRequest, collection *types.GenericCollection) {
+	collection.AddAction(request, ActionGraph)
+}
+
+func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
+	h := NewHandler(scaled)
+
+	server.BaseSchemas.MustImportAndCustomize(GraphInput{}, nil)
+
+	t := []schema.Template{
+		{
+			ID: lineageEdgeSchemaID,
+			Customize: func(s *types.APISchema) {
+				s.CollectionFormatter = CollectionFormatter
+				s.CollectionActions = map[string]schemas.Action{
+					ActionGraph: {
+						Input: "graphInput",
+					},
+				}
+				s.ActionHandlers = map[string]http.Handler{
+					ActionGraph: h,
+				}
+			},
+		},
+	}
+
+	server.SchemaFactory.AddTemplate(t...)
+	return nil
+}
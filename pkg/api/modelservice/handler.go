@@ -0,0 +1,100 @@
+package modelservice
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	"github.com/sirupsen/logrus"
+
+	ctlmodelservice "github.com/llmos-ai/llmos-operator/pkg/controller/master/modelservice"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/utils"
+)
+
+type Handler struct {
+	modelServiceClient ctlmlv1.ModelServiceClient
+	modelServiceCache  ctlmlv1.ModelServiceCache
+}
+
+func (h Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if err := h.do(req); err != nil {
+		logrus.Errorf("do modelService action failed: %v", err)
+		var e *apierror.APIError
+		if errors.As(err, &e) {
+			utils.ResponseAPIError(rw, e.Code.Status, e)
+			return
+		}
+		utils.ResponseError(rw, http.StatusInternalServerError, err)
+		return
+	}
+	utils.ResponseOKWithNoContent(rw)
+}
+
+func (h Handler) do(req *http.Request) error {
+	vars := utils.EncodeVars(mux.Vars(req))
+	if req.Method != http.MethodPost {
+		return apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("Unsupported method %s", req.Method))
+	}
+
+	action := vars["action"]
+	namespace, name := vars["namespace"], vars["name"]
+	switch action {
+	case ActionPromote:
+		return h.promote(namespace, name)
+	case ActionRollback:
+		return h.rollback(namespace, name)
+	default:
+		return apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("Unsupported action %s", action))
+	}
+}
+
+// promote replaces the stable revision with the canary revision, the canary pods keep serving
+// until the stable revision is rolled out.
+func (h Handler) promote(namespace, name string) error {
+	ms, err := h.modelServiceCache.Get(namespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to get modelService %s/%s: %w", namespace, name, err)
+	}
+
+	canary := ms.Spec.Canary
+	if canary == nil {
+		return apierror.NewAPIError(validation.InvalidAction,
+			fmt.Sprintf("modelService %s/%s has no canary to promote", namespace, name))
+	}
+
+	msCopy := ms.DeepCopy()
+	if canary.ModelName != "" {
+		msCopy.Spec.ModelName = canary.ModelName
+	}
+	ctlmodelservice.ApplyCanaryOverrides(&msCopy.Spec.Template, canary)
+	msCopy.Spec.Canary = nil
+
+	if _, err = h.modelServiceClient.Update(msCopy); err != nil {
+		return fmt.Errorf("failed to promote canary of modelService %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// rollback removes the canary revision and keeps the stable revision
+func (h Handler) rollback(namespace, name string) error {
+	ms, err := h.modelServiceCache.Get(namespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to get modelService %s/%s: %w", namespace, name, err)
+	}
+
+	if ms.Spec.Canary == nil {
+		return apierror.NewAPIError(validation.InvalidAction,
+			fmt.Sprintf("modelService %s/%s has no canary to rollback", namespace, name))
+	}
+
+	msCopy := ms.DeepCopy()
+	msCopy.Spec.Canary = nil
+	if _, err = h.modelServiceClient.Update(msCopy); err != nil {
+		return fmt.Errorf("failed to rollback canary of modelService %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
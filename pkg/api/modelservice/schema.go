@@ -0,0 +1,59 @@
+package modelservice
+
+import (
+	"net/http"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/steve/pkg/schema"
+	"github.com/rancher/steve/pkg/server"
+	"github.com/rancher/wrangler/v3/pkg/schemas"
+
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+const (
+	modelServiceSchemaID = "ml.llmos.ai.modelservice"
+	ActionPromote        = "promote"
+	ActionRollback       = "rollback"
+)
+
+func Formatter(request *types.APIRequest, resource *types.RawResource) {
+	resource.Actions = make(map[string]string, 1)
+	if request.AccessControl.CanUpdate(request, resource.APIObject, resource.Schema) != nil {
+		return
+	}
+
+	canary := resource.APIObject.Data().Map("spec", "canary")
+	if len(canary) > 0 {
+		resource.AddAction(request, ActionPromote)
+		resource.AddAction(request, ActionRollback)
+	}
+}
+
+func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
+	modelServices := scaled.Management.LLMFactory.Ml().V1().ModelService()
+	h := Handler{
+		modelServiceClient: modelServices,
+		modelServiceCache:  modelServices.Cache(),
+	}
+
+	t := []schema.Template{
+		{
+			ID: modelServiceSchemaID,
+			Customize: func(s *types.APISchema) {
+				s.Formatter = Formatter
+				s.ResourceActions = map[string]schemas.Action{
+					ActionPromote:  {},
+					ActionRollback: {},
+				}
+				s.ActionHandlers = map[string]http.Handler{
+					ActionPromote:  h,
+					ActionRollback: h,
+				}
+			},
+		},
+	}
+
+	server.SchemaFactory.AddTemplate(t...)
+	return nil
+}
@@ -5,11 +5,14 @@ import (
 
 	"github.com/rancher/steve/pkg/server"
 
+	"github.com/llmos-ai/llmos-operator/pkg/api/auditlog"
 	"github.com/llmos-ai/llmos-operator/pkg/api/chat"
 	"github.com/llmos-ai/llmos-operator/pkg/api/datacollection"
 	"github.com/llmos-ai/llmos-operator/pkg/api/datasetversion"
 	"github.com/llmos-ai/llmos-operator/pkg/api/knowledgebase"
+	"github.com/llmos-ai/llmos-operator/pkg/api/lineage"
 	"github.com/llmos-ai/llmos-operator/pkg/api/model"
+	"github.com/llmos-ai/llmos-operator/pkg/api/modelservice"
 	"github.com/llmos-ai/llmos-operator/pkg/api/token"
 	"github.com/llmos-ai/llmos-operator/pkg/api/user"
 	"github.com/llmos-ai/llmos-operator/pkg/server/config"
@@ -22,9 +25,12 @@ var registers = []registerSchema{
 	user.RegisterSchema,
 	token.RegisterSchema,
 	model.RegisterSchema,
+	modelservice.RegisterSchema,
 	datasetversion.RegisterSchema,
 	datacollection.RegisterSchema,
 	knowledgebase.RegisterSchema,
+	lineage.RegisterSchema,
+	auditlog.RegisterSchema,
 }
 
 func registerSchemas(scaled *config.Scaled, server *server.Server, registers ...registerSchema) error {
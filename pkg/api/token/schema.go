@@ -2,18 +2,24 @@ package token
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/gorilla/mux"
+	"github.com/rancher/apiserver/pkg/apierror"
 	"github.com/rancher/apiserver/pkg/types"
 	"github.com/rancher/steve/pkg/schema"
 	"github.com/rancher/steve/pkg/server"
+	"github.com/rancher/wrangler/v3/pkg/schemas"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/labels"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/auth"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/serviceaccount"
 	tokens2 "github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
 	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/server/config"
@@ -22,7 +28,9 @@ import (
 )
 
 const (
-	tokenSchemaID = "management.llmos.ai.token"
+	tokenSchemaID   = "management.llmos.ai.token"
+	ActionRevoke    = "revoke"
+	ActionRevokeAll = "revokeAll"
 )
 
 type handler struct {
@@ -31,13 +39,19 @@ type handler struct {
 	tokensCache ctlmgmtv1.TokenCache
 	manager     *tokens2.Manager
 	middleware  *auth.Middleware
+	sa          *serviceaccount.Manager
 }
 
-func formatter(_ *types.APIRequest, resource *types.RawResource) {
-	resource.Actions = nil
+func formatter(request *types.APIRequest, resource *types.RawResource) {
+	resource.Actions = make(map[string]string, 1)
+	resource.AddAction(request, ActionRevoke)
 	delete(resource.Links, "update")
 }
 
+func collectionFormatter(request *types.APIRequest, collection *types.GenericCollection) {
+	collection.AddAction(request, ActionRevokeAll)
+}
+
 func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
 	tokens := scaled.MgmtFactory.Management().V1().Token()
 	h := &handler{
@@ -46,6 +60,7 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
 		tokensCache: tokens.Cache(),
 		manager:     tokens2.NewManager(scaled),
 		middleware:  auth.NewMiddleware(scaled),
+		sa:          serviceaccount.NewManager(scaled),
 	}
 
 	t := []schema.Template{
@@ -55,6 +70,17 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
 			Customize: func(apiSchema *types.APISchema) {
 				apiSchema.CreateHandler = h.createHandler
 				apiSchema.ListHandler = h.listHandler
+				apiSchema.CollectionFormatter = collectionFormatter
+				apiSchema.ResourceActions = map[string]schemas.Action{
+					ActionRevoke: {},
+				}
+				apiSchema.CollectionActions = map[string]schemas.Action{
+					ActionRevokeAll: {},
+				}
+				apiSchema.ActionHandlers = map[string]http.Handler{
+					ActionRevoke:    h,
+					ActionRevokeAll: h,
+				}
 			},
 		},
 	}
@@ -89,11 +115,22 @@ func (h *handler) createHandler(request *types.APIRequest) (types.APIObject, err
 }
 
 func (h *handler) constructAPIKeyToken(req *http.Request, token *mgmtv1.Token) (*mgmtv1.Token, string, error) {
-	user, _, err := h.getUserBySessionToken(req)
+	user, sessionToken, err := h.getUserBySessionToken(req)
 	if err != nil {
 		return nil, "", err
 	}
 
+	// the scoped API keys can't create the API keys, which would escape their scopes
+	if len(sessionToken.Spec.Scopes) > 0 {
+		return nil, "", fmt.Errorf("scoped API keys are not allowed to create API keys")
+	}
+	if err = tokens2.ValidateScopes(token.Spec.Scopes); err != nil {
+		return nil, "", err
+	}
+	// the client of the API key is taken from the request rather than the body
+	token.Spec.ClientIP = tokens2.ClientIP(req)
+	token.Spec.UserAgent = req.UserAgent()
+
 	return h.generateToken(user.Name, token)
 }
 
@@ -146,8 +183,17 @@ func (h *handler) listHandler(request *types.APIRequest) (types.APIObjectList, e
 		utils.ResponseError(request.Response, http.StatusBadRequest, err)
 		return types.APIObjectList{}, err
 	}
+	// the API keys of the service account are listed by the service account query for its managers
+	owner := currentUser.Name
+	if name := request.Query.Get("serviceAccount"); name != "" {
+		if err = h.checkServiceAccountManager(currentUser, name); err != nil {
+			utils.ResponseError(request.Response, http.StatusForbidden, err)
+			return types.APIObjectList{}, err
+		}
+		owner = name
+	}
 	selector := labels.SelectorFromSet(map[string]string{
-		tokens2.LabelAuthUserId: currentUser.Name,
+		tokens2.LabelAuthUserId: owner,
 	})
 
 	tokens, err := h.tokensCache.List(selector)
@@ -163,3 +209,71 @@ func (h *handler) listHandler(request *types.APIRequest) (types.APIObjectList, e
 		Objects: result,
 	}, nil
 }
+
+func (h *handler) checkServiceAccountManager(user *mgmtv1.User, name string) error {
+	sa, err := h.middleware.GetUserByName(name)
+	if err != nil {
+		return fmt.Errorf("service account %s not found", name)
+	}
+	allowed, err := h.sa.CanManage(user, sa)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("not allowed to list the API keys of service account %s", name)
+	}
+	return nil
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if err := h.do(req); err != nil {
+		status := http.StatusInternalServerError
+		var e *apierror.APIError
+		if errors.As(err, &e) {
+			status = e.Code.Status
+		}
+		utils.ResponseAPIError(rw, status, e)
+		return
+	}
+	utils.ResponseOKWithNoContent(rw)
+}
+
+func (h *handler) do(req *http.Request) error {
+	if req.Method != http.MethodPost {
+		return apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("Unsupported method %s", req.Method))
+	}
+
+	user, sessionToken, err := h.getUserBySessionToken(req)
+	if err != nil {
+		return apierror.NewAPIError(validation.Unauthorized, err.Error())
+	}
+
+	vars := utils.EncodeVars(mux.Vars(req))
+	action := vars["action"]
+	switch action {
+	case ActionRevoke:
+		return h.revoke(user, vars["name"])
+	case ActionRevokeAll:
+		// the current session is kept so that the user is not logged out
+		if err = h.manager.RevokeUserSessions(user.Name, sessionToken.Name); err != nil {
+			return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to revoke sessions: %v", err))
+		}
+		return nil
+	default:
+		return apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("Unsupported POST action %s", action))
+	}
+}
+
+// revoke deletes the session token or the API key of the user or the service accounts managed by the user, the
+// tokens of the other users are not found
+func (h *handler) revoke(user *mgmtv1.User, name string) error {
+	token, err := h.tokensCache.Get(name)
+	if err != nil || (token.Spec.UserId != user.Name && h.checkServiceAccountManager(user, token.Spec.UserId) != nil) {
+		return apierror.NewAPIError(validation.NotFound, fmt.Sprintf("token %s not found", name))
+	}
+
+	if err = h.manager.RevokeToken(token.Name); err != nil {
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to revoke token: %v", err))
+	}
+	return nil
+}
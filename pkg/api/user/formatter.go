@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -16,28 +17,55 @@ import (
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/auth"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/mfa"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/permissions"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/serviceaccount"
 	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/group"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/roletemplatebinding"
 	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
 	"github.com/llmos-ai/llmos-operator/pkg/utils"
 )
 
 func Formatter(request *types.APIRequest, resource *types.RawResource) {
 	resource.Actions = make(map[string]string, 1)
+	// the users can review their own access, which is checked by the handler
+	resource.AddAction(request, ActionListBindings)
+	resource.AddAction(request, ActionEffectivePermissions)
+	resource.AddAction(request, ActionCanI)
+	// the API keys of the service accounts are managed by the namespace owners as well, which is checked by the handler
+	if len(resource.APIObject.Data().Map("spec", "serviceAccount")) > 0 {
+		resource.AddAction(request, ActionCreateAPIKey)
+		resource.AddAction(request, ActionRotateAPIKey)
+	}
 	if request.AccessControl.CanUpdate(request, resource.APIObject, resource.Schema) != nil {
 		return
 	}
 	resource.AddAction(request, ActionSetIsActive)
+	resource.AddAction(request, ActionUnlock)
+	resource.AddAction(request, ActionResetMFA)
 }
 
 func CollectionFormatter(request *types.APIRequest, collection *types.GenericCollection) {
 	collection.AddAction(request, ActionChangePassword)
 	collection.AddAction(request, ActionSearch)
+	collection.AddAction(request, ActionEnrollMFA)
+	collection.AddAction(request, ActionActivateMFA)
+	collection.AddAction(request, ActionDisableMFA)
 }
 
 type Handler struct {
 	userClient ctlmgmtv1.UserClient
 	userCache  ctlmgmtv1.UserCache
+	groupCache ctlmgmtv1.GroupCache
+	rtbCache   ctlmgmtv1.RoleTemplateBindingCache
 	middleware *auth.Middleware
+	mfa        *mfa.Manager
+	manager    *tokens.Manager
+	sa         *serviceaccount.Manager
+	reviewer   *permissions.Reviewer
 }
 
 func (h Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -68,6 +96,26 @@ func (h Handler) doPost(rw http.ResponseWriter, req *http.Request, vars map[stri
 	switch action {
 	case ActionSetIsActive:
 		return h.setIsActive(name, req)
+	case ActionUnlock:
+		return h.unlock(name, req)
+	case ActionResetMFA:
+		return h.resetMFA(name, req)
+	case ActionEnrollMFA:
+		return h.enrollMFA(req, rw)
+	case ActionActivateMFA:
+		return h.activateMFA(req, rw)
+	case ActionDisableMFA:
+		return h.disableMFA(req)
+	case ActionCreateAPIKey:
+		return h.createAPIKey(name, req, rw)
+	case ActionRotateAPIKey:
+		return h.rotateAPIKey(name, req, rw)
+	case ActionListBindings:
+		return h.listBindings(name, req, rw)
+	case ActionEffectivePermissions:
+		return h.effectivePermissions(name, req, rw)
+	case ActionCanI:
+		return h.canI(name, req, rw)
 	case ActionChangePassword:
 		return h.changeCurrentUserPassword(req)
 	case ActionSearch:
@@ -82,6 +130,9 @@ func (h Handler) setIsActive(name string, req *http.Request) error {
 	if err := json.NewDecoder(req.Body).Decode(input); err != nil {
 		return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
 	}
+	if err := h.checkCanUpdateUser(name, req); err != nil {
+		return err
+	}
 
 	// check if user exists
 	user, err := h.userCache.Get(name)
@@ -94,9 +145,357 @@ func (h Handler) setIsActive(name string, req *http.Request) error {
 	if _, err = h.userClient.Update(userCpy); err != nil {
 		return err
 	}
+
+	// the sessions and API keys of the deactivated user are revoked immediately rather than waiting for the user
+	// status to be reconciled
+	if !input.IsActive {
+		if err = h.manager.RevokeUserTokens(user.Name); err != nil {
+			return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to revoke tokens: %v", err))
+		}
+	}
+	return nil
+}
+
+// checkCanUpdateUser requires the user of the request to be allowed to update the user, since the formatter only
+// hides the action links and any user who can get the user can post the actions
+func (h Handler) checkCanUpdateUser(name string, req *http.Request) error {
+	apiOp := types.GetAPIContext(req.Context())
+	if apiOp == nil || apiOp.AccessControl == nil {
+		return apierror.NewAPIError(validation.PermissionDenied, fmt.Sprintf("not allowed to update user %s", name))
+	}
+	if err := apiOp.AccessControl.CanDo(apiOp, userSchemaID, "update", "", name); err != nil {
+		return apierror.NewAPIError(validation.PermissionDenied, fmt.Sprintf("not allowed to update user %s", name))
+	}
+	return nil
+}
+
+// unlock clears the failed login attempts and the lockout of the user
+func (h Handler) unlock(name string, req *http.Request) error {
+	if err := h.checkCanUpdateUser(name, req); err != nil {
+		return err
+	}
+
+	user, err := h.userCache.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err = h.userClient.UpdateStatus(tokens.ResetFailedLogins(user)); err != nil {
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to unlock user: %v", err))
+	}
+	return nil
+}
+
+// resetMFA disables MFA of the user who lost the authenticator device and the recovery codes
+func (h Handler) resetMFA(name string, req *http.Request) error {
+	if err := h.checkCanUpdateUser(name, req); err != nil {
+		return err
+	}
+
+	user, err := h.userCache.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if err = h.mfa.Disable(user); err != nil {
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to reset MFA: %v", err))
+	}
+	return nil
+}
+
+// getCurrentLocalUser returns the user of the request, only the local users are allowed to manage MFA
+func (h Handler) getCurrentLocalUser(req *http.Request) (*mgmtv1.User, error) {
+	userInfo, authed := request.UserFrom(req.Context())
+	if !authed {
+		return nil, apierror.NewAPIError(validation.Unauthorized, "Unauthorized")
+	}
+
+	user, err := h.userCache.Get(userInfo.GetName())
+	if err != nil {
+		return nil, apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("failed to get user: %v", err))
+	}
+
+	if !tokens.IsLocalUser(user) {
+		return nil, apierror.NewAPIError(validation.InvalidAction,
+			fmt.Sprintf("MFA of the user is managed by auth provider %s", user.Spec.AuthProvider))
+	}
+	return user, nil
+}
+
+func (h Handler) enrollMFA(req *http.Request, rw http.ResponseWriter) error {
+	user, err := h.getCurrentLocalUser(req)
+	if err != nil {
+		return err
+	}
+
+	enrollment, err := h.mfa.Enroll(user)
+	if err != nil {
+		if errors.Is(err, mfa.ErrAlreadyEnabled) {
+			return apierror.NewAPIError(validation.InvalidAction, err.Error())
+		}
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to enroll MFA: %v", err))
+	}
+
+	utils.ResponseOKWithBody(rw, enrollment)
+	return nil
+}
+
+func (h Handler) activateMFA(req *http.Request, rw http.ResponseWriter) error {
+	input := &MFACodeInput{}
+	if err := json.NewDecoder(req.Body).Decode(input); err != nil {
+		return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
+	}
+
+	user, err := h.getCurrentLocalUser(req)
+	if err != nil {
+		return err
+	}
+
+	codes, err := h.mfa.Activate(user, input.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, mfa.ErrInvalidCode):
+			return apierror.NewAPIError(validation.InvalidBodyContent, err.Error())
+		case errors.Is(err, mfa.ErrAlreadyEnabled), errors.Is(err, mfa.ErrNotEnrolled):
+			return apierror.NewAPIError(validation.InvalidAction, err.Error())
+		default:
+			return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to activate MFA: %v", err))
+		}
+	}
+
+	utils.ResponseOKWithBody(rw, &RecoveryCodesOutput{RecoveryCodes: codes})
+	return nil
+}
+
+func (h Handler) disableMFA(req *http.Request) error {
+	input := &MFACodeInput{}
+	if err := json.NewDecoder(req.Body).Decode(input); err != nil {
+		return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
+	}
+
+	user, err := h.getCurrentLocalUser(req)
+	if err != nil {
+		return err
+	}
+	if !user.Status.MFAEnabled {
+		return apierror.NewAPIError(validation.InvalidAction, "MFA is not enabled")
+	}
+	if settings.AuthMFARequired.Get() == constant.TrueStr {
+		return apierror.NewAPIError(validation.InvalidAction, "MFA is required for all local users")
+	}
+
+	// the code is required so that a stolen session can't disable MFA
+	if err = h.mfa.Verify(user, input.Code); err != nil {
+		if errors.Is(err, mfa.ErrInvalidCode) {
+			return apierror.NewAPIError(validation.InvalidBodyContent, err.Error())
+		}
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to verify MFA code: %v", err))
+	}
+
+	if err = h.mfa.Disable(user); err != nil {
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to disable MFA: %v", err))
+	}
 	return nil
 }
 
+// getManagedServiceAccount returns the service account if the user of the request is allowed to manage it
+func (h Handler) getManagedServiceAccount(name string, req *http.Request) (*mgmtv1.User, error) {
+	userInfo, authed := request.UserFrom(req.Context())
+	if !authed {
+		return nil, apierror.NewAPIError(validation.Unauthorized, "Unauthorized")
+	}
+	user, err := h.userCache.Get(userInfo.GetName())
+	if err != nil {
+		return nil, apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("failed to get user: %v", err))
+	}
+
+	sa, err := h.userCache.Get(name)
+	if err != nil {
+		return nil, apierror.NewAPIError(validation.NotFound, fmt.Sprintf("service account %s not found", name))
+	}
+	allowed, err := h.sa.CanManage(user, sa)
+	if err != nil {
+		if errors.Is(err, serviceaccount.ErrNotServiceAccount) {
+			return nil, apierror.NewAPIError(validation.InvalidAction, err.Error())
+		}
+		return nil, apierror.NewAPIError(validation.ServerError, err.Error())
+	}
+	if !allowed {
+		return nil, apierror.NewAPIError(validation.PermissionDenied,
+			fmt.Sprintf("not allowed to manage the API keys of service account %s", name))
+	}
+	return sa, nil
+}
+
+func (h Handler) createAPIKey(name string, req *http.Request, rw http.ResponseWriter) error {
+	input := &serviceaccount.KeyInput{}
+	if err := json.NewDecoder(req.Body).Decode(input); err != nil {
+		return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
+	}
+
+	sa, err := h.getManagedServiceAccount(name, req)
+	if err != nil {
+		return err
+	}
+
+	if err = serviceaccount.ValidateKey(input.TTLSeconds, input.Scopes); err != nil {
+		return apierror.NewAPIError(validation.InvalidBodyContent, err.Error())
+	}
+	token, err := h.sa.CreateKey(sa, input)
+	if err != nil {
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to create API key: %v", err))
+	}
+
+	utils.ResponseOKWithBody(rw, token)
+	return nil
+}
+
+func (h Handler) rotateAPIKey(name string, req *http.Request, rw http.ResponseWriter) error {
+	input := &RotateAPIKeyInput{}
+	if err := json.NewDecoder(req.Body).Decode(input); err != nil {
+		return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
+	}
+
+	sa, err := h.getManagedServiceAccount(name, req)
+	if err != nil {
+		return err
+	}
+
+	token, err := h.sa.RotateKey(sa, input.TokenName)
+	if err != nil {
+		if errors.Is(err, serviceaccount.ErrKeyNotFound) {
+			return apierror.NewAPIError(validation.NotFound, err.Error())
+		}
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to rotate API key: %v", err))
+	}
+
+	utils.ResponseOKWithBody(rw, token)
+	return nil
+}
+
+// checkSelfOrAdmin only allows the admins and the user itself to review the access of the user
+func (h Handler) checkSelfOrAdmin(name string, req *http.Request) error {
+	userInfo, authed := request.UserFrom(req.Context())
+	if !authed {
+		return apierror.NewAPIError(validation.Unauthorized, "Unauthorized")
+	}
+	if userInfo.GetName() == name {
+		return nil
+	}
+
+	current, err := h.userCache.Get(userInfo.GetName())
+	if err != nil {
+		return apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("failed to get user: %v", err))
+	}
+	if !current.Status.IsAdmin {
+		return apierror.NewAPIError(validation.PermissionDenied,
+			fmt.Sprintf("not allowed to review the access of user %s", name))
+	}
+	return nil
+}
+
+// listBindings returns the RoleTemplateBindings of the user to the admins and the user itself
+func (h Handler) listBindings(name string, req *http.Request, rw http.ResponseWriter) error {
+	if err := h.checkSelfOrAdmin(name, req); err != nil {
+		return err
+	}
+
+	groups, err := h.groupCache.List(labels.Everything())
+	if err != nil {
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to list groups: %v", err))
+	}
+	rtbs, err := h.rtbCache.List(labels.Everything())
+	if err != nil {
+		return apierror.NewAPIError(validation.ServerError,
+			fmt.Sprintf("failed to list role template bindings: %v", err))
+	}
+
+	utils.ResponseOKWithBody(rw, userBindings(name, groups, rtbs))
+	return nil
+}
+
+// effectivePermissions returns the rules granted to the user and the bindings that granted them
+func (h Handler) effectivePermissions(name string, req *http.Request, rw http.ResponseWriter) error {
+	input := &EffectivePermissionsInput{}
+	if err := json.NewDecoder(req.Body).Decode(input); err != nil {
+		return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
+	}
+	if err := h.checkSelfOrAdmin(name, req); err != nil {
+		return err
+	}
+
+	user, err := h.userCache.Get(name)
+	if err != nil {
+		return apierror.NewAPIError(validation.NotFound, fmt.Sprintf("user %s not found", name))
+	}
+	rules, err := h.reviewer.EffectiveRules(user, input.Namespace)
+	if err != nil {
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("failed to get effective rules: %v", err))
+	}
+
+	utils.ResponseOKWithBody(rw, &EffectivePermissionsOutput{Rules: rules})
+	return nil
+}
+
+// canI checks whether the user is allowed to perform the request
+func (h Handler) canI(name string, req *http.Request, rw http.ResponseWriter) error {
+	input := &permissions.AccessReview{}
+	if err := json.NewDecoder(req.Body).Decode(input); err != nil {
+		return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to parse body: %v", err))
+	}
+	if input.Verb == "" || input.Resource == "" {
+		return apierror.NewAPIError(validation.InvalidBodyContent, "verb and resource are required")
+	}
+	if err := h.checkSelfOrAdmin(name, req); err != nil {
+		return err
+	}
+
+	user, err := h.userCache.Get(name)
+	if err != nil {
+		return apierror.NewAPIError(validation.NotFound, fmt.Sprintf("user %s not found", name))
+	}
+	result, err := h.reviewer.CanI(user, input)
+	if err != nil {
+		return apierror.NewAPIError(validation.ServerError, err.Error())
+	}
+
+	utils.ResponseOKWithBody(rw, result)
+	return nil
+}
+
+func userBindings(name string, groups []*mgmtv1.Group, rtbs []*mgmtv1.RoleTemplateBinding) *BindingsOutput {
+	output := &BindingsOutput{
+		Groups:   make([]string, 0),
+		Bindings: make([]UserBinding, 0),
+	}
+	groupMap := make(map[string]*mgmtv1.Group, len(groups))
+	for _, g := range groups {
+		groupMap[g.Name] = g
+		if group.HasMember(g, name) {
+			output.Groups = append(output.Groups, g.Name)
+		}
+	}
+
+	for _, rtb := range rtbs {
+		bound, through := roletemplatebinding.BoundUser(rtb, name, groupMap)
+		if !bound {
+			continue
+		}
+		output.Bindings = append(output.Bindings, UserBinding{
+			Name:             rtb.Name,
+			RoleTemplateKind: rtb.RoleTemplateRef.Kind,
+			RoleTemplateName: rtb.RoleTemplateRef.Name,
+			NamespaceId:      rtb.NamespaceId,
+			Group:            through,
+		})
+	}
+	sort.Strings(output.Groups)
+	sort.Slice(output.Bindings, func(i, j int) bool {
+		return output.Bindings[i].Name < output.Bindings[j].Name
+	})
+	return output
+}
+
 func (h Handler) userListHandler(request *types.APIRequest) (types.APIObjectList, error) {
 	if err := request.AccessControl.CanList(request, request.Schema); err != nil {
 		return types.APIObjectList{}, err
@@ -117,14 +516,23 @@ func (h Handler) userListHandler(request *types.APIRequest) (types.APIObjectList
 	}
 
 	if me == "true" || !user.Status.IsAdmin {
-		return types.APIObjectList{
-			Objects: []types.APIObject{
-				{
-					Type:   userSchemaID,
-					ID:     user.Name,
-					Object: user,
-				},
+		objects := []types.APIObject{
+			{
+				Type:   userSchemaID,
+				ID:     user.Name,
+				Object: user,
 			},
+		}
+		// the namespace owners can see the service accounts of their namespaces to manage the API keys
+		if me != "true" {
+			serviceAccounts, err := h.managedServiceAccounts(user)
+			if err != nil {
+				return types.APIObjectList{}, err
+			}
+			objects = append(objects, serviceAccounts...)
+		}
+		return types.APIObjectList{
+			Objects:  objects,
 			Revision: "0",
 		}, nil
 	}
@@ -132,6 +540,32 @@ func (h Handler) userListHandler(request *types.APIRequest) (types.APIObjectList
 	return store.List(request, request.Schema)
 }
 
+func (h Handler) managedServiceAccounts(user *mgmtv1.User) ([]types.APIObject, error) {
+	users, err := h.userCache.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.APIObject, 0)
+	for _, sa := range users {
+		if !tokens.IsServiceAccount(sa) {
+			continue
+		}
+		allowed, err := h.sa.CanManage(user, sa)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			result = append(result, types.APIObject{
+				Type:   userSchemaID,
+				ID:     sa.Name,
+				Object: sa,
+			})
+		}
+	}
+	return result, nil
+}
+
 func (h Handler) changeCurrentUserPassword(req *http.Request) error {
 	input := &ChangePasswordInput{}
 	if err := json.NewDecoder(req.Body).Decode(input); err != nil {
@@ -148,12 +582,20 @@ func (h Handler) changeCurrentUserPassword(req *http.Request) error {
 		return apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("failed to get user: %v", err))
 	}
 
+	if !tokens.IsLocalUser(user) {
+		return apierror.NewAPIError(validation.InvalidAction,
+			fmt.Sprintf("password of the user is managed by auth provider %s", user.Spec.AuthProvider))
+	}
+
 	if valid := tokens.CheckPasswordHash(user.Spec.Password, input.CurrentPassword); !valid {
 		return apierror.NewAPIError(validation.InvalidBodyContent, "Current password is incorrect")
 	}
 
+	// the forced password change is done once the users change their own password, the password reset by the
+	// admins keeps the flag as it is set
 	toUpdate := user.DeepCopy()
 	toUpdate.Spec.Password = input.NewPassword
+	toUpdate.Spec.MustChangePassword = false
 	if _, err = h.userClient.Update(toUpdate); err != nil {
 		return apierror.NewAPIError(validation.ServerError, err.Error())
 	}
@@ -172,10 +614,16 @@ func (h Handler) findUserByName(req *http.Request, rw http.ResponseWriter) error
 		return apierror.NewAPIError(validation.ServerError, err.Error())
 	}
 
-	result := make([]*mgmtv1.User, 0)
+	// the users are returned without the spec, which contains the password hashes
+	result := make([]SearchResult, 0)
 	for _, user := range users {
 		if strings.Contains(user.Spec.Username, input.Name) {
-			result = append(result, user)
+			result = append(result, SearchResult{
+				Name:        user.Name,
+				Username:    user.Spec.Username,
+				DisplayName: user.Spec.DisplayName,
+				Active:      user.Spec.Active,
+			})
 		}
 	}
 
@@ -9,14 +9,28 @@ import (
 	"github.com/rancher/wrangler/v3/pkg/schemas"
 
 	"github.com/llmos-ai/llmos-operator/pkg/auth"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/mfa"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/permissions"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/serviceaccount"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
 	"github.com/llmos-ai/llmos-operator/pkg/server/config"
 )
 
 const (
-	userSchemaID         = "management.llmos.ai.user"
-	ActionSetIsActive    = "setIsActive"
-	ActionChangePassword = "changePassword"
-	ActionSearch         = "search"
+	userSchemaID               = "management.llmos.ai.user"
+	ActionSetIsActive          = "setIsActive"
+	ActionChangePassword       = "changePassword"
+	ActionSearch               = "search"
+	ActionUnlock               = "unlock"
+	ActionResetMFA             = "resetMFA"
+	ActionEnrollMFA            = "enrollMFA"
+	ActionActivateMFA          = "activateMFA"
+	ActionDisableMFA           = "disableMFA"
+	ActionCreateAPIKey         = "createAPIKey"
+	ActionRotateAPIKey         = "rotateAPIKey"
+	ActionListBindings         = "listBindings"
+	ActionEffectivePermissions = "effectivePermissions"
+	ActionCanI                 = "canI"
 )
 
 type SetIsActiveInput struct {
@@ -32,17 +46,81 @@ type SearchInput struct {
 	Name string `json:"name"`
 }
 
+// SearchResult is the user found by the search, which only exposes the fields to identify the user
+type SearchResult struct {
+	Name        string `json:"name"`
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName,omitempty"`
+	Active      bool   `json:"active"`
+}
+
+// MFACodeInput is the TOTP code of the authenticator app or one of the recovery codes
+type MFACodeInput struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesOutput struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// RotateAPIKeyInput is the API key of the service account to rotate
+type RotateAPIKeyInput struct {
+	TokenName string `json:"tokenName"`
+}
+
+// BindingsOutput is the RoleTemplateBindings of the user, including the bindings of the user's groups
+type BindingsOutput struct {
+	Groups   []string      `json:"groups"`
+	Bindings []UserBinding `json:"bindings"`
+}
+
+type UserBinding struct {
+	Name             string `json:"name"`
+	RoleTemplateKind string `json:"roleTemplateKind"`
+	RoleTemplateName string `json:"roleTemplateName"`
+	NamespaceId      string `json:"namespaceId,omitempty"`
+	// Group is the group the user is bound through, it is empty if the user is bound directly
+	Group string `json:"group,omitempty"`
+}
+
+// EffectivePermissionsInput limits the effective rules to the namespace, all rules are returned if it's empty
+type EffectivePermissionsInput struct {
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type EffectivePermissionsOutput struct {
+	Rules []permissions.EffectiveRule `json:"rules"`
+}
+
 func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
 	users := scaled.MgmtFactory.Management().V1().User()
 	h := Handler{
 		userClient: users,
 		userCache:  users.Cache(),
+		groupCache: scaled.MgmtFactory.Management().V1().Group().Cache(),
+		rtbCache:   scaled.MgmtFactory.Management().V1().RoleTemplateBinding().Cache(),
 		middleware: auth.NewMiddleware(scaled),
+		mfa:        mfa.NewManager(scaled.CoreFactory.Core().V1().Secret(), users),
+		manager:    tokens.NewManager(scaled),
+		sa:         serviceaccount.NewManager(scaled),
+		reviewer:   permissions.NewReviewer(scaled),
 	}
 
 	server.BaseSchemas.MustImportAndCustomize(SetIsActiveInput{}, nil)
 	server.BaseSchemas.MustImportAndCustomize(ChangePasswordInput{}, nil)
 	server.BaseSchemas.MustImportAndCustomize(SearchInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(MFACodeInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(RecoveryCodesOutput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(mfa.Enrollment{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(serviceaccount.KeyInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(RotateAPIKeyInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(UserBinding{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(BindingsOutput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(EffectivePermissionsInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(permissions.EffectiveRule{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(EffectivePermissionsOutput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(permissions.AccessReview{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(permissions.AccessReviewResult{}, nil)
 	t := []schema.Template{
 		{
 			ID: userSchemaID,
@@ -55,6 +133,16 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
 					ActionSearch: {
 						Input: "searchInput",
 					},
+					ActionEnrollMFA: {
+						Output: "enrollment",
+					},
+					ActionActivateMFA: {
+						Input:  "mfaCodeInput",
+						Output: "recoveryCodesOutput",
+					},
+					ActionDisableMFA: {
+						Input: "mfaCodeInput",
+					},
 				}
 				s.ListHandler = h.userListHandler
 				s.Formatter = Formatter
@@ -62,11 +150,42 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server) error {
 					ActionSetIsActive: {
 						Input: "setIsActiveInput",
 					},
+					ActionUnlock:   {},
+					ActionResetMFA: {},
+					ActionCreateAPIKey: {
+						Input:  "keyInput",
+						Output: "management.llmos.ai.token",
+					},
+					ActionRotateAPIKey: {
+						Input:  "rotateAPIKeyInput",
+						Output: "management.llmos.ai.token",
+					},
+					ActionListBindings: {
+						Output: "bindingsOutput",
+					},
+					ActionEffectivePermissions: {
+						Input:  "effectivePermissionsInput",
+						Output: "effectivePermissionsOutput",
+					},
+					ActionCanI: {
+						Input:  "accessReview",
+						Output: "accessReviewResult",
+					},
 				}
 				s.ActionHandlers = map[string]http.Handler{
-					ActionSetIsActive:    h,
-					ActionChangePassword: h,
-					ActionSearch:         h,
+					ActionSetIsActive:          h,
+					ActionChangePassword:       h,
+					ActionSearch:               h,
+					ActionUnlock:               h,
+					ActionResetMFA:             h,
+					ActionEnrollMFA:            h,
+					ActionActivateMFA:          h,
+					ActionDisableMFA:           h,
+					ActionCreateAPIKey:         h,
+					ActionRotateAPIKey:         h,
+					ActionListBindings:         h,
+					ActionEffectivePermissions: h,
+					ActionCanI:                 h,
 				}
 			},
 		},
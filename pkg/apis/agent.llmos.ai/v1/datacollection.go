@@ -23,10 +23,115 @@ type DataCollection struct {
 
 type DataCollectionSpec struct {
 	Registry string `json:"registry"`
+
+	// +optional
+	// Sources are the external sources synced into the data collection, the files of each source are written to
+	// the directory named after the source.
+	Sources []DataSource `json:"sources,omitempty"`
+}
+
+// DataSource is an external source of the data collection, exactly one of url, git and s3 is required
+type DataSource struct {
+	// Name is the name of the source, which is unique in the data collection
+	Name string `json:"name"`
+
+	// +optional
+	// Schedule is the cron expression to sync the source in UTC, e.g., "0 */6 * * *". The source is only synced
+	// when it's added or changed if the schedule is not specified.
+	Schedule string `json:"schedule,omitempty"`
+
+	// +optional
+	URL *URLSource `json:"url,omitempty"`
+
+	// +optional
+	Git *GitSource `json:"git,omitempty"`
+
+	// +optional
+	S3 *S3Source `json:"s3,omitempty"`
+}
+
+// URLSource downloads the web pages or files of the urls and the pages listed in the sitemap
+type URLSource struct {
+	// +optional
+	// URLs are the http or https urls to download
+	URLs []string `json:"urls,omitempty"`
+
+	// +optional
+	// Sitemap is the url of the sitemap or sitemap index listing the pages to download
+	Sitemap string `json:"sitemap,omitempty"`
+
+	// +optional
+	// MaxPages is the maximum number of pages downloaded from the sitemap
+	// +kubebuilder:default:=500
+	// +kubebuilder:validation:Minimum:=1
+	MaxPages int32 `json:"maxPages,omitempty"`
+}
+
+// GitSource downloads the files of a git repository at a ref
+type GitSource struct {
+	// Repository is the http or https url of the repository
+	Repository string `json:"repository"`
+
+	// +optional
+	// Ref is the branch or tag to sync, the default branch is synced if not specified
+	Ref string `json:"ref,omitempty"`
+
+	// +optional
+	// Paths are the directories or files in the repository to sync, the whole repository is synced if not
+	// specified
+	Paths []string `json:"paths,omitempty"`
+
+	// +optional
+	// CredentialSecret is the secret in the same namespace with the username and password keys, the password
+	// can be an access token
+	CredentialSecret string `json:"credentialSecret,omitempty"`
+}
+
+// S3Source downloads the objects of a prefix in an S3 compatible bucket
+type S3Source struct {
+	// Endpoint is the endpoint of the S3 compatible service without the scheme, e.g., s3.amazonaws.com
+	Endpoint string `json:"endpoint"`
+
+	Bucket string `json:"bucket"`
+
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// +optional
+	UseSSL bool `json:"useSSL,omitempty"`
+
+	// +optional
+	// CredentialSecret is the secret in the same namespace with the accessKeyID and accessKeySecret keys,
+	// the bucket is accessed anonymously if not specified
+	CredentialSecret string `json:"credentialSecret,omitempty"`
 }
 
 type DataCollectionStatus struct {
 	RootPath   string             `json:"rootPath"`
 	Files      []FileInfo         `json:"files,omitempty"`
 	Conditions []common.Condition `json:"conditions,omitempty"`
+	// Sources are the sync status of the sources
+	Sources []DataSourceStatus `json:"sources,omitempty"`
+}
+
+// DataSourceStatus is the sync status of a source
+type DataSourceStatus struct {
+	Name string `json:"name"`
+	// ObservedGeneration is the generation of the data collection when the source is synced last time
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastAttemptTime is the time of the last sync
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+	// LastSyncTime is the time of the last successful sync
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// NextSyncTime is the time of the next scheduled sync
+	NextSyncTime *metav1.Time `json:"nextSyncTime,omitempty"`
+	// Revision is the commit SHA of the synced git repository
+	Revision string `json:"revision,omitempty"`
+	// ETags are the ETags of the synced urls or objects keyed by the url or the object path in the source,
+	// the unchanged urls or objects are skipped by the next sync
+	ETags map[string]string `json:"etags,omitempty"`
+	// ChangedFiles is the number of files written or deleted by the last successful sync
+	ChangedFiles int32 `json:"changedFiles,omitempty"`
+	// Message is the error of the last sync
+	Message string `json:"message,omitempty"`
 }
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Display Name",type="string",JSONPath=`.spec.displayName`
+// +kubebuilder:printcolumn:name="Members",type="integer",JSONPath=`.status.memberCount`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Group is a team of users, which is bound to the role templates and global roles by the RoleTemplateBinding
+// subjects of the Group kind, and the bindings are expanded to the members of the group
+type Group struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              GroupSpec   `json:"spec"`
+	Status            GroupStatus `json:"status,omitempty"`
+}
+
+type GroupSpec struct {
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Members are the names of the users in the group
+	// +optional
+	Members []string `json:"members,omitempty"`
+}
+
+type GroupStatus struct {
+	// MemberCount is the number of the existing users in the group
+	// +optional
+	MemberCount int `json:"memberCount,omitempty"`
+
+	// MissingMembers are the members of the group whose users don't exist
+	// +optional
+	MissingMembers []string `json:"missingMembers,omitempty"`
+}
@@ -31,9 +31,40 @@ type TokenSpec struct {
 	Expired    bool   `json:"expired,omitempty"`
 	TTLSeconds int64  `json:"ttlSeconds,omitempty"`
 	Token      string `json:"token,omitempty"`
+
+	// Scopes restrict the requests the API key is allowed to make, a request is allowed if it matches any of the
+	// scopes, and all requests of the user are allowed if no scopes are specified
+	// +optional
+	Scopes []TokenScope `json:"scopes,omitempty"`
+
+	// ClientIP and UserAgent are the client the session token is issued to at login
+	// +optional
+	ClientIP string `json:"clientIP,omitempty"`
+	// +optional
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+// TokenScope matches the requests of all its namespaces, resources and verbs, the empty fields match everything
+type TokenScope struct {
+	// Namespaces the requests are allowed in, the cluster-scoped resources are excluded if specified
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Resources are the plural resource names allowed, e.g., modelservices, datasetversions or services/proxy,
+	// "*" matches all resources
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// Verbs are the verbs allowed, e.g., get, list and watch for the read-only access, the resource actions such as
+	// upload are verbs as well, "*" matches all verbs
+	// +optional
+	Verbs []string `json:"verbs,omitempty"`
 }
 
 type TokenStatus struct {
 	ExpiresAt metav1.Time `json:"expiresAt,omitempty"`
 	IsExpired bool        `json:"isExpired"`
+	// LastUsedAt is when the token was last used to authenticate a request, it is updated at most once a minute
+	// +optional
+	LastUsedAt *metav1.Time `json:"lastUsedAt,omitempty"`
 }
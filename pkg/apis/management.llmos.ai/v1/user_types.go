@@ -32,11 +32,46 @@ type UserSpec struct {
 	// +optional
 	Description string `json:"description,omitempty"`
 
+	// Password is the password hash of the local user, it is empty for the users of the external auth providers
 	// +kubebuilder:validation:Required
 	Password string `json:"password"`
 
 	// +kubebuilder:default:=true
 	Active bool `json:"active"`
+
+	// AuthProvider is the name of the auth provider the user is provisioned by, e.g., oidc,
+	// the user is a local user if not specified
+	// +optional
+	AuthProvider string `json:"authProvider,omitempty"`
+
+	// PrincipalID is the unique id of the user in the auth provider, e.g., the subject of the OIDC ID token
+	// +optional
+	PrincipalID string `json:"principalId,omitempty"`
+
+	// MustChangePassword requires the user to change the password before using the API, e.g., the bootstrapped admin
+	// +optional
+	MustChangePassword bool `json:"mustChangePassword,omitempty"`
+
+	// PasswordChangedTime is the time the password was last changed, it is managed by the webhook
+	// +optional
+	PasswordChangedTime *metav1.Time `json:"passwordChangedTime,omitempty"`
+
+	// PasswordHistory are the hashes of the previous passwords that can't be reused, it is managed by the webhook
+	// +optional
+	PasswordHistory []string `json:"passwordHistory,omitempty"`
+
+	// ServiceAccount marks the user as a non-human service account, which can't log in with the password and can
+	// only use the scoped API keys with the mandatory expiry
+	// +optional
+	ServiceAccount *ServiceAccountSpec `json:"serviceAccount,omitempty"`
+}
+
+// ServiceAccountSpec holds the owner of the service account, it is immutable once the service account is created
+type ServiceAccountSpec struct {
+	// Namespace owns the service account, the service account can only be bound to the role templates of the
+	// namespace, and the namespace owners are allowed to manage its API keys
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
 }
 
 type UserStatus struct {
@@ -44,4 +79,24 @@ type UserStatus struct {
 	LastUpdateTime string             `json:"lastUpdateTime,omitempty"`
 	IsAdmin        bool               `json:"isAdmin"`
 	IsActive       bool               `json:"isActive"`
+
+	// Groups are the groups of the user in the auth provider observed at the last login
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// FailedLoginAttempts is the number of consecutive failed login attempts, it is reset by the successful login
+	// +optional
+	FailedLoginAttempts int `json:"failedLoginAttempts,omitempty"`
+
+	// LastFailedLoginTime is the time of the last failed login attempt
+	// +optional
+	LastFailedLoginTime *metav1.Time `json:"lastFailedLoginTime,omitempty"`
+
+	// LockedUntil is the time the user is locked out until after too many failed login attempts
+	// +optional
+	LockedUntil *metav1.Time `json:"lockedUntil,omitempty"`
+
+	// MFAEnabled is true once the user has activated the TOTP multi-factor authentication
+	// +optional
+	MFAEnabled bool `json:"mfaEnabled,omitempty"`
 }
@@ -42,6 +42,23 @@ func NewGlobalRole(namespace, name string, obj GlobalRole) *GlobalRole {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// GroupList is a list of Group resources
+type GroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Group `json:"items"`
+}
+
+func NewGroup(namespace, name string, obj Group) *Group {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("Group").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // ManagedAddonList is a list of ManagedAddon resources
 type ManagedAddonList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -28,6 +28,7 @@ import (
 
 var (
 	GlobalRoleResourceName          = "globalroles"
+	GroupResourceName               = "groups"
 	ManagedAddonResourceName        = "managedaddons"
 	RoleTemplateResourceName        = "roletemplates"
 	RoleTemplateBindingResourceName = "roletemplatebindings"
@@ -61,6 +62,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&GlobalRole{},
 		&GlobalRoleList{},
+		&Group{},
+		&GroupList{},
 		&ManagedAddon{},
 		&ManagedAddonList{},
 		&RoleTemplate{},
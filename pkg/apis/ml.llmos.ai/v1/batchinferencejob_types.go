@@ -0,0 +1,161 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llmos-ai/llmos-operator/pkg/apis/common"
+)
+
+type BatchInferenceJobPhase string
+
+const (
+	BatchInferenceJobPhasePending   BatchInferenceJobPhase = "Pending"
+	BatchInferenceJobPhaseRunning   BatchInferenceJobPhase = "Running"
+	BatchInferenceJobPhaseSucceeded BatchInferenceJobPhase = "Succeeded"
+	BatchInferenceJobPhaseFailed    BatchInferenceJobPhase = "Failed"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=bij;bijs
+// +kubebuilder:printcolumn:name="ModelService",type="string",JSONPath=`.spec.modelService`
+// +kubebuilder:printcolumn:name="Input",type="string",JSONPath=`.spec.input.datasetVersion`
+// +kubebuilder:printcolumn:name="Output",type="string",JSONPath=`.status.outputDatasetVersion`
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Progress",type="string",JSONPath=`.status.progress.percentage`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// BatchInferenceJob runs a ModelService over the records of a DatasetVersion file and writes the outputs as a new
+// DatasetVersion
+type BatchInferenceJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BatchInferenceJobSpec   `json:"spec,omitempty"`
+	Status BatchInferenceJobStatus `json:"status,omitempty"`
+}
+
+// BatchInferenceJobSpec defines the desired state of BatchInferenceJob
+type BatchInferenceJobSpec struct {
+	// +kubebuilder:validation:Required
+	// name of the ModelService in the same namespace to call
+	ModelService string `json:"modelService"`
+
+	// +kubebuilder:validation:Required
+	Input BatchInferenceInput `json:"input"`
+
+	// +kubebuilder:validation:Required
+	Output BatchInferenceOutput `json:"output"`
+
+	// +optional, number of concurrent requests
+	// +kubebuilder:default:=4
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=256
+	Concurrency int32 `json:"concurrency,omitempty"`
+
+	// +optional, number of retries of a failed request, the record is marked as failed once the retries are
+	// exhausted
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=10
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+
+	// +optional, timeout of each request
+	// +kubebuilder:default:="5m"
+	RequestTimeout metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// +optional, maximum number of tokens to generate for each record
+	// +kubebuilder:default:=512
+	// +kubebuilder:validation:Minimum:=1
+	MaxTokens int32 `json:"maxTokens,omitempty"`
+
+	// +optional, sampling temperature as a decimal string, e.g., "0.7", the default of the model service is used
+	// if not specified
+	Temperature string `json:"temperature,omitempty"`
+}
+
+// BatchInferenceInput defines the records to run the model on
+type BatchInferenceInput struct {
+	// +kubebuilder:validation:Required
+	// name of the DatasetVersion in the same namespace
+	DatasetVersion string `json:"datasetVersion"`
+
+	// +kubebuilder:validation:Required
+	// path of the jsonl file in the dataset version, e.g., data/train.jsonl
+	File string `json:"file"`
+
+	// +optional, Go template rendering the prompt with the record as the data, e.g., .article is the article
+	// field of the record. The promptField is used if the template is not specified.
+	PromptTemplate string `json:"promptTemplate,omitempty"`
+
+	// +optional, field of the prompts in the records
+	// +kubebuilder:default:=prompt
+	PromptField string `json:"promptField,omitempty"`
+
+	// +optional, system prompt of each request
+	SystemPrompt string `json:"systemPrompt,omitempty"`
+
+	// +optional, maximum number of records to run, all records are used if not specified
+	// +kubebuilder:validation:Minimum:=0
+	MaxRecords int64 `json:"maxRecords,omitempty"`
+}
+
+// BatchInferenceOutput defines the new DatasetVersion of the outputs, each output record is the input record
+// with the output field, or the error field if the record is failed
+type BatchInferenceOutput struct {
+	// +kubebuilder:validation:Required
+	// name of the Dataset in the same namespace to add the output version to
+	Dataset string `json:"dataset"`
+
+	// +kubebuilder:validation:Required
+	// version of the output DatasetVersion, which must not exist
+	Version string `json:"version"`
+
+	// +optional, field of the outputs in the output records
+	// +kubebuilder:default:=output
+	OutputField string `json:"outputField,omitempty"`
+}
+
+// BatchInferenceJobStatus defines the observed state of BatchInferenceJob
+type BatchInferenceJobStatus struct {
+	// Conditions is a list of conditions representing the status of the BatchInferenceJob
+	Conditions []common.Condition `json:"conditions,omitempty"`
+	// Phase is the phase of the batch inference
+	Phase BatchInferenceJobPhase `json:"phase,omitempty"`
+	// Message is the human-readable message of the batch inference phase
+	Message string `json:"message,omitempty"`
+	// JobName is the name of the Job running the batch inference
+	JobName string `json:"jobName,omitempty"`
+	// OutputDatasetVersion is the name of the DatasetVersion created for the outputs
+	OutputDatasetVersion string `json:"outputDatasetVersion,omitempty"`
+	// StartTime is the time when the batch inference job is created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time when the batch inference is finished
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Progress is the progress of the batch inference
+	Progress BatchInferenceProgress `json:"progress,omitempty"`
+	// RecentFailures are the recent failed records
+	RecentFailures []BatchInferenceFailure `json:"recentFailures,omitempty"`
+}
+
+// BatchInferenceProgress is the number of the processed records, which is updated once a shard of records is
+// written to the output DatasetVersion
+type BatchInferenceProgress struct {
+	// Total is the number of records to run
+	Total int64 `json:"total"`
+	// Succeeded is the number of records with outputs
+	Succeeded int64 `json:"succeeded"`
+	// Failed is the number of records failed after the retries
+	Failed int64 `json:"failed"`
+	// Percentage is the percentage of the processed records, formatted with two fractional digits
+	Percentage string `json:"percentage,omitempty"`
+}
+
+// BatchInferenceFailure is a failed record
+type BatchInferenceFailure struct {
+	// Line is the line number of the record in the input file, starting from 1
+	Line int64 `json:"line"`
+	// Error is the error of the last attempt
+	Error string `json:"error"`
+}
@@ -96,6 +96,73 @@ type DatasetVersionStatus struct {
 	RootPath string `json:"rootPath"`
 	// +optional
 	PublishStatus SnapshottingStatus `json:"publishStatus"`
+	// +optional
+	// Schema is inferred from the files of the dataset version and refreshed once the files are changed
+	Schema *DatasetSchema `json:"schema,omitempty"`
+	// +optional
+	// Manifest records the hashes of the files once the dataset version is published,
+	// the files of the dataset version can't be changed after that
+	Manifest *DatasetManifest `json:"manifest,omitempty"`
+}
+
+// DatasetManifest is the content manifest of a published dataset version
+type DatasetManifest struct {
+	// +optional
+	Files []DatasetManifestFile `json:"files,omitempty"`
+	// Digest is the sha256 digest of the canonical form of the files
+	Digest string `json:"digest"`
+	// +optional
+	// Signature is the base64 encoded ed25519 signature of the digest made with the cluster signing key
+	Signature string `json:"signature,omitempty"`
+	// +optional
+	// KeyID identifies the public key to verify the signature
+	KeyID string `json:"keyID,omitempty"`
+	// +optional
+	CreateTime *metav1.Time `json:"createTime,omitempty"`
+}
+
+type DatasetManifestFile struct {
+	// Path is relative to the root path of the dataset version
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// DatasetSchema is the schema and statistics inferred from the files of a dataset version
+type DatasetSchema struct {
+	// +optional
+	// Format is the file format of the dataset, e.g., csv, json and parquet, or mixed for multiple formats
+	Format string `json:"format,omitempty"`
+	// +optional
+	Features []DatasetFeature `json:"features,omitempty"`
+	// +optional
+	Splits []DatasetSplit `json:"splits,omitempty"`
+	// +optional
+	NumRows int64 `json:"numRows,omitempty"`
+	// +optional
+	// Fingerprint identifies the inspected files, the files are inspected again once the fingerprint changes
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// +optional
+	InspectTime *metav1.Time `json:"inspectTime,omitempty"`
+	// +optional
+	// Message is the error message if the files failed to be inspected
+	Message string `json:"message,omitempty"`
+}
+
+type DatasetFeature struct {
+	Name string `json:"name"`
+	// Type is the inferred data type of the feature, e.g., string, int64, float64, bool, list and struct
+	Type string `json:"type"`
+}
+
+type DatasetSplit struct {
+	// Name is the split name, e.g., train, validation and test
+	Name string `json:"name"`
+	// +optional
+	// Files are the data files of the split relative to the root path of the dataset version
+	Files []string `json:"files,omitempty"`
+	// +optional
+	NumRows int64 `json:"numRows,omitempty"`
 }
 
 type CopyFrom struct {
@@ -0,0 +1,182 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llmos-ai/llmos-operator/pkg/apis/common"
+)
+
+type FineTuneMethod string
+
+const (
+	// FineTuneMethodFull updates all the weights of the base model
+	FineTuneMethodFull FineTuneMethod = "full"
+	// FineTuneMethodLoRA trains a low-rank adapter of the base model
+	FineTuneMethodLoRA FineTuneMethod = "lora"
+	// FineTuneMethodQLoRA trains a low-rank adapter of the 4-bit quantized base model
+	FineTuneMethodQLoRA FineTuneMethod = "qlora"
+)
+
+type FineTuneJobPhase string
+
+const (
+	FineTuneJobPhasePending   FineTuneJobPhase = "Pending"
+	FineTuneJobPhaseRunning   FineTuneJobPhase = "Running"
+	FineTuneJobPhaseSucceeded FineTuneJobPhase = "Succeeded"
+	FineTuneJobPhaseFailed    FineTuneJobPhase = "Failed"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ftj;ftjs
+// +kubebuilder:printcolumn:name="Method",type="string",JSONPath=`.spec.method`
+// +kubebuilder:printcolumn:name="DatasetVersion",type="string",JSONPath=`.spec.datasetVersion`
+// +kubebuilder:printcolumn:name="OutputModel",type="string",JSONPath=`.spec.output.modelName`
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Loss",type="string",JSONPath=`.status.latestMetric.loss`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// FineTuneJob fine-tunes a base model with a published DatasetVersion and uploads the result as a new Model
+type FineTuneJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FineTuneJobSpec   `json:"spec,omitempty"`
+	Status FineTuneJobStatus `json:"status,omitempty"`
+}
+
+// FineTuneJobSpec defines the desired state of FineTuneJob
+type FineTuneJobSpec struct {
+	// +kubebuilder:validation:Required
+	BaseModel FineTuneBaseModel `json:"baseModel"`
+
+	// +kubebuilder:validation:Required
+	// name of the published DatasetVersion in the same namespace, which is mounted from its volume snapshot
+	DatasetVersion string `json:"datasetVersion"`
+
+	// +kubebuilder:validation:Enum:={"full","lora","qlora"}
+	// +kubebuilder:default:=lora
+	Method FineTuneMethod `json:"method,omitempty"`
+
+	// +optional
+	Hyperparameters FineTuneHyperparameters `json:"hyperparameters,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Output FineTuneOutput `json:"output"`
+
+	// +kubebuilder:validation:Required
+	// Image of the trainer, which fine-tunes the base model by the LLMOS_* environment variables:
+	// LLMOS_BASE_MODEL is the local directory of the base model, or a Hugging Face model id;
+	// LLMOS_DATASET_DIR is the directory of the dataset, and LLMOS_DATASET_FIELD is the text field of the samples;
+	// LLMOS_FINETUNE_METHOD is full, lora or qlora, and LLMOS_MERGE_ADAPTER merges the adapter into the base model;
+	// LLMOS_EPOCHS, LLMOS_LEARNING_RATE, LLMOS_BATCH_SIZE, LLMOS_GRADIENT_ACCUMULATION_STEPS, LLMOS_MAX_SEQ_LENGTH,
+	// LLMOS_LORA_RANK, LLMOS_LORA_ALPHA and LLMOS_LORA_DROPOUT are set if the hyperparameters are specified.
+	// The trainer saves the fine-tuned model or adapter to LLMOS_OUTPUT_DIR and appends the training metrics to
+	// LLMOS_METRICS_FILE in json lines, e.g., {"step": 10, "epoch": 0.25, "loss": 1.2345, "learning_rate": 0.0002}
+	Image string `json:"image"`
+
+	// +optional, resources of the trainer container, e.g., the GPUs
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// FineTuneBaseModel is the model to fine-tune, either a Model in the same namespace or a Hugging Face model
+type FineTuneBaseModel struct {
+	// +optional, name of the Model in the same namespace, which is downloaded before training
+	Model string `json:"model,omitempty"`
+
+	// +optional, Hugging Face model id, e.g., Qwen/Qwen2.5-0.5B-Instruct, which is downloaded by the trainer
+	HuggingFaceID string `json:"huggingFaceID,omitempty"`
+}
+
+// FineTuneHyperparameters are passed to the trainer, the defaults of the trainer are used for the unset fields
+type FineTuneHyperparameters struct {
+	// +optional
+	// +kubebuilder:validation:Minimum:=1
+	Epochs int32 `json:"epochs,omitempty"`
+
+	// +optional, decimal string, e.g., "2e-4"
+	LearningRate string `json:"learningRate,omitempty"`
+
+	// +optional, batch size per device
+	// +kubebuilder:validation:Minimum:=1
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum:=1
+	GradientAccumulationSteps int32 `json:"gradientAccumulationSteps,omitempty"`
+
+	// +optional, maximum number of tokens of each training sample
+	// +kubebuilder:validation:Minimum:=1
+	MaxSeqLength int32 `json:"maxSeqLength,omitempty"`
+
+	// +optional, rank of the LoRA adapter, only used by the lora and qlora methods
+	// +kubebuilder:validation:Minimum:=1
+	LoRARank int32 `json:"loraRank,omitempty"`
+
+	// +optional, alpha of the LoRA adapter, only used by the lora and qlora methods
+	// +kubebuilder:validation:Minimum:=1
+	LoRAAlpha int32 `json:"loraAlpha,omitempty"`
+
+	// +optional, dropout of the LoRA adapter as a decimal string, e.g., "0.05"
+	LoRADropout string `json:"loraDropout,omitempty"`
+
+	// +optional, field of the text or the messages in the dataset records
+	// +kubebuilder:default:=text
+	DatasetField string `json:"datasetField,omitempty"`
+}
+
+// FineTuneOutput defines where the fine-tuned model is uploaded
+type FineTuneOutput struct {
+	// +kubebuilder:validation:Required
+	// name of the Registry to upload the fine-tuned model to
+	Registry string `json:"registry"`
+
+	// +kubebuilder:validation:Required
+	// name of the new Model in the same namespace, which must not exist
+	ModelName string `json:"modelName"`
+
+	// +optional, merge the trained adapter into the base model, otherwise the lora and qlora methods output an
+	// adapter of the base model
+	MergeAdapter bool `json:"mergeAdapter,omitempty"`
+}
+
+// FineTuneJobStatus defines the observed state of FineTuneJob
+type FineTuneJobStatus struct {
+	// Conditions is a list of conditions representing the status of the FineTuneJob
+	Conditions []common.Condition `json:"conditions,omitempty"`
+	// Phase is the phase of the fine-tuning
+	Phase FineTuneJobPhase `json:"phase,omitempty"`
+	// Message is the human-readable message of the fine-tuning phase
+	Message string `json:"message,omitempty"`
+	// JobName is the name of the Job running the fine-tuning
+	JobName string `json:"jobName,omitempty"`
+	// OutputModel is the name of the Model created for the fine-tuned model
+	OutputModel string `json:"outputModel,omitempty"`
+	// Adapter is true if the output model is an adapter of the base model
+	Adapter bool `json:"adapter,omitempty"`
+	// StartTime is the time when the fine-tuning job is created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time when the fine-tuning is finished
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// LatestMetric is the latest training metric reported by the trainer
+	LatestMetric *FineTuneMetric `json:"latestMetric,omitempty"`
+	// Metrics are the recent training metrics reported by the trainer, the older metrics are dropped once the
+	// number of metrics exceeds the limit
+	Metrics []FineTuneMetric `json:"metrics,omitempty"`
+}
+
+// FineTuneMetric is a training metric of a step, the decimals are kept as strings
+type FineTuneMetric struct {
+	Step         int64  `json:"step"`
+	Epoch        string `json:"epoch,omitempty"`
+	Loss         string `json:"loss,omitempty"`
+	LearningRate string `json:"learningRate,omitempty"`
+}
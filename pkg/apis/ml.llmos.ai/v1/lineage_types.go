@@ -0,0 +1,66 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type LineageEdgeType string
+
+const (
+	// LineageEdgeTypeCopy links a DatasetVersion to the DatasetVersion copied from it
+	LineageEdgeTypeCopy LineageEdgeType = "Copy"
+	// LineageEdgeTypeTrain links a DatasetVersion to the Model trained or fine-tuned on it
+	LineageEdgeTypeTrain LineageEdgeType = "Train"
+	// LineageEdgeTypeDownload links a Model to the LocalModelVersion downloaded from it
+	LineageEdgeTypeDownload LineageEdgeType = "Download"
+	// LineageEdgeTypeServe links a Model to the ModelService serving it
+	LineageEdgeTypeServe LineageEdgeType = "Serve"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=lineage;lineages
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Source Kind",type="string",JSONPath=`.spec.source.kind`
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=`.spec.source.name`
+// +kubebuilder:printcolumn:name="Target Kind",type="string",JSONPath=`.spec.target.kind`
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=`.spec.target.name`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// LineageEdge records that the target object is derived from the source object.
+// The edges are maintained by the controllers in the namespace of the target object and owned by it,
+// so the lineage is kept after the source object is deleted.
+type LineageEdge struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec LineageEdgeSpec `json:"spec,omitempty"`
+}
+
+type LineageEdgeSpec struct {
+	// +kubebuilder:validation:Enum=Copy;Train;Download;Serve
+	Type   LineageEdgeType        `json:"type"`
+	Source LineageObjectReference `json:"source"`
+	Target LineageObjectReference `json:"target"`
+}
+
+// LineageObjectReference references an object of the lineage graph
+type LineageObjectReference struct {
+	// Kind is one of DatasetVersion, Model, LocalModelVersion and ModelService
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// +optional
+	// UID identifies the object even if it's deleted and recreated with the same name
+	UID types.UID `json:"uid,omitempty"`
+}
+
+// DatasetVersionReference references a version of a dataset
+type DatasetVersionReference struct {
+	// +optional
+	// Namespace defaults to the namespace of the referencing object
+	Namespace string `json:"namespace,omitempty"`
+	Dataset   string `json:"dataset"`
+	Version   string `json:"version"`
+}
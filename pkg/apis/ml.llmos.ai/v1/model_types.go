@@ -27,6 +27,11 @@ type ModelSpec struct {
 	Card *ModelCard `json:"modelCard,omitempty"`
 
 	Registry string `json:"registry"`
+
+	// +optional
+	// TrainedOn references the dataset versions the model is trained or fine-tuned on,
+	// which are recorded as the lineage of the model
+	TrainedOn []DatasetVersionReference `json:"trainedOn,omitempty"`
 }
 
 type ModelStatus struct {
@@ -0,0 +1,174 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llmos-ai/llmos-operator/pkg/apis/common"
+)
+
+type ModelBenchmarkType string
+
+const (
+	// ModelBenchmarkTypePerformance measures the latency and throughput of the model service
+	ModelBenchmarkTypePerformance ModelBenchmarkType = "performance"
+	// ModelBenchmarkTypeAccuracy scores the answers of the model service against the reference answers
+	ModelBenchmarkTypeAccuracy ModelBenchmarkType = "accuracy"
+)
+
+type ModelBenchmarkPhase string
+
+const (
+	ModelBenchmarkPhasePending   ModelBenchmarkPhase = "Pending"
+	ModelBenchmarkPhaseRunning   ModelBenchmarkPhase = "Running"
+	ModelBenchmarkPhaseSucceeded ModelBenchmarkPhase = "Succeeded"
+	ModelBenchmarkPhaseFailed    ModelBenchmarkPhase = "Failed"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=mb;mbs
+// +kubebuilder:printcolumn:name="ModelService",type="string",JSONPath=`.spec.modelService`
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ModelBenchmark runs a benchmark job against a ModelService with the prompts of a DatasetVersion
+type ModelBenchmark struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ModelBenchmarkSpec   `json:"spec,omitempty"`
+	Status ModelBenchmarkStatus `json:"status,omitempty"`
+}
+
+// ModelBenchmarkSpec defines the desired state of ModelBenchmark
+type ModelBenchmarkSpec struct {
+	// +kubebuilder:validation:Required
+	// name of the ModelService in the same namespace to benchmark
+	ModelService string `json:"modelService"`
+
+	// +kubebuilder:validation:Enum:={"performance","accuracy"}
+	// +kubebuilder:default:=performance
+	Type ModelBenchmarkType `json:"type,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Dataset ModelBenchmarkDataset `json:"dataset"`
+
+	// +optional
+	// numbers of concurrent requests, the performance benchmark runs once for each level
+	// and the accuracy benchmark only uses the first level
+	// +kubebuilder:default:={1}
+	// +kubebuilder:validation:MaxItems:=8
+	ConcurrencyLevels []int32 `json:"concurrencyLevels,omitempty"`
+
+	// +optional, duration of each performance benchmark run
+	// +kubebuilder:default:="1m"
+	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// +optional, maximum number of tokens to generate for each request
+	// +kubebuilder:default:=256
+	// +kubebuilder:validation:Minimum:=1
+	MaxTokens int32 `json:"maxTokens,omitempty"`
+}
+
+// ModelBenchmarkDataset defines the prompts of the benchmark
+type ModelBenchmarkDataset struct {
+	// +kubebuilder:validation:Required
+	// name of the DatasetVersion in the same namespace that stores the prompts in json or jsonl files
+	DatasetVersion string `json:"datasetVersion"`
+
+	// +optional, field of the prompts in the dataset records
+	// +kubebuilder:default:=prompt
+	PromptField string `json:"promptField,omitempty"`
+
+	// +optional, field of the reference answers in the dataset records, required by the accuracy benchmark
+	ReferenceField string `json:"referenceField,omitempty"`
+
+	// +optional, maximum number of prompts to use, all prompts are used if not specified
+	// +kubebuilder:validation:Minimum:=0
+	MaxPrompts int32 `json:"maxPrompts,omitempty"`
+}
+
+// ModelBenchmarkStatus defines the observed state of ModelBenchmark
+type ModelBenchmarkStatus struct {
+	// Conditions is a list of conditions representing the status of the ModelBenchmark
+	Conditions []common.Condition `json:"conditions,omitempty"`
+	// Phase is the phase of the benchmark
+	Phase ModelBenchmarkPhase `json:"phase,omitempty"`
+	// Message is the human-readable message of the benchmark phase
+	Message string `json:"message,omitempty"`
+	// JobName is the name of the Job running the benchmark
+	JobName string `json:"jobName,omitempty"`
+	// StartTime is the time when the benchmark job is created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time when the benchmark is finished
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Environment is the serving environment of the model service when the benchmark is started,
+	// it's recorded so that the results of different runs are comparable
+	Environment *ModelBenchmarkEnvironment `json:"environment,omitempty"`
+	// Results are the performance results of each concurrency level
+	Results []ModelBenchmarkResult `json:"results,omitempty"`
+	// Accuracy is the result of the accuracy benchmark
+	Accuracy *ModelBenchmarkAccuracy `json:"accuracy,omitempty"`
+}
+
+// ModelBenchmarkEnvironment is the serving environment of the model service
+type ModelBenchmarkEnvironment struct {
+	// Model is the served model
+	Model string `json:"model,omitempty"`
+	// Replicas is the number of replicas of the model service
+	Replicas int32 `json:"replicas,omitempty"`
+	// Image is the image of the serving engine
+	Image string `json:"image,omitempty"`
+	// Args are the arguments of the serving engine
+	Args []string `json:"args,omitempty"`
+	// Resources are the resources of the serving container
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// NodeSelector is the node selector of the serving pods, e.g., to select the GPU type
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Accelerators are the accelerators of the model service
+	Accelerators map[string]uint8 `json:"accelerators,omitempty"`
+}
+
+// ModelBenchmarkResult is the performance result of a concurrency level, the decimals are formatted with
+// two fractional digits
+type ModelBenchmarkResult struct {
+	// Concurrency is the number of concurrent requests
+	Concurrency int32 `json:"concurrency"`
+	// Requests is the number of finished requests
+	Requests int64 `json:"requests"`
+	// Errors is the number of failed requests
+	Errors int64 `json:"errors"`
+	// ErrorRate is the percentage of failed requests
+	ErrorRate string `json:"errorRate"`
+	// RequestThroughput is the number of successful requests per second
+	RequestThroughput string `json:"requestThroughput"`
+	// OutputTokenThroughput is the number of generated tokens per second
+	OutputTokenThroughput string `json:"outputTokenThroughput"`
+	// TimeToFirstToken is the latency between sending the request and receiving the first token
+	TimeToFirstToken ModelBenchmarkLatency `json:"timeToFirstToken"`
+	// InterTokenLatency is the latency between two consecutive tokens
+	InterTokenLatency ModelBenchmarkLatency `json:"interTokenLatency"`
+}
+
+// ModelBenchmarkLatency is the latency distribution
+type ModelBenchmarkLatency struct {
+	Mean metav1.Duration `json:"mean"`
+	P50  metav1.Duration `json:"p50"`
+	P90  metav1.Duration `json:"p90"`
+	P99  metav1.Duration `json:"p99"`
+}
+
+// ModelBenchmarkAccuracy is the result of the accuracy benchmark
+type ModelBenchmarkAccuracy struct {
+	// Total is the number of scored prompts
+	Total int64 `json:"total"`
+	// Correct is the number of answers matching the reference answers
+	Correct int64 `json:"correct"`
+	// Errors is the number of failed requests
+	Errors int64 `json:"errors"`
+	// Accuracy is the percentage of correct answers, formatted with two fractional digits
+	Accuracy string `json:"accuracy"`
+}
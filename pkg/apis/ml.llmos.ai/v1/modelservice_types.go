@@ -6,6 +6,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/llmos-ai/llmos-operator/pkg/apis/common"
+	"github.com/llmos-ai/llmos-operator/pkg/utils/condition"
+)
+
+var (
+	// ModelDownloaded indicates whether the model weights are downloaded by the init container
+	ModelDownloaded condition.Cond = "ModelDownloaded"
+	// ModelLoaded indicates whether the model weights are loaded by the serving engine
+	ModelLoaded condition.Cond = "ModelLoaded"
 )
 
 // +genclient
@@ -73,6 +81,74 @@ type ModelServiceSpec struct {
 
 	// +optional, library name of the model, e.g., transformers, diffusers, etc.
 	LibraryName string `json:"libraryName,omitempty"`
+
+	// +optional, canary revision that runs side by side with the stable revision, the traffic is split by the
+	// replica counts of the two revisions
+	Canary *ModelServiceCanary `json:"canary,omitempty"`
+
+	// +optional, list of LoRA adapters served on top of the base model, the adapters are loaded at runtime.
+	// Adding the first adapter or removing the last one restarts the pods unless --enable-lora is set in the args
+	// of the serving container
+	// +listType=map
+	// +listMapKey=name
+	Adapters []ModelServiceAdapter `json:"adapters,omitempty"`
+
+	// +optional, serve the model across multiple nodes with a Ray cluster
+	Distributed *ModelServiceDistributed `json:"distributed,omitempty"`
+}
+
+// ModelServiceDistributed defines the multi-node serving of the model service. The model is served by a
+// Ray cluster with tensor parallelism inside a node and pipeline parallelism across the nodes, each node
+// runs a pod with the pod template of the model service.
+type ModelServiceDistributed struct {
+	// +kubebuilder:validation:Minimum:=2
+	// +kubebuilder:validation:Required
+	// number of nodes to serve the model, it is used as the pipeline parallel size
+	PipelineParallelSize int32 `json:"pipelineParallelSize"`
+
+	// +optional, enable the GCS fault tolerance of the Ray cluster
+	EnableGCSFaultTolerance bool `json:"enableGCSFaultTolerance,omitempty"`
+}
+
+// ModelServiceAdapter defines a LoRA adapter stored as a Model in the registry
+type ModelServiceAdapter struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`
+	// name of the adapter to serve in API
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	// name of the Model in the same namespace that stores the adapter weights
+	Model string `json:"model"`
+}
+
+// ModelServiceCanary defines a canary revision of the model service.
+// The traffic split is replica-based, no traffic weight is routed: the canary pods share the service endpoint with
+// the stable revision and the service balances the requests across the ready pods of both revisions, e.g., 1 canary
+// replica next to 3 stable replicas serves about 25% of the traffic. The canary pods use the stable pod template
+// with the overrides of the serving container.
+type ModelServiceCanary struct {
+	// +optional, model of the canary revision, defaults to the stable model
+	ModelName string `json:"model,omitempty"`
+
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Required
+	// number of the canary replicas, which sets the share of the traffic served by the canary revision
+	Replicas int32 `json:"replicas"`
+
+	// +optional, image of the serving container, defaults to the stable image
+	Image string `json:"image,omitempty"`
+
+	// +optional, args of the serving container, replace the stable args if specified
+	Args []string `json:"args,omitempty"`
+
+	// +optional, environment variables of the serving container, override the stable ones of the same names
+	// +listType=map
+	// +listMapKey=name
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// +optional, resources of the serving container, defaults to the stable resources
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 type ModelServiceTemplateSpec struct {
@@ -88,4 +164,31 @@ type ModelServiceStatus struct {
 	ContainerState corev1.ContainerState `json:"containerState,omitempty"`
 	// State is the state of the model service
 	State string `json:"state,omitempty"`
+	// Reason is the brief reason of the model service failure, e.g., OutOfMemory, CUDAError or ModelNotFound
+	Reason string `json:"reason,omitempty"`
+	// Message is the human-readable message explaining the failure reason
+	Message string `json:"message,omitempty"`
+	// Canary is the observed state of the canary revision
+	Canary *ModelServiceCanaryStatus `json:"canary,omitempty"`
+	// Adapters is the observed state of the LoRA adapters
+	Adapters []ModelServiceAdapterStatus `json:"adapters,omitempty"`
+}
+
+type ModelServiceCanaryStatus struct {
+	// ReadyReplicas is the number of ready Pods of the canary revision
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	// ReadyReplicaPercentage is the percentage of the canary revision in the ready replicas of both revisions.
+	// The traffic isn't weighted, it's balanced across the ready pods of both revisions by the service.
+	ReadyReplicaPercentage int32 `json:"readyReplicaPercentage,omitempty"`
+}
+
+type ModelServiceAdapterStatus struct {
+	// Name is the name of the adapter
+	Name string `json:"name"`
+	// Model is the name of the Model that stores the adapter weights
+	Model string `json:"model"`
+	// Ready indicates whether the adapter is loaded by the model service
+	Ready bool `json:"ready"`
+	// Message is the human-readable message of the adapter state
+	Message string `json:"message,omitempty"`
 }
@@ -28,6 +28,16 @@ type Notebook struct {
 
 // NotebookSpec defines the desired state of Dataset
 type NotebookSpec struct {
+	// +optional
+	// ProfileName is the name of the NotebookProfile expanded into the notebook by the webhook, the settings
+	// specified in the notebook take precedence over the profile.
+	ProfileName string `json:"profileName,omitempty"`
+
+	// +optional
+	// ResourcePreset is the name of the resource preset of the profile, the first preset is used if the
+	// resources of the notebook container are not specified.
+	ResourcePreset string `json:"resourcePreset,omitempty"`
+
 	// +optional, template describes the notebooks that will be created.
 	Template NotebookTemplateSpec `json:"template,omitempty"`
 
@@ -48,12 +58,45 @@ type NotebookSpec struct {
 
 	// +optional
 	DatasetMountings []DatasetMounting `json:"datasetMountings,omitempty"`
+
+	// +optional
+	// ModelMountings mounts the local model versions into the notebook as read-only volumes.
+	ModelMountings []ModelMounting `json:"modelMountings,omitempty"`
+
+	// +optional
+	// Schedule stops and starts the notebook on a cron-style schedule, e.g., on working hours.
+	Schedule *NotebookSchedule `json:"schedule,omitempty"`
 }
 
 type NotebookTemplateSpec struct {
 	Spec corev1.PodSpec `json:"spec,omitempty"`
 }
 
+// ModelMounting references a LocalModelVersion to mount, the default version of the LocalModel
+// is mounted if the version is not specified.
+type ModelMounting struct {
+	LocalModel string `json:"localModel"`
+	// +optional
+	// Version is the name of the LocalModelVersion of the LocalModel.
+	Version   string `json:"version,omitempty"`
+	MountPath string `json:"mountPath"`
+}
+
+// NotebookSchedule defines the cron-style working hours of the notebook
+type NotebookSchedule struct {
+	// +optional
+	// Start is the cron expression to start the notebook, e.g., "0 8 * * 1-5".
+	Start string `json:"start,omitempty"`
+
+	// +optional
+	// Stop is the cron expression to stop the notebook, e.g., "0 19 * * 1-5".
+	Stop string `json:"stop,omitempty"`
+
+	// +optional
+	// TimeZone is the IANA time zone of the schedule, e.g., "Asia/Shanghai", defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
 type DatasetMounting struct {
 	DatasetName string `json:"datasetName"`
 	Version     string `json:"version"`
@@ -70,4 +113,17 @@ type NotebookStatus struct {
 	ContainerState corev1.ContainerState `json:"containerState,omitempty"`
 	// State is the state of the notebook
 	State string `json:"state,omitempty"`
+	// LastActivityTime is the last activity time of the notebook reported by the Jupyter server
+	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+	// LastScheduleTime is the last time a start or stop action of the schedule is fired
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// StoppedReason is the reason the notebook is stopped by the controller, either Idle or Schedule
+	StoppedReason NotebookStoppedReason `json:"stoppedReason,omitempty"`
 }
+
+type NotebookStoppedReason string
+
+const (
+	NotebookStoppedReasonIdle     NotebookStoppedReason = "Idle"
+	NotebookStoppedReasonSchedule NotebookStoppedReason = "Schedule"
+)
@@ -0,0 +1,85 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=nbp;nbps,scope=Cluster
+// +kubebuilder:printcolumn:name="Display Name",type="string",JSONPath=`.spec.displayName`
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=`.spec.image`
+// +kubebuilder:printcolumn:name="GPU Type",type="string",JSONPath=`.spec.gpuType`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NotebookProfile is a cluster-level preset of notebooks managed by the admins, the notebooks referencing the
+// profile are expanded with its image, resources, volumes and scheduling settings by the webhook
+type NotebookProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NotebookProfileSpec `json:"spec,omitempty"`
+}
+
+// NotebookProfileSpec defines the notebook settings of the profile
+type NotebookProfileSpec struct {
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Image is the image of the notebook container
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// +optional
+	// ResourcePresets are the resource sizes the users choose from, the first one is used by default
+	// +listType=map
+	// +listMapKey=name
+	ResourcePresets []NotebookResourcePreset `json:"resourcePresets,omitempty"`
+
+	// +optional
+	// GPUType is the GPU product the notebook is scheduled to, e.g., NVIDIA-A100-SXM4-80GB, which selects the
+	// nodes by the nvidia.com/gpu.product label
+	GPUType string `json:"gpuType,omitempty"`
+
+	// +optional
+	// Env is the environment variables of the notebook container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// +optional
+	// Volumes are the volumes of the notebook pod, e.g., the shared memory
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// +optional
+	// VolumeMounts are the mounts of the volumes and the volume claim templates in the notebook container
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// +optional
+	// VolumeClaimTemplates are the persistent volume claims created for the notebook, e.g., the workspace
+	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+
+	// +optional
+	DatasetMountings []DatasetMounting `json:"datasetMountings,omitempty"`
+
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// +optional
+	// NamespaceSelector selects the namespaces whose notebooks may use the profile, the profile is available
+	// to all namespaces if not specified
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// NotebookResourcePreset is a named resource size of the notebook container
+type NotebookResourcePreset struct {
+	Name string `json:"name"`
+
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	Resources corev1.ResourceRequirements `json:"resources"`
+}
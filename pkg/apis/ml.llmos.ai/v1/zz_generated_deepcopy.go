@@ -27,6 +27,185 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceFailure) DeepCopyInto(out *BatchInferenceFailure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceFailure.
+func (in *BatchInferenceFailure) DeepCopy() *BatchInferenceFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceInput) DeepCopyInto(out *BatchInferenceInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceInput.
+func (in *BatchInferenceInput) DeepCopy() *BatchInferenceInput {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceJob) DeepCopyInto(out *BatchInferenceJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceJob.
+func (in *BatchInferenceJob) DeepCopy() *BatchInferenceJob {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BatchInferenceJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceJobList) DeepCopyInto(out *BatchInferenceJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BatchInferenceJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceJobList.
+func (in *BatchInferenceJobList) DeepCopy() *BatchInferenceJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BatchInferenceJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceJobSpec) DeepCopyInto(out *BatchInferenceJobSpec) {
+	*out = *in
+	out.Input = in.Input
+	out.Output = in.Output
+	out.RequestTimeout = in.RequestTimeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceJobSpec.
+func (in *BatchInferenceJobSpec) DeepCopy() *BatchInferenceJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceJobStatus) DeepCopyInto(out *BatchInferenceJobStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]common.Condition, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	out.Progress = in.Progress
+	if in.RecentFailures != nil {
+		in, out := &in.RecentFailures, &out.RecentFailures
+		*out = make([]BatchInferenceFailure, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceJobStatus.
+func (in *BatchInferenceJobStatus) DeepCopy() *BatchInferenceJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceOutput) DeepCopyInto(out *BatchInferenceOutput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceOutput.
+func (in *BatchInferenceOutput) DeepCopy() *BatchInferenceOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchInferenceProgress) DeepCopyInto(out *BatchInferenceProgress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchInferenceProgress.
+func (in *BatchInferenceProgress) DeepCopy() *BatchInferenceProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchInferenceProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CopyFrom) DeepCopyInto(out *CopyFrom) {
 	*out = *in
@@ -88,6 +267,22 @@ func (in *DatasetCard) DeepCopy() *DatasetCard {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatasetFeature) DeepCopyInto(out *DatasetFeature) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasetFeature.
+func (in *DatasetFeature) DeepCopy() *DatasetFeature {
+	if in == nil {
+		return nil
+	}
+	out := new(DatasetFeature)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatasetList) DeepCopyInto(out *DatasetList) {
 	*out = *in
@@ -121,6 +316,47 @@ func (in *DatasetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatasetManifest) DeepCopyInto(out *DatasetManifest) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]DatasetManifestFile, len(*in))
+		copy(*out, *in)
+	}
+	if in.CreateTime != nil {
+		in, out := &in.CreateTime, &out.CreateTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasetManifest.
+func (in *DatasetManifest) DeepCopy() *DatasetManifest {
+	if in == nil {
+		return nil
+	}
+	out := new(DatasetManifest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatasetManifestFile) DeepCopyInto(out *DatasetManifestFile) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasetManifestFile.
+func (in *DatasetManifestFile) DeepCopy() *DatasetManifestFile {
+	if in == nil {
+		return nil
+	}
+	out := new(DatasetManifestFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatasetMetaData) DeepCopyInto(out *DatasetMetaData) {
 	*out = *in
@@ -173,6 +409,38 @@ func (in *DatasetMounting) DeepCopy() *DatasetMounting {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatasetSchema) DeepCopyInto(out *DatasetSchema) {
+	*out = *in
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]DatasetFeature, len(*in))
+		copy(*out, *in)
+	}
+	if in.Splits != nil {
+		in, out := &in.Splits, &out.Splits
+		*out = make([]DatasetSplit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InspectTime != nil {
+		in, out := &in.InspectTime, &out.InspectTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasetSchema.
+func (in *DatasetSchema) DeepCopy() *DatasetSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(DatasetSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatasetSpec) DeepCopyInto(out *DatasetSpec) {
 	*out = *in
@@ -194,6 +462,27 @@ func (in *DatasetSpec) DeepCopy() *DatasetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatasetSplit) DeepCopyInto(out *DatasetSplit) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasetSplit.
+func (in *DatasetSplit) DeepCopy() *DatasetSplit {
+	if in == nil {
+		return nil
+	}
+	out := new(DatasetSplit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatasetStatus) DeepCopyInto(out *DatasetStatus) {
 	*out = *in
@@ -281,6 +570,22 @@ func (in *DatasetVersionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatasetVersionReference) DeepCopyInto(out *DatasetVersionReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasetVersionReference.
+func (in *DatasetVersionReference) DeepCopy() *DatasetVersionReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DatasetVersionReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatasetVersionSpec) DeepCopyInto(out *DatasetVersionSpec) {
 	*out = *in
@@ -311,6 +616,16 @@ func (in *DatasetVersionStatus) DeepCopyInto(out *DatasetVersionStatus) {
 		copy(*out, *in)
 	}
 	in.PublishStatus.DeepCopyInto(&out.PublishStatus)
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(DatasetSchema)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Manifest != nil {
+		in, out := &in.Manifest, &out.Manifest
+		*out = new(DatasetManifest)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -325,27 +640,59 @@ func (in *DatasetVersionStatus) DeepCopy() *DatasetVersionStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalModel) DeepCopyInto(out *LocalModel) {
+func (in *FineTuneBaseModel) DeepCopyInto(out *FineTuneBaseModel) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FineTuneBaseModel.
+func (in *FineTuneBaseModel) DeepCopy() *FineTuneBaseModel {
+	if in == nil {
+		return nil
+	}
+	out := new(FineTuneBaseModel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FineTuneHyperparameters) DeepCopyInto(out *FineTuneHyperparameters) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FineTuneHyperparameters.
+func (in *FineTuneHyperparameters) DeepCopy() *FineTuneHyperparameters {
+	if in == nil {
+		return nil
+	}
+	out := new(FineTuneHyperparameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FineTuneJob) DeepCopyInto(out *FineTuneJob) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalModel.
-func (in *LocalModel) DeepCopy() *LocalModel {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FineTuneJob.
+func (in *FineTuneJob) DeepCopy() *FineTuneJob {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalModel)
+	out := new(FineTuneJob)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LocalModel) DeepCopyObject() runtime.Object {
+func (in *FineTuneJob) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -353,13 +700,13 @@ func (in *LocalModel) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalModelList) DeepCopyInto(out *LocalModelList) {
+func (in *FineTuneJobList) DeepCopyInto(out *FineTuneJobList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]LocalModel, len(*in))
+		*out = make([]FineTuneJob, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -367,7 +714,267 @@ func (in *LocalModelList) DeepCopyInto(out *LocalModelList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalModelList.
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FineTuneJobList.
+func (in *FineTuneJobList) DeepCopy() *FineTuneJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(FineTuneJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FineTuneJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FineTuneJobSpec) DeepCopyInto(out *FineTuneJobSpec) {
+	*out = *in
+	out.BaseModel = in.BaseModel
+	out.Hyperparameters = in.Hyperparameters
+	out.Output = in.Output
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FineTuneJobSpec.
+func (in *FineTuneJobSpec) DeepCopy() *FineTuneJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FineTuneJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FineTuneJobStatus) DeepCopyInto(out *FineTuneJobStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]common.Condition, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LatestMetric != nil {
+		in, out := &in.LatestMetric, &out.LatestMetric
+		*out = new(FineTuneMetric)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]FineTuneMetric, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FineTuneJobStatus.
+func (in *FineTuneJobStatus) DeepCopy() *FineTuneJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FineTuneJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FineTuneMetric) DeepCopyInto(out *FineTuneMetric) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FineTuneMetric.
+func (in *FineTuneMetric) DeepCopy() *FineTuneMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(FineTuneMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FineTuneOutput) DeepCopyInto(out *FineTuneOutput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FineTuneOutput.
+func (in *FineTuneOutput) DeepCopy() *FineTuneOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(FineTuneOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LineageEdge) DeepCopyInto(out *LineageEdge) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LineageEdge.
+func (in *LineageEdge) DeepCopy() *LineageEdge {
+	if in == nil {
+		return nil
+	}
+	out := new(LineageEdge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LineageEdge) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LineageEdgeList) DeepCopyInto(out *LineageEdgeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LineageEdge, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LineageEdgeList.
+func (in *LineageEdgeList) DeepCopy() *LineageEdgeList {
+	if in == nil {
+		return nil
+	}
+	out := new(LineageEdgeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LineageEdgeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LineageEdgeSpec) DeepCopyInto(out *LineageEdgeSpec) {
+	*out = *in
+	out.Source = in.Source
+	out.Target = in.Target
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LineageEdgeSpec.
+func (in *LineageEdgeSpec) DeepCopy() *LineageEdgeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LineageEdgeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LineageObjectReference) DeepCopyInto(out *LineageObjectReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LineageObjectReference.
+func (in *LineageObjectReference) DeepCopy() *LineageObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LineageObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalModel) DeepCopyInto(out *LocalModel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalModel.
+func (in *LocalModel) DeepCopy() *LocalModel {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalModel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LocalModel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalModelList) DeepCopyInto(out *LocalModelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LocalModel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalModelList.
 func (in *LocalModelList) DeepCopy() *LocalModelList {
 	if in == nil {
 		return nil
@@ -500,55 +1107,289 @@ func (in *LocalModelVersionSpec) DeepCopy() *LocalModelVersionSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalModelVersionStatus) DeepCopyInto(out *LocalModelVersionStatus) {
+func (in *LocalModelVersionStatus) DeepCopyInto(out *LocalModelVersionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]common.Condition, len(*in))
+		copy(*out, *in)
+	}
+	in.SnapshottingStatus.DeepCopyInto(&out.SnapshottingStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalModelVersionStatus.
+func (in *LocalModelVersionStatus) DeepCopy() *LocalModelVersionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalModelVersionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Model) DeepCopyInto(out *Model) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
+func (in *Model) DeepCopy() *Model {
+	if in == nil {
+		return nil
+	}
+	out := new(Model)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Model) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBenchmark) DeepCopyInto(out *ModelBenchmark) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmark.
+func (in *ModelBenchmark) DeepCopy() *ModelBenchmark {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelBenchmark)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelBenchmark) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBenchmarkAccuracy) DeepCopyInto(out *ModelBenchmarkAccuracy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmarkAccuracy.
+func (in *ModelBenchmarkAccuracy) DeepCopy() *ModelBenchmarkAccuracy {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelBenchmarkAccuracy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBenchmarkDataset) DeepCopyInto(out *ModelBenchmarkDataset) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmarkDataset.
+func (in *ModelBenchmarkDataset) DeepCopy() *ModelBenchmarkDataset {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelBenchmarkDataset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBenchmarkEnvironment) DeepCopyInto(out *ModelBenchmarkEnvironment) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Accelerators != nil {
+		in, out := &in.Accelerators, &out.Accelerators
+		*out = make(map[string]byte, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmarkEnvironment.
+func (in *ModelBenchmarkEnvironment) DeepCopy() *ModelBenchmarkEnvironment {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelBenchmarkEnvironment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBenchmarkLatency) DeepCopyInto(out *ModelBenchmarkLatency) {
+	*out = *in
+	out.Mean = in.Mean
+	out.P50 = in.P50
+	out.P90 = in.P90
+	out.P99 = in.P99
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmarkLatency.
+func (in *ModelBenchmarkLatency) DeepCopy() *ModelBenchmarkLatency {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelBenchmarkLatency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBenchmarkList) DeepCopyInto(out *ModelBenchmarkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ModelBenchmark, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmarkList.
+func (in *ModelBenchmarkList) DeepCopy() *ModelBenchmarkList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelBenchmarkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelBenchmarkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBenchmarkResult) DeepCopyInto(out *ModelBenchmarkResult) {
+	*out = *in
+	out.TimeToFirstToken = in.TimeToFirstToken
+	out.InterTokenLatency = in.InterTokenLatency
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmarkResult.
+func (in *ModelBenchmarkResult) DeepCopy() *ModelBenchmarkResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelBenchmarkResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBenchmarkSpec) DeepCopyInto(out *ModelBenchmarkSpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]common.Condition, len(*in))
+	out.Dataset = in.Dataset
+	if in.ConcurrencyLevels != nil {
+		in, out := &in.ConcurrencyLevels, &out.ConcurrencyLevels
+		*out = make([]int32, len(*in))
 		copy(*out, *in)
 	}
-	in.SnapshottingStatus.DeepCopyInto(&out.SnapshottingStatus)
+	out.Duration = in.Duration
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalModelVersionStatus.
-func (in *LocalModelVersionStatus) DeepCopy() *LocalModelVersionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmarkSpec.
+func (in *ModelBenchmarkSpec) DeepCopy() *ModelBenchmarkSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalModelVersionStatus)
+	out := new(ModelBenchmarkSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Model) DeepCopyInto(out *Model) {
+func (in *ModelBenchmarkStatus) DeepCopyInto(out *ModelBenchmarkStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]common.Condition, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Environment != nil {
+		in, out := &in.Environment, &out.Environment
+		*out = new(ModelBenchmarkEnvironment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]ModelBenchmarkResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.Accuracy != nil {
+		in, out := &in.Accuracy, &out.Accuracy
+		*out = new(ModelBenchmarkAccuracy)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
-func (in *Model) DeepCopy() *Model {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBenchmarkStatus.
+func (in *ModelBenchmarkStatus) DeepCopy() *ModelBenchmarkStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Model)
+	out := new(ModelBenchmarkStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Model) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelCard) DeepCopyInto(out *ModelCard) {
 	*out = *in
@@ -630,6 +1471,22 @@ func (in *ModelMetaData) DeepCopy() *ModelMetaData {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelMounting) DeepCopyInto(out *ModelMounting) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelMounting.
+func (in *ModelMounting) DeepCopy() *ModelMounting {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelMounting)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelService) DeepCopyInto(out *ModelService) {
 	*out = *in
@@ -658,6 +1515,103 @@ func (in *ModelService) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelServiceAdapter) DeepCopyInto(out *ModelServiceAdapter) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServiceAdapter.
+func (in *ModelServiceAdapter) DeepCopy() *ModelServiceAdapter {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelServiceAdapter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelServiceAdapterStatus) DeepCopyInto(out *ModelServiceAdapterStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServiceAdapterStatus.
+func (in *ModelServiceAdapterStatus) DeepCopy() *ModelServiceAdapterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelServiceAdapterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelServiceCanary) DeepCopyInto(out *ModelServiceCanary) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServiceCanary.
+func (in *ModelServiceCanary) DeepCopy() *ModelServiceCanary {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelServiceCanary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelServiceCanaryStatus) DeepCopyInto(out *ModelServiceCanaryStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServiceCanaryStatus.
+func (in *ModelServiceCanaryStatus) DeepCopy() *ModelServiceCanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelServiceCanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelServiceDistributed) DeepCopyInto(out *ModelServiceDistributed) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelServiceDistributed.
+func (in *ModelServiceDistributed) DeepCopy() *ModelServiceDistributed {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelServiceDistributed)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelServiceList) DeepCopyInto(out *ModelServiceList) {
 	*out = *in
@@ -720,6 +1674,21 @@ func (in *ModelServiceSpec) DeepCopyInto(out *ModelServiceSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(ModelServiceCanary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Adapters != nil {
+		in, out := &in.Adapters, &out.Adapters
+		*out = make([]ModelServiceAdapter, len(*in))
+		copy(*out, *in)
+	}
+	if in.Distributed != nil {
+		in, out := &in.Distributed, &out.Distributed
+		*out = new(ModelServiceDistributed)
+		**out = **in
+	}
 	return
 }
 
@@ -742,6 +1711,16 @@ func (in *ModelServiceStatus) DeepCopyInto(out *ModelServiceStatus) {
 		copy(*out, *in)
 	}
 	in.ContainerState.DeepCopyInto(&out.ContainerState)
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(ModelServiceCanaryStatus)
+		**out = **in
+	}
+	if in.Adapters != nil {
+		in, out := &in.Adapters, &out.Adapters
+		*out = make([]ModelServiceAdapterStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -780,6 +1759,11 @@ func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
 		*out = new(ModelCard)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TrainedOn != nil {
+		in, out := &in.TrainedOn, &out.TrainedOn
+		*out = make([]DatasetVersionReference, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -875,6 +1859,167 @@ func (in *NotebookList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotebookProfile) DeepCopyInto(out *NotebookProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotebookProfile.
+func (in *NotebookProfile) DeepCopy() *NotebookProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(NotebookProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotebookProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotebookProfileList) DeepCopyInto(out *NotebookProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NotebookProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotebookProfileList.
+func (in *NotebookProfileList) DeepCopy() *NotebookProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(NotebookProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotebookProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotebookProfileSpec) DeepCopyInto(out *NotebookProfileSpec) {
+	*out = *in
+	if in.ResourcePresets != nil {
+		in, out := &in.ResourcePresets, &out.ResourcePresets
+		*out = make([]NotebookResourcePreset, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeClaimTemplates != nil {
+		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
+		*out = make([]corev1.PersistentVolumeClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DatasetMountings != nil {
+		in, out := &in.DatasetMountings, &out.DatasetMountings
+		*out = make([]DatasetMounting, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotebookProfileSpec.
+func (in *NotebookProfileSpec) DeepCopy() *NotebookProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotebookProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotebookResourcePreset) DeepCopyInto(out *NotebookResourcePreset) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotebookResourcePreset.
+func (in *NotebookResourcePreset) DeepCopy() *NotebookResourcePreset {
+	if in == nil {
+		return nil
+	}
+	out := new(NotebookResourcePreset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotebookSchedule) DeepCopyInto(out *NotebookSchedule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotebookSchedule.
+func (in *NotebookSchedule) DeepCopy() *NotebookSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(NotebookSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotebookSpec) DeepCopyInto(out *NotebookSpec) {
 	*out = *in
@@ -896,6 +2041,16 @@ func (in *NotebookSpec) DeepCopyInto(out *NotebookSpec) {
 		*out = make([]DatasetMounting, len(*in))
 		copy(*out, *in)
 	}
+	if in.ModelMountings != nil {
+		in, out := &in.ModelMountings, &out.ModelMountings
+		*out = make([]ModelMounting, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(NotebookSchedule)
+		**out = **in
+	}
 	return
 }
 
@@ -918,6 +2073,14 @@ func (in *NotebookStatus) DeepCopyInto(out *NotebookStatus) {
 		copy(*out, *in)
 	}
 	in.ContainerState.DeepCopyInto(&out.ContainerState)
+	if in.LastActivityTime != nil {
+		in, out := &in.LastActivityTime, &out.LastActivityTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -25,6 +25,23 @@ import (
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// BatchInferenceJobList is a list of BatchInferenceJob resources
+type BatchInferenceJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []BatchInferenceJob `json:"items"`
+}
+
+func NewBatchInferenceJob(namespace, name string, obj BatchInferenceJob) *BatchInferenceJob {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("BatchInferenceJob").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // DatasetList is a list of Dataset resources
 type DatasetList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -59,6 +76,40 @@ func NewDatasetVersion(namespace, name string, obj DatasetVersion) *DatasetVersi
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// FineTuneJobList is a list of FineTuneJob resources
+type FineTuneJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []FineTuneJob `json:"items"`
+}
+
+func NewFineTuneJob(namespace, name string, obj FineTuneJob) *FineTuneJob {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("FineTuneJob").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LineageEdgeList is a list of LineageEdge resources
+type LineageEdgeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []LineageEdge `json:"items"`
+}
+
+func NewLineageEdge(namespace, name string, obj LineageEdge) *LineageEdge {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("LineageEdge").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // LocalModelList is a list of LocalModel resources
 type LocalModelList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -110,6 +161,23 @@ func NewModel(namespace, name string, obj Model) *Model {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// ModelBenchmarkList is a list of ModelBenchmark resources
+type ModelBenchmarkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ModelBenchmark `json:"items"`
+}
+
+func NewModelBenchmark(namespace, name string, obj ModelBenchmark) *ModelBenchmark {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("ModelBenchmark").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // ModelServiceList is a list of ModelService resources
 type ModelServiceList struct {
 	metav1.TypeMeta `json:",inline"`
@@ -144,6 +212,23 @@ func NewNotebook(namespace, name string, obj Notebook) *Notebook {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// NotebookProfileList is a list of NotebookProfile resources
+type NotebookProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NotebookProfile `json:"items"`
+}
+
+func NewNotebookProfile(namespace, name string, obj NotebookProfile) *NotebookProfile {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("NotebookProfile").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // RegistryList is a list of Registry resources
 type RegistryList struct {
 	metav1.TypeMeta `json:",inline"`
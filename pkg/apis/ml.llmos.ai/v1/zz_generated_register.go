@@ -27,13 +27,18 @@ import (
 )
 
 var (
+	BatchInferenceJobResourceName = "batchinferencejobs"
 	DatasetResourceName           = "datasets"
 	DatasetVersionResourceName    = "datasetversions"
+	FineTuneJobResourceName       = "finetunejobs"
+	LineageEdgeResourceName       = "lineageedges"
 	LocalModelResourceName        = "localmodels"
 	LocalModelVersionResourceName = "localmodelversions"
 	ModelResourceName             = "models"
+	ModelBenchmarkResourceName    = "modelbenchmarks"
 	ModelServiceResourceName      = "modelservices"
 	NotebookResourceName          = "notebooks"
+	NotebookProfileResourceName   = "notebookprofiles"
 	RegistryResourceName          = "registries"
 )
 
@@ -58,20 +63,30 @@ var (
 // Adds the list of known types to Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&BatchInferenceJob{},
+		&BatchInferenceJobList{},
 		&Dataset{},
 		&DatasetList{},
 		&DatasetVersion{},
 		&DatasetVersionList{},
+		&FineTuneJob{},
+		&FineTuneJobList{},
+		&LineageEdge{},
+		&LineageEdgeList{},
 		&LocalModel{},
 		&LocalModelList{},
 		&LocalModelVersion{},
 		&LocalModelVersionList{},
 		&Model{},
 		&ModelList{},
+		&ModelBenchmark{},
+		&ModelBenchmarkList{},
 		&ModelService{},
 		&ModelServiceList{},
 		&Notebook{},
 		&NotebookList{},
+		&NotebookProfile{},
+		&NotebookProfileList{},
 		&Registry{},
 		&RegistryList{},
 	)
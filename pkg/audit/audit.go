@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/auditlog"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	bufferSize    = 1000
+	batchSize     = 100
+	flushInterval = 5 * time.Second
+	// retentionInterval is how often the audit records older than the retention days are deleted
+	retentionInterval = time.Hour
+	webhookTimeout    = 10 * time.Second
+)
+
+// Record is a mutating API request made by the user
+type Record struct {
+	UserID    string `json:"userId,omitempty"`
+	TokenName string `json:"tokenName,omitempty"`
+	Verb      string `json:"verb"`
+	Resource  string `json:"resource,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	// StatusCode is the outcome of the request
+	StatusCode int       `json:"statusCode"`
+	LatencyMs  int64     `json:"latencyMs"`
+	SourceIP   string    `json:"sourceIP,omitempty"`
+	UserAgent  string    `json:"userAgent,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Sink writes the batches of the audit records
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, records []*Record) error
+}
+
+// Logger collects the audit records of the API requests and writes them to the sinks in batches in the background,
+// so that the requests are never blocked by the sinks
+type Logger struct {
+	records chan *Record
+	sinks   []Sink
+	db      EntClientGetter
+}
+
+// EntClientGetter returns the ent client of the database, which is nil until the database is configured
+type EntClientGetter interface {
+	GetEntClient() *ent.Client
+}
+
+func NewLogger(db EntClientGetter, sinks ...Sink) *Logger {
+	return &Logger{
+		records: make(chan *Record, bufferSize),
+		sinks:   sinks,
+		db:      db,
+	}
+}
+
+// NewDefaultLogger returns the logger writing to the database and the audit log webhook
+func NewDefaultLogger(db EntClientGetter) *Logger {
+	return NewLogger(db, NewDBSink(db), NewWebhookSink())
+}
+
+// Submit queues the record, the record is dropped if the queue is full
+func (l *Logger) Submit(record *Record) {
+	select {
+	case l.records <- record:
+	default:
+		logrus.Warnf("audit log queue is full, dropping the record of %s %s by user %s", record.Method,
+			record.Path, record.UserID)
+	}
+}
+
+// Run writes the queued records to the sinks and deletes the expired records until the context is done
+func (l *Logger) Run(ctx context.Context) {
+	flushTicker := time.NewTicker(flushInterval)
+	defer flushTicker.Stop()
+	retentionTicker := time.NewTicker(retentionInterval)
+	defer retentionTicker.Stop()
+
+	batch := make([]*Record, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		l.write(ctx, batch)
+		batch = make([]*Record, 0, batchSize)
+	}
+
+	for {
+		select {
+		case record := <-l.records:
+			batch = append(batch, record)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-flushTicker.C:
+			flush()
+		case <-retentionTicker.C:
+			l.deleteExpired(ctx)
+		case <-ctx.Done():
+			if len(batch) > 0 {
+				// the records are written with a new context as the server is shutting down
+				flushCtx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+				l.write(flushCtx, batch)
+				cancel()
+			}
+			return
+		}
+	}
+}
+
+func (l *Logger) write(ctx context.Context, records []*Record) {
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, records); err != nil {
+			logrus.Errorf("failed to write %d audit records to %s: %v", len(records), sink.Name(), err)
+		}
+	}
+}
+
+func (l *Logger) deleteExpired(ctx context.Context) {
+	days := settings.AuditLogRetentionDays.GetInt()
+	client := l.db.GetEntClient()
+	if days <= 0 || client == nil {
+		return
+	}
+
+	deleted, err := client.AuditLog.Delete().
+		Where(auditlog.CreatedAtLT(time.Now().AddDate(0, 0, -days))).
+		Exec(ctx)
+	if err != nil {
+		logrus.Errorf("failed to delete expired audit records: %v", err)
+		return
+	}
+	logrus.Debugf("deleted %d audit records older than %d days", deleted, days)
+}
+
+type dbSink struct {
+	db EntClientGetter
+}
+
+// NewDBSink returns the sink writing the records to the database, the records are skipped if the database is not
+// configured
+func NewDBSink(db EntClientGetter) Sink {
+	return &dbSink{db: db}
+}
+
+func (s *dbSink) Name() string {
+	return "database"
+}
+
+func (s *dbSink) Write(ctx context.Context, records []*Record) error {
+	client := s.db.GetEntClient()
+	if client == nil {
+		logrus.Debugf("database is not configured, skip writing %d audit records", len(records))
+		return nil
+	}
+
+	builders := make([]*ent.AuditLogCreate, 0, len(records))
+	for _, r := range records {
+		builders = append(builders, client.AuditLog.Create().
+			SetUserId(r.UserID).
+			SetTokenName(r.TokenName).
+			SetVerb(r.Verb).
+			SetResource(r.Resource).
+			SetNamespace(r.Namespace).
+			SetName(r.Name).
+			SetMethod(r.Method).
+			SetPath(r.Path).
+			SetStatusCode(r.StatusCode).
+			SetLatencyMs(r.LatencyMs).
+			SetSourceIP(r.SourceIP).
+			SetUserAgent(r.UserAgent).
+			SetCreatedAt(r.CreatedAt))
+	}
+	return client.AuditLog.CreateBulk(builders...).Exec(ctx)
+}
+
+type webhookSink struct {
+	client *http.Client
+}
+
+// NewWebhookSink returns the sink posting the records as a JSON array to the audit-log-webhook-url
+func NewWebhookSink() Sink {
+	return &webhookSink{
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Write(ctx context.Context, records []*Record) error {
+	url := settings.AuditLogWebhookURL.Get()
+	if url == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit log webhook responded status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+type fakeDB struct{}
+
+func (fakeDB) GetEntClient() *ent.Client {
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	logger := NewLogger(fakeDB{})
+	authenticated := func(status int) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			SetUser(req, "user-abc", "token-xyz")
+			rw.WriteHeader(status)
+		})
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		handler  http.Handler
+		expected *Record
+	}{
+		{
+			name:    "steve action",
+			method:  http.MethodPost,
+			target:  "/v1/ml.llmos.ai.registries/default?action=upload",
+			handler: authenticated(http.StatusOK),
+			expected: &Record{
+				UserID:     "user-abc",
+				TokenName:  "token-xyz",
+				Verb:       "upload",
+				Resource:   "registries",
+				Name:       "default",
+				Method:     http.MethodPost,
+				Path:       "/v1/ml.llmos.ai.registries/default",
+				StatusCode: http.StatusOK,
+			},
+		},
+		{
+			name:    "steve delete",
+			method:  http.MethodDelete,
+			target:  "/v1/ml.llmos.ai.notebooks/team-a/jupyter",
+			handler: authenticated(http.StatusForbidden),
+			expected: &Record{
+				UserID:     "user-abc",
+				TokenName:  "token-xyz",
+				Verb:       "delete",
+				Resource:   "notebooks",
+				Namespace:  "team-a",
+				Name:       "jupyter",
+				Method:     http.MethodDelete,
+				Path:       "/v1/ml.llmos.ai.notebooks/team-a/jupyter",
+				StatusCode: http.StatusForbidden,
+			},
+		},
+		{
+			name:   "unauthenticated",
+			method: http.MethodPost,
+			target: "/v1/management.llmos.ai.tokens",
+			handler: http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(http.StatusUnauthorized)
+			}),
+			expected: &Record{
+				Verb:       "post",
+				Method:     http.MethodPost,
+				Path:       "/v1/management.llmos.ai.tokens",
+				StatusCode: http.StatusUnauthorized,
+			},
+		},
+		{
+			name:    "read-only request",
+			method:  http.MethodGet,
+			target:  "/v1/ml.llmos.ai.notebooks",
+			handler: authenticated(http.StatusOK),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body := &readCounter{Reader: strings.NewReader(`{"metadata":{"namespace":"default"}}`)}
+			req := httptest.NewRequest(tc.method, tc.target, body)
+			logger.Middleware(tc.handler).ServeHTTP(httptest.NewRecorder(), req)
+			if tc.expected != nil && tc.expected.UserID == "" {
+				// the body of the unauthenticated request isn't read to parse its resource
+				assert.Zero(t, body.reads)
+			}
+
+			if tc.expected == nil {
+				assert.Empty(t, logger.records)
+				return
+			}
+			require.Len(t, logger.records, 1)
+			record := <-logger.records
+			assert.Equal(t, "192.0.2.1", record.SourceIP)
+			assert.False(t, record.CreatedAt.IsZero())
+			record.SourceIP = ""
+			record.CreatedAt = tc.expected.CreatedAt
+			record.LatencyMs = 0
+			assert.Equal(t, tc.expected, record)
+		})
+	}
+}
+
+type readCounter struct {
+	io.Reader
+	reads int
+}
+
+func (r *readCounter) Read(p []byte) (int, error) {
+	r.reads++
+	return r.Reader.Read(p)
+}
+
+func TestMiddlewareDisabled(t *testing.T) {
+	require.NoError(t, settings.AuditLogEnabled.Set("false"))
+	t.Cleanup(func() { _ = settings.AuditLogEnabled.Set("true") })
+
+	logger := NewLogger(fakeDB{})
+	req := httptest.NewRequest(http.MethodDelete, "/v1/ml.llmos.ai.notebooks/team-a/jupyter", nil)
+	logger.Middleware(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), req)
+	assert.Empty(t, logger.records)
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received []*Record
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink()
+	records := []*Record{{UserID: "user-abc", Verb: "create", Method: http.MethodPost, Path: "/v1/ml.llmos.ai.notebooks"}}
+
+	// the sink is disabled without the url
+	require.NoError(t, sink.Write(context.Background(), records))
+	assert.Nil(t, received)
+
+	require.NoError(t, settings.AuditLogWebhookURL.Set(server.URL))
+	t.Cleanup(func() { _ = settings.AuditLogWebhookURL.Set("") })
+	require.NoError(t, sink.Write(context.Background(), records))
+	require.Len(t, received, 1)
+	assert.Equal(t, "user-abc", received[0].UserID)
+}
@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+type recordKey struct{}
+
+// Middleware records the mutating requests, it wraps the auth middleware which sets the user, token and resource of
+// the record by SetUser, so that the requests rejected by the auth middleware are recorded as well. The resource
+// is only parsed for the authenticated requests, since parsing the create requests reads their bodies.
+func (l *Logger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if !isMutating(req.Method) || settings.AuditLogEnabled.Get() != constant.TrueStr {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		start := time.Now()
+		record := newRecord(req, start)
+		recorder := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+		next.ServeHTTP(recorder, req.WithContext(context.WithValue(req.Context(), recordKey{}, record)))
+
+		record.StatusCode = recorder.status
+		record.LatencyMs = time.Since(start).Milliseconds()
+		l.Submit(record)
+	})
+}
+
+// SetUser sets the authenticated user, the token and the requested resource of the request being recorded
+func SetUser(req *http.Request, userID, tokenName string) {
+	record, ok := req.Context().Value(recordKey{}).(*Record)
+	if !ok {
+		return
+	}
+	record.UserID = userID
+	record.TokenName = tokenName
+
+	// the unsupported paths are recorded by their methods and paths only
+	if attrs, err := tokens.GetRequestAttributes(req); err == nil {
+		record.Verb = attrs.Verb
+		record.Resource = attrs.Resource
+		record.Namespace = attrs.Namespace
+		record.Name = attrs.Name
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func newRecord(req *http.Request, start time.Time) *Record {
+	return &Record{
+		Verb:      strings.ToLower(req.Method),
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		SourceIP:  tokens.ClientIP(req),
+		UserAgent: req.UserAgent(),
+		CreatedAt: start,
+	}
+}
+
+// statusRecorder keeps the status code of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to flush and hijack the underlying response writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,322 @@
+package mfa
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/hashers"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+	"github.com/llmos-ai/llmos-operator/pkg/utils"
+)
+
+const (
+	// Issuer is the issuer of the TOTP secrets shown by the authenticator apps
+	Issuer = "LLMOS"
+
+	secretNamePrefix     = "user-mfa-"
+	totpSecretKey        = "totpSecret"
+	pendingSecretKey     = "pendingTOTPSecret"
+	recoveryCodesKey     = "recoveryCodes"
+	lastUsedStepKey      = "lastUsedStep"
+	challengeKey         = "challenge"
+	challengeExpiresKey  = "challengeExpires"
+	challengeFailuresKey = "challengeFailures"
+
+	// challengeTTL is how long the user has to enter the code after the password is verified
+	challengeTTL = 5 * time.Minute
+	// maxChallengeFailures is how many invalid codes the challenge accepts before it's burned, the password has to
+	// be verified again for a new challenge
+	maxChallengeFailures = 3
+)
+
+var (
+	ErrInvalidCode      = errors.New("invalid MFA code")
+	ErrInvalidChallenge = errors.New("invalid or expired MFA challenge")
+	ErrAlreadyEnabled   = errors.New("MFA is already enabled")
+	ErrNotEnrolled      = errors.New("MFA enrollment is not started")
+)
+
+// Enrollment is the TOTP secret to add to the authenticator app, either by the secret or the QR code of the URI
+type Enrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Manager keeps the TOTP secrets, recovery code hashes and login challenges of the users in the secrets of the
+// system namespace, which are not readable by the users themselves
+type Manager struct {
+	secrets    ctlcorev1.SecretClient
+	userClient ctlmgmtv1.UserClient
+}
+
+func NewManager(secrets ctlcorev1.SecretClient, userClient ctlmgmtv1.UserClient) *Manager {
+	return &Manager{
+		secrets:    secrets,
+		userClient: userClient,
+	}
+}
+
+// EnrollmentRequired returns whether the local user must enroll MFA before using the API as MFA is required by the
+// auth-mfa-required setting
+func EnrollmentRequired(user *mgmtv1.User) bool {
+	return settings.AuthMFARequired.Get() == constant.TrueStr && tokens.IsLocalUser(user) && !user.Status.MFAEnabled
+}
+
+// Enroll generates a new TOTP secret of the user, MFA is enabled once the secret is activated by a valid code
+func (m *Manager) Enroll(user *mgmtv1.User) (*Enrollment, error) {
+	if user.Status.MFAEnabled {
+		return nil, ErrAlreadyEnabled
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err = m.saveSecret(user, map[string][]byte{pendingSecretKey: []byte(secret)}); err != nil {
+		return nil, err
+	}
+
+	return &Enrollment{
+		Secret: secret,
+		URI:    KeyURI(Issuer, user.Spec.Username, secret),
+	}, nil
+}
+
+// Activate enables MFA of the user if the code of the pending secret is valid, the recovery codes are returned
+// only once
+func (m *Manager) Activate(user *mgmtv1.User, code string) ([]string, error) {
+	if user.Status.MFAEnabled {
+		return nil, ErrAlreadyEnabled
+	}
+
+	secret, err := m.getSecret(user)
+	if apierrors.IsNotFound(err) {
+		return nil, ErrNotEnrolled
+	} else if err != nil {
+		return nil, err
+	}
+	pending := string(secret.Data[pendingSecretKey])
+	if pending == "" {
+		return nil, ErrNotEnrolled
+	}
+
+	step, ok := ValidateCode(pending, code, time.Now(), 0)
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+
+	codes, hashes, err := GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = m.saveSecret(user, map[string][]byte{
+		totpSecretKey:    []byte(pending),
+		recoveryCodesKey: []byte(strings.Join(hashes, "\n")),
+		lastUsedStepKey:  []byte(strconv.FormatInt(step, 10)),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = m.setEnabled(user, true); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable disables MFA of the user and removes its TOTP secret and recovery codes
+func (m *Manager) Disable(user *mgmtv1.User) error {
+	err := m.secrets.Delete(constant.SystemNamespaceName, secretName(user), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete MFA secret of user %s: %w", user.Spec.Username, err)
+	}
+	return m.setEnabled(user, false)
+}
+
+// Verify checks the TOTP code or one of the recovery codes of the user, the used recovery code is removed
+func (m *Manager) Verify(user *mgmtv1.User, code string) error {
+	secret, err := m.getSecret(user)
+	if err != nil {
+		return err
+	}
+	if err = verifyCode(secret, code, time.Now()); err != nil {
+		return err
+	}
+	_, err = m.secrets.Update(secret)
+	return err
+}
+
+// NewChallenge returns the challenge of the user whose password is verified, the session token is issued once the
+// challenge is completed with the MFA code, a new challenge replaces the previous one
+func (m *Manager) NewChallenge(user *mgmtv1.User) (string, error) {
+	secret, err := m.getSecret(user)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := utils.GenerateToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate MFA challenge: %w", err)
+	}
+	hash, err := hashers.NewHasher().CreateHash(key)
+	if err != nil {
+		return "", err
+	}
+
+	secret.Data[challengeKey] = []byte(hash)
+	secret.Data[challengeExpiresKey] = []byte(time.Now().Add(challengeTTL).Format(time.RFC3339))
+	delete(secret.Data, challengeFailuresKey)
+	if _, err = m.secrets.Update(secret); err != nil {
+		return "", err
+	}
+	return user.Name + ":" + key, nil
+}
+
+// SplitChallenge returns the name of the user and the key of the challenge
+func SplitChallenge(challenge string) (string, string) {
+	return tokens.SplitTokenParts(challenge)
+}
+
+// CompleteChallenge verifies the challenge key and the MFA code of the user, the challenge is one-time if the code
+// is valid, and is burned after the max invalid codes
+func (m *Manager) CompleteChallenge(user *mgmtv1.User, key, code string) error {
+	secret, err := m.getSecret(user)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ErrInvalidChallenge
+		}
+		return err
+	}
+
+	now := time.Now()
+	expires, err := time.Parse(time.RFC3339, string(secret.Data[challengeExpiresKey]))
+	if err != nil || now.After(expires) || key == "" ||
+		hashers.NewHasher().VerifyHash(string(secret.Data[challengeKey]), key) != nil {
+		return ErrInvalidChallenge
+	}
+
+	if err = verifyCode(secret, code, now); err != nil {
+		if errors.Is(err, ErrInvalidCode) {
+			return m.recordChallengeFailure(secret)
+		}
+		return err
+	}
+	deleteChallenge(secret)
+	_, err = m.secrets.Update(secret)
+	return err
+}
+
+// recordChallengeFailure counts the invalid code of the challenge and burns the challenge once the failures reach
+// the max, ErrInvalidCode is returned unless the failure can't be recorded
+func (m *Manager) recordChallengeFailure(secret *corev1.Secret) error {
+	failures, _ := strconv.Atoi(string(secret.Data[challengeFailuresKey]))
+	failures++
+	if failures >= maxChallengeFailures {
+		deleteChallenge(secret)
+	} else {
+		secret.Data[challengeFailuresKey] = []byte(strconv.Itoa(failures))
+	}
+	if _, err := m.secrets.Update(secret); err != nil {
+		return fmt.Errorf("failed to record invalid MFA code: %w", err)
+	}
+	return ErrInvalidCode
+}
+
+func deleteChallenge(secret *corev1.Secret) {
+	delete(secret.Data, challengeKey)
+	delete(secret.Data, challengeExpiresKey)
+	delete(secret.Data, challengeFailuresKey)
+}
+
+// verifyCode checks the code against the TOTP secret and the recovery codes, and updates the secret data to
+// prevent the code from being used again
+func verifyCode(secret *corev1.Secret, code string, now time.Time) error {
+	totpSecret := string(secret.Data[totpSecretKey])
+	if totpSecret == "" {
+		return ErrNotEnrolled
+	}
+
+	lastUsedStep, _ := strconv.ParseInt(string(secret.Data[lastUsedStepKey]), 10, 64)
+	if step, ok := ValidateCode(totpSecret, strings.TrimSpace(code), now, lastUsedStep); ok {
+		secret.Data[lastUsedStepKey] = []byte(strconv.FormatInt(step, 10))
+		return nil
+	}
+
+	var hashes []string
+	if v := string(secret.Data[recoveryCodesKey]); v != "" {
+		hashes = strings.Split(v, "\n")
+	}
+	i := MatchRecoveryCode(hashes, code)
+	if i < 0 {
+		return ErrInvalidCode
+	}
+	hashes = append(hashes[:i], hashes[i+1:]...)
+	secret.Data[recoveryCodesKey] = []byte(strings.Join(hashes, "\n"))
+	return nil
+}
+
+func secretName(user *mgmtv1.User) string {
+	return secretNamePrefix + user.Name
+}
+
+func (m *Manager) getSecret(user *mgmtv1.User) (*corev1.Secret, error) {
+	secret, err := m.secrets.Get(constant.SystemNamespaceName, secretName(user), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	secret = secret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	return secret, nil
+}
+
+// saveSecret replaces the data of the MFA secret of the user
+func (m *Manager) saveSecret(user *mgmtv1.User, data map[string][]byte) error {
+	secret, err := m.getSecret(user)
+	if apierrors.IsNotFound(err) {
+		_, err = m.secrets.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName(user),
+				Namespace: constant.SystemNamespaceName,
+				Labels: map[string]string{
+					tokens.LabelAuthUserId: user.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(user, mgmtv1.SchemeGroupVersion.WithKind("User")),
+				},
+			},
+			Data: data,
+		})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	secret.Data = data
+	_, err = m.secrets.Update(secret)
+	return err
+}
+
+func (m *Manager) setEnabled(user *mgmtv1.User, enabled bool) error {
+	if user.Status.MFAEnabled == enabled {
+		return nil
+	}
+	toUpdate := user.DeepCopy()
+	toUpdate.Status.MFAEnabled = enabled
+	if _, err := m.userClient.UpdateStatus(toUpdate); err != nil {
+		return fmt.Errorf("failed to update MFA status of user %s: %w", user.Spec.Username, err)
+	}
+	return nil
+}
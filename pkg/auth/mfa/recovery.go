@@ -0,0 +1,71 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/llmos-ai/llmos-operator/pkg/auth/hashers"
+)
+
+const (
+	recoveryCodeCount = 10
+	// recoveryCodeLength is the length of each half of the recovery code, e.g., 4k7xm-q2n8p
+	recoveryCodeLength = 5
+	recoveryCodeChars  = "abcdefghijkmnpqrstuvwxyz23456789"
+)
+
+// GenerateRecoveryCodes returns the one-time recovery codes along with their hashes
+func GenerateRecoveryCodes() ([]string, []string, error) {
+	hasher := hashers.NewHasher()
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		first, err := randomString(recoveryCodeLength)
+		if err != nil {
+			return nil, nil, err
+		}
+		second, err := randomString(recoveryCodeLength)
+		if err != nil {
+			return nil, nil, err
+		}
+		code := first + "-" + second
+
+		hash, err := hasher.CreateHash(code)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to hash recovery code: %w", err)
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hash)
+	}
+	return codes, hashes, nil
+}
+
+// MatchRecoveryCode returns the index of the hash matching the recovery code, -1 is returned if none matches
+func MatchRecoveryCode(hashes []string, code string) int {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return -1
+	}
+	hasher := hashers.NewHasher()
+	for i, hash := range hashes {
+		if hasher.VerifyHash(hash, code) == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+func randomString(length int) (string, error) {
+	result := make([]byte, length)
+	max := big.NewInt(int64(len(recoveryCodeChars)))
+	for i := range result {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		result[i] = recoveryCodeChars[n.Int64()]
+	}
+	return string(result), nil
+}
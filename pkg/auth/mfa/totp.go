@@ -0,0 +1,111 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // HMAC-SHA1 is the default TOTP algorithm supported by all authenticator apps
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// totpPeriod, totpDigits and the SHA1 algorithm are the RFC 6238 defaults supported by all authenticator apps
+	totpPeriod = 30
+	totpDigits = 6
+	// totpSkew is the number of the periods before and after the current one the codes are accepted in, which
+	// tolerates the clock drift of the devices
+	totpSkew   = 1
+	secretSize = 20
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 encoded TOTP secret
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("unable to read random values for TOTP secret: %w", err)
+	}
+	return secretEncoding.EncodeToString(secret), nil
+}
+
+// KeyURI returns the otpauth URI of the secret, which is encoded as the QR code scanned by the authenticator apps
+func KeyURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// GenerateCode returns the TOTP code of the secret at the time
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, timeStep(t)), nil
+}
+
+// ValidateCode returns the time step the code is generated at if the code is valid at the time, the codes of the
+// steps not after the lastUsedStep are rejected so that a code can't be replayed
+func ValidateCode(secret, code string, t time.Time, lastUsedStep int64) (int64, bool) {
+	key, err := decodeSecret(secret)
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := timeStep(t)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+	return key, nil
+}
+
+func timeStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+// hotp returns the RFC 4226 HOTP code of the counter
+func hotp(key []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
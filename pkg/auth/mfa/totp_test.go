@@ -0,0 +1,79 @@
+package mfa
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the base32 encoded SHA1 secret of the RFC 6238 test vectors
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode(t *testing.T) {
+	// the RFC 6238 test vectors truncated to 6 digits
+	tests := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range tests {
+		code, err := GenerateCode(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidateCode(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	code, err := GenerateCode(secret, now)
+	require.NoError(t, err)
+
+	step, ok := ValidateCode(secret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, timeStep(now), step)
+
+	// the code is accepted within the clock skew
+	_, ok = ValidateCode(secret, code, now.Add(totpPeriod*time.Second), 0)
+	assert.True(t, ok)
+	_, ok = ValidateCode(secret, code, now.Add(3*totpPeriod*time.Second), 0)
+	assert.False(t, ok)
+
+	// the used code can't be replayed
+	_, ok = ValidateCode(secret, code, now, step)
+	assert.False(t, ok)
+
+	_, ok = ValidateCode(secret, "12345", now, 0)
+	assert.False(t, ok)
+	_, ok = ValidateCode("invalid!", code, now, 0)
+	assert.False(t, ok)
+}
+
+func TestKeyURI(t *testing.T) {
+	uri, err := url.Parse(KeyURI(Issuer, "admin", rfcSecret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/LLMOS:admin", uri.Path)
+	assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(t, Issuer, uri.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	require.Len(t, hashes, recoveryCodeCount)
+
+	assert.Equal(t, 3, MatchRecoveryCode(hashes, codes[3]))
+	assert.Equal(t, 3, MatchRecoveryCode(hashes, " "+codes[3]+" "))
+	assert.Equal(t, -1, MatchRecoveryCode(hashes, "aaaaa-aaaaa"))
+	assert.Equal(t, -1, MatchRecoveryCode(hashes, ""))
+}
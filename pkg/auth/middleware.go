@@ -3,14 +3,20 @@ package auth
 import (
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	authUser "k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/endpoints/request"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/audit"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/mfa"
 	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
 	"github.com/llmos-ai/llmos-operator/pkg/constant"
 	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
@@ -44,12 +50,22 @@ type Middleware struct {
 
 func (m *Middleware) AuthMiddleware(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		token := tokens.ExtractTokenFromRequest(req)
-		userInfo, err := m.GetUserInfoFromToken(token)
+		token, user, userInfo, err := m.authenticate(tokens.ExtractTokenFromRequest(req))
 		if err != nil {
 			utils.ResponseError(rw, http.StatusUnauthorized, err)
 			return
 		}
+		audit.SetUser(req, user.Name, token.Name)
+
+		if err = checkRestrictedUser(user, req); err != nil {
+			utils.ResponseError(rw, http.StatusForbidden, err)
+			return
+		}
+
+		if err = checkTokenScopes(token, req); err != nil {
+			utils.ResponseError(rw, http.StatusForbidden, err)
+			return
+		}
 
 		ctx := request.WithUser(req.Context(), userInfo)
 		req = req.WithContext(ctx)
@@ -58,31 +74,109 @@ func (m *Middleware) AuthMiddleware(handler http.Handler) http.Handler {
 }
 
 func (m *Middleware) GetUserInfoFromToken(tokenStr string) (authUser.Info, error) {
+	_, _, userInfo, err := m.authenticate(tokenStr)
+	return userInfo, err
+}
+
+func (m *Middleware) authenticate(tokenStr string) (*mgmtv1.Token, *mgmtv1.User, authUser.Info, error) {
 	token, err := m.GetTokenFromRequest(tokenStr)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	user, err := m.GetUserByName(token.Spec.UserId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user %s, %v", token.Spec.UserId, err)
+		return nil, nil, nil, fmt.Errorf("failed to get user %s, %v", token.Spec.UserId, err)
 	}
 
 	if !user.Status.IsActive {
-		return nil, errors.Wrap(ErrMustAuthenticate, "user is not enabled")
+		return nil, nil, nil, errors.Wrap(ErrMustAuthenticate, "user is not enabled")
 	}
+	m.updateLastUsed(token)
 
 	var userInfo authUser.DefaultInfo
 	userInfo.Name = user.Name
 	userInfo.UID = string(user.UID)
-	userInfo.Groups = []string{
-		"system:authenticated",
+	userInfo.Groups = UserGroups(user)
+
+	return token, user, &userInfo, nil
+}
+
+// UserGroups returns the kubernetes groups the requests of the user are impersonated with
+func UserGroups(user *mgmtv1.User) []string {
+	groups := []string{
+		authUser.AllAuthenticated,
 	}
 	if user.Status.IsAdmin {
-		userInfo.Groups = append(userInfo.Groups, constant.AdminRole)
+		groups = append(groups, constant.AdminRole)
+	}
+	return groups
+}
+
+// updateLastUsed records when the token was last used, the updates are throttled to avoid writing the token on every
+// request, and a failed update is retried by the next request
+func (m *Middleware) updateLastUsed(token *mgmtv1.Token) {
+	now := time.Now()
+	if !tokens.ShouldUpdateLastUsed(token, now) {
+		return
+	}
+
+	toUpdate := token.DeepCopy()
+	toUpdate.Status.LastUsedAt = &metav1.Time{Time: now}
+	if _, err := m.tokenClient.UpdateStatus(toUpdate); err != nil {
+		logrus.Debugf("failed to update last used time of token %s: %v", token.Name, err)
+	}
+}
+
+// checkRestrictedUser only allows the users required to change their passwords or to enroll MFA to get themselves
+// and the schemas, and to complete the required actions
+func checkRestrictedUser(user *mgmtv1.User, req *http.Request) error {
+	var restrictedErr error
+	var allowedActions []string
+	switch {
+	case tokens.PasswordChangeRequired(user):
+		restrictedErr = errors.New("password must be changed before using the API")
+		allowedActions = []string{"changePassword"}
+	case mfa.EnrollmentRequired(user):
+		restrictedErr = errors.New("MFA must be enrolled before using the API")
+		allowedActions = []string{"enrollMFA", "activateMFA"}
+	default:
+		return nil
+	}
+
+	if !strings.HasPrefix(req.URL.Path, "/v1/") {
+		return restrictedErr
+	}
+	attrs, err := tokens.GetRequestAttributes(req)
+	if err != nil {
+		return restrictedErr
 	}
 
-	return &userInfo, nil
+	switch {
+	case attrs.Resource == "users" && (attrs.Verb == "list" || slices.Contains(allowedActions, attrs.Verb)):
+		return nil
+	case attrs.Resource == "schemas" && (attrs.Verb == "list" || attrs.Verb == "get"):
+		return nil
+	default:
+		return restrictedErr
+	}
+}
+
+// checkTokenScopes restricts the requests of the scoped API keys on top of the permissions of their users
+func checkTokenScopes(token *mgmtv1.Token, req *http.Request) error {
+	if len(token.Spec.Scopes) == 0 {
+		return nil
+	}
+
+	attrs, err := tokens.GetRequestAttributes(req)
+	if err != nil {
+		return err
+	}
+	if !tokens.ScopesAllow(token.Spec.Scopes, attrs) {
+		return fmt.Errorf("the API key is not allowed to %s %s in namespace %q", attrs.Verb, attrs.Resource,
+			attrs.Namespace)
+	}
+	return nil
 }
 
 func (m *Middleware) GetTokenFromRequest(tokenAuthValue string) (*mgmtv1.Token, error) {
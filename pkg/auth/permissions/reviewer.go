@@ -0,0 +1,218 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	authzv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	authzclientv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/roletemplatebinding"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+// EffectiveRule is a policy rule granted to the user, along with the RoleTemplateBinding that granted it
+type EffectiveRule struct {
+	rbacv1.PolicyRule `json:",inline"`
+	// Namespace the rule is granted in, it is empty if the rule is granted cluster-wide
+	Namespace        string `json:"namespace,omitempty"`
+	Binding          string `json:"binding"`
+	RoleTemplateKind string `json:"roleTemplateKind"`
+	RoleTemplateName string `json:"roleTemplateName"`
+	// Group is the group the user is bound through, it is empty if the user is bound directly
+	Group string `json:"group,omitempty"`
+}
+
+// AccessReview is the request to check whether the user is allowed to, the empty namespace checks the
+// cluster-scoped resources or all namespaces
+type AccessReview struct {
+	Namespace   string `json:"namespace,omitempty"`
+	Verb        string `json:"verb"`
+	Group       string `json:"group,omitempty"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type AccessReviewResult struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Reviewer answers what the users are allowed to do, by the role templates and global roles bound to them
+type Reviewer struct {
+	ctx               context.Context
+	sar               authzclientv1.SubjectAccessReviewInterface
+	rtbCache          ctlmgmtv1.RoleTemplateBindingCache
+	groupCache        ctlmgmtv1.GroupCache
+	grCache           ctlmgmtv1.GlobalRoleCache
+	roleTemplateCache ctlmgmtv1.RoleTemplateCache
+}
+
+func NewReviewer(scaled *config.Scaled) *Reviewer {
+	mgmt := scaled.MgmtFactory.Management().V1()
+	return &Reviewer{
+		ctx:               scaled.Ctx,
+		sar:               scaled.ClientSet.AuthorizationV1().SubjectAccessReviews(),
+		rtbCache:          mgmt.RoleTemplateBinding().Cache(),
+		groupCache:        mgmt.Group().Cache(),
+		grCache:           mgmt.GlobalRole().Cache(),
+		roleTemplateCache: mgmt.RoleTemplate().Cache(),
+	}
+}
+
+// EffectiveRules returns the rules granted to the user by the GlobalRole rules, the GlobalRole namespaced rules and
+// the RoleTemplates of the user's bindings, including the bindings of the implicit groups the user's requests are
+// impersonated with. The rules are limited to the namespace if it is specified, which includes the cluster-wide rules
+func (r *Reviewer) EffectiveRules(user *mgmtv1.User, namespace string) ([]EffectiveRule, error) {
+	rtbs, err := r.rtbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role template bindings: %w", err)
+	}
+	groups, err := r.groupCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	groupMap := make(map[string]*mgmtv1.Group, len(groups))
+	for _, group := range groups {
+		groupMap[group.Name] = group
+	}
+
+	userGroups := auth.UserGroups(user)
+
+	rules := make([]EffectiveRule, 0)
+	for _, rtb := range rtbs {
+		if rtb.DeletionTimestamp != nil {
+			continue
+		}
+		bound, through := roletemplatebinding.BoundUser(rtb, user.Name, groupMap)
+		if !bound {
+			bound, through = boundUserGroup(rtb, userGroups)
+		}
+		if !bound {
+			continue
+		}
+
+		granted, err := r.bindingRules(rtb, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for i := range granted {
+			granted[i].Binding = rtb.Name
+			granted[i].RoleTemplateKind = rtb.RoleTemplateRef.Kind
+			granted[i].RoleTemplateName = rtb.RoleTemplateRef.Name
+			granted[i].Group = through
+		}
+		rules = append(rules, granted...)
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Namespace != rules[j].Namespace {
+			return rules[i].Namespace < rules[j].Namespace
+		}
+		return rules[i].Binding < rules[j].Binding
+	})
+	return rules, nil
+}
+
+// boundUserGroup returns whether the binding has a kubernetes group subject the user belongs to, and the group
+func boundUserGroup(rtb *mgmtv1.RoleTemplateBinding, userGroups []string) (bool, string) {
+	for _, subject := range rtb.Subjects {
+		if subject.Kind != rbacv1.GroupKind || roletemplatebinding.IsGroupSubject(subject) {
+			continue
+		}
+		if slices.Contains(userGroups, subject.Name) {
+			return true, subject.Name
+		}
+	}
+	return false, ""
+}
+
+// bindingRules returns the rules of the role the binding refers to, the missing roles grant nothing
+func (r *Reviewer) bindingRules(rtb *mgmtv1.RoleTemplateBinding, namespace string) ([]EffectiveRule, error) {
+	switch rtb.RoleTemplateRef.Kind {
+	case roletemplatebinding.GlobalRoleKindName:
+		gr, err := r.grCache.Get(rtb.RoleTemplateRef.Name)
+		if err != nil && errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get global role %s: %w", rtb.RoleTemplateRef.Name, err)
+		}
+		return globalRoleRules(gr, namespace), nil
+	case roletemplatebinding.RoleTemplateKindName:
+		rt, err := r.roleTemplateCache.Get(rtb.RoleTemplateRef.Name)
+		if err != nil && errors.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get role template %s: %w", rtb.RoleTemplateRef.Name, err)
+		}
+		return roleTemplateRules(rt, rtb.NamespaceId, namespace), nil
+	default:
+		return nil, nil
+	}
+}
+
+func globalRoleRules(gr *mgmtv1.GlobalRole, namespace string) []EffectiveRule {
+	rules := make([]EffectiveRule, 0, len(gr.Rules))
+	for _, rule := range gr.Rules {
+		rules = append(rules, EffectiveRule{PolicyRule: rule})
+	}
+	for ns, nsRules := range gr.NamespacedRules {
+		if namespace != "" && ns != namespace {
+			continue
+		}
+		for _, rule := range nsRules {
+			rules = append(rules, EffectiveRule{PolicyRule: rule, Namespace: ns})
+		}
+	}
+	return rules
+}
+
+func roleTemplateRules(rt *mgmtv1.RoleTemplate, bindingNamespace, namespace string) []EffectiveRule {
+	if namespace != "" && bindingNamespace != namespace {
+		return nil
+	}
+	rules := make([]EffectiveRule, 0, len(rt.Rules))
+	for _, rule := range rt.Rules {
+		rules = append(rules, EffectiveRule{PolicyRule: rule, Namespace: bindingNamespace})
+	}
+	return rules
+}
+
+// CanI checks the access of the user by a SubjectAccessReview, which impersonates the user the same way as the
+// API requests of the user, so that the result is what the kubernetes authorizer decides
+func (r *Reviewer) CanI(user *mgmtv1.User, review *AccessReview) (*AccessReviewResult, error) {
+	sar := &authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authzv1.ResourceAttributes{
+				Namespace:   review.Namespace,
+				Verb:        review.Verb,
+				Group:       review.Group,
+				Resource:    review.Resource,
+				Subresource: review.Subresource,
+				Name:        review.Name,
+			},
+			User:   user.Name,
+			UID:    string(user.UID),
+			Groups: auth.UserGroups(user),
+		},
+	}
+
+	result, err := r.sar.Create(r.ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subject access review: %w", err)
+	}
+	return &AccessReviewResult{
+		Allowed: result.Status.Allowed,
+		Reason:  result.Status.Reason,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+)
+
+func TestGlobalRoleRules(t *testing.T) {
+	clusterRule := rbacv1.PolicyRule{APIGroups: []string{"ml.llmos.ai"}, Resources: []string{"notebookprofiles"},
+		Verbs: []string{"get"}}
+	teamARule := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}}
+	teamBRule := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"services"}, Verbs: []string{"get"}}
+	gr := &mgmtv1.GlobalRole{
+		Rules: []rbacv1.PolicyRule{clusterRule},
+		NamespacedRules: map[string][]rbacv1.PolicyRule{
+			"team-a": {teamARule},
+			"team-b": {teamBRule},
+		},
+	}
+
+	assert.ElementsMatch(t, []EffectiveRule{
+		{PolicyRule: clusterRule},
+		{PolicyRule: teamARule, Namespace: "team-a"},
+		{PolicyRule: teamBRule, Namespace: "team-b"},
+	}, globalRoleRules(gr, ""))
+	assert.Equal(t, []EffectiveRule{
+		{PolicyRule: clusterRule},
+		{PolicyRule: teamARule, Namespace: "team-a"},
+	}, globalRoleRules(gr, "team-a"))
+}
+
+func TestRoleTemplateRules(t *testing.T) {
+	rule := rbacv1.PolicyRule{APIGroups: []string{"ml.llmos.ai"}, Resources: []string{"modelservices"},
+		Verbs: []string{"*"}}
+	rt := &mgmtv1.RoleTemplate{Rules: []rbacv1.PolicyRule{rule}}
+
+	assert.Equal(t, []EffectiveRule{{PolicyRule: rule, Namespace: "team-a"}}, roleTemplateRules(rt, "team-a", ""))
+	assert.Equal(t, []EffectiveRule{{PolicyRule: rule, Namespace: "team-a"}},
+		roleTemplateRules(rt, "team-a", "team-a"))
+	assert.Empty(t, roleTemplateRules(rt, "team-a", "team-b"))
+}
+
+func TestBoundUserGroup(t *testing.T) {
+	rtb := &mgmtv1.RoleTemplateBinding{
+		Subjects: []rbacv1.Subject{
+			{Kind: rbacv1.GroupKind, APIGroup: mgmtv1.SchemeGroupVersion.Group, Name: constant.AdminRole},
+			{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: constant.AdminRole},
+		},
+	}
+	admin := &mgmtv1.User{Status: mgmtv1.UserStatus{IsAdmin: true}}
+	user := &mgmtv1.User{}
+
+	bound, through := boundUserGroup(rtb, auth.UserGroups(admin))
+	assert.True(t, bound)
+	assert.Equal(t, constant.AdminRole, through)
+
+	bound, _ = boundUserGroup(rtb, auth.UserGroups(user))
+	assert.False(t, bound)
+}
@@ -0,0 +1,444 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	ProviderName = "ldap"
+
+	serviceAccountPasswordKey = "password"
+	defaultSyncInterval       = time.Hour
+	defaultTimeout            = 30 * time.Second
+)
+
+// Config is the config of the LDAP provider stored in the auth-ldap-config setting
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// DisplayName is the name shown on the login page, e.g., Active Directory
+	DisplayName string `json:"displayName,omitempty"`
+	// URL is the url of the LDAP server, e.g., ldaps://ad.example.com:636 or ldap://ldap.example.com:389
+	URL string `json:"url"`
+	// StartTLS upgrades the ldap:// connections to TLS by the StartTLS operation
+	StartTLS bool `json:"startTLS,omitempty"`
+	// CACertificate is the PEM encoded CA certificates verifying the server, the system CAs are used if empty
+	CACertificate string `json:"caCertificate,omitempty"`
+
+	// ServiceAccountDN is the DN of the account searching the users and groups, its password is read from the
+	// password key of the ServiceAccountSecretName secret in the system namespace
+	ServiceAccountDN         string `json:"serviceAccountDN,omitempty"`
+	ServiceAccountSecretName string `json:"serviceAccountSecretName,omitempty"`
+
+	// UserDNTemplate is the DN the users bind with directly, e.g., uid=%s,ou=people,dc=example,dc=com,
+	// the users are searched under the UserSearchBase by the service account if not specified
+	UserDNTemplate       string `json:"userDNTemplate,omitempty"`
+	UserSearchBase       string `json:"userSearchBase,omitempty"`
+	UserSearchFilter     string `json:"userSearchFilter,omitempty"`
+	UsernameAttribute    string `json:"usernameAttribute,omitempty"`
+	DisplayNameAttribute string `json:"displayNameAttribute,omitempty"`
+	EmailAttribute       string `json:"emailAttribute,omitempty"`
+
+	// GroupSearchBase is where the groups of the users are searched by their member attribute, the groups are read
+	// from the memberOf attribute of the users if not specified, e.g., Active Directory
+	GroupSearchBase       string `json:"groupSearchBase,omitempty"`
+	GroupSearchFilter     string `json:"groupSearchFilter,omitempty"`
+	GroupMemberAttribute  string `json:"groupMemberAttribute,omitempty"`
+	GroupNameAttribute    string `json:"groupNameAttribute,omitempty"`
+	UserMemberOfAttribute string `json:"userMemberOfAttribute,omitempty"`
+
+	// AllowedGroups restricts the login to the members of the groups if specified
+	AllowedGroups []string                 `json:"allowedGroups,omitempty"`
+	GroupMappings []providers.GroupMapping `json:"groupMappings,omitempty"`
+	// SyncIntervalMinutes is the interval of syncing the group memberships of the users, defaults to 60
+	SyncIntervalMinutes int `json:"syncIntervalMinutes,omitempty"`
+}
+
+// ParseConfig parses the config of the setting, nil is returned if the setting is empty
+func ParseConfig(value string) (*Config, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal([]byte(value), config); err != nil {
+		return nil, fmt.Errorf("failed to parse LDAP config: %w", err)
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("url of LDAP config is required")
+	}
+	if config.UserDNTemplate != "" && strings.Count(config.UserDNTemplate, "%s") != 1 {
+		return nil, fmt.Errorf("userDNTemplate of LDAP config must contain exactly one %%s")
+	}
+	if config.UserDNTemplate == "" && (config.UserSearchBase == "" || config.ServiceAccountDN == "") {
+		return nil, fmt.Errorf("userDNTemplate, or userSearchBase and serviceAccountDN of LDAP config are required")
+	}
+
+	setDefault := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	setDefault(&config.UserSearchFilter, "(objectClass=person)")
+	setDefault(&config.UsernameAttribute, "uid")
+	setDefault(&config.DisplayNameAttribute, "cn")
+	setDefault(&config.EmailAttribute, "mail")
+	setDefault(&config.GroupSearchFilter,
+		"(|(objectClass=groupOfNames)(objectClass=groupOfUniqueNames)(objectClass=group))")
+	setDefault(&config.GroupMemberAttribute, "member")
+	setDefault(&config.GroupNameAttribute, "cn")
+	setDefault(&config.UserMemberOfAttribute, "memberOf")
+	return config, nil
+}
+
+// Provider is the LDAP auth provider, the principal ids of the users are their DNs
+type Provider struct {
+	secrets ctlcorev1.SecretClient
+}
+
+var (
+	_ providers.PasswordProvider = &Provider{}
+	_ providers.SyncProvider     = &Provider{}
+)
+
+func NewProvider(secrets ctlcorev1.SecretClient) *Provider {
+	return &Provider{
+		secrets: secrets,
+	}
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+func (p *Provider) DisplayName() string {
+	config, err := ParseConfig(settings.AuthLDAPConfig.Get())
+	if err != nil || config == nil || config.DisplayName == "" {
+		return "LDAP"
+	}
+	return config.DisplayName
+}
+
+func (p *Provider) Enabled() bool {
+	config, err := ParseConfig(settings.AuthLDAPConfig.Get())
+	return err == nil && config != nil && config.Enabled
+}
+
+func (p *Provider) GroupMappings() []providers.GroupMapping {
+	config, err := ParseConfig(settings.AuthLDAPConfig.Get())
+	if err != nil || config == nil {
+		return nil
+	}
+	return config.GroupMappings
+}
+
+func (p *Provider) SyncInterval() time.Duration {
+	config, err := ParseConfig(settings.AuthLDAPConfig.Get())
+	if err != nil || config == nil || config.SyncIntervalMinutes <= 0 {
+		return defaultSyncInterval
+	}
+	return time.Duration(config.SyncIntervalMinutes) * time.Minute
+}
+
+func (p *Provider) getConfig() (*Config, error) {
+	config, err := ParseConfig(settings.AuthLDAPConfig.Get())
+	if err != nil {
+		return nil, err
+	}
+	if config == nil || !config.Enabled {
+		return nil, providers.ErrProviderDisabled
+	}
+	return config, nil
+}
+
+// Authenticate binds as the user to verify the password, the user is either bound by the DN of the template or
+// searched by the service account
+func (p *Provider) Authenticate(_ context.Context, username, password string) (*providers.Principal, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+	if username == "" || password == "" {
+		return nil, providers.ErrInvalidCredentials
+	}
+
+	conn, err := p.connect(config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var entry *goldap.Entry
+	if config.UserDNTemplate != "" {
+		dn := fmt.Sprintf(config.UserDNTemplate, escapeDN(username))
+		if err = bindUser(conn, dn, password); err != nil {
+			return nil, err
+		}
+		// the user entry and groups are read as the user if there is no service account
+		if err = p.bindServiceAccount(conn, config); err != nil {
+			return nil, err
+		}
+		if entry, err = getUser(conn, config, dn); err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return nil, fmt.Errorf("user %s doesn't match the user search filter", dn)
+		}
+	} else {
+		if err = p.bindServiceAccount(conn, config); err != nil {
+			return nil, err
+		}
+		if entry, err = searchUser(conn, config, username); err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return nil, providers.ErrInvalidCredentials
+		}
+		if err = bindUser(conn, entry.DN, password); err != nil {
+			return nil, err
+		}
+		if err = p.bindServiceAccount(conn, config); err != nil {
+			return nil, err
+		}
+	}
+
+	principal, err := getPrincipal(conn, config, entry)
+	if err != nil {
+		return nil, err
+	}
+	if principal.Username == "" {
+		principal.Username = username
+	}
+	if !providers.IsGroupAllowed(principal, config.AllowedGroups) {
+		return nil, providers.ErrGroupNotAllowed
+	}
+	return principal, nil
+}
+
+// Lookup returns the principals of the DNs still matching the user search filter, the service account is
+// required to look up the users
+func (p *Provider) Lookup(_ context.Context, ids []string) (map[string]*providers.Principal, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.ServiceAccountDN == "" {
+		return nil, fmt.Errorf("serviceAccountDN of LDAP config is required to sync the users")
+	}
+
+	conn, err := p.connect(config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err = p.bindServiceAccount(conn, config); err != nil {
+		return nil, err
+	}
+
+	result := map[string]*providers.Principal{}
+	for _, id := range ids {
+		entry, err := getUser(conn, config, id)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+		if result[id], err = getPrincipal(conn, config, entry); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// connect dials the ldap:// or ldaps:// url, the ldap:// connection is upgraded by StartTLS if enabled
+func (p *Provider) connect(config *Config) (*goldap.Conn, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url of LDAP config: %w", err)
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, fmt.Errorf("unsupported scheme %s of LDAP url, must be ldap or ldaps", u.Scheme)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: u.Hostname()}
+	if config.CACertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CACertificate)) {
+			return nil, fmt.Errorf("invalid caCertificate of LDAP config")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	conn, err := goldap.DialURL(config.URL, goldap.DialWithDialer(&net.Dialer{Timeout: defaultTimeout}),
+		goldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	conn.SetTimeout(defaultTimeout)
+	if config.StartTLS && u.Scheme == "ldap" {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (p *Provider) bindServiceAccount(conn *goldap.Conn, config *Config) error {
+	if config.ServiceAccountDN == "" {
+		return nil
+	}
+
+	secret, err := p.secrets.Get(constant.SystemNamespaceName, config.ServiceAccountSecretName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get LDAP service account secret: %w", err)
+	}
+	if err = conn.Bind(config.ServiceAccountDN, string(secret.Data[serviceAccountPasswordKey])); err != nil {
+		return fmt.Errorf("failed to bind LDAP service account: %w", err)
+	}
+	return nil
+}
+
+func bindUser(conn *goldap.Conn, dn, password string) error {
+	if err := conn.Bind(dn, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return providers.ErrInvalidCredentials
+		}
+		return fmt.Errorf("failed to bind LDAP user: %w", err)
+	}
+	return nil
+}
+
+func userAttributes(config *Config) []string {
+	return []string{config.UsernameAttribute, config.DisplayNameAttribute, config.EmailAttribute,
+		config.UserMemberOfAttribute}
+}
+
+// getUser returns the entry of the DN, nil is returned if the entry doesn't exist or doesn't match the user search
+// filter any more
+func getUser(conn *goldap.Conn, config *Config, dn string) (*goldap.Entry, error) {
+	result, err := conn.Search(newSearchRequest(dn, goldap.ScopeBaseObject, 1, config.UserSearchFilter,
+		userAttributes(config)))
+	if err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get LDAP user %s: %w", dn, err)
+	}
+	if len(result.Entries) == 0 {
+		return nil, nil
+	}
+	return result.Entries[0], nil
+}
+
+func searchUser(conn *goldap.Conn, config *Config, username string) (*goldap.Entry, error) {
+	filter := fmt.Sprintf("(&%s(%s=%s))", config.UserSearchFilter, config.UsernameAttribute,
+		goldap.EscapeFilter(username))
+	result, err := conn.Search(newSearchRequest(config.UserSearchBase, goldap.ScopeWholeSubtree, 2, filter,
+		userAttributes(config)))
+	// the entries found before the size limit exceeded are still returned
+	if err != nil && (result == nil || !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded)) {
+		return nil, fmt.Errorf("failed to search LDAP user %s: %w", username, err)
+	}
+	entries := result.Entries
+	if len(entries) > 1 {
+		return nil, fmt.Errorf("found more than one LDAP users with username %s", username)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return entries[0], nil
+}
+
+func getPrincipal(conn *goldap.Conn, config *Config, entry *goldap.Entry) (*providers.Principal, error) {
+	groups, err := getGroups(conn, config, entry)
+	if err != nil {
+		return nil, err
+	}
+	return &providers.Principal{
+		Provider:    ProviderName,
+		ID:          entry.DN,
+		Username:    entry.GetEqualFoldAttributeValue(config.UsernameAttribute),
+		DisplayName: entry.GetEqualFoldAttributeValue(config.DisplayNameAttribute),
+		Email:       entry.GetEqualFoldAttributeValue(config.EmailAttribute),
+		Groups:      groups,
+	}, nil
+}
+
+func getGroups(conn *goldap.Conn, config *Config, entry *goldap.Entry) ([]string, error) {
+	var groups []string
+	if config.GroupSearchBase == "" {
+		for _, dn := range entry.GetEqualFoldAttributeValues(config.UserMemberOfAttribute) {
+			if name := firstRDNValue(dn); name != "" {
+				groups = append(groups, name)
+			}
+		}
+		return groups, nil
+	}
+
+	filter := fmt.Sprintf("(&%s(%s=%s))", config.GroupSearchFilter, config.GroupMemberAttribute,
+		goldap.EscapeFilter(entry.DN))
+	result, err := conn.Search(newSearchRequest(config.GroupSearchBase, goldap.ScopeWholeSubtree, 0, filter,
+		[]string{config.GroupNameAttribute}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search LDAP groups of %s: %w", entry.DN, err)
+	}
+	for _, group := range result.Entries {
+		if name := group.GetEqualFoldAttributeValue(config.GroupNameAttribute); name != "" {
+			groups = append(groups, name)
+		}
+	}
+	return groups, nil
+}
+
+// newSearchRequest returns the search request never dereferencing the aliases, the referrals are ignored
+func newSearchRequest(baseDN string, scope, sizeLimit int, filter string, attributes []string) *goldap.SearchRequest {
+	return goldap.NewSearchRequest(baseDN, scope, goldap.NeverDerefAliases, sizeLimit,
+		int(defaultTimeout/time.Second), false, filter, attributes, nil)
+}
+
+// firstRDNValue returns the value of the first RDN of the DN, e.g., ML Admins of CN=ML Admins,OU=Groups,DC=corp
+func firstRDNValue(dn string) string {
+	parsed, err := goldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(parsed.RDNs[0].Attributes[0].Value)
+}
+
+// escapeDN escapes the attribute value used in the distinguished name, see RFC 4514 section 2.4
+func escapeDN(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case strings.IndexByte(`,+"\<>;=`, c) >= 0,
+			c == '#' && i == 0,
+			c == ' ' && (i == 0 || i == len(value)-1):
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, "\\%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
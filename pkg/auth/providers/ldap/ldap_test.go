@@ -0,0 +1,416 @@
+package ldap
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+	"github.com/llmos-ai/llmos-operator/pkg/utils/fakeclients"
+)
+
+const (
+	serviceAccountDN       = "cn=admin,dc=example,dc=com"
+	serviceAccountPassword = "admin-password"
+	serviceAccountSecret   = "ldap-service-account"
+)
+
+// mockServer is an in-process LDAP server supporting the simple bind, search and unbind operations
+type mockServer struct {
+	listener  net.Listener
+	entries   []*goldap.Entry
+	passwords map[string]string
+}
+
+func newMockServer(t *testing.T) *mockServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	m := &mockServer{
+		listener: listener,
+		entries: []*goldap.Entry{
+			goldap.NewEntry("uid=alice,ou=people,dc=example,dc=com", map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"alice"},
+				"cn":          {"Alice Smith"},
+				"mail":        {"alice@example.com"},
+				"memberOf":    {"cn=ml-admins,ou=groups,dc=example,dc=com"},
+			}),
+			goldap.NewEntry("uid=bob,ou=people,dc=example,dc=com", map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"bob"},
+				"cn":          {"Bob"},
+			}),
+			goldap.NewEntry("cn=ml-admins,ou=groups,dc=example,dc=com", map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"cn":          {"ml-admins"},
+				"member":      {"uid=alice,ou=people,dc=example,dc=com"},
+			}),
+			goldap.NewEntry("cn=developers,ou=groups,dc=example,dc=com", map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"cn":          {"developers"},
+				"member":      {"uid=alice,ou=people,dc=example,dc=com", "uid=bob,ou=people,dc=example,dc=com"},
+			}),
+		},
+		passwords: map[string]string{
+			serviceAccountDN:                        serviceAccountPassword,
+			"uid=alice,ou=people,dc=example,dc=com": "alice-password",
+			"uid=bob,ou=people,dc=example,dc=com":   "bob-password",
+		},
+	}
+	go m.serve()
+	return m
+}
+
+func (m *mockServer) url() string {
+	return "ldap://" + m.listener.Addr().String()
+}
+
+func (m *mockServer) serve() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.handle(conn)
+	}
+}
+
+func (m *mockServer) handle(conn net.Conn) {
+	defer conn.Close()
+	bound := false
+	for {
+		msg, err := ber.ReadPacket(conn)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		id, op := msg.Children[0].Value, msg.Children[1]
+		reply := func(p *ber.Packet) {
+			envelope := ber.NewSequence("")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+			envelope.AppendChild(p)
+			_, _ = conn.Write(envelope.Bytes())
+		}
+
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			dn, password := op.Children[1].Value.(string), op.Children[2].Data.String()
+			code := int64(goldap.LDAPResultInvalidCredentials)
+			if expected, ok := m.passwords[dn]; ok && expected == password {
+				code, bound = goldap.LDAPResultSuccess, true
+			}
+			reply(newResult(goldap.ApplicationBindResponse, code))
+		case goldap.ApplicationSearchRequest:
+			if !bound {
+				reply(newResult(goldap.ApplicationSearchResultDone, goldap.LDAPResultInsufficientAccessRights))
+				continue
+			}
+			entries, code := m.search(op)
+			for _, entry := range entries {
+				reply(encodeEntry(entry))
+			}
+			reply(newResult(goldap.ApplicationSearchResultDone, code))
+		case goldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func (m *mockServer) search(op *ber.Packet) ([]*goldap.Entry, int64) {
+	base := strings.ToLower(op.Children[0].Value.(string))
+	scope := op.Children[1].Value.(int64)
+	filter := op.Children[6]
+
+	var result []*goldap.Entry
+	found := false
+	for _, entry := range m.entries {
+		dn := strings.ToLower(entry.DN)
+		switch {
+		case scope == goldap.ScopeBaseObject && dn == base:
+			found = true
+		case scope == goldap.ScopeWholeSubtree && strings.HasSuffix(dn, base):
+			found = true
+		default:
+			continue
+		}
+		if matchFilter(filter, entry) {
+			result = append(result, entry)
+		}
+	}
+	if !found {
+		return nil, goldap.LDAPResultNoSuchObject
+	}
+	return result, goldap.LDAPResultSuccess
+}
+
+func matchFilter(filter *ber.Packet, entry *goldap.Entry) bool {
+	switch filter.Tag {
+	case goldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matchFilter(child, entry) {
+				return false
+			}
+		}
+		return true
+	case goldap.FilterOr:
+		for _, child := range filter.Children {
+			if matchFilter(child, entry) {
+				return true
+			}
+		}
+		return false
+	case goldap.FilterNot:
+		return !matchFilter(filter.Children[0], entry)
+	case goldap.FilterPresent:
+		return len(entry.GetEqualFoldAttributeValues(filter.Data.String())) > 0
+	case goldap.FilterEqualityMatch:
+		for _, v := range entry.GetEqualFoldAttributeValues(filter.Children[0].Value.(string)) {
+			if strings.EqualFold(v, filter.Children[1].Value.(string)) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func newOctetString(s string) *ber.Packet {
+	return ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, s, "")
+}
+
+func newResult(op ber.Tag, code int64) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, op, nil, "")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, ""))
+	p.AppendChild(newOctetString(""))
+	p.AppendChild(newOctetString(""))
+	return p
+}
+
+func encodeEntry(entry *goldap.Entry) *ber.Packet {
+	attrs := ber.NewSequence("")
+	for _, attr := range entry.Attributes {
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+		for _, v := range attr.Values {
+			set.AppendChild(newOctetString(v))
+		}
+		item := ber.NewSequence("")
+		item.AppendChild(newOctetString(attr.Name))
+		item.AppendChild(set)
+		attrs.AppendChild(item)
+	}
+
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "")
+	p.AppendChild(newOctetString(entry.DN))
+	p.AppendChild(attrs)
+	return p
+}
+
+func newTestProvider(t *testing.T) *Provider {
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: serviceAccountSecret, Namespace: constant.SystemNamespaceName},
+		Data:       map[string][]byte{serviceAccountPasswordKey: []byte(serviceAccountPassword)},
+	})
+	return NewProvider(fakeclients.SecretClient(clientset.CoreV1().Secrets))
+}
+
+func setConfig(t *testing.T, config Config) {
+	value, err := json.Marshal(config)
+	require.NoError(t, err)
+	require.NoError(t, settings.AuthLDAPConfig.Set(string(value)))
+	t.Cleanup(func() { _ = settings.AuthLDAPConfig.Set("") })
+}
+
+func searchConfig(url string) Config {
+	return Config{
+		Enabled:                  true,
+		URL:                      url,
+		ServiceAccountDN:         serviceAccountDN,
+		ServiceAccountSecretName: serviceAccountSecret,
+		UserSearchBase:           "ou=people,dc=example,dc=com",
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	server := newMockServer(t)
+
+	var tests = []struct {
+		name     string
+		config   func(c *Config)
+		username string
+		password string
+		expected *providers.Principal
+		err      error
+	}{
+		{
+			name:     "search with memberOf groups",
+			username: "alice",
+			password: "alice-password",
+			expected: &providers.Principal{
+				Provider:    ProviderName,
+				ID:          "uid=alice,ou=people,dc=example,dc=com",
+				Username:    "alice",
+				DisplayName: "Alice Smith",
+				Email:       "alice@example.com",
+				Groups:      []string{"ml-admins"},
+			},
+		},
+		{
+			name: "search with group search base",
+			config: func(c *Config) {
+				c.GroupSearchBase = "ou=groups,dc=example,dc=com"
+			},
+			username: "bob",
+			password: "bob-password",
+			expected: &providers.Principal{
+				Provider:    ProviderName,
+				ID:          "uid=bob,ou=people,dc=example,dc=com",
+				Username:    "bob",
+				DisplayName: "Bob",
+				Groups:      []string{"developers"},
+			},
+		},
+		{
+			name: "bind with user DN template",
+			config: func(c *Config) {
+				c.ServiceAccountDN = ""
+				c.UserSearchBase = ""
+				c.UserDNTemplate = "uid=%s,ou=people,dc=example,dc=com"
+			},
+			username: "alice",
+			password: "alice-password",
+			expected: &providers.Principal{
+				Provider:    ProviderName,
+				ID:          "uid=alice,ou=people,dc=example,dc=com",
+				Username:    "alice",
+				DisplayName: "Alice Smith",
+				Email:       "alice@example.com",
+				Groups:      []string{"ml-admins"},
+			},
+		},
+		{
+			name:     "wrong password",
+			username: "alice",
+			password: "bob-password",
+			err:      providers.ErrInvalidCredentials,
+		},
+		{
+			name:     "empty password",
+			username: "alice",
+			password: "",
+			err:      providers.ErrInvalidCredentials,
+		},
+		{
+			name:     "user not found",
+			username: "carol",
+			password: "carol-password",
+			err:      providers.ErrInvalidCredentials,
+		},
+		{
+			name:     "filter injection",
+			username: "*",
+			password: "alice-password",
+			err:      providers.ErrInvalidCredentials,
+		},
+		{
+			name: "not in allowed groups",
+			config: func(c *Config) {
+				c.AllowedGroups = []string{"ml-admins"}
+			},
+			username: "bob",
+			password: "bob-password",
+			err:      providers.ErrGroupNotAllowed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := searchConfig(server.url())
+			if tc.config != nil {
+				tc.config(&config)
+			}
+			setConfig(t, config)
+
+			principal, err := newTestProvider(t).Authenticate(t.Context(), tc.username, tc.password)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, principal)
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	server := newMockServer(t)
+	setConfig(t, searchConfig(server.url()))
+
+	principals, err := newTestProvider(t).Lookup(t.Context(), []string{
+		"uid=alice,ou=people,dc=example,dc=com",
+		"uid=carol,ou=people,dc=example,dc=com",
+		// the groups don't match the user search filter
+		"cn=developers,ou=groups,dc=example,dc=com",
+	})
+	require.NoError(t, err)
+	assert.Len(t, principals, 1)
+	require.Contains(t, principals, "uid=alice,ou=people,dc=example,dc=com")
+	assert.Equal(t, []string{"ml-admins"}, principals["uid=alice,ou=people,dc=example,dc=com"].Groups)
+}
+
+func TestProviderDisabled(t *testing.T) {
+	config := searchConfig("ldap://127.0.0.1:1")
+	config.Enabled = false
+	setConfig(t, config)
+
+	provider := newTestProvider(t)
+	assert.False(t, provider.Enabled())
+	_, err := provider.Authenticate(t.Context(), "alice", "alice-password")
+	assert.ErrorIs(t, err, providers.ErrProviderDisabled)
+}
+
+func TestParseConfig(t *testing.T) {
+	var tests = []struct {
+		name  string
+		value string
+		err   bool
+	}{
+		{name: "empty", value: ""},
+		{name: "search", value: `{"url":"ldaps://ad","userSearchBase":"dc=corp","serviceAccountDN":"cn=svc"}`},
+		{name: "template", value: `{"url":"ldap://ldap","userDNTemplate":"uid=%s,dc=example"}`},
+		{name: "missing url", value: `{"userDNTemplate":"uid=%s,dc=example"}`, err: true},
+		{name: "invalid template", value: `{"url":"ldap://ldap","userDNTemplate":"uid=%s,cn=%s"}`, err: true},
+		{name: "missing service account", value: `{"url":"ldap://ldap","userSearchBase":"dc=corp"}`, err: true},
+		{name: "invalid json", value: `{`, err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseConfig(tc.value)
+			if tc.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `\#a\,b\+c\=d\ `, escapeDN(`#a,b+c=d `))
+	assert.Equal(t, "ML Admins", firstRDNValue(`CN=ML Admins,OU=Groups,DC=corp`))
+	assert.Equal(t, "a,b", firstRDNValue(`cn=a\,b,dc=corp`))
+	assert.Equal(t, "", firstRDNValue(`not a dn`))
+}
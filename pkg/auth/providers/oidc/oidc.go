@@ -0,0 +1,355 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	ProviderName = "oidc"
+
+	clientSecretKey      = "clientSecret"
+	defaultUsernameClaim = "preferred_username"
+	defaultGroupsClaim   = "groups"
+	clockSkew            = time.Minute
+)
+
+var (
+	defaultScopes = []string{"openid", "profile", "email", "groups"}
+
+	supportedAlgorithms = []jose.SignatureAlgorithm{
+		jose.RS256, jose.RS384, jose.RS512,
+		jose.ES256, jose.ES384, jose.ES512,
+		jose.PS256, jose.PS384, jose.PS512,
+	}
+)
+
+// Config is the config of the OIDC provider stored in the auth-oidc-config setting
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// DisplayName is the name shown on the login page, e.g., Keycloak
+	DisplayName string `json:"displayName,omitempty"`
+	// Issuer is the issuer url of the identity provider, the endpoints are discovered from
+	// the /.well-known/openid-configuration of the issuer
+	Issuer   string `json:"issuer"`
+	ClientID string `json:"clientId"`
+	// ClientSecretName is the name of the secret in the system namespace holding the client secret in the
+	// clientSecret key, it's not required by the public clients as the PKCE is always used
+	ClientSecretName string   `json:"clientSecretName,omitempty"`
+	Scopes           []string `json:"scopes,omitempty"`
+	// UsernameClaim is the claim used as the username, defaults to preferred_username
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	// GroupsClaim is the claim holding the groups of the user, defaults to groups
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// AllowedGroups restricts the login to the members of the groups if specified
+	AllowedGroups []string                 `json:"allowedGroups,omitempty"`
+	GroupMappings []providers.GroupMapping `json:"groupMappings,omitempty"`
+}
+
+// ParseConfig parses the config of the setting, nil is returned if the setting is empty
+func ParseConfig(value string) (*Config, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal([]byte(value), config); err != nil {
+		return nil, fmt.Errorf("failed to parse OIDC config: %w", err)
+	}
+	if config.Issuer == "" || config.ClientID == "" {
+		return nil, fmt.Errorf("issuer and clientId of OIDC config are required")
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaultScopes
+	}
+	if config.UsernameClaim == "" {
+		config.UsernameClaim = defaultUsernameClaim
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = defaultGroupsClaim
+	}
+	return config, nil
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is the OIDC auth provider, the identity provider is discovered again whenever the setting changes
+type Provider struct {
+	secrets    ctlcorev1.SecretClient
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	rawConfig string
+	config    *Config
+	discovery *discovery
+	keys      *jose.JSONWebKeySet
+}
+
+var _ providers.RedirectProvider = &Provider{}
+
+func NewProvider(secrets ctlcorev1.SecretClient) *Provider {
+	return &Provider{
+		secrets:    secrets,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+func (p *Provider) DisplayName() string {
+	config, err := ParseConfig(settings.AuthOIDCConfig.Get())
+	if err != nil || config == nil || config.DisplayName == "" {
+		return "OIDC"
+	}
+	return config.DisplayName
+}
+
+func (p *Provider) Enabled() bool {
+	config, err := ParseConfig(settings.AuthOIDCConfig.Get())
+	return err == nil && config != nil && config.Enabled
+}
+
+func (p *Provider) GroupMappings() []providers.GroupMapping {
+	config, err := ParseConfig(settings.AuthOIDCConfig.Get())
+	if err != nil || config == nil {
+		return nil
+	}
+	return config.GroupMappings
+}
+
+func (p *Provider) AuthCodeURL(ctx context.Context, state *providers.LoginState, redirectURI string) (string, error) {
+	config, _, oauthConfig, err := p.load(ctx, redirectURI)
+	if err != nil {
+		return "", err
+	}
+	if !config.Enabled {
+		return "", providers.ErrProviderDisabled
+	}
+
+	return oauthConfig.AuthCodeURL(state.State, oauth2.S256ChallengeOption(state.Verifier),
+		oauth2.SetAuthURLParam("nonce", state.Nonce)), nil
+}
+
+func (p *Provider) Exchange(ctx context.Context, code string, state *providers.LoginState,
+	redirectURI string) (*providers.Principal, error) {
+	config, d, oauthConfig, err := p.load(ctx, redirectURI)
+	if err != nil {
+		return nil, err
+	}
+	if !config.Enabled {
+		return nil, providers.ErrProviderDisabled
+	}
+
+	token, err := oauthConfig.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.httpClient), code,
+		oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("no id_token in the token response")
+	}
+
+	claims, err := p.verifyIDToken(ctx, config, d, rawIDToken, state.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	principal, err := principalFromClaims(config, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !providers.IsGroupAllowed(principal, config.AllowedGroups) {
+		return nil, providers.ErrGroupNotAllowed
+	}
+	return principal, nil
+}
+
+// load returns the config of the setting, the discovery document and the oauth2 config of the discovered endpoints
+func (p *Provider) load(ctx context.Context, redirectURI string) (*Config, *discovery, *oauth2.Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	raw := settings.AuthOIDCConfig.Get()
+	if p.config == nil || raw != p.rawConfig {
+		config, err := ParseConfig(raw)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if config == nil {
+			return nil, nil, nil, providers.ErrProviderDisabled
+		}
+
+		d, err := p.discover(ctx, config.Issuer)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		p.rawConfig, p.config, p.discovery, p.keys = raw, config, d, nil
+	}
+
+	clientSecret := ""
+	if p.config.ClientSecretName != "" {
+		secret, err := p.secrets.Get(constant.SystemNamespaceName, p.config.ClientSecretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get OIDC client secret: %w", err)
+		}
+		clientSecret = string(secret.Data[clientSecretKey])
+	}
+
+	return p.config, p.discovery, &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: clientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.discovery.AuthorizationEndpoint,
+			TokenURL: p.discovery.TokenEndpoint,
+		},
+		RedirectURL: redirectURI,
+		Scopes:      p.config.Scopes,
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context, issuer string) (*discovery, error) {
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	d := &discovery{}
+	if err := p.getJSON(ctx, wellKnown, d); err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC issuer %s: %w", issuer, err)
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return nil, fmt.Errorf("issuer %s of the discovery document doesn't match %s", d.Issuer, issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("authorization, token or jwks endpoint of OIDC issuer %s is missing", issuer)
+	}
+	return d, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s of %s", resp.Status, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// verifyIDToken verifies the signature, issuer, audience, expiry and nonce of the ID token and returns its claims,
+// the keys are fetched again once if the token is signed by an unknown key as the identity provider rotates them
+func (p *Provider) verifyIDToken(ctx context.Context, config *Config, d *discovery,
+	raw, nonce string) (map[string]interface{}, error) {
+	token, err := jwt.ParseSigned(raw, supportedAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse id_token: %w", err)
+	}
+
+	standard := jwt.Claims{}
+	claims := map[string]interface{}{}
+	for refreshed := false; ; refreshed = true {
+		keys, err := p.getKeys(ctx, d, refreshed)
+		if err != nil {
+			return nil, err
+		}
+		if err = token.Claims(keys, &standard, &claims); err == nil {
+			break
+		} else if refreshed {
+			return nil, fmt.Errorf("failed to verify id_token: %w", err)
+		}
+		logrus.Debugf("failed to verify id_token with the cached keys, refetching: %v", err)
+	}
+
+	if err = standard.ValidateWithLeeway(jwt.Expected{
+		Issuer:      d.Issuer,
+		AnyAudience: jwt.Audience{config.ClientID},
+		Time:        time.Now(),
+	}, clockSkew); err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if standard.Expiry == nil {
+		return nil, fmt.Errorf("invalid id_token: exp claim is required")
+	}
+	if claims["nonce"] != nonce {
+		return nil, fmt.Errorf("invalid id_token: nonce doesn't match")
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) getKeys(ctx context.Context, d *discovery, refresh bool) (*jose.JSONWebKeySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys != nil && !refresh {
+		return p.keys, nil
+	}
+
+	keys := &jose.JSONWebKeySet{}
+	if err := p.getJSON(ctx, d.JWKSURI, keys); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC keys: %w", err)
+	}
+	p.keys = keys
+	return keys, nil
+}
+
+func principalFromClaims(config *Config, claims map[string]interface{}) (*providers.Principal, error) {
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, errors.New("sub claim is missing in id_token")
+	}
+
+	username, _ := claims[config.UsernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("username claim %s is missing in id_token", config.UsernameClaim)
+	}
+
+	principal := &providers.Principal{
+		Provider: ProviderName,
+		ID:       sub,
+		Username: username,
+	}
+	principal.DisplayName, _ = claims["name"].(string)
+	principal.Email, _ = claims["email"].(string)
+
+	switch groups := claims[config.GroupsClaim].(type) {
+	case string:
+		principal.Groups = []string{groups}
+	case []interface{}:
+		for _, group := range groups {
+			if s, ok := group.(string); ok {
+				principal.Groups = append(principal.Groups, s)
+			}
+		}
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,276 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	testClientID    = "llmos"
+	testRedirectURI = "https://llmos.example.com/v1-public/auth/oidc/callback"
+	testCode        = "test-code"
+)
+
+// mockIssuer is a minimal OIDC issuer issuing the ID tokens of a single user
+type mockIssuer struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	keyID     string
+	forgedKey *rsa.PrivateKey
+	challenge string
+	nonce     string
+	claims    map[string]interface{}
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockIssuer{key: key, keyID: "key-1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(rw).Encode(map[string]string{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+			"jwks_uri":               m.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(rw http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(rw).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &m.key.PublicKey, KeyID: m.keyID, Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", func(rw http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != testCode ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     m.sign(t),
+		})
+	})
+	m.Server = httptest.NewServer(mux)
+
+	m.claims = map[string]interface{}{
+		"iss":                m.URL,
+		"sub":                "8d9a1c",
+		"aud":                testClientID,
+		"preferred_username": "alice",
+		"name":               "Alice",
+		"email":              "alice@example.com",
+		"groups":             []string{"ml-engineers", "ml-admins"},
+	}
+	return m
+}
+
+func (m *mockIssuer) sign(t *testing.T) string {
+	key := m.key
+	if m.forgedKey != nil {
+		key = m.forgedKey
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", m.keyID))
+	require.NoError(t, err)
+
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": m.nonce,
+	}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	raw, err := jwt.Signed(signer).Claims(claims).Serialize()
+	require.NoError(t, err)
+	return raw
+}
+
+// authorize simulates the user logging in the identity provider and returns the authorization code
+func (m *mockIssuer) authorize(t *testing.T, authCodeURL string) string {
+	u, err := url.Parse(authCodeURL)
+	require.NoError(t, err)
+	assert.Equal(t, m.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+
+	query := u.Query()
+	assert.Equal(t, testClientID, query.Get("client_id"))
+	assert.Equal(t, testRedirectURI, query.Get("redirect_uri"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	m.challenge = query.Get("code_challenge")
+	m.nonce = query.Get("nonce")
+	return testCode
+}
+
+func setConfig(t *testing.T, config Config) {
+	value, err := json.Marshal(config)
+	require.NoError(t, err)
+	require.NoError(t, settings.AuthOIDCConfig.Set(string(value)))
+	t.Cleanup(func() { _ = settings.AuthOIDCConfig.Set("") })
+}
+
+func newLoginState() *providers.LoginState {
+	return &providers.LoginState{State: "state", Nonce: "nonce", Verifier: "verifier-verifier-verifier-verifier-verifier"}
+}
+
+func TestExchange(t *testing.T) {
+	issuer := newMockIssuer(t)
+	defer issuer.Close()
+
+	setConfig(t, Config{
+		Enabled:       true,
+		Issuer:        issuer.URL,
+		ClientID:      testClientID,
+		AllowedGroups: []string{"ml-engineers"},
+	})
+	p := NewProvider(nil)
+	ctx := context.Background()
+	state := newLoginState()
+
+	authCodeURL, err := p.AuthCodeURL(ctx, state, testRedirectURI)
+	require.NoError(t, err)
+	code := issuer.authorize(t, authCodeURL)
+	assert.Equal(t, state.Nonce, issuer.nonce)
+
+	principal, err := p.Exchange(ctx, code, state, testRedirectURI)
+	require.NoError(t, err)
+	assert.Equal(t, &providers.Principal{
+		Provider:    ProviderName,
+		ID:          "8d9a1c",
+		Username:    "alice",
+		DisplayName: "Alice",
+		Email:       "alice@example.com",
+		Groups:      []string{"ml-engineers", "ml-admins"},
+	}, principal)
+
+	// the keys rotated by the issuer are fetched again
+	issuer.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer.keyID = "key-2"
+	_, err = p.Exchange(ctx, issuer.authorize(t, authCodeURL), state, testRedirectURI)
+	assert.NoError(t, err)
+}
+
+func TestExchangeErrors(t *testing.T) {
+	issuer := newMockIssuer(t)
+	defer issuer.Close()
+
+	var testCases = []struct {
+		name              string
+		config            Config
+		mutate            func(issuer *mockIssuer, state *providers.LoginState)
+		expectedErrString string
+	}{
+		{
+			name: "wrong verifier",
+			mutate: func(_ *mockIssuer, state *providers.LoginState) {
+				state.Verifier = "another-verifier-another-verifier-another"
+			},
+			expectedErrString: "failed to exchange authorization code",
+		},
+		{
+			name: "wrong nonce",
+			mutate: func(issuer *mockIssuer, _ *providers.LoginState) {
+				issuer.nonce = "replayed"
+			},
+			expectedErrString: "invalid id_token: nonce doesn't match",
+		},
+		{
+			name: "wrong audience",
+			mutate: func(issuer *mockIssuer, _ *providers.LoginState) {
+				issuer.claims["aud"] = "another-client"
+			},
+			expectedErrString: "invalid id_token",
+		},
+		{
+			name: "expired token",
+			mutate: func(issuer *mockIssuer, _ *providers.LoginState) {
+				issuer.claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			},
+			expectedErrString: "invalid id_token",
+		},
+		{
+			name: "forged signature",
+			mutate: func(issuer *mockIssuer, _ *providers.LoginState) {
+				issuer.forgedKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+			},
+			expectedErrString: "failed to verify id_token",
+		},
+		{
+			name:   "group not allowed",
+			config: Config{AllowedGroups: []string{"finance"}},
+			mutate: func(_ *mockIssuer, _ *providers.LoginState) {
+			},
+			expectedErrString: providers.ErrGroupNotAllowed.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		config := tc.config
+		config.Enabled, config.Issuer, config.ClientID = true, issuer.URL, testClientID
+		setConfig(t, config)
+
+		p := NewProvider(nil)
+		ctx := context.Background()
+		state := newLoginState()
+		authCodeURL, err := p.AuthCodeURL(ctx, state, testRedirectURI)
+		require.NoError(t, err, tc.name)
+		code := issuer.authorize(t, authCodeURL)
+
+		claims := issuer.claims
+		issuer.claims = map[string]interface{}{}
+		for k, v := range claims {
+			issuer.claims[k] = v
+		}
+		tc.mutate(issuer, state)
+
+		_, err = p.Exchange(ctx, code, state, testRedirectURI)
+		assert.ErrorContains(t, err, tc.expectedErrString, tc.name)
+		issuer.forgedKey, issuer.claims = nil, claims
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig("")
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	_, err = ParseConfig(`{"enabled":true}`)
+	assert.EqualError(t, err, "issuer and clientId of OIDC config are required")
+
+	config, err = ParseConfig(`{"enabled":true,"issuer":"https://dex.example.com","clientId":"llmos",` +
+		`"groupMappings":[{"group":"ml-admins","globalRoles":["admin"]}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, defaultScopes, config.Scopes)
+	assert.Equal(t, defaultUsernameClaim, config.UsernameClaim)
+	assert.Equal(t, defaultGroupsClaim, config.GroupsClaim)
+	assert.Equal(t, []providers.GroupMapping{{Group: "ml-admins", GlobalRoles: []string{"admin"}}},
+		config.GroupMappings)
+}
@@ -0,0 +1,112 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"time"
+)
+
+const (
+	LabelAuthProvider  = "auth.management.llmos.ai/provider"
+	LabelPrincipalHash = "auth.management.llmos.ai/principal-hash"
+)
+
+var (
+	ErrProviderDisabled = errors.New("auth provider is not enabled")
+	ErrGroupNotAllowed  = errors.New("user is not a member of the allowed groups")
+	// ErrInvalidCredentials is returned by the password providers if the username or password is incorrect
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
+
+// Principal is the identity of the user authenticated by the auth provider
+type Principal struct {
+	Provider    string
+	ID          string
+	Username    string
+	DisplayName string
+	Email       string
+	Groups      []string
+}
+
+// GroupMapping grants the global roles and the namespaced role templates to the members of the group
+type GroupMapping struct {
+	Group         string                   `json:"group"`
+	GlobalRoles   []string                 `json:"globalRoles,omitempty"`
+	RoleTemplates []NamespacedRoleTemplate `json:"roleTemplates,omitempty"`
+}
+
+type NamespacedRoleTemplate struct {
+	Namespace    string `json:"namespace"`
+	RoleTemplate string `json:"roleTemplate"`
+}
+
+// LoginState is the state of the redirect login kept by the browser between the login and the callback
+type LoginState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Redirect string `json:"redirect,omitempty"`
+}
+
+// Provider is an external auth provider the users are authenticated by, the users and their role bindings are
+// provisioned from the principals of the provider at login
+type Provider interface {
+	// Name is the unique name of the provider recorded in the users and tokens
+	Name() string
+	// DisplayName is the name shown on the login page
+	DisplayName() string
+	// Enabled returns whether the provider is configured and enabled
+	Enabled() bool
+	// GroupMappings returns the roles granted to the groups of the provider
+	GroupMappings() []GroupMapping
+}
+
+// RedirectProvider authenticates the users by redirecting them to the identity provider, e.g., OIDC
+type RedirectProvider interface {
+	Provider
+	// AuthCodeURL returns the url of the identity provider the user is redirected to
+	AuthCodeURL(ctx context.Context, state *LoginState, redirectURI string) (string, error)
+	// Exchange exchanges the authorization code returned to the callback for the principal of the user
+	Exchange(ctx context.Context, code string, state *LoginState, redirectURI string) (*Principal, error)
+}
+
+// PasswordProvider authenticates the users by their usernames and passwords, e.g., LDAP
+type PasswordProvider interface {
+	Provider
+	// Authenticate returns the principal of the user, ErrInvalidCredentials is returned if the username or
+	// password is incorrect
+	Authenticate(ctx context.Context, username, password string) (*Principal, error)
+}
+
+// SyncProvider looks up the principals periodically to keep the users in sync with the directory
+type SyncProvider interface {
+	Provider
+	// SyncInterval is the interval between the syncs
+	SyncInterval() time.Duration
+	// Lookup returns the principals of the ids found in the directory keyed by their ids
+	Lookup(ctx context.Context, ids []string) (map[string]*Principal, error)
+}
+
+// PrincipalHash returns the label value identifying the user of the principal, the principal ids are hashed as
+// they're not valid label values in general
+func PrincipalHash(provider, id string) string {
+	sum := sha256.Sum256([]byte(provider + "://" + id))
+	return hex.EncodeToString(sum[:])[:63]
+}
+
+// IsGroupAllowed returns whether the principal is a member of one of the allowed groups, all principals are
+// allowed if no groups are specified
+func IsGroupAllowed(principal *Principal, allowedGroups []string) bool {
+	if len(allowedGroups) == 0 {
+		return true
+	}
+	for _, group := range principal.Groups {
+		if slices.Contains(allowedGroups, group) {
+			return true
+		}
+	}
+	return false
+}
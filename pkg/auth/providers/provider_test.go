@@ -0,0 +1,71 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDesiredBindings(t *testing.T) {
+	mappings := []GroupMapping{
+		{
+			Group:       "ml-admins",
+			GlobalRoles: []string{"admin"},
+		},
+		{
+			Group:       "ml-engineers",
+			GlobalRoles: []string{"user"},
+			RoleTemplates: []NamespacedRoleTemplate{
+				{Namespace: "team-a", RoleTemplate: "namespace-owner"},
+			},
+		},
+		{
+			Group: "finance",
+			RoleTemplates: []NamespacedRoleTemplate{
+				{Namespace: "billing", RoleTemplate: "namespace-read-only"},
+			},
+		},
+	}
+
+	var testCases = []struct {
+		name     string
+		groups   []string
+		expected []BindingRef
+	}{
+		{
+			name:   "no groups",
+			groups: nil,
+			expected: []BindingRef{
+				{Kind: globalRoleKind, Name: "user"},
+			},
+		},
+		{
+			name:   "multiple groups",
+			groups: []string{"ml-engineers", "ml-admins", "unknown"},
+			expected: []BindingRef{
+				{Kind: globalRoleKind, Name: "user"},
+				{Kind: globalRoleKind, Name: "admin"},
+				{Kind: roleTemplateKind, Name: "namespace-owner", Namespace: "team-a"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, DesiredBindings(tc.groups, mappings, []string{"user"}), tc.name)
+	}
+}
+
+func TestIsGroupAllowed(t *testing.T) {
+	principal := &Principal{Groups: []string{"ml-engineers"}}
+
+	assert.True(t, IsGroupAllowed(principal, nil))
+	assert.True(t, IsGroupAllowed(principal, []string{"finance", "ml-engineers"}))
+	assert.False(t, IsGroupAllowed(principal, []string{"finance"}))
+}
+
+func TestPrincipalHash(t *testing.T) {
+	hash := PrincipalHash("oidc", "8d9a1c")
+	assert.Len(t, hash, 63)
+	assert.Equal(t, hash, PrincipalHash("oidc", "8d9a1c"))
+	assert.NotEqual(t, hash, PrincipalHash("ldap", "8d9a1c"))
+}
@@ -0,0 +1,246 @@
+package providers
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
+	ctlmgmt "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/indexeres"
+)
+
+const (
+	globalRoleKind   = "GlobalRole"
+	roleTemplateKind = "RoleTemplate"
+)
+
+// Provisioner creates the users of the principals and keeps their role bindings in sync with the group mappings
+type Provisioner struct {
+	userClient ctlmgmtv1.UserClient
+	userCache  ctlmgmtv1.UserCache
+	rtbClient  ctlmgmtv1.RoleTemplateBindingClient
+	grCache    ctlmgmtv1.GlobalRoleCache
+}
+
+func NewProvisioner(mgmt *ctlmgmt.Factory) *Provisioner {
+	users := mgmt.Management().V1().User()
+	return &Provisioner{
+		userClient: users,
+		userCache:  users.Cache(),
+		rtbClient:  mgmt.Management().V1().RoleTemplateBinding(),
+		grCache:    mgmt.Management().V1().GlobalRole().Cache(),
+	}
+}
+
+// Provision returns the user of the principal, the user is created at the first login, and its display name,
+// groups and the role bindings granted by the group mappings are updated at every login
+func (p *Provisioner) Provision(principal *Principal, mappings []GroupMapping) (*mgmtv1.User, error) {
+	user, err := p.ensureUser(principal)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.Spec.Active {
+		return user, nil
+	}
+
+	return p.Sync(user, principal, mappings)
+}
+
+// Sync updates the groups of the active user and the role bindings granted to them by the group mappings
+func (p *Provisioner) Sync(user *mgmtv1.User, principal *Principal, mappings []GroupMapping) (*mgmtv1.User, error) {
+	// the status is updated here rather than by the user controller so that the new user is able to log in
+	// right away
+	toUpdate := user.DeepCopy()
+	toUpdate.Status.IsActive = toUpdate.Spec.Active
+	toUpdate.Status.Groups = principal.Groups
+	if !reflect.DeepEqual(user.Status, toUpdate.Status) {
+		updated, err := p.userClient.UpdateStatus(toUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update status of user %s: %w", user.Spec.Username, err)
+		}
+		user = updated
+	}
+
+	if err := p.syncBindings(user, principal.Provider, principal.Groups, mappings); err != nil {
+		return nil, fmt.Errorf("failed to sync role bindings of user %s: %w", user.Spec.Username, err)
+	}
+
+	return user, nil
+}
+
+func (p *Provisioner) ensureUser(principal *Principal) (*mgmtv1.User, error) {
+	hash := PrincipalHash(principal.Provider, principal.ID)
+	users, err := p.userCache.List(labels.SelectorFromSet(map[string]string{LabelPrincipalHash: hash}))
+	if err != nil {
+		return nil, err
+	}
+
+	var user *mgmtv1.User
+	for _, u := range users {
+		if u.Spec.AuthProvider == principal.Provider && u.Spec.PrincipalID == principal.ID {
+			user = u
+			break
+		}
+	}
+
+	if user == nil {
+		// the existing users are never linked to the principals by their usernames, which would allow the
+		// identity provider to take over the local users
+		existing, err := p.userCache.GetByIndex(indexeres.UserNameIndex, principal.Username)
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("username %s is already taken by another user", principal.Username)
+		}
+
+		user, err = p.userClient.Create(&mgmtv1.User{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "user-",
+				Labels: map[string]string{
+					LabelAuthProvider:  principal.Provider,
+					LabelPrincipalHash: hash,
+				},
+			},
+			Spec: mgmtv1.UserSpec{
+				Username:     principal.Username,
+				DisplayName:  principal.DisplayName,
+				Active:       true,
+				AuthProvider: principal.Provider,
+				PrincipalID:  principal.ID,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create user %s: %w", principal.Username, err)
+		}
+		logrus.Infof("user %s is provisioned by auth provider %s", user.Spec.Username, principal.Provider)
+	} else if principal.DisplayName != "" && user.Spec.DisplayName != principal.DisplayName {
+		toUpdate := user.DeepCopy()
+		toUpdate.Spec.DisplayName = principal.DisplayName
+		if user, err = p.userClient.Update(toUpdate); err != nil {
+			return nil, fmt.Errorf("failed to update user %s: %w", principal.Username, err)
+		}
+	}
+
+	return user, nil
+}
+
+// syncBindings creates the role template bindings granted to the user and removes the ones no longer granted,
+// only the bindings created by the provider are touched
+func (p *Provisioner) syncBindings(user *mgmtv1.User, provider string, groups []string,
+	mappings []GroupMapping) error {
+	globalRoles, err := p.grCache.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	var defaultRoles []string
+	for _, gr := range globalRoles {
+		if gr.Spec.NewUserDefault {
+			defaultRoles = append(defaultRoles, gr.Name)
+		}
+	}
+	desired := DesiredBindings(groups, mappings, defaultRoles)
+
+	// the client is used instead of the cache to avoid duplicating the bindings created by the last login
+	existing, err := p.rtbClient.List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			tokens.LabelAuthUserId: user.Name,
+			LabelAuthProvider:      provider,
+		}).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rtb := range existing.Items {
+		ref := BindingRef{Kind: rtb.RoleTemplateRef.Kind, Name: rtb.RoleTemplateRef.Name, Namespace: rtb.NamespaceId}
+		if i := slices.Index(desired, ref); i >= 0 {
+			desired = slices.Delete(desired, i, i+1)
+			continue
+		}
+		if err = p.rtbClient.Delete(rtb.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	for _, ref := range desired {
+		if _, err = p.rtbClient.Create(constructRoleTemplateBinding(user, provider, ref)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BindingRef is the role granted to the user, the namespace is empty for the global roles
+type BindingRef struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// DesiredBindings returns the roles granted to the members of the groups by the mappings along with the
+// default roles of the new users
+func DesiredBindings(groups []string, mappings []GroupMapping, defaultRoles []string) []BindingRef {
+	refs := make([]BindingRef, 0)
+	add := func(ref BindingRef) {
+		if !slices.Contains(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+
+	for _, role := range defaultRoles {
+		add(BindingRef{Kind: globalRoleKind, Name: role})
+	}
+	for _, mapping := range mappings {
+		if !slices.Contains(groups, mapping.Group) {
+			continue
+		}
+		for _, role := range mapping.GlobalRoles {
+			add(BindingRef{Kind: globalRoleKind, Name: role})
+		}
+		for _, rt := range mapping.RoleTemplates {
+			add(BindingRef{Kind: roleTemplateKind, Name: rt.RoleTemplate, Namespace: rt.Namespace})
+		}
+	}
+
+	return refs
+}
+
+func constructRoleTemplateBinding(user *mgmtv1.User, provider string, ref BindingRef) *mgmtv1.RoleTemplateBinding {
+	return &mgmtv1.RoleTemplateBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "rtb-",
+			Labels: map[string]string{
+				tokens.LabelAuthUserId: user.Name,
+				LabelAuthProvider:      provider,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(user, mgmtv1.SchemeGroupVersion.WithKind("User")),
+			},
+		},
+		RoleTemplateRef: mgmtv1.RoleTemplateRef{
+			APIGroup: mgmtv1.SchemeGroupVersion.Group,
+			Kind:     ref.Kind,
+			Name:     ref.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "User",
+				Name:     user.Name,
+			},
+		},
+		NamespaceId: ref.Namespace,
+	}
+}
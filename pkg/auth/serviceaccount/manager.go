@@ -0,0 +1,180 @@
+package serviceaccount
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/roletemplatebinding"
+	"github.com/llmos-ai/llmos-operator/pkg/data"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	// DescriptionAnnotation is the description of the API key shown in the token list
+	DescriptionAnnotation = "field.llmos.io/description"
+
+	roleTemplateKind = "RoleTemplate"
+	keyGenerateName  = "llmos-"
+)
+
+var (
+	ErrNotServiceAccount = errors.New("user is not a service account")
+	ErrKeyNotFound       = errors.New("API key of the service account is not found")
+)
+
+// KeyInput is the API key to create for the service account
+type KeyInput struct {
+	Description string              `json:"description,omitempty"`
+	TTLSeconds  int64               `json:"ttlSeconds"`
+	Scopes      []mgmtv1.TokenScope `json:"scopes"`
+}
+
+// Manager manages the API keys of the service accounts on behalf of the admins and the namespace owners
+type Manager struct {
+	tokens      *tokens.Manager
+	tokensCache ctlmgmtv1.TokenCache
+	rtbCache    ctlmgmtv1.RoleTemplateBindingCache
+	groupCache  ctlmgmtv1.GroupCache
+}
+
+func NewManager(scaled *config.Scaled) *Manager {
+	mgmt := scaled.MgmtFactory.Management().V1()
+	return &Manager{
+		tokens:      tokens.NewManager(scaled),
+		tokensCache: mgmt.Token().Cache(),
+		rtbCache:    mgmt.RoleTemplateBinding().Cache(),
+		groupCache:  mgmt.Group().Cache(),
+	}
+}
+
+// ValidateKey requires the API keys of the service accounts to be scoped and to expire within the
+// service-account-key-max-ttl-minutes, so that the keys are rotated regularly
+func ValidateKey(ttlSeconds int64, scopes []mgmtv1.TokenScope) error {
+	if len(scopes) == 0 {
+		return errors.New("scopes of the service account API key are required")
+	}
+	if err := tokens.ValidateScopes(scopes); err != nil {
+		return err
+	}
+
+	maxTTL := int64(settings.ServiceAccountKeyMaxTTLMinutes.GetInt()) * 60
+	if ttlSeconds <= 0 {
+		return errors.New("ttlSeconds of the service account API key is required")
+	}
+	if maxTTL > 0 && ttlSeconds > maxTTL {
+		return fmt.Errorf("ttlSeconds of the service account API key can't be greater than %d", maxTTL)
+	}
+	return nil
+}
+
+// CanManage returns whether the user is allowed to manage the API keys of the service account, which are the admins
+// and the owners of the namespace the service account belongs to
+func (m *Manager) CanManage(user, sa *mgmtv1.User) (bool, error) {
+	if !tokens.IsServiceAccount(sa) {
+		return false, ErrNotServiceAccount
+	}
+	if user.Status.IsAdmin {
+		return true, nil
+	}
+
+	rtbs, err := m.rtbCache.List(labels.Everything())
+	if err != nil {
+		return false, fmt.Errorf("failed to list role template bindings: %w", err)
+	}
+	groups, err := m.groupCache.List(labels.Everything())
+	if err != nil {
+		return false, fmt.Errorf("failed to list groups: %w", err)
+	}
+	groupMap := make(map[string]*mgmtv1.Group, len(groups))
+	for _, group := range groups {
+		groupMap[group.Name] = group
+	}
+	return isNamespaceOwner(rtbs, groupMap, user.Name, sa.Spec.ServiceAccount.Namespace), nil
+}
+
+// isNamespaceOwner returns whether the user is bound to the owner role template of the namespace, either directly or
+// through a group
+func isNamespaceOwner(rtbs []*mgmtv1.RoleTemplateBinding, groups map[string]*mgmtv1.Group, userName,
+	namespace string) bool {
+	for _, rtb := range rtbs {
+		if rtb.NamespaceId != namespace || rtb.RoleTemplateRef.Kind != roleTemplateKind ||
+			rtb.RoleTemplateRef.Name != data.DefaultNsOwner {
+			continue
+		}
+		if bound, _ := roletemplatebinding.BoundUser(rtb, userName, groups); bound {
+			return true
+		}
+	}
+	return false
+}
+
+// ListKeys returns the API keys of the service account
+func (m *Manager) ListKeys(sa *mgmtv1.User) ([]*mgmtv1.Token, error) {
+	return m.tokensCache.List(labels.SelectorFromSet(map[string]string{
+		tokens.LabelAuthUserId: sa.Name,
+	}))
+}
+
+// CreateKey creates the API key of the service account, the token returned holds the key in the form of name:key
+func (m *Manager) CreateKey(sa *mgmtv1.User, input *KeyInput) (*mgmtv1.Token, error) {
+	if !tokens.IsServiceAccount(sa) {
+		return nil, ErrNotServiceAccount
+	}
+	if err := ValidateKey(input.TTLSeconds, input.Scopes); err != nil {
+		return nil, err
+	}
+
+	token := &mgmtv1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: keyGenerateName,
+			Annotations:  map[string]string{},
+		},
+		Spec: mgmtv1.TokenSpec{
+			Scopes: input.Scopes,
+		},
+	}
+	if input.Description != "" {
+		token.Annotations[DescriptionAnnotation] = input.Description
+	}
+
+	token, key, err := m.tokens.NewAPIKeyToken(sa.Name, input.TTLSeconds, token)
+	if err != nil {
+		return nil, err
+	}
+	token.Spec.Token = fmt.Sprintf("%s:%s", token.Name, key)
+	return token, nil
+}
+
+// RotateKey replaces the API key of the service account by a new key with the same description, scopes and TTL,
+// the old key is revoked once the new key is created
+func (m *Manager) RotateKey(sa *mgmtv1.User, tokenName string) (*mgmtv1.Token, error) {
+	old, err := m.tokensCache.Get(tokenName)
+	if err != nil || old.Spec.UserId != sa.Name {
+		return nil, ErrKeyNotFound
+	}
+
+	ttl := old.Spec.TTLSeconds
+	if maxTTL := int64(settings.ServiceAccountKeyMaxTTLMinutes.GetInt()) * 60; maxTTL > 0 && ttl > maxTTL {
+		ttl = maxTTL
+	}
+	token, err := m.CreateKey(sa, &KeyInput{
+		Description: old.Annotations[DescriptionAnnotation],
+		TTLSeconds:  ttl,
+		Scopes:      old.Spec.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.tokens.RevokeToken(old.Name); err != nil {
+		return nil, fmt.Errorf("failed to revoke the rotated API key %s: %w", old.Name, err)
+	}
+	return token, nil
+}
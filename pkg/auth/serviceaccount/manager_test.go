@@ -0,0 +1,80 @@
+package serviceaccount
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/data"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+func TestValidateKey(t *testing.T) {
+	require.NoError(t, settings.ServiceAccountKeyMaxTTLMinutes.Set("60"))
+	t.Cleanup(func() { _ = settings.ServiceAccountKeyMaxTTLMinutes.Set("43200") })
+
+	scopes := []mgmtv1.TokenScope{{Namespaces: []string{"team-a"}, Resources: []string{"modelservices"}}}
+	tests := []struct {
+		name    string
+		ttl     int64
+		scopes  []mgmtv1.TokenScope
+		wantErr bool
+	}{
+		{name: "scoped key within the max ttl", ttl: 3600, scopes: scopes},
+		{name: "unscoped key", ttl: 3600, wantErr: true},
+		{name: "key without expiry", ttl: 0, scopes: scopes, wantErr: true},
+		{name: "key exceeding the max ttl", ttl: 3601, scopes: scopes, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateKey(tc.ttl, tc.scopes)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIsNamespaceOwner(t *testing.T) {
+	newRTB := func(namespace, roleTemplate string, subjects ...rbacv1.Subject) *mgmtv1.RoleTemplateBinding {
+		return &mgmtv1.RoleTemplateBinding{
+			RoleTemplateRef: mgmtv1.RoleTemplateRef{
+				Kind: roleTemplateKind,
+				Name: roleTemplate,
+			},
+			NamespaceId: namespace,
+			Subjects:    subjects,
+		}
+	}
+	alice := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "alice"}
+	bob := rbacv1.Subject{Kind: rbacv1.UserKind, Name: "bob"}
+	owners := rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: mgmtv1.SchemeGroupVersion.Group,
+		Name: "team-c-owners"}
+	groups := map[string]*mgmtv1.Group{
+		"team-c-owners": {
+			ObjectMeta: metav1.ObjectMeta{Name: "team-c-owners"},
+			Spec:       mgmtv1.GroupSpec{Members: []string{"carol"}},
+		},
+	}
+
+	rtbs := []*mgmtv1.RoleTemplateBinding{
+		newRTB("team-a", data.DefaultNsOwner, alice),
+		newRTB("team-a", data.DefaultNsReadOnly, bob),
+		newRTB("team-b", data.DefaultNsOwner, bob),
+		newRTB("team-c", data.DefaultNsOwner, owners),
+	}
+
+	assert.True(t, isNamespaceOwner(rtbs, groups, "alice", "team-a"))
+	assert.False(t, isNamespaceOwner(rtbs, groups, "bob", "team-a"))
+	assert.True(t, isNamespaceOwner(rtbs, groups, "bob", "team-b"))
+	assert.False(t, isNamespaceOwner(rtbs, groups, "alice", "team-b"))
+	assert.True(t, isNamespaceOwner(rtbs, groups, "carol", "team-c"))
+	assert.False(t, isNamespaceOwner(rtbs, groups, "alice", "team-c"))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
 	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
@@ -48,15 +49,28 @@ func NewManager(scaled *config.Scaled) *Manager {
 	}
 }
 
-func (m *Manager) NewLoginToken(userId string, ttl int64) (*mgmtv1.Token, string, error) {
-	return m.createToken("token-", userId, nil, sessionToken, ttl)
+// NewLoginToken creates the session token of the user authenticated by the auth provider, the client of the login
+// request is recorded so that the users can tell their sessions apart
+func (m *Manager) NewLoginToken(userId, authProvider string, ttl int64, req *http.Request) (*mgmtv1.Token, string,
+	error) {
+	token := &mgmtv1.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "token-",
+			Annotations:  map[string]string{},
+		},
+		Spec: mgmtv1.TokenSpec{
+			ClientIP:  ClientIP(req),
+			UserAgent: req.UserAgent(),
+		},
+	}
+	return m.createToken("token-", userId, authProvider, token, sessionToken, ttl)
 }
 
 func (m *Manager) NewAPIKeyToken(userId string, ttl int64, token *mgmtv1.Token) (*mgmtv1.Token, string, error) {
-	return m.createToken("llmos-", userId, token, apiKeyToken, ttl)
+	return m.createToken("llmos-", userId, LocalProviderName, token, apiKeyToken, ttl)
 }
 
-func (m *Manager) createToken(generateName, userId string, token *mgmtv1.Token, kind tokenKind,
+func (m *Manager) createToken(generateName, userId, authProvider string, token *mgmtv1.Token, kind tokenKind,
 	ttl int64) (*mgmtv1.Token, string, error) {
 	key, err := utils.GenerateToken()
 	if err != nil {
@@ -78,11 +92,14 @@ func (m *Manager) createToken(generateName, userId string, token *mgmtv1.Token,
 		toCreate.Labels[LabelAuthUserId] = userId
 		toCreate.Labels[LabelAuthTokenKind] = string(kind)
 		toCreate.Spec = mgmtv1.TokenSpec{
-			AuthProvider: LocalProviderName,
+			AuthProvider: authProvider,
 			Expired:      ttl != 0,
 			UserId:       userId,
 			TTLSeconds:   ttl,
 			Token:        hashedToken,
+			Scopes:       token.Spec.Scopes,
+			ClientIP:     token.Spec.ClientIP,
+			UserAgent:    token.Spec.UserAgent,
 		}
 	} else {
 		toCreate = &mgmtv1.Token{
@@ -95,7 +112,7 @@ func (m *Manager) createToken(generateName, userId string, token *mgmtv1.Token,
 				Annotations: map[string]string{},
 			},
 			Spec: mgmtv1.TokenSpec{
-				AuthProvider: LocalProviderName,
+				AuthProvider: authProvider,
 				Expired:      ttl != 0,
 				UserId:       userId,
 				TTLSeconds:   ttl,
@@ -141,6 +158,42 @@ func (m *Manager) Logout(req *http.Request, rw http.ResponseWriter) error {
 	return nil
 }
 
+// RevokeToken deletes the token so that it can't be used anymore
+func (m *Manager) RevokeToken(tokenName string) error {
+	return m.deleteTokenByName(tokenName)
+}
+
+// RevokeUserSessions deletes the session tokens of the user except the token named except, the API keys are kept
+func (m *Manager) RevokeUserSessions(userId, except string) error {
+	return m.deleteUserTokens(userId, sessionToken, except)
+}
+
+// RevokeUserTokens deletes all the session tokens and API keys of the user
+func (m *Manager) RevokeUserTokens(userId string) error {
+	return m.deleteUserTokens(userId, "", "")
+}
+
+func (m *Manager) deleteUserTokens(userId string, kind tokenKind, except string) error {
+	selector := labels.Set{LabelAuthUserId: userId}
+	if kind != "" {
+		selector[LabelAuthTokenKind] = string(kind)
+	}
+	tokenList, err := m.tokensClient.List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return fmt.Errorf("failed to list tokens of user %s, err %s", userId, err.Error())
+	}
+
+	for _, token := range tokenList.Items {
+		if token.Name == except {
+			continue
+		}
+		if err = m.deleteTokenByName(token.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *Manager) deleteTokenByName(tokenName string) error {
 	err := m.tokensClient.Delete(tokenName, &metav1.DeleteOptions{})
 	if err != nil {
@@ -0,0 +1,165 @@
+package tokens
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	// maxLoginDelay caps the progressive delay between the failed login attempts
+	maxLoginDelay = 30 * time.Second
+)
+
+// ValidatePassword checks the password against the length and complexity policies
+func ValidatePassword(password string) error {
+	if minLength := settings.PasswordMinLength.GetInt(); len([]rune(password)) < minLength {
+		return fmt.Errorf("password must be at least %d characters long", minLength)
+	}
+
+	if settings.PasswordRequireComplexity.Get() != constant.TrueStr {
+		return nil
+	}
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		case unicode.IsPunct(c) || unicode.IsSymbol(c):
+			hasSymbol = true
+		}
+	}
+	if !hasUpper || !hasLower || !hasDigit || !hasSymbol {
+		return fmt.Errorf("password must contain upper and lower case letters, digits and symbols")
+	}
+	return nil
+}
+
+// CheckPasswordHistory returns an error if the password matches the current or the previous password hashes
+// kept by the password history policy
+func CheckPasswordHistory(password, currentHash string, history []string) error {
+	count := settings.PasswordHistoryCount.GetInt()
+	if count <= 0 {
+		return nil
+	}
+
+	hashes := append([]string{currentHash}, history...)
+	for i := 0; i < len(hashes) && i < count; i++ {
+		if hashes[i] != "" && CheckPasswordHash(hashes[i], password) {
+			return fmt.Errorf("password must not be one of the last %d passwords", count)
+		}
+	}
+	return nil
+}
+
+// PasswordHistory returns the previous password hashes to keep after the current password is changed
+func PasswordHistory(currentHash string, history []string) []string {
+	// the current password is checked along with the history, so one less password is kept
+	count := settings.PasswordHistoryCount.GetInt() - 1
+	if count <= 0 || currentHash == "" {
+		return nil
+	}
+
+	result := append([]string{currentHash}, history...)
+	if len(result) > count {
+		result = result[:count]
+	}
+	return result
+}
+
+// IsLocalUser returns whether the user is authenticated by the local password
+func IsLocalUser(user *mgmtv1.User) bool {
+	if IsServiceAccount(user) {
+		return false
+	}
+	return user.Spec.AuthProvider == "" || user.Spec.AuthProvider == LocalProviderName
+}
+
+// IsServiceAccount returns whether the user is a non-human service account authenticated by the API keys only
+func IsServiceAccount(user *mgmtv1.User) bool {
+	return user.Spec.ServiceAccount != nil
+}
+
+// PasswordChangeRequired returns whether the local user must change the password before using the API, either
+// it is required explicitly or the password is older than the max age
+func PasswordChangeRequired(user *mgmtv1.User) bool {
+	if !IsLocalUser(user) {
+		return false
+	}
+	if user.Spec.MustChangePassword {
+		return true
+	}
+
+	maxAgeDays := settings.PasswordMaxAgeDays.GetInt()
+	if maxAgeDays <= 0 || user.Spec.PasswordChangedTime == nil {
+		return false
+	}
+	return time.Since(user.Spec.PasswordChangedTime.Time) > time.Duration(maxAgeDays)*24*time.Hour
+}
+
+// LoginRetryAfter returns the time the user is allowed to retry the login, which is either the end of the lockout
+// or the progressive delay after the last failed attempt, the zero time is returned if the login is allowed
+func LoginRetryAfter(user *mgmtv1.User, now time.Time) time.Time {
+	status := user.Status
+	if status.LockedUntil != nil && now.Before(status.LockedUntil.Time) {
+		return status.LockedUntil.Time
+	}
+	if status.FailedLoginAttempts == 0 || status.LastFailedLoginTime == nil {
+		return time.Time{}
+	}
+
+	retryAfter := status.LastFailedLoginTime.Add(LoginDelay(status.FailedLoginAttempts))
+	if now.Before(retryAfter) {
+		return retryAfter
+	}
+	return time.Time{}
+}
+
+// LoginDelay returns the delay after the failed attempts, which doubles from 1 second up to 30 seconds
+func LoginDelay(failedAttempts int) time.Duration {
+	if failedAttempts <= 0 {
+		return 0
+	}
+	// 2^5 seconds exceeds the max delay already
+	if failedAttempts > 5 {
+		return maxLoginDelay
+	}
+	return min(time.Duration(1<<(failedAttempts-1))*time.Second, maxLoginDelay)
+}
+
+// RecordFailedLogin updates the status of the user after the failed login attempt, the user is locked out once the
+// attempts reach the max failed attempts
+func RecordFailedLogin(user *mgmtv1.User, now time.Time) *mgmtv1.User {
+	toUpdate := user.DeepCopy()
+	toUpdate.Status.FailedLoginAttempts++
+	toUpdate.Status.LastFailedLoginTime = &metav1.Time{Time: now}
+
+	maxAttempts := settings.AuthLoginMaxFailedAttempts.GetInt()
+	if maxAttempts > 0 && toUpdate.Status.FailedLoginAttempts >= maxAttempts {
+		lockout := time.Duration(settings.AuthLoginLockoutMinutes.GetInt()) * time.Minute
+		toUpdate.Status.LockedUntil = &metav1.Time{Time: now.Add(lockout)}
+		// the attempts start over after the lockout
+		toUpdate.Status.FailedLoginAttempts = 0
+		toUpdate.Status.LastFailedLoginTime = nil
+	}
+	return toUpdate
+}
+
+// ResetFailedLogins clears the failed login attempts and the lockout of the user
+func ResetFailedLogins(user *mgmtv1.User) *mgmtv1.User {
+	toUpdate := user.DeepCopy()
+	toUpdate.Status.FailedLoginAttempts = 0
+	toUpdate.Status.LastFailedLoginTime = nil
+	toUpdate.Status.LockedUntil = nil
+	return toUpdate
+}
@@ -0,0 +1,134 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+func setSetting(t *testing.T, setting settings.Setting, value string) {
+	old := setting.Get()
+	require.NoError(t, setting.Set(value))
+	t.Cleanup(func() { _ = setting.Set(old) })
+}
+
+func TestValidatePassword(t *testing.T) {
+	setSetting(t, settings.PasswordMinLength, "8")
+
+	setSetting(t, settings.PasswordRequireComplexity, "false")
+	assert.Error(t, ValidatePassword("short"))
+	assert.NoError(t, ValidatePassword("password"))
+
+	setSetting(t, settings.PasswordRequireComplexity, "true")
+	assert.Error(t, ValidatePassword("password"))
+	assert.Error(t, ValidatePassword("Password1"))
+	assert.NoError(t, ValidatePassword("Password1!"))
+}
+
+func TestPasswordHistory(t *testing.T) {
+	hash := func(password string) string {
+		h, err := HashPassword(password)
+		require.NoError(t, err)
+		return h
+	}
+	current := hash("current")
+	history := []string{hash("previous"), hash("oldest")}
+
+	setSetting(t, settings.PasswordHistoryCount, "0")
+	assert.NoError(t, CheckPasswordHistory("current", current, history))
+	assert.Nil(t, PasswordHistory(current, history))
+
+	setSetting(t, settings.PasswordHistoryCount, "2")
+	assert.Error(t, CheckPasswordHistory("current", current, history))
+	assert.Error(t, CheckPasswordHistory("previous", current, history))
+	assert.NoError(t, CheckPasswordHistory("oldest", current, history))
+	assert.Equal(t, []string{current}, PasswordHistory(current, history))
+
+	setSetting(t, settings.PasswordHistoryCount, "5")
+	assert.Equal(t, append([]string{current}, history...), PasswordHistory(current, history))
+}
+
+func TestLoginDelay(t *testing.T) {
+	assert.Equal(t, time.Duration(0), LoginDelay(0))
+	assert.Equal(t, time.Second, LoginDelay(1))
+	assert.Equal(t, 4*time.Second, LoginDelay(3))
+	assert.Equal(t, 16*time.Second, LoginDelay(5))
+	assert.Equal(t, maxLoginDelay, LoginDelay(6))
+	assert.Equal(t, maxLoginDelay, LoginDelay(100))
+}
+
+func TestFailedLogins(t *testing.T) {
+	setSetting(t, settings.AuthLoginMaxFailedAttempts, "3")
+	setSetting(t, settings.AuthLoginLockoutMinutes, "15")
+
+	now := time.Now()
+	user := &mgmtv1.User{}
+	assert.True(t, LoginRetryAfter(user, now).IsZero())
+
+	user = RecordFailedLogin(user, now)
+	assert.Equal(t, 1, user.Status.FailedLoginAttempts)
+	assert.Equal(t, now.Add(time.Second), LoginRetryAfter(user, now))
+	assert.True(t, LoginRetryAfter(user, now.Add(time.Second)).IsZero())
+
+	user = RecordFailedLogin(user, now.Add(time.Second))
+	assert.Equal(t, 2, user.Status.FailedLoginAttempts)
+	assert.Equal(t, now.Add(3*time.Second), LoginRetryAfter(user, now.Add(time.Second)))
+
+	// the third failed attempt locks out the user
+	user = RecordFailedLogin(user, now.Add(3*time.Second))
+	assert.Equal(t, 0, user.Status.FailedLoginAttempts)
+	require.NotNil(t, user.Status.LockedUntil)
+	lockedUntil := now.Add(3*time.Second + 15*time.Minute)
+	assert.Equal(t, lockedUntil, LoginRetryAfter(user, now.Add(time.Minute)))
+	assert.True(t, LoginRetryAfter(user, lockedUntil).IsZero())
+
+	user = ResetFailedLogins(user)
+	assert.Equal(t, mgmtv1.UserStatus{}, user.Status)
+}
+
+func TestPasswordChangeRequired(t *testing.T) {
+	setSetting(t, settings.PasswordMaxAgeDays, "90")
+
+	recent := metav1.NewTime(time.Now().Add(-24 * time.Hour))
+	expired := metav1.NewTime(time.Now().Add(-91 * 24 * time.Hour))
+	tests := []struct {
+		name     string
+		spec     mgmtv1.UserSpec
+		expected bool
+	}{
+		{
+			name: "recently changed",
+			spec: mgmtv1.UserSpec{PasswordChangedTime: &recent},
+		},
+		{
+			name: "unknown changed time",
+			spec: mgmtv1.UserSpec{},
+		},
+		{
+			name:     "expired",
+			spec:     mgmtv1.UserSpec{PasswordChangedTime: &expired},
+			expected: true,
+		},
+		{
+			name:     "must change",
+			spec:     mgmtv1.UserSpec{PasswordChangedTime: &recent, MustChangePassword: true},
+			expected: true,
+		},
+		{
+			name: "external user",
+			spec: mgmtv1.UserSpec{AuthProvider: "ldap", MustChangePassword: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, PasswordChangeRequired(&mgmtv1.User{Spec: tc.spec}))
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package tokens
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/steve/pkg/attributes"
+	steveschema "github.com/rancher/steve/pkg/schema"
+	"github.com/rancher/steve/pkg/schema/converter"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+)
+
+const (
+	steveAPIPrefix = "/v1/"
+	actionQuery    = "action"
+
+	// maxScopedBodySize limits the request body read to find the namespace of the created object
+	maxScopedBodySize = 10 << 20
+)
+
+var (
+	ErrUnsupportedScopedRequest = errors.New("the request is not allowed for the scoped API keys")
+
+	requestInfoFactory = &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("api", "apis"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	}
+)
+
+// RequestAttributes are the attributes of the request checked against the scopes of the API keys
+type RequestAttributes struct {
+	Verb      string
+	Namespace string
+	// Resource is the plural resource name with the subresource if any, e.g., services/proxy
+	Resource string
+	// Name is the name of the object if the request is for a single object
+	Name string
+}
+
+// GetRequestAttributes returns the attributes of the steve API requests under /v1 and the Kubernetes API requests
+// under /api and /apis, ErrUnsupportedScopedRequest is returned for the other requests
+func GetRequestAttributes(req *http.Request) (*RequestAttributes, error) {
+	if strings.HasPrefix(req.URL.Path, steveAPIPrefix) {
+		return steveRequestAttributes(req)
+	}
+
+	info, err := requestInfoFactory.NewRequestInfo(req)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsResourceRequest {
+		if isDiscoveryPath(info.Path) {
+			// the discovery requests are allowed by the scopes of all resources
+			return &RequestAttributes{Verb: info.Verb}, nil
+		}
+		return nil, ErrUnsupportedScopedRequest
+	}
+
+	resource := info.Resource
+	if info.Subresource != "" {
+		resource = resource + "/" + info.Subresource
+	}
+	return &RequestAttributes{
+		Verb:      info.Verb,
+		Namespace: info.Namespace,
+		Resource:  resource,
+		Name:      info.Name,
+	}, nil
+}
+
+func isDiscoveryPath(path string) bool {
+	return path == "/api" || path == "/apis" || strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/apis/")
+}
+
+// steveRequestAttributes parses the steve API paths /v1/{type}, /v1/{type}/{namespace or name} and
+// /v1/{type}/{namespace}/{name}, the resource actions are parsed as the verbs of their names
+func steveRequestAttributes(req *http.Request) (*RequestAttributes, error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, steveAPIPrefix), "/"), "/")
+	if parts[0] == "" || len(parts) > 3 {
+		return nil, ErrUnsupportedScopedRequest
+	}
+
+	action := req.URL.Query().Get(actionQuery)
+	resource, namespaced := steveTypeToResource(parts[0])
+	attrs := &RequestAttributes{
+		Resource: resource,
+	}
+
+	hasName := len(parts) == 3
+	switch len(parts) {
+	case 2:
+		// the second part is the namespace of the list and create requests of the namespaced resource, and the name
+		// of the object otherwise
+		if namespaced && action == "" && (req.Method == http.MethodGet || req.Method == http.MethodPost) {
+			attrs.Namespace = parts[1]
+		} else {
+			hasName = true
+			attrs.Name = parts[1]
+		}
+	case 3:
+		attrs.Namespace = parts[1]
+		attrs.Name = parts[2]
+	}
+
+	switch {
+	case action != "":
+		attrs.Verb = action
+	case req.Method == http.MethodGet && hasName:
+		attrs.Verb = "get"
+	case req.Method == http.MethodGet:
+		attrs.Verb = "list"
+	case req.Method == http.MethodPost:
+		attrs.Verb = "create"
+	case req.Method == http.MethodPut:
+		attrs.Verb = "update"
+	case req.Method == http.MethodPatch:
+		attrs.Verb = "patch"
+	case req.Method == http.MethodDelete:
+		attrs.Verb = "delete"
+	default:
+		return nil, ErrUnsupportedScopedRequest
+	}
+
+	if attrs.Verb == "create" && attrs.Namespace == "" {
+		namespace, err := namespaceFromBody(req)
+		if err != nil {
+			return nil, err
+		}
+		attrs.Namespace = namespace
+	}
+	return attrs, nil
+}
+
+// steveTypeToResource converts the steve type to the plural resource name, the steve links use the plural types,
+// e.g., ml.llmos.ai.modelservices, and the singular schema ids, e.g., ml.llmos.ai.modelservice, are accepted as well.
+// The resources are looked up from the steve schemas, the names are only guessed for the types without the schemas
+func steveTypeToResource(steveType string) (string, bool) {
+	if resources := schemaResources.Load(); resources != nil {
+		if resource, ok := (*resources)[strings.ToLower(steveType)]; ok {
+			return resource.name, resource.namespaced
+		}
+	}
+
+	// the types without the schemas are guessed as the namespaced resources
+	name := strings.ToLower(steveType[strings.LastIndex(steveType, ".")+1:])
+	if strings.HasSuffix(name, "s") {
+		return name, true
+	}
+	plural, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Kind: name})
+	return plural.Resource, true
+}
+
+// schemaResource is the resource name of the steve schema and whether the resource is namespaced
+type schemaResource struct {
+	name       string
+	namespaced bool
+}
+
+// schemaResources maps the steve schema ids and plural names to the resources of the schemas
+var schemaResources atomic.Pointer[map[string]schemaResource]
+
+type schemaCollection interface {
+	IDs() []string
+	Schema(id string) *types.APISchema
+}
+
+// WatchSchemaResources indexes the resource names of the steve schemas, the index is rebuilt when the schemas change,
+// e.g., when the CRDs are installed
+func WatchSchemaResources(ctx context.Context, factory steveschema.Factory) {
+	collection, ok := factory.(schemaCollection)
+	if !ok {
+		logrus.Warnf("steve schema factory %T can't be indexed, the scoped resources are guessed", factory)
+		return
+	}
+
+	// the schemas are read outside the change callback, which is called with the collection locked
+	factory.OnChange(ctx, func() { go indexSchemaResources(collection) })
+	indexSchemaResources(collection)
+}
+
+func indexSchemaResources(collection schemaCollection) {
+	var schemas []*types.APISchema
+	for _, id := range collection.IDs() {
+		if s := collection.Schema(id); s != nil {
+			schemas = append(schemas, s)
+		}
+	}
+	setSchemaResources(schemas)
+}
+
+func setSchemaResources(schemas []*types.APISchema) {
+	resources := make(map[string]schemaResource, 2*len(schemas))
+	for _, s := range schemas {
+		gvr := attributes.GVR(s)
+		if gvr.Resource == "" {
+			continue
+		}
+		resource := schemaResource{name: gvr.Resource, namespaced: attributes.Namespaced(s)}
+		resources[strings.ToLower(s.ID)] = resource
+		resources[strings.ToLower(converter.GVRToPluralName(gvr))] = resource
+	}
+	schemaResources.Store(&resources)
+}
+
+// namespaceFromBody returns the namespace of the object created without the namespace in the path, the body is
+// restored for the following handlers
+func namespaceFromBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxScopedBodySize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxScopedBodySize {
+		// the body is kept for the following handlers even if it's too large to parse
+		req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+		return "", fmt.Errorf("request body of the scoped API key exceeds %d bytes", maxScopedBodySize)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	obj := struct {
+		Metadata struct {
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}{}
+	// the invalid bodies are left to the API server to reject, only the namespaced scopes are denied here
+	if err = json.Unmarshal(body, &obj); err != nil {
+		return "", nil
+	}
+	return obj.Metadata.Namespace, nil
+}
+
+// ScopesAllow returns whether the request is allowed by any of the scopes, all requests are allowed if there are
+// no scopes
+func ScopesAllow(scopes []mgmtv1.TokenScope, attrs *RequestAttributes) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if scopeAllows(scope, attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeAllows(scope mgmtv1.TokenScope, attrs *RequestAttributes) bool {
+	if len(scope.Namespaces) > 0 && !slices.Contains(scope.Namespaces, attrs.Namespace) {
+		return false
+	}
+	return matchesRule(scope.Resources, attrs.Resource) && matchesRule(scope.Verbs, attrs.Verb)
+}
+
+// matchesRule returns whether the value matches any of the rules, the empty rules and "*" match all values, and
+// "services/*" matches all subresources of services
+func matchesRule(rules []string, value string) bool {
+	if len(rules) == 0 {
+		return true
+	}
+	for _, rule := range rules {
+		if rule == "*" || rule == value {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(rule, "/*"); ok && strings.HasPrefix(value, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateScopes checks the scopes of the API key
+func ValidateScopes(scopes []mgmtv1.TokenScope) error {
+	for i, scope := range scopes {
+		for _, values := range [][]string{scope.Namespaces, scope.Resources, scope.Verbs} {
+			for _, v := range values {
+				if strings.TrimSpace(v) == "" {
+					return fmt.Errorf("scope %d of the API key contains an empty value", i)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package tokens
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/steve/pkg/attributes"
+	wschemas "github.com/rancher/wrangler/v3/pkg/schemas"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+)
+
+func TestGetRequestAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		expected *RequestAttributes
+		wantErr  bool
+	}{
+		{
+			name:     "steve list in namespace",
+			method:   "GET",
+			target:   "/v1/ml.llmos.ai.modelservice/team-a",
+			expected: &RequestAttributes{Verb: "list", Namespace: "team-a", Resource: "modelservices"},
+		},
+		{
+			name:     "steve list in all namespaces",
+			method:   "GET",
+			target:   "/v1/ml.llmos.ai.notebook",
+			expected: &RequestAttributes{Verb: "list", Resource: "notebooks"},
+		},
+		{
+			name:     "steve plural type",
+			method:   "GET",
+			target:   "/v1/ml.llmos.ai.modelservices/team-a/qwen",
+			expected: &RequestAttributes{Verb: "get", Namespace: "team-a", Resource: "modelservices", Name: "qwen"},
+		},
+		{
+			name:     "steve get",
+			method:   "GET",
+			target:   "/v1/ml.llmos.ai.notebook/team-a/jupyter",
+			expected: &RequestAttributes{Verb: "get", Namespace: "team-a", Resource: "notebooks", Name: "jupyter"},
+		},
+		{
+			name:     "steve delete",
+			method:   "DELETE",
+			target:   "/v1/ml.llmos.ai.notebook/team-a/jupyter",
+			expected: &RequestAttributes{Verb: "delete", Namespace: "team-a", Resource: "notebooks", Name: "jupyter"},
+		},
+		{
+			name:     "steve delete cluster-scoped",
+			method:   "DELETE",
+			target:   "/v1/management.llmos.ai.user/user-abc",
+			expected: &RequestAttributes{Verb: "delete", Resource: "users", Name: "user-abc"},
+		},
+		{
+			name:     "steve action",
+			method:   "POST",
+			target:   "/v1/ml.llmos.ai.datasetversion/team-a/v1?action=upload",
+			expected: &RequestAttributes{Verb: "upload", Namespace: "team-a", Resource: "datasetversions", Name: "v1"},
+		},
+		{
+			name:     "steve create with namespace in body",
+			method:   "POST",
+			target:   "/v1/ml.llmos.ai.notebook",
+			body:     `{"metadata":{"name":"jupyter","namespace":"team-b"}}`,
+			expected: &RequestAttributes{Verb: "create", Namespace: "team-b", Resource: "notebooks"},
+		},
+		{
+			name:     "steve core type",
+			method:   "GET",
+			target:   "/v1/pod/team-a/pod-1",
+			expected: &RequestAttributes{Verb: "get", Namespace: "team-a", Resource: "pods", Name: "pod-1"},
+		},
+		{
+			name:   "kubernetes service proxy",
+			method: "POST",
+			target: "/api/v1/namespaces/team-a/services/modelservice-qwen:http/proxy/v1/chat/completions",
+			expected: &RequestAttributes{Verb: "create", Namespace: "team-a", Resource: "services/proxy",
+				Name: "modelservice-qwen:http"},
+		},
+		{
+			name:     "kubernetes watch",
+			method:   "GET",
+			target:   "/apis/ml.llmos.ai/v1/namespaces/team-a/modelservices?watch=true",
+			expected: &RequestAttributes{Verb: "watch", Namespace: "team-a", Resource: "modelservices"},
+		},
+		{
+			name:     "kubernetes discovery",
+			method:   "GET",
+			target:   "/apis",
+			expected: &RequestAttributes{Verb: "get"},
+		},
+		{
+			name:    "unsupported path",
+			method:  "GET",
+			target:  "/v1-public/ui",
+			wantErr: true,
+		},
+		{
+			name:    "steve path too long",
+			method:  "GET",
+			target:  "/v1/ml.llmos.ai.notebook/team-a/jupyter/extra",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			attrs, err := GetRequestAttributes(req)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, attrs)
+
+			// the body is kept for the following handlers
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.body, string(body))
+		})
+	}
+}
+
+func newSchema(id string, gvr schema.GroupVersionResource, namespaced bool) *types.APISchema {
+	s := &types.APISchema{Schema: &wschemas.Schema{ID: id}}
+	attributes.SetGVR(s, gvr)
+	attributes.SetNamespaced(s, namespaced)
+	return s
+}
+
+func TestSteveTypeToResource(t *testing.T) {
+	setSchemaResources([]*types.APISchema{
+		newSchema("networking.k8s.io.ingress",
+			schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, true),
+		newSchema("componentstatus", schema.GroupVersionResource{Version: "v1", Resource: "componentstatuses"},
+			false),
+		newSchema("ml.llmos.ai.modelservice",
+			schema.GroupVersionResource{Group: "ml.llmos.ai", Version: "v1", Resource: "modelservices"}, true),
+	})
+	t.Cleanup(func() { schemaResources.Store(nil) })
+
+	tests := map[string]schemaResource{
+		"networking.k8s.io.ingress":   {name: "ingresses", namespaced: true},
+		"networking.k8s.io.ingresses": {name: "ingresses", namespaced: true},
+		"componentstatus":             {name: "componentstatuses"},
+		"componentstatuses":           {name: "componentstatuses"},
+		"ml.llmos.ai.modelservice":    {name: "modelservices", namespaced: true},
+		"ml.llmos.ai.modelservices":   {name: "modelservices", namespaced: true},
+		// the types without the schemas are guessed
+		"schemas": {name: "schemas", namespaced: true},
+		"count":   {name: "counts", namespaced: true},
+	}
+	for steveType, expected := range tests {
+		resource, namespaced := steveTypeToResource(steveType)
+		assert.Equal(t, expected, schemaResource{name: resource, namespaced: namespaced}, steveType)
+	}
+}
+
+func TestGetRequestAttributesByScope(t *testing.T) {
+	setSchemaResources([]*types.APISchema{
+		newSchema("management.llmos.ai.user",
+			schema.GroupVersionResource{Group: "management.llmos.ai", Version: "v1", Resource: "users"}, false),
+		newSchema("ml.llmos.ai.notebook",
+			schema.GroupVersionResource{Group: "ml.llmos.ai", Version: "v1", Resource: "notebooks"}, true),
+	})
+	t.Cleanup(func() { schemaResources.Store(nil) })
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		expected *RequestAttributes
+	}{
+		{
+			name:     "cluster-scoped get",
+			method:   "GET",
+			target:   "/v1/management.llmos.ai.users/admin",
+			expected: &RequestAttributes{Verb: "get", Resource: "users", Name: "admin"},
+		},
+		{
+			name:     "cluster-scoped list",
+			method:   "GET",
+			target:   "/v1/management.llmos.ai.users",
+			expected: &RequestAttributes{Verb: "list", Resource: "users"},
+		},
+		{
+			name:     "namespaced list",
+			method:   "GET",
+			target:   "/v1/ml.llmos.ai.notebooks/admin",
+			expected: &RequestAttributes{Verb: "list", Namespace: "admin", Resource: "notebooks"},
+		},
+		{
+			name:     "namespaced get",
+			method:   "GET",
+			target:   "/v1/ml.llmos.ai.notebooks/admin/jupyter",
+			expected: &RequestAttributes{Verb: "get", Namespace: "admin", Resource: "notebooks", Name: "jupyter"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs, err := GetRequestAttributes(httptest.NewRequest(tc.method, tc.target, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, attrs)
+		})
+	}
+}
+
+func TestScopesAllow(t *testing.T) {
+	inference := mgmtv1.TokenScope{
+		Namespaces: []string{"team-a"},
+		Resources:  []string{"services/proxy"},
+		Verbs:      []string{"get", "create"},
+	}
+	readOnly := mgmtv1.TokenScope{
+		Namespaces: []string{"team-b"},
+		Verbs:      []string{"get", "list", "watch"},
+	}
+	upload := mgmtv1.TokenScope{
+		Resources: []string{"datasetversions"},
+		Verbs:     []string{"upload"},
+	}
+
+	tests := []struct {
+		name     string
+		scopes   []mgmtv1.TokenScope
+		attrs    RequestAttributes
+		expected bool
+	}{
+		{
+			name:     "unscoped",
+			attrs:    RequestAttributes{Verb: "delete", Namespace: "team-a", Resource: "notebooks"},
+			expected: true,
+		},
+		{
+			name:     "inference allowed",
+			scopes:   []mgmtv1.TokenScope{inference},
+			attrs:    RequestAttributes{Verb: "create", Namespace: "team-a", Resource: "services/proxy"},
+			expected: true,
+		},
+		{
+			name:   "inference in other namespace",
+			scopes: []mgmtv1.TokenScope{inference},
+			attrs:  RequestAttributes{Verb: "create", Namespace: "team-b", Resource: "services/proxy"},
+		},
+		{
+			name:   "subresource not covered by resource",
+			scopes: []mgmtv1.TokenScope{{Resources: []string{"services"}}},
+			attrs:  RequestAttributes{Verb: "create", Namespace: "team-a", Resource: "services/proxy"},
+		},
+		{
+			name:     "subresource wildcard",
+			scopes:   []mgmtv1.TokenScope{{Resources: []string{"services/*"}}},
+			attrs:    RequestAttributes{Verb: "create", Namespace: "team-a", Resource: "services/proxy"},
+			expected: true,
+		},
+		{
+			name:     "read-only get",
+			scopes:   []mgmtv1.TokenScope{inference, readOnly},
+			attrs:    RequestAttributes{Verb: "list", Namespace: "team-b", Resource: "notebooks"},
+			expected: true,
+		},
+		{
+			name:   "read-only delete",
+			scopes: []mgmtv1.TokenScope{inference, readOnly},
+			attrs:  RequestAttributes{Verb: "delete", Namespace: "team-b", Resource: "notebooks"},
+		},
+		{
+			name:   "namespaced scope excludes cluster-scoped resources",
+			scopes: []mgmtv1.TokenScope{readOnly},
+			attrs:  RequestAttributes{Verb: "list", Resource: "users"},
+		},
+		{
+			name:     "upload in any namespace",
+			scopes:   []mgmtv1.TokenScope{upload},
+			attrs:    RequestAttributes{Verb: "upload", Namespace: "team-c", Resource: "datasetversions"},
+			expected: true,
+		},
+		{
+			name:   "upload scope can't remove",
+			scopes: []mgmtv1.TokenScope{upload},
+			attrs:  RequestAttributes{Verb: "remove", Namespace: "team-c", Resource: "datasetversions"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ScopesAllow(tc.scopes, &tc.attrs))
+		})
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	assert.NoError(t, ValidateScopes(nil))
+	assert.NoError(t, ValidateScopes([]mgmtv1.TokenScope{{Namespaces: []string{"team-a"}, Verbs: []string{"get"}}}))
+	assert.Error(t, ValidateScopes([]mgmtv1.TokenScope{{Namespaces: []string{""}}}))
+}
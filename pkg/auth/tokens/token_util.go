@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ import (
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/auth/hashers"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
 )
 
 const (
@@ -21,6 +23,9 @@ const (
 	AuthHeaderName  = "Authorization"
 	AuthValuePrefix = "Bearer"
 	BasicAuthPrefix = "Basic"
+
+	// lastUsedInterval throttles the updates of the last used time of the tokens
+	lastUsedInterval = time.Minute
 )
 
 func SplitTokenParts(tokenID string) (string, string) {
@@ -52,6 +57,107 @@ func IsExpired(token *mgmtv1.Token) bool {
 	return durationElapsed.Seconds() >= ttlDuration.Seconds()
 }
 
+// IsSessionToken returns whether the token is the UI session issued at login
+func IsSessionToken(token *mgmtv1.Token) bool {
+	return token.Labels[LabelAuthTokenKind] == string(sessionToken)
+}
+
+// IsIdle returns whether the session token is not used for longer than the auth-user-session-idle-timeout-minutes,
+// the API keys never become idle
+func IsIdle(token *mgmtv1.Token) bool {
+	timeout := settings.AuthUserSessionIdleTimeoutMinutes.GetInt()
+	if timeout <= 0 || !IsSessionToken(token) {
+		return false
+	}
+
+	lastUsed := token.CreationTimestamp.Time
+	if token.Status.LastUsedAt != nil {
+		lastUsed = token.Status.LastUsedAt.Time
+	}
+	return time.Since(lastUsed) >= time.Duration(timeout)*time.Minute
+}
+
+// ShouldUpdateLastUsed returns whether the last used time of the token is older than the throttling interval
+func ShouldUpdateLastUsed(token *mgmtv1.Token, now time.Time) bool {
+	return token.Status.LastUsedAt == nil || now.Sub(token.Status.LastUsedAt.Time) >= lastUsedInterval
+}
+
+// ClientIP returns the ip of the client of the request, the X-Forwarded-For and X-Real-IP headers are only read if
+// the request comes from one of the trusted proxies, so that the clients can't spoof their IPs
+func ClientIP(req *http.Request) string {
+	remote := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		remote = host
+	}
+
+	trusted := trustedProxies()
+	if !isTrustedProxy(remote, trusted) {
+		return remote
+	}
+
+	// the proxies append the address they received the request from, the right-most address that is not a trusted
+	// proxy is the client, the addresses left of it are set by the client and can't be trusted
+	if forwarded := req.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			if !isTrustedProxy(hop, trusted) || i == 0 {
+				return hop
+			}
+		}
+	}
+	if realIP := strings.TrimSpace(req.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return remote
+}
+
+// trustedProxies parses the networks of the AuthTrustedProxies setting, the single IPs are treated as host networks
+func trustedProxies() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(settings.AuthTrustedProxies.Get(), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				logrus.Warnf("invalid trusted proxy %s", entry)
+				continue
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			entry = fmt.Sprintf("%s/%d", entry, bits)
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			logrus.Warnf("invalid trusted proxy %s: %v", entry, err)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func isTrustedProxy(addr string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // VerifyToken helps to check if the token is valid
 func VerifyToken(token *mgmtv1.Token, tokenName, tokenKey string) (int, error) {
 	invalidAuthTokenErr := errors.New("invalid auth token value")
@@ -68,6 +174,10 @@ func VerifyToken(token *mgmtv1.Token, tokenName, tokenKey string) (int, error) {
 	if IsExpired(token) {
 		return http.StatusUnauthorized, errors.New("token expired")
 	}
+
+	if IsIdle(token) {
+		return http.StatusUnauthorized, errors.New("session expired due to inactivity")
+	}
 	return http.StatusOK, nil
 }
 
@@ -1,13 +1,16 @@
 package tokens
 
 import (
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
 )
 
 func TestVerifyToken(t *testing.T) {
@@ -151,9 +154,117 @@ func TestConvertTokenKeyToHash(t *testing.T) {
 	}
 }
 
+func TestIsIdle(t *testing.T) {
+	newSession := func(kind tokenKind, created, lastUsed time.Duration) *mgmtv1.Token {
+		token := &mgmtv1.Token{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:            map[string]string{LabelAuthTokenKind: string(kind)},
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-created)),
+			},
+		}
+		if lastUsed != 0 {
+			token.Status.LastUsedAt = &metav1.Time{Time: time.Now().Add(-lastUsed)}
+		}
+		return token
+	}
+
+	setSetting(t, settings.AuthUserSessionIdleTimeoutMinutes, "0")
+	require.False(t, IsIdle(newSession(sessionToken, time.Hour, 0)))
+
+	setSetting(t, settings.AuthUserSessionIdleTimeoutMinutes, "30")
+	require.True(t, IsIdle(newSession(sessionToken, time.Hour, 0)))
+	require.False(t, IsIdle(newSession(sessionToken, 10*time.Minute, 0)))
+	require.False(t, IsIdle(newSession(sessionToken, time.Hour, 10*time.Minute)))
+	require.True(t, IsIdle(newSession(sessionToken, 2*time.Hour, time.Hour)))
+	// the API keys never become idle
+	require.False(t, IsIdle(newSession(apiKeyToken, 2*time.Hour, time.Hour)))
+}
+
+func TestShouldUpdateLastUsed(t *testing.T) {
+	now := time.Now()
+	token := &mgmtv1.Token{}
+	require.True(t, ShouldUpdateLastUsed(token, now))
+
+	token.Status.LastUsedAt = &metav1.Time{Time: now.Add(-10 * time.Second)}
+	require.False(t, ShouldUpdateLastUsed(token, now))
+
+	token.Status.LastUsedAt = &metav1.Time{Time: now.Add(-2 * time.Minute)}
+	require.True(t, ShouldUpdateLastUsed(token, now))
+}
+
 func expireToken(token *mgmtv1.Token) *mgmtv1.Token {
 	newToken := token.DeepCopy()
 	newToken.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Second * 10))
 	newToken.Spec.TTLSeconds = 1
 	return newToken
 }
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxies    string
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		expected   string
+	}{
+		{
+			name:       "no trusted proxies ignores the headers",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  "203.0.113.7",
+			realIP:     "203.0.113.8",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "untrusted remote ignores the headers",
+			proxies:    "10.0.0.0/8",
+			remoteAddr: "192.0.2.1:1234",
+			forwarded:  "203.0.113.7",
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "trusted proxy uses the forwarded client",
+			proxies:    "10.0.0.0/8",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  "203.0.113.7",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "spoofed forwarded entries are skipped",
+			proxies:    "10.0.0.1, 10.0.0.2",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  "1.1.1.1, 203.0.113.7, 10.0.0.2",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy uses the real ip",
+			proxies:    "10.0.0.1",
+			remoteAddr: "10.0.0.1:1234",
+			realIP:     "203.0.113.8",
+			expected:   "203.0.113.8",
+		},
+		{
+			name:       "invalid headers fall back to the remote address",
+			proxies:    "10.0.0.1",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  "unknown",
+			realIP:     "invalid",
+			expected:   "10.0.0.1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setSetting(t, settings.AuthTrustedProxies, tc.proxies)
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+			if tc.realIP != "" {
+				req.Header.Set("X-Real-IP", tc.realIP)
+			}
+			assert.Equal(t, tc.expected, ClientIP(req))
+		})
+	}
+}
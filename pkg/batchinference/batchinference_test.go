@@ -0,0 +1,298 @@
+package batchinference
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
+)
+
+type fakeStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func (s *fakeStorage) List(_ context.Context, prefix string, _, _ bool) ([]backend.FileInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var files []backend.FileInfo
+	for p := range s.files {
+		if path.Dir(p) == prefix {
+			files = append(files, backend.FileInfo{Name: path.Base(p), Path: p})
+		}
+	}
+	return files, nil
+}
+
+func (s *fakeStorage) Download(_ context.Context, src string, rw io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[src]
+	if !ok {
+		return fmt.Errorf("%s not found", src)
+	}
+	_, err := rw.Write(data)
+	return err
+}
+
+func (s *fakeStorage) UploadFromReader(_ context.Context, reader io.Reader, dst string, _ int64, _ string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[dst] = data
+	return nil
+}
+
+type fakeReporter struct {
+	progress mlv1.BatchInferenceProgress
+	failures []mlv1.BatchInferenceFailure
+}
+
+func (r *fakeReporter) Report(progress mlv1.BatchInferenceProgress, failures []mlv1.BatchInferenceFailure) error {
+	r.progress = progress
+	r.failures = append(r.failures, failures...)
+	return nil
+}
+
+// newServer echoes the prompt in upper case, the prompt "flaky" fails once with 503 and the prompt "bad" is
+// rejected with 400
+func newServer(t *testing.T, requests map[string]int) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := chatRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		prompt := req.Messages[len(req.Messages)-1].Content
+
+		mu.Lock()
+		requests[prompt]++
+		count := requests[prompt]
+		mu.Unlock()
+
+		switch {
+		case prompt == "bad":
+			http.Error(w, "prompt is too long", http.StatusBadRequest)
+		case prompt == "flaky" && count == 1:
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		default:
+			_, _ = fmt.Fprintf(w, `{"choices": [{"message": {"role": "assistant", "content": %q}}]}`,
+				strings.ToUpper(prompt))
+		}
+	}))
+}
+
+func TestPromptBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		field    string
+		record   map[string]interface{}
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "prompt field",
+			field:    "prompt",
+			record:   map[string]interface{}{"prompt": "hello"},
+			expected: "hello",
+		},
+		{
+			name:     "non-string prompt field",
+			field:    "prompt",
+			record:   map[string]interface{}{"prompt": float64(42)},
+			expected: "42",
+		},
+		{
+			name:    "missing prompt field",
+			field:   "prompt",
+			record:  map[string]interface{}{"text": "hello"},
+			wantErr: true,
+		},
+		{
+			name:     "template",
+			template: "Translate to {{ .lang }}: {{ .text }}",
+			record:   map[string]interface{}{"lang": "French", "text": "hello"},
+			expected: "Translate to French: hello",
+		},
+		{
+			name:     "template with missing field",
+			template: "Translate to {{ .lang }}: {{ .text }}",
+			record:   map[string]interface{}{"text": "hello"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := NewPromptBuilder(tc.template, tc.field)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			prompt, err := b.Build(tc.record)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got prompt %q", prompt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if prompt != tc.expected {
+				t.Errorf("Expected prompt %q, got %q", tc.expected, prompt)
+			}
+		})
+	}
+
+	if _, err := NewPromptBuilder("{{ .text ", ""); err == nil {
+		t.Errorf("Expected an error of the invalid template")
+	}
+}
+
+func TestRun(t *testing.T) {
+	retryInterval = time.Millisecond
+
+	input := filepath.Join(t.TempDir(), "input.jsonl")
+	lines := []string{
+		`{"text": "a"}`,
+		`{"text": "flaky"}`,
+		``,
+		`{"text": "bad"}`,
+		`not json`,
+		`{"text": "b"}`,
+		`{"text": "c"}`,
+	}
+	if err := os.WriteFile(input, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	requests := map[string]int{}
+	server := newServer(t, requests)
+	defer server.Close()
+
+	storage := &fakeStorage{files: map[string][]byte{
+		// the first shard is written before the restart
+		"out/part-00000.jsonl": []byte(`{"text": "a", "output": "A"}` + "\n" + `{"text": "flaky", "error": "x"}` + "\n"),
+	}}
+	reporter := &fakeReporter{}
+	opts := Options{
+		Endpoint:    server.URL,
+		Model:       "test",
+		InputFile:   input,
+		OutputDir:   "out",
+		PromptField: "text",
+		OutputField: "output",
+		MaxRecords:  5,
+		Concurrency: 2,
+		MaxRetries:  1,
+		ShardSize:   2,
+	}
+	if err := Run(context.Background(), opts, storage, reporter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests["a"] != 0 || requests["flaky"] != 0 {
+		t.Errorf("Expected the completed shard to be skipped, got requests %v", requests)
+	}
+	if requests["bad"] != 1 {
+		t.Errorf("Expected the rejected prompt not to be retried, got %d requests", requests["bad"])
+	}
+	if requests["c"] != 0 {
+		t.Errorf("Expected the records beyond the max records to be skipped, got requests %v", requests)
+	}
+
+	expectedProgress := mlv1.BatchInferenceProgress{Total: 5, Succeeded: 2, Failed: 3}
+	if reporter.progress != expectedProgress {
+		t.Errorf("Expected progress %+v, got %+v", expectedProgress, reporter.progress)
+	}
+	if len(reporter.failures) != 2 || reporter.failures[0].Line != 4 || reporter.failures[1].Line != 5 {
+		t.Errorf("Expected failures of line 4 and 5, got %+v", reporter.failures)
+	}
+
+	expectedShards := map[string][]string{
+		"out/part-00001.jsonl": {`{"error":"request failed with 400 Bad Request: prompt is too long","text":"bad"}`,
+			`{"error":"invalid json object: invalid character 'o' in literal null (expecting 'u')"}`},
+		"out/part-00002.jsonl": {`{"output":"B","text":"b"}`},
+	}
+	for file, expected := range expectedShards {
+		got := strings.Split(strings.TrimSpace(string(storage.files[file])), "\n")
+		if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected shard %s to be %v, got %v", file, expected, got)
+		}
+	}
+}
+
+func TestRunRetry(t *testing.T) {
+	retryInterval = time.Millisecond
+
+	input := filepath.Join(t.TempDir(), "input.jsonl")
+	if err := os.WriteFile(input, []byte(`{"prompt": "flaky"}`), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	requests := map[string]int{}
+	server := newServer(t, requests)
+	defer server.Close()
+
+	storage := &fakeStorage{files: map[string][]byte{}}
+	reporter := &fakeReporter{}
+	opts := Options{
+		Endpoint:    server.URL,
+		Model:       "test",
+		InputFile:   input,
+		OutputDir:   "out",
+		PromptField: "prompt",
+		OutputField: "answer",
+		MaxRetries:  2,
+	}
+	if err := Run(context.Background(), opts, storage, reporter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests["flaky"] != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests["flaky"])
+	}
+	expected := `{"answer":"FLAKY","prompt":"flaky"}`
+	if got := string(bytes.TrimSpace(storage.files["out/part-00000.jsonl"])); got != expected {
+		t.Errorf("Expected output %s, got %s", expected, got)
+	}
+	if reporter.progress.Succeeded != 1 || reporter.progress.Failed != 0 {
+		t.Errorf("Expected 1 succeeded record, got %+v", reporter.progress)
+	}
+}
+
+func TestSetProgress(t *testing.T) {
+	status := &mlv1.BatchInferenceJobStatus{}
+	for i := 0; i < MaxRecentFailures; i++ {
+		status.RecentFailures = append(status.RecentFailures, mlv1.BatchInferenceFailure{Line: int64(i + 1)})
+	}
+
+	SetProgress(status, mlv1.BatchInferenceProgress{Total: 3, Succeeded: 1, Failed: 1},
+		[]mlv1.BatchInferenceFailure{{Line: 100, Error: "failed"}})
+	if status.Progress.Percentage != "66.67" {
+		t.Errorf("Expected percentage 66.67, got %s", status.Progress.Percentage)
+	}
+	if len(status.RecentFailures) != MaxRecentFailures {
+		t.Fatalf("Expected %d recent failures, got %d", MaxRecentFailures, len(status.RecentFailures))
+	}
+	if status.RecentFailures[0].Line != 2 || status.RecentFailures[MaxRecentFailures-1].Line != 100 {
+		t.Errorf("Expected the oldest failure to be dropped, got %+v", status.RecentFailures)
+	}
+}
@@ -0,0 +1,143 @@
+package batchinference
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	chatCompletionsPath = "/v1/chat/completions"
+	modelsPath          = "/v1/models"
+)
+
+// client is the OpenAI compatible client of the model service
+type client struct {
+	endpoint    string
+	model       string
+	maxTokens   int
+	temperature *float64
+	httpClient  *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// statusError is returned if the model service responds with an unexpected status code
+type statusError struct {
+	code    int
+	status  string
+	message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("request failed with %s: %s", e.status, e.message)
+}
+
+// isRetryable returns false if the request is rejected by the model service and sending it again doesn't help,
+// e.g., the prompt exceeds the context length of the model
+func isRetryable(err error) bool {
+	var se *statusError
+	if !errors.As(err, &se) {
+		return true
+	}
+	return se.code == http.StatusRequestTimeout || se.code == http.StatusTooManyRequests ||
+		se.code >= http.StatusInternalServerError
+}
+
+// discoverModel returns the first model served by the endpoint
+func (c *client) discoverModel(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+modelsPath, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to list models: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to list models: %s", resp.Status)
+	}
+
+	models := struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return "", fmt.Errorf("failed to decode models: %w", err)
+	}
+	if len(models.Data) == 0 {
+		return "", fmt.Errorf("no models served by %s", c.endpoint)
+	}
+	return models.Data[0].ID, nil
+}
+
+// complete sends a chat request with the system prompt if it's not empty and returns the answer
+func (c *client) complete(ctx context.Context, systemPrompt, prompt string) (string, error) {
+	var messages []chatMessage
+	if systemPrompt != "" {
+		messages = append(messages, chatMessage{Role: "system", Content: systemPrompt})
+	}
+	messages = append(messages, chatMessage{Role: "user", Content: prompt})
+
+	body, err := json.Marshal(chatRequest{
+		Model:       c.model,
+		Messages:    messages,
+		MaxTokens:   c.maxTokens,
+		Temperature: c.temperature,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+chatCompletionsPath,
+		bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return "", &statusError{
+			code:    resp.StatusCode,
+			status:  resp.Status,
+			message: strings.TrimSpace(string(message)),
+		}
+	}
+
+	result := &chatResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("no choices returned")
+	}
+	return result.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,57 @@
+package batchinference
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// PromptBuilder renders the prompt of a record with the prompt template, or takes the prompt field of the
+// record if the template is empty
+type PromptBuilder struct {
+	tmpl  *template.Template
+	field string
+}
+
+// NewPromptBuilder parses the prompt template, referring to a missing field of a record is an error
+func NewPromptBuilder(promptTemplate, promptField string) (*PromptBuilder, error) {
+	if promptTemplate == "" {
+		if promptField == "" {
+			return nil, fmt.Errorf("either prompt template or prompt field is required")
+		}
+		return &PromptBuilder{field: promptField}, nil
+	}
+
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(promptTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template: %w", err)
+	}
+	return &PromptBuilder{tmpl: tmpl}, nil
+}
+
+// Build returns the prompt of the record
+func (b *PromptBuilder) Build(record map[string]interface{}) (string, error) {
+	if b.tmpl == nil {
+		value, ok := record[b.field]
+		if !ok || value == nil {
+			return "", fmt.Errorf("prompt field %s is not found", b.field)
+		}
+		prompt, ok := value.(string)
+		if !ok {
+			prompt = fmt.Sprint(value)
+		}
+		if strings.TrimSpace(prompt) == "" {
+			return "", fmt.Errorf("prompt field %s is empty", b.field)
+		}
+		return prompt, nil
+	}
+
+	sb := &strings.Builder{}
+	if err := b.tmpl.Execute(sb, record); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	if strings.TrimSpace(sb.String()) == "" {
+		return "", fmt.Errorf("rendered prompt is empty")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,381 @@
+package batchinference
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
+)
+
+const (
+	// DefaultShardSize is the number of records written to each output file. The shard size must not be
+	// changed when a job is resumed, otherwise the written shards don't match the records.
+	DefaultShardSize = 100
+	// ErrorField is the field of the error in the output record of a failed record
+	ErrorField = "error"
+
+	maxRecordSize    = 16 << 20
+	shardPrefix      = "part-"
+	shardSuffix      = ".jsonl"
+	shardContentType = "application/jsonl"
+	maxRetryInterval = 30 * time.Second
+)
+
+// retryInterval is the interval before the first retry, which is doubled for each retry
+var retryInterval = time.Second
+
+// Options are the options of the batch inference runner
+type Options struct {
+	// Endpoint is the base url of the OpenAI compatible API, e.g., http://modelservice-foo.default.svc:8000
+	Endpoint string
+	// Model is the served model name, the first served model is used if it's empty
+	Model string
+	// InputFile is the local path of the jsonl input file
+	InputFile string
+	// OutputDir is the path in the storage to write the output shards to
+	OutputDir      string
+	PromptTemplate string
+	PromptField    string
+	SystemPrompt   string
+	OutputField    string
+	MaxRecords     int64
+	Concurrency    int
+	MaxRetries     int
+	MaxTokens      int
+	Temperature    *float64
+	RequestTimeout time.Duration
+	ShardSize      int
+}
+
+// Storage is the storage of the output shards
+type Storage interface {
+	List(ctx context.Context, prefix string, recursive, skipItself bool) ([]backend.FileInfo, error)
+	Download(ctx context.Context, src string, rw io.Writer) error
+	UploadFromReader(ctx context.Context, reader io.Reader, dst string, size int64, contentType string) error
+}
+
+type record struct {
+	// line is the line number in the input file, starting from 1
+	line int64
+	data map[string]interface{}
+	err  error
+}
+
+type runner struct {
+	opts    Options
+	prompts *PromptBuilder
+	client  *client
+}
+
+// Run runs the model over the records of the input file. The records are processed in shards, each shard is
+// written to the storage once all its records are processed and the progress is reported. The shards which
+// already exist in the storage are skipped, so that the run is resumed after a restart.
+func Run(ctx context.Context, opts Options, storage Storage, reporter Reporter) error {
+	if opts.ShardSize <= 0 {
+		opts.ShardSize = DefaultShardSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+
+	prompts, err := NewPromptBuilder(opts.PromptTemplate, opts.PromptField)
+	if err != nil {
+		return err
+	}
+	r := &runner{
+		opts:    opts,
+		prompts: prompts,
+		client: &client{
+			endpoint:    strings.TrimRight(opts.Endpoint, "/"),
+			model:       opts.Model,
+			maxTokens:   opts.MaxTokens,
+			temperature: opts.Temperature,
+			httpClient:  &http.Client{Timeout: opts.RequestTimeout},
+		},
+	}
+	if r.client.model == "" {
+		if r.client.model, err = r.client.discoverModel(ctx); err != nil {
+			return err
+		}
+	}
+
+	total, err := countRecords(opts.InputFile, opts.MaxRecords)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return fmt.Errorf("no records found in %s", opts.InputFile)
+	}
+	progress := mlv1.BatchInferenceProgress{Total: total}
+
+	completed, err := completedShards(ctx, storage, opts.OutputDir)
+	if err != nil {
+		return err
+	}
+	for shard := range completed {
+		succeeded, failed, err := countShard(ctx, storage, path.Join(opts.OutputDir, shardName(shard)))
+		if err != nil {
+			return err
+		}
+		progress.Succeeded += succeeded
+		progress.Failed += failed
+	}
+	if len(completed) > 0 {
+		logrus.Infof("resuming batch inference with %d completed shards", len(completed))
+	}
+
+	f, err := os.Open(opts.InputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	reader := newRecordReader(f)
+
+	for shard, read := 0, int64(0); read < total; shard++ {
+		records, err := reader.read(min(int64(opts.ShardSize), total-read))
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			break
+		}
+		read += int64(len(records))
+		if completed[shard] {
+			continue
+		}
+
+		outputs, failures := r.process(ctx, records)
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if err = writeShard(ctx, storage, path.Join(opts.OutputDir, shardName(shard)), outputs); err != nil {
+			return err
+		}
+
+		progress.Succeeded += int64(len(records) - len(failures))
+		progress.Failed += int64(len(failures))
+		logrus.Infof("shard %d is written, %d/%d records processed, %d failed", shard,
+			progress.Succeeded+progress.Failed, progress.Total, progress.Failed)
+		if err = reporter.Report(progress, failures); err != nil {
+			logrus.Warnf("failed to report progress: %v", err)
+		}
+	}
+
+	// report the resumed progress even if all shards are completed before the restart
+	return reporter.Report(progress, nil)
+}
+
+// process runs the model over the records with the concurrent workers, the outputs are in the order of the
+// records
+func (r *runner) process(ctx context.Context, records []record) ([]map[string]interface{},
+	[]mlv1.BatchInferenceFailure) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures []mlv1.BatchInferenceFailure
+	)
+
+	outputs := make([]map[string]interface{}, len(records))
+	queue := make(chan int)
+	for i := 0; i < r.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				rec := records[index]
+				output, err := r.processRecord(ctx, rec)
+				outputs[index] = output
+				if err != nil {
+					logrus.Debugf("record of line %d failed: %v", rec.line, err)
+					mu.Lock()
+					failures = append(failures, mlv1.BatchInferenceFailure{Line: rec.line, Error: err.Error()})
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range records {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Line < failures[j].Line })
+	return outputs, failures
+}
+
+// processRecord returns the output record, which is the input record with the output field or the error field
+func (r *runner) processRecord(ctx context.Context, rec record) (map[string]interface{}, error) {
+	output := make(map[string]interface{}, len(rec.data)+1)
+	for k, v := range rec.data {
+		output[k] = v
+	}
+
+	err := rec.err
+	if err == nil {
+		var prompt, answer string
+		if prompt, err = r.prompts.Build(rec.data); err == nil {
+			if answer, err = r.infer(ctx, prompt); err == nil {
+				output[r.opts.OutputField] = answer
+				return output, nil
+			}
+		}
+	}
+	output[ErrorField] = err.Error()
+	return output, err
+}
+
+// infer sends the prompt and retries the retryable errors with exponential backoff
+func (r *runner) infer(ctx context.Context, prompt string) (string, error) {
+	interval := retryInterval
+	for attempt := 0; ; attempt++ {
+		answer, err := r.client.complete(ctx, r.opts.SystemPrompt, prompt)
+		if err == nil {
+			return answer, nil
+		}
+		if attempt >= r.opts.MaxRetries || !isRetryable(err) {
+			return "", err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxRetryInterval)
+	}
+}
+
+// recordReader reads the non-empty lines of a jsonl file as records, a line which is not a json object is
+// returned as a failed record
+type recordReader struct {
+	scanner *bufio.Scanner
+	line    int64
+}
+
+func newRecordReader(r io.Reader) *recordReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRecordSize)
+	return &recordReader{scanner: scanner}
+}
+
+// read returns at most n records, fewer records are returned at the end of the file
+func (r *recordReader) read(n int64) ([]record, error) {
+	var records []record
+	for int64(len(records)) < n && r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		rec := record{line: r.line}
+		if err := json.Unmarshal(line, &rec.data); err != nil {
+			rec.data, rec.err = nil, fmt.Errorf("invalid json object: %w", err)
+		}
+		records = append(records, rec)
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read line %d: %w", r.line+1, err)
+	}
+	return records, nil
+}
+
+// countRecords returns the number of records to run in the input file
+func countRecords(file string, maxRecords int64) (int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRecordSize)
+	var count int64
+	for scanner.Scan() && (maxRecords <= 0 || count < maxRecords) {
+		if len(bytes.TrimSpace(scanner.Bytes())) > 0 {
+			count++
+		}
+	}
+	return count, scanner.Err()
+}
+
+func shardName(shard int) string {
+	return fmt.Sprintf("%s%05d%s", shardPrefix, shard, shardSuffix)
+}
+
+// completedShards returns the indexes of the shards written in the output directory
+func completedShards(ctx context.Context, storage Storage, outputDir string) (map[int]bool, error) {
+	files, err := storage.List(ctx, outputDir, false, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list output directory %s: %w", outputDir, err)
+	}
+
+	completed := map[int]bool{}
+	for _, f := range files {
+		if f.IsDir || !strings.HasPrefix(f.Name, shardPrefix) || !strings.HasSuffix(f.Name, shardSuffix) {
+			continue
+		}
+		shard, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(f.Name, shardPrefix), shardSuffix))
+		if err != nil || shardName(shard) != f.Name {
+			continue
+		}
+		completed[shard] = true
+	}
+	return completed, nil
+}
+
+// countShard returns the numbers of the succeeded and failed records in a written shard
+func countShard(ctx context.Context, storage Storage, file string) (int64, int64, error) {
+	buf := &bytes.Buffer{}
+	if err := storage.Download(ctx, file, buf); err != nil {
+		return 0, 0, fmt.Errorf("failed to download shard %s: %w", file, err)
+	}
+
+	var succeeded, failed int64
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRecordSize)
+	for scanner.Scan() {
+		output := map[string]interface{}{}
+		if err := json.Unmarshal(scanner.Bytes(), &output); err != nil {
+			return 0, 0, fmt.Errorf("invalid record in shard %s: %w", file, err)
+		}
+		if _, ok := output[ErrorField]; ok {
+			failed++
+		} else {
+			succeeded++
+		}
+	}
+	return succeeded, failed, scanner.Err()
+}
+
+func writeShard(ctx context.Context, storage Storage, file string, outputs []map[string]interface{}) error {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	for _, output := range outputs {
+		if err := encoder.Encode(output); err != nil {
+			return err
+		}
+	}
+
+	if err := storage.UploadFromReader(ctx, buf, file, int64(buf.Len()), shardContentType); err != nil {
+		return fmt.Errorf("failed to write shard %s: %w", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package batchinference
+
+import (
+	"reflect"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+// MaxRecentFailures is the maximum number of failed records kept in the status
+const MaxRecentFailures = 20
+
+// Reporter reports the progress and the failed records of each written shard
+type Reporter interface {
+	Report(progress mlv1.BatchInferenceProgress, failures []mlv1.BatchInferenceFailure) error
+}
+
+// JobClient is the client to update the status of the batch inference jobs
+type JobClient interface {
+	Get(namespace, name string, opts metav1.GetOptions) (*mlv1.BatchInferenceJob, error)
+	UpdateStatus(*mlv1.BatchInferenceJob) (*mlv1.BatchInferenceJob, error)
+}
+
+// StatusReporter records the progress in the status of the batch inference job
+type StatusReporter struct {
+	Client    JobClient
+	Namespace string
+	Name      string
+}
+
+func (r *StatusReporter) Report(progress mlv1.BatchInferenceProgress, failures []mlv1.BatchInferenceFailure) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		bij, err := r.Client.Get(r.Namespace, r.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		bijCopy := bij.DeepCopy()
+		SetProgress(&bijCopy.Status, progress, failures)
+		if reflect.DeepEqual(bij.Status, bijCopy.Status) {
+			return nil
+		}
+		_, err = r.Client.UpdateStatus(bijCopy)
+		return err
+	})
+}
+
+// SetProgress sets the progress and appends the failures to the recent failures of the status, only the last
+// MaxRecentFailures failures are kept
+func SetProgress(status *mlv1.BatchInferenceJobStatus, progress mlv1.BatchInferenceProgress,
+	failures []mlv1.BatchInferenceFailure) {
+	progress.Percentage = strconv.FormatFloat(percentage(progress.Succeeded+progress.Failed, progress.Total),
+		'f', 2, 64)
+	status.Progress = progress
+
+	if len(failures) == 0 {
+		return
+	}
+	recent := make([]mlv1.BatchInferenceFailure, 0, len(status.RecentFailures)+len(failures))
+	recent = append(recent, status.RecentFailures...)
+	recent = append(recent, failures...)
+	if len(recent) > MaxRecentFailures {
+		recent = recent[len(recent)-MaxRecentFailures:]
+	}
+	status.RecentFailures = recent
+}
+
+func percentage(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
@@ -0,0 +1,217 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+func TestLoadPrompts(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "b.jsonl"), `{"question": "2+2?", "answer": 4}
+{"question": "", "answer": "skipped"}
+
+{"question": "Capital of France?", "answer": "Paris"}`)
+	writeFile(t, filepath.Join(dir, "a.json"), `[{"question": "Is water wet?", "answer": true}]`)
+	writeFile(t, filepath.Join(dir, "README.md"), "not a dataset file")
+
+	prompts, err := LoadPrompts(dir, "question", "answer", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Prompt{
+		{Text: "Is water wet?", Reference: "true"},
+		{Text: "2+2?", Reference: "4"},
+		{Text: "Capital of France?", Reference: "Paris"},
+	}
+	if len(prompts) != len(expected) {
+		t.Fatalf("Expected %d prompts, got %d", len(expected), len(prompts))
+	}
+	for i := range expected {
+		if prompts[i] != expected[i] {
+			t.Errorf("Expected prompt %d to be %+v, got %+v", i, expected[i], prompts[i])
+		}
+	}
+
+	prompts, err = LoadPrompts(dir, "question", "", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prompts) != 2 || prompts[1].Reference != "" {
+		t.Errorf("Expected 2 prompts without reference, got %+v", prompts)
+	}
+
+	if _, err = LoadPrompts(dir, "prompt", "", 0); err == nil {
+		t.Errorf("Expected an error when no prompts are found")
+	}
+}
+
+func TestIsCorrect(t *testing.T) {
+	tests := []struct {
+		answer    string
+		reference string
+		expected  bool
+	}{
+		{answer: "Paris", reference: "paris", expected: true},
+		{answer: "B) Paris is the capital.", reference: "b", expected: true},
+		{answer: "  42.", reference: "42", expected: true},
+		{answer: "The answer is B", reference: "b", expected: false},
+		{answer: "Par", reference: "Paris", expected: false},
+		{answer: "anything", reference: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := isCorrect(tt.answer, tt.reference); got != tt.expected {
+			t.Errorf("Expected isCorrect(%q, %q) to be %v, got %v", tt.answer, tt.reference, tt.expected, got)
+		}
+	}
+}
+
+func TestSummarizeLatency(t *testing.T) {
+	latencies := make([]time.Duration, 0, 100)
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	summary := summarizeLatency(latencies)
+	if summary.Mean.Duration != 50500*time.Microsecond {
+		t.Errorf("Expected mean 50.5ms, got %s", summary.Mean.Duration)
+	}
+	if summary.P50.Duration != 50*time.Millisecond {
+		t.Errorf("Expected p50 50ms, got %s", summary.P50.Duration)
+	}
+	if summary.P90.Duration != 90*time.Millisecond {
+		t.Errorf("Expected p90 90ms, got %s", summary.P90.Duration)
+	}
+	if summary.P99.Duration != 99*time.Millisecond {
+		t.Errorf("Expected p99 99ms, got %s", summary.P99.Duration)
+	}
+	if latencies[0] != 100*time.Millisecond {
+		t.Errorf("Expected the latencies not to be sorted in place")
+	}
+
+	if summary = summarizeLatency(nil); summary.P99.Duration != 0 {
+		t.Errorf("Expected empty summary, got %+v", summary)
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case modelsPath:
+			_, _ = fmt.Fprint(w, `{"data": [{"id": "test-model"}]}`)
+		case chatCompletionsPath:
+			request := chatRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Model != "test-model" {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			if !request.Stream {
+				_, _ = fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "Paris."}}]}`)
+				return
+			}
+			for _, token := range []string{"Hello", " world"} {
+				_, _ = fmt.Fprintf(w, "data: {\"choices\": [{\"delta\": {\"content\": %q}}]}\n\n", token)
+			}
+			_, _ = fmt.Fprint(w, "data: {\"choices\": [], \"usage\": {\"completion_tokens\": 3}}\n\n")
+			_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "data.jsonl"), `{"prompt": "Capital of France?", "answer": "paris"}
+{"prompt": "Capital of Germany?", "answer": "berlin"}`)
+
+	opts := Options{
+		Endpoint:          server.URL + "/",
+		Type:              mlv1.ModelBenchmarkTypePerformance,
+		DatasetDir:        dir,
+		PromptField:       "prompt",
+		ConcurrencyLevels: []int{1, 2},
+		Duration:          100 * time.Millisecond,
+		MaxTokens:         16,
+		RequestTimeout:    time.Second,
+	}
+	report, err := Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(report.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(report.Results))
+	}
+	for i, result := range report.Results {
+		if result.Concurrency != int32(opts.ConcurrencyLevels[i]) {
+			t.Errorf("Expected concurrency %d, got %d", opts.ConcurrencyLevels[i], result.Concurrency)
+		}
+		if result.Requests == 0 || result.Errors != 0 {
+			t.Errorf("Expected successful requests, got %d requests and %d errors", result.Requests, result.Errors)
+		}
+	}
+
+	opts.Type = mlv1.ModelBenchmarkTypeAccuracy
+	opts.ReferenceField = "answer"
+	report, err = Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := mlv1.ModelBenchmarkAccuracy{Total: 2, Correct: 1, Errors: 0, Accuracy: "50.00"}
+	if report.Accuracy == nil || *report.Accuracy != expected {
+		t.Errorf("Expected accuracy %+v, got %+v", expected, report.Accuracy)
+	}
+
+	message := filepath.Join(t.TempDir(), "termination-log")
+	if err = WriteReport(message, report); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(message)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, err := ParseReport(string(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed.Accuracy == nil || *parsed.Accuracy != expected {
+		t.Errorf("Expected parsed accuracy %+v, got %+v", expected, parsed.Accuracy)
+	}
+}
+
+func TestStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		for _, token := range []string{"a", "b", "c"} {
+			_, _ = fmt.Fprintf(w, "data: {\"choices\": [{\"delta\": {\"content\": %q}}]}\n\n", token)
+		}
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	c := &client{endpoint: server.URL, model: "test-model", httpClient: server.Client()}
+	result, err := c.stream(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.outputTokens != 3 {
+		t.Errorf("Expected 3 output tokens counted from chunks, got %d", result.outputTokens)
+	}
+	if len(result.interTokens) != 2 {
+		t.Errorf("Expected 2 inter-token latencies, got %d", len(result.interTokens))
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,203 @@
+package benchmark
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	chatCompletionsPath = "/v1/chat/completions"
+	modelsPath          = "/v1/models"
+	sseDataPrefix       = "data:"
+	sseDone             = "[DONE]"
+)
+
+// client is the OpenAI compatible client of the model service
+type client struct {
+	endpoint   string
+	model      string
+	maxTokens  int
+	httpClient *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model         string         `json:"model"`
+	Messages      []chatMessage  `json:"messages"`
+	MaxTokens     int            `json:"max_tokens"`
+	Temperature   float64        `json:"temperature"`
+	Stream        bool           `json:"stream"`
+	StreamOptions *streamOptions `json:"stream_options,omitempty"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+		Delta   chatMessage `json:"delta"`
+	} `json:"choices"`
+	Usage *struct {
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// streamResult is the measurement of a streaming request
+type streamResult struct {
+	timeToFirstToken time.Duration
+	interTokens      []time.Duration
+	outputTokens     int
+}
+
+// discoverModel returns the first model served by the endpoint
+func (c *client) discoverModel(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+modelsPath, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to list models: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to list models: %s", resp.Status)
+	}
+
+	models := struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return "", fmt.Errorf("failed to decode models: %w", err)
+	}
+	if len(models.Data) == 0 {
+		return "", fmt.Errorf("no models served by %s", c.endpoint)
+	}
+	return models.Data[0].ID, nil
+}
+
+// stream sends a streaming chat request and measures the latency of the generated tokens,
+// each content chunk is counted as a token if the usage is not returned by the server.
+func (c *client) stream(ctx context.Context, prompt string) (*streamResult, error) {
+	resp, err := c.send(ctx, chatRequest{
+		Model:         c.model,
+		Messages:      []chatMessage{{Role: "user", Content: prompt}},
+		MaxTokens:     c.maxTokens,
+		Stream:        true,
+		StreamOptions: &streamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	start := time.Now()
+	result := &streamResult{}
+	var last time.Time
+	chunks, usageTokens := 0, 0
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRecordSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, sseDataPrefix) {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, sseDataPrefix))
+		if data == sseDone {
+			break
+		}
+
+		chunk := &chatResponse{}
+		if err = json.Unmarshal([]byte(data), chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		if chunk.Usage != nil {
+			usageTokens = chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		now := time.Now()
+		if chunks == 0 {
+			result.timeToFirstToken = now.Sub(start)
+		} else {
+			result.interTokens = append(result.interTokens, now.Sub(last))
+		}
+		last = now
+		chunks++
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+	if chunks == 0 {
+		return nil, fmt.Errorf("no tokens generated")
+	}
+
+	result.outputTokens = chunks
+	if usageTokens > 0 {
+		result.outputTokens = usageTokens
+	}
+	return result, nil
+}
+
+// complete sends a deterministic chat request and returns the answer
+func (c *client) complete(ctx context.Context, prompt string) (string, error) {
+	resp, err := c.send(ctx, chatRequest{
+		Model:     c.model,
+		Messages:  []chatMessage{{Role: "user", Content: prompt}},
+		MaxTokens: c.maxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	result := &chatResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("no choices returned")
+	}
+	return result.Choices[0].Message.Content, nil
+}
+
+func (c *client) send(ctx context.Context, request chatRequest) (*http.Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+chatCompletionsPath,
+		bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return nil, fmt.Errorf("request failed with %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
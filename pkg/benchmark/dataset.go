@@ -0,0 +1,121 @@
+package benchmark
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const maxRecordSize = 16 << 20
+
+// Prompt is a benchmark prompt with its optional reference answer
+type Prompt struct {
+	Text      string
+	Reference string
+}
+
+// LoadPrompts loads the prompts from the json and jsonl files in the directory, the files are loaded
+// in lexical order so that the prompts are the same across runs.
+func LoadPrompts(dir, promptField, referenceField string, maxPrompts int) ([]Prompt, error) {
+	files := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json", ".jsonl":
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk dataset directory %s: %w", dir, err)
+	}
+	sort.Strings(files)
+
+	prompts := make([]Prompt, 0)
+	for _, file := range files {
+		records, err := readRecords(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			prompt, ok := toPrompt(record, promptField, referenceField)
+			if !ok {
+				continue
+			}
+			prompts = append(prompts, prompt)
+			if maxPrompts > 0 && len(prompts) >= maxPrompts {
+				return prompts, nil
+			}
+		}
+	}
+
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("no prompts with field %q found in %s", promptField, dir)
+	}
+	return prompts, nil
+}
+
+// readRecords reads the records of a jsonl file or a json file with an array of records
+func readRecords(file string) ([]map[string]interface{}, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	records := make([]map[string]interface{}, 0)
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		if err = json.NewDecoder(f).Decode(&records); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", file, err)
+		}
+		return records, nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRecordSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		record := make(map[string]interface{})
+		if err = json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", file, err)
+		}
+		records = append(records, record)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return records, nil
+}
+
+func toPrompt(record map[string]interface{}, promptField, referenceField string) (Prompt, bool) {
+	text, ok := record[promptField].(string)
+	if !ok || strings.TrimSpace(text) == "" {
+		return Prompt{}, false
+	}
+
+	prompt := Prompt{Text: text}
+	if referenceField != "" {
+		switch reference := record[referenceField].(type) {
+		case string:
+			prompt.Reference = reference
+		case float64, bool:
+			prompt.Reference = fmt.Sprint(reference)
+		default:
+			return Prompt{}, false
+		}
+	}
+	return prompt, true
+}
@@ -0,0 +1,41 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+// DefaultReportPath is the termination message path of the benchmark container, the report is read
+// by the controller from the container status so that no extra storage is required.
+const DefaultReportPath = "/dev/termination-log"
+
+// Report is the result of the benchmark
+type Report struct {
+	Results  []mlv1.ModelBenchmarkResult  `json:"results,omitempty"`
+	Accuracy *mlv1.ModelBenchmarkAccuracy `json:"accuracy,omitempty"`
+}
+
+// WriteReport writes the report to the file in compact json
+func WriteReport(path string, report *Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if err = os.WriteFile(filepath.Clean(path), data, 0600); err != nil {
+		return fmt.Errorf("failed to write report to %s: %w", path, err)
+	}
+	return nil
+}
+
+// ParseReport parses the report from the termination message of the benchmark container
+func ParseReport(message string) (*Report, error) {
+	report := &Report{}
+	if err := json.Unmarshal([]byte(message), report); err != nil {
+		return nil, fmt.Errorf("failed to parse benchmark report: %w", err)
+	}
+	return report, nil
+}
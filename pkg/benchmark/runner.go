@@ -0,0 +1,251 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+// Options are the options of the benchmark runner
+type Options struct {
+	// Endpoint is the base url of the OpenAI compatible API, e.g., http://modelservice-foo.default.svc:8000
+	Endpoint string
+	// Model is the served model name, the first served model is used if it's empty
+	Model             string
+	Type              mlv1.ModelBenchmarkType
+	DatasetDir        string
+	PromptField       string
+	ReferenceField    string
+	MaxPrompts        int
+	ConcurrencyLevels []int
+	Duration          time.Duration
+	MaxTokens         int
+	RequestTimeout    time.Duration
+}
+
+// Run runs the benchmark and returns the report
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	referenceField := ""
+	if opts.Type == mlv1.ModelBenchmarkTypeAccuracy {
+		if opts.ReferenceField == "" {
+			return nil, fmt.Errorf("reference field is required by the accuracy benchmark")
+		}
+		referenceField = opts.ReferenceField
+	}
+	if len(opts.ConcurrencyLevels) == 0 {
+		opts.ConcurrencyLevels = []int{1}
+	}
+
+	prompts, err := LoadPrompts(opts.DatasetDir, opts.PromptField, referenceField, opts.MaxPrompts)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("loaded %d prompts from %s", len(prompts), opts.DatasetDir)
+
+	c := &client{
+		endpoint:   strings.TrimRight(opts.Endpoint, "/"),
+		model:      opts.Model,
+		maxTokens:  opts.MaxTokens,
+		httpClient: &http.Client{Timeout: opts.RequestTimeout},
+	}
+	if c.model == "" {
+		if c.model, err = c.discoverModel(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Type == mlv1.ModelBenchmarkTypeAccuracy {
+		accuracy := runAccuracy(ctx, c, prompts, opts.ConcurrencyLevels[0])
+		logrus.Infof("accuracy of model %s: %s%% (%d/%d)", c.model, accuracy.Accuracy, accuracy.Correct,
+			accuracy.Total)
+		return &Report{Accuracy: accuracy}, nil
+	}
+
+	report := &Report{}
+	for _, concurrency := range opts.ConcurrencyLevels {
+		logrus.Infof("running benchmark of model %s with concurrency %d for %s", c.model, concurrency, opts.Duration)
+		result := runPerformance(ctx, c, prompts, concurrency, opts.Duration)
+		logrus.Infof("concurrency %d: %d requests, %d errors, %s req/s, %s tokens/s", concurrency, result.Requests,
+			result.Errors, result.RequestThroughput, result.OutputTokenThroughput)
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// runPerformance sends the prompts in turn with the concurrent workers until the duration is elapsed,
+// the in-flight requests are waited so that they are counted in the result.
+func runPerformance(ctx context.Context, c *client, prompts []Prompt, concurrency int,
+	duration time.Duration) mlv1.ModelBenchmarkResult {
+	var (
+		mu           sync.Mutex
+		wg           sync.WaitGroup
+		next         int
+		requests     int64
+		errors       int64
+		outputTokens int64
+		ttfts        []time.Duration
+		interTokens  []time.Duration
+	)
+
+	start := time.Now()
+	deadline := start.Add(duration)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) && ctx.Err() == nil {
+				mu.Lock()
+				prompt := prompts[next%len(prompts)]
+				next++
+				mu.Unlock()
+
+				result, err := c.stream(ctx, prompt.Text)
+
+				mu.Lock()
+				requests++
+				if err != nil {
+					errors++
+					logrus.Debugf("request failed: %v", err)
+				} else {
+					outputTokens += int64(result.outputTokens)
+					ttfts = append(ttfts, result.timeToFirstToken)
+					interTokens = append(interTokens, result.interTokens...)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start).Seconds()
+
+	return mlv1.ModelBenchmarkResult{
+		Concurrency:           int32(concurrency),
+		Requests:              requests,
+		Errors:                errors,
+		ErrorRate:             formatDecimal(percentage(errors, requests)),
+		RequestThroughput:     formatDecimal(float64(requests-errors) / elapsed),
+		OutputTokenThroughput: formatDecimal(float64(outputTokens) / elapsed),
+		TimeToFirstToken:      summarizeLatency(ttfts),
+		InterTokenLatency:     summarizeLatency(interTokens),
+	}
+}
+
+// runAccuracy sends each prompt once and scores the answers against the reference answers
+func runAccuracy(ctx context.Context, c *client, prompts []Prompt, concurrency int) *mlv1.ModelBenchmarkAccuracy {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		correct int64
+		errors  int64
+	)
+
+	queue := make(chan Prompt)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prompt := range queue {
+				answer, err := c.complete(ctx, prompt.Text)
+
+				mu.Lock()
+				if err != nil {
+					errors++
+					logrus.Debugf("request failed: %v", err)
+				} else if isCorrect(answer, prompt.Reference) {
+					correct++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, prompt := range prompts {
+		queue <- prompt
+	}
+	close(queue)
+	wg.Wait()
+
+	total := int64(len(prompts))
+	return &mlv1.ModelBenchmarkAccuracy{
+		Total:    total,
+		Correct:  correct,
+		Errors:   errors,
+		Accuracy: formatDecimal(percentage(correct, total)),
+	}
+}
+
+// isCorrect returns true if the normalized answer equals to or starts with the normalized reference answer,
+// e.g., the answer "B) Paris" matches the reference answer "b".
+func isCorrect(answer, reference string) bool {
+	answerWords, referenceWords := normalizeWords(answer), normalizeWords(reference)
+	if len(referenceWords) == 0 || len(answerWords) < len(referenceWords) {
+		return false
+	}
+	for i := range referenceWords {
+		if answerWords[i] != referenceWords[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func summarizeLatency(latencies []time.Duration) mlv1.ModelBenchmarkLatency {
+	if len(latencies) == 0 {
+		return mlv1.ModelBenchmarkLatency{}
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	return mlv1.ModelBenchmarkLatency{
+		Mean: roundDuration(sum / time.Duration(len(sorted))),
+		P50:  roundDuration(percentile(sorted, 50)),
+		P90:  roundDuration(percentile(sorted, 90)),
+		P99:  roundDuration(percentile(sorted, 99)),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func roundDuration(d time.Duration) metav1.Duration {
+	return metav1.Duration{Duration: d.Round(time.Microsecond)}
+}
+
+func percentage(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+func formatDecimal(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
@@ -8,6 +8,7 @@ const (
 	LLMOSPrefix = "llmos.ai"
 	MLPrefix    = "ml.llmos.ai"
 	MgmtPrefix  = "management.llmos.ai"
+	AgentPrefix = "agent.llmos.ai"
 
 	SystemNamespaceName          = "llmos-system"
 	StorageSystemNamespaceName   = "storage-system"
@@ -19,6 +20,7 @@ const (
 	SUCNamespace                 = "system-upgrade"
 	AdminRole                    = "cluster-admin"
 	NvidiaGPUKey                 = "nvidia.com/gpu"
+	NvidiaGPUProductLabel        = "nvidia.com/gpu.product"
 	KubeSystemNamespaceName      = "kube-system"
 
 	LLMOSCrdChartName      = "llmos-crd"
@@ -61,14 +63,18 @@ const (
 	AnnotationClusterPolicyProviderKey = LLMOSPrefix + "/k8s-provider"
 	AnnotationSkipWebhook              = LLMOSPrefix + "/skip-webhook"
 	AnnotationOnDeleteVolumes          = LLMOSPrefix + "/on-delete-volumes"
+	AnnotationSpecHash                 = LLMOSPrefix + "/spec-hash"
 
 	/*
 		KubeRay related constant
 	*/
-	LabelRaySchedulerName           = "ray.io/scheduler-name"
-	AnnotationRayClusterInitialized = MLPrefix + "rayClusterInitialized"
-	AnnotationRayFTEnabledKey       = "ray.io/ft-enabled"
-	RayRedisCleanUpFinalizer        = "ray.io/gcs-ft-redis-cleanup-finalizer"
+	LabelRaySchedulerName              = "ray.io/scheduler-name"
+	LabelRayClusterName                = "ray.io/cluster"
+	LabelRayNodeType                   = "ray.io/node-type"
+	AnnotationRayClusterInitialized    = MLPrefix + "rayClusterInitialized"
+	AnnotationRayFTEnabledKey          = "ray.io/ft-enabled"
+	AnnotationRayOverwriteContainerCmd = "ray.io/overwrite-container-cmd"
+	RayRedisCleanUpFinalizer           = "ray.io/gcs-ft-redis-cleanup-finalizer"
 
 	RayServiceKind     = "RayService"
 	RedisSecretKeyName = "redis-password" // #nosec G101
@@ -83,16 +89,34 @@ const (
 	/*
 		ML related constants
 	*/
-	LabelNotebookName            = MLPrefix + "/notebook-name"
-	LabelLLMOSMLAppName          = MLPrefix + "/app"
-	LabelLLMOSMLType             = MLPrefix + "/type"
-	LabelModelServiceName        = MLPrefix + "/model-service-name"
-	LabelModelServiceServeEngine = MLPrefix + "/serve-engine"
-	LabelDatasetName             = MLPrefix + "/dataset-name"
-	LabelDatasetVersion          = MLPrefix + "/dataset-version"
-	LabelResourceType            = MLPrefix + "/resource-type"
-	LabelLocalModelName          = MLPrefix + "/local-model-name"
-	LabelModelNamespace          = MLPrefix + "/model-namespace"
-	LabelModelName               = MLPrefix + "/model-name"
-	LabelRegistryName            = MLPrefix + "/registry-name"
+	LabelNotebookName        = MLPrefix + "/notebook-name"
+	LabelNotebookProfileName = MLPrefix + "/notebook-profile-name"
+	// AnnotationNotebookIdleTimeout overrides the notebook idle timeout setting of the namespace
+	AnnotationNotebookIdleTimeout = MLPrefix + "/notebook-idle-timeout-minutes"
+	LabelLLMOSMLAppName           = MLPrefix + "/app"
+	LabelLLMOSMLType              = MLPrefix + "/type"
+	LabelModelServiceName         = MLPrefix + "/model-service-name"
+	LabelModelServiceServeEngine  = MLPrefix + "/serve-engine"
+	LabelModelServiceRevision     = MLPrefix + "/model-service-revision"
+	LabelModelBenchmarkName       = MLPrefix + "/model-benchmark-name"
+	LabelFineTuneJobName          = MLPrefix + "/fine-tune-job-name"
+	LabelBatchInferenceJobName    = MLPrefix + "/batch-inference-job-name"
+	LabelDatasetName              = MLPrefix + "/dataset-name"
+	LabelDatasetVersion           = MLPrefix + "/dataset-version"
+	// AnnotationDatasetFilesChangedAt is updated once the files of a dataset version are changed by the API
+	AnnotationDatasetFilesChangedAt = MLPrefix + "/files-changed-at"
+	LabelResourceType               = MLPrefix + "/resource-type"
+	LabelLocalModelName             = MLPrefix + "/local-model-name"
+	LabelModelNamespace             = MLPrefix + "/model-namespace"
+	LabelModelName                  = MLPrefix + "/model-name"
+	LabelRegistryName               = MLPrefix + "/registry-name"
+
+	LabelDataCollectionName = AgentPrefix + "/data-collection-name"
+	// AnnotationDataCollectionSources are the sources synced by the job of a data collection
+	AnnotationDataCollectionSources = AgentPrefix + "/sources"
+
+	// DatasetSigningKeySecretName is the secret of the cluster key signing the manifests of the published
+	// dataset versions, the public key is shared by the configmap to be verified by the downloader
+	DatasetSigningKeySecretName          = "llmos-dataset-signing-key"
+	DatasetSigningPublicKeyConfigMapName = "llmos-dataset-signing-public-key"
 )
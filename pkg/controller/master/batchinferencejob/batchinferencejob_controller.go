@@ -0,0 +1,331 @@
+package batchinferencejob
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	ctlbatchv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+const (
+	batchInferenceJobOnChange = "batchInferenceJob.onChange"
+	bijJobOnChange            = "batchInferenceJob.jobOnChange"
+
+	requeueInterval = 10 * time.Second
+)
+
+type handler struct {
+	BatchInferenceJobs     ctlmlv1.BatchInferenceJobController
+	BatchInferenceJobCache ctlmlv1.BatchInferenceJobCache
+	ModelServiceCache      ctlmlv1.ModelServiceCache
+	DatasetVersions        ctlmlv1.DatasetVersionClient
+	DatasetVersionCache    ctlmlv1.DatasetVersionCache
+	Jobs                   ctlbatchv1.JobClient
+	JobCache               ctlbatchv1.JobCache
+	PodCache               ctlcorev1.PodCache
+
+	DownloaderAccess *snapshotting.DownloaderAccess
+}
+
+func Register(ctx context.Context, mgmt *config.Management, _ config.Options) error {
+	batchInferenceJobs := mgmt.LLMFactory.Ml().V1().BatchInferenceJob()
+	datasetVersions := mgmt.LLMFactory.Ml().V1().DatasetVersion()
+	jobs := mgmt.BatchFactory.Batch().V1().Job()
+
+	h := &handler{
+		BatchInferenceJobs:     batchInferenceJobs,
+		BatchInferenceJobCache: batchInferenceJobs.Cache(),
+		ModelServiceCache:      mgmt.LLMFactory.Ml().V1().ModelService().Cache(),
+		DatasetVersions:        datasetVersions,
+		DatasetVersionCache:    datasetVersions.Cache(),
+		Jobs:                   jobs,
+		JobCache:               jobs.Cache(),
+		PodCache:               mgmt.CoreFactory.Core().V1().Pod().Cache(),
+
+		DownloaderAccess: snapshotting.NewDownloaderAccess(mgmt),
+	}
+
+	batchInferenceJobs.OnChange(ctx, batchInferenceJobOnChange, h.OnChange)
+	jobs.OnChange(ctx, bijJobOnChange, h.OnJobChange)
+	return nil
+}
+
+// OnChange creates the output dataset version and starts the batch inference job once the model service and
+// the dataset versions are ready
+func (h *handler) OnChange(_ string, bij *mlv1.BatchInferenceJob) (*mlv1.BatchInferenceJob, error) {
+	if bij == nil || bij.DeletionTimestamp != nil || isFinished(bij) {
+		return bij, nil
+	}
+
+	jobName := getJobName(bij.Name)
+	if _, err := h.JobCache.Get(bij.Namespace, jobName); err == nil {
+		return bij, nil
+	} else if !errors.IsNotFound(err) {
+		return bij, fmt.Errorf("failed to get job %s/%s: %w", bij.Namespace, jobName, err)
+	}
+
+	ms, err := h.ModelServiceCache.Get(bij.Namespace, bij.Spec.ModelService)
+	if err != nil && !errors.IsNotFound(err) {
+		return bij, err
+	} else if err != nil {
+		return h.fail(bij, fmt.Sprintf("model service %s not found", bij.Spec.ModelService))
+	}
+	if ms.Status.ReadyReplicas == 0 {
+		return h.pending(bij, fmt.Sprintf("waiting for model service %s to be ready", ms.Name))
+	}
+
+	input, err := h.DatasetVersionCache.Get(bij.Namespace, bij.Spec.Input.DatasetVersion)
+	if err != nil && !errors.IsNotFound(err) {
+		return bij, err
+	} else if err != nil {
+		return h.fail(bij, fmt.Sprintf("dataset version %s not found", bij.Spec.Input.DatasetVersion))
+	}
+	if !mlv1.Ready.IsTrue(input) {
+		return h.pending(bij, fmt.Sprintf("waiting for dataset version %s to be ready", input.Name))
+	}
+
+	output, err := h.ensureOutputDatasetVersion(bij)
+	if err != nil {
+		return bij, err
+	}
+	if output == nil {
+		return h.fail(bij, fmt.Sprintf("version %s of dataset %s already exists", bij.Spec.Output.Version,
+			bij.Spec.Output.Dataset))
+	}
+	if bij.Status.OutputDatasetVersion != output.Name {
+		bijCopy := bij.DeepCopy()
+		bijCopy.Status.OutputDatasetVersion = output.Name
+		if bij, err = h.BatchInferenceJobs.UpdateStatus(bijCopy); err != nil {
+			return bij, err
+		}
+	}
+	if !mlv1.Ready.IsTrue(output) {
+		return h.pending(bij, fmt.Sprintf("waiting for output dataset version %s to be ready", output.Name))
+	}
+
+	// the input is downloaded and the outputs are uploaded by the downloader service account
+	if err = h.DownloaderAccess.Ensure(bij.Namespace); err != nil {
+		return bij, err
+	}
+
+	logrus.Infof("creating batch inference job of %s/%s", bij.Namespace, bij.Name)
+	if _, err = h.Jobs.Create(constructJob(bij, ms)); err != nil && !errors.IsAlreadyExists(err) {
+		return bij, fmt.Errorf("failed to create job %s/%s: %w", bij.Namespace, jobName, err)
+	}
+
+	bijCopy := bij.DeepCopy()
+	bijCopy.Status.JobName = jobName
+	bijCopy.Status.StartTime = &metav1.Time{Time: time.Now()}
+	return h.updateStatus(bijCopy, mlv1.BatchInferenceJobPhaseRunning, "batch inference is running")
+}
+
+// ensureOutputDatasetVersion creates the output dataset version of the batch inference job, nil is returned if
+// the version exists and isn't created by the job
+func (h *handler) ensureOutputDatasetVersion(bij *mlv1.BatchInferenceJob) (*mlv1.DatasetVersion, error) {
+	name := getOutputDatasetVersionName(bij)
+	dv, err := h.DatasetVersionCache.Get(bij.Namespace, name)
+	if err == nil {
+		if dv.Labels[constant.LabelBatchInferenceJobName] != bij.Name {
+			return nil, nil
+		}
+		return dv, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get dataset version %s/%s: %w", bij.Namespace, name, err)
+	}
+
+	dv = &mlv1.DatasetVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: bij.Namespace,
+			Labels: map[string]string{
+				constant.LabelBatchInferenceJobName: bij.Name,
+			},
+		},
+		Spec: mlv1.DatasetVersionSpec{
+			Dataset: bij.Spec.Output.Dataset,
+			Version: bij.Spec.Output.Version,
+		},
+	}
+
+	logrus.Infof("creating output dataset version %s/%s of batch inference job %s", bij.Namespace, name, bij.Name)
+	dv, err = h.DatasetVersions.Create(dv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dataset version %s/%s: %w", bij.Namespace, name, err)
+	}
+	return dv, nil
+}
+
+// OnJobChange records the result of the finished batch inference job
+func (h *handler) OnJobChange(_ string, job *batchv1.Job) (*batchv1.Job, error) {
+	if job == nil || job.DeletionTimestamp != nil || job.Labels[constant.LabelBatchInferenceJobName] == "" {
+		return job, nil
+	}
+
+	bij, err := h.BatchInferenceJobCache.Get(job.Namespace, job.Labels[constant.LabelBatchInferenceJobName])
+	if err != nil && errors.IsNotFound(err) {
+		return job, nil
+	} else if err != nil {
+		return job, err
+	}
+	if isFinished(bij) {
+		return job, nil
+	}
+
+	switch {
+	case isJobConditionTrue(job, batchv1.JobComplete):
+		// inspect the schema of the written outputs
+		if err = h.markFilesChanged(bij); err != nil {
+			return job, err
+		}
+		progress := bij.Status.Progress
+		_, err = h.updateStatus(bij, mlv1.BatchInferenceJobPhaseSucceeded,
+			fmt.Sprintf("%d records succeeded and %d records failed, outputs are written to dataset version %s",
+				progress.Succeeded, progress.Failed, bij.Status.OutputDatasetVersion))
+		return job, err
+	case isJobConditionTrue(job, batchv1.JobFailed):
+		message, err := h.getTerminationMessage(job)
+		if err != nil {
+			return job, err
+		}
+		if message == "" {
+			message = getJobConditionMessage(job, batchv1.JobFailed)
+		}
+		_, err = h.fail(bij, message)
+		return job, err
+	}
+
+	return job, nil
+}
+
+func (h *handler) markFilesChanged(bij *mlv1.BatchInferenceJob) error {
+	name := bij.Status.OutputDatasetVersion
+	dv, err := h.DatasetVersionCache.Get(bij.Namespace, name)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get dataset version %s/%s: %w", bij.Namespace, name, err)
+	}
+
+	dvCopy := dv.DeepCopy()
+	if dvCopy.Annotations == nil {
+		dvCopy.Annotations = make(map[string]string)
+	}
+	dvCopy.Annotations[constant.AnnotationDatasetFilesChangedAt] = time.Now().UTC().Format(time.RFC3339Nano)
+	if _, err = h.DatasetVersions.Update(dvCopy); err != nil {
+		return fmt.Errorf("failed to update dataset version %s/%s: %w", bij.Namespace, name, err)
+	}
+	return nil
+}
+
+func (h *handler) pending(bij *mlv1.BatchInferenceJob, message string) (*mlv1.BatchInferenceJob, error) {
+	h.BatchInferenceJobs.EnqueueAfter(bij.Namespace, bij.Name, requeueInterval)
+	return h.updateStatus(bij, mlv1.BatchInferenceJobPhasePending, message)
+}
+
+// fail marks the batch inference job as failed and deletes the output dataset version created by the job, so
+// that the incomplete outputs aren't used by mistake
+func (h *handler) fail(bij *mlv1.BatchInferenceJob, message string) (*mlv1.BatchInferenceJob, error) {
+	if name := bij.Status.OutputDatasetVersion; name != "" {
+		dv, err := h.DatasetVersionCache.Get(bij.Namespace, name)
+		if err != nil && !errors.IsNotFound(err) {
+			return bij, err
+		}
+		if err == nil && dv.Labels[constant.LabelBatchInferenceJobName] == bij.Name {
+			logrus.Infof("deleting output dataset version %s/%s of failed batch inference job %s",
+				bij.Namespace, name, bij.Name)
+			if err = h.DatasetVersions.Delete(bij.Namespace, name, &metav1.DeleteOptions{}); err != nil &&
+				!errors.IsNotFound(err) {
+				return bij, fmt.Errorf("failed to delete dataset version %s/%s: %w", bij.Namespace, name, err)
+			}
+		}
+	}
+
+	return h.updateStatus(bij, mlv1.BatchInferenceJobPhaseFailed, message)
+}
+
+func (h *handler) updateStatus(bij *mlv1.BatchInferenceJob, phase mlv1.BatchInferenceJobPhase,
+	message string) (*mlv1.BatchInferenceJob, error) {
+	bijCopy := bij.DeepCopy()
+	bijCopy.Status.Phase = phase
+	bijCopy.Status.Message = message
+	switch phase {
+	case mlv1.BatchInferenceJobPhaseSucceeded:
+		mlv1.Ready.True(bijCopy)
+	case mlv1.BatchInferenceJobPhaseFailed:
+		mlv1.Ready.False(bijCopy)
+		mlv1.Ready.Reason(bijCopy, string(phase))
+	default:
+		mlv1.Ready.False(bijCopy)
+	}
+	mlv1.Ready.Message(bijCopy, message)
+	if phase == mlv1.BatchInferenceJobPhaseSucceeded || phase == mlv1.BatchInferenceJobPhaseFailed {
+		bijCopy.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	}
+
+	if reflect.DeepEqual(bij.Status, bijCopy.Status) {
+		return bij, nil
+	}
+	return h.BatchInferenceJobs.UpdateStatus(bijCopy)
+}
+
+// getTerminationMessage returns the termination message of the last failed pod of the job
+func (h *handler) getTerminationMessage(job *batchv1.Job) (string, error) {
+	pods, err := h.PodCache.List(job.Namespace, labels.SelectorFromSet(map[string]string{
+		batchv1.JobNameLabel: job.Name,
+	}))
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods of job %s/%s: %w", job.Namespace, job.Name, err)
+	}
+
+	var message string
+	var finishedAt time.Time
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			terminated := cs.State.Terminated
+			if terminated == nil || terminated.ExitCode == 0 || terminated.Message == "" ||
+				terminated.FinishedAt.Time.Before(finishedAt) {
+				continue
+			}
+			message, finishedAt = terminated.Message, terminated.FinishedAt.Time
+		}
+	}
+	return message, nil
+}
+
+func isFinished(bij *mlv1.BatchInferenceJob) bool {
+	return bij.Status.Phase == mlv1.BatchInferenceJobPhaseSucceeded ||
+		bij.Status.Phase == mlv1.BatchInferenceJobPhaseFailed
+}
+
+func isJobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func getJobConditionMessage(job *batchv1.Job, conditionType batchv1.JobConditionType) string {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType {
+			return c.Message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package batchinferencejob
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/modelservice"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	jobPrefix                   = "batchinferencejob"
+	batchInferenceContainerName = "batch-inference"
+	workVolumeName              = "work"
+	workMountPath               = "/work"
+	llmosModeEnvName            = "LLMOS_MODE"
+	batchInferenceMode          = "batchinference"
+
+	// the written shards are skipped by the retried pods, so that the job is resumed from the last written shard
+	backoffLimit = 3
+)
+
+// constructJob builds the batch inference job, the container reads the spec of the batch inference job and
+// reports the progress to its status
+func constructJob(bij *mlv1.BatchInferenceJob, ms *mlv1.ModelService) *batchv1.Job {
+	args := []string{
+		fmt.Sprintf("--name=%s/%s", bij.Namespace, bij.Name),
+		fmt.Sprintf("--endpoint=%s", modelservice.GetServiceEndpoint(ms)),
+		fmt.Sprintf("--work-dir=%s", workMountPath),
+	}
+	if ms.Spec.ServedModelName != "" {
+		args = append(args, fmt.Sprintf("--model=%s", ms.Spec.ServedModelName))
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getJobName(bij.Name),
+			Namespace: bij.Namespace,
+			Labels: map[string]string{
+				constant.LabelBatchInferenceJobName: bij.Name,
+				constant.LabelModelServiceName:      ms.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(bij, mlv1.SchemeGroupVersion.WithKind("BatchInferenceJob")),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(backoffLimit)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						constant.LabelBatchInferenceJobName: bij.Name,
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: snapshotting.DownloaderServiceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:  batchInferenceContainerName,
+							Image: settings.ModelDownloaderImage.Get(),
+							Env:   []corev1.EnvVar{{Name: llmosModeEnvName, Value: batchInferenceMode}},
+							Args:  args,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: workVolumeName, MountPath: workMountPath},
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Volumes: []corev1.Volume{
+						{Name: workVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getJobName(name string) string {
+	return fmt.Sprintf("%s-%s", jobPrefix, name)
+}
+
+// getOutputDatasetVersionName returns the name of the output DatasetVersion
+func getOutputDatasetVersionName(bij *mlv1.BatchInferenceJob) string {
+	return fmt.Sprintf("%s-%s", bij.Spec.Output.Dataset, bij.Spec.Output.Version)
+}
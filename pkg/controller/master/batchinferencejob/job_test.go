@@ -0,0 +1,89 @@
+package batchinferencejob
+
+import (
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+)
+
+func TestConstructJob(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		servedModelName string
+		expectedArgs    []string
+	}{
+		{
+			name: "default served model",
+			expectedArgs: []string{
+				"--name=default/summarize",
+				"--endpoint=http://modelservice-qwen.default.svc:8000",
+				"--work-dir=/work",
+			},
+		},
+		{
+			name:            "served model name",
+			servedModelName: "qwen2.5",
+			expectedArgs: []string{
+				"--name=default/summarize",
+				"--endpoint=http://modelservice-qwen.default.svc:8000",
+				"--work-dir=/work",
+				"--model=qwen2.5",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		bij := &mlv1.BatchInferenceJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "summarize", Namespace: "default"},
+			Spec: mlv1.BatchInferenceJobSpec{
+				ModelService: "qwen",
+				Input:        mlv1.BatchInferenceInput{DatasetVersion: "news-v1", File: "train.jsonl"},
+				Output:       mlv1.BatchInferenceOutput{Dataset: "news", Version: "summaries"},
+			},
+		}
+		ms := &mlv1.ModelService{
+			ObjectMeta: metav1.ObjectMeta{Name: "qwen", Namespace: "default"},
+			Spec:       mlv1.ModelServiceSpec{ServedModelName: tc.servedModelName},
+		}
+
+		job := constructJob(bij, ms)
+		if job.Name != "batchinferencejob-summarize" || job.Labels[constant.LabelBatchInferenceJobName] != "summarize" {
+			t.Errorf("%s: unexpected job name %s or labels %v", tc.name, job.Name, job.Labels)
+		}
+		if job.Spec.BackoffLimit == nil || *job.Spec.BackoffLimit != backoffLimit {
+			t.Errorf("%s: expected backoff limit %d to resume the job, got %v", tc.name, backoffLimit,
+				job.Spec.BackoffLimit)
+		}
+		if len(job.OwnerReferences) != 1 || job.OwnerReferences[0].Kind != "BatchInferenceJob" {
+			t.Errorf("%s: expected the job to be owned by the batch inference job, got %v", tc.name,
+				job.OwnerReferences)
+		}
+
+		containers := job.Spec.Template.Spec.Containers
+		if len(containers) != 1 {
+			t.Fatalf("%s: expected 1 container, got %d", tc.name, len(containers))
+		}
+		if !slices.Equal(containers[0].Args, tc.expectedArgs) {
+			t.Errorf("%s: expected args %v, got %v", tc.name, tc.expectedArgs, containers[0].Args)
+		}
+		if !slices.Contains(containers[0].Env, corev1.EnvVar{Name: llmosModeEnvName, Value: batchInferenceMode}) {
+			t.Errorf("%s: expected the batch inference mode, got env %v", tc.name, containers[0].Env)
+		}
+	}
+}
+
+func TestGetOutputDatasetVersionName(t *testing.T) {
+	bij := &mlv1.BatchInferenceJob{
+		Spec: mlv1.BatchInferenceJobSpec{
+			Output: mlv1.BatchInferenceOutput{Dataset: "news", Version: "summaries"},
+		},
+	}
+	if name := getOutputDatasetVersionName(bij); name != "news-summaries" {
+		t.Errorf("Expected output dataset version name news-summaries, got %s", name)
+	}
+}
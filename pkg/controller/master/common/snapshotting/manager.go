@@ -8,11 +8,9 @@ import (
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	ctlbatchv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
 	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
-	ctlrbacv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
 	"github.com/sirupsen/logrus"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,49 +26,37 @@ const (
 	// SnapshotManagerLabel is used to identify resources managed by the snapshotting manager
 	SnapshotManagerLabel = "llmos.ai/snapshotting-manager"
 	SnapshotManagerValue = "true"
-	// VolumeSnapshotClassName is the fixed volume snapshot class name
-	volumeSnapshotClassName = "llmos-ceph-block-snapshot-class"
-	// StorageClassName is the fixed storage class name
-	storageClassName = "llmos-ceph-block"
-	// ServiceAccountName is the fixed service account name
-	serviceAccountName = "llmos-operator-downloader"
-	// ClusterRoleBindingName is the fixed cluster role binding name
-	clusterRoleBindingName = "llmos-operator"
 )
 
 type Manager struct {
-	JobClient                ctlbatchv1.JobClient
-	JobCache                 ctlbatchv1.JobCache
-	PVCClient                ctlcorev1.PersistentVolumeClaimClient
-	PVCCache                 ctlcorev1.PersistentVolumeClaimCache
-	VolumeSnapshotClient     ctlsnapshotv1.VolumeSnapshotClient
-	VolumeSnapshotCache      ctlsnapshotv1.VolumeSnapshotCache
-	ServiceAccountClient     ctlcorev1.ServiceAccountClient
-	ServiceAccountCache      ctlcorev1.ServiceAccountCache
-	ClusterRoleBindingClient ctlrbacv1.ClusterRoleBindingClient
-	ClusterRoleBindingCache  ctlrbacv1.ClusterRoleBindingCache
-
+	JobClient            ctlbatchv1.JobClient
+	JobCache             ctlbatchv1.JobCache
+	PVCClient            ctlcorev1.PersistentVolumeClaimClient
+	PVCCache             ctlcorev1.PersistentVolumeClaimCache
+	VolumeSnapshotClient ctlsnapshotv1.VolumeSnapshotClient
+	VolumeSnapshotCache  ctlsnapshotv1.VolumeSnapshotCache
+	DownloaderAccess     *DownloaderAccess
+
+	StorageResolver *StorageResolver
 	ResourceHandler ResourceHandler
 }
 
 func NewManager(mgmt *config.Management, resourceHandler ResourceHandler) (*Manager, error) {
 	jobs := mgmt.BatchFactory.Batch().V1().Job()
 	pvcs := mgmt.CoreFactory.Core().V1().PersistentVolumeClaim()
-	serviceAccounts := mgmt.CoreFactory.Core().V1().ServiceAccount()
-	clusterRoleBindings := mgmt.RbacFactory.Rbac().V1().ClusterRoleBinding()
 	volumeSnapshots := mgmt.SnapshotFactory.Snapshot().V1().VolumeSnapshot()
+	storageResolver := NewStorageResolver(mgmt.StorageFactory.Storage().V1().StorageClass().Cache(),
+		mgmt.SnapshotFactory.Snapshot().V1().VolumeSnapshotClass().Cache())
 	m := &Manager{
-		JobClient:                jobs,
-		JobCache:                 jobs.Cache(),
-		PVCClient:                pvcs,
-		PVCCache:                 pvcs.Cache(),
-		VolumeSnapshotClient:     volumeSnapshots,
-		VolumeSnapshotCache:      volumeSnapshots.Cache(),
-		ServiceAccountClient:     serviceAccounts,
-		ServiceAccountCache:      serviceAccounts.Cache(),
-		ClusterRoleBindingClient: clusterRoleBindings,
-		ClusterRoleBindingCache:  clusterRoleBindings.Cache(),
-		ResourceHandler:          resourceHandler,
+		JobClient:            jobs,
+		JobCache:             jobs.Cache(),
+		PVCClient:            pvcs,
+		PVCCache:             pvcs.Cache(),
+		VolumeSnapshotClient: volumeSnapshots,
+		VolumeSnapshotCache:  volumeSnapshots.Cache(),
+		DownloaderAccess:     NewDownloaderAccess(mgmt),
+		StorageResolver:      storageResolver,
+		ResourceHandler:      resourceHandler,
 	}
 
 	resourceType := resourceHandler.GetResourceType()
@@ -270,6 +256,10 @@ func (m *Manager) createPVC(ctx context.Context, spec *Spec) error {
 	if err != nil {
 		return fmt.Errorf("calculate pvc size: %w", err)
 	}
+	sc, err := m.StorageResolver.GetStorageClass()
+	if err != nil {
+		return err
+	}
 
 	// Create PVC
 	pvc := &corev1.PersistentVolumeClaim{
@@ -280,7 +270,7 @@ func (m *Manager) createPVC(ctx context.Context, spec *Spec) error {
 			OwnerReferences: spec.OwnerReferences,
 		},
 		Spec: corev1.PersistentVolumeClaimSpec{
-			StorageClassName: ptr.To(storageClassName),
+			StorageClassName: ptr.To(sc.Name),
 			AccessModes:      spec.PVCSpec.AccessModes,
 			Resources: corev1.VolumeResourceRequirements{
 				Requests: corev1.ResourceList{
@@ -369,7 +359,7 @@ func (m *Manager) createJob(spec *Spec) error {
 			TTLSecondsAfterFinished: ptr.To(int32(0)),
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					ServiceAccountName: serviceAccountName,
+					ServiceAccountName: DownloaderServiceAccountName,
 					RestartPolicy:      corev1.RestartPolicyNever,
 					Containers: []corev1.Container{
 						{
@@ -420,6 +410,20 @@ func (m *Manager) createVolumeSnapshot(spec *Spec) error {
 
 	logrus.Debugf("Creating VolumeSnapshot %s/%s", spec.Namespace, spec.Name)
 
+	// The volume snapshot class must match the CSI driver of the snapshotting PVC
+	pvc, err := m.PVCCache.Get(spec.Namespace, spec.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get pvc %s/%s: %w", spec.Namespace, spec.Name, err)
+	}
+	sc, err := m.StorageResolver.GetStorageClassOfPVC(pvc)
+	if err != nil {
+		return err
+	}
+	vsc, err := m.StorageResolver.GetVolumeSnapshotClass(sc)
+	if err != nil {
+		return err
+	}
+
 	// Prepare labels with snapshotting manager label and resource type
 	labels := make(map[string]string)
 	for k, v := range spec.Labels {
@@ -437,7 +441,7 @@ func (m *Manager) createVolumeSnapshot(spec *Spec) error {
 			OwnerReferences: spec.OwnerReferences,
 		},
 		Spec: snapshotv1.VolumeSnapshotSpec{
-			VolumeSnapshotClassName: ptr.To(volumeSnapshotClassName),
+			VolumeSnapshotClassName: ptr.To(vsc.Name),
 			Source: snapshotv1.VolumeSnapshotSource{
 				PersistentVolumeClaimName: ptr.To(spec.Name),
 			},
@@ -453,66 +457,9 @@ func (m *Manager) createVolumeSnapshot(spec *Spec) error {
 	return nil
 }
 
-// ensureServiceAccountAndRoleBinding ensures the service account and cluster role binding exist
+// ensureServiceAccountAndRoleBinding ensures the service account and its role bindings exist
 func (m *Manager) ensureServiceAccountAndRoleBinding(namespace string) error {
-	// Check if ServiceAccount exists
-	_, err := m.ServiceAccountCache.Get(namespace, serviceAccountName)
-	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("failed to get service account %s/%s: %w", namespace, serviceAccountName, err)
-	} else if errors.IsNotFound(err) {
-		// Create ServiceAccount
-		sa := &corev1.ServiceAccount{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: namespace,
-				Name:      serviceAccountName,
-				Labels: map[string]string{
-					SnapshotManagerLabel: SnapshotManagerValue,
-				},
-			},
-		}
-
-		_, err = m.ServiceAccountClient.Create(sa)
-		if err != nil && !errors.IsAlreadyExists(err) {
-			return fmt.Errorf("failed to create service account %s/%s: %w", namespace, serviceAccountName, err)
-		}
-		logrus.Debugf("Created ServiceAccount %s/%s", namespace, serviceAccountName)
-	}
-
-	// Check if ClusterRoleBinding exists
-	_, err = m.ClusterRoleBindingCache.Get(clusterRoleBindingName)
-	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("failed to get cluster role binding %s: %w", clusterRoleBindingName, err)
-	} else if errors.IsNotFound(err) {
-		// Create ClusterRoleBinding
-		crb := &rbacv1.ClusterRoleBinding{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: clusterRoleBindingName,
-				Labels: map[string]string{
-					SnapshotManagerLabel: SnapshotManagerValue,
-				},
-			},
-			Subjects: []rbacv1.Subject{
-				{
-					Kind:      "ServiceAccount",
-					Name:      serviceAccountName,
-					Namespace: namespace,
-				},
-			},
-			RoleRef: rbacv1.RoleRef{
-				APIGroup: "rbac.authorization.k8s.io",
-				Kind:     "ClusterRole",
-				Name:     "llmos-operator-registry-reader",
-			},
-		}
-
-		_, err = m.ClusterRoleBindingClient.Create(crb)
-		if err != nil && !errors.IsAlreadyExists(err) {
-			return fmt.Errorf("failed to create cluster role binding %s: %w", clusterRoleBindingName, err)
-		}
-		logrus.Debugf("Created ClusterRoleBinding %s", clusterRoleBindingName)
-	}
-
-	return nil
+	return m.DownloaderAccess.Ensure(namespace)
 }
 
 func calculatePVCSize(size int64) (string, error) {
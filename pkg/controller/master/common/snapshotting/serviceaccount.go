@@ -0,0 +1,334 @@
+package snapshotting
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	ctlrbacv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+const (
+	// DownloaderServiceAccountName is the fixed service account name used to download files from registries
+	DownloaderServiceAccountName = "llmos-operator-downloader"
+	// registryReaderClusterRoleName is the cluster role allowing to read the registries, models and dataset versions
+	registryReaderClusterRoleName = "llmos-operator-registry-reader"
+	// registryCredentialsRoleName is the role of the system namespace allowing to read the registry credentials
+	registryCredentialsRoleName = "llmos-operator-registry-credentials"
+	// downloaderRoleName is the role of the downloader in its own namespace
+	downloaderRoleName = "llmos-operator-downloader"
+	// legacyClusterRoleBindingName is the cluster role binding shared by the downloaders of all namespaces,
+	// which is replaced by the bindings of each namespace
+	legacyClusterRoleBindingName = "llmos-operator"
+)
+
+// downloaderRules are the rules of the downloader in its own namespace, the jobs report their progress to the status
+// of the resources they run for
+var downloaderRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{mlv1.SchemeGroupVersion.Group},
+		Resources: []string{"finetunejobs", "batchinferencejobs"},
+		Verbs:     []string{"get"},
+	},
+	{
+		APIGroups: []string{mlv1.SchemeGroupVersion.Group},
+		Resources: []string{"finetunejobs/status", "batchinferencejobs/status"},
+		Verbs:     []string{"update"},
+	},
+	{
+		APIGroups: []string{agentv1.SchemeGroupVersion.Group},
+		Resources: []string{"datacollections"},
+		Verbs:     []string{"get"},
+	},
+	{
+		APIGroups: []string{agentv1.SchemeGroupVersion.Group},
+		Resources: []string{"datacollections/status"},
+		Verbs:     []string{"update"},
+	},
+}
+
+// DownloaderAccess grants the downloader service account of a namespace the access it needs, which is reading the
+// registries, models and dataset versions, and only the credential secrets of the registries
+type DownloaderAccess struct {
+	NamespaceCache           ctlcorev1.NamespaceCache
+	ServiceAccountClient     ctlcorev1.ServiceAccountClient
+	ServiceAccountCache      ctlcorev1.ServiceAccountCache
+	RoleClient               ctlrbacv1.RoleClient
+	RoleCache                ctlrbacv1.RoleCache
+	RoleBindingClient        ctlrbacv1.RoleBindingClient
+	RoleBindingCache         ctlrbacv1.RoleBindingCache
+	ClusterRoleBindingClient ctlrbacv1.ClusterRoleBindingClient
+	ClusterRoleBindingCache  ctlrbacv1.ClusterRoleBindingCache
+	RegistryCache            ctlmlv1.RegistryCache
+}
+
+func NewDownloaderAccess(mgmt *config.Management) *DownloaderAccess {
+	serviceAccounts := mgmt.CoreFactory.Core().V1().ServiceAccount()
+	roles := mgmt.RbacFactory.Rbac().V1().Role()
+	roleBindings := mgmt.RbacFactory.Rbac().V1().RoleBinding()
+	clusterRoleBindings := mgmt.RbacFactory.Rbac().V1().ClusterRoleBinding()
+	return &DownloaderAccess{
+		NamespaceCache:           mgmt.CoreFactory.Core().V1().Namespace().Cache(),
+		ServiceAccountClient:     serviceAccounts,
+		ServiceAccountCache:      serviceAccounts.Cache(),
+		RoleClient:               roles,
+		RoleCache:                roles.Cache(),
+		RoleBindingClient:        roleBindings,
+		RoleBindingCache:         roleBindings.Cache(),
+		ClusterRoleBindingClient: clusterRoleBindings,
+		ClusterRoleBindingCache:  clusterRoleBindings.Cache(),
+		RegistryCache:            mgmt.LLMFactory.Ml().V1().Registry().Cache(),
+	}
+}
+
+// Ensure ensures the downloader service account exists in the namespace and is bound to the registry reader
+// cluster role, the registry credentials role and the downloader role of the namespace, the bindings out of the
+// namespace are owned by the namespace to be deleted with it
+func (d *DownloaderAccess) Ensure(namespace string) error {
+	ns, err := d.NamespaceCache.Get(namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+
+	if err = d.ensureServiceAccount(namespace); err != nil {
+		return err
+	}
+	if err = d.removeLegacyClusterRoleBinding(); err != nil {
+		return err
+	}
+
+	subject := rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      DownloaderServiceAccountName,
+		Namespace: namespace,
+	}
+	owner := metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Namespace",
+		Name:       ns.Name,
+		UID:        ns.UID,
+	}
+	bindingName := fmt.Sprintf("%s-%s", DownloaderServiceAccountName, namespace)
+
+	if err = d.ensureClusterRoleBinding(&rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            bindingName,
+			Labels:          map[string]string{SnapshotManagerLabel: SnapshotManagerValue},
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Subjects: []rbacv1.Subject{subject},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     registryReaderClusterRoleName,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err = d.ensureRole(&rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      downloaderRoleName,
+			Namespace: namespace,
+			Labels:    map[string]string{SnapshotManagerLabel: SnapshotManagerValue},
+		},
+		Rules: downloaderRules,
+	}); err != nil {
+		return err
+	}
+	if err = d.ensureRoleBinding(&rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      downloaderRoleName,
+			Namespace: namespace,
+			Labels:    map[string]string{SnapshotManagerLabel: SnapshotManagerValue},
+		},
+		Subjects: []rbacv1.Subject{subject},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     downloaderRoleName,
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err = d.EnsureRegistryCredentialsRole(); err != nil {
+		return err
+	}
+	return d.ensureRoleBinding(&rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            bindingName,
+			Namespace:       constant.SystemNamespaceName,
+			Labels:          map[string]string{SnapshotManagerLabel: SnapshotManagerValue},
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Subjects: []rbacv1.Subject{subject},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     registryCredentialsRoleName,
+		},
+	})
+}
+
+func (d *DownloaderAccess) ensureServiceAccount(namespace string) error {
+	_, err := d.ServiceAccountCache.Get(namespace, DownloaderServiceAccountName)
+	if err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get service account %s/%s: %w", namespace, DownloaderServiceAccountName, err)
+	}
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      DownloaderServiceAccountName,
+			Labels: map[string]string{
+				SnapshotManagerLabel: SnapshotManagerValue,
+			},
+		},
+	}
+	if _, err = d.ServiceAccountClient.Create(sa); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create service account %s/%s: %w", namespace, DownloaderServiceAccountName, err)
+	}
+	logrus.Debugf("Created ServiceAccount %s/%s", namespace, DownloaderServiceAccountName)
+	return nil
+}
+
+// removeLegacyClusterRoleBinding deletes the shared cluster role binding of the previous versions, which bound the
+// downloaders of all namespaces to the secrets of the cluster
+func (d *DownloaderAccess) removeLegacyClusterRoleBinding() error {
+	crb, err := d.ClusterRoleBindingCache.Get(legacyClusterRoleBindingName)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get cluster role binding %s: %w", legacyClusterRoleBindingName, err)
+	}
+	if crb.Labels[SnapshotManagerLabel] != SnapshotManagerValue {
+		return nil
+	}
+
+	if err = d.ClusterRoleBindingClient.Delete(crb.Name, &metav1.DeleteOptions{}); err != nil &&
+		!errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete cluster role binding %s: %w", crb.Name, err)
+	}
+	logrus.Infof("Deleted the shared downloader ClusterRoleBinding %s", crb.Name)
+	return nil
+}
+
+func (d *DownloaderAccess) ensureClusterRoleBinding(crb *rbacv1.ClusterRoleBinding) error {
+	_, err := d.ClusterRoleBindingCache.Get(crb.Name)
+	if err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get cluster role binding %s: %w", crb.Name, err)
+	}
+
+	if _, err = d.ClusterRoleBindingClient.Create(crb); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create cluster role binding %s: %w", crb.Name, err)
+	}
+	logrus.Debugf("Created ClusterRoleBinding %s", crb.Name)
+	return nil
+}
+
+func (d *DownloaderAccess) ensureRoleBinding(rb *rbacv1.RoleBinding) error {
+	_, err := d.RoleBindingCache.Get(rb.Namespace, rb.Name)
+	if err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get role binding %s/%s: %w", rb.Namespace, rb.Name, err)
+	}
+
+	if _, err = d.RoleBindingClient.Create(rb); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create role binding %s/%s: %w", rb.Namespace, rb.Name, err)
+	}
+	logrus.Debugf("Created RoleBinding %s/%s", rb.Namespace, rb.Name)
+	return nil
+}
+
+// EnsureRegistryCredentialsRole ensures the role of the system namespace only allows to read the credential secrets
+// of the registries, and the public key to verify the signed dataset versions
+func (d *DownloaderAccess) EnsureRegistryCredentialsRole() error {
+	registries, err := d.RegistryCache.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list registries: %w", err)
+	}
+
+	return d.ensureRole(&rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      registryCredentialsRoleName,
+			Namespace: constant.SystemNamespaceName,
+			Labels:    map[string]string{SnapshotManagerLabel: SnapshotManagerValue},
+		},
+		Rules: registryCredentialsRules(registries),
+	})
+}
+
+// ensureRole creates the role or updates its rules if they are changed
+func (d *DownloaderAccess) ensureRole(role *rbacv1.Role) error {
+	found, err := d.RoleCache.Get(role.Namespace, role.Name)
+	if err != nil && errors.IsNotFound(err) {
+		if _, err = d.RoleClient.Create(role); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create role %s/%s: %w", role.Namespace, role.Name, err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get role %s/%s: %w", role.Namespace, role.Name, err)
+	}
+
+	if reflect.DeepEqual(found.Rules, role.Rules) {
+		return nil
+	}
+	toUpdate := found.DeepCopy()
+	toUpdate.Rules = role.Rules
+	if _, err = d.RoleClient.Update(toUpdate); err != nil {
+		return fmt.Errorf("failed to update role %s/%s: %w", role.Namespace, role.Name, err)
+	}
+	return nil
+}
+
+func registryCredentialsRules(registries []*mlv1.Registry) []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"configmaps"},
+			ResourceNames: []string{constant.DatasetSigningPublicKeyConfigMapName},
+			Verbs:         []string{"get"},
+		},
+	}
+
+	secretNames := make([]string, 0, len(registries))
+	seen := make(map[string]bool, len(registries))
+	for _, registry := range registries {
+		name := registry.Spec.S3Config.AccessCredentialSecretName
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		secretNames = append(secretNames, name)
+	}
+	// an empty resourceNames matches all secrets, so the rule is only added for the named secrets
+	if len(secretNames) > 0 {
+		sort.Strings(secretNames)
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: secretNames,
+			Verbs:         []string{"get"},
+		})
+	}
+	return rules
+}
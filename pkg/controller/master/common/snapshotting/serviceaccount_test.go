@@ -0,0 +1,40 @@
+package snapshotting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+)
+
+func TestRegistryCredentialsRules(t *testing.T) {
+	newRegistry := func(secretName string) *mlv1.Registry {
+		return &mlv1.Registry{
+			Spec: mlv1.RegistrySpec{S3Config: mlv1.S3Config{AccessCredentialSecretName: secretName}},
+		}
+	}
+	configMapRule := rbacv1.PolicyRule{
+		APIGroups:     []string{""},
+		Resources:     []string{"configmaps"},
+		ResourceNames: []string{constant.DatasetSigningPublicKeyConfigMapName},
+		Verbs:         []string{"get"},
+	}
+
+	// no secret is allowed without the registries, since an empty resourceNames matches all secrets
+	assert.Equal(t, []rbacv1.PolicyRule{configMapRule}, registryCredentialsRules(nil))
+
+	rules := registryCredentialsRules([]*mlv1.Registry{newRegistry("s3-b"), newRegistry("s3-a"),
+		newRegistry("s3-b"), newRegistry("")})
+	assert.Equal(t, []rbacv1.PolicyRule{
+		configMapRule,
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: []string{"s3-a", "s3-b"},
+			Verbs:         []string{"get"},
+		},
+	}, rules)
+}
@@ -0,0 +1,145 @@
+package snapshotting
+
+import (
+	"fmt"
+	"sort"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	ctlsnapshotv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/snapshot.storage.k8s.io/v1"
+	ctlstoragev1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/storage.k8s.io/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	annotationDefaultStorageClass  = "storageclass.kubernetes.io/is-default-class"
+	annotationDefaultSnapshotClass = "snapshot.storage.kubernetes.io/is-default-class"
+)
+
+// StorageResolver resolves the storage class and the volume snapshot class of the snapshotting volumes.
+// The classes are specified by the settings, or discovered from the cluster if the settings are empty:
+//   - the default storage class is used if its CSI driver supports volume snapshots, otherwise the first
+//     storage class in name order whose CSI driver supports volume snapshots is used;
+//   - the default volume snapshot class of the CSI driver is used, otherwise the first one in name order.
+type StorageResolver struct {
+	storageClassCache  ctlstoragev1.StorageClassCache
+	snapshotClassCache ctlsnapshotv1.VolumeSnapshotClassCache
+}
+
+func NewStorageResolver(storageClassCache ctlstoragev1.StorageClassCache,
+	snapshotClassCache ctlsnapshotv1.VolumeSnapshotClassCache) *StorageResolver {
+	return &StorageResolver{
+		storageClassCache:  storageClassCache,
+		snapshotClassCache: snapshotClassCache,
+	}
+}
+
+// GetStorageClass returns the storage class of the snapshotting and the snapshot-restored volumes
+func (r *StorageResolver) GetStorageClass() (*storagev1.StorageClass, error) {
+	if name := settings.SnapshotStorageClass.Get(); name != "" {
+		sc, err := r.storageClassCache.Get(name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("storage class %s specified by setting %s not found", name,
+					settings.SnapshotStorageClassName)
+			}
+			return nil, fmt.Errorf("failed to get storage class %s: %w", name, err)
+		}
+		return sc, nil
+	}
+
+	storageClasses, err := r.storageClassCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage classes: %w", err)
+	}
+	snapshotClasses, err := r.snapshotClassCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume snapshot classes: %w", err)
+	}
+	drivers := make(map[string]bool, len(snapshotClasses))
+	for _, vsc := range snapshotClasses {
+		drivers[vsc.Driver] = true
+	}
+
+	sort.Slice(storageClasses, func(i, j int) bool {
+		iDefault := storageClasses[i].Annotations[annotationDefaultStorageClass] == "true"
+		jDefault := storageClasses[j].Annotations[annotationDefaultStorageClass] == "true"
+		if iDefault != jDefault {
+			return iDefault
+		}
+		return storageClasses[i].Name < storageClasses[j].Name
+	})
+	for _, sc := range storageClasses {
+		if drivers[sc.Provisioner] {
+			return sc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no storage class supporting volume snapshots found, please enable system storage "+
+		"or specify the storage class by setting %s", settings.SnapshotStorageClassName)
+}
+
+// GetStorageClassOfPVC returns the storage class of the PVC, or the resolved one if the PVC has no storage class
+func (r *StorageResolver) GetStorageClassOfPVC(pvc *corev1.PersistentVolumeClaim) (*storagev1.StorageClass, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return r.GetStorageClass()
+	}
+	sc, err := r.storageClassCache.Get(*pvc.Spec.StorageClassName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage class %s of pvc %s/%s: %w", *pvc.Spec.StorageClassName,
+			pvc.Namespace, pvc.Name, err)
+	}
+	return sc, nil
+}
+
+// GetVolumeSnapshotClass returns the volume snapshot class of the CSI driver of the storage class
+func (r *StorageResolver) GetVolumeSnapshotClass(sc *storagev1.StorageClass) (*snapshotv1.VolumeSnapshotClass, error) {
+	if name := settings.VolumeSnapshotClass.Get(); name != "" {
+		vsc, err := r.snapshotClassCache.Get(name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("volume snapshot class %s specified by setting %s not found", name,
+					settings.VolumeSnapshotClassName)
+			}
+			return nil, fmt.Errorf("failed to get volume snapshot class %s: %w", name, err)
+		}
+		return vsc, nil
+	}
+
+	snapshotClasses, err := r.snapshotClassCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume snapshot classes: %w", err)
+	}
+
+	var found *snapshotv1.VolumeSnapshotClass
+	for _, vsc := range snapshotClasses {
+		if vsc.Driver != sc.Provisioner {
+			continue
+		}
+		if vsc.Annotations[annotationDefaultSnapshotClass] == "true" {
+			return vsc, nil
+		}
+		if found == nil || vsc.Name < found.Name {
+			found = vsc
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no volume snapshot class found for the CSI driver %s of storage class %s",
+			sc.Provisioner, sc.Name)
+	}
+	return found, nil
+}
+
+// GetRestoreAccessMode returns the access mode of the volumes restored from the snapshots, ReadOnlyMany allows
+// a snapshot to be mounted by many pods if the CSI driver supports it.
+func GetRestoreAccessMode() corev1.PersistentVolumeAccessMode {
+	if mode := corev1.PersistentVolumeAccessMode(settings.SnapshotRestoreAccessMode.Get()); mode ==
+		corev1.ReadOnlyMany {
+		return mode
+	}
+	return corev1.ReadWriteOnce
+}
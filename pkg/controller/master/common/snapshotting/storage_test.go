@@ -0,0 +1,172 @@
+package snapshotting
+
+import (
+	"testing"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/llmos-ai/llmos-operator/pkg/generated/clientset/versioned/fake"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+	"github.com/llmos-ai/llmos-operator/pkg/utils/fakeclients"
+)
+
+func newStorageClass(name, provisioner string, isDefault bool) *storagev1.StorageClass {
+	sc := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: name},
+		Provisioner: provisioner,
+	}
+	if isDefault {
+		sc.Annotations = map[string]string{annotationDefaultStorageClass: "true"}
+	}
+	return sc
+}
+
+func newSnapshotClass(name, driver string, isDefault bool) *snapshotv1.VolumeSnapshotClass {
+	vsc := &snapshotv1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Driver:     driver,
+	}
+	if isDefault {
+		vsc.Annotations = map[string]string{annotationDefaultSnapshotClass: "true"}
+	}
+	return vsc
+}
+
+func TestStorageResolver(t *testing.T) {
+	var testCases = []struct {
+		name                  string
+		storageClasses        []runtime.Object
+		snapshotClasses       []runtime.Object
+		storageClassSetting   string
+		snapshotClassSetting  string
+		expectedStorageClass  string
+		expectedSnapshotClass string
+		expectedErr           bool
+	}{
+		{
+			name: "default storage class supporting snapshots",
+			storageClasses: []runtime.Object{
+				newStorageClass("ceph-block", "rbd.csi.ceph.com", false),
+				newStorageClass("local-path", "rancher.io/local-path", false),
+				newStorageClass("longhorn", "driver.longhorn.io", true),
+			},
+			snapshotClasses: []runtime.Object{
+				newSnapshotClass("ceph-block-snapshot", "rbd.csi.ceph.com", false),
+				newSnapshotClass("longhorn-snapshot", "driver.longhorn.io", false),
+			},
+			expectedStorageClass:  "longhorn",
+			expectedSnapshotClass: "longhorn-snapshot",
+		},
+		{
+			name: "default storage class not supporting snapshots",
+			storageClasses: []runtime.Object{
+				newStorageClass("local-path", "rancher.io/local-path", true),
+				newStorageClass("longhorn", "driver.longhorn.io", false),
+				newStorageClass("ceph-block", "rbd.csi.ceph.com", false),
+			},
+			snapshotClasses: []runtime.Object{
+				newSnapshotClass("ceph-block-snapshot-b", "rbd.csi.ceph.com", false),
+				newSnapshotClass("ceph-block-snapshot-a", "rbd.csi.ceph.com", false),
+				newSnapshotClass("longhorn-snapshot", "driver.longhorn.io", false),
+			},
+			expectedStorageClass:  "ceph-block",
+			expectedSnapshotClass: "ceph-block-snapshot-a",
+		},
+		{
+			name: "default snapshot class of the driver",
+			storageClasses: []runtime.Object{
+				newStorageClass("ceph-block", "rbd.csi.ceph.com", true),
+			},
+			snapshotClasses: []runtime.Object{
+				newSnapshotClass("ceph-block-snapshot-a", "rbd.csi.ceph.com", false),
+				newSnapshotClass("ceph-block-snapshot-b", "rbd.csi.ceph.com", true),
+			},
+			expectedStorageClass:  "ceph-block",
+			expectedSnapshotClass: "ceph-block-snapshot-b",
+		},
+		{
+			name: "classes specified by settings",
+			storageClasses: []runtime.Object{
+				newStorageClass("ceph-block", "rbd.csi.ceph.com", true),
+				newStorageClass("ceph-block-retain", "rbd.csi.ceph.com", false),
+			},
+			snapshotClasses: []runtime.Object{
+				newSnapshotClass("ceph-block-snapshot", "rbd.csi.ceph.com", true),
+				newSnapshotClass("ceph-block-snapshot-retain", "rbd.csi.ceph.com", false),
+			},
+			storageClassSetting:   "ceph-block-retain",
+			snapshotClassSetting:  "ceph-block-snapshot-retain",
+			expectedStorageClass:  "ceph-block-retain",
+			expectedSnapshotClass: "ceph-block-snapshot-retain",
+		},
+		{
+			name: "storage class specified by setting not found",
+			storageClasses: []runtime.Object{
+				newStorageClass("ceph-block", "rbd.csi.ceph.com", true),
+			},
+			storageClassSetting: "not-found",
+			expectedErr:         true,
+		},
+		{
+			name: "no storage class supporting snapshots",
+			storageClasses: []runtime.Object{
+				newStorageClass("local-path", "rancher.io/local-path", true),
+			},
+			snapshotClasses: []runtime.Object{
+				newSnapshotClass("ceph-block-snapshot", "rbd.csi.ceph.com", false),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Nil(t, settings.SnapshotStorageClass.Set(tc.storageClassSetting))
+		assert.Nil(t, settings.VolumeSnapshotClass.Set(tc.snapshotClassSetting))
+
+		k8sClient := k8sfake.NewSimpleClientset(tc.storageClasses...)
+		fakeClient := fake.NewSimpleClientset(tc.snapshotClasses...)
+		r := NewStorageResolver(fakeclients.StorageClassCache(k8sClient.StorageV1().StorageClasses),
+			fakeclients.VolumeSnapshotClassCache(fakeClient.SnapshotV1().VolumeSnapshotClasses))
+
+		sc, err := r.GetStorageClass()
+		if tc.expectedErr {
+			assert.NotNil(t, err, "case %q", tc.name)
+			continue
+		}
+		if !assert.Nil(t, err, "case %q", tc.name) {
+			continue
+		}
+		assert.Equal(t, tc.expectedStorageClass, sc.Name, "case %q", tc.name)
+
+		vsc, err := r.GetVolumeSnapshotClass(sc)
+		if assert.Nil(t, err, "case %q", tc.name) {
+			assert.Equal(t, tc.expectedSnapshotClass, vsc.Name, "case %q", tc.name)
+		}
+	}
+
+	assert.Nil(t, settings.SnapshotStorageClass.Set(""))
+	assert.Nil(t, settings.VolumeSnapshotClass.Set(""))
+}
+
+func TestGetRestoreAccessMode(t *testing.T) {
+	var testCases = []struct {
+		setting  string
+		expected corev1.PersistentVolumeAccessMode
+	}{
+		{setting: "ReadWriteOnce", expected: corev1.ReadWriteOnce},
+		{setting: "ReadOnlyMany", expected: corev1.ReadOnlyMany},
+		{setting: "invalid", expected: corev1.ReadWriteOnce},
+	}
+
+	for _, tc := range testCases {
+		assert.Nil(t, settings.SnapshotRestoreAccessMode.Set(tc.setting))
+		assert.Equal(t, tc.expected, GetRestoreAccessMode(), "setting %q", tc.setting)
+	}
+	assert.Nil(t, settings.SnapshotRestoreAccessMode.Set("ReadWriteOnce"))
+}
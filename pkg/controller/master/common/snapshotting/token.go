@@ -0,0 +1,61 @@
+package snapshotting
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// DownloaderTokenVolumeName is the projected token volume of the downloader service account
+	DownloaderTokenVolumeName = "downloader-token"
+	serviceAccountTokenPath   = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+// DownloaderTokenVolume returns the projected volume of the service account token, it's mounted only into the
+// containers of the downloader when the pod runs the user images as the downloader service account with the
+// token auto-mounting disabled
+func DownloaderTokenVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: DownloaderTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Path:              "token",
+							ExpirationSeconds: ptr.To(int64(3607)),
+						},
+					},
+					{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"},
+							Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+						},
+					},
+					{
+						DownwardAPI: &corev1.DownwardAPIProjection{
+							Items: []corev1.DownwardAPIVolumeFile{
+								{
+									Path: "namespace",
+									FieldRef: &corev1.ObjectFieldSelector{
+										APIVersion: "v1",
+										FieldPath:  "metadata.namespace",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// DownloaderTokenVolumeMount mounts the downloader token to the default path of the service account token
+func DownloaderTokenVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      DownloaderTokenVolumeName,
+		MountPath: serviceAccountTokenPath,
+		ReadOnly:  true,
+	}
+}
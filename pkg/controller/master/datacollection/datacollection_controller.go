@@ -5,10 +5,16 @@ import (
 	"fmt"
 	"path"
 	"reflect"
+	"time"
 
 	ctlbatchv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
 	ctlagentv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/agent.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/registry"
 	"github.com/llmos-ai/llmos-operator/pkg/server/config"
@@ -17,10 +23,13 @@ import (
 type handler struct {
 	ctx context.Context
 
-	dataCollectionClient ctlagentv1.DataCollectionClient
-	jobClient            ctlbatchv1.JobClient
+	dataCollectionClient     ctlagentv1.DataCollectionClient
+	dataCollectionController ctlagentv1.DataCollectionController
+	jobClient                ctlbatchv1.JobClient
+	jobCache                 ctlbatchv1.JobCache
 
-	rm *registry.Manager
+	rm               *registry.Manager
+	downloaderAccess *snapshotting.DownloaderAccess
 }
 
 func Register(_ context.Context, mgmt *config.Management, _ config.Options) error {
@@ -32,13 +41,17 @@ func Register(_ context.Context, mgmt *config.Management, _ config.Options) erro
 	h := handler{
 		ctx: mgmt.Ctx,
 
-		dataCollectionClient: datacollections,
-		jobClient:            jobs,
+		dataCollectionClient:     datacollections,
+		dataCollectionController: datacollections,
+		jobClient:                jobs,
+		jobCache:                 jobs.Cache(),
+		downloaderAccess:         snapshotting.NewDownloaderAccess(mgmt),
 	}
 	h.rm = registry.NewManager(secrets.Cache().Get, registries.Cache().Get)
 
 	datacollections.OnChange(mgmt.Ctx, "datacollection.OnChange", h.OnChange)
 	datacollections.OnRemove(mgmt.Ctx, "datacollection.OnRemove", h.OnRemove)
+	jobs.OnChange(mgmt.Ctx, "datacollection.OnJobChange", h.OnJobChange)
 
 	return nil
 }
@@ -49,11 +62,45 @@ func (h *handler) OnChange(_ string, ad *agentv1.DataCollection) (*agentv1.DataC
 		return ad, nil
 	}
 
+	jobName := getJobName(ad.Name)
+	job, err := h.jobCache.Get(ad.Namespace, jobName)
+	switch {
+	case err == nil && !isJobFinished(job):
+		// the data collection is enqueued by the job once it's finished
+		return ad, nil
+	case err == nil:
+		// the results reported by the job may not be in the cache yet
+		if ad, err = h.dataCollectionClient.Get(ad.Namespace, ad.Name, metav1.GetOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to get data collection %s/%s: %w", ad.Namespace, ad.Name, err)
+		}
+	case errors.IsNotFound(err):
+		job = nil
+	default:
+		return ad, fmt.Errorf("failed to get job %s/%s: %w", ad.Namespace, jobName, err)
+	}
+
 	adCopy := ad.DeepCopy()
-	err := h.ensureRootPath(adCopy)
+	err = h.ensureRootPath(adCopy)
+	if err == nil {
+		var next time.Duration
+		if next, err = h.syncSources(adCopy, job); next > 0 {
+			h.dataCollectionController.EnqueueAfter(ad.Namespace, ad.Name, next)
+		}
+	}
 	return h.updateDataCollectionStatus(adCopy, ad, err)
 }
 
+// OnJobChange enqueues the data collection once its sync job is finished
+func (h *handler) OnJobChange(_ string, job *batchv1.Job) (*batchv1.Job, error) {
+	if job == nil || job.DeletionTimestamp != nil || job.Labels[constant.LabelDataCollectionName] == "" {
+		return job, nil
+	}
+	if isJobFinished(job) {
+		h.dataCollectionController.Enqueue(job.Namespace, job.Labels[constant.LabelDataCollectionName])
+	}
+	return job, nil
+}
+
 func (h *handler) OnRemove(_ string, dc *agentv1.DataCollection) (*agentv1.DataCollection, error) {
 	if dc == nil || dc.DeletionTimestamp == nil {
 		return dc, nil
@@ -0,0 +1,116 @@
+package datacollection
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	"github.com/llmos-ai/llmos-operator/pkg/datacollection"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	jobPrefix              = "datacollection"
+	syncContainerName      = "sync"
+	credentialsMountPath   = "/credentials"
+	llmosModeEnvName       = "LLMOS_MODE"
+	dataCollectionMode     = "datacollection"
+	credentialVolumePrefix = "credential-"
+)
+
+// constructJob builds the job syncing the due sources, the container reads the spec of the data collection and
+// records the result of each source in its status. The credential secrets are mounted by the source names, so
+// that the job doesn't need to read the secrets of the namespace.
+func constructJob(dc *agentv1.DataCollection, due []agentv1.DataSource) *batchv1.Job {
+	names := make([]string, 0, len(due))
+	volumes := make([]corev1.Volume, 0)
+	mounts := make([]corev1.VolumeMount, 0)
+	for _, source := range due {
+		names = append(names, source.Name)
+
+		secretName := ""
+		switch {
+		case source.Git != nil:
+			secretName = source.Git.CredentialSecret
+		case source.S3 != nil:
+			secretName = source.S3.CredentialSecret
+		}
+		if secretName == "" {
+			continue
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name:         credentialVolumePrefix + source.Name,
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secretName}},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      credentialVolumePrefix + source.Name,
+			MountPath: path.Join(credentialsMountPath, source.Name),
+			ReadOnly:  true,
+		})
+	}
+
+	args := []string{
+		fmt.Sprintf("--name=%s/%s", dc.Namespace, dc.Name),
+		fmt.Sprintf("--sources=%s", strings.Join(names, ",")),
+		fmt.Sprintf("--credentials-dir=%s", credentialsMountPath),
+	}
+	// the private addresses are rejected unless they're allowed by the admin
+	if cidrs := settings.DataCollectionAllowedCIDRs.Get(); cidrs != "" {
+		args = append(args, fmt.Sprintf("--allowed-cidrs=%s", cidrs))
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getJobName(dc.Name),
+			Namespace: dc.Namespace,
+			Labels: map[string]string{
+				constant.LabelDataCollectionName: dc.Name,
+			},
+			Annotations: map[string]string{
+				constant.AnnotationDataCollectionSources: strings.Join(names, ","),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(dc, agentv1.SchemeGroupVersion.WithKind("DataCollection")),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			// the failed sources are retried by the controller after the retry interval
+			BackoffLimit:          ptr.To(int32(0)),
+			ActiveDeadlineSeconds: ptr.To(int64(datacollection.SyncTimeout.Seconds()) * int64(len(due))),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						constant.LabelDataCollectionName: dc.Name,
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: snapshotting.DownloaderServiceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:                     syncContainerName,
+							Image:                    settings.ModelDownloaderImage.Get(),
+							Env:                      []corev1.EnvVar{{Name: llmosModeEnvName, Value: dataCollectionMode}},
+							Args:                     args,
+							VolumeMounts:             mounts,
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+func getJobName(name string) string {
+	return fmt.Sprintf("%s-%s", jobPrefix, name)
+}
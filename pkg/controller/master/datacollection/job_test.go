@@ -0,0 +1,62 @@
+package datacollection
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+)
+
+func TestConstructJob(t *testing.T) {
+	dc := &agentv1.DataCollection{
+		ObjectMeta: metav1.ObjectMeta{Name: "docs", Namespace: "default"},
+	}
+	due := []agentv1.DataSource{
+		{Name: "site", URL: &agentv1.URLSource{Sitemap: "https://example.com/sitemap.xml"}},
+		{Name: "repo", Git: &agentv1.GitSource{Repository: "https://example.com/repo.git", CredentialSecret: "git"}},
+	}
+
+	job := constructJob(dc, due)
+	if job.Name != "datacollection-docs" || job.Labels[constant.LabelDataCollectionName] != "docs" {
+		t.Errorf("unexpected job name %s or labels %v", job.Name, job.Labels)
+	}
+	if sources := job.Annotations[constant.AnnotationDataCollectionSources]; sources != "site,repo" {
+		t.Errorf("expected the sources site,repo, got %s", sources)
+	}
+
+	pod := job.Spec.Template.Spec
+	if pod.ServiceAccountName != snapshotting.DownloaderServiceAccountName {
+		t.Errorf("expected the downloader service account, got %s", pod.ServiceAccountName)
+	}
+	expectedArgs := []string{"--name=default/docs", "--sources=site,repo", "--credentials-dir=/credentials"}
+	if args := pod.Containers[0].Args; !slices.Equal(args, expectedArgs) {
+		t.Errorf("expected args %v, got %v", expectedArgs, args)
+	}
+
+	// only the credential secrets of the due sources are mounted
+	if len(pod.Volumes) != 1 || pod.Volumes[0].Secret == nil || pod.Volumes[0].Secret.SecretName != "git" {
+		t.Fatalf("expected the credential secret git to be mounted, got %+v", pod.Volumes)
+	}
+	if mounts := pod.Containers[0].VolumeMounts; len(mounts) != 1 || mounts[0].MountPath != "/credentials/repo" {
+		t.Errorf("expected the credential secret to be mounted at /credentials/repo, got %+v", mounts)
+	}
+}
+
+func TestStartSync(t *testing.T) {
+	now := metav1.Now()
+	last := &metav1.Time{Time: now.Add(-time.Minute)}
+	status := &agentv1.DataSourceStatus{Name: "repo", ObservedGeneration: 1, LastSyncTime: last, Revision: "abc",
+		ETags: map[string]string{"a": "b"}, Message: "failed", ChangedFiles: 3}
+
+	// the revision of the last spec is dropped to sync all files
+	startSync(status, 2, now.Time)
+	if status.ObservedGeneration != 2 || status.Revision != "" || status.ETags != nil || status.Message != "" ||
+		status.ChangedFiles != 0 || status.LastSyncTime != last || !status.LastAttemptTime.Equal(&now) {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
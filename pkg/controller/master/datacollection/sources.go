@@ -0,0 +1,249 @@
+package datacollection
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/datacollection"
+)
+
+const (
+	// failed syncs are retried after the interval instead of waiting for the next schedule
+	retryInterval = 5 * time.Minute
+)
+
+// syncSources records the result of the finished sync job, deletes the files of the removed sources and starts a
+// sync job of the due sources, and returns the duration until the next sync. The sources are fetched by the job
+// running as the downloader service account of the namespace instead of the operator.
+func (h *handler) syncSources(dc *agentv1.DataCollection, job *batchv1.Job) (time.Duration, error) {
+	if len(dc.Spec.Sources) == 0 && len(dc.Status.Sources) == 0 && job == nil {
+		return 0, nil
+	}
+
+	b, err := h.rm.NewBackendFromRegistry(h.ctx, dc.Spec.Registry)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create backend from registry %s: %w", dc.Spec.Registry, err)
+	}
+
+	now := time.Now()
+	filesChanged := false
+	if job != nil {
+		if filesChanged, err = h.finishJob(dc, job); err != nil {
+			return 0, err
+		}
+	}
+
+	// delete the files of the removed sources
+	statuses := make([]agentv1.DataSourceStatus, 0, len(dc.Spec.Sources))
+	for _, status := range dc.Status.Sources {
+		if containsSource(dc.Spec.Sources, status.Name) {
+			continue
+		}
+		dir := path.Join(dc.Status.RootPath, status.Name)
+		if err := b.Delete(h.ctx, dir); err != nil {
+			return 0, fmt.Errorf("failed to delete directory %s of removed source %s: %w", dir, status.Name, err)
+		}
+		filesChanged = true
+	}
+
+	due := make([]agentv1.DataSource, 0)
+	for _, source := range dc.Spec.Sources {
+		status := getSourceStatus(dc, source.Name)
+		if ok, _ := isSourceDue(source, status, dc.Generation, now); ok {
+			startSync(status, dc.Generation, now)
+			due = append(due, source)
+		} else {
+			setNextSyncTime(source, status, dc.Generation, now)
+		}
+		statuses = append(statuses, *status)
+	}
+	dc.Status.Sources = statuses
+
+	if filesChanged {
+		files, err := datacollection.ListFiles(h.ctx, b, dc.Status.RootPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list files in %s from registry %s: %w", dc.Status.RootPath,
+				dc.Spec.Registry, err)
+		}
+		dc.Status.Files = files
+	}
+
+	if len(due) > 0 {
+		// the attempts are recorded before the job is created, so that the results reported by the job aren't
+		// overwritten
+		if err = h.startJob(dc, due); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	return nextSyncDuration(statuses, now), nil
+}
+
+// finishJob records the failure of the sources which aren't reported by the failed job, deletes the finished job,
+// and returns whether any file is changed by the job
+func (h *handler) finishJob(dc *agentv1.DataCollection, job *batchv1.Job) (bool, error) {
+	failed := isJobConditionTrue(job, batchv1.JobFailed)
+	filesChanged := false
+	for _, name := range strings.Split(job.Annotations[constant.AnnotationDataCollectionSources], ",") {
+		for i := range dc.Status.Sources {
+			status := &dc.Status.Sources[i]
+			if status.Name != name {
+				continue
+			}
+			filesChanged = filesChanged || status.ChangedFiles > 0
+			// the sources whose result isn't reported by the job
+			if failed && status.Message == "" && (status.LastSyncTime == nil ||
+				status.LastSyncTime.Before(status.LastAttemptTime)) {
+				status.Message = fmt.Sprintf("sync job %s failed: %s", job.Name,
+					getJobConditionMessage(job, batchv1.JobFailed))
+			}
+		}
+	}
+
+	logrus.Infof("deleting finished sync job %s/%s of data collection %s", job.Namespace, job.Name, dc.Name)
+	if err := h.jobClient.Delete(job.Namespace, job.Name, &metav1.DeleteOptions{
+		PropagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+	}); err != nil && !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to delete job %s/%s: %w", job.Namespace, job.Name, err)
+	}
+	return filesChanged, nil
+}
+
+// startJob records the attempts of the due sources and creates the job syncing them
+func (h *handler) startJob(dc *agentv1.DataCollection, due []agentv1.DataSource) error {
+	updated, err := h.dataCollectionClient.UpdateStatus(dc)
+	if err != nil {
+		return fmt.Errorf("failed to record the sync attempts: %w", err)
+	}
+	*dc = *updated.DeepCopy()
+
+	// the job reads the spec and the credential secrets of the namespace as the downloader service account
+	if err = h.downloaderAccess.Ensure(dc.Namespace); err != nil {
+		return err
+	}
+	logrus.Infof("creating sync job of data collection %s/%s for %d sources", dc.Namespace, dc.Name, len(due))
+	if _, err = h.jobClient.Create(constructJob(dc, due)); err != nil {
+		err = fmt.Errorf("failed to create job %s/%s: %w", dc.Namespace, getJobName(dc.Name), err)
+		// the sources are retried after the retry interval
+		for i := range dc.Status.Sources {
+			if containsSource(due, dc.Status.Sources[i].Name) {
+				dc.Status.Sources[i].Message = err.Error()
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// startSync records the attempt of the source, the revision and ETags of the last sync are dropped after the
+// spec is changed to sync all files
+func startSync(status *agentv1.DataSourceStatus, generation int64, now time.Time) {
+	if status.ObservedGeneration != generation {
+		*status = agentv1.DataSourceStatus{Name: status.Name, LastSyncTime: status.LastSyncTime}
+	}
+	status.ObservedGeneration = generation
+	status.LastAttemptTime = &metav1.Time{Time: now}
+	status.ChangedFiles = 0
+	status.Message = ""
+	status.NextSyncTime = nil
+}
+
+func setNextSyncTime(source agentv1.DataSource, status *agentv1.DataSourceStatus, generation int64,
+	now time.Time) {
+	status.NextSyncTime = nil
+	if _, next := isSourceDue(source, status, generation, now); !next.IsZero() {
+		status.NextSyncTime = &metav1.Time{Time: next}
+	}
+}
+
+func nextSyncDuration(statuses []agentv1.DataSourceStatus, now time.Time) time.Duration {
+	var next time.Duration
+	for i := range statuses {
+		if statuses[i].NextSyncTime == nil {
+			continue
+		}
+		d := max(statuses[i].NextSyncTime.Sub(now), time.Second)
+		if next == 0 || d < next {
+			next = d
+		}
+	}
+	return next
+}
+
+// isSourceDue returns whether the source should be synced now and the time of its next sync, which is zero if
+// the source is only synced when it's changed
+func isSourceDue(source agentv1.DataSource, status *agentv1.DataSourceStatus, generation int64,
+	now time.Time) (bool, time.Time) {
+	if status.LastAttemptTime == nil || status.ObservedGeneration != generation {
+		return true, now
+	}
+
+	var next time.Time
+	if status.Message != "" {
+		next = status.LastAttemptTime.Add(retryInterval)
+	}
+	if source.Schedule != "" {
+		schedule, err := cron.ParseStandard(source.Schedule)
+		if err != nil {
+			// the schedule is validated by the webhook
+			logrus.Warnf("invalid schedule %q of source %s: %v", source.Schedule, source.Name, err)
+		} else if t := schedule.Next(status.LastAttemptTime.UTC()); next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+
+	return !next.IsZero() && !now.Before(next), next
+}
+
+func getSourceStatus(dc *agentv1.DataCollection, name string) *agentv1.DataSourceStatus {
+	for i := range dc.Status.Sources {
+		if dc.Status.Sources[i].Name == name {
+			return dc.Status.Sources[i].DeepCopy()
+		}
+	}
+	return &agentv1.DataSourceStatus{Name: name}
+}
+
+func containsSource(sources []agentv1.DataSource, name string) bool {
+	for _, source := range sources {
+		if source.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	return isJobConditionTrue(job, batchv1.JobComplete) || isJobConditionTrue(job, batchv1.JobFailed)
+}
+
+func isJobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func getJobConditionMessage(job *batchv1.Job, conditionType batchv1.JobConditionType) string {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType {
+			return c.Message
+		}
+	}
+	return ""
+}
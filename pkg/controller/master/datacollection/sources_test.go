@@ -0,0 +1,80 @@
+package datacollection
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+)
+
+func TestIsSourceDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(d)}
+	}
+
+	var testCases = []struct {
+		name         string
+		schedule     string
+		status       agentv1.DataSourceStatus
+		expectedDue  bool
+		expectedNext time.Time
+	}{
+		{
+			name:         "never synced",
+			status:       agentv1.DataSourceStatus{},
+			expectedDue:  true,
+			expectedNext: now,
+		},
+		{
+			name:         "spec changed",
+			status:       agentv1.DataSourceStatus{ObservedGeneration: 1, LastAttemptTime: at(-time.Minute)},
+			expectedDue:  true,
+			expectedNext: now,
+		},
+		{
+			name:   "synced without schedule",
+			status: agentv1.DataSourceStatus{ObservedGeneration: 2, LastAttemptTime: at(-time.Hour)},
+		},
+		{
+			name:         "scheduled later",
+			schedule:     "0 * * * *",
+			status:       agentv1.DataSourceStatus{ObservedGeneration: 2, LastAttemptTime: at(-10 * time.Minute)},
+			expectedNext: time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "schedule missed",
+			schedule:     "0 * * * *",
+			status:       agentv1.DataSourceStatus{ObservedGeneration: 2, LastAttemptTime: at(-time.Hour)},
+			expectedDue:  true,
+			expectedNext: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "failed sync retried before the schedule",
+			schedule: "0 * * * *",
+			status: agentv1.DataSourceStatus{ObservedGeneration: 2, LastAttemptTime: at(-time.Minute),
+				Message: "failed"},
+			expectedNext: now.Add(4 * time.Minute),
+		},
+		{
+			name: "failed sync without schedule",
+			status: agentv1.DataSourceStatus{ObservedGeneration: 2, LastAttemptTime: at(-10 * time.Minute),
+				Message: "failed"},
+			expectedDue:  true,
+			expectedNext: now.Add(-5 * time.Minute),
+		},
+	}
+
+	for _, tc := range testCases {
+		source := agentv1.DataSource{Name: "docs", Schedule: tc.schedule}
+		due, next := isSourceDue(source, &tc.status, 2, now)
+		if due != tc.expectedDue {
+			t.Errorf("%s: expected due %v, got %v", tc.name, tc.expectedDue, due)
+		}
+		if !next.Equal(tc.expectedNext) {
+			t.Errorf("%s: expected next sync time %v, got %v", tc.name, tc.expectedNext, next)
+		}
+	}
+}
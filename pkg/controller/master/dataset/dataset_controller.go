@@ -6,6 +6,7 @@ import (
 	"path"
 	"reflect"
 
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	"github.com/sirupsen/logrus"
 
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
@@ -20,6 +21,7 @@ const (
 	datasetOnRemoveName        = "dataset.OnRemove"
 	datasetVersionOnChangeName = "datasetversion.OnChange"
 	datasetVersionOnRemoveName = "datasetversion.OnRemove"
+	datasetVersionInspectName  = "datasetversion.inspect"
 
 	volumeName      = "dataset-volume"
 	volumeMountPath = "/data/"
@@ -32,6 +34,10 @@ type handler struct {
 	datasetCache         ctlmlv1.DatasetCache
 	datasetVersionClient ctlmlv1.DatasetVersionClient
 	datasetVersionCache  ctlmlv1.DatasetVersionCache
+	secretClient         ctlcorev1.SecretClient
+	secretCache          ctlcorev1.SecretCache
+	configMapClient      ctlcorev1.ConfigMapClient
+	configMapCache       ctlcorev1.ConfigMapCache
 
 	rm              *registry.Manager
 	snapshotManager *snapshotting.Manager
@@ -42,6 +48,7 @@ func Register(_ context.Context, mgmt *config.Management, _ config.Options) erro
 	secrets := mgmt.CoreFactory.Core().V1().Secret()
 	datasets := mgmt.LLMFactory.Ml().V1().Dataset()
 	datasetVersions := mgmt.LLMFactory.Ml().V1().DatasetVersion()
+	configMaps := mgmt.CoreFactory.Core().V1().ConfigMap()
 
 	h := handler{
 		ctx: mgmt.Ctx,
@@ -50,6 +57,10 @@ func Register(_ context.Context, mgmt *config.Management, _ config.Options) erro
 		datasetCache:         datasets.Cache(),
 		datasetVersionClient: datasetVersions,
 		datasetVersionCache:  datasetVersions.Cache(),
+		secretClient:         secrets,
+		secretCache:          secrets.Cache(),
+		configMapClient:      configMaps,
+		configMapCache:       configMaps.Cache(),
 	}
 	h.rm = registry.NewManager(secrets.Cache().Get, registries.Cache().Get)
 
@@ -64,6 +75,7 @@ func Register(_ context.Context, mgmt *config.Management, _ config.Options) erro
 	datasets.OnRemove(mgmt.Ctx, datasetOnRemoveName, h.OnRemoveDataset)
 	datasetVersions.OnChange(mgmt.Ctx, datasetVersionOnChangeName, h.OnChangeDatasetVersion)
 	datasetVersions.OnRemove(mgmt.Ctx, datasetVersionOnRemoveName, h.OnRemoveDatasetVersion)
+	datasetVersions.OnChange(mgmt.Ctx, datasetVersionInspectName, h.OnInspectDatasetVersion)
 	return nil
 }
 
@@ -67,6 +67,13 @@ func (h *handler) OnChangeDatasetVersion(_ string, dv *mlv1.DatasetVersion) (*ml
 
 	// Handle publish functionality
 	if dv.Spec.Publish && mlv1.Ready.IsTrue(dv) {
+		// freeze the files with the manifest before snapshotting, so that the downloader can verify them
+		if dv.Status.Manifest == nil {
+			if err := h.generateManifest(dvCopy); err != nil {
+				return h.updateDatasetVersionStatus(dvCopy, dv, fmt.Errorf("generate manifest failed: %w", err))
+			}
+			return h.updateDatasetVersionStatus(dvCopy, dv, nil)
+		}
 		if err := h.handlePublish(dvCopy); err != nil {
 			return h.updateDatasetVersionStatus(dvCopy, dv, fmt.Errorf("publish failed: %w", err))
 		}
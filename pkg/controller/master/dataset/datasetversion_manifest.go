@@ -0,0 +1,130 @@
+package dataset
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	datasetschema "github.com/llmos-ai/llmos-operator/pkg/dataset"
+	"github.com/llmos-ai/llmos-operator/pkg/registry"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+// generateManifest records the hashes of the files of the dataset version to freeze it, the manifest is signed
+// with the cluster key if signing is enabled
+func (h *handler) generateManifest(dv *mlv1.DatasetVersion) error {
+	logrus.Infof("generate manifest of dataset version %s/%s", dv.Namespace, dv.Name)
+
+	b, err := h.rm.NewBackendFromRegistry(h.ctx, dv.Status.Registry)
+	if err != nil {
+		return fmt.Errorf(registry.ErrCreateBackendClient, err)
+	}
+	files, err := b.List(h.ctx, dv.Status.RootPath, true, true)
+	if err != nil {
+		return fmt.Errorf("list files of dataset version %s/%s failed: %w", dv.Namespace, dv.Name, err)
+	}
+	manifest, err := datasetschema.BuildManifest(h.ctx, b, dv.Namespace, dv.Name, dv.Status.RootPath, files)
+	if err != nil {
+		return err
+	}
+
+	if settings.DatasetVersionSigningEnabled.Get() == constant.TrueStr {
+		key, err := h.getSigningKey()
+		if err != nil {
+			return fmt.Errorf("get signing key failed: %w", err)
+		}
+		datasetschema.SignManifest(manifest, key)
+	}
+
+	dv.Status.Manifest = manifest
+	return nil
+}
+
+// getSigningKey returns the cluster signing key, the key is generated at the first time and its public key is
+// shared by the configmap in the system namespace
+func (h *handler) getSigningKey() (ed25519.PrivateKey, error) {
+	namespace, name := constant.SystemNamespaceName, constant.DatasetSigningKeySecretName
+	secret, err := h.secretCache.Get(namespace, name)
+	if errors.IsNotFound(err) {
+		secret, err = h.createSigningKey()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get secret %s/%s failed: %w", namespace, name, err)
+	}
+
+	key, err := datasetschema.ParsePrivateKey(secret.Data[datasetschema.PrivateKeyName])
+	if err != nil {
+		return nil, fmt.Errorf("parse signing key %s/%s failed: %w", namespace, name, err)
+	}
+	if err = h.syncPublicKey(key.Public().(ed25519.PublicKey)); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (h *handler) createSigningKey() (*corev1.Secret, error) {
+	namespace, name := constant.SystemNamespaceName, constant.DatasetSigningKeySecretName
+	privateKey, err := datasetschema.GenerateSigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := h.secretClient.Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			datasetschema.PrivateKeyName: privateKey,
+		},
+	})
+	// the key may be created by another worker
+	if errors.IsAlreadyExists(err) {
+		return h.secretClient.Get(namespace, name, metav1.GetOptions{})
+	} else if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("generated the signing key %s/%s of the published dataset versions", namespace, name)
+	return secret, nil
+}
+
+// syncPublicKey shares the public key of the signing key by the configmap
+func (h *handler) syncPublicKey(publicKey ed25519.PublicKey) error {
+	namespace, name := constant.SystemNamespaceName, constant.DatasetSigningPublicKeyConfigMapName
+	data, err := datasetschema.EncodePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	cm, err := h.configMapCache.Get(namespace, name)
+	switch {
+	case errors.IsNotFound(err):
+		_, err = h.configMapClient.Create(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Data: map[string]string{
+				datasetschema.PublicKeyName: string(data),
+			},
+		})
+	case err == nil && cm.Data[datasetschema.PublicKeyName] != string(data):
+		cmCopy := cm.DeepCopy()
+		if cmCopy.Data == nil {
+			cmCopy.Data = make(map[string]string)
+		}
+		cmCopy.Data[datasetschema.PublicKeyName] = string(data)
+		_, err = h.configMapClient.Update(cmCopy)
+	}
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("share public key by configmap %s/%s failed: %w", namespace, name, err)
+	}
+	return nil
+}
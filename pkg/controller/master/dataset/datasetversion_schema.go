@@ -0,0 +1,98 @@
+package dataset
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	datasetschema "github.com/llmos-ai/llmos-operator/pkg/dataset"
+	"github.com/llmos-ai/llmos-operator/pkg/registry"
+)
+
+// OnInspectDatasetVersion infers the schema and statistics of the dataset version once its files are changed,
+// and fills the card of the dataset if it's the latest version.
+func (h *handler) OnInspectDatasetVersion(_ string, dv *mlv1.DatasetVersion) (*mlv1.DatasetVersion, error) {
+	if dv == nil || dv.DeletionTimestamp != nil || !mlv1.Ready.IsTrue(dv) || dv.Status.RootPath == "" {
+		return dv, nil
+	}
+
+	b, err := h.rm.NewBackendFromRegistry(h.ctx, dv.Status.Registry)
+	if err != nil {
+		return dv, fmt.Errorf(registry.ErrCreateBackendClient, err)
+	}
+	files, err := b.List(h.ctx, dv.Status.RootPath, true, true)
+	if err != nil {
+		return dv, fmt.Errorf("list files of dataset version %s/%s failed: %w", dv.Namespace, dv.Name, err)
+	}
+
+	fingerprint := datasetschema.Fingerprint(files)
+	if dv.Status.Schema != nil && dv.Status.Schema.Fingerprint == fingerprint {
+		return dv, h.syncDatasetCard(dv)
+	}
+
+	logrus.Infof("inspect files of dataset version %s/%s", dv.Namespace, dv.Name)
+	schema, err := datasetschema.Inspect(h.ctx, b, dv.Status.RootPath, files)
+	if err != nil {
+		// keep the fingerprint to avoid inspecting the same files again
+		logrus.Warnf("inspect dataset version %s/%s failed: %v", dv.Namespace, dv.Name, err)
+		now := metav1.Now()
+		schema = &mlv1.DatasetSchema{Fingerprint: fingerprint, InspectTime: &now, Message: err.Error()}
+	}
+
+	dvCopy := dv.DeepCopy()
+	dvCopy.Status.Schema = schema
+	updated, err := h.datasetVersionClient.UpdateStatus(dvCopy)
+	if err != nil {
+		return dv, fmt.Errorf("update schema of dataset version %s/%s failed: %w", dv.Namespace, dv.Name, err)
+	}
+
+	return updated, h.syncDatasetCard(updated)
+}
+
+// syncDatasetCard fills the features, splits and number of samples of the dataset card from the schema of
+// the latest dataset version
+func (h *handler) syncDatasetCard(dv *mlv1.DatasetVersion) error {
+	schema := dv.Status.Schema
+	if schema == nil || schema.Message != "" {
+		return nil
+	}
+
+	dataset, err := h.datasetCache.Get(dv.Namespace, dv.Spec.Dataset)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("get dataset %s/%s failed: %w", dv.Namespace, dv.Spec.Dataset, err)
+	}
+	versions := dataset.Status.Versions
+	if len(versions) == 0 || versions[len(versions)-1].ObjectName != dv.Name {
+		return nil
+	}
+
+	datasetCopy := dataset.DeepCopy()
+	if datasetCopy.Spec.Card == nil {
+		datasetCopy.Spec.Card = &mlv1.DatasetCard{}
+	}
+	metadata := &datasetCopy.Spec.Card.MetaData
+	metadata.Features = make([]string, 0, len(schema.Features))
+	for _, f := range schema.Features {
+		metadata.Features = append(metadata.Features, f.Name)
+	}
+	metadata.SplitTypes = make([]string, 0, len(schema.Splits))
+	for _, s := range schema.Splits {
+		metadata.SplitTypes = append(metadata.SplitTypes, s.Name)
+	}
+	metadata.NumSamples = int(schema.NumRows)
+
+	if reflect.DeepEqual(datasetCopy.Spec, dataset.Spec) {
+		return nil
+	}
+	if _, err = h.datasetClient.Update(datasetCopy); err != nil {
+		return fmt.Errorf("update card of dataset %s/%s failed: %w", dataset.Namespace, dataset.Name, err)
+	}
+	return nil
+}
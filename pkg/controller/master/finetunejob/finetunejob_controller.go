@@ -0,0 +1,354 @@
+package finetunejob
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	ctlbatchv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	ctlsnapshotv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/snapshot.storage.k8s.io/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+const (
+	fineTuneJobOnChange = "fineTuneJob.onChange"
+	ftjJobOnChange      = "fineTuneJob.jobOnChange"
+
+	requeueInterval = 10 * time.Second
+
+	fineTunedTag = "fine-tuned"
+	adapterTag   = "adapter"
+)
+
+type handler struct {
+	FineTuneJobs        ctlmlv1.FineTuneJobController
+	FineTuneJobCache    ctlmlv1.FineTuneJobCache
+	Models              ctlmlv1.ModelClient
+	ModelCache          ctlmlv1.ModelCache
+	DatasetVersionCache ctlmlv1.DatasetVersionCache
+	VolumeSnapshotCache ctlsnapshotv1.VolumeSnapshotCache
+	Jobs                ctlbatchv1.JobClient
+	JobCache            ctlbatchv1.JobCache
+	PodCache            ctlcorev1.PodCache
+
+	DownloaderAccess *snapshotting.DownloaderAccess
+
+	StorageResolver *snapshotting.StorageResolver
+}
+
+func Register(ctx context.Context, mgmt *config.Management, _ config.Options) error {
+	fineTuneJobs := mgmt.LLMFactory.Ml().V1().FineTuneJob()
+	models := mgmt.LLMFactory.Ml().V1().Model()
+	jobs := mgmt.BatchFactory.Batch().V1().Job()
+
+	h := &handler{
+		FineTuneJobs:        fineTuneJobs,
+		FineTuneJobCache:    fineTuneJobs.Cache(),
+		Models:              models,
+		ModelCache:          models.Cache(),
+		DatasetVersionCache: mgmt.LLMFactory.Ml().V1().DatasetVersion().Cache(),
+		VolumeSnapshotCache: mgmt.SnapshotFactory.Snapshot().V1().VolumeSnapshot().Cache(),
+		Jobs:                jobs,
+		JobCache:            jobs.Cache(),
+		PodCache:            mgmt.CoreFactory.Core().V1().Pod().Cache(),
+
+		DownloaderAccess: snapshotting.NewDownloaderAccess(mgmt),
+
+		StorageResolver: snapshotting.NewStorageResolver(mgmt.StorageFactory.Storage().V1().StorageClass().Cache(),
+			mgmt.SnapshotFactory.Snapshot().V1().VolumeSnapshotClass().Cache()),
+	}
+
+	fineTuneJobs.OnChange(ctx, fineTuneJobOnChange, h.OnChange)
+	jobs.OnChange(ctx, ftjJobOnChange, h.OnJobChange)
+	return nil
+}
+
+// OnChange creates the output model and starts the fine-tuning job once the base model, the dataset version
+// and the output model are ready
+func (h *handler) OnChange(_ string, ftj *mlv1.FineTuneJob) (*mlv1.FineTuneJob, error) {
+	if ftj == nil || ftj.DeletionTimestamp != nil || isFinished(ftj) {
+		return ftj, nil
+	}
+
+	jobName := getJobName(ftj.Name)
+	if _, err := h.JobCache.Get(ftj.Namespace, jobName); err == nil {
+		return ftj, nil
+	} else if !errors.IsNotFound(err) {
+		return ftj, fmt.Errorf("failed to get job %s/%s: %w", ftj.Namespace, jobName, err)
+	}
+
+	dv, err := h.DatasetVersionCache.Get(ftj.Namespace, ftj.Spec.DatasetVersion)
+	if err != nil && !errors.IsNotFound(err) {
+		return ftj, err
+	} else if err != nil {
+		return h.fail(ftj, fmt.Sprintf("dataset version %s not found", ftj.Spec.DatasetVersion))
+	}
+	if !dv.Spec.Publish || dv.Status.PublishStatus.Phase != mlv1.SnapshottingPhaseSnapshotReady ||
+		dv.Status.PublishStatus.SnapshotName == "" {
+		return h.pending(ftj, fmt.Sprintf("waiting for dataset version %s to be published", dv.Name))
+	}
+
+	if name := ftj.Spec.BaseModel.Model; name != "" {
+		baseModel, err := h.ModelCache.Get(ftj.Namespace, name)
+		if err != nil && !errors.IsNotFound(err) {
+			return ftj, err
+		} else if err != nil {
+			return h.fail(ftj, fmt.Sprintf("base model %s not found", name))
+		}
+		if !mlv1.Ready.IsTrue(baseModel) {
+			return h.pending(ftj, fmt.Sprintf("waiting for base model %s to be ready", name))
+		}
+	}
+
+	outputModel, err := h.ensureOutputModel(ftj, dv)
+	if err != nil {
+		return ftj, err
+	}
+	if outputModel == nil {
+		return h.fail(ftj, fmt.Sprintf("model %s already exists", ftj.Spec.Output.ModelName))
+	}
+	if ftj.Status.OutputModel != outputModel.Name || ftj.Status.Adapter != isAdapter(ftj) {
+		ftjCopy := ftj.DeepCopy()
+		ftjCopy.Status.OutputModel = outputModel.Name
+		ftjCopy.Status.Adapter = isAdapter(ftj)
+		if ftj, err = h.FineTuneJobs.UpdateStatus(ftjCopy); err != nil {
+			return ftj, err
+		}
+	}
+	if !mlv1.Ready.IsTrue(outputModel) {
+		return h.pending(ftj, fmt.Sprintf("waiting for output model %s to be ready", outputModel.Name))
+	}
+
+	snapshot, err := h.VolumeSnapshotCache.Get(dv.Namespace, dv.Status.PublishStatus.SnapshotName)
+	if err != nil {
+		return ftj, fmt.Errorf("failed to get volume snapshot %s/%s: %w", dv.Namespace,
+			dv.Status.PublishStatus.SnapshotName, err)
+	}
+	if snapshot.Status == nil || snapshot.Status.RestoreSize == nil {
+		return h.pending(ftj, fmt.Sprintf("waiting for volume snapshot %s to be ready", snapshot.Name))
+	}
+	sc, err := h.StorageResolver.GetStorageClass()
+	if err != nil {
+		return ftj, err
+	}
+
+	// the base model is downloaded and the output is uploaded by the downloader service account
+	if err = h.DownloaderAccess.Ensure(ftj.Namespace); err != nil {
+		return ftj, err
+	}
+
+	logrus.Infof("creating fine-tuning job of %s/%s", ftj.Namespace, ftj.Name)
+	if _, err = h.Jobs.Create(constructJob(ftj, sc.Name, snapshot)); err != nil && !errors.IsAlreadyExists(err) {
+		return ftj, fmt.Errorf("failed to create job %s/%s: %w", ftj.Namespace, jobName, err)
+	}
+
+	ftjCopy := ftj.DeepCopy()
+	ftjCopy.Status.JobName = jobName
+	ftjCopy.Status.StartTime = &metav1.Time{Time: time.Now()}
+	return h.updateStatus(ftjCopy, mlv1.FineTuneJobPhaseRunning, "fine-tuning is running")
+}
+
+// ensureOutputModel creates the output model of the fine-tuning job, nil is returned if the model exists and
+// isn't created by the job. The model records the dataset version it's trained on as its lineage.
+func (h *handler) ensureOutputModel(ftj *mlv1.FineTuneJob, dv *mlv1.DatasetVersion) (*mlv1.Model, error) {
+	name := ftj.Spec.Output.ModelName
+	model, err := h.ModelCache.Get(ftj.Namespace, name)
+	if err == nil {
+		if model.Labels[constant.LabelFineTuneJobName] != ftj.Name {
+			return nil, nil
+		}
+		return model, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get model %s/%s: %w", ftj.Namespace, name, err)
+	}
+
+	baseModel := ftj.Spec.BaseModel.Model
+	if baseModel == "" {
+		baseModel = ftj.Spec.BaseModel.HuggingFaceID
+	}
+	tags := []string{fineTunedTag, string(ftj.Spec.Method)}
+	if isAdapter(ftj) {
+		tags = append(tags, adapterTag)
+	}
+
+	model = &mlv1.Model{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ftj.Namespace,
+			Labels: map[string]string{
+				constant.LabelFineTuneJobName: ftj.Name,
+			},
+		},
+		Spec: mlv1.ModelSpec{
+			Registry: ftj.Spec.Output.Registry,
+			Card: &mlv1.ModelCard{
+				Description: fmt.Sprintf("%s fine-tuned from %s by fine-tuning job %s", name, baseModel, ftj.Name),
+				MetaData: mlv1.ModelMetaData{
+					Tags:      tags,
+					Datasets:  []string{dv.Spec.Dataset},
+					BaseModel: baseModel,
+				},
+			},
+			TrainedOn: []mlv1.DatasetVersionReference{
+				{
+					Namespace: dv.Namespace,
+					Dataset:   dv.Spec.Dataset,
+					Version:   dv.Spec.Version,
+				},
+			},
+		},
+	}
+
+	logrus.Infof("creating output model %s/%s of fine-tuning job %s", ftj.Namespace, name, ftj.Name)
+	model, err = h.Models.Create(model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create model %s/%s: %w", ftj.Namespace, name, err)
+	}
+	return model, nil
+}
+
+// OnJobChange records the result of the finished fine-tuning job
+func (h *handler) OnJobChange(_ string, job *batchv1.Job) (*batchv1.Job, error) {
+	if job == nil || job.DeletionTimestamp != nil || job.Labels[constant.LabelFineTuneJobName] == "" {
+		return job, nil
+	}
+
+	ftj, err := h.FineTuneJobCache.Get(job.Namespace, job.Labels[constant.LabelFineTuneJobName])
+	if err != nil && errors.IsNotFound(err) {
+		return job, nil
+	} else if err != nil {
+		return job, err
+	}
+	if isFinished(ftj) {
+		return job, nil
+	}
+
+	switch {
+	case isJobConditionTrue(job, batchv1.JobComplete):
+		_, err = h.updateStatus(ftj, mlv1.FineTuneJobPhaseSucceeded,
+			fmt.Sprintf("fine-tuned model is uploaded to model %s", ftj.Status.OutputModel))
+		return job, err
+	case isJobConditionTrue(job, batchv1.JobFailed):
+		message, err := h.getTerminationMessage(job)
+		if err != nil {
+			return job, err
+		}
+		if message == "" {
+			message = getJobConditionMessage(job, batchv1.JobFailed)
+		}
+		_, err = h.fail(ftj, message)
+		return job, err
+	}
+
+	return job, nil
+}
+
+func (h *handler) pending(ftj *mlv1.FineTuneJob, message string) (*mlv1.FineTuneJob, error) {
+	h.FineTuneJobs.EnqueueAfter(ftj.Namespace, ftj.Name, requeueInterval)
+	return h.updateStatus(ftj, mlv1.FineTuneJobPhasePending, message)
+}
+
+// fail marks the fine-tuning job as failed and deletes the output model created by the job, so that the
+// incomplete model isn't used by mistake
+func (h *handler) fail(ftj *mlv1.FineTuneJob, message string) (*mlv1.FineTuneJob, error) {
+	if name := ftj.Status.OutputModel; name != "" {
+		model, err := h.ModelCache.Get(ftj.Namespace, name)
+		if err != nil && !errors.IsNotFound(err) {
+			return ftj, err
+		}
+		if err == nil && model.Labels[constant.LabelFineTuneJobName] == ftj.Name {
+			logrus.Infof("deleting output model %s/%s of failed fine-tuning job %s", ftj.Namespace, name, ftj.Name)
+			if err = h.Models.Delete(ftj.Namespace, name, &metav1.DeleteOptions{}); err != nil &&
+				!errors.IsNotFound(err) {
+				return ftj, fmt.Errorf("failed to delete model %s/%s: %w", ftj.Namespace, name, err)
+			}
+		}
+	}
+
+	return h.updateStatus(ftj, mlv1.FineTuneJobPhaseFailed, message)
+}
+
+func (h *handler) updateStatus(ftj *mlv1.FineTuneJob, phase mlv1.FineTuneJobPhase,
+	message string) (*mlv1.FineTuneJob, error) {
+	ftjCopy := ftj.DeepCopy()
+	ftjCopy.Status.Phase = phase
+	ftjCopy.Status.Message = message
+	switch phase {
+	case mlv1.FineTuneJobPhaseSucceeded:
+		mlv1.Ready.True(ftjCopy)
+	case mlv1.FineTuneJobPhaseFailed:
+		mlv1.Ready.False(ftjCopy)
+		mlv1.Ready.Reason(ftjCopy, string(phase))
+	default:
+		mlv1.Ready.False(ftjCopy)
+	}
+	mlv1.Ready.Message(ftjCopy, message)
+	if phase == mlv1.FineTuneJobPhaseSucceeded || phase == mlv1.FineTuneJobPhaseFailed {
+		ftjCopy.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	}
+
+	if reflect.DeepEqual(ftj.Status, ftjCopy.Status) {
+		return ftj, nil
+	}
+	return h.FineTuneJobs.UpdateStatus(ftjCopy)
+}
+
+// getTerminationMessage returns the termination message of the failed trainer or upload container
+func (h *handler) getTerminationMessage(job *batchv1.Job) (string, error) {
+	pods, err := h.PodCache.List(job.Namespace, labels.SelectorFromSet(map[string]string{
+		batchv1.JobNameLabel: job.Name,
+	}))
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods of job %s/%s: %w", job.Namespace, job.Name, err)
+	}
+
+	for _, pod := range pods {
+		statuses := make([]corev1.ContainerStatus, 0,
+			len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		for _, cs := range append(statuses, pod.Status.ContainerStatuses...) {
+			if cs.Name == reporterContainerName || cs.State.Terminated == nil || cs.State.Terminated.ExitCode == 0 {
+				continue
+			}
+			if message := cs.State.Terminated.Message; message != "" {
+				return fmt.Sprintf("container %s failed: %s", cs.Name, message), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func isFinished(ftj *mlv1.FineTuneJob) bool {
+	return ftj.Status.Phase == mlv1.FineTuneJobPhaseSucceeded || ftj.Status.Phase == mlv1.FineTuneJobPhaseFailed
+}
+
+func isJobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func getJobConditionMessage(job *batchv1.Job, conditionType batchv1.JobConditionType) string {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType {
+			return c.Message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,249 @@
+package finetunejob
+
+import (
+	"fmt"
+	"strconv"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	jobPrefix = "finetunejob"
+
+	downloadContainerName = "download-model"
+	reporterContainerName = "metrics-reporter"
+	trainerContainerName  = "trainer"
+	uploadContainerName   = "upload"
+
+	datasetVolumeName = "dataset"
+	modelVolumeName   = "model"
+	outputVolumeName  = "output"
+	metricsVolumeName = "metrics"
+	datasetMountPath  = "/data"
+	modelMountPath    = "/model"
+	outputMountPath   = "/output"
+	metricsMountPath  = "/metrics"
+	metricsFile       = metricsMountPath + "/metrics.jsonl"
+
+	llmosModeEnvName = "LLMOS_MODE"
+	fineTuneMode     = "finetune"
+)
+
+// The environment variables of the trainer, whose contract is documented by the image of FineTuneJobSpec
+const (
+	envBaseModel                 = "LLMOS_BASE_MODEL"
+	envDatasetDir                = "LLMOS_DATASET_DIR"
+	envDatasetField              = "LLMOS_DATASET_FIELD"
+	envOutputDir                 = "LLMOS_OUTPUT_DIR"
+	envMetricsFile               = "LLMOS_METRICS_FILE"
+	envMethod                    = "LLMOS_FINETUNE_METHOD"
+	envMergeAdapter              = "LLMOS_MERGE_ADAPTER"
+	envEpochs                    = "LLMOS_EPOCHS"
+	envLearningRate              = "LLMOS_LEARNING_RATE"
+	envBatchSize                 = "LLMOS_BATCH_SIZE"
+	envGradientAccumulationSteps = "LLMOS_GRADIENT_ACCUMULATION_STEPS"
+	envMaxSeqLength              = "LLMOS_MAX_SEQ_LENGTH"
+	envLoRARank                  = "LLMOS_LORA_RANK"
+	envLoRAAlpha                 = "LLMOS_LORA_ALPHA"
+	envLoRADropout               = "LLMOS_LORA_DROPOUT"
+)
+
+// constructJob builds the fine-tuning job. The base model is downloaded by the first init container, the
+// metrics reporter runs as a sidecar of the trainer, and the upload container uploads the output once the
+// trainer is completed. The dataset volume is restored from the volume snapshot of the published dataset
+// version and deleted with the pod. The token of the downloader service account is only mounted into the
+// containers of the downloader image, the trainer running the user image has no access to the cluster.
+func constructJob(ftj *mlv1.FineTuneJob, storageClassName string,
+	snapshot *snapshotv1.VolumeSnapshot) *batchv1.Job {
+	image := settings.ModelDownloaderImage.Get()
+	name := fmt.Sprintf("--name=%s/%s", ftj.Namespace, ftj.Name)
+	outputMount := corev1.VolumeMount{Name: outputVolumeName, MountPath: outputMountPath}
+	metricsMount := corev1.VolumeMount{Name: metricsVolumeName, MountPath: metricsMountPath}
+	tokenMount := snapshotting.DownloaderTokenVolumeMount()
+
+	var initContainers []corev1.Container
+	if ftj.Spec.BaseModel.Model != "" {
+		initContainers = append(initContainers, corev1.Container{
+			Name:  downloadContainerName,
+			Image: image,
+			Args: []string{
+				fmt.Sprintf("--type=%s", mlv1.ModelResourceName),
+				fmt.Sprintf("--name=%s/%s", ftj.Namespace, ftj.Spec.BaseModel.Model),
+				fmt.Sprintf("--output-dir=%s", modelMountPath),
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: modelVolumeName, MountPath: modelMountPath}, tokenMount},
+		})
+	}
+	initContainers = append(initContainers,
+		corev1.Container{
+			Name:          reporterContainerName,
+			Image:         image,
+			RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways),
+			Env:           []corev1.EnvVar{{Name: llmosModeEnvName, Value: fineTuneMode}},
+			Args:          []string{"report", name, "--metrics-file=" + metricsFile},
+			VolumeMounts:  []corev1.VolumeMount{metricsMount, tokenMount},
+		},
+		constructTrainer(ftj),
+	)
+
+	volumes := []corev1.Volume{
+		{
+			Name: datasetVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Ephemeral: &corev1.EphemeralVolumeSource{
+					VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+						Spec: corev1.PersistentVolumeClaimSpec{
+							AccessModes:      []corev1.PersistentVolumeAccessMode{snapshotting.GetRestoreAccessMode()},
+							StorageClassName: ptr.To(storageClassName),
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceStorage: *snapshot.Status.RestoreSize,
+								},
+							},
+							DataSource: &corev1.TypedLocalObjectReference{
+								Kind:     "VolumeSnapshot",
+								Name:     snapshot.Name,
+								APIGroup: ptr.To("snapshot.storage.k8s.io"),
+							},
+						},
+					},
+				},
+			},
+		},
+		{Name: outputVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		{Name: metricsVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		snapshotting.DownloaderTokenVolume(),
+	}
+	if ftj.Spec.BaseModel.Model != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name:         modelVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getJobName(ftj.Name),
+			Namespace: ftj.Namespace,
+			Labels: map[string]string{
+				constant.LabelFineTuneJobName: ftj.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(ftj, mlv1.SchemeGroupVersion.WithKind("FineTuneJob")),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(0)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						constant.LabelFineTuneJobName: ftj.Name,
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName:           snapshotting.DownloaderServiceAccountName,
+					AutomountServiceAccountToken: ptr.To(false),
+					RestartPolicy:                corev1.RestartPolicyNever,
+					NodeSelector:                 ftj.Spec.NodeSelector,
+					Tolerations:                  ftj.Spec.Tolerations,
+					InitContainers:               initContainers,
+					Containers: []corev1.Container{
+						{
+							Name:  uploadContainerName,
+							Image: image,
+							Env:   []corev1.EnvVar{{Name: llmosModeEnvName, Value: fineTuneMode}},
+							Args: []string{
+								"upload", name,
+								"--metrics-file=" + metricsFile,
+								"--output-dir=" + outputMountPath,
+							},
+							VolumeMounts:             []corev1.VolumeMount{outputMount, metricsMount, tokenMount},
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+func constructTrainer(ftj *mlv1.FineTuneJob) corev1.Container {
+	volumeMounts := []corev1.VolumeMount{
+		{Name: datasetVolumeName, MountPath: datasetMountPath, ReadOnly: true},
+		{Name: outputVolumeName, MountPath: outputMountPath},
+		{Name: metricsVolumeName, MountPath: metricsMountPath},
+	}
+	baseModel := ftj.Spec.BaseModel.HuggingFaceID
+	if ftj.Spec.BaseModel.Model != "" {
+		baseModel = modelMountPath
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: modelVolumeName, MountPath: modelMountPath})
+	}
+
+	return corev1.Container{
+		Name:                     trainerContainerName,
+		Image:                    ftj.Spec.Image,
+		Env:                      buildTrainerEnv(ftj, baseModel),
+		Resources:                ftj.Spec.Resources,
+		VolumeMounts:             volumeMounts,
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+}
+
+func buildTrainerEnv(ftj *mlv1.FineTuneJob, baseModel string) []corev1.EnvVar {
+	hp := ftj.Spec.Hyperparameters
+	env := []corev1.EnvVar{
+		{Name: envBaseModel, Value: baseModel},
+		{Name: envDatasetDir, Value: datasetMountPath},
+		{Name: envOutputDir, Value: outputMountPath},
+		{Name: envMetricsFile, Value: metricsFile},
+		{Name: envMethod, Value: string(ftj.Spec.Method)},
+		{Name: envMergeAdapter, Value: strconv.FormatBool(ftj.Spec.Output.MergeAdapter)},
+	}
+
+	optional := []struct {
+		name  string
+		value string
+	}{
+		{envDatasetField, hp.DatasetField},
+		{envEpochs, formatInt(hp.Epochs)},
+		{envLearningRate, hp.LearningRate},
+		{envBatchSize, formatInt(hp.BatchSize)},
+		{envGradientAccumulationSteps, formatInt(hp.GradientAccumulationSteps)},
+		{envMaxSeqLength, formatInt(hp.MaxSeqLength)},
+		{envLoRARank, formatInt(hp.LoRARank)},
+		{envLoRAAlpha, formatInt(hp.LoRAAlpha)},
+		{envLoRADropout, hp.LoRADropout},
+	}
+	for _, o := range optional {
+		if o.value != "" {
+			env = append(env, corev1.EnvVar{Name: o.name, Value: o.value})
+		}
+	}
+	return env
+}
+
+func formatInt(v int32) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(v))
+}
+
+func getJobName(name string) string {
+	return fmt.Sprintf("%s-%s", jobPrefix, name)
+}
+
+// isAdapter returns true if the output of the fine-tuning is an adapter of the base model
+func isAdapter(ftj *mlv1.FineTuneJob) bool {
+	return ftj.Spec.Method != mlv1.FineTuneMethodFull && !ftj.Spec.Output.MergeAdapter
+}
@@ -0,0 +1,150 @@
+package finetunejob
+
+import (
+	"slices"
+	"testing"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+)
+
+func TestConstructJob(t *testing.T) {
+	snapshot := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "alpaca-v1-snapshot", Namespace: "default"},
+		Status:     &snapshotv1.VolumeSnapshotStatus{RestoreSize: ptr.To(resource.MustParse("10Gi"))},
+	}
+
+	var testCases = []struct {
+		name              string
+		baseModel         mlv1.FineTuneBaseModel
+		expectedBaseModel string
+		expectedInits     []string
+	}{
+		{
+			name:              "model",
+			baseModel:         mlv1.FineTuneBaseModel{Model: "qwen"},
+			expectedBaseModel: modelMountPath,
+			expectedInits:     []string{downloadContainerName, reporterContainerName, trainerContainerName},
+		},
+		{
+			name:              "hugging face",
+			baseModel:         mlv1.FineTuneBaseModel{HuggingFaceID: "Qwen/Qwen2.5-0.5B-Instruct"},
+			expectedBaseModel: "Qwen/Qwen2.5-0.5B-Instruct",
+			expectedInits:     []string{reporterContainerName, trainerContainerName},
+		},
+	}
+
+	for _, tc := range testCases {
+		ftj := &mlv1.FineTuneJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "sft", Namespace: "default"},
+			Spec: mlv1.FineTuneJobSpec{
+				BaseModel:      tc.baseModel,
+				DatasetVersion: "alpaca-v1",
+				Method:         mlv1.FineTuneMethodQLoRA,
+				Hyperparameters: mlv1.FineTuneHyperparameters{
+					Epochs:       3,
+					LearningRate: "2e-4",
+					LoRARank:     16,
+				},
+				Output: mlv1.FineTuneOutput{Registry: "default", ModelName: "qwen-sft"},
+				Image:  "registry.example.com/trainer:v1",
+			},
+		}
+
+		job := constructJob(ftj, "ceph-block", snapshot)
+		if job.Name != "finetunejob-sft" || job.Labels[constant.LabelFineTuneJobName] != "sft" {
+			t.Errorf("%s: unexpected job metadata: %+v", tc.name, job.ObjectMeta)
+		}
+		if len(job.OwnerReferences) != 1 || job.OwnerReferences[0].Kind != "FineTuneJob" {
+			t.Errorf("%s: expected the job to be owned by the fine-tuning job, got %+v", tc.name, job.OwnerReferences)
+		}
+
+		podSpec := job.Spec.Template.Spec
+		var inits []string
+		for _, c := range podSpec.InitContainers {
+			inits = append(inits, c.Name)
+		}
+		if !slices.Equal(inits, tc.expectedInits) {
+			t.Errorf("%s: expected init containers %v, got %v", tc.name, tc.expectedInits, inits)
+		}
+
+		reporter := podSpec.InitContainers[len(podSpec.InitContainers)-2]
+		if reporter.RestartPolicy == nil || *reporter.RestartPolicy != corev1.ContainerRestartPolicyAlways {
+			t.Errorf("%s: expected the metrics reporter to be a sidecar", tc.name)
+		}
+
+		trainer := podSpec.InitContainers[len(podSpec.InitContainers)-1]
+		if trainer.Image != ftj.Spec.Image {
+			t.Errorf("%s: expected trainer image %s, got %s", tc.name, ftj.Spec.Image, trainer.Image)
+		}
+
+		// the token of the downloader is only mounted into the containers of the downloader image
+		if podSpec.AutomountServiceAccountToken == nil || *podSpec.AutomountServiceAccountToken {
+			t.Errorf("%s: expected the service account token not to be auto-mounted", tc.name)
+		}
+		for _, c := range append(slices.Clone(podSpec.InitContainers), podSpec.Containers...) {
+			hasToken := slices.ContainsFunc(c.VolumeMounts, func(m corev1.VolumeMount) bool {
+				return m.Name == snapshotting.DownloaderTokenVolumeName
+			})
+			if hasToken == (c.Name == trainerContainerName) {
+				t.Errorf("%s: unexpected token mount %v of container %s", tc.name, hasToken, c.Name)
+			}
+		}
+		env := map[string]string{}
+		for _, e := range trainer.Env {
+			env[e.Name] = e.Value
+		}
+		for name, value := range map[string]string{
+			envBaseModel:    tc.expectedBaseModel,
+			envMethod:       "qlora",
+			envEpochs:       "3",
+			envLearningRate: "2e-4",
+			envLoRARank:     "16",
+			envMergeAdapter: "false",
+		} {
+			if env[name] != value {
+				t.Errorf("%s: expected env %s=%s, got %q", tc.name, name, value, env[name])
+			}
+		}
+		if _, ok := env[envBatchSize]; ok {
+			t.Errorf("%s: expected the unset hyperparameters to be omitted", tc.name)
+		}
+
+		dataset := podSpec.Volumes[0]
+		if dataset.Ephemeral == nil || dataset.Ephemeral.VolumeClaimTemplate.Spec.DataSource.Name != snapshot.Name {
+			t.Errorf("%s: expected the dataset volume to be restored from the snapshot, got %+v", tc.name, dataset)
+		}
+		if !slices.Contains(podSpec.Containers[0].Args, "--name=default/sft") {
+			t.Errorf("%s: unexpected upload args %v", tc.name, podSpec.Containers[0].Args)
+		}
+	}
+}
+
+func TestIsAdapter(t *testing.T) {
+	var testCases = []struct {
+		method       mlv1.FineTuneMethod
+		mergeAdapter bool
+		expected     bool
+	}{
+		{method: mlv1.FineTuneMethodFull, expected: false},
+		{method: mlv1.FineTuneMethodLoRA, expected: true},
+		{method: mlv1.FineTuneMethodQLoRA, mergeAdapter: true, expected: false},
+	}
+
+	for _, tc := range testCases {
+		ftj := &mlv1.FineTuneJob{Spec: mlv1.FineTuneJobSpec{
+			Method: tc.method,
+			Output: mlv1.FineTuneOutput{MergeAdapter: tc.mergeAdapter},
+		}}
+		if isAdapter(ftj) != tc.expected {
+			t.Errorf("expected isAdapter of %s (merge %v) to be %v", tc.method, tc.mergeAdapter, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package group
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+const (
+	groupOnChangeName = "group.onChange"
+	userOnChangeName  = "group.onUserChange"
+)
+
+type handler struct {
+	groups     ctlmgmtv1.GroupController
+	groupCache ctlmgmtv1.GroupCache
+	userCache  ctlmgmtv1.UserCache
+}
+
+func Register(ctx context.Context, mgmt *config.Management, _ config.Options) error {
+	groups := mgmt.MgmtFactory.Management().V1().Group()
+	users := mgmt.MgmtFactory.Management().V1().User()
+
+	h := &handler{
+		groups:     groups,
+		groupCache: groups.Cache(),
+		userCache:  users.Cache(),
+	}
+	groups.OnChange(ctx, groupOnChangeName, h.onChange)
+	users.OnChange(ctx, userOnChangeName, h.onUserChange)
+	return nil
+}
+
+// onChange updates the member count and the missing members of the group
+func (h *handler) onChange(_ string, group *mgmtv1.Group) (*mgmtv1.Group, error) {
+	if group == nil || group.DeletionTimestamp != nil {
+		return group, nil
+	}
+
+	toUpdate := group.DeepCopy()
+	toUpdate.Status.MemberCount = 0
+	toUpdate.Status.MissingMembers = nil
+	for _, member := range sets.List(sets.New(group.Spec.Members...)) {
+		_, err := h.userCache.Get(member)
+		if err != nil && errors.IsNotFound(err) {
+			toUpdate.Status.MissingMembers = append(toUpdate.Status.MissingMembers, member)
+			continue
+		} else if err != nil {
+			return group, err
+		}
+		toUpdate.Status.MemberCount++
+	}
+
+	if !reflect.DeepEqual(group.Status, toUpdate.Status) {
+		return h.groups.UpdateStatus(toUpdate)
+	}
+	return group, nil
+}
+
+// onUserChange re-syncs the groups of the user when the user is created or deleted, the user is nil if it is deleted
+func (h *handler) onUserChange(name string, _ *mgmtv1.User) (*mgmtv1.User, error) {
+	groups, err := h.groupCache.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if HasMember(group, name) {
+			h.groups.Enqueue(group.Name)
+		}
+	}
+	return nil, nil
+}
+
+// HasMember returns whether the user is a member of the group
+func HasMember(group *mgmtv1.Group, userName string) bool {
+	for _, member := range group.Spec.Members {
+		if member == userName {
+			return true
+		}
+	}
+	return false
+}
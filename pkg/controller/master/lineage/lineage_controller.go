@@ -0,0 +1,278 @@
+package lineage
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/indexeres"
+	"github.com/llmos-ai/llmos-operator/pkg/lineage"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+const (
+	datasetVersionOnChangeName    = "lineage.datasetVersionOnChange"
+	modelOnChangeName             = "lineage.modelOnChange"
+	localModelVersionOnChangeName = "lineage.localModelVersionOnChange"
+	modelServiceOnChangeName      = "lineage.modelServiceOnChange"
+
+	localModelRegistry = "local"
+)
+
+// handler records the lineage edges of the datasets, models and model services. The edges of an object are
+// derived from its spec and owned by it, so they are garbage collected once the object is deleted.
+type handler struct {
+	datasetCache        ctlmlv1.DatasetCache
+	datasetVersionCache ctlmlv1.DatasetVersionCache
+	modelCache          ctlmlv1.ModelCache
+	lineageEdges        ctlmlv1.LineageEdgeClient
+	lineageEdgeCache    ctlmlv1.LineageEdgeCache
+}
+
+func Register(ctx context.Context, mgmt *config.Management, _ config.Options) error {
+	datasetVersions := mgmt.LLMFactory.Ml().V1().DatasetVersion()
+	models := mgmt.LLMFactory.Ml().V1().Model()
+	localModelVersions := mgmt.LLMFactory.Ml().V1().LocalModelVersion()
+	modelServices := mgmt.LLMFactory.Ml().V1().ModelService()
+	lineageEdges := mgmt.LLMFactory.Ml().V1().LineageEdge()
+
+	h := &handler{
+		datasetCache:        mgmt.LLMFactory.Ml().V1().Dataset().Cache(),
+		datasetVersionCache: datasetVersions.Cache(),
+		modelCache:          models.Cache(),
+		lineageEdges:        lineageEdges,
+		lineageEdgeCache:    lineageEdges.Cache(),
+	}
+
+	datasetVersions.OnChange(ctx, datasetVersionOnChangeName, h.OnDatasetVersionChange)
+	models.OnChange(ctx, modelOnChangeName, h.OnModelChange)
+	localModelVersions.OnChange(ctx, localModelVersionOnChangeName, h.OnLocalModelVersionChange)
+	modelServices.OnChange(ctx, modelServiceOnChangeName, h.OnModelServiceChange)
+
+	return nil
+}
+
+// OnDatasetVersionChange records the dataset version the version is copied from
+func (h *handler) OnDatasetVersionChange(_ string, dv *mlv1.DatasetVersion) (*mlv1.DatasetVersion, error) {
+	if dv == nil || dv.DeletionTimestamp != nil {
+		return dv, nil
+	}
+
+	edges := newEdgeSet(lineage.KindDatasetVersion, dv)
+	if from := dv.Spec.CopyFrom; from != nil {
+		source, err := h.datasetVersionReference(mlv1.DatasetVersionReference{
+			Namespace: from.Namespace,
+			Dataset:   from.Dataset,
+			Version:   from.Version,
+		}, dv.Namespace)
+		if err = edges.add(mlv1.LineageEdgeTypeCopy, source, err); err != nil {
+			return dv, err
+		}
+	}
+
+	return dv, h.syncEdges(edges)
+}
+
+// OnModelChange records the dataset versions the model is trained on
+func (h *handler) OnModelChange(_ string, model *mlv1.Model) (*mlv1.Model, error) {
+	if model == nil || model.DeletionTimestamp != nil {
+		return model, nil
+	}
+
+	edges := newEdgeSet(lineage.KindModel, model)
+	for _, ref := range model.Spec.TrainedOn {
+		source, err := h.datasetVersionReference(ref, model.Namespace)
+		if err = edges.add(mlv1.LineageEdgeTypeTrain, source, err); err != nil {
+			return model, err
+		}
+	}
+
+	return model, h.syncEdges(edges)
+}
+
+// OnLocalModelVersionChange records the model the local model version is downloaded from
+func (h *handler) OnLocalModelVersionChange(_ string,
+	version *mlv1.LocalModelVersion) (*mlv1.LocalModelVersion, error) {
+	if version == nil || version.DeletionTimestamp != nil {
+		return version, nil
+	}
+
+	edges := newEdgeSet(lineage.KindLocalModelVersion, version)
+	namespace, name := version.Labels[constant.LabelModelNamespace], version.Labels[constant.LabelModelName]
+	if namespace != "" && name != "" {
+		source, err := h.modelReference(namespace, name)
+		if err = edges.add(mlv1.LineageEdgeTypeDownload, source, err); err != nil {
+			return version, err
+		}
+	}
+
+	return version, h.syncEdges(edges)
+}
+
+// OnModelServiceChange records the local models served by the model service, including the canary model and
+// the LoRA adapters
+func (h *handler) OnModelServiceChange(_ string, ms *mlv1.ModelService) (*mlv1.ModelService, error) {
+	if ms == nil || ms.DeletionTimestamp != nil {
+		return ms, nil
+	}
+
+	var modelNames []string
+	if ms.Spec.ModelRegistry == localModelRegistry {
+		modelNames = append(modelNames, ms.Spec.ModelName)
+		if ms.Spec.Canary != nil && ms.Spec.Canary.ModelName != "" {
+			modelNames = append(modelNames, ms.Spec.Canary.ModelName)
+		}
+	}
+	for _, adapter := range ms.Spec.Adapters {
+		modelNames = append(modelNames, adapter.Model)
+	}
+
+	edges := newEdgeSet(lineage.KindModelService, ms)
+	for _, name := range modelNames {
+		source, err := h.modelReference(ms.Namespace, name)
+		if err = edges.add(mlv1.LineageEdgeTypeServe, source, err); err != nil {
+			return ms, err
+		}
+	}
+
+	return ms, h.syncEdges(edges)
+}
+
+// datasetVersionReference resolves the dataset version object of the version of the dataset
+func (h *handler) datasetVersionReference(ref mlv1.DatasetVersionReference,
+	defaultNamespace string) (mlv1.LineageObjectReference, error) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	dataset, err := h.datasetCache.Get(namespace, ref.Dataset)
+	if err != nil {
+		return mlv1.LineageObjectReference{}, fmt.Errorf("get dataset %s/%s failed: %w", namespace, ref.Dataset, err)
+	}
+	for _, v := range dataset.Status.Versions {
+		if v.Version != ref.Version {
+			continue
+		}
+		dv, err := h.datasetVersionCache.Get(namespace, v.ObjectName)
+		if err != nil {
+			return mlv1.LineageObjectReference{}, fmt.Errorf("get dataset version %s/%s failed: %w",
+				namespace, v.ObjectName, err)
+		}
+		return lineage.Reference(lineage.KindDatasetVersion, dv), nil
+	}
+
+	return mlv1.LineageObjectReference{}, errors.NewNotFound(mlv1.Resource(mlv1.DatasetVersionResourceName),
+		fmt.Sprintf("%s/%s/%s", namespace, ref.Dataset, ref.Version))
+}
+
+func (h *handler) modelReference(namespace, name string) (mlv1.LineageObjectReference, error) {
+	model, err := h.modelCache.Get(namespace, name)
+	if err != nil {
+		return mlv1.LineageObjectReference{}, fmt.Errorf("get model %s/%s failed: %w", namespace, name, err)
+	}
+	return lineage.Reference(lineage.KindModel, model), nil
+}
+
+// edgeSet collects the desired edges to the target object
+type edgeSet struct {
+	targetKind string
+	target     metav1.Object
+	edges      []*mlv1.LineageEdge
+	// partial is set if any source object is not found, the existing edges are kept in this case since the
+	// lineage should survive the deletion of the source objects
+	partial bool
+}
+
+func newEdgeSet(targetKind string, target metav1.Object) *edgeSet {
+	return &edgeSet{targetKind: targetKind, target: target}
+}
+
+// add adds the edge from the resolved source, a source not found is skipped instead of failing the sync
+func (s *edgeSet) add(edgeType mlv1.LineageEdgeType, source mlv1.LineageObjectReference, err error) error {
+	if errors.IsNotFound(err) {
+		logrus.Warnf("skip lineage of %s/%s: %v", s.target.GetNamespace(), s.target.GetName(), err)
+		s.partial = true
+		return nil
+	} else if err != nil {
+		return err
+	}
+	s.edges = append(s.edges, lineage.NewEdge(edgeType, source, s.target, s.targetKind))
+	return nil
+}
+
+// syncEdges creates, updates and deletes the edges to the target object to match the desired edges
+func (h *handler) syncEdges(s *edgeSet) error {
+	key := lineage.Key(lineage.Reference(s.targetKind, s.target))
+	existing, err := h.lineageEdgeCache.GetByIndex(indexeres.LineageEdgeTargetIndex, key)
+	if err != nil {
+		return fmt.Errorf("get lineage edges of %s failed: %w", key, err)
+	}
+
+	toCreate, toUpdate, toDelete := diffEdges(existing, s.edges)
+	if s.partial {
+		toDelete = nil
+	}
+	for _, edge := range toCreate {
+		logrus.Debugf("create lineage edge %s/%s", edge.Namespace, edge.Name)
+		if _, err := h.lineageEdges.Create(edge); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("create lineage edge %s/%s failed: %w", edge.Namespace, edge.Name, err)
+		}
+	}
+	for _, edge := range toUpdate {
+		if _, err := h.lineageEdges.Update(edge); err != nil {
+			return fmt.Errorf("update lineage edge %s/%s failed: %w", edge.Namespace, edge.Name, err)
+		}
+	}
+	for _, edge := range toDelete {
+		logrus.Debugf("delete lineage edge %s/%s", edge.Namespace, edge.Name)
+		if err := h.lineageEdges.Delete(edge.Namespace, edge.Name, &metav1.DeleteOptions{}); err != nil &&
+			!errors.IsNotFound(err) {
+			return fmt.Errorf("delete lineage edge %s/%s failed: %w", edge.Namespace, edge.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// diffEdges compares the existing edges with the desired edges by name. An existing edge is updated if its
+// spec or owner is changed, e.g., the source or target object is recreated with the same name.
+func diffEdges(existing, desired []*mlv1.LineageEdge) (toCreate, toUpdate, toDelete []*mlv1.LineageEdge) {
+	existingEdges := make(map[string]*mlv1.LineageEdge, len(existing))
+	for _, edge := range existing {
+		existingEdges[edge.Name] = edge
+	}
+
+	desiredNames := make(map[string]bool, len(desired))
+	for _, edge := range desired {
+		desiredNames[edge.Name] = true
+		current, ok := existingEdges[edge.Name]
+		if !ok {
+			toCreate = append(toCreate, edge)
+			continue
+		}
+		if reflect.DeepEqual(current.Spec, edge.Spec) &&
+			reflect.DeepEqual(current.OwnerReferences, edge.OwnerReferences) {
+			continue
+		}
+		currentCopy := current.DeepCopy()
+		currentCopy.Spec = edge.Spec
+		currentCopy.OwnerReferences = edge.OwnerReferences
+		toUpdate = append(toUpdate, currentCopy)
+	}
+
+	for _, edge := range existing {
+		if !desiredNames[edge.Name] {
+			toDelete = append(toDelete, edge)
+		}
+	}
+
+	return toCreate, toUpdate, toDelete
+}
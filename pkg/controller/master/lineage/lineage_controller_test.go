@@ -0,0 +1,93 @@
+package lineage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/lineage"
+)
+
+func newModel(uid string) *mlv1.Model {
+	return &mlv1.Model{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "model", UID: types.UID("model-" + uid)},
+	}
+}
+
+func dvRef(name string) mlv1.LineageObjectReference {
+	return mlv1.LineageObjectReference{Kind: lineage.KindDatasetVersion, Namespace: "default", Name: name, UID: "dv-uid"}
+}
+
+func edgeNames(edges []*mlv1.LineageEdge) []string {
+	names := make([]string, 0, len(edges))
+	for _, e := range edges {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func TestDiffEdges(t *testing.T) {
+	model := newModel("1")
+	edgeA := lineage.NewEdge(mlv1.LineageEdgeTypeTrain, dvRef("a"), model, lineage.KindModel)
+	edgeB := lineage.NewEdge(mlv1.LineageEdgeTypeTrain, dvRef("b"), model, lineage.KindModel)
+	edgeC := lineage.NewEdge(mlv1.LineageEdgeTypeTrain, dvRef("c"), model, lineage.KindModel)
+	recreatedEdgeA := lineage.NewEdge(mlv1.LineageEdgeTypeTrain, dvRef("a"), newModel("2"), lineage.KindModel)
+
+	var testCases = []struct {
+		name             string
+		existing         []*mlv1.LineageEdge
+		desired          []*mlv1.LineageEdge
+		expectedToCreate []string
+		expectedToUpdate []string
+		expectedToDelete []string
+	}{
+		{
+			name:             "create edges",
+			desired:          []*mlv1.LineageEdge{edgeA, edgeB},
+			expectedToCreate: []string{edgeA.Name, edgeB.Name},
+		},
+		{
+			name:     "edges unchanged",
+			existing: []*mlv1.LineageEdge{edgeA, edgeB},
+			desired:  []*mlv1.LineageEdge{edgeA, edgeB},
+		},
+		{
+			name:             "replace edge",
+			existing:         []*mlv1.LineageEdge{edgeA, edgeB},
+			desired:          []*mlv1.LineageEdge{edgeA, edgeC},
+			expectedToCreate: []string{edgeC.Name},
+			expectedToDelete: []string{edgeB.Name},
+		},
+		{
+			name:             "update edge of recreated target",
+			existing:         []*mlv1.LineageEdge{edgeA},
+			desired:          []*mlv1.LineageEdge{recreatedEdgeA},
+			expectedToUpdate: []string{edgeA.Name},
+		},
+	}
+
+	for _, tc := range testCases {
+		toCreate, toUpdate, toDelete := diffEdges(tc.existing, tc.desired)
+		assert.ElementsMatch(t, tc.expectedToCreate, edgeNames(toCreate), tc.name)
+		assert.ElementsMatch(t, tc.expectedToUpdate, edgeNames(toUpdate), tc.name)
+		assert.ElementsMatch(t, tc.expectedToDelete, edgeNames(toDelete), tc.name)
+	}
+}
+
+func TestDiffEdges_UpdateOwner(t *testing.T) {
+	existing := lineage.NewEdge(mlv1.LineageEdgeTypeTrain, dvRef("a"), newModel("1"), lineage.KindModel)
+	desired := lineage.NewEdge(mlv1.LineageEdgeTypeTrain, dvRef("a"), newModel("2"), lineage.KindModel)
+	existing.ResourceVersion = "10"
+
+	_, toUpdate, _ := diffEdges([]*mlv1.LineageEdge{existing}, []*mlv1.LineageEdge{desired})
+
+	assert.Len(t, toUpdate, 1)
+	assert.Equal(t, "10", toUpdate[0].ResourceVersion)
+	assert.Equal(t, desired.Spec, toUpdate[0].Spec)
+	assert.Equal(t, desired.OwnerReferences, toUpdate[0].OwnerReferences)
+	// the cached object is not mutated
+	assert.Equal(t, types.UID("model-1"), existing.OwnerReferences[0].UID)
+}
@@ -0,0 +1,139 @@
+package modelbenchmark
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/modelservice"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	jobPrefix              = "modelbenchmark"
+	benchmarkContainerName = "benchmark"
+	datasetVolumeName      = "dataset"
+	datasetMountPath       = "/data"
+	llmosModeEnvName       = "LLMOS_MODE"
+	benchmarkMode          = "benchmark"
+)
+
+// constructJob builds the benchmark job, the init container downloads the dataset version and the benchmark
+// container writes the report to its termination message
+func constructJob(mb *mlv1.ModelBenchmark, ms *mlv1.ModelService) *batchv1.Job {
+	image := settings.ModelDownloaderImage.Get()
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      datasetVolumeName,
+			MountPath: datasetMountPath,
+		},
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getJobName(mb.Name),
+			Namespace: mb.Namespace,
+			Labels: map[string]string{
+				constant.LabelModelBenchmarkName: mb.Name,
+				constant.LabelModelServiceName:   ms.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(mb, mb.GroupVersionKind()),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To(int32(0)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						constant.LabelModelBenchmarkName: mb.Name,
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: snapshotting.DownloaderServiceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					InitContainers: []corev1.Container{
+						{
+							Name:  "download-dataset",
+							Image: image,
+							Args: []string{
+								fmt.Sprintf("--type=%s", mlv1.DatasetVersionResourceName),
+								fmt.Sprintf("--name=%s/%s", mb.Namespace, mb.Spec.Dataset.DatasetVersion),
+								fmt.Sprintf("--output-dir=%s", datasetMountPath),
+							},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:  benchmarkContainerName,
+							Image: image,
+							Args:  buildBenchmarkArgs(mb, ms),
+							Env: []corev1.EnvVar{
+								{
+									Name:  llmosModeEnvName,
+									Value: benchmarkMode,
+								},
+							},
+							VolumeMounts:             volumeMounts,
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: datasetVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildBenchmarkArgs(mb *mlv1.ModelBenchmark, ms *mlv1.ModelService) []string {
+	levels := make([]string, 0, len(mb.Spec.ConcurrencyLevels))
+	for _, level := range mb.Spec.ConcurrencyLevels {
+		levels = append(levels, strconv.Itoa(int(level)))
+	}
+
+	args := []string{
+		fmt.Sprintf("--endpoint=%s", modelservice.GetServiceEndpoint(ms)),
+		fmt.Sprintf("--type=%s", mb.Spec.Type),
+		fmt.Sprintf("--dataset-dir=%s", datasetMountPath),
+		fmt.Sprintf("--prompt-field=%s", mb.Spec.Dataset.PromptField),
+	}
+	if ms.Spec.ServedModelName != "" {
+		args = append(args, fmt.Sprintf("--model=%s", ms.Spec.ServedModelName))
+	}
+	if mb.Spec.Dataset.ReferenceField != "" {
+		args = append(args, fmt.Sprintf("--reference-field=%s", mb.Spec.Dataset.ReferenceField))
+	}
+	if mb.Spec.Dataset.MaxPrompts > 0 {
+		args = append(args, fmt.Sprintf("--max-prompts=%d", mb.Spec.Dataset.MaxPrompts))
+	}
+	if len(levels) > 0 {
+		args = append(args, fmt.Sprintf("--concurrency=%s", strings.Join(levels, ",")))
+	}
+	if mb.Spec.Duration.Duration > 0 {
+		args = append(args, fmt.Sprintf("--duration=%s", mb.Spec.Duration.Duration))
+	}
+	if mb.Spec.MaxTokens > 0 {
+		args = append(args, fmt.Sprintf("--max-tokens=%d", mb.Spec.MaxTokens))
+	}
+	return args
+}
+
+func getJobName(name string) string {
+	return fmt.Sprintf("%s-%s", jobPrefix, name)
+}
@@ -0,0 +1,66 @@
+package modelbenchmark
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+)
+
+func TestConstructJob(t *testing.T) {
+	mb := &mlv1.ModelBenchmark{
+		ObjectMeta: metav1.ObjectMeta{Name: "bench", Namespace: "default"},
+		Spec: mlv1.ModelBenchmarkSpec{
+			ModelService: "qwen",
+			Type:         mlv1.ModelBenchmarkTypeAccuracy,
+			Dataset: mlv1.ModelBenchmarkDataset{
+				DatasetVersion: "mmlu-v1",
+				PromptField:    "question",
+				ReferenceField: "answer",
+				MaxPrompts:     100,
+			},
+			ConcurrencyLevels: []int32{1, 8},
+			Duration:          metav1.Duration{Duration: time.Minute},
+			MaxTokens:         32,
+		},
+	}
+	ms := &mlv1.ModelService{
+		ObjectMeta: metav1.ObjectMeta{Name: "qwen", Namespace: "default"},
+		Spec:       mlv1.ModelServiceSpec{ServedModelName: "qwen2.5"},
+	}
+
+	job := constructJob(mb, ms)
+	if job.Name != "modelbenchmark-bench" || job.Labels[constant.LabelModelBenchmarkName] != "bench" {
+		t.Errorf("Unexpected job metadata: %+v", job.ObjectMeta)
+	}
+	if len(job.OwnerReferences) != 1 || job.OwnerReferences[0].Name != "bench" {
+		t.Errorf("Expected the job to be owned by the model benchmark, got %+v", job.OwnerReferences)
+	}
+
+	podSpec := job.Spec.Template.Spec
+	if len(podSpec.InitContainers) != 1 ||
+		!slices.Contains(podSpec.InitContainers[0].Args, "--name=default/mmlu-v1") {
+		t.Errorf("Expected the init container to download the dataset version, got %+v", podSpec.InitContainers)
+	}
+
+	args := podSpec.Containers[0].Args
+	for _, arg := range []string{
+		"--endpoint=" + "http://modelservice-qwen.default.svc:8000",
+		"--model=qwen2.5",
+		"--type=accuracy",
+		"--prompt-field=question",
+		"--reference-field=answer",
+		"--max-prompts=100",
+		"--concurrency=1,8",
+		"--duration=1m0s",
+		"--max-tokens=32",
+	} {
+		if !slices.Contains(args, arg) {
+			t.Errorf("Expected arg %s in %v", arg, args)
+		}
+	}
+}
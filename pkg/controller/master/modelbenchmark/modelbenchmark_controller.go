@@ -0,0 +1,240 @@
+package modelbenchmark
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	ctlbatchv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/benchmark"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/server/config"
+)
+
+const (
+	modelBenchmarkOnChange = "modelBenchmark.onChange"
+	mbJobOnChange          = "modelBenchmark.jobOnChange"
+
+	requeueInterval = 10 * time.Second
+)
+
+type handler struct {
+	ModelBenchmarks     ctlmlv1.ModelBenchmarkController
+	ModelBenchmarkCache ctlmlv1.ModelBenchmarkCache
+	ModelServiceCache   ctlmlv1.ModelServiceCache
+	Jobs                ctlbatchv1.JobClient
+	JobCache            ctlbatchv1.JobCache
+	PodCache            ctlcorev1.PodCache
+
+	DownloaderAccess *snapshotting.DownloaderAccess
+}
+
+func Register(ctx context.Context, mgmt *config.Management, _ config.Options) error {
+	modelBenchmarks := mgmt.LLMFactory.Ml().V1().ModelBenchmark()
+	jobs := mgmt.BatchFactory.Batch().V1().Job()
+
+	h := &handler{
+		ModelBenchmarks:     modelBenchmarks,
+		ModelBenchmarkCache: modelBenchmarks.Cache(),
+		ModelServiceCache:   mgmt.LLMFactory.Ml().V1().ModelService().Cache(),
+		Jobs:                jobs,
+		JobCache:            jobs.Cache(),
+		PodCache:            mgmt.CoreFactory.Core().V1().Pod().Cache(),
+
+		DownloaderAccess: snapshotting.NewDownloaderAccess(mgmt),
+	}
+
+	modelBenchmarks.OnChange(ctx, modelBenchmarkOnChange, h.OnChange)
+	jobs.OnChange(ctx, mbJobOnChange, h.OnJobChange)
+	return nil
+}
+
+// OnChange starts the benchmark job once the model service is ready
+func (h *handler) OnChange(_ string, mb *mlv1.ModelBenchmark) (*mlv1.ModelBenchmark, error) {
+	if mb == nil || mb.DeletionTimestamp != nil || isFinished(mb) {
+		return mb, nil
+	}
+
+	jobName := getJobName(mb.Name)
+	if _, err := h.JobCache.Get(mb.Namespace, jobName); err == nil {
+		return mb, nil
+	} else if !errors.IsNotFound(err) {
+		return mb, fmt.Errorf("failed to get job %s/%s: %w", mb.Namespace, jobName, err)
+	}
+
+	ms, err := h.ModelServiceCache.Get(mb.Namespace, mb.Spec.ModelService)
+	if err != nil && !errors.IsNotFound(err) {
+		return mb, err
+	} else if err != nil {
+		return h.updateStatus(mb, mlv1.ModelBenchmarkPhaseFailed,
+			fmt.Sprintf("model service %s not found", mb.Spec.ModelService))
+	}
+
+	if ms.Status.ReadyReplicas == 0 {
+		h.ModelBenchmarks.EnqueueAfter(mb.Namespace, mb.Name, requeueInterval)
+		return h.updateStatus(mb, mlv1.ModelBenchmarkPhasePending,
+			fmt.Sprintf("waiting for model service %s to be ready", ms.Name))
+	}
+
+	// the dataset version is downloaded by the downloader service account
+	if err = h.DownloaderAccess.Ensure(mb.Namespace); err != nil {
+		return mb, err
+	}
+
+	logrus.Infof("creating benchmark job of model benchmark %s/%s", mb.Namespace, mb.Name)
+	if _, err = h.Jobs.Create(constructJob(mb, ms)); err != nil && !errors.IsAlreadyExists(err) {
+		return mb, fmt.Errorf("failed to create job %s/%s: %w", mb.Namespace, jobName, err)
+	}
+
+	mbCopy := mb.DeepCopy()
+	mbCopy.Status.Phase = mlv1.ModelBenchmarkPhaseRunning
+	mbCopy.Status.Message = "benchmark is running"
+	mbCopy.Status.JobName = jobName
+	mbCopy.Status.StartTime = &metav1.Time{Time: time.Now()}
+	mbCopy.Status.Environment = constructEnvironment(ms)
+	mlv1.Ready.False(mbCopy)
+	mlv1.Ready.Message(mbCopy, mbCopy.Status.Message)
+	return h.ModelBenchmarks.UpdateStatus(mbCopy)
+}
+
+// OnJobChange records the result of the finished benchmark job
+func (h *handler) OnJobChange(_ string, job *batchv1.Job) (*batchv1.Job, error) {
+	if job == nil || job.DeletionTimestamp != nil || job.Labels[constant.LabelModelBenchmarkName] == "" {
+		return job, nil
+	}
+
+	mb, err := h.ModelBenchmarkCache.Get(job.Namespace, job.Labels[constant.LabelModelBenchmarkName])
+	if err != nil && errors.IsNotFound(err) {
+		return job, nil
+	} else if err != nil {
+		return job, err
+	}
+	if isFinished(mb) {
+		return job, nil
+	}
+
+	switch {
+	case isJobConditionTrue(job, batchv1.JobComplete):
+		message, err := h.getTerminationMessage(job)
+		if err != nil {
+			return job, err
+		}
+		report, err := benchmark.ParseReport(message)
+		if err != nil {
+			_, err = h.updateStatus(mb, mlv1.ModelBenchmarkPhaseFailed, err.Error())
+			return job, err
+		}
+
+		mbCopy := mb.DeepCopy()
+		mbCopy.Status.Results = report.Results
+		mbCopy.Status.Accuracy = report.Accuracy
+		_, err = h.updateStatus(mbCopy, mlv1.ModelBenchmarkPhaseSucceeded, "benchmark is completed")
+		return job, err
+	case isJobConditionTrue(job, batchv1.JobFailed):
+		message, err := h.getTerminationMessage(job)
+		if err != nil {
+			return job, err
+		}
+		if message == "" {
+			message = getJobConditionMessage(job, batchv1.JobFailed)
+		}
+		_, err = h.updateStatus(mb, mlv1.ModelBenchmarkPhaseFailed, message)
+		return job, err
+	}
+
+	return job, nil
+}
+
+func (h *handler) updateStatus(mb *mlv1.ModelBenchmark, phase mlv1.ModelBenchmarkPhase,
+	message string) (*mlv1.ModelBenchmark, error) {
+	mbCopy := mb.DeepCopy()
+	mbCopy.Status.Phase = phase
+	mbCopy.Status.Message = message
+	switch phase {
+	case mlv1.ModelBenchmarkPhaseSucceeded:
+		mlv1.Ready.True(mbCopy)
+	case mlv1.ModelBenchmarkPhaseFailed:
+		mlv1.Ready.False(mbCopy)
+		mlv1.Ready.Reason(mbCopy, string(phase))
+	default:
+		mlv1.Ready.False(mbCopy)
+	}
+	mlv1.Ready.Message(mbCopy, message)
+	if phase == mlv1.ModelBenchmarkPhaseSucceeded || phase == mlv1.ModelBenchmarkPhaseFailed {
+		mbCopy.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	}
+
+	if reflect.DeepEqual(mb.Status, mbCopy.Status) {
+		return mb, nil
+	}
+	return h.ModelBenchmarks.UpdateStatus(mbCopy)
+}
+
+// getTerminationMessage returns the termination message of the benchmark container, which is the benchmark
+// report if the job is completed or the error message if the job is failed
+func (h *handler) getTerminationMessage(job *batchv1.Job) (string, error) {
+	pods, err := h.PodCache.List(job.Namespace, labels.SelectorFromSet(map[string]string{
+		batchv1.JobNameLabel: job.Name,
+	}))
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods of job %s/%s: %w", job.Namespace, job.Name, err)
+	}
+
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name == benchmarkContainerName && cs.State.Terminated != nil && cs.State.Terminated.Message != "" {
+				return cs.State.Terminated.Message, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func constructEnvironment(ms *mlv1.ModelService) *mlv1.ModelBenchmarkEnvironment {
+	env := &mlv1.ModelBenchmarkEnvironment{
+		Model:        ms.Spec.ModelName,
+		Replicas:     ms.Spec.Replicas,
+		NodeSelector: ms.Spec.Template.Spec.NodeSelector,
+		Accelerators: ms.Spec.Accelerators,
+	}
+	if containers := ms.Spec.Template.Spec.Containers; len(containers) > 0 {
+		env.Image = containers[0].Image
+		env.Args = containers[0].Args
+		env.Resources = *containers[0].Resources.DeepCopy()
+	}
+	return env.DeepCopy()
+}
+
+func isFinished(mb *mlv1.ModelBenchmark) bool {
+	return mb.Status.Phase == mlv1.ModelBenchmarkPhaseSucceeded || mb.Status.Phase == mlv1.ModelBenchmarkPhaseFailed
+}
+
+func isJobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func getJobConditionMessage(job *batchv1.Job, conditionType batchv1.JobConditionType) string {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType {
+			return c.Message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,387 @@
+package modelservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	"github.com/llmos-ai/llmos-operator/pkg/indexeres"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	loraAdapterVolumeName    = "lora-adapters"
+	loraAdapterMountPath     = "/root/.cache/lora-adapters"
+	adapterConfigVolumeName  = "lora-adapter-config"
+	adapterConfigMountPath   = "/etc/llmos/lora-adapters"
+	adapterConfigMapAppendix = "adapters"
+	adapterLoaderName        = "adapter-loader"
+	adapterLoaderMode        = "load-adapters"
+	llmosModeEnvName         = "LLMOS_MODE"
+	enableLoRAArg            = "--enable-lora"
+	runtimeLoRAUpdatingEnv   = "VLLM_ALLOW_RUNTIME_LORA_UPDATING"
+
+	servedModelsPath    = "/v1/models"
+	servedModelsTimeout = 5 * time.Second
+	// the pods are probed again with backoff until the adapters are loaded
+	minAdapterProbeInterval = 10 * time.Second
+	maxAdapterProbeInterval = 5 * time.Minute
+)
+
+// adaptersEnabled returns true if the model service has adapters or LoRA is enabled by the args of the serving
+// container, the adapter loader is installed as long as the adapters are enabled
+func adaptersEnabled(ms *mlv1.ModelService) bool {
+	if len(ms.Spec.Adapters) > 0 {
+		return true
+	}
+	containers := ms.Spec.Template.Spec.Containers
+	return len(containers) > 0 && slices.Contains(containers[0].Args, enableLoRAArg)
+}
+
+// addAdapters adds a shared volume to the pod and the adapter loader sidecar once the adapters are enabled, the
+// loader downloads the adapters listed in the adapters configMap into the volume and loads them into vLLM at runtime.
+// The pod spec doesn't depend on the listed adapters, so adding or removing an adapter only updates the configMap.
+// Enabling or disabling the adapters, i.e., adding the first adapter or removing the last one without
+// --enable-lora in the args, changes the pod spec and restarts the pods.
+// If no service account is specified, the pod runs with the downloader service account, and its token is
+// only mounted into the adapter loader.
+func addAdapters(ms *mlv1.ModelService, podSpec *corev1.PodSpec) {
+	if !adaptersEnabled(ms) || len(podSpec.Containers) == 0 {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes,
+		corev1.Volume{
+			Name: loraAdapterVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		corev1.Volume{
+			Name: adapterConfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: getAdapterConfigMapName(ms.Name)},
+					Optional:             ptr.To(true),
+				},
+			},
+		},
+	)
+	adapterMount := corev1.VolumeMount{
+		Name:      loraAdapterVolumeName,
+		MountPath: loraAdapterMountPath,
+	}
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, adapterMount)
+	container.Env = append(container.Env, corev1.EnvVar{Name: runtimeLoRAUpdatingEnv, Value: "True"})
+
+	loaderMounts := []corev1.VolumeMount{
+		adapterMount,
+		{
+			Name:      adapterConfigVolumeName,
+			MountPath: adapterConfigMountPath,
+			ReadOnly:  true,
+		},
+	}
+	if podSpec.ServiceAccountName == "" {
+		podSpec.ServiceAccountName = snapshotting.DownloaderServiceAccountName
+		podSpec.AutomountServiceAccountToken = ptr.To(false)
+		podSpec.Volumes = append(podSpec.Volumes, snapshotting.DownloaderTokenVolume())
+		loaderMounts = append(loaderMounts, snapshotting.DownloaderTokenVolumeMount())
+	}
+
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:  adapterLoaderName,
+		Image: settings.ModelDownloaderImage.Get(),
+		Env:   []corev1.EnvVar{{Name: llmosModeEnvName, Value: adapterLoaderMode}},
+		Args: []string{
+			fmt.Sprintf("--config-dir=%s", adapterConfigMountPath),
+			fmt.Sprintf("--output-dir=%s", loraAdapterMountPath),
+			fmt.Sprintf("--endpoint=http://localhost:%d", getServingPort(podSpec.Containers[0])),
+		},
+		VolumeMounts: loaderMounts,
+	})
+}
+
+// buildAdapterArgs enables LoRA, the adapters are loaded at runtime by the adapter loader
+func buildAdapterArgs(args []string, adapters []mlv1.ModelServiceAdapter) []string {
+	if len(adapters) == 0 || slices.Contains(args, enableLoRAArg) {
+		return args
+	}
+	return append(args, enableLoRAArg)
+}
+
+// constructAdapterConfigMap builds the configMap read by the adapter loader, the keys are the adapter names and
+// the values are the namespaced names of the Models storing the adapter weights
+func constructAdapterConfigMap(ms *mlv1.ModelService, adapters []mlv1.ModelServiceAdapter) *corev1.ConfigMap {
+	data := make(map[string]string, len(adapters))
+	for _, adapter := range adapters {
+		data[adapter.Name] = fmt.Sprintf("%s/%s", ms.Namespace, adapter.Model)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getAdapterConfigMapName(ms.Name),
+			Namespace: ms.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(ms, ms.GroupVersionKind()),
+			},
+			Labels: map[string]string{
+				constant.LabelModelServiceName: ms.Name,
+			},
+		},
+		Data: data,
+	}
+}
+
+func getAdapterConfigMapName(name string) string {
+	return getFormattedMSName(name, adapterConfigMapAppendix)
+}
+
+func getServingPort(container corev1.Container) int32 {
+	if len(container.Ports) == 0 {
+		return vllmPort
+	}
+	return container.Ports[0].ContainerPort
+}
+
+// reconcileAdapters updates the adapters configMap with the adapters whose models are ready, and returns the status
+// of all adapters
+func (h *handler) reconcileAdapters(ms *mlv1.ModelService) ([]mlv1.ModelServiceAdapterStatus, error) {
+	if !adaptersEnabled(ms) {
+		return nil, nil
+	}
+
+	adapters := make([]mlv1.ModelServiceAdapter, 0, len(ms.Spec.Adapters))
+	var statuses []mlv1.ModelServiceAdapterStatus
+	for _, adapter := range ms.Spec.Adapters {
+		status := mlv1.ModelServiceAdapterStatus{
+			Name:  adapter.Name,
+			Model: adapter.Model,
+		}
+
+		model, err := h.ModelCache.Get(ms.Namespace, adapter.Model)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get model %s/%s: %w", ms.Namespace, adapter.Model, err)
+		}
+
+		switch {
+		case err != nil:
+			status.Message = fmt.Sprintf("model %s not found", adapter.Model)
+		case !mlv1.Ready.IsTrue(model):
+			status.Message = fmt.Sprintf("model %s is not ready", adapter.Model)
+		default:
+			adapters = append(adapters, adapter)
+		}
+		statuses = append(statuses, status)
+	}
+
+	// the pods run with the downloader service account as long as the adapter loader is installed
+	if ms.Spec.Template.Spec.ServiceAccountName == "" {
+		if err := h.DownloaderAccess.Ensure(ms.Namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	// the configMap is kept without adapters, so that the adapter loader unloads the removed ones
+	return statuses, h.reconcileAdapterConfigMap(ms, adapters)
+}
+
+func (h *handler) reconcileAdapterConfigMap(ms *mlv1.ModelService, adapters []mlv1.ModelServiceAdapter) error {
+	cm := constructAdapterConfigMap(ms, adapters)
+	found, err := h.ConfigMapCache.Get(cm.Namespace, cm.Name)
+	if err != nil && errors.IsNotFound(err) {
+		logrus.Infof("creating adapters configMap of model %s/%s", ms.Namespace, ms.Name)
+		_, err = h.ConfigMaps.Create(cm)
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(found.Data, cm.Data) {
+		return nil
+	}
+	logrus.Debugf("updating adapters configMap of model %s/%s", ms.Namespace, ms.Name)
+	toUpdate := found.DeepCopy()
+	toUpdate.Data = cm.Data
+	_, err = h.ConfigMaps.Update(toUpdate)
+	return err
+}
+
+// updateAdapterStatus marks the adapters as ready once they are served by all the ready pods of the model service,
+// the served adapters are listed from the vLLM servers of the pods by the adapter prober in the background. The
+// adapters whose models are missing or not ready aren't retried, the model service is enqueued by the model watch.
+func (h *handler) updateAdapterStatus(ms *mlv1.ModelService, statuses []mlv1.ModelServiceAdapterStatus) error {
+	if len(statuses) == 0 {
+		h.adapterProber.forget(ms.Namespace, ms.Name)
+	} else {
+		endpoints, err := h.listPodEndpoints(ms)
+		if err != nil {
+			return err
+		}
+		served, ok := h.adapterProber.get(ms.Namespace, ms.Name, endpoints)
+		if !ok {
+			// keep the readiness until the pods are probed, the model service is enqueued after that
+			keepAdapterReadiness(statuses, ms.Status.Adapters)
+		} else if !setAdapterReadiness(statuses, served) {
+			// the adapters are loaded by the adapter loaders asynchronously, the pods are probed again with backoff
+			h.adapterProber.retry(ms.Namespace, ms.Name)
+		} else {
+			h.adapterProber.reset(ms.Namespace, ms.Name)
+		}
+	}
+
+	if reflect.DeepEqual(ms.Status.Adapters, statuses) {
+		return nil
+	}
+
+	msCopy := ms.DeepCopy()
+	msCopy.Status.Adapters = statuses
+	_, err := h.ModelServices.UpdateStatus(msCopy)
+	return err
+}
+
+// setAdapterReadiness sets the readiness of the adapters whose models are ready by the models served by the ready
+// pods, it returns whether all the adapters whose models are ready are loaded
+func setAdapterReadiness(statuses []mlv1.ModelServiceAdapterStatus, served map[string]sets.Set[string]) bool {
+	allReady := true
+	for i := range statuses {
+		// the adapters whose models are not ready are not served
+		if statuses[i].Message != "" {
+			continue
+		}
+
+		loaded := 0
+		for _, models := range served {
+			if models.Has(statuses[i].Name) {
+				loaded++
+			}
+		}
+		statuses[i].Ready = len(served) > 0 && loaded == len(served)
+		switch {
+		case statuses[i].Ready:
+			statuses[i].Message = "adapter is loaded"
+		case len(served) == 0:
+			statuses[i].Message = "adapter is waiting for the ready pods"
+		default:
+			statuses[i].Message = fmt.Sprintf("adapter is loaded by %d of %d ready pods", loaded, len(served))
+		}
+		allReady = allReady && statuses[i].Ready
+	}
+	return allReady
+}
+
+// keepAdapterReadiness copies the last readiness of the adapters whose models are ready
+func keepAdapterReadiness(statuses, last []mlv1.ModelServiceAdapterStatus) {
+	for i := range statuses {
+		if statuses[i].Message != "" {
+			continue
+		}
+		statuses[i].Message = "adapter is waiting for the ready pods"
+		for _, l := range last {
+			if l.Name == statuses[i].Name && l.Model == statuses[i].Model {
+				statuses[i].Ready, statuses[i].Message = l.Ready, l.Message
+			}
+		}
+	}
+}
+
+// listPodEndpoints returns the serving endpoints of the ready pods of the model service by the pod names
+func (h *handler) listPodEndpoints(ms *mlv1.ModelService) (map[string]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(GetModelServiceSelector(ms))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert LabelSelector: %v", err)
+	}
+	pods, err := h.PodCache.List(ms.Namespace, selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods of model %s/%s: %w", ms.Namespace, ms.Name, err)
+	}
+
+	endpoints := make(map[string]string, len(pods))
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.PodIP == "" || !isPodReady(pod) || len(pod.Spec.Containers) == 0 {
+			continue
+		}
+		endpoints[pod.Name] = fmt.Sprintf("http://%s", net.JoinHostPort(pod.Status.PodIP,
+			strconv.Itoa(int(getServingPort(pod.Spec.Containers[0])))))
+	}
+	return endpoints, nil
+}
+
+// syncModelServicesByAdapterModel enqueues the model services whose adapters are stored in the model, so that the
+// adapters are loaded once their models are ready
+func (h *handler) syncModelServicesByAdapterModel(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	model, ok := obj.(*mlv1.Model)
+	if !ok {
+		return nil, nil
+	}
+	mss, err := h.ModelServiceCache.GetByIndex(indexeres.ModelServiceAdapterIndex, model.Namespace+"/"+model.Name)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]relatedresource.Key, 0, len(mss))
+	for _, ms := range mss {
+		keys = append(keys, relatedresource.Key{Namespace: ms.Namespace, Name: ms.Name})
+	}
+	return keys, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// listServedModels returns the ids of the models served by the vLLM server, the loaded LoRA adapters are listed by
+// their names
+func listServedModels(ctx context.Context, endpoint string) (sets.Set[string], error) {
+	ctx, cancel := context.WithTimeout(ctx, servedModelsTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+servedModelsPath, nil)
+	if err != nil {
+		return sets.New[string](), err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return sets.New[string](), err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return sets.New[string](), fmt.Errorf("failed to list models: %s", resp.Status)
+	}
+
+	models := struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return sets.New[string](), fmt.Errorf("failed to decode models: %w", err)
+	}
+	ids := sets.New[string]()
+	for _, model := range models.Data {
+		ids.Insert(model.ID)
+	}
+	return ids, nil
+}
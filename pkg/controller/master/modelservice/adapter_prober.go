@@ -0,0 +1,131 @@
+package modelservice
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// adapterProber lists the models served by the ready pods of the model services in the background, so that the
+// reconciliation isn't blocked by the requests to the pods. The result is kept until the ready pods are changed or
+// the pods are probed again by the retry.
+type adapterProber struct {
+	ctx    context.Context
+	mu     sync.Mutex
+	probes map[string]*adapterProbe
+
+	enqueue func(namespace, name string)
+	list    func(ctx context.Context, endpoint string) (sets.Set[string], error)
+	after   func(d time.Duration, f func())
+}
+
+type adapterProbe struct {
+	// endpoints are the serving endpoints of the probed pods by the pod names
+	endpoints map[string]string
+	served    map[string]sets.Set[string]
+	probing   bool
+	retrying  bool
+	retries   int
+}
+
+func newAdapterProber(ctx context.Context, enqueue func(namespace, name string)) *adapterProber {
+	return &adapterProber{
+		ctx:     ctx,
+		probes:  map[string]*adapterProbe{},
+		enqueue: enqueue,
+		list:    listServedModels,
+		after: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+}
+
+// get returns the models served by each pod if the pods of the endpoints are probed, otherwise the pods are probed
+// in the background and the model service is enqueued once they're probed. The pods whose models can't be listed
+// are counted as serving nothing.
+func (p *adapterProber) get(namespace, name string, endpoints map[string]string) (map[string]sets.Set[string], bool) {
+	key := namespace + "/" + name
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	probe, ok := p.probes[key]
+	if ok && maps.Equal(probe.endpoints, endpoints) {
+		return probe.served, !probe.probing
+	}
+
+	probe = &adapterProbe{endpoints: endpoints}
+	p.probes[key] = probe
+	if len(endpoints) == 0 {
+		probe.served = map[string]sets.Set[string]{}
+		return probe.served, true
+	}
+	probe.probing = true
+	go p.probe(namespace, name, probe)
+	return nil, false
+}
+
+// retry probes the pods again after the backoff interval, which is doubled by each retry until the pods are changed
+// or the retries are reset
+func (p *adapterProber) retry(namespace, name string) {
+	key := namespace + "/" + name
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	probe, ok := p.probes[key]
+	if !ok || probe.probing || probe.retrying {
+		return
+	}
+	probe.retrying = true
+	delay := min(minAdapterProbeInterval<<min(probe.retries, 5), maxAdapterProbeInterval)
+	probe.retries++
+	p.after(delay, func() {
+		p.mu.Lock()
+		if p.probes[key] != probe {
+			p.mu.Unlock()
+			return
+		}
+		probe.retrying, probe.probing = false, true
+		p.mu.Unlock()
+		p.probe(namespace, name, probe)
+	})
+}
+
+// reset resets the backoff of the model service once its adapters are loaded
+func (p *adapterProber) reset(namespace, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if probe, ok := p.probes[namespace+"/"+name]; ok {
+		probe.retries = 0
+	}
+}
+
+// forget drops the result of the model service without adapters or being deleted
+func (p *adapterProber) forget(namespace, name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.probes, namespace+"/"+name)
+}
+
+func (p *adapterProber) probe(namespace, name string, probe *adapterProbe) {
+	served := make(map[string]sets.Set[string], len(probe.endpoints))
+	for pod, endpoint := range probe.endpoints {
+		models, err := p.list(p.ctx, endpoint)
+		if err != nil {
+			logrus.Debugf("failed to list the models served by pod %s/%s: %v", namespace, pod, err)
+		}
+		served[pod] = models
+	}
+
+	p.mu.Lock()
+	probe.served, probe.probing = served, false
+	current := p.probes[namespace+"/"+name] == probe
+	p.mu.Unlock()
+
+	if current {
+		p.enqueue(namespace, name)
+	}
+}
@@ -0,0 +1,249 @@
+package modelservice
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	"github.com/llmos-ai/llmos-operator/pkg/utils"
+)
+
+func TestBuildArgs_WithAdapters(t *testing.T) {
+	ms := &mlv1.ModelService{}
+	ms.Spec.ModelName = "test-model"
+	ms.Spec.Template.Spec.Containers = []v1.Container{
+		{
+			Args: []string{"--max-lora-rank=64"},
+		},
+	}
+	ms.Spec.Adapters = []mlv1.ModelServiceAdapter{
+		{Name: "sql", Model: "sql-lora"},
+		{Name: "chat", Model: "chat-lora"},
+	}
+
+	// the adapters are loaded at runtime, so the args don't depend on the adapters
+	expectedArgs := []string{
+		"--max-lora-rank=64",
+		"--model=test-model",
+		"--enable-lora",
+	}
+
+	result := buildArgs(ms)
+
+	if !utils.EqualIgnoreOrder(result, expectedArgs) {
+		t.Errorf("Expected %v, got %v", expectedArgs, result)
+	}
+}
+
+func TestAddAdapters(t *testing.T) {
+	ms := &mlv1.ModelService{
+		ObjectMeta: metav1.ObjectMeta{Name: "qwen", Namespace: "team-a"},
+	}
+	ms.Spec.Adapters = []mlv1.ModelServiceAdapter{
+		{Name: "sql", Model: "sql-lora"},
+	}
+	podSpec := &v1.PodSpec{
+		Containers: []v1.Container{{Name: "vllm", Ports: []v1.ContainerPort{{ContainerPort: 8080}}}},
+	}
+
+	addAdapters(ms, podSpec)
+
+	if len(podSpec.Containers) != 2 || podSpec.Containers[1].Name != adapterLoaderName {
+		t.Fatalf("Expected the adapter loader sidecar, got %v", podSpec.Containers)
+	}
+	loader := podSpec.Containers[1]
+	if !containsArg(loader.Args, "--endpoint=http://localhost:8080") {
+		t.Errorf("Expected the adapter loader to load the adapters into the serving container, got %v", loader.Args)
+	}
+	if len(loader.VolumeMounts) != 3 {
+		t.Errorf("Expected the adapter loader to mount the adapter volume, the config and the token, got %v",
+			loader.VolumeMounts)
+	}
+	if podSpec.ServiceAccountName != snapshotting.DownloaderServiceAccountName {
+		t.Errorf("Expected the downloader service account, got %s", podSpec.ServiceAccountName)
+	}
+	if podSpec.AutomountServiceAccountToken == nil || *podSpec.AutomountServiceAccountToken {
+		t.Errorf("Expected the service account token not to be mounted into the serving container")
+	}
+	serving := podSpec.Containers[0]
+	if len(serving.VolumeMounts) != 1 || serving.VolumeMounts[0].MountPath != loraAdapterMountPath {
+		t.Errorf("Expected the serving container to mount the adapter volume, got %v", serving.VolumeMounts)
+	}
+	if len(serving.Env) != 1 || serving.Env[0].Name != runtimeLoRAUpdatingEnv {
+		t.Errorf("Expected the runtime LoRA updating to be enabled, got %v", serving.Env)
+	}
+
+	// the pod spec doesn't change with the adapters, so that the pods are not restarted
+	other := ms.DeepCopy()
+	other.Spec.Adapters = append(other.Spec.Adapters, mlv1.ModelServiceAdapter{Name: "chat", Model: "chat-lora"})
+	otherSpec := &v1.PodSpec{
+		Containers: []v1.Container{{Name: "vllm", Ports: []v1.ContainerPort{{ContainerPort: 8080}}}},
+	}
+	addAdapters(other, otherSpec)
+	if !reflect.DeepEqual(podSpec, otherSpec) {
+		t.Errorf("Expected the pod spec not to depend on the adapters")
+	}
+
+	// the adapter loader is kept without adapters if LoRA is enabled, so that the first adapter doesn't restart
+	// the pods
+	enabled := ms.DeepCopy()
+	enabled.Spec.Adapters = nil
+	enabled.Spec.Template.Spec.Containers = []v1.Container{{Name: "vllm", Args: []string{enableLoRAArg}}}
+	enabledSpec := &v1.PodSpec{
+		Containers: []v1.Container{{Name: "vllm", Ports: []v1.ContainerPort{{ContainerPort: 8080}}}},
+	}
+	addAdapters(enabled, enabledSpec)
+	if !reflect.DeepEqual(podSpec, enabledSpec) {
+		t.Errorf("Expected the adapter loader to be installed once LoRA is enabled")
+	}
+
+	disabled := ms.DeepCopy()
+	disabled.Spec.Adapters = nil
+	disabledSpec := &v1.PodSpec{
+		Containers: []v1.Container{{Name: "vllm", Ports: []v1.ContainerPort{{ContainerPort: 8080}}}},
+	}
+	addAdapters(disabled, disabledSpec)
+	if len(disabledSpec.Containers) != 1 || len(disabledSpec.Volumes) != 0 {
+		t.Errorf("Expected no adapter loader without adapters, got %v", disabledSpec)
+	}
+}
+
+func TestConstructAdapterConfigMap(t *testing.T) {
+	ms := &mlv1.ModelService{
+		ObjectMeta: metav1.ObjectMeta{Name: "qwen", Namespace: "team-a"},
+	}
+	cm := constructAdapterConfigMap(ms, []mlv1.ModelServiceAdapter{{Name: "sql", Model: "sql-lora"}})
+
+	if cm.Name != "modelservice-qwen-adapters" || cm.Namespace != "team-a" {
+		t.Errorf("Expected configMap team-a/modelservice-qwen-adapters, got %s/%s", cm.Namespace, cm.Name)
+	}
+	if !reflect.DeepEqual(cm.Data, map[string]string{"sql": "team-a/sql-lora"}) {
+		t.Errorf("Expected the adapter models in the configMap, got %v", cm.Data)
+	}
+}
+
+func TestSetAdapterReadiness(t *testing.T) {
+	newStatuses := func() []mlv1.ModelServiceAdapterStatus {
+		return []mlv1.ModelServiceAdapterStatus{
+			{Name: "sql", Model: "sql-lora"},
+			{Name: "chat", Model: "chat-lora"},
+			{Name: "pending", Model: "pending-lora", Message: "model pending-lora is not ready"},
+		}
+	}
+
+	statuses := newStatuses()
+	allReady := setAdapterReadiness(statuses, map[string]sets.Set[string]{
+		"pod-0": sets.New("qwen", "sql", "chat"),
+		"pod-1": sets.New("qwen", "sql"),
+	})
+	if allReady {
+		t.Errorf("Expected not all adapters to be ready")
+	}
+	if !statuses[0].Ready || statuses[0].Message != "adapter is loaded" {
+		t.Errorf("Expected adapter sql to be ready, got %+v", statuses[0])
+	}
+	if statuses[1].Ready || statuses[1].Message != "adapter is loaded by 1 of 2 ready pods" {
+		t.Errorf("Expected adapter chat to be partially loaded, got %+v", statuses[1])
+	}
+	if statuses[2].Ready || statuses[2].Message != "model pending-lora is not ready" {
+		t.Errorf("Expected adapter pending to keep its message, got %+v", statuses[2])
+	}
+
+	statuses = newStatuses()[:2]
+	if !setAdapterReadiness(statuses, map[string]sets.Set[string]{"pod-0": sets.New("qwen", "sql", "chat")}) {
+		t.Errorf("Expected all adapters to be ready, got %+v", statuses)
+	}
+
+	statuses = newStatuses()[:1]
+	setAdapterReadiness(statuses, nil)
+	if statuses[0].Ready || statuses[0].Message != "adapter is waiting for the ready pods" {
+		t.Errorf("Expected adapter sql to wait for the ready pods, got %+v", statuses[0])
+	}
+
+	// the adapters whose models aren't ready aren't retried, they're enqueued by the model watch
+	statuses = []mlv1.ModelServiceAdapterStatus{newStatuses()[0], newStatuses()[2]}
+	if !setAdapterReadiness(statuses, map[string]sets.Set[string]{"pod-0": sets.New("qwen", "sql")}) {
+		t.Errorf("Expected the loadable adapters to be ready, got %+v", statuses)
+	}
+}
+
+func TestAdapterProber(t *testing.T) {
+	enqueued := make(chan string, 10)
+	p := newAdapterProber(context.Background(), func(namespace, name string) {
+		enqueued <- namespace + "/" + name
+	})
+	var mu sync.Mutex
+	served := sets.New("qwen")
+	p.list = func(_ context.Context, _ string) (sets.Set[string], error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return served.Clone(), nil
+	}
+	var retries []func()
+	var delays []time.Duration
+	p.after = func(d time.Duration, f func()) {
+		delays = append(delays, d)
+		retries = append(retries, f)
+	}
+	endpoints := map[string]string{"pod-0": "http://10.0.0.1:8000"}
+
+	// the pods are probed in the background and the model service is enqueued after that
+	if _, ok := p.get("default", "qwen", endpoints); ok {
+		t.Fatal("Expected the pods to be probed in the background")
+	}
+	if key := <-enqueued; key != "default/qwen" {
+		t.Errorf("Expected default/qwen to be enqueued, got %s", key)
+	}
+	result, ok := p.get("default", "qwen", endpoints)
+	if !ok || !result["pod-0"].Has("qwen") || result["pod-0"].Has("sql") {
+		t.Errorf("Expected the probed models of pod-0, got %v, %v", result, ok)
+	}
+
+	// the retries are backed off until the adapter is loaded
+	p.retry("default", "qwen")
+	p.retry("default", "qwen")
+	if len(retries) != 1 || delays[0] != minAdapterProbeInterval {
+		t.Fatalf("Expected one retry after %s, got %v", minAdapterProbeInterval, delays)
+	}
+	mu.Lock()
+	served.Insert("sql")
+	mu.Unlock()
+	retries[0]()
+	<-enqueued
+	if result, ok = p.get("default", "qwen", endpoints); !ok || !result["pod-0"].Has("sql") {
+		t.Errorf("Expected the adapter to be loaded by the retry, got %v, %v", result, ok)
+	}
+	p.retry("default", "qwen")
+	if len(delays) != 2 || delays[1] != 2*minAdapterProbeInterval {
+		t.Errorf("Expected the second retry to be backed off, got %v", delays)
+	}
+
+	// the changed pods are probed again
+	if _, ok = p.get("default", "qwen", map[string]string{"pod-1": "http://10.0.0.2:8000"}); ok {
+		t.Error("Expected the changed pods to be probed again")
+	}
+	<-enqueued
+
+	// the retry of the replaced probe is dropped
+	retries[1]()
+	select {
+	case key := <-enqueued:
+		t.Errorf("Expected the replaced probe not to be retried, got %s", key)
+	default:
+	}
+
+	// the forgotten model service is probed again
+	p.forget("default", "qwen")
+	if _, ok = p.get("default", "qwen", endpoints); ok {
+		t.Error("Expected the forgotten model service to be probed again")
+	}
+	<-enqueued
+}
@@ -0,0 +1,187 @@
+package modelservice
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/utils/reconcilehelper"
+)
+
+const (
+	canaryRevision  = "canary"
+	requeueInterval = 10 * time.Second
+)
+
+// getCanaryStatefulSetName returns the name of the canary statefulSet of the model service. The revision is put in
+// front of the prefix shared by the statefulSets of the model services, so the canary can't collide with the
+// statefulSet of another model service whatever it is named.
+func getCanaryStatefulSetName(name string) string {
+	return fmt.Sprintf("%s-%s", canaryRevision, getFormattedMSName(name, ""))
+}
+
+// constructCanaryStatefulSet builds the canary statefulSet of the model service, the canary pods keep
+// the model service selector labels so that they are served by the same service as the stable pods.
+// The startup probe replaces the stable one if the canary revision serves a different model.
+func constructCanaryStatefulSet(ms *mlv1.ModelService, startupProbe *corev1.Probe) *appsv1.StatefulSet {
+	canary := ms.Spec.Canary
+	msCopy := ms.DeepCopy()
+	msCopy.Spec.Replicas = canary.Replicas
+	if canary.ModelName != "" {
+		msCopy.Spec.ModelName = canary.ModelName
+	}
+	ApplyCanaryOverrides(&msCopy.Spec.Template, canary)
+	if startupProbe != nil && len(msCopy.Spec.Template.Spec.Containers) > 0 {
+		msCopy.Spec.Template.Spec.Containers[0].StartupProbe = startupProbe.DeepCopy()
+	}
+
+	ss := constructModelStatefulSet(msCopy)
+	ss.Name = getCanaryStatefulSetName(ms.Name)
+	ss.Labels[constant.LabelModelServiceRevision] = canaryRevision
+	ss.Spec.Selector.MatchLabels[constant.LabelModelServiceRevision] = canaryRevision
+	ss.Spec.Template.Labels[constant.LabelModelServiceRevision] = canaryRevision
+
+	return ss
+}
+
+// ApplyCanaryOverrides applies the overrides of the canary revision to the serving container of the template, the
+// env variables are overridden by name and the others are replaced if they are specified
+func ApplyCanaryOverrides(template *mlv1.ModelServiceTemplateSpec, canary *mlv1.ModelServiceCanary) {
+	if len(template.Spec.Containers) == 0 {
+		return
+	}
+
+	container := &template.Spec.Containers[0]
+	if canary.Image != "" {
+		container.Image = canary.Image
+	}
+	if len(canary.Args) > 0 {
+		container.Args = slices.Clone(canary.Args)
+	}
+	for _, env := range canary.Env {
+		i := slices.IndexFunc(container.Env, func(e corev1.EnvVar) bool { return e.Name == env.Name })
+		if i >= 0 {
+			container.Env[i] = *env.DeepCopy()
+		} else {
+			container.Env = append(container.Env, *env.DeepCopy())
+		}
+	}
+	if canary.Resources != nil {
+		container.Resources = *canary.Resources.DeepCopy()
+	}
+}
+
+// reconcileCanaryStatefulSet reconciles the canary statefulSet of the model service. Once the canary is
+// removed from the spec (promoted or rolled back), the canary statefulSet is kept until the stable
+// statefulSet is fully rolled out, so that the model service endpoint is always available.
+func (h *handler) reconcileCanaryStatefulSet(ms *mlv1.ModelService, stable *appsv1.StatefulSet,
+	startupProbe *corev1.Probe) error {
+	name := getCanaryStatefulSetName(ms.Name)
+	foundSs, err := h.StatefulSetCache.Get(ms.Namespace, name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	if ms.Spec.Canary == nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return h.removeCanaryStatefulSet(ms, stable, foundSs)
+	}
+
+	ss := constructCanaryStatefulSet(ms, startupProbe)
+	if errors.IsNotFound(err) {
+		logrus.Infof("creating canary statefulSet of model %s/%s", ms.Namespace, ms.Name)
+		_, err = h.StatefulSets.Create(ss)
+		return err
+	}
+
+	ssCopy := foundSs.DeepCopy()
+	toUpdate, toRedeploy := reconcilehelper.CopyStatefulSetFields(ss, ssCopy)
+	if toUpdate {
+		logrus.Debugf("updating modelService canary statefulSet %s/%s", foundSs.Namespace, foundSs.Name)
+		if _, err = h.StatefulSets.Update(ssCopy); err != nil {
+			return err
+		}
+	}
+
+	if toRedeploy {
+		return h.deleteStatefulSetPods(foundSs)
+	}
+
+	return nil
+}
+
+func (h *handler) removeCanaryStatefulSet(ms *mlv1.ModelService, stable, canary *appsv1.StatefulSet) error {
+	if canary.DeletionTimestamp != nil {
+		return nil
+	}
+
+	if !isStatefulSetRolledOut(stable) {
+		logrus.Debugf("waiting for stable statefulSet %s/%s to roll out before removing the canary",
+			stable.Namespace, stable.Name)
+		h.ModelServices.EnqueueAfter(ms.Namespace, ms.Name, requeueInterval)
+		return nil
+	}
+
+	logrus.Infof("removing canary statefulSet of model %s/%s", ms.Namespace, ms.Name)
+	if err := h.StatefulSets.Delete(canary.Namespace, canary.Name, &metav1.DeleteOptions{}); err != nil &&
+		!errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete canary statefulSet %s/%s: %w", canary.Namespace, canary.Name, err)
+	}
+
+	if ms.Status.Canary != nil {
+		msCopy := ms.DeepCopy()
+		msCopy.Status.Canary = nil
+		if _, err := h.ModelServices.UpdateStatus(msCopy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isStatefulSetRolledOut returns true if all the replicas of the statefulSet are updated and ready
+func isStatefulSetRolledOut(ss *appsv1.StatefulSet) bool {
+	if ss == nil || ss.Spec.Replicas == nil {
+		return false
+	}
+	replicas := *ss.Spec.Replicas
+	return ss.Status.ObservedGeneration >= ss.Generation &&
+		ss.Status.UpdatedReplicas == replicas &&
+		ss.Status.ReadyReplicas == replicas
+}
+
+// constructCanaryStatus returns the canary status of the model service, the service doesn't weight the traffic, so
+// only the share of the canary revision in the ready replicas behind the service is reported.
+func constructCanaryStatus(stable, canary *appsv1.StatefulSet) *mlv1.ModelServiceCanaryStatus {
+	if canary == nil {
+		return nil
+	}
+
+	status := &mlv1.ModelServiceCanaryStatus{
+		ReadyReplicas: canary.Status.ReadyReplicas,
+	}
+
+	var stableReady int32
+	if stable != nil {
+		stableReady = stable.Status.ReadyReplicas
+	}
+	if total := stableReady + canary.Status.ReadyReplicas; total > 0 {
+		status.ReadyReplicaPercentage = canary.Status.ReadyReplicas * 100 / total
+	}
+
+	return status
+}
+
+func isCanaryStatefulSet(ss *appsv1.StatefulSet) bool {
+	return ss.Labels[constant.LabelModelServiceRevision] == canaryRevision
+}
@@ -0,0 +1,157 @@
+package modelservice
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+)
+
+func TestConstructCanaryStatefulSet(t *testing.T) {
+	ms := &mlv1.ModelService{
+		ObjectMeta: metav1.ObjectMeta{Name: "qwen", Namespace: "default"},
+	}
+	ms.Spec.ModelName = "stable-model"
+	ms.Spec.Replicas = 3
+	ms.Spec.Template.Spec.Containers = []v1.Container{
+		{
+			Image: "vllm:stable",
+			Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8000}},
+		},
+	}
+	ms.Spec.Canary = &mlv1.ModelServiceCanary{
+		ModelName: "canary-model",
+		Replicas:  1,
+	}
+
+	ss := constructCanaryStatefulSet(ms, nil)
+
+	if ss.Name != "canary-modelservice-qwen" {
+		t.Errorf("Expected canary statefulSet name canary-modelservice-qwen, got %s", ss.Name)
+	}
+	if *ss.Spec.Replicas != 1 {
+		t.Errorf("Expected 1 canary replica, got %d", *ss.Spec.Replicas)
+	}
+	if ss.Spec.Template.Spec.Containers[0].Image != "vllm:stable" {
+		t.Errorf("Expected canary to inherit the stable image, got %s", ss.Spec.Template.Spec.Containers[0].Image)
+	}
+	if !containsArg(ss.Spec.Template.Spec.Containers[0].Args, "--model=canary-model") {
+		t.Errorf("Expected canary model arg, got %v", ss.Spec.Template.Spec.Containers[0].Args)
+	}
+
+	// canary pods must match the model service selector so that they share the service endpoint
+	for k, v := range GetModelServiceSelector(ms).MatchLabels {
+		if ss.Spec.Template.Labels[k] != v {
+			t.Errorf("Expected canary pod label %s=%s, got %s", k, v, ss.Spec.Template.Labels[k])
+		}
+	}
+	if ss.Spec.Selector.MatchLabels[constant.LabelModelServiceRevision] != canaryRevision {
+		t.Errorf("Expected canary selector to contain the revision label, got %v", ss.Spec.Selector.MatchLabels)
+	}
+	if ms.Spec.ModelName != "stable-model" {
+		t.Errorf("Expected the model service not to be modified, got model %s", ms.Spec.ModelName)
+	}
+}
+
+func TestApplyCanaryOverrides(t *testing.T) {
+	template := &mlv1.ModelServiceTemplateSpec{}
+	template.Spec.Containers = []v1.Container{
+		{
+			Image: "vllm:stable",
+			Args:  []string{"--max-model-len=4096"},
+			Env:   []v1.EnvVar{{Name: "A", Value: "stable"}, {Name: "B", Value: "stable"}},
+		},
+	}
+	canary := &mlv1.ModelServiceCanary{
+		Image: "vllm:canary",
+		Env:   []v1.EnvVar{{Name: "B", Value: "canary"}, {Name: "C", Value: "canary"}},
+		Resources: &v1.ResourceRequirements{
+			Limits: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("2")},
+		},
+	}
+
+	ApplyCanaryOverrides(template, canary)
+
+	container := template.Spec.Containers[0]
+	if container.Image != "vllm:canary" {
+		t.Errorf("Expected the canary image, got %s", container.Image)
+	}
+	if !containsArg(container.Args, "--max-model-len=4096") {
+		t.Errorf("Expected the stable args to be kept, got %v", container.Args)
+	}
+	expectedEnv := []v1.EnvVar{{Name: "A", Value: "stable"}, {Name: "B", Value: "canary"}, {Name: "C", Value: "canary"}}
+	if !reflect.DeepEqual(container.Env, expectedEnv) {
+		t.Errorf("Expected env %v, got %v", expectedEnv, container.Env)
+	}
+	if gpu := container.Resources.Limits["nvidia.com/gpu"]; gpu.String() != "2" {
+		t.Errorf("Expected the canary resources, got %v", container.Resources)
+	}
+}
+
+func TestConstructCanaryStatus(t *testing.T) {
+	newSs := func(ready int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{ReadyReplicas: ready}}
+	}
+
+	tests := []struct {
+		name     string
+		stable   *appsv1.StatefulSet
+		canary   *appsv1.StatefulSet
+		expected *mlv1.ModelServiceCanaryStatus
+	}{
+		{
+			name:     "no canary",
+			stable:   newSs(3),
+			canary:   nil,
+			expected: nil,
+		},
+		{
+			name:     "canary not ready",
+			stable:   newSs(3),
+			canary:   newSs(0),
+			expected: &mlv1.ModelServiceCanaryStatus{},
+		},
+		{
+			name:     "split by ready replicas",
+			stable:   newSs(3),
+			canary:   newSs(1),
+			expected: &mlv1.ModelServiceCanaryStatus{ReadyReplicas: 1, ReadyReplicaPercentage: 25},
+		},
+		{
+			name:     "stable not found",
+			stable:   nil,
+			canary:   newSs(2),
+			expected: &mlv1.ModelServiceCanaryStatus{ReadyReplicas: 2, ReadyReplicaPercentage: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := constructCanaryStatus(tt.stable, tt.canary)
+			if tt.expected == nil {
+				if result != nil {
+					t.Errorf("Expected nil, got %+v", result)
+				}
+				return
+			}
+			if result == nil || *result != *tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func containsArg(args []string, arg string) bool {
+	for _, a := range args {
+		if a == arg {
+			return true
+		}
+	}
+	return false
+}
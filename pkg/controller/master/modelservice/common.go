@@ -2,7 +2,9 @@ package modelservice
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -25,6 +27,7 @@ const (
 	vllmEngineName = "vllm"
 	modelScopeName = "modelscope"
 	vGPUNumber     = "volcano.sh/vgpu-number"
+	vllmPort       = 8000
 )
 
 func constructModelStatefulSet(ms *mlv1.ModelService) *v1.StatefulSet {
@@ -35,6 +38,7 @@ func constructModelStatefulSet(ms *mlv1.ModelService) *v1.StatefulSet {
 	}
 	podSpec := *ms.Spec.Template.Spec.DeepCopy()
 	podSpec.InitContainers = constructInitContainers(ms, podSpec.Containers[0])
+	addAdapters(ms, &podSpec)
 	ss := &v1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      getFormattedMSName(ms.Name, ""),
@@ -66,27 +70,35 @@ func constructModelStatefulSet(ms *mlv1.ModelService) *v1.StatefulSet {
 	container := &ss.Spec.Template.Spec.Containers[0]
 	container.Args = buildArgs(ms)
 	container.Env = buildEnvs(ms, podSpec.Containers[0])
+	setDefaultProbes(container)
+
+	// Copy all the labels to the pod
+	ls := &ss.Spec.Template.Labels
+	for k, v := range ms.Labels {
+		(*ls)[k] = v
+	}
+
+	// Copy all the annotations to the pod
+	annos := &ss.Spec.Template.Annotations
+	for k, v := range ms.Annotations {
+		if !strings.Contains(k, "kubectl") && !strings.Contains(k, "notebook") {
+			(*annos)[k] = v
+		}
+	}
+
+	return ss
+}
+
+// setDefaultProbes sets the health check probes of the serving container if they are not specified
+func setDefaultProbes(container *corev1.Container) {
 	containerPort := container.Ports[0].ContainerPort
 
 	if container.StartupProbe == nil {
-		ss.Spec.Template.Spec.Containers[0].StartupProbe = &corev1.Probe{
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Scheme: corev1.URISchemeHTTP,
-					Path:   "/health",
-					Port:   intstr.FromInt32(containerPort),
-				},
-			},
-			InitialDelaySeconds: 60,
-			FailureThreshold:    30,
-			PeriodSeconds:       10,
-			TimeoutSeconds:      3,
-			SuccessThreshold:    1,
-		}
+		container.StartupProbe = constructStartupProbe(containerPort, 0)
 	}
 
 	if container.ReadinessProbe == nil {
-		ss.Spec.Template.Spec.Containers[0].ReadinessProbe = &corev1.Probe{
+		container.ReadinessProbe = &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Scheme: corev1.URISchemeHTTP,
@@ -102,7 +114,7 @@ func constructModelStatefulSet(ms *mlv1.ModelService) *v1.StatefulSet {
 	}
 
 	if container.LivenessProbe == nil {
-		ss.Spec.Template.Spec.Containers[0].LivenessProbe = &corev1.Probe{
+		container.LivenessProbe = &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Scheme: corev1.URISchemeHTTP,
@@ -116,23 +128,8 @@ func constructModelStatefulSet(ms *mlv1.ModelService) *v1.StatefulSet {
 			SuccessThreshold: 1,
 		}
 	}
-
-	// Copy all the labels to the pod
-	ls := &ss.Spec.Template.Labels
-	for k, v := range ms.Labels {
-		(*ls)[k] = v
-	}
-
-	// Copy all the annotations to the pod
-	annos := &ss.Spec.Template.Annotations
-	for k, v := range ms.Annotations {
-		if !strings.Contains(k, "kubectl") && !strings.Contains(k, "notebook") {
-			(*annos)[k] = v
-		}
-	}
-
-	return ss
 }
+
 func constructModelSvc(ms *mlv1.ModelService) *corev1.Service {
 	selector := GetModelServiceSelector(ms)
 
@@ -168,9 +165,14 @@ func constructModelSvc(ms *mlv1.ModelService) *corev1.Service {
 }
 
 func constructModelStatus(ss *v1.StatefulSet, pod *corev1.Pod) mlv1.ModelServiceStatus {
+	return constructPodStatus(ss.Status.ReadyReplicas, pod)
+}
+
+// constructPodStatus constructs the model service status from the serving pod
+func constructPodStatus(readyReplicas int32, pod *corev1.Pod) mlv1.ModelServiceStatus {
 	status := mlv1.ModelServiceStatus{
 		Conditions:     make([]common.Condition, 0),
-		ReadyReplicas:  ss.Status.ReadyReplicas,
+		ReadyReplicas:  readyReplicas,
 		ContainerState: corev1.ContainerState{},
 		State:          "",
 	}
@@ -180,8 +182,15 @@ func constructModelStatus(ss *v1.StatefulSet, pod *corev1.Pod) mlv1.ModelService
 		return status
 	}
 
-	if len(pod.Status.ContainerStatuses) > 0 {
-		cState := pod.Status.ContainerStatuses[0].State
+	// the statuses are sorted by the container names, the serving container is the first one in the spec
+	var cs *corev1.ContainerStatus
+	if len(pod.Spec.Containers) > 0 {
+		cs = getContainerStatus(pod.Status.ContainerStatuses, pod.Spec.Containers[0].Name)
+	} else if len(pod.Status.ContainerStatuses) > 0 {
+		cs = &pod.Status.ContainerStatuses[0]
+	}
+	if cs != nil {
+		cState := cs.State
 		status.ContainerState = cState
 		if cState.Running != nil {
 			status.State = "Running"
@@ -218,6 +227,11 @@ func buildArgs(ms *mlv1.ModelService) []string {
 		"--served-model-name": ms.Spec.ServedModelName,
 	}
 
+	if ms.Spec.Distributed != nil {
+		specArgs["--pipeline-parallel-size"] = strconv.Itoa(int(ms.Spec.Distributed.PipelineParallelSize))
+		specArgs["--distributed-executor-backend"] = "ray"
+	}
+
 	vGPUNumber := getVGPUNumber(ms)
 	if vGPUNumber > 0 {
 		specArgs["--tensor-parallel-size"] = strconv.Itoa(vGPUNumber)
@@ -237,13 +251,13 @@ func buildArgs(ms *mlv1.ModelService) []string {
 		}
 	}
 
-	// Add new args that are not already present
-	for k, v := range specArgs {
-		if !existingArgs[k] && v != "" {
+	// Add new args that are not already present, in a stable order to avoid unnecessary redeployment
+	for _, k := range slices.Sorted(maps.Keys(specArgs)) {
+		if v := specArgs[k]; !existingArgs[k] && v != "" {
 			args = append(args, fmt.Sprintf("%s=%s", k, v))
 		}
 	}
-	return args
+	return buildAdapterArgs(args, ms.Spec.Adapters)
 }
 
 func buildEnvs(ms *mlv1.ModelService, container corev1.Container) []corev1.EnvVar {
@@ -332,3 +346,12 @@ func getVGPUNumber(ms *mlv1.ModelService) int {
 
 	return 0
 }
+
+// GetServiceEndpoint returns the in-cluster base url of the model service API
+func GetServiceEndpoint(ms *mlv1.ModelService) string {
+	port := int32(vllmPort)
+	if containers := ms.Spec.Template.Spec.Containers; len(containers) > 0 && len(containers[0].Ports) > 0 {
+		port = containers[0].Ports[0].ContainerPort
+	}
+	return fmt.Sprintf("http://%s.%s.svc:%d", getFormattedMSName(ms.Name, ""), ms.Namespace, port)
+}
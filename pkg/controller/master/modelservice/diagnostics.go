@@ -0,0 +1,313 @@
+package modelservice
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	"github.com/llmos-ai/llmos-operator/pkg/apis/common"
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/utils/condition"
+)
+
+const (
+	modelDownloaderName = "download-models"
+	oomKilledReason     = "OOMKilled"
+	podLogTailLines     = 50
+	podLogLimitBytes    = 64 << 10
+	maxMessageLength    = 256
+
+	reasonOutOfMemory       = "OutOfMemory"
+	reasonCUDAError         = "CUDAError"
+	reasonModelNotFound     = "ModelNotFound"
+	reasonModelAccessDenied = "ModelAccessDenied"
+	reasonDownloadFailed    = "DownloadFailed"
+	reasonLoadingFailed     = "LoadingFailed"
+)
+
+// failurePatterns are the well-known log patterns of the model download and loading failures
+var failurePatterns = []struct {
+	reason   string
+	patterns []string
+}{
+	{
+		reason: reasonOutOfMemory,
+		patterns: []string{"CUDA out of memory", "OutOfMemoryError", "No available memory for the cache blocks",
+			"larger than the available KV cache memory"},
+	},
+	{
+		reason: reasonCUDAError,
+		patterns: []string{"CUDA error", "No CUDA GPUs are available", "no CUDA-capable device",
+			"CUDA driver version is insufficient", "NCCL error"},
+	},
+	{
+		reason: reasonModelAccessDenied,
+		patterns: []string{"GatedRepoError", "401 Client Error", "403 Client Error",
+			"Access to model", "Cannot access gated repo"},
+	},
+	{
+		reason: reasonModelNotFound,
+		patterns: []string{"RepositoryNotFoundError", "Repository Not Found", "404 Client Error",
+			"does not appear to have a file named", "is not a valid model identifier",
+			"Cannot find any model weights", "No such file or directory"},
+	},
+}
+
+var (
+	// progressRegexp matches the progress bar of tqdm, e.g., "Fetching 4 files:  50%|█████  | 2/4"
+	// or "Loading safetensors checkpoint shards:  50% Completed | 2/4"
+	progressRegexp = regexp.MustCompile(`^\s*(.*?):?\s*(\d{1,3})%(?: Completed)?\s*\|.*?(\d+/\d+)?\s*(?:\[|$)`)
+	// loadingStages are the log markers of vLLM after the model weights are loaded
+	loadingStages = []struct {
+		pattern string
+		message string
+	}{
+		{pattern: "Application startup complete", message: "model server is started"},
+		{pattern: "Capturing CUDA graph", message: "capturing CUDA graphs"},
+		{pattern: "Loading weights took", message: "model weights are loaded, initializing the engine"},
+		{pattern: "Model loading took", message: "model weights are loaded, initializing the engine"},
+		{pattern: "Starting to load model", message: "starting to load model weights"},
+	}
+)
+
+// podLogGetter returns the logs of the pod container
+type podLogGetter func(namespace, name string, opts *corev1.PodLogOptions) (string, error)
+
+func newPodLogGetter(ctx context.Context, clientSet kubernetes.Interface) podLogGetter {
+	return func(namespace, name string, opts *corev1.PodLogOptions) (string, error) {
+		data, err := clientSet.CoreV1().Pods(namespace).GetLogs(name, opts).DoRaw(ctx)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// diagnoseModelPod sets the download and loading conditions of the model and the failure reason to the status,
+// true is returned if the model is still being downloaded or loaded.
+func diagnoseModelPod(status *mlv1.ModelServiceStatus, previous []common.Condition, pod *corev1.Pod,
+	getLogs podLogGetter) bool {
+	// keep the previous model conditions so that their timestamps are only updated on changes
+	for _, c := range previous {
+		if c.Type == mlv1.ModelDownloaded || c.Type == mlv1.ModelLoaded {
+			status.Conditions = append(status.Conditions, c)
+		}
+	}
+
+	if cs := getContainerStatus(pod.Status.InitContainerStatuses, modelDownloaderName); cs != nil {
+		downloaded, inProgress := diagnoseDownload(status, pod, cs, getLogs)
+		if !downloaded {
+			return inProgress
+		}
+	}
+
+	if len(pod.Spec.Containers) == 0 {
+		return false
+	}
+	cs := getContainerStatus(pod.Status.ContainerStatuses, pod.Spec.Containers[0].Name)
+	if cs == nil {
+		return false
+	}
+	return diagnoseLoading(status, pod, cs, getLogs)
+}
+
+func diagnoseDownload(status *mlv1.ModelServiceStatus, pod *corev1.Pod, cs *corev1.ContainerStatus,
+	getLogs podLogGetter) (bool, bool) {
+	switch {
+	case cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0:
+		setModelCondition(status, mlv1.ModelDownloaded, true, "Downloaded", "model is downloaded")
+		return true, false
+	case cs.State.Running != nil:
+		message := "model is being downloaded"
+		if progress := parseProgress(getContainerLogs(pod, cs.Name, false, getLogs)); progress != "" {
+			message = fmt.Sprintf("%s, %s", message, progress)
+		}
+		setModelCondition(status, mlv1.ModelDownloaded, false, "Downloading", message)
+		return false, true
+	case isContainerFailed(cs):
+		reason, message := diagnoseContainerFailure(pod, cs, reasonDownloadFailed, getLogs)
+		setModelCondition(status, mlv1.ModelDownloaded, false, reason, message)
+		status.Reason, status.Message = reason, message
+		return false, false
+	default:
+		setModelCondition(status, mlv1.ModelDownloaded, false, "Pending", "waiting for the model to be downloaded")
+		return false, false
+	}
+}
+
+func diagnoseLoading(status *mlv1.ModelServiceStatus, pod *corev1.Pod, cs *corev1.ContainerStatus,
+	getLogs podLogGetter) bool {
+	switch {
+	case cs.Ready:
+		setModelCondition(status, mlv1.ModelLoaded, true, "Loaded", "model is loaded")
+		return false
+	case cs.State.Running != nil:
+		message := "model is being loaded"
+		if progress := parseLoadingProgress(getContainerLogs(pod, cs.Name, false, getLogs)); progress != "" {
+			message = fmt.Sprintf("%s, %s", message, progress)
+		}
+		setModelCondition(status, mlv1.ModelLoaded, false, "Loading", message)
+		// explain why the container is restarted
+		if cs.LastTerminationState.Terminated != nil {
+			status.Reason, status.Message = diagnoseContainerFailure(pod, cs, reasonLoadingFailed, getLogs)
+		}
+		return true
+	case isContainerFailed(cs):
+		reason, message := diagnoseContainerFailure(pod, cs, reasonLoadingFailed, getLogs)
+		setModelCondition(status, mlv1.ModelLoaded, false, reason, message)
+		status.Reason, status.Message = reason, message
+		return false
+	default:
+		setModelCondition(status, mlv1.ModelLoaded, false, "Pending", "waiting for the model to be loaded")
+		return false
+	}
+}
+
+// diagnoseContainerFailure explains the failure of the container by its termination state and logs
+func diagnoseContainerFailure(pod *corev1.Pod, cs *corev1.ContainerStatus, defaultReason string,
+	getLogs podLogGetter) (string, string) {
+	terminated, previous := cs.State.Terminated, false
+	if terminated == nil {
+		terminated, previous = cs.LastTerminationState.Terminated, true
+	}
+	if terminated != nil && terminated.Reason == oomKilledReason {
+		return reasonOutOfMemory, fmt.Sprintf("container %s is killed due to out of memory", cs.Name)
+	}
+
+	logs := getContainerLogs(pod, cs.Name, previous, getLogs)
+	if reason, message := diagnoseLogs(logs); reason != "" {
+		return reason, message
+	}
+
+	message := fmt.Sprintf("container %s is failed", cs.Name)
+	if terminated != nil {
+		message = fmt.Sprintf("container %s exited with code %d", cs.Name, terminated.ExitCode)
+	}
+	if line := lastLogLine(logs); line != "" {
+		message = fmt.Sprintf("%s: %s", message, line)
+	}
+	return defaultReason, message
+}
+
+// diagnoseLogs returns the failure reason and the log line of the well-known failures
+func diagnoseLogs(logs string) (string, string) {
+	lines := splitLogLines(logs)
+	for _, failure := range failurePatterns {
+		for i := len(lines) - 1; i >= 0; i-- {
+			for _, pattern := range failure.patterns {
+				if strings.Contains(lines[i], pattern) {
+					return failure.reason, truncateMessage(lines[i])
+				}
+			}
+		}
+	}
+	return "", ""
+}
+
+// parseProgress returns the latest progress of the tqdm progress bars in the logs
+func parseProgress(logs string) string {
+	lines := splitLogLines(logs)
+	for i := len(lines) - 1; i >= 0; i-- {
+		matches := progressRegexp.FindStringSubmatch(lines[i])
+		if matches == nil {
+			continue
+		}
+		progress := matches[2] + "%"
+		if matches[3] != "" {
+			progress = fmt.Sprintf("%s (%s)", progress, matches[3])
+		}
+		if desc := strings.TrimSpace(matches[1]); desc != "" {
+			return fmt.Sprintf("%s: %s", desc, progress)
+		}
+		return progress
+	}
+	return ""
+}
+
+// parseLoadingProgress returns the loading progress of the model weights or the latest loading stage of vLLM
+func parseLoadingProgress(logs string) string {
+	lines := splitLogLines(logs)
+	for i := len(lines) - 1; i >= 0; i-- {
+		for _, stage := range loadingStages {
+			if strings.Contains(lines[i], stage.pattern) {
+				return stage.message
+			}
+		}
+		if progress := parseProgress(lines[i]); progress != "" {
+			return progress
+		}
+	}
+	return ""
+}
+
+func getContainerLogs(pod *corev1.Pod, container string, previous bool, getLogs podLogGetter) string {
+	if getLogs == nil {
+		return ""
+	}
+	logs, err := getLogs(pod.Namespace, pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		Previous:   previous,
+		TailLines:  ptr.To(int64(podLogTailLines)),
+		LimitBytes: ptr.To(int64(podLogLimitBytes)),
+	})
+	if err != nil {
+		logrus.Debugf("failed to get logs of container %s in pod %s/%s: %v", container, pod.Namespace, pod.Name, err)
+		return ""
+	}
+	return logs
+}
+
+func getContainerStatus(statuses []corev1.ContainerStatus, name string) *corev1.ContainerStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// isContainerFailed returns true if the container is exited with error or is waiting to be restarted
+func isContainerFailed(cs *corev1.ContainerStatus) bool {
+	if cs.State.Terminated != nil {
+		return cs.State.Terminated.ExitCode != 0
+	}
+	return cs.State.Waiting != nil && cs.LastTerminationState.Terminated != nil
+}
+
+func setModelCondition(status *mlv1.ModelServiceStatus, cond condition.Cond, ready bool, reason, message string) {
+	cond.SetStatusBool(status, ready)
+	cond.Reason(status, reason)
+	cond.Message(status, message)
+}
+
+// splitLogLines splits the logs by lines, the carriage returns of the progress bars are treated as new lines
+func splitLogLines(logs string) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.FieldsFunc(logs, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func lastLogLine(logs string) string {
+	lines := splitLogLines(logs)
+	if len(lines) == 0 {
+		return ""
+	}
+	return truncateMessage(lines[len(lines)-1])
+}
+
+func truncateMessage(message string) string {
+	if len(message) > maxMessageLength {
+		return message[:maxMessageLength] + "..."
+	}
+	return message
+}
@@ -0,0 +1,181 @@
+package modelservice
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		logs     string
+		expected string
+	}{
+		{
+			name: "download progress",
+			logs: "Fetching 4 files:   0%|          | 0/4 [00:00<?, ?it/s]\r" +
+				"Fetching 4 files:  50%|█████     | 2/4 [00:10<00:10,  5.00s/it]",
+			expected: "Fetching 4 files: 50% (2/4)",
+		},
+		{
+			name: "loading progress",
+			logs: "INFO 10-18 08:00:00 [default_loader.py:262] Loading weights\n" +
+				"Loading safetensors checkpoint shards:  75% Completed | 3/4 [00:30<00:10, 10.00s/it]\n",
+			expected: "Loading safetensors checkpoint shards: 75% (3/4)",
+		},
+		{
+			name:     "no progress",
+			logs:     "downloading model\n",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseProgress(tt.logs); got != tt.expected {
+				t.Errorf("Expected progress %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseLoadingProgress(t *testing.T) {
+	logs := "Loading safetensors checkpoint shards: 100% Completed | 4/4 [00:40<00:00, 10.00s/it]\n" +
+		"INFO 10-18 08:00:40 [gpu_model_runner.py:1892] Model loading took 14.99 GiB and 41.2 seconds\n"
+	if got := parseLoadingProgress(logs); got != "model weights are loaded, initializing the engine" {
+		t.Errorf("Expected the latest loading stage, got %q", got)
+	}
+}
+
+func TestDiagnoseLogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		logs     string
+		expected string
+	}{
+		{
+			name:     "out of memory",
+			logs:     "torch.OutOfMemoryError: CUDA out of memory. Tried to allocate 2.00 GiB.",
+			expected: reasonOutOfMemory,
+		},
+		{
+			name:     "cuda error",
+			logs:     "RuntimeError: CUDA error: no kernel image is available for execution on the device",
+			expected: reasonCUDAError,
+		},
+		{
+			name: "missing weights",
+			logs: "OSError: meta-llama/Llama-3.1-8B does not appear to have a file named config.json.\n" +
+				"ERROR engine core failed to start",
+			expected: reasonModelNotFound,
+		},
+		{
+			name:     "gated model",
+			logs:     "huggingface_hub.errors.GatedRepoError: 401 Client Error.",
+			expected: reasonModelAccessDenied,
+		},
+		{
+			name:     "unknown failure",
+			logs:     "Traceback (most recent call last):\nValueError: invalid argument",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := diagnoseLogs(tt.logs); got != tt.expected {
+				t.Errorf("Expected reason %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func newDiagnosedPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "modelservice-test-0", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: modelDownloaderName}},
+			Containers:     []corev1.Container{{Name: "vllm"}},
+		},
+	}
+}
+
+func fakeLogGetter(logs map[string]string) podLogGetter {
+	return func(_, _ string, opts *corev1.PodLogOptions) (string, error) {
+		if opts.Previous {
+			return logs[opts.Container+"/previous"], nil
+		}
+		return logs[opts.Container], nil
+	}
+}
+
+func TestDiagnoseModelPod_Downloading(t *testing.T) {
+	pod := newDiagnosedPod()
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{Name: modelDownloaderName, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+	}
+	getLogs := fakeLogGetter(map[string]string{
+		modelDownloaderName: "Fetching 4 files:  25%|██▌       | 1/4 [00:10<00:30, 10.00s/it]",
+	})
+
+	status := &mlv1.ModelServiceStatus{}
+	if !diagnoseModelPod(status, nil, pod, getLogs) {
+		t.Errorf("Expected the model to be in progress")
+	}
+	if !mlv1.ModelDownloaded.IsFalse(status) ||
+		mlv1.ModelDownloaded.GetMessage(status) != "model is being downloaded, Fetching 4 files: 25% (1/4)" {
+		t.Errorf("Expected the download progress, got %q", mlv1.ModelDownloaded.GetMessage(status))
+	}
+}
+
+func TestDiagnoseModelPod_OutOfMemory(t *testing.T) {
+	pod := newDiagnosedPod()
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{Name: modelDownloaderName, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:  "vllm",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"},
+			},
+		},
+	}
+	getLogs := fakeLogGetter(map[string]string{
+		"vllm/previous": "torch.OutOfMemoryError: CUDA out of memory. Tried to allocate 2.00 GiB.",
+	})
+
+	status := &mlv1.ModelServiceStatus{}
+	if diagnoseModelPod(status, nil, pod, getLogs) {
+		t.Errorf("Expected the failed model not to be in progress")
+	}
+	if !mlv1.ModelDownloaded.IsTrue(status) {
+		t.Errorf("Expected the model to be downloaded")
+	}
+	if status.Reason != reasonOutOfMemory || mlv1.ModelLoaded.GetReason(status) != reasonOutOfMemory {
+		t.Errorf("Expected reason %s, got %s", reasonOutOfMemory, status.Reason)
+	}
+}
+
+func TestDiagnoseModelPod_KeepTimestamps(t *testing.T) {
+	pod := newDiagnosedPod()
+	pod.Spec.InitContainers = nil
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "vllm", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+	}
+
+	previous := &mlv1.ModelServiceStatus{}
+	diagnoseModelPod(previous, nil, pod, nil)
+	mlv1.ModelLoaded.LastUpdated(previous, "2025-01-01T00:00:00Z")
+
+	status := &mlv1.ModelServiceStatus{}
+	diagnoseModelPod(status, previous.Conditions, pod, nil)
+	if got := mlv1.ModelLoaded.GetLastUpdated(status); got != "2025-01-01T00:00:00Z" {
+		t.Errorf("Expected the unchanged condition to keep its timestamp, got %s", got)
+	}
+}
@@ -16,7 +16,10 @@ import (
 
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
 	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	ctlkuberayv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ray.io/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/registry"
 	"github.com/llmos-ai/llmos-operator/pkg/server/config"
 	"github.com/llmos-ai/llmos-operator/pkg/utils"
 	"github.com/llmos-ai/llmos-operator/pkg/utils/reconcilehelper"
@@ -27,11 +30,17 @@ const (
 	modelServiceOnDelete  = "modelService.onDelete"
 	msStatefulSetOnChange = "modelService.statefulSetOnChange"
 	msSyncStatusByPod     = "modelService.syncStatusByPod"
+	msRayClusterOnChange  = "modelService.rayClusterOnChange"
+	msSyncRayStatusByPod  = "modelService.syncRayClusterStatusByPod"
+	msSyncByAdapterModel  = "modelService.syncByAdapterModel"
 )
 
 type handler struct {
-	ModelServices     ctlmlv1.ModelServiceClient
+	ctx context.Context
+
+	ModelServices     ctlmlv1.ModelServiceController
 	ModelServiceCache ctlmlv1.ModelServiceCache
+	ModelCache        ctlmlv1.ModelCache
 	StatefulSets      ctlappsv1.StatefulSetClient
 	StatefulSetCache  ctlappsv1.StatefulSetCache
 	Deployments       ctlappsv1.DeploymentClient
@@ -40,7 +49,17 @@ type handler struct {
 	ServiceCache      ctlcorev1.ServiceCache
 	Pods              ctlcorev1.PodClient
 	PodCache          ctlcorev1.PodCache
+	ConfigMaps        ctlcorev1.ConfigMapClient
+	ConfigMapCache    ctlcorev1.ConfigMapCache
+	RayClusters       ctlkuberayv1.RayClusterClient
+	RayClusterCache   ctlkuberayv1.RayClusterCache
 	pvcHandler        *utils.PVCHandler
+
+	DownloaderAccess *snapshotting.DownloaderAccess
+
+	rm            *registry.Manager
+	modelSizes    *modelSizeCache
+	adapterProber *adapterProber
 }
 
 func Register(ctx context.Context, mgmt *config.Management, _ config.Options) error {
@@ -49,11 +68,19 @@ func Register(ctx context.Context, mgmt *config.Management, _ config.Options) er
 	deployment := mgmt.AppsFactory.Apps().V1().Deployment()
 	service := mgmt.CoreFactory.Core().V1().Service()
 	pod := mgmt.CoreFactory.Core().V1().Pod()
+	configMaps := mgmt.CoreFactory.Core().V1().ConfigMap()
 	pvcs := mgmt.CoreFactory.Core().V1().PersistentVolumeClaim()
+	rayClusters := mgmt.KubeRayFactory.Ray().V1().RayCluster()
+	registries := mgmt.LLMFactory.Ml().V1().Registry()
+	secrets := mgmt.CoreFactory.Core().V1().Secret()
+	models := mgmt.LLMFactory.Ml().V1().Model()
 
 	h := &handler{
+		ctx: ctx,
+
 		ModelServices:     modelService,
 		ModelServiceCache: modelService.Cache(),
+		ModelCache:        models.Cache(),
 		StatefulSets:      statefulSet,
 		StatefulSetCache:  statefulSet.Cache(),
 		Deployments:       deployment,
@@ -62,20 +89,43 @@ func Register(ctx context.Context, mgmt *config.Management, _ config.Options) er
 		ServiceCache:      service.Cache(),
 		Pods:              pod,
 		PodCache:          pod.Cache(),
+		ConfigMaps:        configMaps,
+		ConfigMapCache:    configMaps.Cache(),
+		RayClusters:       rayClusters,
+		RayClusterCache:   rayClusters.Cache(),
 		pvcHandler:        utils.NewPVCHandler(pvcs),
+
+		DownloaderAccess: snapshotting.NewDownloaderAccess(mgmt),
 	}
+	h.modelSizes = newModelSizeCache(modelService.Enqueue)
+	h.adapterProber = newAdapterProber(ctx, modelService.Enqueue)
+	h.rm = registry.NewManager(secrets.Cache().Get, registries.Cache().Get)
 	modelService.OnChange(ctx, modelServiceOnChange, h.OnChange)
 	modelService.OnRemove(ctx, modelServiceOnDelete, h.OnDelete)
+	relatedresource.Watch(ctx, msSyncByAdapterModel, h.syncModelServicesByAdapterModel, modelService, models)
 
+	getLogs := newPodLogGetter(ctx, mgmt.ClientSet)
 	ssHandler := &statefulSetHandler{
+		statefulSets:      statefulSet,
 		statefulSetCache:  statefulSet.Cache(),
 		modelService:      modelService,
 		modelServiceCache: modelService.Cache(),
 		pods:              pod,
 		podCache:          pod.Cache(),
+		getLogs:           getLogs,
 	}
 	statefulSet.OnChange(ctx, msStatefulSetOnChange, ssHandler.OnChange)
 	relatedresource.Watch(ctx, msSyncStatusByPod, ssHandler.syncServiceStatusByPod, statefulSet, pod)
+
+	rcHandler := &rayClusterHandler{
+		rayClusters:       rayClusters,
+		modelService:      modelService,
+		modelServiceCache: modelService.Cache(),
+		podCache:          pod.Cache(),
+		getLogs:           getLogs,
+	}
+	rayClusters.OnChange(ctx, msRayClusterOnChange, rcHandler.OnChange)
+	relatedresource.Watch(ctx, msSyncRayStatusByPod, syncRayClusterStatusByPod, rayClusters, pod)
 	return nil
 }
 
@@ -83,9 +133,38 @@ func (h *handler) OnChange(_ string, ms *mlv1.ModelService) (*mlv1.ModelService,
 	if ms == nil || ms.DeletionTimestamp != nil {
 		return nil, nil
 	}
-	var err error
+	// clean up the serving resources of the other mode
+	if err := h.cleanupServingResources(ms); err != nil {
+		return ms, err
+	}
+
+	// derive the startup probe budgets from the model sizes, so that large models won't be restarted while loading
+	desired := ms.DeepCopy()
+	canaryStartupProbe, pending := h.setStartupProbes(desired)
+	if pending {
+		// wait for the lookup of the model sizes instead of rolling the statefulSet with the default probe,
+		// the model service is enqueued once the lookup is finished
+		return ms, nil
+	}
+
+	if ms.Spec.Distributed != nil {
+		return ms, h.reconcileDistributed(desired)
+	}
+
+	// only load the adapters whose models are ready, so that a pending adapter won't block the model service
+	adapterStatuses, err := h.reconcileAdapters(ms)
+	if err != nil {
+		return ms, err
+	}
+
 	// reconcile model service statefulSet
-	if _, err = h.reconcileModelStatefulSet(ms); err != nil {
+	ss, err := h.reconcileModelStatefulSet(desired)
+	if err != nil {
+		return ms, err
+	}
+
+	// reconcile model service canary statefulSet
+	if err = h.reconcileCanaryStatefulSet(desired, ss, canaryStartupProbe); err != nil {
 		return ms, err
 	}
 
@@ -94,6 +173,10 @@ func (h *handler) OnChange(_ string, ms *mlv1.ModelService) (*mlv1.ModelService,
 		return ms, err
 	}
 
+	if err = h.updateAdapterStatus(ms, adapterStatuses); err != nil {
+		return ms, err
+	}
+
 	// TODO: only handle pvcs clean up on delete
 	// NOTE: this is a workaround to clean up pvcs on delete while update reconcile is called simultaneously
 	strVolumes := ms.Annotations[constant.AnnotationOnDeleteVolumes]
@@ -108,6 +191,16 @@ func (h *handler) OnChange(_ string, ms *mlv1.ModelService) (*mlv1.ModelService,
 	return ms, nil
 }
 
+// reconcileDistributed reconciles the Ray cluster and the service of the distributed model service
+func (h *handler) reconcileDistributed(ms *mlv1.ModelService) error {
+	if err := h.reconcileRayCluster(ms); err != nil {
+		return err
+	}
+
+	_, err := h.reconcileModelService(ms)
+	return err
+}
+
 // reconcileModelStatefulSet reconciles the statefulSet of the model
 func (h *handler) reconcileModelStatefulSet(ms *mlv1.ModelService) (*appsv1.StatefulSet, error) {
 	ss := constructModelStatefulSet(ms)
@@ -120,12 +213,12 @@ func (h *handler) reconcileModelStatefulSet(ms *mlv1.ModelService) (*appsv1.Stat
 	}
 
 	// Update the object and write the result back if there are any changes
-	toUpdate, toRedeploy := reconcilehelper.CopyStatefulSetFields(ss, foundSs)
+	ssCopy := foundSs.DeepCopy()
+	toUpdate, toRedeploy := reconcilehelper.CopyStatefulSetFields(ss, ssCopy)
 	if toUpdate {
 		logrus.Debugf("updating modelSerive statefulSet %s/%s", foundSs.Namespace, foundSs.Name)
-		ssCopy := foundSs.DeepCopy()
-		if ss, err = h.StatefulSets.Update(ssCopy); err != nil {
-			return ss, err
+		if foundSs, err = h.StatefulSets.Update(ssCopy); err != nil {
+			return nil, err
 		}
 	}
 
@@ -163,6 +256,8 @@ func (h *handler) OnDelete(_ string, ms *mlv1.ModelService) (*mlv1.ModelService,
 		return nil, nil
 	}
 
+	h.adapterProber.forget(ms.Namespace, ms.Name)
+
 	// Clean up on-delete pvcs if specified
 	strVolumes := ms.Annotations[constant.AnnotationOnDeleteVolumes]
 	if strVolumes != "" {
@@ -187,8 +282,11 @@ func (h *handler) deleteStatefulSetPods(sts *appsv1.StatefulSet) error {
 		return fmt.Errorf("failed to list modelService pods: %w", err)
 	}
 
-	// Delete each pod
+	// Delete each pod, skip the pods owned by other revisions that share the same labels
 	for _, pod := range pods {
+		if !metav1.IsControlledBy(pod, sts) {
+			continue
+		}
 		err = h.Pods.Delete(pod.Namespace, pod.Name, &metav1.DeleteOptions{})
 		if err != nil {
 			return fmt.Errorf("failed to delete modelService pod %s: %w", pod.Name, err)
@@ -0,0 +1,311 @@
+package modelservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+const (
+	startupProbeInitialDelaySeconds     = 60
+	startupProbePeriodSeconds           = 10
+	defaultStartupProbeFailureThreshold = 30
+	// modelLoadingBytesPerSecond is a conservative throughput of loading the model weights into the accelerators
+	modelLoadingBytesPerSecond = 50 << 20
+
+	defaultHuggingFaceEndpoint = "https://huggingface.co"
+	modelScopeEndpoint         = "https://modelscope.cn"
+	hfTokenEnvName             = "HF_TOKEN"
+	modelMetadataTimeout       = 10 * time.Second
+	// modelSizeRetryInterval is the interval to retry the failed lookup of the model size
+	modelSizeRetryInterval = 10 * time.Minute
+)
+
+// weightFileSuffixes are the file suffixes of the model weights, the safetensors files are preferred by vLLM
+var weightFileSuffixes = []string{".safetensors", ".bin", ".pt", ".pth", ".gguf"}
+
+// constructStartupProbe returns the startup probe of the serving container, the failure threshold is
+// extended by the time of loading the model weights so that large models won't be restarted while loading.
+func constructStartupProbe(containerPort int32, modelSize int64) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Scheme: corev1.URISchemeHTTP,
+				Path:   "/health",
+				Port:   intstr.FromInt32(containerPort),
+			},
+		},
+		InitialDelaySeconds: startupProbeInitialDelaySeconds,
+		FailureThreshold:    getStartupProbeFailureThreshold(modelSize),
+		PeriodSeconds:       startupProbePeriodSeconds,
+		TimeoutSeconds:      3,
+		SuccessThreshold:    1,
+	}
+}
+
+func getStartupProbeFailureThreshold(modelSize int64) int32 {
+	if modelSize <= 0 {
+		return defaultStartupProbeFailureThreshold
+	}
+	loadingSeconds := (modelSize + modelLoadingBytesPerSecond - 1) / modelLoadingBytesPerSecond
+	periods := (loadingSeconds + startupProbePeriodSeconds - 1) / startupProbePeriodSeconds
+	return defaultStartupProbeFailureThreshold + int32(periods)
+}
+
+// setModelStartupProbe sets the startup probe of the serving container by the model size
+// if it's not specified in the template
+func setModelStartupProbe(template *mlv1.ModelServiceTemplateSpec, modelSize int64) {
+	if modelSize <= 0 || len(template.Spec.Containers) == 0 {
+		return
+	}
+	container := &template.Spec.Containers[0]
+	if container.StartupProbe != nil || len(container.Ports) == 0 {
+		return
+	}
+	container.StartupProbe = constructStartupProbe(container.Ports[0].ContainerPort, modelSize)
+}
+
+// setStartupProbes sets the startup probe of the stable revision by its model size, and returns the startup probe
+// of the canary revision if it serves a different model, which needs its own startup probe budget. The sizes are
+// looked up in the background, pending is returned until the first lookups are finished, and the model service is
+// enqueued once they're finished.
+func (h *handler) setStartupProbes(ms *mlv1.ModelService) (canaryProbe *corev1.Probe, pending bool) {
+	template := ms.Spec.Template.DeepCopy()
+	size, pending := h.getModelSize(ms, ms.Spec.ModelName)
+	setModelStartupProbe(&ms.Spec.Template, size)
+
+	canary := ms.Spec.Canary
+	if canary == nil || canary.ModelName == "" || canary.ModelName == ms.Spec.ModelName {
+		return nil, pending
+	}
+	size, canaryPending := h.getModelSize(ms, canary.ModelName)
+	setModelStartupProbe(template, size)
+	if len(template.Spec.Containers) == 0 {
+		return nil, pending || canaryPending
+	}
+	return template.Spec.Containers[0].StartupProbe, pending || canaryPending
+}
+
+// getModelSize returns the size of the model weights, 0 is returned if the size is unknown
+func (h *handler) getModelSize(ms *mlv1.ModelService, modelName string) (int64, bool) {
+	key := fmt.Sprintf("%s/%s/%s", ms.Spec.ModelRegistry, ms.Namespace, modelName)
+	registry, namespace, token := ms.Spec.ModelRegistry, ms.Namespace, getHFToken(ms)
+	return h.modelSizes.get(key, ms.Namespace+"/"+ms.Name, time.Now(), func() (int64, error) {
+		switch registry {
+		case "local":
+			return h.getLocalModelSize(namespace, modelName)
+		case modelScopeName:
+			return getModelScopeModelSize(h.ctx, modelName)
+		default:
+			return getHuggingFaceModelSize(h.ctx, modelName, token)
+		}
+	})
+}
+
+// modelSizeCache caches the sizes of the models, which are looked up in the background to avoid blocking the
+// reconciliation by the requests to the model hubs. The failed lookups are retried after modelSizeRetryInterval.
+type modelSizeCache struct {
+	mu      sync.Mutex
+	entries map[string]*modelSizeEntry
+	// enqueue enqueues the model service waiting for the first lookup of the size
+	enqueue func(namespace, name string)
+}
+
+type modelSizeEntry struct {
+	size    int64
+	looking bool
+	// retryAt is the time to retry the failed lookup, which is zero if the lookup succeeds
+	retryAt time.Time
+	// waiters are the model services waiting for the first lookup
+	waiters []string
+}
+
+func newModelSizeCache(enqueue func(namespace, name string)) *modelSizeCache {
+	return &modelSizeCache{entries: map[string]*modelSizeEntry{}, enqueue: enqueue}
+}
+
+// get returns the cached size and whether the first lookup of the size is pending, the lookup is started if the
+// size is not cached or the failed lookup should be retried. The failed size is 0 until the retry succeeds.
+func (c *modelSizeCache) get(key, waiter string, now time.Time, lookup func() (int64, error)) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		e = &modelSizeEntry{}
+		c.entries[key] = e
+		c.lookup(key, e, now, lookup)
+	} else if !e.looking && !e.retryAt.IsZero() && !now.Before(e.retryAt) {
+		c.lookup(key, e, now, lookup)
+	}
+
+	// only the first lookup is waited for, the retries don't block the model services
+	if e.looking && e.retryAt.IsZero() {
+		if !slices.Contains(e.waiters, waiter) {
+			e.waiters = append(e.waiters, waiter)
+		}
+		return 0, true
+	}
+	return e.size, false
+}
+
+func (c *modelSizeCache) lookup(key string, e *modelSizeEntry, now time.Time, lookup func() (int64, error)) {
+	e.looking = true
+	go func() {
+		size, err := lookup()
+
+		c.mu.Lock()
+		e.looking = false
+		if err != nil {
+			logrus.Warnf("failed to get size of model %s, fallback to the default startup probe: %v", key, err)
+			e.retryAt = now.Add(modelSizeRetryInterval)
+		} else {
+			e.size, e.retryAt = size, time.Time{}
+		}
+		waiters := e.waiters
+		e.waiters = nil
+		c.mu.Unlock()
+
+		for _, waiter := range waiters {
+			namespace, name, _ := strings.Cut(waiter, "/")
+			c.enqueue(namespace, name)
+		}
+	}()
+}
+
+// getLocalModelSize returns the size of the model stored in the registry
+func (h *handler) getLocalModelSize(namespace, modelName string) (int64, error) {
+	model, err := h.ModelCache.Get(namespace, modelName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get model %s/%s: %w", namespace, modelName, err)
+	}
+	if model.Status.RootPath == "" {
+		return 0, fmt.Errorf("model %s/%s is not ready", namespace, modelName)
+	}
+
+	b, err := h.rm.NewBackendFromRegistry(h.ctx, model.Spec.Registry)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get backend from registry %s: %w", model.Spec.Registry, err)
+	}
+	return b.GetSize(h.ctx, model.Status.RootPath)
+}
+
+type hfModelInfo struct {
+	Siblings []struct {
+		Filename string `json:"rfilename"`
+		Size     int64  `json:"size"`
+	} `json:"siblings"`
+}
+
+func getHuggingFaceModelSize(ctx context.Context, modelName, token string) (int64, error) {
+	endpoint := strings.TrimRight(settings.HuggingFaceEndpoint.Get(), "/")
+	if endpoint == "" {
+		endpoint = defaultHuggingFaceEndpoint
+	}
+
+	info := &hfModelInfo{}
+	if err := getModelMetadata(ctx, fmt.Sprintf("%s/api/models/%s?blobs=true", endpoint, modelName),
+		token, info); err != nil {
+		return 0, err
+	}
+
+	files := make(map[string]int64, len(info.Siblings))
+	for _, f := range info.Siblings {
+		files[f.Filename] = f.Size
+	}
+	return sumWeightFileSizes(files), nil
+}
+
+type modelScopeFiles struct {
+	Data struct {
+		Files []struct {
+			Path string `json:"Path"`
+			Size int64  `json:"Size"`
+		} `json:"Files"`
+	} `json:"Data"`
+}
+
+func getModelScopeModelSize(ctx context.Context, modelName string) (int64, error) {
+	files := &modelScopeFiles{}
+	if err := getModelMetadata(ctx, fmt.Sprintf("%s/api/v1/models/%s/repo/files?Recursive=true",
+		modelScopeEndpoint, modelName), "", files); err != nil {
+		return 0, err
+	}
+
+	sizes := make(map[string]int64, len(files.Data.Files))
+	for _, f := range files.Data.Files {
+		sizes[f.Path] = f.Size
+	}
+	return sumWeightFileSizes(sizes), nil
+}
+
+func getModelMetadata(ctx context.Context, rawURL, token string, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, modelMetadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to request %s: %s", rawURL, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// sumWeightFileSizes returns the total size of the weight files, only the safetensors files are counted
+// if they exist since the other formats won't be loaded.
+func sumWeightFileSizes(files map[string]int64) int64 {
+	sizes := make(map[string]int64, len(weightFileSuffixes))
+	for name, size := range files {
+		for _, suffix := range weightFileSuffixes {
+			if strings.HasSuffix(name, suffix) {
+				sizes[suffix] += size
+				break
+			}
+		}
+	}
+
+	if size := sizes[weightFileSuffixes[0]]; size > 0 {
+		return size
+	}
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+	return total
+}
+
+// getHFToken returns the hugging face token of the serving container if it's set as a plain value
+func getHFToken(ms *mlv1.ModelService) string {
+	if len(ms.Spec.Template.Spec.Containers) == 0 {
+		return ""
+	}
+	for _, env := range ms.Spec.Template.Spec.Containers[0].Env {
+		if env.Name == hfTokenEnvName {
+			return env.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package modelservice
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+func TestGetStartupProbeFailureThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		modelSize int64
+		expected  int32
+	}{
+		{name: "unknown size", modelSize: 0, expected: defaultStartupProbeFailureThreshold},
+		{name: "small model", modelSize: 1 << 30, expected: 33},
+		{name: "large model", modelSize: 140 << 30, expected: 317},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getStartupProbeFailureThreshold(tt.modelSize); got != tt.expected {
+				t.Errorf("Expected failure threshold %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSetModelStartupProbe(t *testing.T) {
+	template := &mlv1.ModelServiceTemplateSpec{}
+	template.Spec.Containers = []v1.Container{
+		{Name: "vllm", Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8000}}},
+	}
+
+	setModelStartupProbe(template, 0)
+	if template.Spec.Containers[0].StartupProbe != nil {
+		t.Fatalf("Expected no startup probe for the unknown model size")
+	}
+
+	setModelStartupProbe(template, 140<<30)
+	probe := template.Spec.Containers[0].StartupProbe
+	if probe == nil || probe.FailureThreshold != 317 {
+		t.Fatalf("Expected startup probe derived from the model size, got %+v", probe)
+	}
+
+	// the specified startup probe should not be overridden
+	setModelStartupProbe(template, 1<<30)
+	if template.Spec.Containers[0].StartupProbe.FailureThreshold != 317 {
+		t.Errorf("Expected the specified startup probe to be kept")
+	}
+}
+
+func TestSumWeightFileSizes(t *testing.T) {
+	files := map[string]int64{
+		"config.json":                      1 << 10,
+		"model-00001-of-00002.safetensors": 4 << 30,
+		"model-00002-of-00002.safetensors": 2 << 30,
+		"pytorch_model-00001-of-00002.bin": 4 << 30,
+		"original/consolidated.00.pth":     6 << 30,
+		"tokenizer.json":                   1 << 20,
+	}
+	if got := sumWeightFileSizes(files); got != 6<<30 {
+		t.Errorf("Expected only the safetensors files to be counted, got %d", got)
+	}
+
+	delete(files, "model-00001-of-00002.safetensors")
+	delete(files, "model-00002-of-00002.safetensors")
+	if got := sumWeightFileSizes(files); got != 10<<30 {
+		t.Errorf("Expected the other weight files to be counted, got %d", got)
+	}
+}
+
+func TestModelSizeCache(t *testing.T) {
+	enqueued := make(chan string, 10)
+	c := newModelSizeCache(func(namespace, name string) { enqueued <- namespace + "/" + name })
+	now := time.Now()
+
+	lookups := 0
+	failed := func() (int64, error) { lookups++; return 0, errors.New("hub unavailable") }
+	succeeded := func() (int64, error) { lookups++; return 1 << 30, nil }
+
+	// the first lookup is pending and the waiter is enqueued once it fails
+	if _, pending := c.get("hf/default/model", "default/ms", now, failed); !pending {
+		t.Fatal("expected the first lookup to be pending")
+	}
+	if waiter := <-enqueued; waiter != "default/ms" {
+		t.Errorf("expected default/ms to be enqueued, got %s", waiter)
+	}
+
+	// the failure isn't retried before the retry interval
+	if size, pending := c.get("hf/default/model", "default/ms", now, succeeded); size != 0 || pending {
+		t.Errorf("expected the failed size 0 without pending, got %d, %v", size, pending)
+	}
+	if lookups != 1 {
+		t.Errorf("expected 1 lookup before the retry interval, got %d", lookups)
+	}
+
+	// the failure is retried after the retry interval without blocking the model service
+	later := now.Add(modelSizeRetryInterval)
+	if _, pending := c.get("hf/default/model", "default/ms", later, succeeded); pending {
+		t.Error("expected the retry not to be pending")
+	}
+	waitForLookup(t, c, "hf/default/model")
+	if size, _ := c.get("hf/default/model", "default/ms", later, failed); size != 1<<30 {
+		t.Errorf("expected the retried size %d, got %d", 1<<30, size)
+	}
+
+	// the successful lookup is kept
+	c.get("hf/default/model", "default/ms", later.Add(modelSizeRetryInterval), failed)
+	if lookups != 2 {
+		t.Errorf("expected the successful lookup to be cached, got %d lookups", lookups)
+	}
+}
+
+func waitForLookup(t *testing.T, c *modelSizeCache, key string) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		c.mu.Lock()
+		looking := c.entries[key].looking
+		c.mu.Unlock()
+		if !looking {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("lookup of %s isn't finished", key)
+}
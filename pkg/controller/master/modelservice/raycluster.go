@@ -0,0 +1,329 @@
+package modelservice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	rayv1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	ctlkuberayv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ray.io/v1"
+)
+
+const (
+	rayHeadNodeType     = "head"
+	rayWorkerGroupName  = "workers"
+	rayStartCmdEnv      = "KUBERAY_GEN_RAY_START_CMD"
+	vllmServeCommand    = "python3 -m vllm.entrypoints.openai.api_server"
+	rayGCSServerPort    = 6379
+	rayDashboardPort    = 8265
+	rayClientServerPort = 10001
+)
+
+// constructRayCluster builds the Ray cluster that serves the model across multiple nodes.
+// The head pod starts the Ray head node in background and runs the vLLM server on top of it, and it's
+// the only pod selected by the model service service. The worker pods only join the Ray cluster.
+func constructRayCluster(ms *mlv1.ModelService) *rayv1.RayCluster {
+	selector := GetModelServiceSelector(ms)
+	workers := ms.Spec.Distributed.PipelineParallelSize - 1
+
+	headSpec := constructRayPodSpec(ms)
+	head := &headSpec.Containers[0]
+	head.Command = []string{"/bin/bash", "-lc", "--"}
+	head.Args = []string{fmt.Sprintf("${%s/--block/} && exec %s %s", rayStartCmdEnv, vllmServeCommand,
+		shellQuoteArgs(buildArgs(ms)))}
+	head.Ports = append(head.Ports,
+		corev1.ContainerPort{Name: "gcs-server", ContainerPort: rayGCSServerPort},
+		corev1.ContainerPort{Name: "dashboard", ContainerPort: rayDashboardPort},
+		corev1.ContainerPort{Name: "client", ContainerPort: rayClientServerPort},
+	)
+	setDefaultProbes(head)
+
+	workerSpec := constructRayPodSpec(ms)
+	worker := &workerSpec.Containers[0]
+	worker.Command = []string{"/bin/bash", "-lc", "--"}
+	worker.Args = []string{fmt.Sprintf("$%s", rayStartCmdEnv)}
+
+	headLabels := map[string]string{}
+	for k, v := range ms.Labels {
+		headLabels[k] = v
+	}
+	for k, v := range selector.MatchLabels {
+		headLabels[k] = v
+	}
+
+	annotations := map[string]string{
+		constant.AnnotationRayOverwriteContainerCmd: "true",
+	}
+	if ms.Spec.Distributed.EnableGCSFaultTolerance {
+		annotations[constant.AnnotationRayFTEnabledKey] = "true"
+	}
+
+	cluster := &rayv1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getFormattedMSName(ms.Name, ""),
+			Namespace: ms.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(ms, ms.GroupVersionKind()),
+			},
+			Labels: map[string]string{
+				constant.LabelLLMOSMLType:             typeName,
+				constant.LabelModelServiceName:        ms.Name,
+				constant.LabelModelServiceServeEngine: vllmEngineName,
+			},
+			Annotations: annotations,
+		},
+		Spec: rayv1.RayClusterSpec{
+			Suspend: ptr.To(metav1.HasAnnotation(ms.ObjectMeta, constant.AnnotationResourceStopped)),
+			HeadGroupSpec: rayv1.HeadGroupSpec{
+				RayStartParams: map[string]string{
+					"dashboard-host": "0.0.0.0",
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: headLabels,
+					},
+					Spec: headSpec,
+				},
+			},
+			WorkerGroupSpecs: []rayv1.WorkerGroupSpec{
+				{
+					GroupName:      rayWorkerGroupName,
+					Replicas:       ptr.To(workers),
+					MinReplicas:    ptr.To(workers),
+					MaxReplicas:    ptr.To(workers),
+					RayStartParams: map[string]string{},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{
+								constant.LabelModelServiceName: ms.Name,
+							},
+						},
+						Spec: workerSpec,
+					},
+				},
+			},
+		},
+	}
+	cluster.Annotations[constant.AnnotationSpecHash] = hashRayClusterSpec(cluster.Spec)
+
+	return cluster
+}
+
+// constructRayPodSpec builds the pod spec of the Ray nodes, each node downloads the model by itself
+// and the volume claim templates are converted to the ephemeral volumes of the pod.
+func constructRayPodSpec(ms *mlv1.ModelService) corev1.PodSpec {
+	podSpec := *ms.Spec.Template.Spec.DeepCopy()
+	podSpec.InitContainers = constructInitContainers(ms, podSpec.Containers[0])
+	podSpec.Containers[0].Env = buildEnvs(ms, podSpec.Containers[0])
+
+	for _, vct := range ms.Spec.VolumeClaimTemplates {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: vct.Name,
+			VolumeSource: corev1.VolumeSource{
+				Ephemeral: &corev1.EphemeralVolumeSource{
+					VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels:      vct.Labels,
+							Annotations: vct.Annotations,
+						},
+						Spec: *vct.Spec.DeepCopy(),
+					},
+				},
+			},
+		})
+	}
+
+	return podSpec
+}
+
+// hashRayClusterSpec returns the hash of the Ray cluster spec without the suspend field,
+// the Ray cluster needs to be recreated since KubeRay won't roll out the changed pod templates.
+func hashRayClusterSpec(spec rayv1.RayClusterSpec) string {
+	spec.Suspend = nil
+	data, err := json.Marshal(spec)
+	if err != nil {
+		logrus.Errorf("failed to marshal ray cluster spec: %v", err)
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func shellQuoteArgs(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
+
+// reconcileRayCluster reconciles the Ray cluster of the distributed model service
+func (h *handler) reconcileRayCluster(ms *mlv1.ModelService) error {
+	cluster := constructRayCluster(ms)
+	found, err := h.RayClusterCache.Get(cluster.Namespace, cluster.Name)
+	if err != nil && errors.IsNotFound(err) {
+		logrus.Infof("creating ray cluster of model %s/%s", ms.Namespace, ms.Name)
+		_, err = h.RayClusters.Create(cluster)
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if found.DeletionTimestamp != nil {
+		h.ModelServices.EnqueueAfter(ms.Namespace, ms.Name, requeueInterval)
+		return nil
+	}
+
+	if found.Annotations[constant.AnnotationSpecHash] != cluster.Annotations[constant.AnnotationSpecHash] {
+		logrus.Infof("recreating ray cluster %s/%s since its spec is changed", found.Namespace, found.Name)
+		if err = h.RayClusters.Delete(found.Namespace, found.Name, &metav1.DeleteOptions{}); err != nil &&
+			!errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ray cluster %s/%s: %w", found.Namespace, found.Name, err)
+		}
+		h.ModelServices.EnqueueAfter(ms.Namespace, ms.Name, requeueInterval)
+		return nil
+	}
+
+	if !ptr.Equal(found.Spec.Suspend, cluster.Spec.Suspend) {
+		toUpdate := found.DeepCopy()
+		toUpdate.Spec.Suspend = cluster.Spec.Suspend
+		_, err = h.RayClusters.Update(toUpdate)
+		return err
+	}
+
+	return nil
+}
+
+// cleanupServingResources removes the serving resources of the other mode when the model service
+// is switched between the single node and the distributed mode
+func (h *handler) cleanupServingResources(ms *mlv1.ModelService) error {
+	name := getFormattedMSName(ms.Name, "")
+	if ms.Spec.Distributed != nil {
+		if _, err := h.StatefulSetCache.Get(ms.Namespace, name); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		logrus.Infof("removing statefulSet of the distributed model %s/%s", ms.Namespace, ms.Name)
+		if err := h.StatefulSets.Delete(ms.Namespace, name, &metav1.DeleteOptions{}); err != nil &&
+			!errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete statefulSet %s/%s: %w", ms.Namespace, name, err)
+		}
+		return nil
+	}
+
+	if _, err := h.RayClusterCache.Get(ms.Namespace, name); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	logrus.Infof("removing ray cluster of the model %s/%s", ms.Namespace, ms.Name)
+	if err := h.RayClusters.Delete(ms.Namespace, name, &metav1.DeleteOptions{}); err != nil &&
+		!errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ray cluster %s/%s: %w", ms.Namespace, name, err)
+	}
+	return nil
+}
+
+type rayClusterHandler struct {
+	rayClusters       ctlkuberayv1.RayClusterController
+	modelService      ctlmlv1.ModelServiceClient
+	modelServiceCache ctlmlv1.ModelServiceCache
+	podCache          ctlcorev1.PodCache
+	getLogs           podLogGetter
+}
+
+// OnChange updates the model service status by the Ray cluster and its head pod
+func (h *rayClusterHandler) OnChange(_ string, cluster *rayv1.RayCluster) (*rayv1.RayCluster, error) {
+	if cluster == nil || cluster.DeletionTimestamp != nil || cluster.Labels == nil {
+		return nil, nil
+	}
+
+	msName := cluster.Labels[constant.LabelModelServiceName]
+	if msName == "" {
+		return nil, nil
+	}
+
+	modelService, err := h.modelServiceCache.Get(cluster.Namespace, msName)
+	if err != nil && errors.IsNotFound(err) {
+		return cluster, nil
+	} else if err != nil {
+		return cluster, err
+	}
+
+	if modelService.Spec.Distributed == nil {
+		return cluster, nil
+	}
+
+	pods, err := h.podCache.List(cluster.Namespace, labels.SelectorFromSet(map[string]string{
+		constant.LabelRayClusterName: cluster.Name,
+		constant.LabelRayNodeType:    rayHeadNodeType,
+	}))
+	if err != nil {
+		return cluster, err
+	}
+
+	status := constructRayClusterStatus(cluster, pods)
+	if len(pods) > 0 && diagnoseModelPod(&status, modelService.Status.Conditions, pods[0], h.getLogs) {
+		h.rayClusters.EnqueueAfter(cluster.Namespace, cluster.Name, requeueInterval)
+	}
+	status.Adapters = modelService.Status.Adapters
+	if !reflect.DeepEqual(modelService.Status, status) {
+		msCpy := modelService.DeepCopy()
+		msCpy.Status = status
+		if _, err = h.modelService.UpdateStatus(msCpy); err != nil {
+			return cluster, err
+		}
+	}
+
+	return cluster, nil
+}
+
+func constructRayClusterStatus(cluster *rayv1.RayCluster, headPods []*corev1.Pod) mlv1.ModelServiceStatus {
+	if ptr.Deref(cluster.Spec.Suspend, false) {
+		return mlv1.ModelServiceStatus{State: "Paused"}
+	}
+	if len(headPods) == 0 {
+		return mlv1.ModelServiceStatus{State: "Pending"}
+	}
+
+	var readyReplicas int32
+	if cluster.Status.State == rayv1.Ready {
+		readyReplicas = 1
+	}
+	return constructPodStatus(readyReplicas, headPods[0])
+}
+
+// syncRayClusterStatusByPod reconciles the Ray cluster by its head pod event
+func syncRayClusterStatusByPod(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if pod, ok := obj.(*corev1.Pod); ok {
+		if pod.Labels[constant.LabelRayNodeType] != rayHeadNodeType || pod.Labels[constant.LabelModelServiceName] == "" {
+			return nil, nil
+		}
+		return []relatedresource.Key{
+			{
+				Name:      pod.Labels[constant.LabelRayClusterName],
+				Namespace: pod.Namespace,
+			},
+		}, nil
+	}
+	return nil, nil
+}
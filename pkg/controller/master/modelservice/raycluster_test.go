@@ -0,0 +1,87 @@
+package modelservice
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+)
+
+func newDistributedModelService() *mlv1.ModelService {
+	ms := &mlv1.ModelService{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama-70b", Namespace: "default"},
+	}
+	ms.Spec.ModelRegistry = "huggingface"
+	ms.Spec.ModelName = "meta-llama/Llama-3.1-70B"
+	ms.Spec.Replicas = 1
+	ms.Spec.Template.Spec.Containers = []v1.Container{
+		{
+			Name:  "vllm",
+			Image: "vllm/vllm-openai",
+			Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8000}},
+			Resources: v1.ResourceRequirements{
+				Limits: v1.ResourceList{
+					vGPUNumber: resource.MustParse("4"),
+				},
+			},
+		},
+	}
+	ms.Spec.Distributed = &mlv1.ModelServiceDistributed{PipelineParallelSize: 3}
+	return ms
+}
+
+func TestConstructRayCluster(t *testing.T) {
+	ms := newDistributedModelService()
+
+	cluster := constructRayCluster(ms)
+
+	if *cluster.Spec.WorkerGroupSpecs[0].Replicas != 2 {
+		t.Errorf("Expected 2 worker replicas, got %d", *cluster.Spec.WorkerGroupSpecs[0].Replicas)
+	}
+	if *cluster.Spec.Suspend {
+		t.Errorf("Expected the ray cluster not to be suspended")
+	}
+
+	headArgs := cluster.Spec.HeadGroupSpec.Template.Spec.Containers[0].Args[0]
+	for _, arg := range []string{"'--pipeline-parallel-size=3'", "'--tensor-parallel-size=4'",
+		"'--distributed-executor-backend=ray'"} {
+		if !strings.Contains(headArgs, arg) {
+			t.Errorf("Expected head args to contain %s, got %s", arg, headArgs)
+		}
+	}
+
+	// only the head pod serves the model
+	for k, v := range GetModelServiceSelector(ms).MatchLabels {
+		if cluster.Spec.HeadGroupSpec.Template.Labels[k] != v {
+			t.Errorf("Expected head pod label %s=%s", k, v)
+		}
+	}
+	if _, ok := cluster.Spec.WorkerGroupSpecs[0].Template.Labels[constant.LabelLLMOSMLType]; ok {
+		t.Errorf("Expected worker pods not to be selected by the model service")
+	}
+	if len(cluster.Spec.WorkerGroupSpecs[0].Template.Spec.InitContainers) != 1 {
+		t.Errorf("Expected worker pods to download the model")
+	}
+}
+
+func TestConstructRayCluster_SpecHash(t *testing.T) {
+	ms := newDistributedModelService()
+	hash := constructRayCluster(ms).Annotations[constant.AnnotationSpecHash]
+
+	stopped := ms.DeepCopy()
+	stopped.Annotations = map[string]string{constant.AnnotationResourceStopped: "true"}
+	if got := constructRayCluster(stopped).Annotations[constant.AnnotationSpecHash]; got != hash {
+		t.Errorf("Expected suspending not to change the spec hash")
+	}
+
+	changed := ms.DeepCopy()
+	changed.Spec.Distributed.PipelineParallelSize = 4
+	if got := constructRayCluster(changed).Annotations[constant.AnnotationSpecHash]; got == hash {
+		t.Errorf("Expected the spec hash to be changed")
+	}
+}
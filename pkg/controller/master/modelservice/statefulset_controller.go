@@ -19,11 +19,13 @@ import (
 )
 
 type statefulSetHandler struct {
+	statefulSets      ctlappsv1.StatefulSetController
 	statefulSetCache  ctlappsv1.StatefulSetCache
 	modelService      ctlmlv1.ModelServiceClient
 	modelServiceCache ctlmlv1.ModelServiceCache
 	pods              ctlcorev1.PodClient
 	podCache          ctlcorev1.PodCache
+	getLogs           podLogGetter
 }
 
 func (h *statefulSetHandler) OnChange(_ string, statefulSet *appsv1.StatefulSet) (*appsv1.StatefulSet, error) {
@@ -44,6 +46,10 @@ func (h *statefulSetHandler) OnChange(_ string, statefulSet *appsv1.StatefulSet)
 		return statefulSet, err
 	}
 
+	if isCanaryStatefulSet(statefulSet) {
+		return h.updateCanaryStatus(statefulSet, modelService)
+	}
+
 	return h.updateModelServiceStatus(statefulSet, modelService)
 }
 
@@ -64,6 +70,16 @@ func (h *statefulSetHandler) updateModelServiceStatus(ss *appsv1.StatefulSet, mo
 	}
 
 	status := constructModelStatus(ss, pod)
+	if diagnoseModelPod(&status, modelService.Status.Conditions, pod, h.getLogs) {
+		// pod events won't be triggered by the download and loading progress
+		h.statefulSets.EnqueueAfter(ss.Namespace, ss.Name, requeueInterval)
+	}
+	canary, err := h.getStatefulSet(ss.Namespace, getCanaryStatefulSetName(modelService.Name))
+	if err != nil {
+		return ss, err
+	}
+	status.Canary = constructCanaryStatus(ss, canary)
+	status.Adapters = modelService.Status.Adapters
 	if !reflect.DeepEqual(modelService.Status, status) {
 		msCpy := modelService.DeepCopy()
 		msCpy.Status = status
@@ -75,6 +91,36 @@ func (h *statefulSetHandler) updateModelServiceStatus(ss *appsv1.StatefulSet, mo
 	return ss, nil
 }
 
+func (h *statefulSetHandler) updateCanaryStatus(canary *appsv1.StatefulSet, modelService *mlv1.ModelService) (
+	*appsv1.StatefulSet, error) {
+	stable, err := h.getStatefulSet(canary.Namespace, getFormattedMSName(modelService.Name, ""))
+	if err != nil {
+		return canary, err
+	}
+
+	status := constructCanaryStatus(stable, canary)
+	if !reflect.DeepEqual(modelService.Status.Canary, status) {
+		msCpy := modelService.DeepCopy()
+		msCpy.Status.Canary = status
+		if _, err = h.modelService.UpdateStatus(msCpy); err != nil {
+			return canary, err
+		}
+	}
+
+	return canary, nil
+}
+
+// getStatefulSet returns nil if the statefulSet is not found
+func (h *statefulSetHandler) getStatefulSet(namespace, name string) (*appsv1.StatefulSet, error) {
+	ss, err := h.statefulSetCache.Get(namespace, name)
+	if err != nil && errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
 // syncServiceStatusByPod reconciles the owner statefulSet by pod event
 func (h *statefulSetHandler) syncServiceStatusByPod(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
 	if pod, ok := obj.(*corev1.Pod); ok {
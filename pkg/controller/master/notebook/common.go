@@ -5,11 +5,13 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
 	"reflect"
 	"regexp"
 	"sort"
 	"strings"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -21,6 +23,7 @@ import (
 	"github.com/llmos-ai/llmos-operator/pkg/apis/common"
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
 	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
 	ctlsnapshotv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/snapshot.storage.k8s.io/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/utils/reconcilehelper"
@@ -34,7 +37,10 @@ const (
 func constructNoteBookStatefulSet(
 	notebook *mlv1.Notebook,
 	datasetVersionCache ctlmlv1.DatasetVersionCache,
+	localModelCache ctlmlv1.LocalModelCache,
+	localModelVersionCache ctlmlv1.LocalModelVersionCache,
 	volumeSnapshotCache ctlsnapshotv1.VolumeSnapshotCache,
+	storageResolver *snapshotting.StorageResolver,
 ) (*v1.StatefulSet, error) {
 	replicas := notebook.Spec.Replicas
 	if metav1.HasAnnotation(notebook.ObjectMeta, constant.AnnotationResourceStopped) {
@@ -106,11 +112,27 @@ func constructNoteBookStatefulSet(
 		}
 	}
 
+	if len(notebook.Spec.DatasetMountings) == 0 && len(notebook.Spec.ModelMountings) == 0 {
+		return ss, nil
+	}
+
+	// The mounting volumes are restored from the volume snapshots with the resolved storage class
+	sc, err := storageResolver.GetStorageClass()
+	if err != nil {
+		return nil, err
+	}
+
 	// Handle dataset mountings
-	if err := addDatasetMountings(ss, notebook, datasetVersionCache, volumeSnapshotCache); err != nil {
+	if err := addDatasetMountings(ss, notebook, sc.Name, datasetVersionCache, volumeSnapshotCache); err != nil {
 		return nil, fmt.Errorf("failed to add dataset mountings: %w", err)
 	}
 
+	// Handle model mountings
+	if err := addModelMountings(ss, notebook, sc.Name, localModelCache, localModelVersionCache,
+		volumeSnapshotCache); err != nil {
+		return nil, fmt.Errorf("failed to add model mountings: %w", err)
+	}
+
 	return ss, nil
 }
 
@@ -219,6 +241,7 @@ func getNotebookPodName(statefulSetName string) string {
 func addDatasetMountings(
 	ss *v1.StatefulSet,
 	notebook *mlv1.Notebook,
+	storageClassName string,
 	datasetVersionCache ctlmlv1.DatasetVersionCache,
 	volumeSnapshotCache ctlsnapshotv1.VolumeSnapshotCache,
 ) error {
@@ -257,44 +280,20 @@ func addDatasetMountings(
 		}
 
 		// Create PVC name for this dataset mounting
-		pvcName := generatePVCName(mounting)
+		pvcName := generatePVCName(mounting.DatasetName, mounting.Version, mounting.MountPath)
 
 		// Create PVC with VolumeSnapshot as data source
-		pvc := corev1.PersistentVolumeClaim{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: pvcName,
-				OwnerReferences: []metav1.OwnerReference{
-					{
-						APIVersion: notebook.APIVersion,
-						Kind:       notebook.Kind,
-						Name:       notebook.Name,
-						UID:        notebook.UID,
-					},
-				},
-			},
-			Spec: corev1.PersistentVolumeClaimSpec{
-				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
-				StorageClassName: ptr.To("llmos-ceph-block"),
-				Resources: corev1.VolumeResourceRequirements{
-					Requests: corev1.ResourceList{
-						corev1.ResourceStorage: *volumeSnapshot.Status.RestoreSize,
-					},
-				},
-				DataSource: &corev1.TypedLocalObjectReference{
-					Kind:     "VolumeSnapshot",
-					Name:     datasetVersion.Status.PublishStatus.SnapshotName,
-					APIGroup: ptr.To("snapshot.storage.k8s.io"),
-				},
-			},
-		}
+		pvc := newSnapshotPVC(notebook, pvcName, storageClassName, volumeSnapshot)
 
 		// Add PVC to VolumeClaimTemplates
 		ss.Spec.VolumeClaimTemplates = append(ss.Spec.VolumeClaimTemplates, pvc)
 
 		// Add volume mount to the container
+		// The ReadOnlyMany volume can only be mounted as read-only
 		volumeMount := corev1.VolumeMount{
 			Name:      pvcName,
 			MountPath: mounting.MountPath,
+			ReadOnly:  pvc.Spec.AccessModes[0] == corev1.ReadOnlyMany,
 		}
 
 		// Add volume mount to the first container (assuming it's the main notebook container)
@@ -309,11 +308,121 @@ func addDatasetMountings(
 	return nil
 }
 
+// addModelMountings adds the local model versions to the StatefulSet, the model volumes are restored from
+// the volume snapshots of the versions and mounted as read-only.
+func addModelMountings(
+	ss *v1.StatefulSet,
+	notebook *mlv1.Notebook,
+	storageClassName string,
+	localModelCache ctlmlv1.LocalModelCache,
+	localModelVersionCache ctlmlv1.LocalModelVersionCache,
+	volumeSnapshotCache ctlsnapshotv1.VolumeSnapshotCache,
+) error {
+	for _, mounting := range notebook.Spec.ModelMountings {
+		version, err := GetModelMountingVersion(notebook.Namespace, mounting, localModelCache, localModelVersionCache)
+		if err != nil {
+			return err
+		}
+
+		volumeSnapshot, err := volumeSnapshotCache.Get(version.Namespace, version.Status.VolumeSnapshot)
+		if err != nil {
+			return fmt.Errorf("failed to get volume snapshot %s: %w", version.Status.VolumeSnapshot, err)
+		}
+
+		pvcName := generatePVCName(mounting.LocalModel, version.Name, mounting.MountPath)
+		ss.Spec.VolumeClaimTemplates = append(ss.Spec.VolumeClaimTemplates,
+			newSnapshotPVC(notebook, pvcName, storageClassName, volumeSnapshot))
+
+		if len(ss.Spec.Template.Spec.Containers) > 0 {
+			ss.Spec.Template.Spec.Containers[0].VolumeMounts = append(
+				ss.Spec.Template.Spec.Containers[0].VolumeMounts,
+				corev1.VolumeMount{
+					Name:      pvcName,
+					MountPath: mounting.MountPath,
+					// the model files are downloaded to the directory of the local model in the volume
+					SubPath:  path.Join(version.Namespace, version.Spec.LocalModel),
+					ReadOnly: true,
+				},
+			)
+		}
+	}
+
+	return nil
+}
+
+// GetModelMountingVersion returns the ready LocalModelVersion of the model mounting, which is the default
+// version of the LocalModel if the version is not specified.
+func GetModelMountingVersion(
+	namespace string,
+	mounting mlv1.ModelMounting,
+	localModelCache ctlmlv1.LocalModelCache,
+	localModelVersionCache ctlmlv1.LocalModelVersionCache,
+) (*mlv1.LocalModelVersion, error) {
+	versionName := mounting.Version
+	if versionName == "" {
+		localModel, err := localModelCache.Get(namespace, mounting.LocalModel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get local model %s/%s: %w", namespace, mounting.LocalModel, err)
+		}
+		if versionName = localModel.Status.DefaultVersionName; versionName == "" {
+			return nil, fmt.Errorf("local model %s/%s has no default version", namespace, mounting.LocalModel)
+		}
+	}
+
+	version, err := localModelVersionCache.Get(namespace, versionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local model version %s/%s: %w", namespace, versionName, err)
+	}
+	if version.Spec.LocalModel != mounting.LocalModel {
+		return nil, fmt.Errorf("local model version %s does not belong to local model %s",
+			versionName, mounting.LocalModel)
+	}
+	if !mlv1.Ready.IsTrue(version) || version.Status.VolumeSnapshot == "" {
+		return nil, fmt.Errorf("local model version %s/%s does not have a ready volume snapshot",
+			namespace, versionName)
+	}
+
+	return version, nil
+}
+
+// newSnapshotPVC returns the volume claim template restored from the volume snapshot, the access mode is
+// ReadOnlyMany if it's enabled so that the notebook replicas can share the restored volume
+func newSnapshotPVC(notebook *mlv1.Notebook, name, storageClassName string,
+	volumeSnapshot *snapshotv1.VolumeSnapshot) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: notebook.APIVersion,
+					Kind:       notebook.Kind,
+					Name:       notebook.Name,
+					UID:        notebook.UID,
+				},
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{snapshotting.GetRestoreAccessMode()},
+			StorageClassName: ptr.To(storageClassName),
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: *volumeSnapshot.Status.RestoreSize,
+				},
+			},
+			DataSource: &corev1.TypedLocalObjectReference{
+				Kind:     "VolumeSnapshot",
+				Name:     volumeSnapshot.Name,
+				APIGroup: ptr.To("snapshot.storage.k8s.io"),
+			},
+		},
+	}
+}
+
 // generatePVCName creates a PVC name that respects Kubernetes naming constraints
 // Kubernetes resource names must be no more than 63 characters and follow DNS naming conventions
-func generatePVCName(mounting mlv1.DatasetMounting) string {
+func generatePVCName(name, version, mountPath string) string {
 	// Create a unique string from the mounting information
-	hashInput := fmt.Sprintf("%s-%s-%s", mounting.DatasetName, mounting.Version, mounting.MountPath)
+	hashInput := fmt.Sprintf("%s-%s-%s", name, version, mountPath)
 
 	// Generate SHA256 hash and take first 8 characters
 	hasher := sha256.New()
@@ -322,10 +431,10 @@ func generatePVCName(mounting mlv1.DatasetMounting) string {
 	hashSuffix := hex.EncodeToString(hashBytes)[:8]
 
 	// normalisze version for Kubernetes naming
-	normalizedVersion := normalizeForK8s(mounting.Version)
+	normalizedVersion := normalizeForK8s(version)
 
-	// Format as datasetName-version-suffix
-	prefix := fmt.Sprintf("%s-%s", mounting.DatasetName, normalizedVersion)
+	// Format as name-version-suffix
+	prefix := fmt.Sprintf("%s-%s", name, normalizedVersion)
 	pvcName := fmt.Sprintf("%s-%s", prefix, hashSuffix)
 
 	// Ensure the name doesn't exceed 63 characters (Kubernetes limit)
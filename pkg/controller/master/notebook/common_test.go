@@ -0,0 +1,151 @@
+package notebook
+
+import (
+	"testing"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/llmos-ai/llmos-operator/pkg/apis/common"
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/clientset/versioned/fake"
+	"github.com/llmos-ai/llmos-operator/pkg/utils/fakeclients"
+)
+
+func TestAddModelMountings(t *testing.T) {
+	readyCondition := []common.Condition{{Type: mlv1.Ready, Status: metav1.ConditionTrue}}
+	fakeClient := fake.NewSimpleClientset(
+		&mlv1.LocalModel{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "qwen"},
+			Status:     mlv1.LocalModelStatus{DefaultVersionName: "qwen-v2"},
+		},
+		&mlv1.LocalModel{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "llama"},
+		},
+		&mlv1.LocalModelVersion{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "qwen-v1"},
+			Spec:       mlv1.LocalModelVersionSpec{LocalModel: "qwen"},
+			Status: mlv1.LocalModelVersionStatus{
+				Version:        1,
+				Conditions:     readyCondition,
+				VolumeSnapshot: "qwen-v1-snapshot",
+			},
+		},
+		&mlv1.LocalModelVersion{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "qwen-v2"},
+			Spec:       mlv1.LocalModelVersionSpec{LocalModel: "qwen"},
+			Status: mlv1.LocalModelVersionStatus{
+				Version:        2,
+				Conditions:     readyCondition,
+				VolumeSnapshot: "qwen-v2-snapshot",
+			},
+		},
+		&mlv1.LocalModelVersion{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "qwen-v3"},
+			Spec:       mlv1.LocalModelVersionSpec{LocalModel: "qwen"},
+			Status:     mlv1.LocalModelVersionStatus{Version: 3},
+		},
+		&snapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "qwen-v1-snapshot"},
+			Status:     &snapshotv1.VolumeSnapshotStatus{RestoreSize: ptrQuantity("10Gi")},
+		},
+		&snapshotv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "qwen-v2-snapshot"},
+			Status:     &snapshotv1.VolumeSnapshotStatus{RestoreSize: ptrQuantity("20Gi")},
+		},
+		&snapshotv1.VolumeSnapshotClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ceph-block-snapshot"},
+			Driver:     "rbd.csi.ceph.com",
+		},
+	)
+	k8sClient := k8sfake.NewSimpleClientset(&storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "ceph-block"},
+		Provisioner: "rbd.csi.ceph.com",
+	})
+	localModelCache := fakeclients.LocalModelCache(fakeClient.MlV1().LocalModels)
+	localModelVersionCache := fakeclients.LocalModelVersionCache(fakeClient.MlV1().LocalModelVersions)
+	volumeSnapshotCache := fakeclients.VolumeSnapshotCache(fakeClient.SnapshotV1().VolumeSnapshots)
+	storageResolver := snapshotting.NewStorageResolver(
+		fakeclients.StorageClassCache(k8sClient.StorageV1().StorageClasses),
+		fakeclients.VolumeSnapshotClassCache(fakeClient.SnapshotV1().VolumeSnapshotClasses))
+
+	var testCases = []struct {
+		name         string
+		mountings    []mlv1.ModelMounting
+		expectedSize string
+		expectedErr  bool
+	}{
+		{
+			name:         "default version",
+			mountings:    []mlv1.ModelMounting{{LocalModel: "qwen", MountPath: "/models/qwen"}},
+			expectedSize: "20Gi",
+		},
+		{
+			name:         "specified version",
+			mountings:    []mlv1.ModelMounting{{LocalModel: "qwen", Version: "qwen-v1", MountPath: "/models/qwen"}},
+			expectedSize: "10Gi",
+		},
+		{
+			name:        "version not ready",
+			mountings:   []mlv1.ModelMounting{{LocalModel: "qwen", Version: "qwen-v3", MountPath: "/models/qwen"}},
+			expectedErr: true,
+		},
+		{
+			name:        "version of another local model",
+			mountings:   []mlv1.ModelMounting{{LocalModel: "llama", Version: "qwen-v1", MountPath: "/models/llama"}},
+			expectedErr: true,
+		},
+		{
+			name:        "local model without default version",
+			mountings:   []mlv1.ModelMounting{{LocalModel: "llama", MountPath: "/models/llama"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		notebook := &mlv1.Notebook{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nb"},
+			Spec: mlv1.NotebookSpec{
+				Template: mlv1.NotebookTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nb", Image: "jupyter"}}},
+				},
+				ModelMountings: tc.mountings,
+			},
+		}
+		ss, err := constructNoteBookStatefulSet(notebook, nil, localModelCache, localModelVersionCache,
+			volumeSnapshotCache, storageResolver)
+		if tc.expectedErr {
+			assert.NotNil(t, err, "case %q", tc.name)
+			continue
+		}
+		assert.Nil(t, err, "case %q", tc.name)
+
+		if assert.Len(t, ss.Spec.VolumeClaimTemplates, 1, "case %q", tc.name) {
+			pvc := ss.Spec.VolumeClaimTemplates[0]
+			assert.Equal(t, resource.MustParse(tc.expectedSize), pvc.Spec.Resources.Requests[corev1.ResourceStorage],
+				"case %q", tc.name)
+			assert.Equal(t, "ceph-block", *pvc.Spec.StorageClassName, "case %q", tc.name)
+
+			volumeMounts := ss.Spec.Template.Spec.Containers[0].VolumeMounts
+			assert.Equal(t, []corev1.VolumeMount{
+				{
+					Name:      pvc.Name,
+					MountPath: tc.mountings[0].MountPath,
+					SubPath:   "default/qwen",
+					ReadOnly:  true,
+				},
+			}, volumeMounts, "case %q", tc.name)
+		}
+	}
+}
+
+func ptrQuantity(value string) *resource.Quantity {
+	q := resource.MustParse(value)
+	return &q
+}
@@ -0,0 +1,299 @@
+package notebook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/settings"
+)
+
+// Note: the idle culling is referred to the kubeflow's notebook culler
+// https://github.com/kubeflow/kubeflow/blob/master/components/notebook-controller/controllers/culling_controller.go
+
+const (
+	cullingCheckInterval  = time.Minute
+	jupyterRequestTimeout = 5 * time.Second
+	// maxScheduleLookBack bounds the schedule activations to evaluate after the controller is down for a long time
+	maxScheduleLookBack = 7 * 24 * time.Hour
+
+	nbPrefixEnvName       = "NB_PREFIX"
+	kernelStateBusy       = "busy"
+	jupyterStatusPath     = "/api/status"
+	jupyterKernelsPath    = "/api/kernels"
+	scheduleActionNone    = ""
+	scheduleActionStart   = "start"
+	scheduleActionStop    = "stop"
+	jupyterActivityLayout = time.RFC3339Nano
+)
+
+type jupyterStatus struct {
+	LastActivity string `json:"last_activity"`
+}
+
+type jupyterKernel struct {
+	LastActivity   string `json:"last_activity"`
+	ExecutionState string `json:"execution_state"`
+}
+
+// reconcileLifecycle stops the notebook when it's idle or out of its schedule and starts it on its schedule,
+// the notebook is requeued to be checked periodically since the activities of the notebook fire no events.
+func (h *Handler) reconcileLifecycle(notebook *mlv1.Notebook) (*mlv1.Notebook, error) {
+	// the time is persisted in seconds, truncate it to avoid updating the status repeatedly
+	now := time.Now().Truncate(time.Second)
+	status := notebook.Status.DeepCopy()
+	stopped := metav1.HasAnnotation(notebook.ObjectMeta, constant.AnnotationResourceStopped)
+	var stoppedReason mlv1.NotebookStoppedReason
+	requeueAfter := time.Duration(0)
+
+	// the notebook is started again by the user, reset the activity so that it's not culled immediately
+	if !stopped && status.StoppedReason != "" {
+		status.StoppedReason = ""
+		status.LastActivityTime = &metav1.Time{Time: now}
+	}
+
+	if schedule := notebook.Spec.Schedule; schedule != nil {
+		since := notebook.CreationTimestamp.Time
+		if status.LastScheduleTime != nil {
+			since = status.LastScheduleTime.Time
+		}
+		action, next, err := evaluateSchedule(schedule, since, now)
+		if err != nil {
+			logrus.Warnf("invalid schedule of notebook %s/%s: %v", notebook.Namespace, notebook.Name, err)
+		} else {
+			switch {
+			case action == scheduleActionStop && !stopped:
+				stopped, stoppedReason = true, mlv1.NotebookStoppedReasonSchedule
+			case action == scheduleActionStart && stopped:
+				stopped = false
+				status.StoppedReason = ""
+				status.LastActivityTime = &metav1.Time{Time: now}
+			}
+			if action != scheduleActionNone {
+				status.LastScheduleTime = &metav1.Time{Time: now}
+			}
+			requeueAfter = next.Sub(now)
+		}
+	}
+
+	if timeout := h.getIdleTimeout(notebook.Namespace); timeout > 0 && !stopped {
+		if lastActivity, err := h.getLastActivity(notebook); err != nil {
+			logrus.Debugf("failed to get activity of notebook %s/%s: %v", notebook.Namespace, notebook.Name, err)
+		} else if lastActivity = lastActivity.Truncate(time.Second); !lastActivity.IsZero() &&
+			(status.LastActivityTime == nil || lastActivity.After(status.LastActivityTime.Time)) {
+			status.LastActivityTime = &metav1.Time{Time: lastActivity}
+		}
+
+		if status.LastActivityTime != nil && now.Sub(status.LastActivityTime.Time) >= timeout {
+			stopped, stoppedReason = true, mlv1.NotebookStoppedReasonIdle
+		}
+		if requeueAfter <= 0 || requeueAfter > cullingCheckInterval {
+			requeueAfter = cullingCheckInterval
+		}
+	}
+
+	if requeueAfter > 0 {
+		h.notebooks.EnqueueAfter(notebook.Namespace, notebook.Name, requeueAfter)
+	}
+
+	var err error
+	if stopped != metav1.HasAnnotation(notebook.ObjectMeta, constant.AnnotationResourceStopped) {
+		nbCopy := notebook.DeepCopy()
+		if stopped {
+			logrus.Infof("stopping notebook %s/%s, reason: %s", notebook.Namespace, notebook.Name, stoppedReason)
+			metav1.SetMetaDataAnnotation(&nbCopy.ObjectMeta, constant.AnnotationResourceStopped, constant.TrueStr)
+			status.StoppedReason = stoppedReason
+		} else {
+			logrus.Infof("starting notebook %s/%s on schedule", notebook.Namespace, notebook.Name)
+			delete(nbCopy.Annotations, constant.AnnotationResourceStopped)
+		}
+		if notebook, err = h.notebooks.Update(nbCopy); err != nil {
+			return notebook, err
+		}
+	}
+
+	if !isLifecycleStatusEqual(&notebook.Status, status) {
+		nbCopy := notebook.DeepCopy()
+		nbCopy.Status.LastActivityTime = status.LastActivityTime
+		nbCopy.Status.LastScheduleTime = status.LastScheduleTime
+		nbCopy.Status.StoppedReason = status.StoppedReason
+		return h.notebooks.UpdateStatus(nbCopy)
+	}
+
+	return notebook, nil
+}
+
+// getIdleTimeout returns the idle timeout of the namespace annotation, or the global setting if not specified
+func (h *Handler) getIdleTimeout(namespace string) time.Duration {
+	minutes := settings.NotebookIdleTimeoutMinutes.GetInt()
+	if ns, err := h.namespaceCache.Get(namespace); err == nil {
+		if value, ok := ns.Annotations[constant.AnnotationNotebookIdleTimeout]; ok {
+			if i, err := strconv.Atoi(value); err == nil {
+				minutes = i
+			} else {
+				logrus.Warnf("invalid notebook idle timeout %q of namespace %s: %v", value, namespace, err)
+			}
+		}
+	}
+	if minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// getLastActivity returns the last activity of the running notebook pod reported by the Jupyter server
+func (h *Handler) getLastActivity(notebook *mlv1.Notebook) (time.Time, error) {
+	pod, err := h.podCache.Get(notebook.Namespace, getNotebookPodName(getFormattedNotebookName(notebook)))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || len(pod.Spec.Containers) == 0 {
+		return time.Time{}, fmt.Errorf("notebook pod %s is not running", pod.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jupyterRequestTimeout)
+	defer cancel()
+	return getJupyterLastActivity(ctx, h.httpClient, getJupyterURL(pod))
+}
+
+// getJupyterURL returns the url of the Jupyter server in the pod, the base url is set by the NB_PREFIX env
+func getJupyterURL(pod *corev1.Pod) string {
+	container := pod.Spec.Containers[0]
+	port := DefaultContainerPort
+	if len(container.Ports) > 0 {
+		port = container.Ports[0].ContainerPort
+	}
+
+	prefix := ""
+	for _, env := range container.Env {
+		if env.Name == nbPrefixEnvName {
+			prefix = strings.TrimRight(env.Value, "/")
+		}
+	}
+	return fmt.Sprintf("http://%s:%d%s", pod.Status.PodIP, port, prefix)
+}
+
+// getJupyterLastActivity returns the last activity of the Jupyter server, a busy kernel is regarded as active now
+func getJupyterLastActivity(ctx context.Context, client *http.Client, url string) (time.Time, error) {
+	status := &jupyterStatus{}
+	if err := getJupyterAPI(ctx, client, url+jupyterStatusPath, status); err != nil {
+		return time.Time{}, err
+	}
+	kernels := make([]jupyterKernel, 0)
+	if err := getJupyterAPI(ctx, client, url+jupyterKernelsPath, &kernels); err != nil {
+		return time.Time{}, err
+	}
+
+	lastActivity := parseJupyterTime(status.LastActivity)
+	for _, kernel := range kernels {
+		if kernel.ExecutionState == kernelStateBusy {
+			return time.Now(), nil
+		}
+		if t := parseJupyterTime(kernel.LastActivity); t.After(lastActivity) {
+			lastActivity = t
+		}
+	}
+	return lastActivity, nil
+}
+
+func getJupyterAPI(ctx context.Context, client *http.Client, url string, obj interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(obj)
+}
+
+func parseJupyterTime(value string) time.Time {
+	t, err := time.Parse(jupyterActivityLayout, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// evaluateSchedule returns the latest schedule action fired in (since, now] and the next activation time
+func evaluateSchedule(schedule *mlv1.NotebookSchedule, since, now time.Time) (string, time.Time, error) {
+	start, stop, err := ParseSchedule(schedule)
+	if err != nil {
+		return scheduleActionNone, time.Time{}, err
+	}
+	if lookBack := now.Add(-maxScheduleLookBack); since.Before(lookBack) {
+		since = lookBack
+	}
+
+	action, actionTime, next := scheduleActionNone, time.Time{}, time.Time{}
+	for name, s := range map[string]cron.Schedule{scheduleActionStart: start, scheduleActionStop: stop} {
+		if s == nil {
+			continue
+		}
+		last := time.Time{}
+		for t := s.Next(since); !t.After(now); t = s.Next(t) {
+			last = t
+		}
+		if !last.IsZero() && last.After(actionTime) {
+			action, actionTime = name, last
+		}
+		if t := s.Next(now); next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	return action, next, nil
+}
+
+// ParseSchedule parses the start and stop cron expressions of the schedule in its time zone
+func ParseSchedule(schedule *mlv1.NotebookSchedule) (start, stop cron.Schedule, err error) {
+	location := time.UTC
+	if schedule.TimeZone != "" {
+		if location, err = time.LoadLocation(schedule.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("invalid time zone %s: %w", schedule.TimeZone, err)
+		}
+	}
+
+	parse := func(expr string) (cron.Schedule, error) {
+		if expr == "" {
+			return nil, nil
+		}
+		s, err := cron.ParseStandard(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		if spec, ok := s.(*cron.SpecSchedule); ok {
+			spec.Location = location
+		}
+		return s, nil
+	}
+
+	if start, err = parse(schedule.Start); err != nil {
+		return nil, nil, err
+	}
+	if stop, err = parse(schedule.Stop); err != nil {
+		return nil, nil, err
+	}
+	return start, stop, nil
+}
+
+func isLifecycleStatusEqual(a, b *mlv1.NotebookStatus) bool {
+	return a.StoppedReason == b.StoppedReason &&
+		a.LastActivityTime.Equal(b.LastActivityTime) &&
+		a.LastScheduleTime.Equal(b.LastScheduleTime)
+}
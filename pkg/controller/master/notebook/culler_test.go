@@ -0,0 +1,157 @@
+package notebook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+func TestEvaluateSchedule(t *testing.T) {
+	schedule := &mlv1.NotebookSchedule{
+		Start:    "0 8 * * 1-5",
+		Stop:     "0 19 * * 1-5",
+		TimeZone: "Asia/Shanghai",
+	}
+	location, err := time.LoadLocation(schedule.TimeZone)
+	assert.Nil(t, err)
+	// 2025-06-02 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 6, day, hour, minute, 0, 0, location)
+	}
+
+	var testCases = []struct {
+		name           string
+		since          time.Time
+		now            time.Time
+		expectedAction string
+		expectedNext   time.Time
+	}{
+		{
+			name:           "no action fired",
+			since:          at(2, 9, 0),
+			now:            at(2, 12, 0),
+			expectedAction: scheduleActionNone,
+			expectedNext:   at(2, 19, 0),
+		},
+		{
+			name:           "stop after working hours",
+			since:          at(2, 9, 0),
+			now:            at(2, 19, 0),
+			expectedAction: scheduleActionStop,
+			expectedNext:   at(3, 8, 0),
+		},
+		{
+			name:           "start on the next working day",
+			since:          at(2, 19, 0),
+			now:            at(3, 8, 30),
+			expectedAction: scheduleActionStart,
+			expectedNext:   at(3, 19, 0),
+		},
+		{
+			name:           "latest action wins after a long downtime",
+			since:          at(2, 9, 0),
+			now:            at(7, 10, 0),
+			expectedAction: scheduleActionStop,
+			expectedNext:   at(9, 8, 0),
+		},
+	}
+
+	for _, tc := range testCases {
+		action, next, err := evaluateSchedule(schedule, tc.since, tc.now)
+		assert.Nil(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.expectedAction, action, "case %q", tc.name)
+		assert.True(t, tc.expectedNext.Equal(next), "case %q: expected next %s, got %s", tc.name, tc.expectedNext, next)
+	}
+
+	_, _, err = evaluateSchedule(&mlv1.NotebookSchedule{Stop: "0 25 * * *"}, at(2, 0, 0), at(3, 0, 0))
+	assert.NotNil(t, err)
+	_, _, err = evaluateSchedule(&mlv1.NotebookSchedule{Stop: "0 19 * * *", TimeZone: "Mars/Base"}, at(2, 0, 0),
+		at(3, 0, 0))
+	assert.NotNil(t, err)
+}
+
+func TestGetJupyterLastActivity(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		status   string
+		kernels  string
+		expected time.Time
+		busy     bool
+	}{
+		{
+			name:     "idle server without kernels",
+			status:   `{"last_activity": "2025-06-02T09:00:00.123456Z", "connections": 0, "kernels": 0}`,
+			kernels:  `[]`,
+			expected: time.Date(2025, 6, 2, 9, 0, 0, 123456000, time.UTC),
+		},
+		{
+			name:   "kernel activity is later than the server",
+			status: `{"last_activity": "2025-06-02T09:00:00Z"}`,
+			kernels: `[{"id": "a", "execution_state": "idle", "last_activity": "2025-06-02T10:30:00Z"},
+				{"id": "b", "execution_state": "idle", "last_activity": "2025-06-02T10:00:00Z"}]`,
+			expected: time.Date(2025, 6, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "busy kernel",
+			status:  `{"last_activity": "2025-06-02T09:00:00Z"}`,
+			kernels: `[{"id": "a", "execution_state": "busy", "last_activity": "2025-06-02T09:00:00Z"}]`,
+			busy:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/notebook/default/nb" + jupyterStatusPath:
+				_, _ = fmt.Fprint(w, tc.status)
+			case "/notebook/default/nb" + jupyterKernelsPath:
+				_, _ = fmt.Fprint(w, tc.kernels)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+
+		start := time.Now()
+		actual, err := getJupyterLastActivity(context.Background(), server.Client(), server.URL+"/notebook/default/nb")
+		server.Close()
+		assert.Nil(t, err, "case %q", tc.name)
+		if tc.busy {
+			assert.False(t, actual.Before(start), "case %q", tc.name)
+		} else {
+			assert.True(t, tc.expected.Equal(actual), "case %q: expected %s, got %s", tc.name, tc.expected, actual)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	_, err := getJupyterLastActivity(context.Background(), server.Client(), server.URL)
+	assert.NotNil(t, err, "the token protected server should not be regarded as idle")
+}
+
+func TestGetJupyterURL(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Env: []corev1.EnvVar{{Name: nbPrefixEnvName, Value: "/notebook/default/nb/"}},
+				},
+			},
+		},
+		Status: corev1.PodStatus{PodIP: "10.0.0.1"},
+	}
+	assert.Equal(t, "http://10.0.0.1:8888/notebook/default/nb", getJupyterURL(pod))
+
+	pod.Spec.Containers[0].Env = nil
+	pod.Spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: 8080}}
+	assert.Equal(t, "http://10.0.0.1:8080", getJupyterURL(pod))
+}
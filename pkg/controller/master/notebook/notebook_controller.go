@@ -3,6 +3,7 @@ package notebook
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
 	ctlappsv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/apps/v1"
@@ -15,6 +16,7 @@ import (
 
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
 	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
 	ctlsnapshotv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/snapshot.storage.k8s.io/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/server/config"
@@ -31,15 +33,21 @@ const (
 )
 
 type Handler struct {
-	statefulSets        ctlappsv1.StatefulSetClient
-	statefulSetCache    ctlappsv1.StatefulSetCache
-	services            ctlcorev1.ServiceClient
-	serviceCache        ctlcorev1.ServiceCache
-	pvcHandler          *utils.PVCHandler
-	pods                ctlcorev1.PodClient
-	podCache            ctlcorev1.PodCache
-	datasetVersionCache ctlmlv1.DatasetVersionCache
-	volumeSnapshotCache ctlsnapshotv1.VolumeSnapshotCache
+	notebooks              ctlmlv1.NotebookController
+	statefulSets           ctlappsv1.StatefulSetClient
+	statefulSetCache       ctlappsv1.StatefulSetCache
+	services               ctlcorev1.ServiceClient
+	serviceCache           ctlcorev1.ServiceCache
+	pvcHandler             *utils.PVCHandler
+	pods                   ctlcorev1.PodClient
+	podCache               ctlcorev1.PodCache
+	datasetVersionCache    ctlmlv1.DatasetVersionCache
+	localModelCache        ctlmlv1.LocalModelCache
+	localModelVersionCache ctlmlv1.LocalModelVersionCache
+	volumeSnapshotCache    ctlsnapshotv1.VolumeSnapshotCache
+	storageResolver        *snapshotting.StorageResolver
+	namespaceCache         ctlcorev1.NamespaceCache
+	httpClient             *http.Client
 }
 
 const (
@@ -57,17 +65,25 @@ func Register(ctx context.Context, mgmt *config.Management, _ config.Options) er
 	pvcs := mgmt.CoreFactory.Core().V1().PersistentVolumeClaim()
 	datasetVersions := mgmt.LLMFactory.Ml().V1().DatasetVersion()
 	volumeSnapshots := mgmt.SnapshotFactory.Snapshot().V1().VolumeSnapshot()
+	storageResolver := snapshotting.NewStorageResolver(mgmt.StorageFactory.Storage().V1().StorageClass().Cache(),
+		mgmt.SnapshotFactory.Snapshot().V1().VolumeSnapshotClass().Cache())
 
 	h := Handler{
-		statefulSets:        ss,
-		statefulSetCache:    ss.Cache(),
-		services:            services,
-		serviceCache:        services.Cache(),
-		pvcHandler:          utils.NewPVCHandler(pvcs),
-		pods:                pods,
-		podCache:            pods.Cache(),
-		datasetVersionCache: datasetVersions.Cache(),
-		volumeSnapshotCache: volumeSnapshots.Cache(),
+		notebooks:              notebooks,
+		statefulSets:           ss,
+		statefulSetCache:       ss.Cache(),
+		services:               services,
+		serviceCache:           services.Cache(),
+		pvcHandler:             utils.NewPVCHandler(pvcs),
+		pods:                   pods,
+		podCache:               pods.Cache(),
+		datasetVersionCache:    datasetVersions.Cache(),
+		localModelCache:        mgmt.LLMFactory.Ml().V1().LocalModel().Cache(),
+		localModelVersionCache: mgmt.LLMFactory.Ml().V1().LocalModelVersion().Cache(),
+		volumeSnapshotCache:    volumeSnapshots.Cache(),
+		storageResolver:        storageResolver,
+		namespaceCache:         mgmt.CoreFactory.Core().V1().Namespace().Cache(),
+		httpClient:             &http.Client{Timeout: jupyterRequestTimeout},
 	}
 	notebooks.OnChange(ctx, notebookOnChange, h.OnChanged)
 	notebooks.OnRemove(ctx, notebookOnDelete, h.OnDelete)
@@ -89,6 +105,12 @@ func (h *Handler) OnChanged(_ string, notebook *mlv1.Notebook) (*mlv1.Notebook,
 		return nil, nil
 	}
 
+	// stop the idle notebook and stop or start the notebook on its schedule
+	notebook, err := h.reconcileLifecycle(notebook)
+	if err != nil {
+		return notebook, err
+	}
+
 	// reconcile notebook statefulSet
 	if _, err := h.reconcileStatefulSet(notebook); err != nil {
 		return notebook, err
@@ -113,7 +135,8 @@ func (h *Handler) OnChanged(_ string, notebook *mlv1.Notebook) (*mlv1.Notebook,
 }
 
 func (h *Handler) reconcileStatefulSet(notebook *mlv1.Notebook) (*appsv1.StatefulSet, error) {
-	ss, err := constructNoteBookStatefulSet(notebook, h.datasetVersionCache, h.volumeSnapshotCache)
+	ss, err := constructNoteBookStatefulSet(notebook, h.datasetVersionCache, h.localModelCache,
+		h.localModelVersionCache, h.volumeSnapshotCache, h.storageResolver)
 	if err != nil {
 		return nil, fmt.Errorf("construct notebook statefulset failed: %w", err)
 	}
@@ -104,6 +104,7 @@ func TestHandler_OnNotebookChanged(t *testing.T) {
 			statefulSetCache: fakeclients.StatefulSetCache(k8sClient.AppsV1().StatefulSets),
 			services:         fakeclients.ServiceClient(k8sClient.CoreV1().Services),
 			serviceCache:     fakeclients.ServiceCache(k8sClient.CoreV1().Services),
+			namespaceCache:   fakeclients.NamespaceCache(k8sClient.CoreV1().Namespaces),
 		}
 		var actual output
 		actual.Notebook, actual.err = h.OnChanged(tc.given.key, tc.given.Notebook)
@@ -0,0 +1,125 @@
+package notebook
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+)
+
+// ApplyProfile expands the profile into the notebook. The image, resources and GPU type of the profile are only
+// used if they're not specified in the notebook, and the env, volumes and mountings of the profile are merged
+// with the notebook's by their names or mount paths.
+func ApplyProfile(nb *mlv1.Notebook, profile *mlv1.NotebookProfile) error {
+	preset, err := GetResourcePreset(profile, nb.Spec.ResourcePreset)
+	if err != nil {
+		return err
+	}
+
+	podSpec := &nb.Spec.Template.Spec
+	if len(podSpec.Containers) == 0 {
+		// the container is renamed after the notebook by the controller
+		podSpec.Containers = []corev1.Container{{Name: nb.Name}}
+	}
+	container := &podSpec.Containers[0]
+	if container.Image == "" {
+		container.Image = profile.Spec.Image
+	}
+	// the explicitly selected preset overrides the resources of the container
+	if preset != nil && (nb.Spec.ResourcePreset != "" ||
+		(len(container.Resources.Requests) == 0 && len(container.Resources.Limits) == 0)) {
+		container.Resources = *preset.Resources.DeepCopy()
+	}
+
+	if profile.Spec.GPUType != "" {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		if _, ok := podSpec.NodeSelector[constant.NvidiaGPUProductLabel]; !ok {
+			podSpec.NodeSelector[constant.NvidiaGPUProductLabel] = profile.Spec.GPUType
+		}
+	}
+
+	for _, env := range profile.Spec.Env {
+		if !slices.ContainsFunc(container.Env, func(e corev1.EnvVar) bool { return e.Name == env.Name }) {
+			container.Env = append(container.Env, *env.DeepCopy())
+		}
+	}
+	for _, volume := range profile.Spec.Volumes {
+		if !slices.ContainsFunc(podSpec.Volumes, func(v corev1.Volume) bool { return v.Name == volume.Name }) {
+			podSpec.Volumes = append(podSpec.Volumes, *volume.DeepCopy())
+		}
+	}
+	for _, mount := range profile.Spec.VolumeMounts {
+		if !slices.ContainsFunc(container.VolumeMounts, func(m corev1.VolumeMount) bool {
+			return m.Name == mount.Name || m.MountPath == mount.MountPath
+		}) {
+			container.VolumeMounts = append(container.VolumeMounts, *mount.DeepCopy())
+		}
+	}
+	for _, pvc := range profile.Spec.VolumeClaimTemplates {
+		if !slices.ContainsFunc(nb.Spec.VolumeClaimTemplates, func(p corev1.PersistentVolumeClaim) bool {
+			return p.Name == pvc.Name
+		}) {
+			nb.Spec.VolumeClaimTemplates = append(nb.Spec.VolumeClaimTemplates, *pvc.DeepCopy())
+		}
+	}
+	for _, mounting := range profile.Spec.DatasetMountings {
+		if !slices.ContainsFunc(nb.Spec.DatasetMountings, func(m mlv1.DatasetMounting) bool {
+			return m.MountPath == mounting.MountPath
+		}) {
+			nb.Spec.DatasetMountings = append(nb.Spec.DatasetMountings, mounting)
+		}
+	}
+	for _, toleration := range profile.Spec.Tolerations {
+		if !slices.ContainsFunc(podSpec.Tolerations, func(t corev1.Toleration) bool {
+			return reflect.DeepEqual(t, toleration)
+		}) {
+			podSpec.Tolerations = append(podSpec.Tolerations, *toleration.DeepCopy())
+		}
+	}
+
+	if nb.Labels == nil {
+		nb.Labels = map[string]string{}
+	}
+	nb.Labels[constant.LabelNotebookProfileName] = profile.Name
+
+	return nil
+}
+
+// GetResourcePreset returns the preset of the profile by its name, the first preset is returned if the name is
+// empty, and nil is returned if the profile has no presets
+func GetResourcePreset(profile *mlv1.NotebookProfile, name string) (*mlv1.NotebookResourcePreset, error) {
+	if name == "" {
+		if len(profile.Spec.ResourcePresets) == 0 {
+			return nil, nil
+		}
+		return &profile.Spec.ResourcePresets[0], nil
+	}
+
+	for i := range profile.Spec.ResourcePresets {
+		if profile.Spec.ResourcePresets[i].Name == name {
+			return &profile.Spec.ResourcePresets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("resource preset %s not found in notebook profile %s", name, profile.Name)
+}
+
+// IsProfileAllowed returns whether the notebooks in the namespace may use the profile
+func IsProfileAllowed(profile *mlv1.NotebookProfile, namespace *corev1.Namespace) (bool, error) {
+	if profile.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(profile.Spec.NamespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace selector of notebook profile %s: %w", profile.Name, err)
+	}
+	return selector.Matches(labels.Set(namespace.Labels)), nil
+}
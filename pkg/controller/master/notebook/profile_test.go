@@ -0,0 +1,171 @@
+package notebook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/constant"
+)
+
+func newTestProfile() *mlv1.NotebookProfile {
+	return &mlv1.NotebookProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "pytorch-gpu"},
+		Spec: mlv1.NotebookProfileSpec{
+			Image: "llmos/notebook-pytorch:v1",
+			ResourcePresets: []mlv1.NotebookResourcePreset{
+				{
+					Name: "small",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+				{
+					Name: "large",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+					},
+				},
+			},
+			GPUType: "NVIDIA-A100-SXM4-80GB",
+			Env:     []corev1.EnvVar{{Name: "HF_HOME", Value: "/data/hf"}, {Name: "TZ", Value: "UTC"}},
+			Volumes: []corev1.Volume{
+				{Name: "dshm", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: "dshm", MountPath: "/dev/shm"}},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{ObjectMeta: metav1.ObjectMeta{Name: "workspace"}},
+			},
+			DatasetMountings: []mlv1.DatasetMounting{{DatasetName: "docs", Version: "v1", MountPath: "/data/docs"}},
+			Tolerations:      []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}},
+		},
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		notebook          *mlv1.Notebook
+		expectedImage     string
+		expectedCPU       string
+		expectedEnv       []corev1.EnvVar
+		expectedGPUType   string
+		expectedErrString string
+	}{
+		{
+			name: "empty notebook",
+			notebook: &mlv1.Notebook{
+				ObjectMeta: metav1.ObjectMeta{Name: "nb", Namespace: "default"},
+				Spec:       mlv1.NotebookSpec{ProfileName: "pytorch-gpu"},
+			},
+			expectedImage:   "llmos/notebook-pytorch:v1",
+			expectedCPU:     "1",
+			expectedEnv:     []corev1.EnvVar{{Name: "HF_HOME", Value: "/data/hf"}, {Name: "TZ", Value: "UTC"}},
+			expectedGPUType: "NVIDIA-A100-SXM4-80GB",
+		},
+		{
+			name: "notebook settings take precedence",
+			notebook: &mlv1.Notebook{
+				ObjectMeta: metav1.ObjectMeta{Name: "nb", Namespace: "default"},
+				Spec: mlv1.NotebookSpec{
+					ProfileName: "pytorch-gpu",
+					Template: mlv1.NotebookTemplateSpec{Spec: corev1.PodSpec{
+						NodeSelector: map[string]string{constant.NvidiaGPUProductLabel: "NVIDIA-L4"},
+						Containers: []corev1.Container{{
+							Name:  "nb",
+							Image: "custom:v1",
+							Env:   []corev1.EnvVar{{Name: "TZ", Value: "Asia/Shanghai"}},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+							},
+						}},
+					}},
+				},
+			},
+			expectedImage:   "custom:v1",
+			expectedCPU:     "2",
+			expectedEnv:     []corev1.EnvVar{{Name: "TZ", Value: "Asia/Shanghai"}, {Name: "HF_HOME", Value: "/data/hf"}},
+			expectedGPUType: "NVIDIA-L4",
+		},
+		{
+			name: "selected preset overrides the resources",
+			notebook: &mlv1.Notebook{
+				ObjectMeta: metav1.ObjectMeta{Name: "nb", Namespace: "default"},
+				Spec: mlv1.NotebookSpec{
+					ProfileName:    "pytorch-gpu",
+					ResourcePreset: "large",
+					Template: mlv1.NotebookTemplateSpec{Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name: "nb",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+							},
+						}},
+					}},
+				},
+			},
+			expectedImage:   "llmos/notebook-pytorch:v1",
+			expectedCPU:     "8",
+			expectedEnv:     []corev1.EnvVar{{Name: "HF_HOME", Value: "/data/hf"}, {Name: "TZ", Value: "UTC"}},
+			expectedGPUType: "NVIDIA-A100-SXM4-80GB",
+		},
+		{
+			name: "missing preset",
+			notebook: &mlv1.Notebook{
+				ObjectMeta: metav1.ObjectMeta{Name: "nb", Namespace: "default"},
+				Spec:       mlv1.NotebookSpec{ProfileName: "pytorch-gpu", ResourcePreset: "huge"},
+			},
+			expectedErrString: "resource preset huge not found in notebook profile pytorch-gpu",
+		},
+	}
+
+	for _, tc := range testCases {
+		err := ApplyProfile(tc.notebook, newTestProfile())
+		if tc.expectedErrString != "" {
+			assert.EqualError(t, err, tc.expectedErrString, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+
+		podSpec := tc.notebook.Spec.Template.Spec
+		container := podSpec.Containers[0]
+		assert.Equal(t, tc.expectedImage, container.Image, tc.name)
+		assert.Equal(t, tc.expectedCPU, container.Resources.Requests.Cpu().String(), tc.name)
+		assert.Equal(t, tc.expectedEnv, container.Env, tc.name)
+		assert.Equal(t, tc.expectedGPUType, podSpec.NodeSelector[constant.NvidiaGPUProductLabel], tc.name)
+		assert.Len(t, podSpec.Volumes, 1, tc.name)
+		assert.Len(t, container.VolumeMounts, 1, tc.name)
+		assert.Len(t, podSpec.Tolerations, 1, tc.name)
+		assert.Len(t, tc.notebook.Spec.VolumeClaimTemplates, 1, tc.name)
+		assert.Len(t, tc.notebook.Spec.DatasetMountings, 1, tc.name)
+		assert.Equal(t, "pytorch-gpu", tc.notebook.Labels[constant.LabelNotebookProfileName], tc.name)
+
+		// applying the profile again changes nothing
+		expanded := tc.notebook.DeepCopy()
+		assert.NoError(t, ApplyProfile(expanded, newTestProfile()), tc.name)
+		assert.Equal(t, tc.notebook, expanded, tc.name)
+	}
+}
+
+func TestIsProfileAllowed(t *testing.T) {
+	profile := newTestProfile()
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"gpu": "true"}}}
+
+	allowed, err := IsProfileAllowed(profile, namespace)
+	assert.NoError(t, err)
+	assert.True(t, allowed, "profile without namespace selector is allowed in all namespaces")
+
+	profile.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}}
+	allowed, err = IsProfileAllowed(profile, namespace)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	namespace.Labels = nil
+	allowed, err = IsProfileAllowed(profile, namespace)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+}
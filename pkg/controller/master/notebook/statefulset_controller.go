@@ -61,6 +61,10 @@ func (h *statefulSetHandler) updateNotebookStatus(ss *appsv1.StatefulSet, notebo
 	}
 
 	status := getNotebookStatus(ss, pod)
+	// the lifecycle fields are managed by the notebook controller
+	status.LastActivityTime = notebook.Status.LastActivityTime
+	status.LastScheduleTime = notebook.Status.LastScheduleTime
+	status.StoppedReason = notebook.Status.StoppedReason
 	if !reflect.DeepEqual(notebook.Status, status) {
 		nbCpy := notebook.DeepCopy()
 		nbCpy.Status = status
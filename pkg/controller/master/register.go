@@ -6,13 +6,18 @@ import (
 	steve "github.com/rancher/steve/pkg/server"
 	"github.com/rancher/wrangler/v3/pkg/leader"
 
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/batchinferencejob"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/datacollection"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/dataset"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/finetunejob"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/globalrole"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/group"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/knowledgebase"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/lineage"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/localmodel"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/managedaddon"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/model"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/modelbenchmark"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/modelservice"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/monitoring"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/namespace"
@@ -39,10 +44,14 @@ var registerFuncs = []registerFunc{
 	raycluster.Register,
 	managedaddon.Register,
 	modelservice.Register,
+	modelbenchmark.Register,
+	finetunejob.Register,
+	batchinferencejob.Register,
 	token.Register,
 	globalrole.Register,
 	roletemplate.Register,
 	roletemplatebinding.Register,
+	group.Register,
 	namespace.Register,
 	node.Register,
 	monitoring.Register,
@@ -50,6 +59,7 @@ var registerFuncs = []registerFunc{
 	dataset.Register,
 	model.Register,
 	localmodel.Register,
+	lineage.Register,
 	datacollection.Register,
 	knowledgebase.Register,
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
 	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/registry"
 	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
@@ -15,7 +16,8 @@ import (
 )
 
 const (
-	registryOnChangeName = "registry.OnChange"
+	registryOnChangeName            = "registry.OnChange"
+	registryCredentialsOnChangeName = "registry.syncDownloaderCredentials"
 )
 
 type handler struct {
@@ -26,6 +28,8 @@ type handler struct {
 	secretClient   ctlcorev1.SecretClient
 	secretCache    ctlcorev1.SecretCache
 
+	downloaderAccess *snapshotting.DownloaderAccess
+
 	rm *registry.Manager
 }
 
@@ -40,13 +44,22 @@ func Register(_ context.Context, mgmt *config.Management, _ config.Options) erro
 		registryCache:  registries.Cache(),
 		secretClient:   secrets,
 		secretCache:    secrets.Cache(),
+
+		downloaderAccess: snapshotting.NewDownloaderAccess(mgmt),
 	}
 	h.rm = registry.NewManager(secrets.Cache().Get, registries.Cache().Get)
 
 	registries.OnChange(mgmt.Ctx, registryOnChangeName, h.CheckRegistryAccessibility)
+	registries.OnChange(mgmt.Ctx, registryCredentialsOnChangeName, h.SyncDownloaderCredentials)
 	return nil
 }
 
+// SyncDownloaderCredentials grants the downloaders the credential secrets of the registries once they are added,
+// changed or removed, rather than only when the next download job is created
+func (h *handler) SyncDownloaderCredentials(_ string, registry *mlv1.Registry) (*mlv1.Registry, error) {
+	return registry, h.downloaderAccess.EnsureRegistryCredentialsRole()
+}
+
 func (h *handler) CheckRegistryAccessibility(_ string, registry *mlv1.Registry) (*mlv1.Registry, error) {
 	if registry == nil || registry.DeletionTimestamp != nil {
 		return registry, nil
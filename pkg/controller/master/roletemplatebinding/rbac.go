@@ -4,10 +4,12 @@ import (
 	"fmt"
 
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/globalrole"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
 )
 
 func (h *handler) getClusterRole(rtb *mgmtv1.RoleTemplateBinding) (*rbacv1.ClusterRole, error) {
@@ -15,7 +17,77 @@ func (h *handler) getClusterRole(rtb *mgmtv1.RoleTemplateBinding) (*rbacv1.Clust
 	return h.crCache.Get(crName)
 }
 
-func constructClusterRoleBinding(rtb *mgmtv1.RoleTemplateBinding, cr *rbacv1.ClusterRole) *rbacv1.ClusterRoleBinding {
+// IsGroupSubject returns whether the subject is a llmos Group, the kubernetes groups are of the rbac api group
+func IsGroupSubject(subject rbacv1.Subject) bool {
+	return subject.Kind == rbacv1.GroupKind && subject.APIGroup == mgmtv1.SchemeGroupVersion.Group
+}
+
+// ExpandSubjects replaces the Group subjects by the User subjects of the group members
+func ExpandSubjects(subjects []rbacv1.Subject, groupCache ctlmgmtv1.GroupCache) ([]rbacv1.Subject, error) {
+	return expandSubjects(subjects, groupCache.Get)
+}
+
+func expandSubjects(subjects []rbacv1.Subject, getGroup func(string) (*mgmtv1.Group, error)) (
+	[]rbacv1.Subject, error) {
+	result := make([]rbacv1.Subject, 0, len(subjects))
+	seen := make(map[rbacv1.Subject]bool, len(subjects))
+	add := func(subject rbacv1.Subject) {
+		if !seen[subject] {
+			seen[subject] = true
+			result = append(result, subject)
+		}
+	}
+
+	for _, subject := range subjects {
+		if !IsGroupSubject(subject) {
+			add(subject)
+			continue
+		}
+
+		// the group may be created after the binding, it is expanded once the group is created
+		group, err := getGroup(subject.Name)
+		if err != nil && errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get group %s: %w", subject.Name, err)
+		}
+		for _, member := range group.Spec.Members {
+			add(rbacv1.Subject{
+				Kind:     rbacv1.UserKind,
+				APIGroup: rbacv1.GroupName,
+				Name:     member,
+			})
+		}
+	}
+	return result, nil
+}
+
+// BoundUser returns whether the user is bound by the RoleTemplateBinding, and the name of the group the user is
+// bound through, which is empty if the user is a subject of the binding directly
+func BoundUser(rtb *mgmtv1.RoleTemplateBinding, userName string, groups map[string]*mgmtv1.Group) (bool, string) {
+	bound, through := false, ""
+	for _, subject := range rtb.Subjects {
+		switch {
+		case subject.Kind == rbacv1.UserKind && subject.Name == userName:
+			return true, ""
+		case IsGroupSubject(subject) && !bound:
+			group, ok := groups[subject.Name]
+			if !ok {
+				continue
+			}
+			for _, member := range group.Spec.Members {
+				if member == userName {
+					bound, through = true, group.Name
+					break
+				}
+			}
+		}
+	}
+	return bound, through
+}
+
+func constructClusterRoleBinding(rtb *mgmtv1.RoleTemplateBinding, cr *rbacv1.ClusterRole,
+	subjects []rbacv1.Subject) *rbacv1.ClusterRoleBinding {
 	return &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: GenerateCRBName(rtb),
@@ -33,7 +105,7 @@ func constructClusterRoleBinding(rtb *mgmtv1.RoleTemplateBinding, cr *rbacv1.Clu
 			Kind:     "ClusterRole",
 			Name:     cr.Name,
 		},
-		Subjects: rtb.Subjects,
+		Subjects: subjects,
 	}
 }
 
@@ -60,7 +132,8 @@ func constructRole(rtb *mgmtv1.RoleTemplateBinding, rules []rbacv1.PolicyRule, n
 	}
 }
 
-func constructRoleBinding(rtb *mgmtv1.RoleTemplateBinding, role *rbacv1.Role, ns string) *rbacv1.RoleBinding {
+func constructRoleBinding(rtb *mgmtv1.RoleTemplateBinding, role *rbacv1.Role, ns string,
+	subjects []rbacv1.Subject) *rbacv1.RoleBinding {
 	return &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      GenerateRoleBindingName(rtb),
@@ -79,7 +152,7 @@ func constructRoleBinding(rtb *mgmtv1.RoleTemplateBinding, role *rbacv1.Role, ns
 			Kind:     "Role",
 			Name:     role.Name,
 		},
-		Subjects: rtb.Subjects,
+		Subjects: subjects,
 	}
 }
 
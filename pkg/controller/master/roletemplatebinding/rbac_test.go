@@ -0,0 +1,77 @@
+package roletemplatebinding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+)
+
+func newGroup(name string, members ...string) *mgmtv1.Group {
+	return &mgmtv1.Group{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       mgmtv1.GroupSpec{Members: members},
+	}
+}
+
+func userSubject(name string) rbacv1.Subject {
+	return rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: name}
+}
+
+func groupSubject(name string) rbacv1.Subject {
+	return rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: mgmtv1.SchemeGroupVersion.Group, Name: name}
+}
+
+func TestExpandSubjects(t *testing.T) {
+	groups := map[string]*mgmtv1.Group{
+		"ml-team":   newGroup("ml-team", "alice", "bob"),
+		"data-team": newGroup("data-team", "bob", "carol"),
+	}
+	getGroup := func(name string) (*mgmtv1.Group, error) {
+		if group, ok := groups[name]; ok {
+			return group, nil
+		}
+		return nil, apierrors.NewNotFound(mgmtv1.Resource("groups"), name)
+	}
+	k8sGroup := rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "system:authenticated"}
+
+	subjects, err := expandSubjects([]rbacv1.Subject{
+		userSubject("alice"),
+		groupSubject("ml-team"),
+		groupSubject("data-team"),
+		groupSubject("missing-team"),
+		k8sGroup,
+	}, getGroup)
+	require.NoError(t, err)
+	assert.Equal(t, []rbacv1.Subject{
+		userSubject("alice"),
+		userSubject("bob"),
+		userSubject("carol"),
+		k8sGroup,
+	}, subjects)
+}
+
+func TestBoundUser(t *testing.T) {
+	groups := map[string]*mgmtv1.Group{
+		"ml-team": newGroup("ml-team", "alice", "bob"),
+	}
+	rtb := &mgmtv1.RoleTemplateBinding{
+		Subjects: []rbacv1.Subject{groupSubject("ml-team"), userSubject("bob"), groupSubject("missing-team")},
+	}
+
+	bound, through := BoundUser(rtb, "alice", groups)
+	assert.True(t, bound)
+	assert.Equal(t, "ml-team", through)
+
+	bound, through = BoundUser(rtb, "bob", groups)
+	assert.True(t, bound)
+	assert.Empty(t, through)
+
+	bound, _ = BoundUser(rtb, "carol", groups)
+	assert.False(t, bound)
+}
@@ -7,7 +7,9 @@ import (
 
 	ctlrbacv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
 	"github.com/sirupsen/logrus"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
 	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
@@ -16,6 +18,7 @@ import (
 
 const (
 	rtbOnChangeName             = "roleTemplateBinding.onChange"
+	groupOnChangeName           = "roleTemplateBinding.onGroupChange"
 	roleTemplateRefNameLabelKey = "auth.management.llmos.ai/template-ref-name"
 	roleTemplateRefKindLabelKey = "auth.management.llmos.ai/template-kind"
 	rtbNameLabelKey             = "auth.management.llmos.ai/role-template-binding-name"
@@ -37,6 +40,9 @@ type handler struct {
 	grCache            ctlmgmtv1.GlobalRoleCache
 	roleTemplateClient ctlmgmtv1.RoleTemplateClient
 	roleTemplateCache  ctlmgmtv1.RoleTemplateCache
+	groupCache         ctlmgmtv1.GroupCache
+	rtbController      ctlmgmtv1.RoleTemplateBindingController
+	rtbCache           ctlmgmtv1.RoleTemplateBindingCache
 }
 
 func Register(ctx context.Context, mgmt *config.Management, _ config.Options) error {
@@ -47,6 +53,7 @@ func Register(ctx context.Context, mgmt *config.Management, _ config.Options) er
 	gr := mgmt.MgmtFactory.Management().V1().GlobalRole()
 	rb := mgmt.RbacFactory.Rbac().V1().RoleBinding()
 	rts := mgmt.MgmtFactory.Management().V1().RoleTemplate()
+	groups := mgmt.MgmtFactory.Management().V1().Group()
 
 	h := &handler{
 		crClient:           crs,
@@ -61,17 +68,45 @@ func Register(ctx context.Context, mgmt *config.Management, _ config.Options) er
 		grCache:            gr.Cache(),
 		roleTemplateClient: rts,
 		roleTemplateCache:  rts.Cache(),
+		groupCache:         groups.Cache(),
+		rtbController:      rtb,
+		rtbCache:           rtb.Cache(),
 	}
 	rtb.OnChange(ctx, rtbOnChangeName, h.onChange)
+	groups.OnChange(ctx, groupOnChangeName, h.onGroupChange)
 	return nil
 }
 
+// onGroupChange re-syncs the RoleTemplateBindings of the group, so that the membership changes are propagated
+// to their ClusterRoleBindings and RoleBindings, the group is nil if it is deleted
+func (h *handler) onGroupChange(name string, _ *mgmtv1.Group) (*mgmtv1.Group, error) {
+	rtbs, err := h.rtbCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role template bindings: %w", err)
+	}
+
+	for _, rtb := range rtbs {
+		for _, subject := range rtb.Subjects {
+			if IsGroupSubject(subject) && subject.Name == name {
+				h.rtbController.Enqueue(rtb.Name)
+				break
+			}
+		}
+	}
+	return nil, nil
+}
+
 // onChange watches RoleTemplateBinding changes and creates/updates the corresponding
 // ClusterRole/Role and ClusterRoleBinding/RoleBinding
 func (h *handler) onChange(_ string, rtb *mgmtv1.RoleTemplateBinding) (*mgmtv1.RoleTemplateBinding, error) {
 	if rtb == nil || rtb.DeletionTimestamp != nil {
 		return rtb, nil
 	}
+	subjects, err := ExpandSubjects(rtb.Subjects, h.groupCache)
+	if err != nil {
+		return rtb, err
+	}
+
 	refKind := rtb.RoleTemplateRef.Kind
 	switch refKind {
 	case GlobalRoleKindName:
@@ -81,12 +116,12 @@ func (h *handler) onChange(_ string, rtb *mgmtv1.RoleTemplateBinding) (*mgmtv1.R
 		}
 
 		// create cluster roleBinding
-		if err := h.reconcileClusterRoleBinding(rtb); err != nil {
+		if err := h.reconcileClusterRoleBinding(rtb, subjects); err != nil {
 			return rtb, err
 		}
 
 		// create namespaced role and roleBinding
-		if err := h.reconcileNamespacedRoles(rtb, gr); err != nil {
+		if err := h.reconcileNamespacedRoles(rtb, gr, subjects); err != nil {
 			return rtb, err
 		}
 		return rtb, nil
@@ -101,7 +136,7 @@ func (h *handler) onChange(_ string, rtb *mgmtv1.RoleTemplateBinding) (*mgmtv1.R
 		}
 
 		// create namespaced role and roleBinding
-		if err := h.reconcileRoleTemplateRoles(rtb, roleTemplate); err != nil {
+		if err := h.reconcileRoleTemplateRoles(rtb, roleTemplate, subjects); err != nil {
 			return rtb, err
 		}
 		return rtb, nil
@@ -111,13 +146,13 @@ func (h *handler) onChange(_ string, rtb *mgmtv1.RoleTemplateBinding) (*mgmtv1.R
 	}
 }
 
-func (h *handler) reconcileClusterRoleBinding(rtb *mgmtv1.RoleTemplateBinding) error {
+func (h *handler) reconcileClusterRoleBinding(rtb *mgmtv1.RoleTemplateBinding, subjects []rbacv1.Subject) error {
 	cr, err := h.getClusterRole(rtb)
 	if err != nil {
 		return err
 	}
 
-	crb := constructClusterRoleBinding(rtb, cr)
+	crb := constructClusterRoleBinding(rtb, cr, subjects)
 	foundCrb, err := h.crbCache.Get(crb.Name)
 	if err != nil && errors.IsNotFound(err) {
 		logrus.Debugf("creating cluster role binding %+v", crb)
@@ -129,13 +164,41 @@ func (h *handler) reconcileClusterRoleBinding(rtb *mgmtv1.RoleTemplateBinding) e
 		return err
 	}
 
-	if foundCrb != nil {
-		logrus.Debugf("cluster rolbe binding %s already exist, nothing to change", foundCrb.Name)
+	if !reflect.DeepEqual(crb.Subjects, foundCrb.Subjects) {
+		logrus.Debugf("updating subjects of cluster role binding %s", foundCrb.Name)
+		toUpdate := foundCrb.DeepCopy()
+		toUpdate.Subjects = crb.Subjects
+		if _, err = h.crbClient.Update(toUpdate); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (h *handler) reconcileNamespacedRoles(rtb *mgmtv1.RoleTemplateBinding, gr *mgmtv1.GlobalRole) error {
+func (h *handler) reconcileRoleBinding(rtb *mgmtv1.RoleTemplateBinding, rb *rbacv1.RoleBinding) error {
+	foundRb, err := h.roleBindingCache.Get(rb.Namespace, rb.Name)
+	if err != nil && errors.IsNotFound(err) {
+		logrus.Debugf("creating roleBinding %s:%s of roleTemplateBinding %s", rb.Name, rb.Namespace, rtb.Name)
+		_, err = h.roleBindingClient.Create(rb)
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(rb.Subjects, foundRb.Subjects) {
+		logrus.Debugf("updating subjects of roleBinding %s:%s of roleTemplateBinding %s", rb.Name, rb.Namespace,
+			rtb.Name)
+		toUpdate := foundRb.DeepCopy()
+		toUpdate.Subjects = rb.Subjects
+		if _, err = h.roleBindingClient.Update(toUpdate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *handler) reconcileNamespacedRoles(rtb *mgmtv1.RoleTemplateBinding, gr *mgmtv1.GlobalRole,
+	subjects []rbacv1.Subject) error {
 	for ns, rules := range gr.NamespacedRules {
 		role := constructRole(rtb, rules, ns)
 		foundRole, err := h.roleCache.Get(ns, role.Name)
@@ -159,21 +222,15 @@ func (h *handler) reconcileNamespacedRoles(rtb *mgmtv1.RoleTemplateBinding, gr *
 			}
 		}
 
-		rb := constructRoleBinding(rtb, role, ns)
-		_, err = h.roleBindingCache.Get(ns, rb.Name)
-		if err != nil && errors.IsNotFound(err) {
-			logrus.Debugf("creating roleBinding %s:%s of roleTemplateBinding %s", rb.Name, rb.Namespace, rtb.Name)
-			if _, err = h.roleBindingClient.Create(rb); err != nil {
-				return err
-			}
-		} else if err != nil {
+		if err = h.reconcileRoleBinding(rtb, constructRoleBinding(rtb, role, ns, subjects)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (h *handler) reconcileRoleTemplateRoles(rtb *mgmtv1.RoleTemplateBinding, rt *mgmtv1.RoleTemplate) error {
+func (h *handler) reconcileRoleTemplateRoles(rtb *mgmtv1.RoleTemplateBinding, rt *mgmtv1.RoleTemplate,
+	subjects []rbacv1.Subject) error {
 	ns := rtb.NamespaceId
 	role := constructRole(rtb, rt.Rules, ns)
 	foundRole, err := h.roleCache.Get(ns, role.Name)
@@ -197,16 +254,5 @@ func (h *handler) reconcileRoleTemplateRoles(rtb *mgmtv1.RoleTemplateBinding, rt
 		}
 	}
 
-	rb := constructRoleBinding(rtb, role, ns)
-	_, err = h.roleBindingCache.Get(ns, rb.Name)
-	if err != nil && errors.IsNotFound(err) {
-		logrus.Debugf("creating roleBinding %s:%s of roleTemplateBinding %s", rb.Name, rb.Namespace, rtb.Name)
-		if _, err = h.roleBindingClient.Create(rb); err != nil {
-			return err
-		}
-	} else if err != nil {
-		return err
-	}
-
-	return nil
+	return h.reconcileRoleBinding(rtb, constructRoleBinding(rtb, role, ns, subjects))
 }
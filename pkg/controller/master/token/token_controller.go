@@ -59,7 +59,7 @@ func (h *handler) reconcileStatus(token *mgmtv1.Token) (*mgmtv1.Token, error) {
 	return token, nil
 }
 
-// onCleanUpSync periodically checks expired tokens and deletes them
+// onCleanUpSync periodically checks expired tokens and idle sessions and deletes them
 func (h *handler) onCleanUpSync(ctx context.Context) {
 	ticker := time.NewTicker(syncInterval)
 	for {
@@ -76,8 +76,8 @@ func (h *handler) onCleanUpSync(ctx context.Context) {
 					continue
 				}
 
-				if token.Status.IsExpired || tokens.IsExpired(token) {
-					logrus.Debugf("deleting expired token %s", token.Name)
+				if token.Status.IsExpired || tokens.IsExpired(token) || tokens.IsIdle(token) {
+					logrus.Debugf("deleting expired or idle token %s", token.Name)
 					if err := h.tokens.Delete(token.Name, &metav1.DeleteOptions{}); err != nil {
 						logrus.Errorf("failed to delete token %s: %v", token.Name, err)
 					}
@@ -0,0 +1,107 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers"
+)
+
+// annotationDeactivatedBySync marks the users deactivated by the sync, only these users are activated again once
+// they're back in the directory, the users deactivated by the admins are left as is
+const annotationDeactivatedBySync = "auth.management.llmos.ai/deactivated-by-sync"
+
+// syncProviderUsers periodically syncs the groups and role bindings of the users of the provider with the directory
+func (h *handler) syncProviderUsers(ctx context.Context, provider providers.SyncProvider) {
+	for {
+		select {
+		case <-time.After(provider.SyncInterval()):
+			if !provider.Enabled() {
+				continue
+			}
+			if err := h.syncProvider(ctx, provider); err != nil {
+				logrus.Errorf("failed to sync users of auth provider %s: %v", provider.Name(), err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (h *handler) syncProvider(ctx context.Context, provider providers.SyncProvider) error {
+	users, err := h.userCache.List(labels.SelectorFromSet(map[string]string{
+		providers.LabelAuthProvider: provider.Name(),
+	}))
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		if user.DeletionTimestamp == nil && user.Spec.PrincipalID != "" {
+			ids = append(ids, user.Spec.PrincipalID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	principals, err := provider.Lookup(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if user.DeletionTimestamp != nil || user.Spec.PrincipalID == "" {
+			continue
+		}
+		if err = h.syncProviderUser(user, principals[user.Spec.PrincipalID], provider.GroupMappings()); err != nil {
+			logrus.Errorf("failed to sync user %s of auth provider %s: %v", user.Spec.Username, provider.Name(), err)
+		}
+	}
+	return nil
+}
+
+// syncProviderUser deactivates the user removed from the directory, and syncs the groups and role bindings of the
+// user still in the directory
+func (h *handler) syncProviderUser(user *mgmtv1.User, principal *providers.Principal,
+	mappings []providers.GroupMapping) error {
+	_, deactivatedBySync := user.Annotations[annotationDeactivatedBySync]
+	if principal == nil {
+		if !user.Spec.Active {
+			return nil
+		}
+		logrus.Infof("deactivating user %s removed from auth provider %s", user.Spec.Username,
+			user.Spec.AuthProvider)
+		toUpdate := user.DeepCopy()
+		toUpdate.Spec.Active = false
+		if toUpdate.Annotations == nil {
+			toUpdate.Annotations = map[string]string{}
+		}
+		toUpdate.Annotations[annotationDeactivatedBySync] = "true"
+		_, err := h.users.Update(toUpdate)
+		return err
+	}
+
+	if deactivatedBySync {
+		logrus.Infof("activating user %s back in auth provider %s", user.Spec.Username, user.Spec.AuthProvider)
+		toUpdate := user.DeepCopy()
+		toUpdate.Spec.Active = true
+		delete(toUpdate.Annotations, annotationDeactivatedBySync)
+		updated, err := h.users.Update(toUpdate)
+		if err != nil {
+			return err
+		}
+		user = updated
+	}
+
+	if !user.Spec.Active {
+		return nil
+	}
+	_, err := h.provisioner.Sync(user, principal, mappings)
+	return err
+}
@@ -9,6 +9,8 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/providers/ldap"
 	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
 	"github.com/llmos-ai/llmos-operator/pkg/constant"
 	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
@@ -28,6 +30,8 @@ type handler struct {
 	userCache ctlmgmtv1.UserCache
 	rtbClient ctlmgmtv1.RoleTemplateBindingClient
 	rtbCache  ctlmgmtv1.RoleTemplateBindingCache
+
+	provisioner *providers.Provisioner
 }
 
 func Register(ctx context.Context, management *config.Management, _ config.Options) error {
@@ -38,12 +42,16 @@ func Register(ctx context.Context, management *config.Management, _ config.Optio
 		userCache: users.Cache(),
 		rtbClient: rtb,
 		rtbCache:  rtb.Cache(),
+
+		provisioner: providers.NewProvisioner(management.MgmtFactory),
 	}
 
 	users.OnChange(ctx, userOnChangeName, h.OnChanged)
 	users.OnRemove(ctx, userOnRemoveName, h.OnDelete)
 	rtb.OnChange(ctx, userRoleTemplateBindingOnChange, h.OnRoleTemplateBindingChanged)
 	rtb.OnRemove(ctx, userRoleTemplateBindingOnRemove, h.OnRoleTemplateBindingDeleted)
+
+	go h.syncProviderUsers(ctx, ldap.NewProvider(management.CoreFactory.Core().V1().Secret()))
 	return nil
 }
 
@@ -83,6 +83,8 @@ func BootstrapDefaultAdmin(mgmt *config.Management) error {
 			Username:    "admin",
 			Password:    hash,
 			Active:      true,
+			// the bootstrap password is logged and kept in the secret, it must be changed at the first login
+			MustChangePassword: true,
 		},
 	}
 
@@ -142,6 +142,19 @@ func constructDefaultGlobalRole() []runtime.Object {
 						"watch",
 					},
 				},
+				{
+					APIGroups: []string{
+						"ml.llmos.ai",
+					},
+					Resources: []string{
+						"notebookprofiles",
+					},
+					Verbs: []string{
+						"get",
+						"list",
+						"watch",
+					},
+				},
 				{
 					APIGroups: []string{
 						"management.llmos.ai",
@@ -0,0 +1,128 @@
+package datacollection
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	// the cloud metadata endpoints which aren't covered by the link-local addresses
+	blockedIPs = []net.IP{
+		net.ParseIP("fd00:ec2::254"),
+	}
+	// the shared address space of the carrier-grade NAT, which isn't covered by the private addresses
+	_, cgnatNet, _ = net.ParseCIDR("100.64.0.0/10")
+
+	// allowedNets are the private networks allowed by the admin, e.g., the networks of the internal git servers
+	allowedNets []*net.IPNet
+)
+
+// SetAllowedCIDRs allows the sources to connect to the private addresses of the comma separated CIDRs
+func SetAllowedCIDRs(cidrs string) error {
+	nets := make([]*net.IPNet, 0)
+	for _, cidr := range strings.Split(cidrs, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr == "" {
+			continue
+		}
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid allowed CIDR %q: %w", cidr, err)
+		}
+		nets = append(nets, n)
+	}
+	allowedNets = nets
+	return nil
+}
+
+// ValidateURL validates the url is a http or https url whose host isn't a blocked address
+func ValidateURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q, must be a http or https url", s)
+	}
+	return ValidateHost(u.Hostname())
+}
+
+// ValidateHost rejects the localhost and the blocked literal addresses, the resolved addresses are checked again
+// when they're dialed. The private addresses are only checked when they're dialed by the sync job, since they may
+// be allowed by the admin.
+func ValidateHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("host %s is not allowed", host)
+	}
+	if ip := net.ParseIP(host); ip != nil && isBlockedIP(ip) && (!isPrivateIP(ip) || isMetadataIP(ip)) {
+		return fmt.Errorf("address %s is not allowed", host)
+	}
+	return nil
+}
+
+// isBlockedIP returns whether the ip is a loopback, link-local, unspecified, metadata or internal address, the
+// link-local addresses include the metadata endpoint 169.254.169.254 of the clouds, and the internal addresses
+// include the pod and service networks of the cluster unless they're allowed by the admin. It's replaced by the
+// tests serving the sources on the loopback address.
+var isBlockedIP = func(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() ||
+		ip.IsMulticast() {
+		return true
+	}
+	if isMetadataIP(ip) {
+		return true
+	}
+	if isPrivateIP(ip) {
+		for _, allowed := range allowedNets {
+			if allowed.Contains(ip) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func isMetadataIP(ip net.IP) bool {
+	for _, blocked := range blockedIPs {
+		if blocked.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPrivateIP returns whether the ip is a private address of RFC 1918 and RFC 4193, or a shared address of the
+// carrier-grade NAT
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsPrivate() || cgnatNet.Contains(ip)
+}
+
+// checkDialAddress rejects the connections to the blocked addresses after the host is resolved, so that a host
+// resolved to the blocked addresses or redirected to them can't be fetched
+func checkDialAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isBlockedIP(ip) {
+		return fmt.Errorf("address %s is not allowed", host)
+	}
+	return nil
+}
+
+// newTransport returns the http transport of the sources, which can't connect to the blocked addresses
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkDialAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// the addresses are checked when they're dialed, which isn't possible through a proxy
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
@@ -0,0 +1,258 @@
+package datacollection
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+)
+
+const (
+	gitUsernameKey = "username"
+	gitPasswordKey = "password"
+)
+
+// gitAllowProtocol are the protocols the repositories are fetched by, which is replaced by the tests fetching the
+// local repositories
+var gitAllowProtocol = "http:https"
+
+// syncGit clones the repository at the ref if its commit SHA is changed since the last sync, and uploads the
+// files whose md5 differs from the ETag of the files in the destination
+func syncGit(ctx context.Context, source *agentv1.GitSource, credential map[string][]byte, dst Destination,
+	dir string, last agentv1.DataSourceStatus) (*Result, error) {
+	repository, err := repositoryURL(source.Repository, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := resolveRef(ctx, repository, source.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if revision == last.Revision {
+		logrus.Debugf("skip repository %s which is not changed since revision %s", source.Repository, revision)
+		return &Result{Revision: revision}, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "datacollection-git-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	args := []string{"clone", "--depth", "1", "--single-branch"}
+	if source.Ref != "" {
+		args = append(args, "--branch", source.Ref)
+	}
+	if _, err := runGit(ctx, repository, append(args, repository, tmpDir)...); err != nil {
+		return nil, err
+	}
+	// the ref may be moved after it's resolved
+	out, err := runGit(ctx, repository, "-C", tmpDir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	revision = strings.TrimSpace(out)
+
+	existing, err := listDestination(ctx, dst, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Revision: revision}
+	synced := map[string]bool{}
+	err = filepath.WalkDir(tmpDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(tmpDir, p)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if !matchPaths(name, source.Paths) {
+			return nil
+		}
+
+		synced[name] = true
+		changed, err := uploadFile(ctx, p, existing[name].ETag, dst, path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if changed {
+			result.ChangedFiles++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync files of repository %s: %w", source.Repository, err)
+	}
+
+	deleted, err := deleteStale(ctx, dst, dir, existing, synced)
+	result.ChangedFiles += deleted
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// uploadFile uploads the local file to the dst if its md5 differs from the etag, and returns whether it's uploaded
+func uploadFile(ctx context.Context, src, etag string, dst Destination, name string) (bool, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	hash := md5.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return false, err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) == strings.Trim(etag, `"`) {
+		return false, nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+	if err := dst.UploadFromReader(ctx, f, name, size, mime.TypeByExtension(path.Ext(name))); err != nil {
+		return false, fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+
+	return true, nil
+}
+
+// resolveRef returns the commit SHA of the ref, the HEAD is resolved if the ref is not specified
+func resolveRef(ctx context.Context, repository, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	out, err := runGit(ctx, repository, "ls-remote", repository, ref)
+	if err != nil {
+		return "", err
+	}
+
+	// prefer the commit of the annotated tag to the tag object itself
+	revision := ""
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if strings.HasSuffix(fields[1], "^{}") {
+			return fields[0], nil
+		}
+		if revision == "" {
+			revision = fields[0]
+		}
+	}
+	if revision == "" {
+		return "", fmt.Errorf("ref %s is not found in repository %s", ref, redact(repository))
+	}
+
+	return revision, nil
+}
+
+func runGit(ctx context.Context, repository string, args ...string) (string, error) {
+	// the redirects aren't followed since the address of the redirected host isn't checked
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-c", "http.followRedirects=false"}, args...)...)
+	// never wait for the credentials from the terminal, and only fetch by http or https
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ALLOW_PROTOCOL="+gitAllowProtocol)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// the credentials in the repository url must not be leaked to the status
+		msg := strings.ReplaceAll(strings.TrimSpace(stderr.String()), repository, redact(repository))
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, msg)
+	}
+
+	return stdout.String(), nil
+}
+
+// checkRepositoryHost rejects the repository whose host is resolved to the blocked addresses, the repository is
+// fetched by the git command whose connections can't be checked
+func checkRepositoryHost(ctx context.Context, repository string) error {
+	if err := ValidateURL(repository); err != nil {
+		return err
+	}
+	u, _ := url.Parse(repository)
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve host of repository %s: %w", repository, err)
+	}
+	for _, addr := range addrs {
+		if isBlockedIP(addr.IP) {
+			return fmt.Errorf("address %s of repository %s is not allowed", addr.IP, repository)
+		}
+	}
+	return nil
+}
+
+// repositoryURL returns the repository url with the credentials as the userinfo
+func repositoryURL(repository string, credential map[string][]byte) (string, error) {
+	if len(credential) == 0 {
+		return repository, nil
+	}
+
+	u, err := url.Parse(repository)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository %s: %w", repository, err)
+	}
+	username := string(credential[gitUsernameKey])
+	if username == "" {
+		// the username is ignored by most git servers when the password is an access token
+		username = "git"
+	}
+	u.User = url.UserPassword(username, string(credential[gitPasswordKey]))
+
+	return u.String(), nil
+}
+
+func redact(repository string) string {
+	u, err := url.Parse(repository)
+	if err != nil || u.User == nil {
+		return repository
+	}
+	return u.Redacted()
+}
+
+// matchPaths returns whether the file is one of the paths or in the directories of the paths
+func matchPaths(name string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		p = strings.Trim(path.Clean("/"+p), "/")
+		if p == "" || name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+	return false
+}
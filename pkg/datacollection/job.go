@@ -0,0 +1,141 @@
+package datacollection
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+)
+
+// SyncTimeout is the timeout of syncing a source
+const SyncTimeout = 30 * time.Minute
+
+// Client is the client to update the status of the data collections
+type Client interface {
+	Get(namespace, name string, opts metav1.GetOptions) (*agentv1.DataCollection, error)
+	UpdateStatus(*agentv1.DataCollection) (*agentv1.DataCollection, error)
+}
+
+// SyncSources syncs the sources of the data collection into the dst and records the result of each source in
+// the status. The credentials of each source are read from the files of the directory named after the source in
+// the credentialsDir. The failures of the sources are recorded in their status instead of being returned.
+func SyncSources(ctx context.Context, client Client, dc *agentv1.DataCollection, names []string,
+	credentialsDir string, dst Destination) error {
+	for _, name := range names {
+		source, ok := getSource(dc, name)
+		if !ok {
+			logrus.Infof("skip source %s which is removed from data collection %s/%s", name, dc.Namespace, dc.Name)
+			continue
+		}
+
+		credential, err := readCredential(filepath.Join(credentialsDir, name))
+		var result *Result
+		if err == nil {
+			result, err = syncSource(ctx, dc, source, credential, dst)
+		}
+		if err != nil {
+			logrus.Errorf("failed to sync source %s of data collection %s/%s: %v", name, dc.Namespace, dc.Name, err)
+		} else {
+			logrus.Infof("synced source %s of data collection %s/%s, %d files changed", name, dc.Namespace,
+				dc.Name, result.ChangedFiles)
+		}
+
+		if err = reportResult(client, dc.Namespace, dc.Name, name, result, err); err != nil {
+			return fmt.Errorf("failed to record the result of source %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func syncSource(ctx context.Context, dc *agentv1.DataCollection, source agentv1.DataSource,
+	credential map[string][]byte, dst Destination) (*Result, error) {
+	// sync all files after the spec is changed, e.g., the paths of the git source are changed
+	last := agentv1.DataSourceStatus{Name: source.Name}
+	for _, status := range dc.Status.Sources {
+		if status.Name == source.Name && status.ObservedGeneration == dc.Generation {
+			last = status
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, SyncTimeout)
+	defer cancel()
+	return Sync(ctx, source, credential, dst, path.Join(dc.Status.RootPath, source.Name), last)
+}
+
+// reportResult records the result of the source in its status, the revision and ETags of the partially synced
+// source are kept to skip the synced files by the next sync
+func reportResult(client Client, namespace, name, source string, result *Result, syncErr error) error {
+	now := metav1.Now()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		dc, err := client.Get(namespace, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		dcCopy := dc.DeepCopy()
+		for i := range dcCopy.Status.Sources {
+			status := &dcCopy.Status.Sources[i]
+			if status.Name != source {
+				continue
+			}
+			if result != nil {
+				status.Revision = result.Revision
+				status.ETags = result.ETags
+				status.ChangedFiles = result.ChangedFiles
+			}
+			if syncErr != nil {
+				status.Message = syncErr.Error()
+			} else {
+				status.Message = ""
+				status.LastSyncTime = &now
+			}
+		}
+		if reflect.DeepEqual(dc.Status, dcCopy.Status) {
+			return nil
+		}
+		_, err = client.UpdateStatus(dcCopy)
+		return err
+	})
+}
+
+// readCredential reads the keys of the credential secret mounted in the dir, nil is returned if the source has
+// no credential secret
+func readCredential(dir string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read credential: %w", err)
+	}
+
+	credential := make(map[string][]byte, len(entries))
+	for _, e := range entries {
+		// the keys of the mounted secret are the symbolic links to the files of the hidden directory
+		if e.Name()[0] == '.' || e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credential key %s: %w", e.Name(), err)
+		}
+		credential[e.Name()] = data
+	}
+	return credential, nil
+}
+
+func getSource(dc *agentv1.DataCollection, name string) (agentv1.DataSource, bool) {
+	for _, source := range dc.Spec.Sources {
+		if source.Name == name {
+			return source, true
+		}
+	}
+	return agentv1.DataSource{}, false
+}
@@ -0,0 +1,152 @@
+package datacollection
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+)
+
+const (
+	s3AccessKeyIDKey     = "accessKeyID"
+	s3AccessKeySecretKey = "accessKeySecret"
+)
+
+type object struct {
+	// Key is the path of the object relative to the prefix
+	Key         string
+	Size        int64
+	ETag        string
+	ContentType string
+}
+
+// objectStore is the prefix of a bucket to sync the objects from
+type objectStore interface {
+	List(ctx context.Context) ([]object, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+type s3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3Store(ctx context.Context, source *agentv1.S3Source, credential map[string][]byte) (*s3Store, error) {
+	creds := credentials.NewStatic("", "", "", credentials.SignatureAnonymous)
+	if len(credential) > 0 {
+		creds = credentials.NewStaticV4(string(credential[s3AccessKeyIDKey]),
+			string(credential[s3AccessKeySecretKey]), "")
+	}
+	host, _, err := net.SplitHostPort(source.Endpoint)
+	if err != nil {
+		host = source.Endpoint
+	}
+	if err := ValidateHost(host); err != nil {
+		return nil, err
+	}
+	client, err := minio.New(source.Endpoint, &minio.Options{Creds: creds, Secure: source.UseSSL,
+		Transport: newTransport()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio client to access %s: %w", source.Endpoint, err)
+	}
+	found, err := client.BucketExists(ctx, source.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if bucket %s exists: %w", source.Bucket, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("bucket %s does not exist", source.Bucket)
+	}
+
+	prefix := strings.TrimPrefix(source.Prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &s3Store{client: client, bucket: source.Bucket, prefix: prefix}, nil
+}
+
+func (s *s3Store) List(ctx context.Context) ([]object, error) {
+	var objects []object
+	for info := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    s.prefix,
+		Recursive: true,
+	}) {
+		if info.Err != nil {
+			return nil, fmt.Errorf("failed to list objects in %s/%s: %w", s.bucket, s.prefix, info.Err)
+		}
+		// skip the directory markers and the keys escaping the prefix
+		key := strings.TrimPrefix(info.Key, s.prefix)
+		if strings.HasSuffix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") {
+			continue
+		}
+		objects = append(objects, object{
+			Key:         key,
+			Size:        info.Size,
+			ETag:        info.ETag,
+			ContentType: info.ContentType,
+		})
+	}
+
+	return objects, nil
+}
+
+func (s *s3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.client.GetObject(ctx, s.bucket, s.prefix+key, minio.GetObjectOptions{})
+}
+
+// syncObjects copies the objects whose ETag is changed since the last sync to the dst
+func syncObjects(ctx context.Context, store objectStore, dst Destination, dir string,
+	last agentv1.DataSourceStatus) (*Result, error) {
+	objects, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := listDestination(ctx, dst, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{ETags: make(map[string]string, len(objects))}
+	synced := make(map[string]bool, len(objects))
+	for _, o := range objects {
+		synced[o.Key] = true
+		result.ETags[o.Key] = o.ETag
+		if _, ok := existing[o.Key]; ok && last.ETags[o.Key] == o.ETag {
+			continue
+		}
+
+		if err := copyObject(ctx, store, o, dst, path.Join(dir, o.Key)); err != nil {
+			return nil, err
+		}
+		result.ChangedFiles++
+	}
+
+	deleted, err := deleteStale(ctx, dst, dir, existing, synced)
+	result.ChangedFiles += deleted
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func copyObject(ctx context.Context, store objectStore, o object, dst Destination, name string) error {
+	r, err := store.Open(ctx, o.Key)
+	if err != nil {
+		return fmt.Errorf("failed to get object %s: %w", o.Key, err)
+	}
+	defer r.Close()
+
+	if err := dst.UploadFromReader(ctx, r, name, o.Size, o.ContentType); err != nil {
+		return fmt.Errorf("failed to copy object %s: %w", o.Key, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package datacollection
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
+)
+
+// Destination is the registry directory the files of a source are written to
+type Destination interface {
+	List(ctx context.Context, prefix string, recursive, skipItself bool) ([]backend.FileInfo, error)
+	UploadFromReader(ctx context.Context, reader io.Reader, dst string, size int64, contentType string) error
+	Delete(ctx context.Context, objectName string) error
+}
+
+// Result is the result of syncing a source
+type Result struct {
+	// Revision is the commit SHA of the synced git repository
+	Revision string
+	// ETags are the ETags of the synced urls or objects
+	ETags map[string]string
+	// ChangedFiles is the number of files written or deleted
+	ChangedFiles int32
+}
+
+// Sync syncs the files of the source into the dir of the destination. The revision and ETags of the last sync
+// are used to skip the unchanged files, pass an empty status to sync all files. The result is returned with the
+// error if only part of the files fail to sync.
+func Sync(ctx context.Context, source agentv1.DataSource, credential map[string][]byte, dst Destination, dir string,
+	last agentv1.DataSourceStatus) (*Result, error) {
+	switch {
+	case source.URL != nil:
+		return syncURL(ctx, source.URL, dst, dir, last)
+	case source.Git != nil:
+		if err := checkRepositoryHost(ctx, source.Git.Repository); err != nil {
+			return nil, err
+		}
+		return syncGit(ctx, source.Git, credential, dst, dir, last)
+	case source.S3 != nil:
+		store, err := newS3Store(ctx, source.S3, credential)
+		if err != nil {
+			return nil, err
+		}
+		return syncObjects(ctx, store, dst, dir, last)
+	default:
+		return nil, fmt.Errorf("source %s has no url, git or s3 specified", source.Name)
+	}
+}
+
+// ListFiles lists the files under the root path of a data collection
+func ListFiles(ctx context.Context, b backend.Lister, rootPath string) ([]agentv1.FileInfo, error) {
+	files, err := b.List(ctx, rootPath, true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfos := make([]agentv1.FileInfo, 0, len(files))
+	for _, f := range files {
+		fileInfos = append(fileInfos, agentv1.FileInfo{
+			UID:          f.UID,
+			Name:         f.Name,
+			Path:         f.Path,
+			Size:         f.Size,
+			LastModified: metav1.NewTime(f.LastModified),
+			ContentType:  f.ContentType,
+			ETag:         f.ETag,
+		})
+	}
+
+	return fileInfos, nil
+}
+
+// listDestination returns the files in the dir keyed by the path relative to the dir
+func listDestination(ctx context.Context, dst Destination, dir string) (map[string]backend.FileInfo, error) {
+	files, err := dst.List(ctx, dir, true, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", dir, err)
+	}
+
+	existing := make(map[string]backend.FileInfo, len(files))
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	for _, f := range files {
+		if f.IsDir || !strings.HasPrefix(f.Path, prefix) {
+			continue
+		}
+		existing[strings.TrimPrefix(f.Path, prefix)] = f
+	}
+
+	return existing, nil
+}
+
+// deleteStale deletes the files in the dir which are not synced from the source any more
+func deleteStale(ctx context.Context, dst Destination, dir string, existing map[string]backend.FileInfo,
+	synced map[string]bool) (int32, error) {
+	var deleted int32
+	for name := range existing {
+		if synced[name] {
+			continue
+		}
+		if err := dst.Delete(ctx, path.Join(dir, name)); err != nil {
+			return deleted, fmt.Errorf("failed to delete stale file %s: %w", name, err)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,260 @@
+package datacollection
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+)
+
+const (
+	defaultMaxPages = 500
+	// maxSitemapDepth is the maximum depth of the nested sitemap indexes
+	maxSitemapDepth = 3
+
+	// the validators of the responses without ETag
+	lastModifiedPrefix = "last-modified:"
+	sha256Prefix       = "sha256:"
+)
+
+var httpClient = &http.Client{Timeout: 5 * time.Minute, Transport: newTransport()}
+
+type sitemap struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// syncURL downloads the urls and the pages of the sitemap. The requests are conditional on the ETag or
+// Last-Modified of the last sync, the pages without both are compared by their content hash. The pages which
+// fail to download keep their files of the last sync, and the result is returned with the error.
+func syncURL(ctx context.Context, source *agentv1.URLSource, dst Destination, dir string,
+	last agentv1.DataSourceStatus) (*Result, error) {
+	urls := append([]string{}, source.URLs...)
+	if source.Sitemap != "" {
+		maxPages := int(source.MaxPages)
+		if maxPages <= 0 {
+			maxPages = defaultMaxPages
+		}
+		pages, err := readSitemap(ctx, source.Sitemap, maxPages, map[string]bool{}, 0)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, pages...)
+	}
+
+	existing, err := listDestination(ctx, dst, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{ETags: map[string]string{}}
+	synced := map[string]bool{}
+	var errs []error
+	for _, u := range urls {
+		name, err := urlFileName(u)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if synced[name] {
+			continue
+		}
+		_, exists := existing[name]
+		validator, changed, err := fetchURL(ctx, u, last.ETags[u], exists, dst, path.Join(dir, name))
+		switch {
+		case errors.Is(err, errNotFound):
+			logrus.Debugf("skip url %s which is not found", u)
+			continue
+		case err != nil:
+			errs = append(errs, err)
+			// keep the file of the last sync
+			if exists {
+				synced[name] = true
+				if v, ok := last.ETags[u]; ok {
+					result.ETags[u] = v
+				}
+			}
+			continue
+		}
+		synced[name] = true
+		result.ETags[u] = validator
+		if changed {
+			result.ChangedFiles++
+		}
+	}
+
+	deleted, err := deleteStale(ctx, dst, dir, existing, synced)
+	result.ChangedFiles += deleted
+	if err != nil {
+		return result, err
+	}
+	if len(errs) > 0 {
+		return result, fmt.Errorf("failed to download %d of %d urls: %w", len(errs), len(urls), errs[0])
+	}
+
+	return result, nil
+}
+
+var errNotFound = errors.New("not found")
+
+// fetchURL downloads the url to the dst if it's changed since the last sync, and returns the validator of the
+// response and whether the file is changed
+func fetchURL(ctx context.Context, u, last string, exists bool, dst Destination,
+	name string) (string, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid url %s: %w", u, err)
+	}
+	// request the whole file if it's removed from the destination
+	if exists {
+		switch {
+		case strings.HasPrefix(last, lastModifiedPrefix):
+			req.Header.Set("If-Modified-Since", strings.TrimPrefix(last, lastModifiedPrefix))
+		case last != "" && !strings.HasPrefix(last, sha256Prefix):
+			req.Header.Set("If-None-Match", last)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && exists:
+		return last, false, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return "", false, errNotFound
+	case resp.StatusCode != http.StatusOK:
+		return "", false, fmt.Errorf("failed to get %s: unexpected status %s", u, resp.Status)
+	}
+
+	validator := ""
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		validator = etag
+	} else if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+		validator = lastModifiedPrefix + lastModified
+	}
+	// some servers ignore the conditional headers
+	if validator != "" && validator == last && exists {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return last, false, nil
+	}
+
+	hash := sha256.New()
+	if err := dst.UploadFromReader(ctx, io.TeeReader(resp.Body, hash), name, resp.ContentLength,
+		resp.Header.Get("Content-Type")); err != nil {
+		return "", false, fmt.Errorf("failed to upload %s to %s: %w", u, name, err)
+	}
+	if validator == "" {
+		validator = sha256Prefix + hex.EncodeToString(hash.Sum(nil))
+	}
+
+	return validator, validator != last || !exists, nil
+}
+
+// readSitemap returns at most maxPages pages of the sitemap, the nested sitemaps of a sitemap index are read
+// recursively
+func readSitemap(ctx context.Context, u string, maxPages int, visited map[string]bool,
+	depth int) ([]string, error) {
+	if visited[u] || depth > maxSitemapDepth {
+		return nil, nil
+	}
+	visited[u] = true
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sitemap url %s: %w", u, err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sitemap %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get sitemap %s: unexpected status %s", u, resp.Status)
+	}
+
+	var sm sitemap
+	if err := xml.NewDecoder(resp.Body).Decode(&sm); err != nil {
+		return nil, fmt.Errorf("failed to parse sitemap %s: %w", u, err)
+	}
+
+	pages := make([]string, 0, len(sm.URLs))
+	for _, page := range sm.URLs {
+		if len(pages) >= maxPages {
+			return pages, nil
+		}
+		loc := strings.TrimSpace(page.Loc)
+		if loc == "" {
+			continue
+		}
+		// the pages of the sitemap are validated as the urls of the spec
+		if err := ValidateURL(loc); err != nil {
+			logrus.Warnf("skip page of sitemap %s: %v", u, err)
+			continue
+		}
+		pages = append(pages, loc)
+	}
+	for _, s := range sm.Sitemaps {
+		if len(pages) >= maxPages {
+			break
+		}
+		loc := strings.TrimSpace(s.Loc)
+		if err := ValidateURL(loc); err != nil {
+			logrus.Warnf("skip nested sitemap of sitemap %s: %v", u, err)
+			continue
+		}
+		nested, err := readSitemap(ctx, loc, maxPages-len(pages), visited, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, nested...)
+	}
+
+	return pages, nil
+}
+
+// urlFileName returns the file name of the url in the form of host/path, the index.html is appended to the
+// directories and the .html extension is appended to the pages without extension
+func urlFileName(u string) (string, error) {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("invalid url %s", u)
+	}
+
+	p := parsed.Path
+	if p == "" || strings.HasSuffix(p, "/") {
+		p += "index.html"
+	}
+	p = path.Clean("/" + p)
+	ext := path.Ext(p)
+	if ext == "" {
+		ext = ".html"
+		p += ext
+	}
+	// distinguish the pages of the same path with different queries
+	if parsed.RawQuery != "" {
+		sum := sha256.Sum256([]byte(parsed.RawQuery))
+		p = strings.TrimSuffix(p, ext) + "_" + hex.EncodeToString(sum[:4]) + ext
+	}
+
+	return path.Join(parsed.Host, p), nil
+}
@@ -0,0 +1,676 @@
+// Package dataset inspects the files of the dataset versions to infer the schema, the splits and the row counts,
+// and reads the first rows of the splits for preview.
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatTSV     = "tsv"
+	FormatJSON    = "json"
+	FormatParquet = "parquet"
+	FormatArrow   = "arrow"
+	FormatMixed   = "mixed"
+
+	SplitTrain      = "train"
+	SplitValidation = "validation"
+	SplitTest       = "test"
+
+	DefaultPreviewRows = 10
+	MaxPreviewRows     = 100
+
+	featureTypeString = "string"
+	featureTypeInt    = "int64"
+	featureTypeFloat  = "float64"
+	featureTypeBool   = "bool"
+	featureTypeList   = "list"
+	featureTypeStruct = "struct"
+
+	// hfDatasetInfoFile is the metadata file of the HF datasets, which contains the features and the splits
+	hfDatasetInfoFile = "dataset_info.json"
+	// sampleRows is the number of rows to infer the feature types
+	sampleRows = 100
+)
+
+var (
+	// splitKeywords are referred to the split patterns of the HF datasets
+	// https://huggingface.co/docs/datasets/repository_structure
+	splitKeywords = map[string]string{
+		"train":      SplitTrain,
+		"training":   SplitTrain,
+		"validation": SplitValidation,
+		"valid":      SplitValidation,
+		"val":        SplitValidation,
+		"dev":        SplitValidation,
+		"test":       SplitTest,
+		"testing":    SplitTest,
+		"eval":       SplitTest,
+		"evaluation": SplitTest,
+	}
+	splitOrder = map[string]int{SplitTrain: 0, SplitValidation: 1, SplitTest: 2}
+
+	tokenSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+
+	errPreviewNotSupported = errors.New("preview is not supported")
+)
+
+type dataFile struct {
+	backend.FileInfo
+	relPath string
+	format  string
+	split   string
+}
+
+type fileStats struct {
+	features []mlv1.DatasetFeature
+	numRows  int64
+}
+
+// Fingerprint returns the fingerprint of the files, which changes once any file is added, removed or modified
+func Fingerprint(files []backend.FileInfo) string {
+	keys := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.IsDir {
+			continue
+		}
+		keys = append(keys, fmt.Sprintf("%s:%d:%s", f.Path, f.Size, f.ETag))
+	}
+	sort.Strings(keys)
+	hash := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// Inspect infers the schema, the splits and the row counts from the files under the root path
+func Inspect(ctx context.Context, b backend.Backend, rootPath string, files []backend.FileInfo) (
+	*mlv1.DatasetSchema, error) {
+	now := metav1.Now()
+	schema := &mlv1.DatasetSchema{
+		Fingerprint: Fingerprint(files),
+		InspectTime: &now,
+	}
+
+	dataFiles, infoFiles := classifyFiles(rootPath, files)
+	formats := make(map[string]bool)
+	splits := make(map[string]*mlv1.DatasetSplit)
+	features := newFeatureSet()
+	for _, f := range dataFiles {
+		formats[f.format] = true
+		split, ok := splits[f.split]
+		if !ok {
+			split = &mlv1.DatasetSplit{Name: f.split}
+			splits[f.split] = split
+		}
+		split.Files = append(split.Files, f.relPath)
+
+		stats, err := inspectFile(ctx, b, f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect file %s: %w", f.relPath, err)
+		}
+		split.NumRows += stats.numRows
+		features.add(stats.features)
+	}
+
+	// the features and the row counts of the HF dataset info are preferred, which are more accurate
+	for _, f := range infoFiles {
+		info, err := readHFDatasetInfo(ctx, b, f.Path)
+		if err != nil {
+			logrus.Warnf("failed to read HF dataset info %s: %v", f.Path, err)
+			continue
+		}
+		if len(info.features) > 0 {
+			features = newFeatureSet()
+			features.add(info.features)
+		}
+		for name, numRows := range info.splits {
+			if split, ok := splits[name]; ok && split.NumRows == 0 {
+				split.NumRows = numRows
+			}
+		}
+	}
+
+	for format := range formats {
+		if schema.Format == "" {
+			schema.Format = format
+		} else {
+			schema.Format = FormatMixed
+		}
+	}
+	schema.Features = features.list()
+	for i := range schema.Features {
+		schema.Features[i].Type = defaultType(schema.Features[i].Type)
+	}
+	for _, split := range splits {
+		schema.Splits = append(schema.Splits, *split)
+		schema.NumRows += split.NumRows
+	}
+	sortSplits(schema.Splits)
+
+	return schema, nil
+}
+
+// Preview returns the first limit rows of the split
+func Preview(ctx context.Context, b backend.Backend, rootPath string, files []backend.FileInfo, split string,
+	limit int) ([]map[string]interface{}, error) {
+	if limit <= 0 {
+		limit = DefaultPreviewRows
+	}
+	limit = min(limit, MaxPreviewRows)
+
+	dataFiles, _ := classifyFiles(rootPath, files)
+	rows := make([]map[string]interface{}, 0, limit)
+	found := false
+	for _, f := range dataFiles {
+		if f.split != split {
+			continue
+		}
+		found = true
+		fileRows, err := readRows(ctx, b, f, limit-len(rows))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", f.relPath, err)
+		}
+		if rows = append(rows, fileRows...); len(rows) >= limit {
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("split %s not found", split)
+	}
+	return rows, nil
+}
+
+// classifyFiles returns the data files with their formats and splits, and the HF dataset info files
+func classifyFiles(rootPath string, files []backend.FileInfo) ([]dataFile, []backend.FileInfo) {
+	dataFiles := make([]dataFile, 0, len(files))
+	infoFiles := make([]backend.FileInfo, 0)
+	for _, f := range files {
+		if f.IsDir {
+			continue
+		}
+		relPath := strings.TrimPrefix(strings.TrimPrefix(f.Path, rootPath), "/")
+		if path.Base(relPath) == hfDatasetInfoFile {
+			infoFiles = append(infoFiles, f)
+			continue
+		}
+		format := getFormat(relPath)
+		if format == "" {
+			continue
+		}
+		dataFiles = append(dataFiles, dataFile{FileInfo: f, relPath: relPath, format: format, split: getSplit(relPath)})
+	}
+	sort.Slice(dataFiles, func(i, j int) bool { return dataFiles[i].relPath < dataFiles[j].relPath })
+	return dataFiles, infoFiles
+}
+
+func getFormat(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		return FormatCSV
+	case ".tsv":
+		return FormatTSV
+	case ".json", ".jsonl":
+		return FormatJSON
+	case ".parquet":
+		return FormatParquet
+	case ".arrow":
+		return FormatArrow
+	default:
+		return ""
+	}
+}
+
+// getSplit infers the split from the file name first and then the directories from the innermost one,
+// the files without split keywords are regarded as the train split like the HF datasets.
+func getSplit(relPath string) string {
+	dir, file := path.Split(relPath)
+	candidates := []string{strings.TrimSuffix(file, path.Ext(file))}
+	dirs := strings.Split(strings.Trim(dir, "/"), "/")
+	for i := len(dirs) - 1; i >= 0; i-- {
+		candidates = append(candidates, dirs[i])
+	}
+
+	for _, candidate := range candidates {
+		for _, token := range tokenSeparator.Split(strings.ToLower(candidate), -1) {
+			if split, ok := splitKeywords[token]; ok {
+				return split
+			}
+		}
+	}
+	return SplitTrain
+}
+
+func sortSplits(splits []mlv1.DatasetSplit) {
+	sort.Slice(splits, func(i, j int) bool {
+		oi, iok := splitOrder[splits[i].Name]
+		oj, jok := splitOrder[splits[j].Name]
+		if iok && jok {
+			return oi < oj
+		}
+		if iok != jok {
+			return iok
+		}
+		return splits[i].Name < splits[j].Name
+	})
+}
+
+func inspectFile(ctx context.Context, b backend.Backend, f dataFile) (*fileStats, error) {
+	switch f.format {
+	case FormatParquet:
+		pf, err := openParquetFile(newBackendReaderAt(ctx, b, f.Path), f.Size)
+		if err != nil {
+			return nil, err
+		}
+		return &fileStats{features: parquetFeatures(pf.Metadata().Schema), numRows: pf.NumRows()}, nil
+	case FormatArrow:
+		// the rows of the arrow files are counted by the HF dataset info
+		return &fileStats{}, nil
+	}
+
+	r, closeFn := openFile(ctx, b, f.Path)
+	defer closeFn()
+	switch f.format {
+	case FormatCSV:
+		return inspectCSV(r, ',')
+	case FormatTSV:
+		return inspectCSV(r, '\t')
+	default:
+		return inspectJSON(r)
+	}
+}
+
+func readRows(ctx context.Context, b backend.Backend, f dataFile, limit int) ([]map[string]interface{}, error) {
+	switch f.format {
+	case FormatParquet:
+		return readParquetRows(newBackendReaderAt(ctx, b, f.Path), f.Size, limit)
+	case FormatArrow:
+		return nil, fmt.Errorf("%w for %s files", errPreviewNotSupported, f.format)
+	}
+
+	r, closeFn := openFile(ctx, b, f.Path)
+	defer closeFn()
+	switch f.format {
+	case FormatCSV:
+		return readCSVRows(r, ',', limit)
+	case FormatTSV:
+		return readCSVRows(r, '\t', limit)
+	default:
+		return readJSONRows(r, limit)
+	}
+}
+
+// openFile streams the file from the backend, the download is canceled once the returned function is called
+func openFile(ctx context.Context, b backend.Backend, src string) (io.Reader, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(b.Download(ctx, src, pw))
+	}()
+	return pr, func() {
+		cancel()
+		_ = pr.Close()
+	}
+}
+
+func inspectCSV(r io.Reader, comma rune) (*fileStats, error) {
+	reader := newCSVReader(r, comma)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &fileStats{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	header = append([]string(nil), header...)
+
+	types := make([]string, len(header))
+	stats := &fileStats{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read row %d: %w", stats.numRows+1, err)
+		}
+		if stats.numRows < sampleRows {
+			for i := range header {
+				if i < len(record) && record[i] != "" {
+					types[i] = mergeType(types[i], inferCSVType(record[i]))
+				}
+			}
+		}
+		stats.numRows++
+	}
+
+	for i, name := range header {
+		stats.features = append(stats.features, mlv1.DatasetFeature{Name: name, Type: types[i]})
+	}
+	return stats, nil
+}
+
+func readCSVRows(r io.Reader, comma rune, limit int) ([]map[string]interface{}, error) {
+	reader := newCSVReader(r, comma)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	header = append([]string(nil), header...)
+
+	rows := make([]map[string]interface{}, 0, limit)
+	for len(rows) < limit {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read row %d: %w", len(rows)+1, err)
+		}
+		row := make(map[string]interface{}, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = record[i]
+			} else {
+				row[name] = nil
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func newCSVReader(r io.Reader, comma rune) *csv.Reader {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+	return reader
+}
+
+func inferCSVType(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return featureTypeInt
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return featureTypeFloat
+	}
+	if lower := strings.ToLower(value); lower == "true" || lower == "false" {
+		return featureTypeBool
+	}
+	return featureTypeString
+}
+
+// inspectJSON inspects the JSON lines or the JSON array of objects
+func inspectJSON(r io.Reader) (*fileStats, error) {
+	stats := &fileStats{}
+	features := newFeatureSet()
+	err := decodeJSONRows(r, func(row map[string]interface{}) bool {
+		if stats.numRows < sampleRows {
+			features.addRow(row)
+		}
+		stats.numRows++
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats.features = features.list()
+	return stats, nil
+}
+
+func readJSONRows(r io.Reader, limit int) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0, limit)
+	err := decodeJSONRows(r, func(row map[string]interface{}) bool {
+		rows = append(rows, row)
+		return len(rows) < limit
+	})
+	return rows, err
+}
+
+// decodeJSONRows calls fn for each row until it returns false
+func decodeJSONRows(r io.Reader, fn func(row map[string]interface{}) bool) error {
+	br := bufio.NewReader(r)
+	isArray := false
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !isSpace(c) {
+			isArray = c == '['
+			if !isArray {
+				_ = br.UnreadByte()
+			}
+			break
+		}
+	}
+
+	decoder := json.NewDecoder(br)
+	decoder.UseNumber()
+	for i := 1; ; i++ {
+		if isArray && !decoder.More() {
+			return nil
+		}
+		row := make(map[string]interface{})
+		if err := decoder.Decode(&row); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to decode row %d: %w", i, err)
+		}
+		if !fn(row) {
+			return nil
+		}
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func inferJSONType(value interface{}) string {
+	switch v := value.(type) {
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return featureTypeInt
+		}
+		return featureTypeFloat
+	case string:
+		return featureTypeString
+	case bool:
+		return featureTypeBool
+	case []interface{}:
+		return featureTypeList
+	case map[string]interface{}:
+		return featureTypeStruct
+	default:
+		return ""
+	}
+}
+
+type hfDatasetInfo struct {
+	features []mlv1.DatasetFeature
+	splits   map[string]int64
+}
+
+// readHFDatasetInfo reads the features and the splits of the dataset_info.json of the HF datasets
+func readHFDatasetInfo(ctx context.Context, b backend.Backend, src string) (*hfDatasetInfo, error) {
+	buf := &bytes.Buffer{}
+	if err := b.Download(ctx, src, buf); err != nil {
+		return nil, err
+	}
+	raw := struct {
+		Features json.RawMessage `json:"features"`
+		Splits   map[string]struct {
+			NumExamples int64 `json:"num_examples"`
+		} `json:"splits"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		return nil, err
+	}
+
+	info := &hfDatasetInfo{splits: make(map[string]int64, len(raw.Splits))}
+	for name, split := range raw.Splits {
+		info.splits[name] = split.NumExamples
+	}
+
+	if len(raw.Features) > 0 {
+		// the features are decoded in order since the order of the columns matters
+		decoder := json.NewDecoder(bytes.NewReader(raw.Features))
+		if t, err := decoder.Token(); err != nil || t != json.Delim('{') {
+			return info, nil
+		}
+		for decoder.More() {
+			t, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			var feature interface{}
+			if err = decoder.Decode(&feature); err != nil {
+				return nil, err
+			}
+			info.features = append(info.features, mlv1.DatasetFeature{Name: t.(string), Type: hfFeatureType(feature)})
+		}
+	}
+	return info, nil
+}
+
+// hfFeatureType returns the type of the HF dataset feature, e.g., {"dtype": "string", "_type": "Value"}
+func hfFeatureType(feature interface{}) string {
+	switch v := feature.(type) {
+	case []interface{}:
+		return featureTypeList
+	case map[string]interface{}:
+		switch v["_type"] {
+		case "Value":
+			if dtype, ok := v["dtype"].(string); ok {
+				return dtype
+			}
+			return featureTypeString
+		case "ClassLabel":
+			return "class_label"
+		case "Sequence", "List", "LargeList":
+			return featureTypeList
+		case nil:
+			return featureTypeStruct
+		default:
+			return strings.ToLower(fmt.Sprint(v["_type"]))
+		}
+	default:
+		return featureTypeString
+	}
+}
+
+// featureSet keeps the features in the order of their first appearance, the types of the features without
+// any non-null values are left empty until all the files are merged
+type featureSet struct {
+	names []string
+	types map[string]string
+}
+
+func newFeatureSet() *featureSet {
+	return &featureSet{types: make(map[string]string)}
+}
+
+func (s *featureSet) add(features []mlv1.DatasetFeature) {
+	for _, f := range features {
+		s.set(f.Name, f.Type)
+	}
+}
+
+func (s *featureSet) addRow(row map[string]interface{}) {
+	names := make([]string, 0, len(row))
+	for name := range row {
+		names = append(names, name)
+	}
+	// the order of the JSON object keys is lost, sort them to keep the result stable
+	sort.Strings(names)
+	for _, name := range names {
+		s.set(name, inferJSONType(row[name]))
+	}
+}
+
+func (s *featureSet) set(name, t string) {
+	existing, ok := s.types[name]
+	if !ok {
+		s.names = append(s.names, name)
+	}
+	s.types[name] = mergeType(existing, t)
+}
+
+func (s *featureSet) list() []mlv1.DatasetFeature {
+	features := make([]mlv1.DatasetFeature, 0, len(s.names))
+	for _, name := range s.names {
+		features = append(features, mlv1.DatasetFeature{Name: name, Type: s.types[name]})
+	}
+	return features
+}
+
+// mergeType merges the types of the same feature, the integers are widened to floats and the other
+// conflicting types fall back to string
+func mergeType(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case b == "":
+		return a
+	case (a == featureTypeInt && b == featureTypeFloat) || (a == featureTypeFloat && b == featureTypeInt):
+		return featureTypeFloat
+	default:
+		return featureTypeString
+	}
+}
+
+func defaultType(t string) string {
+	if t == "" {
+		return featureTypeString
+	}
+	return t
+}
+
+// backendReaderAt reads the ranges of the file from the backend
+type backendReaderAt struct {
+	ctx context.Context
+	b   backend.Backend
+	src string
+}
+
+func newBackendReaderAt(ctx context.Context, b backend.Backend, src string) io.ReaderAt {
+	return &backendReaderAt{ctx: ctx, b: b, src: src}
+}
+
+func (r *backendReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(p)))
+	if err := r.b.DownloadRange(r.ctx, r.src, off, int64(len(p)), buf); err != nil {
+		return 0, err
+	}
+	n := copy(p, buf.Bytes())
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
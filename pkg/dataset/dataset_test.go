@@ -0,0 +1,232 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
+)
+
+// fakeBackend serves the files from memory
+type fakeBackend struct {
+	backend.Backend
+	files map[string][]byte
+}
+
+func (f *fakeBackend) Download(_ context.Context, src string, rw io.Writer) error {
+	data, ok := f.files[src]
+	if !ok {
+		return fmt.Errorf("file %s not found", src)
+	}
+	_, err := rw.Write(data)
+	return err
+}
+
+func (f *fakeBackend) DownloadRange(_ context.Context, src string, offset, length int64, rw io.Writer) error {
+	data, ok := f.files[src]
+	if !ok {
+		return fmt.Errorf("file %s not found", src)
+	}
+	if offset >= int64(len(data)) {
+		return nil
+	}
+	_, err := rw.Write(data[offset:min(offset+length, int64(len(data)))])
+	return err
+}
+
+func (f *fakeBackend) fileInfos() []backend.FileInfo {
+	files := make([]backend.FileInfo, 0, len(f.files))
+	for p, data := range f.files {
+		files = append(files, backend.FileInfo{Path: p, Size: int64(len(data)), ETag: fmt.Sprint(len(data))})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+func TestGetSplit(t *testing.T) {
+	var testCases = []struct {
+		path     string
+		expected string
+	}{
+		{path: "train.csv", expected: SplitTrain},
+		{path: "data/train-00000-of-00002.parquet", expected: SplitTrain},
+		{path: "data/validation-00000-of-00001.parquet", expected: SplitValidation},
+		{path: "dev.jsonl", expected: SplitValidation},
+		{path: "test/data.json", expected: SplitTest},
+		{path: "eval/part_1.csv", expected: SplitTest},
+		{path: "data.csv", expected: SplitTrain},
+		// the file name takes precedence over the directories
+		{path: "test/train.csv", expected: SplitTrain},
+		// the keywords must be separated tokens
+		{path: "contest.csv", expected: SplitTrain},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, getSplit(tc.path), "path %q", tc.path)
+	}
+}
+
+func TestMergeType(t *testing.T) {
+	var testCases = []struct {
+		a, b     string
+		expected string
+	}{
+		{a: "", b: featureTypeInt, expected: featureTypeInt},
+		{a: featureTypeInt, b: "", expected: featureTypeInt},
+		{a: featureTypeInt, b: featureTypeFloat, expected: featureTypeFloat},
+		{a: featureTypeFloat, b: featureTypeInt, expected: featureTypeFloat},
+		{a: featureTypeBool, b: featureTypeInt, expected: featureTypeString},
+		{a: featureTypeList, b: featureTypeList, expected: featureTypeList},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, mergeType(tc.a, tc.b), "merge %q and %q", tc.a, tc.b)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	b := &fakeBackend{files: map[string][]byte{
+		"datasets/ns/ds/v1/README.md":              []byte("# dataset"),
+		"datasets/ns/ds/v1/data/train-00000.csv":   []byte("text,label,score\nhello,1,0.5\nworld,2,1\n"),
+		"datasets/ns/ds/v1/data/test.jsonl":        []byte("{\"text\":\"a\",\"label\":1}\n{\"text\":\"b\",\"label\":2.5}\n"),
+		"datasets/ns/ds/v1/validation.json":        []byte(`[{"text":"x","label":3,"extra":null}]`),
+		"datasets/ns/ds/v1/data/train-00001.csv":   []byte("text,label,score\nfoo,3,\n"),
+		"datasets/ns/ds/v1/images/cat/example.txt": []byte("not a data file"),
+	}}
+	files := b.fileInfos()
+
+	schema, err := Inspect(context.Background(), b, "datasets/ns/ds/v1/", files)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, FormatMixed, schema.Format)
+	assert.Equal(t, Fingerprint(files), schema.Fingerprint)
+	assert.NotNil(t, schema.InspectTime)
+	assert.Equal(t, []mlv1.DatasetFeature{
+		{Name: "label", Type: featureTypeFloat},
+		{Name: "text", Type: featureTypeString},
+		{Name: "score", Type: featureTypeFloat},
+		{Name: "extra", Type: featureTypeString},
+	}, schema.Features)
+	assert.Equal(t, []mlv1.DatasetSplit{
+		{Name: SplitTrain, Files: []string{"data/train-00000.csv", "data/train-00001.csv"}, NumRows: 3},
+		{Name: SplitValidation, Files: []string{"validation.json"}, NumRows: 1},
+		{Name: SplitTest, Files: []string{"data/test.jsonl"}, NumRows: 2},
+	}, schema.Splits)
+	assert.Equal(t, int64(6), schema.NumRows)
+
+	// the fingerprint changes once a file is modified
+	b.files["datasets/ns/ds/v1/validation.json"] = []byte(`[{"text":"x"},{"text":"y"}]`)
+	assert.NotEqual(t, schema.Fingerprint, Fingerprint(b.fileInfos()))
+}
+
+func TestInspectHFDataset(t *testing.T) {
+	b := &fakeBackend{files: map[string][]byte{
+		"datasets/ns/ds/v1/train/data-00000-of-00001.arrow": []byte("arrow"),
+		"datasets/ns/ds/v1/test/data-00000-of-00001.arrow":  []byte("arrow"),
+		"datasets/ns/ds/v1/dataset_info.json": []byte(`{
+			"features": {
+				"text": {"dtype": "string", "_type": "Value"},
+				"label": {"names": ["neg", "pos"], "_type": "ClassLabel"},
+				"tokens": {"feature": {"dtype": "int32", "_type": "Value"}, "_type": "Sequence"}
+			},
+			"splits": {
+				"train": {"name": "train", "num_examples": 10},
+				"test": {"name": "test", "num_examples": 4}
+			}
+		}`),
+	}}
+
+	schema, err := Inspect(context.Background(), b, "datasets/ns/ds/v1", b.fileInfos())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, FormatArrow, schema.Format)
+	assert.Equal(t, []mlv1.DatasetFeature{
+		{Name: "text", Type: "string"},
+		{Name: "label", Type: "class_label"},
+		{Name: "tokens", Type: featureTypeList},
+	}, schema.Features)
+	assert.Equal(t, []mlv1.DatasetSplit{
+		{Name: SplitTrain, Files: []string{"train/data-00000-of-00001.arrow"}, NumRows: 10},
+		{Name: SplitTest, Files: []string{"test/data-00000-of-00001.arrow"}, NumRows: 4},
+	}, schema.Splits)
+	assert.Equal(t, int64(14), schema.NumRows)
+
+	_, err = Preview(context.Background(), b, "datasets/ns/ds/v1", b.fileInfos(), SplitTrain, 0)
+	assert.ErrorIs(t, err, errPreviewNotSupported)
+}
+
+func TestPreview(t *testing.T) {
+	b := &fakeBackend{files: map[string][]byte{
+		"ds/train-1.tsv":           []byte("text\tlabel\na\t1\nb\t2\n"),
+		"ds/train-2.tsv":           []byte("text\tlabel\nc\t3\n"),
+		"ds/test.jsonl":            []byte("{\"text\":\"x\",\"label\":1}\n\n{\"text\":\"y\",\"label\":2}\n"),
+		"ds/validation.parquet":    newTestParquetFile(),
+		"ds/validation/broken.csv": []byte("text,label\n\"unterminated\n"),
+	}}
+	files := b.fileInfos()
+	date := testParquetDate.Format(parquetTimestampLayout)
+
+	var testCases = []struct {
+		name        string
+		split       string
+		limit       int
+		expected    []map[string]interface{}
+		expectedErr bool
+	}{
+		{
+			name:  "rows across files",
+			split: SplitTrain,
+			limit: 3,
+			expected: []map[string]interface{}{
+				{"text": "a", "label": "1"},
+				{"text": "b", "label": "2"},
+				{"text": "c", "label": "3"},
+			},
+		},
+		{
+			name:  "limited json lines",
+			split: SplitTest,
+			limit: 1,
+			expected: []map[string]interface{}{
+				{"text": "x", "label": json.Number("1")},
+			},
+		},
+		{
+			name:  "parquet rows",
+			split: SplitValidation,
+			limit: 3,
+			expected: []map[string]interface{}{
+				{"text": "hello", "tags": nil, "label": int64(9), "date": date},
+				{"text": nil, "tags": nil, "label": int64(7), "date": date},
+				{"text": "world", "tags": nil, "label": int64(9), "date": date},
+			},
+		},
+		{
+			name:        "split not found",
+			split:       "unknown",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		rows, err := Preview(context.Background(), b, "ds", files, tc.split, tc.limit)
+		if tc.expectedErr {
+			assert.NotNil(t, err, "case %q", tc.name)
+			continue
+		}
+		if assert.Nil(t, err, "case %q", tc.name) {
+			assert.Equal(t, tc.expected, rows, "case %q", tc.name)
+		}
+	}
+}
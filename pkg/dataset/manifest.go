@@ -0,0 +1,239 @@
+package dataset
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
+)
+
+const (
+	// PrivateKeyName is the key of the signing key in the secret
+	PrivateKeyName = "privateKey"
+	// PublicKeyName is the key of the public key in the configmap
+	PublicKeyName = "publicKey"
+
+	// downloadMetadataFile is written by the downloader to the output directory, it's not part of the dataset
+	downloadMetadataFile = ".metadata.json"
+)
+
+// BuildManifest hashes the files of the dataset version
+func BuildManifest(ctx context.Context, b backend.Backend, namespace, name, rootPath string,
+	files []backend.FileInfo) (*mlv1.DatasetManifest, error) {
+	manifestFiles := make([]mlv1.DatasetManifestFile, 0, len(files))
+	for _, f := range files {
+		if f.IsDir {
+			continue
+		}
+		h := sha256.New()
+		if err := b.Download(ctx, f.Path, h); err != nil {
+			return nil, fmt.Errorf("hash file %s failed: %w", f.Path, err)
+		}
+		manifestFiles = append(manifestFiles, mlv1.DatasetManifestFile{
+			Path:   strings.TrimPrefix(strings.TrimPrefix(f.Path, rootPath), "/"),
+			Size:   f.Size,
+			SHA256: hex.EncodeToString(h.Sum(nil)),
+		})
+	}
+	sort.Slice(manifestFiles, func(i, j int) bool { return manifestFiles[i].Path < manifestFiles[j].Path })
+
+	now := metav1.Now()
+	return &mlv1.DatasetManifest{
+		Files:      manifestFiles,
+		Digest:     Digest(namespace, name, manifestFiles),
+		CreateTime: &now,
+	}, nil
+}
+
+// Digest returns the sha256 digest of the canonical form of the dataset version, which is a line of
+// "<namespace>/<name>" followed by one line of "<sha256> <size> <path>" for each file sorted by path. The name is
+// part of the digest so that the signed manifest can't be replayed onto another dataset version.
+func Digest(namespace, name string, files []mlv1.DatasetManifestFile) string {
+	sorted := make([]mlv1.DatasetManifestFile, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s/%s\n", namespace, name)
+	for _, f := range sorted {
+		_, _ = fmt.Fprintf(h, "%s %d %s\n", f.SHA256, f.Size, f.Path)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// SignManifest signs the digest of the manifest with the key
+func SignManifest(m *mlv1.DatasetManifest, key ed25519.PrivateKey) {
+	m.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(m.Digest)))
+	m.KeyID = KeyID(key.Public().(ed25519.PublicKey))
+}
+
+// VerifyManifest checks the digest matches the files of the dataset version and the signature is made by the key
+// of the public key
+func VerifyManifest(m *mlv1.DatasetManifest, namespace, name string, publicKey ed25519.PublicKey) error {
+	if digest := Digest(namespace, name, m.Files); digest != m.Digest {
+		return fmt.Errorf("digest %s doesn't match the files, expected %s", m.Digest, digest)
+	}
+	if m.Signature == "" {
+		return fmt.Errorf("manifest is not signed")
+	}
+	if keyID := KeyID(publicKey); m.KeyID != keyID {
+		return fmt.Errorf("manifest is signed by key %s instead of %s", m.KeyID, keyID)
+	}
+	signature, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("decode signature failed: %w", err)
+	}
+	if !ed25519.Verify(publicKey, []byte(m.Digest), signature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// VerifyFiles checks the files in the directory are exactly the files of the manifest
+func VerifyFiles(dir string, m *mlv1.DatasetManifest) error {
+	expected := make(map[string]mlv1.DatasetManifestFile, len(m.Files))
+	for _, f := range m.Files {
+		expected[f.Path] = f
+	}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if relPath == downloadMetadataFile {
+			return nil
+		}
+
+		f, ok := expected[relPath]
+		if !ok {
+			return fmt.Errorf("file %s is not in the manifest", relPath)
+		}
+		delete(expected, relPath)
+
+		sum, size, err := hashFile(p)
+		if err != nil {
+			return err
+		}
+		if size != f.Size || sum != f.SHA256 {
+			return fmt.Errorf("file %s doesn't match the manifest", relPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(expected) > 0 {
+		missing := make([]string, 0, len(expected))
+		for p := range expected {
+			missing = append(missing, p)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("files %v of the manifest are missing", missing)
+	}
+	return nil
+}
+
+func hashFile(p string) (string, int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", 0, fmt.Errorf("open file %s failed: %w", p, err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("read file %s failed: %w", p, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// KeyID returns the short fingerprint of the public key
+func KeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// GenerateSigningKey returns the PEM encoded ed25519 private key
+func GenerateSigningKey() ([]byte, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate key failed: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("marshal private key failed: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// EncodePublicKey returns the PEM encoded ed25519 public key
+func EncodePublicKey(publicKey ed25519.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("marshal public key failed: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParsePrivateKey parses the PEM encoded ed25519 private key
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM data")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key failed: %w", err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an ed25519 key")
+	}
+	return privateKey, nil
+}
+
+// ParsePublicKey parses the PEM encoded ed25519 public key
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM data")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key failed: %w", err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not an ed25519 key")
+	}
+	return publicKey, nil
+}
+
+// IsFrozen returns true if the files of the dataset version can't be changed any more, which happens once the
+// dataset version is published
+func IsFrozen(dv *mlv1.DatasetVersion) bool {
+	return dv.Spec.Publish || dv.Status.Manifest != nil
+}
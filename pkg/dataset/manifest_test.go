@@ -0,0 +1,185 @@
+package dataset
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+func TestBuildManifest(t *testing.T) {
+	b := &fakeBackend{files: map[string][]byte{
+		"datasets/ns/ds/v1/train.csv":     []byte("a,b\n1,2\n"),
+		"datasets/ns/ds/v1/data/test.csv": []byte("a,b\n"),
+	}}
+
+	m, err := BuildManifest(context.Background(), b, "ns", "v1", "datasets/ns/ds/v1", b.fileInfos())
+	assert.Nil(t, err)
+	assert.Equal(t, []mlv1.DatasetManifestFile{
+		{Path: "data/test.csv", Size: 4, SHA256: sha256Hex("a,b\n")},
+		{Path: "train.csv", Size: 8, SHA256: sha256Hex("a,b\n1,2\n")},
+	}, m.Files)
+	assert.Equal(t, Digest("ns", "v1", m.Files), m.Digest)
+	assert.NotNil(t, m.CreateTime)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDigest(t *testing.T) {
+	files := []mlv1.DatasetManifestFile{
+		{Path: "b.csv", Size: 1, SHA256: "bb"},
+		{Path: "a.csv", Size: 2, SHA256: "aa"},
+	}
+	reversed := []mlv1.DatasetManifestFile{files[1], files[0]}
+	changed := []mlv1.DatasetManifestFile{files[0], {Path: "a.csv", Size: 3, SHA256: "aa"}}
+
+	assert.Equal(t, Digest("ns", "v1", files), Digest("ns", "v1", reversed))
+	assert.NotEqual(t, Digest("ns", "v1", files), Digest("ns", "v1", changed))
+	assert.NotEqual(t, Digest("ns", "v1", files), Digest("ns", "v2", files))
+	assert.NotEqual(t, Digest("ns", "v1", files), Digest("other", "v1", files))
+}
+
+func TestSignAndVerifyManifest(t *testing.T) {
+	data, err := GenerateSigningKey()
+	assert.Nil(t, err)
+	key, err := ParsePrivateKey(data)
+	assert.Nil(t, err)
+	publicKeyData, err := EncodePublicKey(key.Public().(ed25519.PublicKey))
+	assert.Nil(t, err)
+	publicKey, err := ParsePublicKey(publicKeyData)
+	assert.Nil(t, err)
+
+	otherData, err := GenerateSigningKey()
+	assert.Nil(t, err)
+	otherKey, err := ParsePrivateKey(otherData)
+	assert.Nil(t, err)
+
+	newManifest := func() *mlv1.DatasetManifest {
+		files := []mlv1.DatasetManifestFile{{Path: "train.csv", Size: 8, SHA256: "aa"}}
+		return &mlv1.DatasetManifest{Files: files, Digest: Digest("ns", "v1", files)}
+	}
+
+	var testCases = []struct {
+		name   string
+		modify func(m *mlv1.DatasetManifest)
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			modify: func(m *mlv1.DatasetManifest) { SignManifest(m, key) },
+			valid:  true,
+		},
+		{
+			name:   "unsigned",
+			modify: func(_ *mlv1.DatasetManifest) {},
+		},
+		{
+			name:   "signed by other key",
+			modify: func(m *mlv1.DatasetManifest) { SignManifest(m, otherKey) },
+		},
+		{
+			name: "files changed after signing",
+			modify: func(m *mlv1.DatasetManifest) {
+				SignManifest(m, key)
+				m.Files[0].SHA256 = "bb"
+			},
+		},
+		{
+			name: "files and digest changed after signing",
+			modify: func(m *mlv1.DatasetManifest) {
+				SignManifest(m, key)
+				m.Files[0].SHA256 = "bb"
+				m.Digest = Digest("ns", "v1", m.Files)
+			},
+		},
+		{
+			name: "replayed onto another dataset version",
+			modify: func(m *mlv1.DatasetManifest) {
+				m.Digest = Digest("ns", "v2", m.Files)
+				SignManifest(m, key)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		m := newManifest()
+		tc.modify(m)
+		err := VerifyManifest(m, "ns", "v1", publicKey)
+		assert.Equal(t, tc.valid, err == nil, tc.name)
+	}
+}
+
+func TestVerifyFiles(t *testing.T) {
+	files := map[string]string{
+		"train.csv":     "a,b\n1,2\n",
+		"data/test.csv": "a,b\n",
+	}
+	b := &fakeBackend{files: map[string][]byte{}}
+	for p, content := range files {
+		b.files[p] = []byte(content)
+	}
+	m, err := BuildManifest(context.Background(), b, "ns", "v1", "", b.fileInfos())
+	assert.Nil(t, err)
+
+	var testCases = []struct {
+		name  string
+		files map[string]string
+		valid bool
+	}{
+		{
+			name:  "match",
+			files: files,
+			valid: true,
+		},
+		{
+			name: "with download metadata",
+			files: map[string]string{
+				"train.csv":          "a,b\n1,2\n",
+				"data/test.csv":      "a,b\n",
+				downloadMetadataFile: "{}",
+			},
+			valid: true,
+		},
+		{
+			name: "modified file",
+			files: map[string]string{
+				"train.csv":     "a,b\n1,3\n",
+				"data/test.csv": "a,b\n",
+			},
+		},
+		{
+			name: "extra file",
+			files: map[string]string{
+				"train.csv":     "a,b\n1,2\n",
+				"data/test.csv": "a,b\n",
+				"extra.csv":     "a,b\n",
+			},
+		},
+		{
+			name: "missing file",
+			files: map[string]string{
+				"train.csv": "a,b\n1,2\n",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		dir := t.TempDir()
+		for p, content := range tc.files {
+			assert.Nil(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, p)), 0o755))
+			assert.Nil(t, os.WriteFile(filepath.Join(dir, p), []byte(content), 0o600))
+		}
+		err := VerifyFiles(dir, m)
+		assert.Equal(t, tc.valid, err == nil, tc.name)
+	}
+}
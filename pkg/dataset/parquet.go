@@ -0,0 +1,275 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/deprecated"
+	"github.com/parquet-go/parquet-go/format"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+// The parquet files are decoded by parquet-go, the schema elements of the file metadata are mapped to the features
+// and the values of the flat columns are converted for preview.
+
+const (
+	parquetMagic      = "PAR1"
+	parquetFooterSize = 8
+	// maxParquetMetadata limits the file metadata read by the reader, whose length is read from the file footer
+	maxParquetMetadata = 64 << 20
+	// parquetReadBatch is the number of rows read at once for preview
+	parquetReadBatch = 16
+
+	julianDayOfUnixEpoch   = 2440588
+	nanosecondsPerDay      = int64(24 * time.Hour)
+	parquetDateLayout      = "2006-01-02"
+	parquetTimestampLayout = time.RFC3339Nano
+)
+
+// openParquetFile opens the parquet file to read its metadata, the page indexes and bloom filters are skipped
+// since only the schema and the first rows are read
+func openParquetFile(r io.ReaderAt, size int64) (*parquet.File, error) {
+	if size < int64(len(parquetMagic)+parquetFooterSize) {
+		return nil, fmt.Errorf("file is too small to be a parquet file")
+	}
+	footer := make([]byte, parquetFooterSize)
+	if _, err := r.ReadAt(footer, size-parquetFooterSize); err != nil {
+		return nil, fmt.Errorf("failed to read parquet footer: %w", err)
+	}
+	if string(footer[4:]) != parquetMagic {
+		return nil, fmt.Errorf("invalid parquet magic number")
+	}
+	metadataLen := int64(binary.LittleEndian.Uint32(footer[:4]))
+	if metadataLen > maxParquetMetadata || metadataLen > size-parquetFooterSize-int64(len(parquetMagic)) {
+		return nil, fmt.Errorf("invalid parquet metadata length %d", metadataLen)
+	}
+
+	f, err := parquet.OpenFile(r, size, parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	return f, nil
+}
+
+// parquetFeatures returns the top-level fields of the parquet schema as the features
+func parquetFeatures(schema []format.SchemaElement) []mlv1.DatasetFeature {
+	features := make([]mlv1.DatasetFeature, 0)
+	for i := 1; i < len(schema); i = skipSchemaElement(schema, i) {
+		features = append(features, mlv1.DatasetFeature{Name: schema[i].Name, Type: parquetFeatureType(&schema[i])})
+	}
+	return features
+}
+
+// skipSchemaElement returns the index of the next sibling of the schema element
+func skipSchemaElement(schema []format.SchemaElement, i int) int {
+	children := int(schema[i].NumChildren)
+	i++
+	for ; children > 0 && i < len(schema); children-- {
+		i = skipSchemaElement(schema, i)
+	}
+	return i
+}
+
+func parquetFeatureType(e *format.SchemaElement) string {
+	if isRepeated(e) {
+		return featureTypeList
+	}
+	lt := logicalType(e)
+	switch {
+	case lt.List != nil || hasConvertedType(e, deprecated.List):
+		return featureTypeList
+	case lt.Map != nil || hasConvertedType(e, deprecated.Map) || hasConvertedType(e, deprecated.MapKeyValue):
+		return "map"
+	case e.NumChildren > 0 || e.Type == nil:
+		return featureTypeStruct
+	case isParquetString(e):
+		return featureTypeString
+	case lt.Decimal != nil || hasConvertedType(e, deprecated.Decimal):
+		return "decimal"
+	case lt.Date != nil || hasConvertedType(e, deprecated.Date):
+		return "date32"
+	case lt.Timestamp != nil || hasConvertedType(e, deprecated.TimestampMillis) ||
+		hasConvertedType(e, deprecated.TimestampMicros) || *e.Type == format.Int96:
+		return "timestamp"
+	case lt.UUID != nil:
+		return "uuid"
+	}
+
+	switch *e.Type {
+	case format.Boolean:
+		return featureTypeBool
+	case format.Int32:
+		return "int32"
+	case format.Int64:
+		return featureTypeInt
+	case format.Float:
+		return "float32"
+	case format.Double:
+		return featureTypeFloat
+	default:
+		return "binary"
+	}
+}
+
+func logicalType(e *format.SchemaElement) *format.LogicalType {
+	if e.LogicalType == nil {
+		return &format.LogicalType{}
+	}
+	return e.LogicalType
+}
+
+func hasConvertedType(e *format.SchemaElement, t deprecated.ConvertedType) bool {
+	return e.ConvertedType != nil && *e.ConvertedType == t
+}
+
+func isRepeated(e *format.SchemaElement) bool {
+	return e.RepetitionType != nil && *e.RepetitionType == format.Repeated
+}
+
+func isParquetString(e *format.SchemaElement) bool {
+	lt := logicalType(e)
+	return lt.UTF8 != nil || lt.Enum != nil || lt.Json != nil || hasConvertedType(e, deprecated.UTF8) ||
+		hasConvertedType(e, deprecated.Enum) || hasConvertedType(e, deprecated.Json)
+}
+
+// readParquetRows reads the first limit rows of the parquet file, the nested columns are returned as nil
+// since only the flat columns are supported.
+func readParquetRows(r io.ReaderAt, size int64, limit int) ([]map[string]interface{}, error) {
+	f, err := openParquetFile(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	// map the leaf columns to the top-level flat fields, the leaf columns are indexed in the depth-first order
+	schema := f.Metadata().Schema
+	flat := make(map[int]*format.SchemaElement)
+	nested := make([]string, 0)
+	column := 0
+	for i := 1; i < len(schema); {
+		e := &schema[i]
+		next := skipSchemaElement(schema, i)
+		if e.NumChildren > 0 || isRepeated(e) {
+			nested = append(nested, e.Name)
+			for j := i; j < next; j++ {
+				if schema[j].NumChildren == 0 {
+					column++
+				}
+			}
+		} else {
+			flat[column] = e
+			column++
+		}
+		i = next
+	}
+
+	rows := make([]map[string]interface{}, 0, limit)
+	for _, rg := range f.RowGroups() {
+		if len(rows) >= limit {
+			break
+		}
+		batch, err := readParquetRowGroup(rg, flat, nested, limit-len(rows))
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, batch...)
+	}
+	return rows, nil
+}
+
+// readParquetRowGroup reads at most n rows of the row group
+func readParquetRowGroup(rg parquet.RowGroup, flat map[int]*format.SchemaElement, nested []string,
+	n int) ([]map[string]interface{}, error) {
+	reader := rg.Rows()
+	defer reader.Close() //nolint:errcheck
+
+	rows := make([]map[string]interface{}, 0, min(int64(n), rg.NumRows()))
+	buf := make([]parquet.Row, min(n, parquetReadBatch))
+	for len(rows) < n {
+		count, err := reader.ReadRows(buf[:min(len(buf), n-len(rows))])
+		for _, values := range buf[:count] {
+			row := make(map[string]interface{}, len(flat)+len(nested))
+			for _, name := range nested {
+				row[name] = nil
+			}
+			for _, v := range values {
+				if e, ok := flat[v.Column()]; ok {
+					row[e.Name] = convertParquetValue(v, e)
+				}
+			}
+			rows = append(rows, row)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read parquet rows: %w", err)
+		}
+	}
+	return rows, nil
+}
+
+// convertParquetValue converts the value of the flat column to the JSON value of the preview
+func convertParquetValue(v parquet.Value, e *format.SchemaElement) interface{} {
+	if v.IsNull() || e.Type == nil {
+		return nil
+	}
+
+	switch *e.Type {
+	case format.Boolean:
+		return v.Boolean()
+	case format.Int32:
+		if logicalType(e).Date != nil || hasConvertedType(e, deprecated.Date) {
+			return time.Unix(int64(v.Int32())*86400, 0).UTC().Format(parquetDateLayout)
+		}
+		return v.Int32()
+	case format.Int64:
+		return convertInt64(v.Int64(), e)
+	case format.Int96:
+		i := v.Int96()
+		nanos := int64(uint64(i[1])<<32 | uint64(i[0]))
+		days := int64(i[2])
+		return time.Unix(0, (days-julianDayOfUnixEpoch)*nanosecondsPerDay+nanos).UTC().Format(parquetTimestampLayout)
+	case format.Float:
+		return v.Float()
+	case format.Double:
+		return v.Double()
+	default:
+		if isParquetString(e) {
+			return string(v.ByteArray())
+		}
+		// the bytes are encoded in base64 by the JSON encoding, copy them since the buffer of the rows is reused
+		return bytes.Clone(v.ByteArray())
+	}
+}
+
+func convertInt64(v int64, e *format.SchemaElement) interface{} {
+	var unit format.TimeUnit
+	switch {
+	case hasConvertedType(e, deprecated.TimestampMillis):
+		unit.Millis = &format.MilliSeconds{}
+	case hasConvertedType(e, deprecated.TimestampMicros):
+		unit.Micros = &format.MicroSeconds{}
+	case logicalType(e).Timestamp != nil:
+		unit = logicalType(e).Timestamp.Unit
+	default:
+		return v
+	}
+
+	var t time.Time
+	switch {
+	case unit.Millis != nil:
+		t = time.UnixMilli(v)
+	case unit.Micros != nil:
+		t = time.UnixMicro(v)
+	case unit.Nanos != nil:
+		t = time.Unix(0, v)
+	default:
+		return v
+	}
+	return t.UTC().Format(parquetTimestampLayout)
+}
@@ -0,0 +1,79 @@
+package dataset
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+type testParquetRow struct {
+	Text  *string   `parquet:"text,optional"`
+	Tags  []string  `parquet:"tags,list"`
+	Label int64     `parquet:"label,dict"`
+	Date  time.Time `parquet:"date,timestamp(millisecond)"`
+}
+
+var testParquetDate = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// newTestParquetFile builds a parquet file with an optional string column, a nested list column, a required int64
+// column encoded by the dictionary and a timestamp column, which is compressed by snappy
+func newTestParquetFile() []byte {
+	text := func(s string) *string { return &s }
+	buf := &bytes.Buffer{}
+	w := parquet.NewGenericWriter[testParquetRow](buf, parquet.Compression(&parquet.Snappy))
+	if _, err := w.Write([]testParquetRow{
+		{Text: text("hello"), Tags: []string{"a"}, Label: 9, Date: testParquetDate},
+		{Tags: []string{"b", "c"}, Label: 7, Date: testParquetDate},
+		{Text: text("world"), Label: 9, Date: testParquetDate},
+	}); err != nil {
+		panic(err)
+	}
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpenParquetFile(t *testing.T) {
+	data := newTestParquetFile()
+	f, err := openParquetFile(bytes.NewReader(data), int64(len(data)))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, int64(3), f.NumRows())
+	assert.Equal(t, []mlv1.DatasetFeature{
+		{Name: "text", Type: "string"},
+		{Name: "tags", Type: "list"},
+		{Name: "label", Type: "int64"},
+		{Name: "date", Type: "timestamp"},
+	}, parquetFeatures(f.Metadata().Schema))
+
+	_, err = openParquetFile(bytes.NewReader([]byte("not a parquet file")), 18)
+	assert.NotNil(t, err)
+
+	// the metadata length exceeding the file is rejected before it's read
+	corrupted := bytes.Clone(data)
+	copy(corrupted[len(corrupted)-parquetFooterSize:], []byte{0xff, 0xff, 0xff, 0x7f})
+	_, err = openParquetFile(bytes.NewReader(corrupted), int64(len(corrupted)))
+	assert.NotNil(t, err)
+}
+
+func TestReadParquetRows(t *testing.T) {
+	data := newTestParquetFile()
+	rows, err := readParquetRows(bytes.NewReader(data), int64(len(data)), 2)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	date := testParquetDate.Format(parquetTimestampLayout)
+	assert.Equal(t, []map[string]interface{}{
+		{"text": "hello", "tags": nil, "label": int64(9), "date": date},
+		{"text": nil, "tags": nil, "label": int64(7), "date": date},
+	}, rows)
+}
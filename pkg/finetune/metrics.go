@@ -0,0 +1,113 @@
+package finetune
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+// MaxMetrics is the maximum number of metrics kept in the status of the fine-tuning job
+const MaxMetrics = 100
+
+// metricRecord is a line of the metrics file written by the trainer, e.g.,
+// {"step": 10, "epoch": 0.25, "loss": 1.2345, "learning_rate": 0.0002}
+type metricRecord struct {
+	Step         int64    `json:"step"`
+	Epoch        *float64 `json:"epoch,omitempty"`
+	Loss         *float64 `json:"loss,omitempty"`
+	LearningRate *float64 `json:"learning_rate,omitempty"`
+}
+
+// ParseMetrics parses the metrics in json lines, the invalid lines are skipped since the last line may be
+// partially written by the trainer
+func ParseMetrics(r io.Reader) ([]mlv1.FineTuneMetric, error) {
+	var metrics []mlv1.FineTuneMetric
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		record := metricRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			logrus.Debugf("skip invalid metric line %q: %v", scanner.Text(), err)
+			continue
+		}
+		metric := mlv1.FineTuneMetric{Step: record.Step}
+		if record.Epoch != nil {
+			metric.Epoch = strconv.FormatFloat(*record.Epoch, 'f', 2, 64)
+		}
+		if record.Loss != nil {
+			metric.Loss = strconv.FormatFloat(*record.Loss, 'f', 4, 64)
+		}
+		if record.LearningRate != nil {
+			metric.LearningRate = strconv.FormatFloat(*record.LearningRate, 'g', 4, 64)
+		}
+		metrics = append(metrics, metric)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+	return metrics, nil
+}
+
+// ReadMetrics reads the metrics file, no metric is returned if the file is not written yet
+func ReadMetrics(path string) ([]mlv1.FineTuneMetric, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open metrics file %s: %w", path, err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	return ParseMetrics(f)
+}
+
+// SetMetrics records the latest metric and the recent metrics in the status
+func SetMetrics(status *mlv1.FineTuneJobStatus, metrics []mlv1.FineTuneMetric) {
+	if len(metrics) == 0 {
+		return
+	}
+	if len(metrics) > MaxMetrics {
+		metrics = metrics[len(metrics)-MaxMetrics:]
+	}
+	latest := metrics[len(metrics)-1]
+	status.LatestMetric = &latest
+	status.Metrics = append([]mlv1.FineTuneMetric(nil), metrics...)
+}
+
+// JobClient is the client to update the status of the fine-tuning jobs
+type JobClient interface {
+	Get(namespace, name string, opts metav1.GetOptions) (*mlv1.FineTuneJob, error)
+	UpdateStatus(*mlv1.FineTuneJob) (*mlv1.FineTuneJob, error)
+}
+
+// ReportMetrics reads the metrics file and records the metrics in the status of the fine-tuning job
+func ReportMetrics(client JobClient, namespace, name, metricsFile string) error {
+	metrics, err := ReadMetrics(metricsFile)
+	if err != nil || len(metrics) == 0 {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ftj, err := client.Get(namespace, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		ftjCopy := ftj.DeepCopy()
+		SetMetrics(&ftjCopy.Status, metrics)
+		if reflect.DeepEqual(ftj.Status, ftjCopy.Status) {
+			return nil
+		}
+		_, err = client.UpdateStatus(ftjCopy)
+		return err
+	})
+}
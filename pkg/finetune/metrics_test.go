@@ -0,0 +1,43 @@
+package finetune
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+func TestParseMetrics(t *testing.T) {
+	input := strings.Join([]string{
+		`{"step": 10, "epoch": 0.25, "loss": 1.23456, "learning_rate": 0.0002}`,
+		`not a metric`,
+		`{"step": 20, "loss": 0.9}`,
+		`{"step": 30, "epo`,
+	}, "\n")
+
+	metrics, err := ParseMetrics(strings.NewReader(input))
+	assert.Nil(t, err)
+	assert.Equal(t, []mlv1.FineTuneMetric{
+		{Step: 10, Epoch: "0.25", Loss: "1.2346", LearningRate: "0.0002"},
+		{Step: 20, Loss: "0.9000"},
+	}, metrics)
+}
+
+func TestSetMetrics(t *testing.T) {
+	var metrics []mlv1.FineTuneMetric
+	for i := 1; i <= MaxMetrics+20; i++ {
+		metrics = append(metrics, mlv1.FineTuneMetric{Step: int64(i), Loss: fmt.Sprint(i)})
+	}
+
+	status := &mlv1.FineTuneJobStatus{}
+	SetMetrics(status, nil)
+	assert.Nil(t, status.LatestMetric)
+
+	SetMetrics(status, metrics)
+	assert.Len(t, status.Metrics, MaxMetrics)
+	assert.Equal(t, int64(21), status.Metrics[0].Step)
+	assert.Equal(t, metrics[len(metrics)-1], *status.LatestMetric)
+}
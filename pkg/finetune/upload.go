@@ -0,0 +1,45 @@
+package finetune
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/llmos-ai/llmos-operator/pkg/registry/backend"
+)
+
+// Upload uploads the files in the output directory of the trainer to the root path of the output model,
+// the directory structure is kept
+func Upload(ctx context.Context, b backend.Backend, outputDir, rootPath string) error {
+	count := 0
+	err := filepath.WalkDir(outputDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(outputDir, p)
+		if err != nil {
+			return err
+		}
+
+		dst := path.Join(rootPath, path.Dir(filepath.ToSlash(relPath)))
+		logrus.Debugf("uploading %s to %s", relPath, dst)
+		if err = b.Upload(ctx, p, dst); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", relPath, err)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return fmt.Errorf("no file is found in the output directory %s", outputDir)
+	}
+	logrus.Infof("uploaded %d files to %s", count, rootPath)
+	return nil
+}
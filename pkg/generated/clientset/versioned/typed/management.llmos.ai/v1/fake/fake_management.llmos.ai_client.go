@@ -31,6 +31,10 @@ func (c *FakeManagementV1) GlobalRoles() v1.GlobalRoleInterface {
 	return newFakeGlobalRoles(c)
 }
 
+func (c *FakeManagementV1) Groups() v1.GroupInterface {
+	return newFakeGroups(c)
+}
+
 func (c *FakeManagementV1) ManagedAddons(namespace string) v1.ManagedAddonInterface {
 	return newFakeManagedAddons(c, namespace)
 }
@@ -19,6 +19,8 @@ package v1
 
 type GlobalRoleExpansion interface{}
 
+type GroupExpansion interface{}
+
 type ManagedAddonExpansion interface{}
 
 type RoleTemplateExpansion interface{}
@@ -28,6 +28,7 @@ import (
 type ManagementV1Interface interface {
 	RESTClient() rest.Interface
 	GlobalRolesGetter
+	GroupsGetter
 	ManagedAddonsGetter
 	RoleTemplatesGetter
 	RoleTemplateBindingsGetter
@@ -47,6 +48,10 @@ func (c *ManagementV1Client) GlobalRoles() GlobalRoleInterface {
 	return newGlobalRoles(c)
 }
 
+func (c *ManagementV1Client) Groups() GroupInterface {
+	return newGroups(c)
+}
+
 func (c *ManagementV1Client) ManagedAddons(namespace string) ManagedAddonInterface {
 	return newManagedAddons(c, namespace)
 }
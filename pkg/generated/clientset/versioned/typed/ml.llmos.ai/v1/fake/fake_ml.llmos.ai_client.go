@@ -27,6 +27,10 @@ type FakeMlV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeMlV1) BatchInferenceJobs(namespace string) v1.BatchInferenceJobInterface {
+	return newFakeBatchInferenceJobs(c, namespace)
+}
+
 func (c *FakeMlV1) Datasets(namespace string) v1.DatasetInterface {
 	return newFakeDatasets(c, namespace)
 }
@@ -35,6 +39,14 @@ func (c *FakeMlV1) DatasetVersions(namespace string) v1.DatasetVersionInterface
 	return newFakeDatasetVersions(c, namespace)
 }
 
+func (c *FakeMlV1) FineTuneJobs(namespace string) v1.FineTuneJobInterface {
+	return newFakeFineTuneJobs(c, namespace)
+}
+
+func (c *FakeMlV1) LineageEdges(namespace string) v1.LineageEdgeInterface {
+	return newFakeLineageEdges(c, namespace)
+}
+
 func (c *FakeMlV1) LocalModels(namespace string) v1.LocalModelInterface {
 	return newFakeLocalModels(c, namespace)
 }
@@ -47,6 +59,10 @@ func (c *FakeMlV1) Models(namespace string) v1.ModelInterface {
 	return newFakeModels(c, namespace)
 }
 
+func (c *FakeMlV1) ModelBenchmarks(namespace string) v1.ModelBenchmarkInterface {
+	return newFakeModelBenchmarks(c, namespace)
+}
+
 func (c *FakeMlV1) ModelServices(namespace string) v1.ModelServiceInterface {
 	return newFakeModelServices(c, namespace)
 }
@@ -55,6 +71,10 @@ func (c *FakeMlV1) Notebooks(namespace string) v1.NotebookInterface {
 	return newFakeNotebooks(c, namespace)
 }
 
+func (c *FakeMlV1) NotebookProfiles() v1.NotebookProfileInterface {
+	return newFakeNotebookProfiles(c)
+}
+
 func (c *FakeMlV1) Registries() v1.RegistryInterface {
 	return newFakeRegistries(c)
 }
@@ -17,18 +17,28 @@ limitations under the License.
 
 package v1
 
+type BatchInferenceJobExpansion interface{}
+
 type DatasetExpansion interface{}
 
 type DatasetVersionExpansion interface{}
 
+type FineTuneJobExpansion interface{}
+
+type LineageEdgeExpansion interface{}
+
 type LocalModelExpansion interface{}
 
 type LocalModelVersionExpansion interface{}
 
 type ModelExpansion interface{}
 
+type ModelBenchmarkExpansion interface{}
+
 type ModelServiceExpansion interface{}
 
 type NotebookExpansion interface{}
 
+type NotebookProfileExpansion interface{}
+
 type RegistryExpansion interface{}
@@ -27,13 +27,18 @@ import (
 
 type MlV1Interface interface {
 	RESTClient() rest.Interface
+	BatchInferenceJobsGetter
 	DatasetsGetter
 	DatasetVersionsGetter
+	FineTuneJobsGetter
+	LineageEdgesGetter
 	LocalModelsGetter
 	LocalModelVersionsGetter
 	ModelsGetter
+	ModelBenchmarksGetter
 	ModelServicesGetter
 	NotebooksGetter
+	NotebookProfilesGetter
 	RegistriesGetter
 }
 
@@ -42,6 +47,10 @@ type MlV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *MlV1Client) BatchInferenceJobs(namespace string) BatchInferenceJobInterface {
+	return newBatchInferenceJobs(c, namespace)
+}
+
 func (c *MlV1Client) Datasets(namespace string) DatasetInterface {
 	return newDatasets(c, namespace)
 }
@@ -50,6 +59,14 @@ func (c *MlV1Client) DatasetVersions(namespace string) DatasetVersionInterface {
 	return newDatasetVersions(c, namespace)
 }
 
+func (c *MlV1Client) FineTuneJobs(namespace string) FineTuneJobInterface {
+	return newFineTuneJobs(c, namespace)
+}
+
+func (c *MlV1Client) LineageEdges(namespace string) LineageEdgeInterface {
+	return newLineageEdges(c, namespace)
+}
+
 func (c *MlV1Client) LocalModels(namespace string) LocalModelInterface {
 	return newLocalModels(c, namespace)
 }
@@ -62,6 +79,10 @@ func (c *MlV1Client) Models(namespace string) ModelInterface {
 	return newModels(c, namespace)
 }
 
+func (c *MlV1Client) ModelBenchmarks(namespace string) ModelBenchmarkInterface {
+	return newModelBenchmarks(c, namespace)
+}
+
 func (c *MlV1Client) ModelServices(namespace string) ModelServiceInterface {
 	return newModelServices(c, namespace)
 }
@@ -70,6 +91,10 @@ func (c *MlV1Client) Notebooks(namespace string) NotebookInterface {
 	return newNotebooks(c, namespace)
 }
 
+func (c *MlV1Client) NotebookProfiles() NotebookProfileInterface {
+	return newNotebookProfiles(c)
+}
+
 func (c *MlV1Client) Registries() RegistryInterface {
 	return newRegistries(c)
 }
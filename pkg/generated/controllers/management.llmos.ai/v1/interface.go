@@ -31,6 +31,7 @@ func init() {
 
 type Interface interface {
 	GlobalRole() GlobalRoleController
+	Group() GroupController
 	ManagedAddon() ManagedAddonController
 	RoleTemplate() RoleTemplateController
 	RoleTemplateBinding() RoleTemplateBindingController
@@ -55,6 +56,10 @@ func (v *version) GlobalRole() GlobalRoleController {
 	return generic.NewNonNamespacedController[*v1.GlobalRole, *v1.GlobalRoleList](schema.GroupVersionKind{Group: "management.llmos.ai", Version: "v1", Kind: "GlobalRole"}, "globalroles", v.controllerFactory)
 }
 
+func (v *version) Group() GroupController {
+	return generic.NewNonNamespacedController[*v1.Group, *v1.GroupList](schema.GroupVersionKind{Group: "management.llmos.ai", Version: "v1", Kind: "Group"}, "groups", v.controllerFactory)
+}
+
 func (v *version) ManagedAddon() ManagedAddonController {
 	return generic.NewController[*v1.ManagedAddon, *v1.ManagedAddonList](schema.GroupVersionKind{Group: "management.llmos.ai", Version: "v1", Kind: "ManagedAddon"}, "managedaddons", true, v.controllerFactory)
 }
@@ -30,13 +30,18 @@ func init() {
 }
 
 type Interface interface {
+	BatchInferenceJob() BatchInferenceJobController
 	Dataset() DatasetController
 	DatasetVersion() DatasetVersionController
+	FineTuneJob() FineTuneJobController
+	LineageEdge() LineageEdgeController
 	LocalModel() LocalModelController
 	LocalModelVersion() LocalModelVersionController
 	Model() ModelController
+	ModelBenchmark() ModelBenchmarkController
 	ModelService() ModelServiceController
 	Notebook() NotebookController
+	NotebookProfile() NotebookProfileController
 	Registry() RegistryController
 }
 
@@ -50,6 +55,10 @@ type version struct {
 	controllerFactory controller.SharedControllerFactory
 }
 
+func (v *version) BatchInferenceJob() BatchInferenceJobController {
+	return generic.NewController[*v1.BatchInferenceJob, *v1.BatchInferenceJobList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "BatchInferenceJob"}, "batchinferencejobs", true, v.controllerFactory)
+}
+
 func (v *version) Dataset() DatasetController {
 	return generic.NewController[*v1.Dataset, *v1.DatasetList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "Dataset"}, "datasets", true, v.controllerFactory)
 }
@@ -58,6 +67,14 @@ func (v *version) DatasetVersion() DatasetVersionController {
 	return generic.NewController[*v1.DatasetVersion, *v1.DatasetVersionList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "DatasetVersion"}, "datasetversions", true, v.controllerFactory)
 }
 
+func (v *version) FineTuneJob() FineTuneJobController {
+	return generic.NewController[*v1.FineTuneJob, *v1.FineTuneJobList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "FineTuneJob"}, "finetunejobs", true, v.controllerFactory)
+}
+
+func (v *version) LineageEdge() LineageEdgeController {
+	return generic.NewController[*v1.LineageEdge, *v1.LineageEdgeList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "LineageEdge"}, "lineageedges", true, v.controllerFactory)
+}
+
 func (v *version) LocalModel() LocalModelController {
 	return generic.NewController[*v1.LocalModel, *v1.LocalModelList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "LocalModel"}, "localmodels", true, v.controllerFactory)
 }
@@ -70,6 +87,10 @@ func (v *version) Model() ModelController {
 	return generic.NewController[*v1.Model, *v1.ModelList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "Model"}, "models", true, v.controllerFactory)
 }
 
+func (v *version) ModelBenchmark() ModelBenchmarkController {
+	return generic.NewController[*v1.ModelBenchmark, *v1.ModelBenchmarkList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "ModelBenchmark"}, "modelbenchmarks", true, v.controllerFactory)
+}
+
 func (v *version) ModelService() ModelServiceController {
 	return generic.NewController[*v1.ModelService, *v1.ModelServiceList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "ModelService"}, "modelservices", true, v.controllerFactory)
 }
@@ -78,6 +99,10 @@ func (v *version) Notebook() NotebookController {
 	return generic.NewController[*v1.Notebook, *v1.NotebookList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "Notebook"}, "notebooks", true, v.controllerFactory)
 }
 
+func (v *version) NotebookProfile() NotebookProfileController {
+	return generic.NewNonNamespacedController[*v1.NotebookProfile, *v1.NotebookProfileList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "NotebookProfile"}, "notebookprofiles", v.controllerFactory)
+}
+
 func (v *version) Registry() RegistryController {
 	return generic.NewNonNamespacedController[*v1.Registry, *v1.RegistryList](schema.GroupVersionKind{Group: "ml.llmos.ai", Version: "v1", Kind: "Registry"}, "registries", v.controllerFactory)
 }
@@ -0,0 +1,255 @@
+/*
+Copyright YEAR llmos.ai.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/auditlog"
+)
+
+// AuditLog is the model entity for the AuditLog schema.
+type AuditLog struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID uuid.UUID `json:"id,omitempty"`
+	// UserId holds the value of the "userId" field.
+	UserId string `json:"userId,omitempty"`
+	// TokenName holds the value of the "tokenName" field.
+	TokenName string `json:"tokenName,omitempty"`
+	// Verb holds the value of the "verb" field.
+	Verb string `json:"verb,omitempty"`
+	// Resource holds the value of the "resource" field.
+	Resource string `json:"resource,omitempty"`
+	// Namespace holds the value of the "namespace" field.
+	Namespace string `json:"namespace,omitempty"`
+	// Name holds the value of the "name" field.
+	Name string `json:"name,omitempty"`
+	// Method holds the value of the "method" field.
+	Method string `json:"method,omitempty"`
+	// Path holds the value of the "path" field.
+	Path string `json:"path,omitempty"`
+	// StatusCode holds the value of the "statusCode" field.
+	StatusCode int `json:"statusCode,omitempty"`
+	// LatencyMs holds the value of the "latencyMs" field.
+	LatencyMs int64 `json:"latencyMs,omitempty"`
+	// SourceIP holds the value of the "sourceIP" field.
+	SourceIP string `json:"sourceIP,omitempty"`
+	// UserAgent holds the value of the "userAgent" field.
+	UserAgent string `json:"userAgent,omitempty"`
+	// CreatedAt holds the value of the "createdAt" field.
+	CreatedAt    time.Time `json:"createdAt,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*AuditLog) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case auditlog.FieldStatusCode, auditlog.FieldLatencyMs:
+			values[i] = new(sql.NullInt64)
+		case auditlog.FieldUserId, auditlog.FieldTokenName, auditlog.FieldVerb, auditlog.FieldResource, auditlog.FieldNamespace, auditlog.FieldName, auditlog.FieldMethod, auditlog.FieldPath, auditlog.FieldSourceIP, auditlog.FieldUserAgent:
+			values[i] = new(sql.NullString)
+		case auditlog.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		case auditlog.FieldID:
+			values[i] = new(uuid.UUID)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the AuditLog fields.
+func (al *AuditLog) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case auditlog.FieldID:
+			if value, ok := values[i].(*uuid.UUID); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value != nil {
+				al.ID = *value
+			}
+		case auditlog.FieldUserId:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field userId", values[i])
+			} else if value.Valid {
+				al.UserId = value.String
+			}
+		case auditlog.FieldTokenName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field tokenName", values[i])
+			} else if value.Valid {
+				al.TokenName = value.String
+			}
+		case auditlog.FieldVerb:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field verb", values[i])
+			} else if value.Valid {
+				al.Verb = value.String
+			}
+		case auditlog.FieldResource:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field resource", values[i])
+			} else if value.Valid {
+				al.Resource = value.String
+			}
+		case auditlog.FieldNamespace:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field namespace", values[i])
+			} else if value.Valid {
+				al.Namespace = value.String
+			}
+		case auditlog.FieldName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field name", values[i])
+			} else if value.Valid {
+				al.Name = value.String
+			}
+		case auditlog.FieldMethod:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field method", values[i])
+			} else if value.Valid {
+				al.Method = value.String
+			}
+		case auditlog.FieldPath:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field path", values[i])
+			} else if value.Valid {
+				al.Path = value.String
+			}
+		case auditlog.FieldStatusCode:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field statusCode", values[i])
+			} else if value.Valid {
+				al.StatusCode = int(value.Int64)
+			}
+		case auditlog.FieldLatencyMs:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field latencyMs", values[i])
+			} else if value.Valid {
+				al.LatencyMs = value.Int64
+			}
+		case auditlog.FieldSourceIP:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field sourceIP", values[i])
+			} else if value.Valid {
+				al.SourceIP = value.String
+			}
+		case auditlog.FieldUserAgent:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field userAgent", values[i])
+			} else if value.Valid {
+				al.UserAgent = value.String
+			}
+		case auditlog.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field createdAt", values[i])
+			} else if value.Valid {
+				al.CreatedAt = value.Time
+			}
+		default:
+			al.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the AuditLog.
+// This includes values selected through modifiers, order, etc.
+func (al *AuditLog) Value(name string) (ent.Value, error) {
+	return al.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this AuditLog.
+// Note that you need to call AuditLog.Unwrap() before calling this method if this AuditLog
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (al *AuditLog) Update() *AuditLogUpdateOne {
+	return NewAuditLogClient(al.config).UpdateOne(al)
+}
+
+// Unwrap unwraps the AuditLog entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (al *AuditLog) Unwrap() *AuditLog {
+	_tx, ok := al.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: AuditLog is not a transactional entity")
+	}
+	al.config.driver = _tx.drv
+	return al
+}
+
+// String implements the fmt.Stringer.
+func (al *AuditLog) String() string {
+	var builder strings.Builder
+	builder.WriteString("AuditLog(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", al.ID))
+	builder.WriteString("userId=")
+	builder.WriteString(al.UserId)
+	builder.WriteString(", ")
+	builder.WriteString("tokenName=")
+	builder.WriteString(al.TokenName)
+	builder.WriteString(", ")
+	builder.WriteString("verb=")
+	builder.WriteString(al.Verb)
+	builder.WriteString(", ")
+	builder.WriteString("resource=")
+	builder.WriteString(al.Resource)
+	builder.WriteString(", ")
+	builder.WriteString("namespace=")
+	builder.WriteString(al.Namespace)
+	builder.WriteString(", ")
+	builder.WriteString("name=")
+	builder.WriteString(al.Name)
+	builder.WriteString(", ")
+	builder.WriteString("method=")
+	builder.WriteString(al.Method)
+	builder.WriteString(", ")
+	builder.WriteString("path=")
+	builder.WriteString(al.Path)
+	builder.WriteString(", ")
+	builder.WriteString("statusCode=")
+	builder.WriteString(fmt.Sprintf("%v", al.StatusCode))
+	builder.WriteString(", ")
+	builder.WriteString("latencyMs=")
+	builder.WriteString(fmt.Sprintf("%v", al.LatencyMs))
+	builder.WriteString(", ")
+	builder.WriteString("sourceIP=")
+	builder.WriteString(al.SourceIP)
+	builder.WriteString(", ")
+	builder.WriteString("userAgent=")
+	builder.WriteString(al.UserAgent)
+	builder.WriteString(", ")
+	builder.WriteString("createdAt=")
+	builder.WriteString(al.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// AuditLogs is a parsable slice of AuditLog.
+type AuditLogs []*AuditLog
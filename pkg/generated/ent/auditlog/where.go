@@ -0,0 +1,990 @@
+/*
+Copyright YEAR llmos.ai.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditlog
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id uuid.UUID) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id uuid.UUID) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id uuid.UUID) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...uuid.UUID) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...uuid.UUID) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id uuid.UUID) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id uuid.UUID) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id uuid.UUID) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id uuid.UUID) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldID, id))
+}
+
+// UserId applies equality check predicate on the "userId" field. It's identical to UserIdEQ.
+func UserId(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldUserId, v))
+}
+
+// TokenName applies equality check predicate on the "tokenName" field. It's identical to TokenNameEQ.
+func TokenName(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldTokenName, v))
+}
+
+// Verb applies equality check predicate on the "verb" field. It's identical to VerbEQ.
+func Verb(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldVerb, v))
+}
+
+// Resource applies equality check predicate on the "resource" field. It's identical to ResourceEQ.
+func Resource(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldResource, v))
+}
+
+// Namespace applies equality check predicate on the "namespace" field. It's identical to NamespaceEQ.
+func Namespace(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldNamespace, v))
+}
+
+// Name applies equality check predicate on the "name" field. It's identical to NameEQ.
+func Name(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldName, v))
+}
+
+// Method applies equality check predicate on the "method" field. It's identical to MethodEQ.
+func Method(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldMethod, v))
+}
+
+// Path applies equality check predicate on the "path" field. It's identical to PathEQ.
+func Path(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldPath, v))
+}
+
+// StatusCode applies equality check predicate on the "statusCode" field. It's identical to StatusCodeEQ.
+func StatusCode(v int) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldStatusCode, v))
+}
+
+// LatencyMs applies equality check predicate on the "latencyMs" field. It's identical to LatencyMsEQ.
+func LatencyMs(v int64) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldLatencyMs, v))
+}
+
+// SourceIP applies equality check predicate on the "sourceIP" field. It's identical to SourceIPEQ.
+func SourceIP(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldSourceIP, v))
+}
+
+// UserAgent applies equality check predicate on the "userAgent" field. It's identical to UserAgentEQ.
+func UserAgent(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldUserAgent, v))
+}
+
+// CreatedAt applies equality check predicate on the "createdAt" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// UserIdEQ applies the EQ predicate on the "userId" field.
+func UserIdEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldUserId, v))
+}
+
+// UserIdNEQ applies the NEQ predicate on the "userId" field.
+func UserIdNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldUserId, v))
+}
+
+// UserIdIn applies the In predicate on the "userId" field.
+func UserIdIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldUserId, vs...))
+}
+
+// UserIdNotIn applies the NotIn predicate on the "userId" field.
+func UserIdNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldUserId, vs...))
+}
+
+// UserIdGT applies the GT predicate on the "userId" field.
+func UserIdGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldUserId, v))
+}
+
+// UserIdGTE applies the GTE predicate on the "userId" field.
+func UserIdGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldUserId, v))
+}
+
+// UserIdLT applies the LT predicate on the "userId" field.
+func UserIdLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldUserId, v))
+}
+
+// UserIdLTE applies the LTE predicate on the "userId" field.
+func UserIdLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldUserId, v))
+}
+
+// UserIdContains applies the Contains predicate on the "userId" field.
+func UserIdContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldUserId, v))
+}
+
+// UserIdHasPrefix applies the HasPrefix predicate on the "userId" field.
+func UserIdHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldUserId, v))
+}
+
+// UserIdHasSuffix applies the HasSuffix predicate on the "userId" field.
+func UserIdHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldUserId, v))
+}
+
+// UserIdIsNil applies the IsNil predicate on the "userId" field.
+func UserIdIsNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIsNull(FieldUserId))
+}
+
+// UserIdNotNil applies the NotNil predicate on the "userId" field.
+func UserIdNotNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotNull(FieldUserId))
+}
+
+// UserIdEqualFold applies the EqualFold predicate on the "userId" field.
+func UserIdEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldUserId, v))
+}
+
+// UserIdContainsFold applies the ContainsFold predicate on the "userId" field.
+func UserIdContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldUserId, v))
+}
+
+// TokenNameEQ applies the EQ predicate on the "tokenName" field.
+func TokenNameEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldTokenName, v))
+}
+
+// TokenNameNEQ applies the NEQ predicate on the "tokenName" field.
+func TokenNameNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldTokenName, v))
+}
+
+// TokenNameIn applies the In predicate on the "tokenName" field.
+func TokenNameIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldTokenName, vs...))
+}
+
+// TokenNameNotIn applies the NotIn predicate on the "tokenName" field.
+func TokenNameNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldTokenName, vs...))
+}
+
+// TokenNameGT applies the GT predicate on the "tokenName" field.
+func TokenNameGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldTokenName, v))
+}
+
+// TokenNameGTE applies the GTE predicate on the "tokenName" field.
+func TokenNameGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldTokenName, v))
+}
+
+// TokenNameLT applies the LT predicate on the "tokenName" field.
+func TokenNameLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldTokenName, v))
+}
+
+// TokenNameLTE applies the LTE predicate on the "tokenName" field.
+func TokenNameLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldTokenName, v))
+}
+
+// TokenNameContains applies the Contains predicate on the "tokenName" field.
+func TokenNameContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldTokenName, v))
+}
+
+// TokenNameHasPrefix applies the HasPrefix predicate on the "tokenName" field.
+func TokenNameHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldTokenName, v))
+}
+
+// TokenNameHasSuffix applies the HasSuffix predicate on the "tokenName" field.
+func TokenNameHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldTokenName, v))
+}
+
+// TokenNameIsNil applies the IsNil predicate on the "tokenName" field.
+func TokenNameIsNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIsNull(FieldTokenName))
+}
+
+// TokenNameNotNil applies the NotNil predicate on the "tokenName" field.
+func TokenNameNotNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotNull(FieldTokenName))
+}
+
+// TokenNameEqualFold applies the EqualFold predicate on the "tokenName" field.
+func TokenNameEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldTokenName, v))
+}
+
+// TokenNameContainsFold applies the ContainsFold predicate on the "tokenName" field.
+func TokenNameContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldTokenName, v))
+}
+
+// VerbEQ applies the EQ predicate on the "verb" field.
+func VerbEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldVerb, v))
+}
+
+// VerbNEQ applies the NEQ predicate on the "verb" field.
+func VerbNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldVerb, v))
+}
+
+// VerbIn applies the In predicate on the "verb" field.
+func VerbIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldVerb, vs...))
+}
+
+// VerbNotIn applies the NotIn predicate on the "verb" field.
+func VerbNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldVerb, vs...))
+}
+
+// VerbGT applies the GT predicate on the "verb" field.
+func VerbGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldVerb, v))
+}
+
+// VerbGTE applies the GTE predicate on the "verb" field.
+func VerbGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldVerb, v))
+}
+
+// VerbLT applies the LT predicate on the "verb" field.
+func VerbLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldVerb, v))
+}
+
+// VerbLTE applies the LTE predicate on the "verb" field.
+func VerbLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldVerb, v))
+}
+
+// VerbContains applies the Contains predicate on the "verb" field.
+func VerbContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldVerb, v))
+}
+
+// VerbHasPrefix applies the HasPrefix predicate on the "verb" field.
+func VerbHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldVerb, v))
+}
+
+// VerbHasSuffix applies the HasSuffix predicate on the "verb" field.
+func VerbHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldVerb, v))
+}
+
+// VerbEqualFold applies the EqualFold predicate on the "verb" field.
+func VerbEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldVerb, v))
+}
+
+// VerbContainsFold applies the ContainsFold predicate on the "verb" field.
+func VerbContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldVerb, v))
+}
+
+// ResourceEQ applies the EQ predicate on the "resource" field.
+func ResourceEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldResource, v))
+}
+
+// ResourceNEQ applies the NEQ predicate on the "resource" field.
+func ResourceNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldResource, v))
+}
+
+// ResourceIn applies the In predicate on the "resource" field.
+func ResourceIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldResource, vs...))
+}
+
+// ResourceNotIn applies the NotIn predicate on the "resource" field.
+func ResourceNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldResource, vs...))
+}
+
+// ResourceGT applies the GT predicate on the "resource" field.
+func ResourceGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldResource, v))
+}
+
+// ResourceGTE applies the GTE predicate on the "resource" field.
+func ResourceGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldResource, v))
+}
+
+// ResourceLT applies the LT predicate on the "resource" field.
+func ResourceLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldResource, v))
+}
+
+// ResourceLTE applies the LTE predicate on the "resource" field.
+func ResourceLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldResource, v))
+}
+
+// ResourceContains applies the Contains predicate on the "resource" field.
+func ResourceContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldResource, v))
+}
+
+// ResourceHasPrefix applies the HasPrefix predicate on the "resource" field.
+func ResourceHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldResource, v))
+}
+
+// ResourceHasSuffix applies the HasSuffix predicate on the "resource" field.
+func ResourceHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldResource, v))
+}
+
+// ResourceIsNil applies the IsNil predicate on the "resource" field.
+func ResourceIsNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIsNull(FieldResource))
+}
+
+// ResourceNotNil applies the NotNil predicate on the "resource" field.
+func ResourceNotNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotNull(FieldResource))
+}
+
+// ResourceEqualFold applies the EqualFold predicate on the "resource" field.
+func ResourceEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldResource, v))
+}
+
+// ResourceContainsFold applies the ContainsFold predicate on the "resource" field.
+func ResourceContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldResource, v))
+}
+
+// NamespaceEQ applies the EQ predicate on the "namespace" field.
+func NamespaceEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldNamespace, v))
+}
+
+// NamespaceNEQ applies the NEQ predicate on the "namespace" field.
+func NamespaceNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldNamespace, v))
+}
+
+// NamespaceIn applies the In predicate on the "namespace" field.
+func NamespaceIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldNamespace, vs...))
+}
+
+// NamespaceNotIn applies the NotIn predicate on the "namespace" field.
+func NamespaceNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldNamespace, vs...))
+}
+
+// NamespaceGT applies the GT predicate on the "namespace" field.
+func NamespaceGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldNamespace, v))
+}
+
+// NamespaceGTE applies the GTE predicate on the "namespace" field.
+func NamespaceGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldNamespace, v))
+}
+
+// NamespaceLT applies the LT predicate on the "namespace" field.
+func NamespaceLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldNamespace, v))
+}
+
+// NamespaceLTE applies the LTE predicate on the "namespace" field.
+func NamespaceLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldNamespace, v))
+}
+
+// NamespaceContains applies the Contains predicate on the "namespace" field.
+func NamespaceContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldNamespace, v))
+}
+
+// NamespaceHasPrefix applies the HasPrefix predicate on the "namespace" field.
+func NamespaceHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldNamespace, v))
+}
+
+// NamespaceHasSuffix applies the HasSuffix predicate on the "namespace" field.
+func NamespaceHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldNamespace, v))
+}
+
+// NamespaceIsNil applies the IsNil predicate on the "namespace" field.
+func NamespaceIsNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIsNull(FieldNamespace))
+}
+
+// NamespaceNotNil applies the NotNil predicate on the "namespace" field.
+func NamespaceNotNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotNull(FieldNamespace))
+}
+
+// NamespaceEqualFold applies the EqualFold predicate on the "namespace" field.
+func NamespaceEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldNamespace, v))
+}
+
+// NamespaceContainsFold applies the ContainsFold predicate on the "namespace" field.
+func NamespaceContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldNamespace, v))
+}
+
+// NameEQ applies the EQ predicate on the "name" field.
+func NameEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldName, v))
+}
+
+// NameNEQ applies the NEQ predicate on the "name" field.
+func NameNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldName, v))
+}
+
+// NameIn applies the In predicate on the "name" field.
+func NameIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldName, vs...))
+}
+
+// NameNotIn applies the NotIn predicate on the "name" field.
+func NameNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldName, vs...))
+}
+
+// NameGT applies the GT predicate on the "name" field.
+func NameGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldName, v))
+}
+
+// NameGTE applies the GTE predicate on the "name" field.
+func NameGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldName, v))
+}
+
+// NameLT applies the LT predicate on the "name" field.
+func NameLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldName, v))
+}
+
+// NameLTE applies the LTE predicate on the "name" field.
+func NameLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldName, v))
+}
+
+// NameContains applies the Contains predicate on the "name" field.
+func NameContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldName, v))
+}
+
+// NameHasPrefix applies the HasPrefix predicate on the "name" field.
+func NameHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldName, v))
+}
+
+// NameHasSuffix applies the HasSuffix predicate on the "name" field.
+func NameHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldName, v))
+}
+
+// NameIsNil applies the IsNil predicate on the "name" field.
+func NameIsNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIsNull(FieldName))
+}
+
+// NameNotNil applies the NotNil predicate on the "name" field.
+func NameNotNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotNull(FieldName))
+}
+
+// NameEqualFold applies the EqualFold predicate on the "name" field.
+func NameEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldName, v))
+}
+
+// NameContainsFold applies the ContainsFold predicate on the "name" field.
+func NameContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldName, v))
+}
+
+// MethodEQ applies the EQ predicate on the "method" field.
+func MethodEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldMethod, v))
+}
+
+// MethodNEQ applies the NEQ predicate on the "method" field.
+func MethodNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldMethod, v))
+}
+
+// MethodIn applies the In predicate on the "method" field.
+func MethodIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldMethod, vs...))
+}
+
+// MethodNotIn applies the NotIn predicate on the "method" field.
+func MethodNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldMethod, vs...))
+}
+
+// MethodGT applies the GT predicate on the "method" field.
+func MethodGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldMethod, v))
+}
+
+// MethodGTE applies the GTE predicate on the "method" field.
+func MethodGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldMethod, v))
+}
+
+// MethodLT applies the LT predicate on the "method" field.
+func MethodLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldMethod, v))
+}
+
+// MethodLTE applies the LTE predicate on the "method" field.
+func MethodLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldMethod, v))
+}
+
+// MethodContains applies the Contains predicate on the "method" field.
+func MethodContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldMethod, v))
+}
+
+// MethodHasPrefix applies the HasPrefix predicate on the "method" field.
+func MethodHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldMethod, v))
+}
+
+// MethodHasSuffix applies the HasSuffix predicate on the "method" field.
+func MethodHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldMethod, v))
+}
+
+// MethodEqualFold applies the EqualFold predicate on the "method" field.
+func MethodEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldMethod, v))
+}
+
+// MethodContainsFold applies the ContainsFold predicate on the "method" field.
+func MethodContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldMethod, v))
+}
+
+// PathEQ applies the EQ predicate on the "path" field.
+func PathEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldPath, v))
+}
+
+// PathNEQ applies the NEQ predicate on the "path" field.
+func PathNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldPath, v))
+}
+
+// PathIn applies the In predicate on the "path" field.
+func PathIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldPath, vs...))
+}
+
+// PathNotIn applies the NotIn predicate on the "path" field.
+func PathNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldPath, vs...))
+}
+
+// PathGT applies the GT predicate on the "path" field.
+func PathGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldPath, v))
+}
+
+// PathGTE applies the GTE predicate on the "path" field.
+func PathGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldPath, v))
+}
+
+// PathLT applies the LT predicate on the "path" field.
+func PathLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldPath, v))
+}
+
+// PathLTE applies the LTE predicate on the "path" field.
+func PathLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldPath, v))
+}
+
+// PathContains applies the Contains predicate on the "path" field.
+func PathContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldPath, v))
+}
+
+// PathHasPrefix applies the HasPrefix predicate on the "path" field.
+func PathHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldPath, v))
+}
+
+// PathHasSuffix applies the HasSuffix predicate on the "path" field.
+func PathHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldPath, v))
+}
+
+// PathEqualFold applies the EqualFold predicate on the "path" field.
+func PathEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldPath, v))
+}
+
+// PathContainsFold applies the ContainsFold predicate on the "path" field.
+func PathContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldPath, v))
+}
+
+// StatusCodeEQ applies the EQ predicate on the "statusCode" field.
+func StatusCodeEQ(v int) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldStatusCode, v))
+}
+
+// StatusCodeNEQ applies the NEQ predicate on the "statusCode" field.
+func StatusCodeNEQ(v int) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldStatusCode, v))
+}
+
+// StatusCodeIn applies the In predicate on the "statusCode" field.
+func StatusCodeIn(vs ...int) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldStatusCode, vs...))
+}
+
+// StatusCodeNotIn applies the NotIn predicate on the "statusCode" field.
+func StatusCodeNotIn(vs ...int) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldStatusCode, vs...))
+}
+
+// StatusCodeGT applies the GT predicate on the "statusCode" field.
+func StatusCodeGT(v int) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldStatusCode, v))
+}
+
+// StatusCodeGTE applies the GTE predicate on the "statusCode" field.
+func StatusCodeGTE(v int) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldStatusCode, v))
+}
+
+// StatusCodeLT applies the LT predicate on the "statusCode" field.
+func StatusCodeLT(v int) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldStatusCode, v))
+}
+
+// StatusCodeLTE applies the LTE predicate on the "statusCode" field.
+func StatusCodeLTE(v int) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldStatusCode, v))
+}
+
+// LatencyMsEQ applies the EQ predicate on the "latencyMs" field.
+func LatencyMsEQ(v int64) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldLatencyMs, v))
+}
+
+// LatencyMsNEQ applies the NEQ predicate on the "latencyMs" field.
+func LatencyMsNEQ(v int64) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldLatencyMs, v))
+}
+
+// LatencyMsIn applies the In predicate on the "latencyMs" field.
+func LatencyMsIn(vs ...int64) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldLatencyMs, vs...))
+}
+
+// LatencyMsNotIn applies the NotIn predicate on the "latencyMs" field.
+func LatencyMsNotIn(vs ...int64) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldLatencyMs, vs...))
+}
+
+// LatencyMsGT applies the GT predicate on the "latencyMs" field.
+func LatencyMsGT(v int64) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldLatencyMs, v))
+}
+
+// LatencyMsGTE applies the GTE predicate on the "latencyMs" field.
+func LatencyMsGTE(v int64) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldLatencyMs, v))
+}
+
+// LatencyMsLT applies the LT predicate on the "latencyMs" field.
+func LatencyMsLT(v int64) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldLatencyMs, v))
+}
+
+// LatencyMsLTE applies the LTE predicate on the "latencyMs" field.
+func LatencyMsLTE(v int64) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldLatencyMs, v))
+}
+
+// SourceIPEQ applies the EQ predicate on the "sourceIP" field.
+func SourceIPEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldSourceIP, v))
+}
+
+// SourceIPNEQ applies the NEQ predicate on the "sourceIP" field.
+func SourceIPNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldSourceIP, v))
+}
+
+// SourceIPIn applies the In predicate on the "sourceIP" field.
+func SourceIPIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldSourceIP, vs...))
+}
+
+// SourceIPNotIn applies the NotIn predicate on the "sourceIP" field.
+func SourceIPNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldSourceIP, vs...))
+}
+
+// SourceIPGT applies the GT predicate on the "sourceIP" field.
+func SourceIPGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldSourceIP, v))
+}
+
+// SourceIPGTE applies the GTE predicate on the "sourceIP" field.
+func SourceIPGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldSourceIP, v))
+}
+
+// SourceIPLT applies the LT predicate on the "sourceIP" field.
+func SourceIPLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldSourceIP, v))
+}
+
+// SourceIPLTE applies the LTE predicate on the "sourceIP" field.
+func SourceIPLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldSourceIP, v))
+}
+
+// SourceIPContains applies the Contains predicate on the "sourceIP" field.
+func SourceIPContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldSourceIP, v))
+}
+
+// SourceIPHasPrefix applies the HasPrefix predicate on the "sourceIP" field.
+func SourceIPHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldSourceIP, v))
+}
+
+// SourceIPHasSuffix applies the HasSuffix predicate on the "sourceIP" field.
+func SourceIPHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldSourceIP, v))
+}
+
+// SourceIPIsNil applies the IsNil predicate on the "sourceIP" field.
+func SourceIPIsNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIsNull(FieldSourceIP))
+}
+
+// SourceIPNotNil applies the NotNil predicate on the "sourceIP" field.
+func SourceIPNotNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotNull(FieldSourceIP))
+}
+
+// SourceIPEqualFold applies the EqualFold predicate on the "sourceIP" field.
+func SourceIPEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldSourceIP, v))
+}
+
+// SourceIPContainsFold applies the ContainsFold predicate on the "sourceIP" field.
+func SourceIPContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldSourceIP, v))
+}
+
+// UserAgentEQ applies the EQ predicate on the "userAgent" field.
+func UserAgentEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldUserAgent, v))
+}
+
+// UserAgentNEQ applies the NEQ predicate on the "userAgent" field.
+func UserAgentNEQ(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldUserAgent, v))
+}
+
+// UserAgentIn applies the In predicate on the "userAgent" field.
+func UserAgentIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldUserAgent, vs...))
+}
+
+// UserAgentNotIn applies the NotIn predicate on the "userAgent" field.
+func UserAgentNotIn(vs ...string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldUserAgent, vs...))
+}
+
+// UserAgentGT applies the GT predicate on the "userAgent" field.
+func UserAgentGT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldUserAgent, v))
+}
+
+// UserAgentGTE applies the GTE predicate on the "userAgent" field.
+func UserAgentGTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldUserAgent, v))
+}
+
+// UserAgentLT applies the LT predicate on the "userAgent" field.
+func UserAgentLT(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldUserAgent, v))
+}
+
+// UserAgentLTE applies the LTE predicate on the "userAgent" field.
+func UserAgentLTE(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldUserAgent, v))
+}
+
+// UserAgentContains applies the Contains predicate on the "userAgent" field.
+func UserAgentContains(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContains(FieldUserAgent, v))
+}
+
+// UserAgentHasPrefix applies the HasPrefix predicate on the "userAgent" field.
+func UserAgentHasPrefix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasPrefix(FieldUserAgent, v))
+}
+
+// UserAgentHasSuffix applies the HasSuffix predicate on the "userAgent" field.
+func UserAgentHasSuffix(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldHasSuffix(FieldUserAgent, v))
+}
+
+// UserAgentIsNil applies the IsNil predicate on the "userAgent" field.
+func UserAgentIsNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIsNull(FieldUserAgent))
+}
+
+// UserAgentNotNil applies the NotNil predicate on the "userAgent" field.
+func UserAgentNotNil() predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotNull(FieldUserAgent))
+}
+
+// UserAgentEqualFold applies the EqualFold predicate on the "userAgent" field.
+func UserAgentEqualFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEqualFold(FieldUserAgent, v))
+}
+
+// UserAgentContainsFold applies the ContainsFold predicate on the "userAgent" field.
+func UserAgentContainsFold(v string) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldContainsFold(FieldUserAgent, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "createdAt" field.
+func CreatedAtEQ(v time.Time) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "createdAt" field.
+func CreatedAtNEQ(v time.Time) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "createdAt" field.
+func CreatedAtIn(vs ...time.Time) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "createdAt" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "createdAt" field.
+func CreatedAtGT(v time.Time) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "createdAt" field.
+func CreatedAtGTE(v time.Time) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "createdAt" field.
+func CreatedAtLT(v time.Time) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "createdAt" field.
+func CreatedAtLTE(v time.Time) predicate.AuditLog {
+	return predicate.AuditLog(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.AuditLog) predicate.AuditLog {
+	return predicate.AuditLog(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.AuditLog) predicate.AuditLog {
+	return predicate.AuditLog(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.AuditLog) predicate.AuditLog {
+	return predicate.AuditLog(sql.NotPredicates(p))
+}
@@ -0,0 +1,1352 @@
+/*
+Copyright YEAR llmos.ai.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/auditlog"
+)
+
+// AuditLogCreate is the builder for creating a AuditLog entity.
+type AuditLogCreate struct {
+	config
+	mutation *AuditLogMutation
+	hooks    []Hook
+	conflict []sql.ConflictOption
+}
+
+// SetUserId sets the "userId" field.
+func (alc *AuditLogCreate) SetUserId(s string) *AuditLogCreate {
+	alc.mutation.SetUserId(s)
+	return alc
+}
+
+// SetNillableUserId sets the "userId" field if the given value is not nil.
+func (alc *AuditLogCreate) SetNillableUserId(s *string) *AuditLogCreate {
+	if s != nil {
+		alc.SetUserId(*s)
+	}
+	return alc
+}
+
+// SetTokenName sets the "tokenName" field.
+func (alc *AuditLogCreate) SetTokenName(s string) *AuditLogCreate {
+	alc.mutation.SetTokenName(s)
+	return alc
+}
+
+// SetNillableTokenName sets the "tokenName" field if the given value is not nil.
+func (alc *AuditLogCreate) SetNillableTokenName(s *string) *AuditLogCreate {
+	if s != nil {
+		alc.SetTokenName(*s)
+	}
+	return alc
+}
+
+// SetVerb sets the "verb" field.
+func (alc *AuditLogCreate) SetVerb(s string) *AuditLogCreate {
+	alc.mutation.SetVerb(s)
+	return alc
+}
+
+// SetResource sets the "resource" field.
+func (alc *AuditLogCreate) SetResource(s string) *AuditLogCreate {
+	alc.mutation.SetResource(s)
+	return alc
+}
+
+// SetNillableResource sets the "resource" field if the given value is not nil.
+func (alc *AuditLogCreate) SetNillableResource(s *string) *AuditLogCreate {
+	if s != nil {
+		alc.SetResource(*s)
+	}
+	return alc
+}
+
+// SetNamespace sets the "namespace" field.
+func (alc *AuditLogCreate) SetNamespace(s string) *AuditLogCreate {
+	alc.mutation.SetNamespace(s)
+	return alc
+}
+
+// SetNillableNamespace sets the "namespace" field if the given value is not nil.
+func (alc *AuditLogCreate) SetNillableNamespace(s *string) *AuditLogCreate {
+	if s != nil {
+		alc.SetNamespace(*s)
+	}
+	return alc
+}
+
+// SetName sets the "name" field.
+func (alc *AuditLogCreate) SetName(s string) *AuditLogCreate {
+	alc.mutation.SetName(s)
+	return alc
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (alc *AuditLogCreate) SetNillableName(s *string) *AuditLogCreate {
+	if s != nil {
+		alc.SetName(*s)
+	}
+	return alc
+}
+
+// SetMethod sets the "method" field.
+func (alc *AuditLogCreate) SetMethod(s string) *AuditLogCreate {
+	alc.mutation.SetMethod(s)
+	return alc
+}
+
+// SetPath sets the "path" field.
+func (alc *AuditLogCreate) SetPath(s string) *AuditLogCreate {
+	alc.mutation.SetPath(s)
+	return alc
+}
+
+// SetStatusCode sets the "statusCode" field.
+func (alc *AuditLogCreate) SetStatusCode(i int) *AuditLogCreate {
+	alc.mutation.SetStatusCode(i)
+	return alc
+}
+
+// SetLatencyMs sets the "latencyMs" field.
+func (alc *AuditLogCreate) SetLatencyMs(i int64) *AuditLogCreate {
+	alc.mutation.SetLatencyMs(i)
+	return alc
+}
+
+// SetSourceIP sets the "sourceIP" field.
+func (alc *AuditLogCreate) SetSourceIP(s string) *AuditLogCreate {
+	alc.mutation.SetSourceIP(s)
+	return alc
+}
+
+// SetNillableSourceIP sets the "sourceIP" field if the given value is not nil.
+func (alc *AuditLogCreate) SetNillableSourceIP(s *string) *AuditLogCreate {
+	if s != nil {
+		alc.SetSourceIP(*s)
+	}
+	return alc
+}
+
+// SetUserAgent sets the "userAgent" field.
+func (alc *AuditLogCreate) SetUserAgent(s string) *AuditLogCreate {
+	alc.mutation.SetUserAgent(s)
+	return alc
+}
+
+// SetNillableUserAgent sets the "userAgent" field if the given value is not nil.
+func (alc *AuditLogCreate) SetNillableUserAgent(s *string) *AuditLogCreate {
+	if s != nil {
+		alc.SetUserAgent(*s)
+	}
+	return alc
+}
+
+// SetCreatedAt sets the "createdAt" field.
+func (alc *AuditLogCreate) SetCreatedAt(t time.Time) *AuditLogCreate {
+	alc.mutation.SetCreatedAt(t)
+	return alc
+}
+
+// SetNillableCreatedAt sets the "createdAt" field if the given value is not nil.
+func (alc *AuditLogCreate) SetNillableCreatedAt(t *time.Time) *AuditLogCreate {
+	if t != nil {
+		alc.SetCreatedAt(*t)
+	}
+	return alc
+}
+
+// SetID sets the "id" field.
+func (alc *AuditLogCreate) SetID(u uuid.UUID) *AuditLogCreate {
+	alc.mutation.SetID(u)
+	return alc
+}
+
+// SetNillableID sets the "id" field if the given value is not nil.
+func (alc *AuditLogCreate) SetNillableID(u *uuid.UUID) *AuditLogCreate {
+	if u != nil {
+		alc.SetID(*u)
+	}
+	return alc
+}
+
+// Mutation returns the AuditLogMutation object of the builder.
+func (alc *AuditLogCreate) Mutation() *AuditLogMutation {
+	return alc.mutation
+}
+
+// Save creates the AuditLog in the database.
+func (alc *AuditLogCreate) Save(ctx context.Context) (*AuditLog, error) {
+	alc.defaults()
+	return withHooks(ctx, alc.sqlSave, alc.mutation, alc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (alc *AuditLogCreate) SaveX(ctx context.Context) *AuditLog {
+	v, err := alc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (alc *AuditLogCreate) Exec(ctx context.Context) error {
+	_, err := alc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (alc *AuditLogCreate) ExecX(ctx context.Context) {
+	if err := alc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (alc *AuditLogCreate) defaults() {
+	if _, ok := alc.mutation.CreatedAt(); !ok {
+		v := auditlog.DefaultCreatedAt()
+		alc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := alc.mutation.ID(); !ok {
+		v := auditlog.DefaultID()
+		alc.mutation.SetID(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (alc *AuditLogCreate) check() error {
+	if _, ok := alc.mutation.Verb(); !ok {
+		return &ValidationError{Name: "verb", err: errors.New(`ent: missing required field "AuditLog.verb"`)}
+	}
+	if _, ok := alc.mutation.Method(); !ok {
+		return &ValidationError{Name: "method", err: errors.New(`ent: missing required field "AuditLog.method"`)}
+	}
+	if _, ok := alc.mutation.Path(); !ok {
+		return &ValidationError{Name: "path", err: errors.New(`ent: missing required field "AuditLog.path"`)}
+	}
+	if _, ok := alc.mutation.StatusCode(); !ok {
+		return &ValidationError{Name: "statusCode", err: errors.New(`ent: missing required field "AuditLog.statusCode"`)}
+	}
+	if _, ok := alc.mutation.LatencyMs(); !ok {
+		return &ValidationError{Name: "latencyMs", err: errors.New(`ent: missing required field "AuditLog.latencyMs"`)}
+	}
+	if _, ok := alc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "createdAt", err: errors.New(`ent: missing required field "AuditLog.createdAt"`)}
+	}
+	return nil
+}
+
+func (alc *AuditLogCreate) sqlSave(ctx context.Context) (*AuditLog, error) {
+	if err := alc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := alc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, alc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(*uuid.UUID); ok {
+			_node.ID = *id
+		} else if err := _node.ID.Scan(_spec.ID.Value); err != nil {
+			return nil, err
+		}
+	}
+	alc.mutation.id = &_node.ID
+	alc.mutation.done = true
+	return _node, nil
+}
+
+func (alc *AuditLogCreate) createSpec() (*AuditLog, *sqlgraph.CreateSpec) {
+	var (
+		_node = &AuditLog{config: alc.config}
+		_spec = sqlgraph.NewCreateSpec(auditlog.Table, sqlgraph.NewFieldSpec(auditlog.FieldID, field.TypeUUID))
+	)
+	_spec.OnConflict = alc.conflict
+	if id, ok := alc.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = &id
+	}
+	if value, ok := alc.mutation.UserId(); ok {
+		_spec.SetField(auditlog.FieldUserId, field.TypeString, value)
+		_node.UserId = value
+	}
+	if value, ok := alc.mutation.TokenName(); ok {
+		_spec.SetField(auditlog.FieldTokenName, field.TypeString, value)
+		_node.TokenName = value
+	}
+	if value, ok := alc.mutation.Verb(); ok {
+		_spec.SetField(auditlog.FieldVerb, field.TypeString, value)
+		_node.Verb = value
+	}
+	if value, ok := alc.mutation.Resource(); ok {
+		_spec.SetField(auditlog.FieldResource, field.TypeString, value)
+		_node.Resource = value
+	}
+	if value, ok := alc.mutation.Namespace(); ok {
+		_spec.SetField(auditlog.FieldNamespace, field.TypeString, value)
+		_node.Namespace = value
+	}
+	if value, ok := alc.mutation.Name(); ok {
+		_spec.SetField(auditlog.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := alc.mutation.Method(); ok {
+		_spec.SetField(auditlog.FieldMethod, field.TypeString, value)
+		_node.Method = value
+	}
+	if value, ok := alc.mutation.Path(); ok {
+		_spec.SetField(auditlog.FieldPath, field.TypeString, value)
+		_node.Path = value
+	}
+	if value, ok := alc.mutation.StatusCode(); ok {
+		_spec.SetField(auditlog.FieldStatusCode, field.TypeInt, value)
+		_node.StatusCode = value
+	}
+	if value, ok := alc.mutation.LatencyMs(); ok {
+		_spec.SetField(auditlog.FieldLatencyMs, field.TypeInt64, value)
+		_node.LatencyMs = value
+	}
+	if value, ok := alc.mutation.SourceIP(); ok {
+		_spec.SetField(auditlog.FieldSourceIP, field.TypeString, value)
+		_node.SourceIP = value
+	}
+	if value, ok := alc.mutation.UserAgent(); ok {
+		_spec.SetField(auditlog.FieldUserAgent, field.TypeString, value)
+		_node.UserAgent = value
+	}
+	if value, ok := alc.mutation.CreatedAt(); ok {
+		_spec.SetField(auditlog.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.AuditLog.Create().
+//		SetUserId(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.AuditLogUpsert) {
+//			SetUserId(v+v).
+//		}).
+//		Exec(ctx)
+func (alc *AuditLogCreate) OnConflict(opts ...sql.ConflictOption) *AuditLogUpsertOne {
+	alc.conflict = opts
+	return &AuditLogUpsertOne{
+		create: alc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.AuditLog.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (alc *AuditLogCreate) OnConflictColumns(columns ...string) *AuditLogUpsertOne {
+	alc.conflict = append(alc.conflict, sql.ConflictColumns(columns...))
+	return &AuditLogUpsertOne{
+		create: alc,
+	}
+}
+
+type (
+	// AuditLogUpsertOne is the builder for "upsert"-ing
+	//  one AuditLog node.
+	AuditLogUpsertOne struct {
+		create *AuditLogCreate
+	}
+
+	// AuditLogUpsert is the "OnConflict" setter.
+	AuditLogUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetUserId sets the "userId" field.
+func (u *AuditLogUpsert) SetUserId(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldUserId, v)
+	return u
+}
+
+// UpdateUserId sets the "userId" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateUserId() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldUserId)
+	return u
+}
+
+// ClearUserId clears the value of the "userId" field.
+func (u *AuditLogUpsert) ClearUserId() *AuditLogUpsert {
+	u.SetNull(auditlog.FieldUserId)
+	return u
+}
+
+// SetTokenName sets the "tokenName" field.
+func (u *AuditLogUpsert) SetTokenName(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldTokenName, v)
+	return u
+}
+
+// UpdateTokenName sets the "tokenName" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateTokenName() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldTokenName)
+	return u
+}
+
+// ClearTokenName clears the value of the "tokenName" field.
+func (u *AuditLogUpsert) ClearTokenName() *AuditLogUpsert {
+	u.SetNull(auditlog.FieldTokenName)
+	return u
+}
+
+// SetVerb sets the "verb" field.
+func (u *AuditLogUpsert) SetVerb(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldVerb, v)
+	return u
+}
+
+// UpdateVerb sets the "verb" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateVerb() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldVerb)
+	return u
+}
+
+// SetResource sets the "resource" field.
+func (u *AuditLogUpsert) SetResource(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldResource, v)
+	return u
+}
+
+// UpdateResource sets the "resource" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateResource() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldResource)
+	return u
+}
+
+// ClearResource clears the value of the "resource" field.
+func (u *AuditLogUpsert) ClearResource() *AuditLogUpsert {
+	u.SetNull(auditlog.FieldResource)
+	return u
+}
+
+// SetNamespace sets the "namespace" field.
+func (u *AuditLogUpsert) SetNamespace(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldNamespace, v)
+	return u
+}
+
+// UpdateNamespace sets the "namespace" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateNamespace() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldNamespace)
+	return u
+}
+
+// ClearNamespace clears the value of the "namespace" field.
+func (u *AuditLogUpsert) ClearNamespace() *AuditLogUpsert {
+	u.SetNull(auditlog.FieldNamespace)
+	return u
+}
+
+// SetName sets the "name" field.
+func (u *AuditLogUpsert) SetName(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldName, v)
+	return u
+}
+
+// UpdateName sets the "name" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateName() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldName)
+	return u
+}
+
+// ClearName clears the value of the "name" field.
+func (u *AuditLogUpsert) ClearName() *AuditLogUpsert {
+	u.SetNull(auditlog.FieldName)
+	return u
+}
+
+// SetMethod sets the "method" field.
+func (u *AuditLogUpsert) SetMethod(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldMethod, v)
+	return u
+}
+
+// UpdateMethod sets the "method" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateMethod() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldMethod)
+	return u
+}
+
+// SetPath sets the "path" field.
+func (u *AuditLogUpsert) SetPath(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldPath, v)
+	return u
+}
+
+// UpdatePath sets the "path" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdatePath() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldPath)
+	return u
+}
+
+// SetStatusCode sets the "statusCode" field.
+func (u *AuditLogUpsert) SetStatusCode(v int) *AuditLogUpsert {
+	u.Set(auditlog.FieldStatusCode, v)
+	return u
+}
+
+// UpdateStatusCode sets the "statusCode" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateStatusCode() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldStatusCode)
+	return u
+}
+
+// AddStatusCode adds v to the "statusCode" field.
+func (u *AuditLogUpsert) AddStatusCode(v int) *AuditLogUpsert {
+	u.Add(auditlog.FieldStatusCode, v)
+	return u
+}
+
+// SetLatencyMs sets the "latencyMs" field.
+func (u *AuditLogUpsert) SetLatencyMs(v int64) *AuditLogUpsert {
+	u.Set(auditlog.FieldLatencyMs, v)
+	return u
+}
+
+// UpdateLatencyMs sets the "latencyMs" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateLatencyMs() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldLatencyMs)
+	return u
+}
+
+// AddLatencyMs adds v to the "latencyMs" field.
+func (u *AuditLogUpsert) AddLatencyMs(v int64) *AuditLogUpsert {
+	u.Add(auditlog.FieldLatencyMs, v)
+	return u
+}
+
+// SetSourceIP sets the "sourceIP" field.
+func (u *AuditLogUpsert) SetSourceIP(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldSourceIP, v)
+	return u
+}
+
+// UpdateSourceIP sets the "sourceIP" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateSourceIP() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldSourceIP)
+	return u
+}
+
+// ClearSourceIP clears the value of the "sourceIP" field.
+func (u *AuditLogUpsert) ClearSourceIP() *AuditLogUpsert {
+	u.SetNull(auditlog.FieldSourceIP)
+	return u
+}
+
+// SetUserAgent sets the "userAgent" field.
+func (u *AuditLogUpsert) SetUserAgent(v string) *AuditLogUpsert {
+	u.Set(auditlog.FieldUserAgent, v)
+	return u
+}
+
+// UpdateUserAgent sets the "userAgent" field to the value that was provided on create.
+func (u *AuditLogUpsert) UpdateUserAgent() *AuditLogUpsert {
+	u.SetExcluded(auditlog.FieldUserAgent)
+	return u
+}
+
+// ClearUserAgent clears the value of the "userAgent" field.
+func (u *AuditLogUpsert) ClearUserAgent() *AuditLogUpsert {
+	u.SetNull(auditlog.FieldUserAgent)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create except the ID field.
+// Using this option is equivalent to using:
+//
+//	client.AuditLog.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//			sql.ResolveWith(func(u *sql.UpdateSet) {
+//				u.SetIgnore(auditlog.FieldID)
+//			}),
+//		).
+//		Exec(ctx)
+func (u *AuditLogUpsertOne) UpdateNewValues() *AuditLogUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.ID(); exists {
+			s.SetIgnore(auditlog.FieldID)
+		}
+		if _, exists := u.create.mutation.CreatedAt(); exists {
+			s.SetIgnore(auditlog.FieldCreatedAt)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.AuditLog.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *AuditLogUpsertOne) Ignore() *AuditLogUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *AuditLogUpsertOne) DoNothing() *AuditLogUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the AuditLogCreate.OnConflict
+// documentation for more info.
+func (u *AuditLogUpsertOne) Update(set func(*AuditLogUpsert)) *AuditLogUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&AuditLogUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetUserId sets the "userId" field.
+func (u *AuditLogUpsertOne) SetUserId(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetUserId(v)
+	})
+}
+
+// UpdateUserId sets the "userId" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateUserId() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateUserId()
+	})
+}
+
+// ClearUserId clears the value of the "userId" field.
+func (u *AuditLogUpsertOne) ClearUserId() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearUserId()
+	})
+}
+
+// SetTokenName sets the "tokenName" field.
+func (u *AuditLogUpsertOne) SetTokenName(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetTokenName(v)
+	})
+}
+
+// UpdateTokenName sets the "tokenName" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateTokenName() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateTokenName()
+	})
+}
+
+// ClearTokenName clears the value of the "tokenName" field.
+func (u *AuditLogUpsertOne) ClearTokenName() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearTokenName()
+	})
+}
+
+// SetVerb sets the "verb" field.
+func (u *AuditLogUpsertOne) SetVerb(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetVerb(v)
+	})
+}
+
+// UpdateVerb sets the "verb" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateVerb() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateVerb()
+	})
+}
+
+// SetResource sets the "resource" field.
+func (u *AuditLogUpsertOne) SetResource(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetResource(v)
+	})
+}
+
+// UpdateResource sets the "resource" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateResource() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateResource()
+	})
+}
+
+// ClearResource clears the value of the "resource" field.
+func (u *AuditLogUpsertOne) ClearResource() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearResource()
+	})
+}
+
+// SetNamespace sets the "namespace" field.
+func (u *AuditLogUpsertOne) SetNamespace(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetNamespace(v)
+	})
+}
+
+// UpdateNamespace sets the "namespace" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateNamespace() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateNamespace()
+	})
+}
+
+// ClearNamespace clears the value of the "namespace" field.
+func (u *AuditLogUpsertOne) ClearNamespace() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearNamespace()
+	})
+}
+
+// SetName sets the "name" field.
+func (u *AuditLogUpsertOne) SetName(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetName(v)
+	})
+}
+
+// UpdateName sets the "name" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateName() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateName()
+	})
+}
+
+// ClearName clears the value of the "name" field.
+func (u *AuditLogUpsertOne) ClearName() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearName()
+	})
+}
+
+// SetMethod sets the "method" field.
+func (u *AuditLogUpsertOne) SetMethod(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetMethod(v)
+	})
+}
+
+// UpdateMethod sets the "method" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateMethod() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateMethod()
+	})
+}
+
+// SetPath sets the "path" field.
+func (u *AuditLogUpsertOne) SetPath(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetPath(v)
+	})
+}
+
+// UpdatePath sets the "path" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdatePath() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdatePath()
+	})
+}
+
+// SetStatusCode sets the "statusCode" field.
+func (u *AuditLogUpsertOne) SetStatusCode(v int) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetStatusCode(v)
+	})
+}
+
+// AddStatusCode adds v to the "statusCode" field.
+func (u *AuditLogUpsertOne) AddStatusCode(v int) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.AddStatusCode(v)
+	})
+}
+
+// UpdateStatusCode sets the "statusCode" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateStatusCode() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateStatusCode()
+	})
+}
+
+// SetLatencyMs sets the "latencyMs" field.
+func (u *AuditLogUpsertOne) SetLatencyMs(v int64) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetLatencyMs(v)
+	})
+}
+
+// AddLatencyMs adds v to the "latencyMs" field.
+func (u *AuditLogUpsertOne) AddLatencyMs(v int64) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.AddLatencyMs(v)
+	})
+}
+
+// UpdateLatencyMs sets the "latencyMs" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateLatencyMs() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateLatencyMs()
+	})
+}
+
+// SetSourceIP sets the "sourceIP" field.
+func (u *AuditLogUpsertOne) SetSourceIP(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetSourceIP(v)
+	})
+}
+
+// UpdateSourceIP sets the "sourceIP" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateSourceIP() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateSourceIP()
+	})
+}
+
+// ClearSourceIP clears the value of the "sourceIP" field.
+func (u *AuditLogUpsertOne) ClearSourceIP() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearSourceIP()
+	})
+}
+
+// SetUserAgent sets the "userAgent" field.
+func (u *AuditLogUpsertOne) SetUserAgent(v string) *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetUserAgent(v)
+	})
+}
+
+// UpdateUserAgent sets the "userAgent" field to the value that was provided on create.
+func (u *AuditLogUpsertOne) UpdateUserAgent() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateUserAgent()
+	})
+}
+
+// ClearUserAgent clears the value of the "userAgent" field.
+func (u *AuditLogUpsertOne) ClearUserAgent() *AuditLogUpsertOne {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearUserAgent()
+	})
+}
+
+// Exec executes the query.
+func (u *AuditLogUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("ent: missing options for AuditLogCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *AuditLogUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *AuditLogUpsertOne) ID(ctx context.Context) (id uuid.UUID, err error) {
+	if u.create.driver.Dialect() == dialect.MySQL {
+		// In case of "ON CONFLICT", there is no way to get back non-numeric ID
+		// fields from the database since MySQL does not support the RETURNING clause.
+		return id, errors.New("ent: AuditLogUpsertOne.ID is not supported by MySQL driver. Use AuditLogUpsertOne.Exec instead")
+	}
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *AuditLogUpsertOne) IDX(ctx context.Context) uuid.UUID {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// AuditLogCreateBulk is the builder for creating many AuditLog entities in bulk.
+type AuditLogCreateBulk struct {
+	config
+	err      error
+	builders []*AuditLogCreate
+	conflict []sql.ConflictOption
+}
+
+// Save creates the AuditLog entities in the database.
+func (alcb *AuditLogCreateBulk) Save(ctx context.Context) ([]*AuditLog, error) {
+	if alcb.err != nil {
+		return nil, alcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(alcb.builders))
+	nodes := make([]*AuditLog, len(alcb.builders))
+	mutators := make([]Mutator, len(alcb.builders))
+	for i := range alcb.builders {
+		func(i int, root context.Context) {
+			builder := alcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*AuditLogMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, alcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = alcb.conflict
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, alcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, alcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (alcb *AuditLogCreateBulk) SaveX(ctx context.Context) []*AuditLog {
+	v, err := alcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (alcb *AuditLogCreateBulk) Exec(ctx context.Context) error {
+	_, err := alcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (alcb *AuditLogCreateBulk) ExecX(ctx context.Context) {
+	if err := alcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.AuditLog.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.AuditLogUpsert) {
+//			SetUserId(v+v).
+//		}).
+//		Exec(ctx)
+func (alcb *AuditLogCreateBulk) OnConflict(opts ...sql.ConflictOption) *AuditLogUpsertBulk {
+	alcb.conflict = opts
+	return &AuditLogUpsertBulk{
+		create: alcb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.AuditLog.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (alcb *AuditLogCreateBulk) OnConflictColumns(columns ...string) *AuditLogUpsertBulk {
+	alcb.conflict = append(alcb.conflict, sql.ConflictColumns(columns...))
+	return &AuditLogUpsertBulk{
+		create: alcb,
+	}
+}
+
+// AuditLogUpsertBulk is the builder for "upsert"-ing
+// a bulk of AuditLog nodes.
+type AuditLogUpsertBulk struct {
+	create *AuditLogCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.AuditLog.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//			sql.ResolveWith(func(u *sql.UpdateSet) {
+//				u.SetIgnore(auditlog.FieldID)
+//			}),
+//		).
+//		Exec(ctx)
+func (u *AuditLogUpsertBulk) UpdateNewValues() *AuditLogUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.ID(); exists {
+				s.SetIgnore(auditlog.FieldID)
+			}
+			if _, exists := b.mutation.CreatedAt(); exists {
+				s.SetIgnore(auditlog.FieldCreatedAt)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.AuditLog.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *AuditLogUpsertBulk) Ignore() *AuditLogUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *AuditLogUpsertBulk) DoNothing() *AuditLogUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the AuditLogCreateBulk.OnConflict
+// documentation for more info.
+func (u *AuditLogUpsertBulk) Update(set func(*AuditLogUpsert)) *AuditLogUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&AuditLogUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetUserId sets the "userId" field.
+func (u *AuditLogUpsertBulk) SetUserId(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetUserId(v)
+	})
+}
+
+// UpdateUserId sets the "userId" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateUserId() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateUserId()
+	})
+}
+
+// ClearUserId clears the value of the "userId" field.
+func (u *AuditLogUpsertBulk) ClearUserId() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearUserId()
+	})
+}
+
+// SetTokenName sets the "tokenName" field.
+func (u *AuditLogUpsertBulk) SetTokenName(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetTokenName(v)
+	})
+}
+
+// UpdateTokenName sets the "tokenName" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateTokenName() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateTokenName()
+	})
+}
+
+// ClearTokenName clears the value of the "tokenName" field.
+func (u *AuditLogUpsertBulk) ClearTokenName() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearTokenName()
+	})
+}
+
+// SetVerb sets the "verb" field.
+func (u *AuditLogUpsertBulk) SetVerb(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetVerb(v)
+	})
+}
+
+// UpdateVerb sets the "verb" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateVerb() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateVerb()
+	})
+}
+
+// SetResource sets the "resource" field.
+func (u *AuditLogUpsertBulk) SetResource(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetResource(v)
+	})
+}
+
+// UpdateResource sets the "resource" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateResource() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateResource()
+	})
+}
+
+// ClearResource clears the value of the "resource" field.
+func (u *AuditLogUpsertBulk) ClearResource() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearResource()
+	})
+}
+
+// SetNamespace sets the "namespace" field.
+func (u *AuditLogUpsertBulk) SetNamespace(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetNamespace(v)
+	})
+}
+
+// UpdateNamespace sets the "namespace" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateNamespace() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateNamespace()
+	})
+}
+
+// ClearNamespace clears the value of the "namespace" field.
+func (u *AuditLogUpsertBulk) ClearNamespace() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearNamespace()
+	})
+}
+
+// SetName sets the "name" field.
+func (u *AuditLogUpsertBulk) SetName(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetName(v)
+	})
+}
+
+// UpdateName sets the "name" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateName() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateName()
+	})
+}
+
+// ClearName clears the value of the "name" field.
+func (u *AuditLogUpsertBulk) ClearName() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearName()
+	})
+}
+
+// SetMethod sets the "method" field.
+func (u *AuditLogUpsertBulk) SetMethod(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetMethod(v)
+	})
+}
+
+// UpdateMethod sets the "method" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateMethod() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateMethod()
+	})
+}
+
+// SetPath sets the "path" field.
+func (u *AuditLogUpsertBulk) SetPath(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetPath(v)
+	})
+}
+
+// UpdatePath sets the "path" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdatePath() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdatePath()
+	})
+}
+
+// SetStatusCode sets the "statusCode" field.
+func (u *AuditLogUpsertBulk) SetStatusCode(v int) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetStatusCode(v)
+	})
+}
+
+// AddStatusCode adds v to the "statusCode" field.
+func (u *AuditLogUpsertBulk) AddStatusCode(v int) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.AddStatusCode(v)
+	})
+}
+
+// UpdateStatusCode sets the "statusCode" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateStatusCode() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateStatusCode()
+	})
+}
+
+// SetLatencyMs sets the "latencyMs" field.
+func (u *AuditLogUpsertBulk) SetLatencyMs(v int64) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetLatencyMs(v)
+	})
+}
+
+// AddLatencyMs adds v to the "latencyMs" field.
+func (u *AuditLogUpsertBulk) AddLatencyMs(v int64) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.AddLatencyMs(v)
+	})
+}
+
+// UpdateLatencyMs sets the "latencyMs" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateLatencyMs() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateLatencyMs()
+	})
+}
+
+// SetSourceIP sets the "sourceIP" field.
+func (u *AuditLogUpsertBulk) SetSourceIP(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetSourceIP(v)
+	})
+}
+
+// UpdateSourceIP sets the "sourceIP" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateSourceIP() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateSourceIP()
+	})
+}
+
+// ClearSourceIP clears the value of the "sourceIP" field.
+func (u *AuditLogUpsertBulk) ClearSourceIP() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearSourceIP()
+	})
+}
+
+// SetUserAgent sets the "userAgent" field.
+func (u *AuditLogUpsertBulk) SetUserAgent(v string) *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.SetUserAgent(v)
+	})
+}
+
+// UpdateUserAgent sets the "userAgent" field to the value that was provided on create.
+func (u *AuditLogUpsertBulk) UpdateUserAgent() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.UpdateUserAgent()
+	})
+}
+
+// ClearUserAgent clears the value of the "userAgent" field.
+func (u *AuditLogUpsertBulk) ClearUserAgent() *AuditLogUpsertBulk {
+	return u.Update(func(s *AuditLogUpsert) {
+		s.ClearUserAgent()
+	})
+}
+
+// Exec executes the query.
+func (u *AuditLogUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("ent: OnConflict was set for builder %d. Set it on the AuditLogCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("ent: missing options for AuditLogCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *AuditLogUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
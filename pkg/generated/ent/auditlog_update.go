@@ -0,0 +1,763 @@
+/*
+Copyright YEAR llmos.ai.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/auditlog"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/predicate"
+)
+
+// AuditLogUpdate is the builder for updating AuditLog entities.
+type AuditLogUpdate struct {
+	config
+	hooks    []Hook
+	mutation *AuditLogMutation
+}
+
+// Where appends a list predicates to the AuditLogUpdate builder.
+func (alu *AuditLogUpdate) Where(ps ...predicate.AuditLog) *AuditLogUpdate {
+	alu.mutation.Where(ps...)
+	return alu
+}
+
+// SetUserId sets the "userId" field.
+func (alu *AuditLogUpdate) SetUserId(s string) *AuditLogUpdate {
+	alu.mutation.SetUserId(s)
+	return alu
+}
+
+// SetNillableUserId sets the "userId" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableUserId(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetUserId(*s)
+	}
+	return alu
+}
+
+// ClearUserId clears the value of the "userId" field.
+func (alu *AuditLogUpdate) ClearUserId() *AuditLogUpdate {
+	alu.mutation.ClearUserId()
+	return alu
+}
+
+// SetTokenName sets the "tokenName" field.
+func (alu *AuditLogUpdate) SetTokenName(s string) *AuditLogUpdate {
+	alu.mutation.SetTokenName(s)
+	return alu
+}
+
+// SetNillableTokenName sets the "tokenName" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableTokenName(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetTokenName(*s)
+	}
+	return alu
+}
+
+// ClearTokenName clears the value of the "tokenName" field.
+func (alu *AuditLogUpdate) ClearTokenName() *AuditLogUpdate {
+	alu.mutation.ClearTokenName()
+	return alu
+}
+
+// SetVerb sets the "verb" field.
+func (alu *AuditLogUpdate) SetVerb(s string) *AuditLogUpdate {
+	alu.mutation.SetVerb(s)
+	return alu
+}
+
+// SetNillableVerb sets the "verb" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableVerb(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetVerb(*s)
+	}
+	return alu
+}
+
+// SetResource sets the "resource" field.
+func (alu *AuditLogUpdate) SetResource(s string) *AuditLogUpdate {
+	alu.mutation.SetResource(s)
+	return alu
+}
+
+// SetNillableResource sets the "resource" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableResource(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetResource(*s)
+	}
+	return alu
+}
+
+// ClearResource clears the value of the "resource" field.
+func (alu *AuditLogUpdate) ClearResource() *AuditLogUpdate {
+	alu.mutation.ClearResource()
+	return alu
+}
+
+// SetNamespace sets the "namespace" field.
+func (alu *AuditLogUpdate) SetNamespace(s string) *AuditLogUpdate {
+	alu.mutation.SetNamespace(s)
+	return alu
+}
+
+// SetNillableNamespace sets the "namespace" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableNamespace(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetNamespace(*s)
+	}
+	return alu
+}
+
+// ClearNamespace clears the value of the "namespace" field.
+func (alu *AuditLogUpdate) ClearNamespace() *AuditLogUpdate {
+	alu.mutation.ClearNamespace()
+	return alu
+}
+
+// SetName sets the "name" field.
+func (alu *AuditLogUpdate) SetName(s string) *AuditLogUpdate {
+	alu.mutation.SetName(s)
+	return alu
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableName(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetName(*s)
+	}
+	return alu
+}
+
+// ClearName clears the value of the "name" field.
+func (alu *AuditLogUpdate) ClearName() *AuditLogUpdate {
+	alu.mutation.ClearName()
+	return alu
+}
+
+// SetMethod sets the "method" field.
+func (alu *AuditLogUpdate) SetMethod(s string) *AuditLogUpdate {
+	alu.mutation.SetMethod(s)
+	return alu
+}
+
+// SetNillableMethod sets the "method" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableMethod(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetMethod(*s)
+	}
+	return alu
+}
+
+// SetPath sets the "path" field.
+func (alu *AuditLogUpdate) SetPath(s string) *AuditLogUpdate {
+	alu.mutation.SetPath(s)
+	return alu
+}
+
+// SetNillablePath sets the "path" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillablePath(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetPath(*s)
+	}
+	return alu
+}
+
+// SetStatusCode sets the "statusCode" field.
+func (alu *AuditLogUpdate) SetStatusCode(i int) *AuditLogUpdate {
+	alu.mutation.ResetStatusCode()
+	alu.mutation.SetStatusCode(i)
+	return alu
+}
+
+// SetNillableStatusCode sets the "statusCode" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableStatusCode(i *int) *AuditLogUpdate {
+	if i != nil {
+		alu.SetStatusCode(*i)
+	}
+	return alu
+}
+
+// AddStatusCode adds i to the "statusCode" field.
+func (alu *AuditLogUpdate) AddStatusCode(i int) *AuditLogUpdate {
+	alu.mutation.AddStatusCode(i)
+	return alu
+}
+
+// SetLatencyMs sets the "latencyMs" field.
+func (alu *AuditLogUpdate) SetLatencyMs(i int64) *AuditLogUpdate {
+	alu.mutation.ResetLatencyMs()
+	alu.mutation.SetLatencyMs(i)
+	return alu
+}
+
+// SetNillableLatencyMs sets the "latencyMs" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableLatencyMs(i *int64) *AuditLogUpdate {
+	if i != nil {
+		alu.SetLatencyMs(*i)
+	}
+	return alu
+}
+
+// AddLatencyMs adds i to the "latencyMs" field.
+func (alu *AuditLogUpdate) AddLatencyMs(i int64) *AuditLogUpdate {
+	alu.mutation.AddLatencyMs(i)
+	return alu
+}
+
+// SetSourceIP sets the "sourceIP" field.
+func (alu *AuditLogUpdate) SetSourceIP(s string) *AuditLogUpdate {
+	alu.mutation.SetSourceIP(s)
+	return alu
+}
+
+// SetNillableSourceIP sets the "sourceIP" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableSourceIP(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetSourceIP(*s)
+	}
+	return alu
+}
+
+// ClearSourceIP clears the value of the "sourceIP" field.
+func (alu *AuditLogUpdate) ClearSourceIP() *AuditLogUpdate {
+	alu.mutation.ClearSourceIP()
+	return alu
+}
+
+// SetUserAgent sets the "userAgent" field.
+func (alu *AuditLogUpdate) SetUserAgent(s string) *AuditLogUpdate {
+	alu.mutation.SetUserAgent(s)
+	return alu
+}
+
+// SetNillableUserAgent sets the "userAgent" field if the given value is not nil.
+func (alu *AuditLogUpdate) SetNillableUserAgent(s *string) *AuditLogUpdate {
+	if s != nil {
+		alu.SetUserAgent(*s)
+	}
+	return alu
+}
+
+// ClearUserAgent clears the value of the "userAgent" field.
+func (alu *AuditLogUpdate) ClearUserAgent() *AuditLogUpdate {
+	alu.mutation.ClearUserAgent()
+	return alu
+}
+
+// Mutation returns the AuditLogMutation object of the builder.
+func (alu *AuditLogUpdate) Mutation() *AuditLogMutation {
+	return alu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (alu *AuditLogUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, alu.sqlSave, alu.mutation, alu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (alu *AuditLogUpdate) SaveX(ctx context.Context) int {
+	affected, err := alu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (alu *AuditLogUpdate) Exec(ctx context.Context) error {
+	_, err := alu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (alu *AuditLogUpdate) ExecX(ctx context.Context) {
+	if err := alu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (alu *AuditLogUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(auditlog.Table, auditlog.Columns, sqlgraph.NewFieldSpec(auditlog.FieldID, field.TypeUUID))
+	if ps := alu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := alu.mutation.UserId(); ok {
+		_spec.SetField(auditlog.FieldUserId, field.TypeString, value)
+	}
+	if alu.mutation.UserIdCleared() {
+		_spec.ClearField(auditlog.FieldUserId, field.TypeString)
+	}
+	if value, ok := alu.mutation.TokenName(); ok {
+		_spec.SetField(auditlog.FieldTokenName, field.TypeString, value)
+	}
+	if alu.mutation.TokenNameCleared() {
+		_spec.ClearField(auditlog.FieldTokenName, field.TypeString)
+	}
+	if value, ok := alu.mutation.Verb(); ok {
+		_spec.SetField(auditlog.FieldVerb, field.TypeString, value)
+	}
+	if value, ok := alu.mutation.Resource(); ok {
+		_spec.SetField(auditlog.FieldResource, field.TypeString, value)
+	}
+	if alu.mutation.ResourceCleared() {
+		_spec.ClearField(auditlog.FieldResource, field.TypeString)
+	}
+	if value, ok := alu.mutation.Namespace(); ok {
+		_spec.SetField(auditlog.FieldNamespace, field.TypeString, value)
+	}
+	if alu.mutation.NamespaceCleared() {
+		_spec.ClearField(auditlog.FieldNamespace, field.TypeString)
+	}
+	if value, ok := alu.mutation.Name(); ok {
+		_spec.SetField(auditlog.FieldName, field.TypeString, value)
+	}
+	if alu.mutation.NameCleared() {
+		_spec.ClearField(auditlog.FieldName, field.TypeString)
+	}
+	if value, ok := alu.mutation.Method(); ok {
+		_spec.SetField(auditlog.FieldMethod, field.TypeString, value)
+	}
+	if value, ok := alu.mutation.Path(); ok {
+		_spec.SetField(auditlog.FieldPath, field.TypeString, value)
+	}
+	if value, ok := alu.mutation.StatusCode(); ok {
+		_spec.SetField(auditlog.FieldStatusCode, field.TypeInt, value)
+	}
+	if value, ok := alu.mutation.AddedStatusCode(); ok {
+		_spec.AddField(auditlog.FieldStatusCode, field.TypeInt, value)
+	}
+	if value, ok := alu.mutation.LatencyMs(); ok {
+		_spec.SetField(auditlog.FieldLatencyMs, field.TypeInt64, value)
+	}
+	if value, ok := alu.mutation.AddedLatencyMs(); ok {
+		_spec.AddField(auditlog.FieldLatencyMs, field.TypeInt64, value)
+	}
+	if value, ok := alu.mutation.SourceIP(); ok {
+		_spec.SetField(auditlog.FieldSourceIP, field.TypeString, value)
+	}
+	if alu.mutation.SourceIPCleared() {
+		_spec.ClearField(auditlog.FieldSourceIP, field.TypeString)
+	}
+	if value, ok := alu.mutation.UserAgent(); ok {
+		_spec.SetField(auditlog.FieldUserAgent, field.TypeString, value)
+	}
+	if alu.mutation.UserAgentCleared() {
+		_spec.ClearField(auditlog.FieldUserAgent, field.TypeString)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, alu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{auditlog.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	alu.mutation.done = true
+	return n, nil
+}
+
+// AuditLogUpdateOne is the builder for updating a single AuditLog entity.
+type AuditLogUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *AuditLogMutation
+}
+
+// SetUserId sets the "userId" field.
+func (aluo *AuditLogUpdateOne) SetUserId(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetUserId(s)
+	return aluo
+}
+
+// SetNillableUserId sets the "userId" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableUserId(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetUserId(*s)
+	}
+	return aluo
+}
+
+// ClearUserId clears the value of the "userId" field.
+func (aluo *AuditLogUpdateOne) ClearUserId() *AuditLogUpdateOne {
+	aluo.mutation.ClearUserId()
+	return aluo
+}
+
+// SetTokenName sets the "tokenName" field.
+func (aluo *AuditLogUpdateOne) SetTokenName(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetTokenName(s)
+	return aluo
+}
+
+// SetNillableTokenName sets the "tokenName" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableTokenName(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetTokenName(*s)
+	}
+	return aluo
+}
+
+// ClearTokenName clears the value of the "tokenName" field.
+func (aluo *AuditLogUpdateOne) ClearTokenName() *AuditLogUpdateOne {
+	aluo.mutation.ClearTokenName()
+	return aluo
+}
+
+// SetVerb sets the "verb" field.
+func (aluo *AuditLogUpdateOne) SetVerb(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetVerb(s)
+	return aluo
+}
+
+// SetNillableVerb sets the "verb" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableVerb(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetVerb(*s)
+	}
+	return aluo
+}
+
+// SetResource sets the "resource" field.
+func (aluo *AuditLogUpdateOne) SetResource(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetResource(s)
+	return aluo
+}
+
+// SetNillableResource sets the "resource" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableResource(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetResource(*s)
+	}
+	return aluo
+}
+
+// ClearResource clears the value of the "resource" field.
+func (aluo *AuditLogUpdateOne) ClearResource() *AuditLogUpdateOne {
+	aluo.mutation.ClearResource()
+	return aluo
+}
+
+// SetNamespace sets the "namespace" field.
+func (aluo *AuditLogUpdateOne) SetNamespace(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetNamespace(s)
+	return aluo
+}
+
+// SetNillableNamespace sets the "namespace" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableNamespace(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetNamespace(*s)
+	}
+	return aluo
+}
+
+// ClearNamespace clears the value of the "namespace" field.
+func (aluo *AuditLogUpdateOne) ClearNamespace() *AuditLogUpdateOne {
+	aluo.mutation.ClearNamespace()
+	return aluo
+}
+
+// SetName sets the "name" field.
+func (aluo *AuditLogUpdateOne) SetName(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetName(s)
+	return aluo
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableName(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetName(*s)
+	}
+	return aluo
+}
+
+// ClearName clears the value of the "name" field.
+func (aluo *AuditLogUpdateOne) ClearName() *AuditLogUpdateOne {
+	aluo.mutation.ClearName()
+	return aluo
+}
+
+// SetMethod sets the "method" field.
+func (aluo *AuditLogUpdateOne) SetMethod(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetMethod(s)
+	return aluo
+}
+
+// SetNillableMethod sets the "method" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableMethod(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetMethod(*s)
+	}
+	return aluo
+}
+
+// SetPath sets the "path" field.
+func (aluo *AuditLogUpdateOne) SetPath(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetPath(s)
+	return aluo
+}
+
+// SetNillablePath sets the "path" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillablePath(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetPath(*s)
+	}
+	return aluo
+}
+
+// SetStatusCode sets the "statusCode" field.
+func (aluo *AuditLogUpdateOne) SetStatusCode(i int) *AuditLogUpdateOne {
+	aluo.mutation.ResetStatusCode()
+	aluo.mutation.SetStatusCode(i)
+	return aluo
+}
+
+// SetNillableStatusCode sets the "statusCode" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableStatusCode(i *int) *AuditLogUpdateOne {
+	if i != nil {
+		aluo.SetStatusCode(*i)
+	}
+	return aluo
+}
+
+// AddStatusCode adds i to the "statusCode" field.
+func (aluo *AuditLogUpdateOne) AddStatusCode(i int) *AuditLogUpdateOne {
+	aluo.mutation.AddStatusCode(i)
+	return aluo
+}
+
+// SetLatencyMs sets the "latencyMs" field.
+func (aluo *AuditLogUpdateOne) SetLatencyMs(i int64) *AuditLogUpdateOne {
+	aluo.mutation.ResetLatencyMs()
+	aluo.mutation.SetLatencyMs(i)
+	return aluo
+}
+
+// SetNillableLatencyMs sets the "latencyMs" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableLatencyMs(i *int64) *AuditLogUpdateOne {
+	if i != nil {
+		aluo.SetLatencyMs(*i)
+	}
+	return aluo
+}
+
+// AddLatencyMs adds i to the "latencyMs" field.
+func (aluo *AuditLogUpdateOne) AddLatencyMs(i int64) *AuditLogUpdateOne {
+	aluo.mutation.AddLatencyMs(i)
+	return aluo
+}
+
+// SetSourceIP sets the "sourceIP" field.
+func (aluo *AuditLogUpdateOne) SetSourceIP(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetSourceIP(s)
+	return aluo
+}
+
+// SetNillableSourceIP sets the "sourceIP" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableSourceIP(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetSourceIP(*s)
+	}
+	return aluo
+}
+
+// ClearSourceIP clears the value of the "sourceIP" field.
+func (aluo *AuditLogUpdateOne) ClearSourceIP() *AuditLogUpdateOne {
+	aluo.mutation.ClearSourceIP()
+	return aluo
+}
+
+// SetUserAgent sets the "userAgent" field.
+func (aluo *AuditLogUpdateOne) SetUserAgent(s string) *AuditLogUpdateOne {
+	aluo.mutation.SetUserAgent(s)
+	return aluo
+}
+
+// SetNillableUserAgent sets the "userAgent" field if the given value is not nil.
+func (aluo *AuditLogUpdateOne) SetNillableUserAgent(s *string) *AuditLogUpdateOne {
+	if s != nil {
+		aluo.SetUserAgent(*s)
+	}
+	return aluo
+}
+
+// ClearUserAgent clears the value of the "userAgent" field.
+func (aluo *AuditLogUpdateOne) ClearUserAgent() *AuditLogUpdateOne {
+	aluo.mutation.ClearUserAgent()
+	return aluo
+}
+
+// Mutation returns the AuditLogMutation object of the builder.
+func (aluo *AuditLogUpdateOne) Mutation() *AuditLogMutation {
+	return aluo.mutation
+}
+
+// Where appends a list predicates to the AuditLogUpdate builder.
+func (aluo *AuditLogUpdateOne) Where(ps ...predicate.AuditLog) *AuditLogUpdateOne {
+	aluo.mutation.Where(ps...)
+	return aluo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (aluo *AuditLogUpdateOne) Select(field string, fields ...string) *AuditLogUpdateOne {
+	aluo.fields = append([]string{field}, fields...)
+	return aluo
+}
+
+// Save executes the query and returns the updated AuditLog entity.
+func (aluo *AuditLogUpdateOne) Save(ctx context.Context) (*AuditLog, error) {
+	return withHooks(ctx, aluo.sqlSave, aluo.mutation, aluo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (aluo *AuditLogUpdateOne) SaveX(ctx context.Context) *AuditLog {
+	node, err := aluo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (aluo *AuditLogUpdateOne) Exec(ctx context.Context) error {
+	_, err := aluo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (aluo *AuditLogUpdateOne) ExecX(ctx context.Context) {
+	if err := aluo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (aluo *AuditLogUpdateOne) sqlSave(ctx context.Context) (_node *AuditLog, err error) {
+	_spec := sqlgraph.NewUpdateSpec(auditlog.Table, auditlog.Columns, sqlgraph.NewFieldSpec(auditlog.FieldID, field.TypeUUID))
+	id, ok := aluo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "AuditLog.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := aluo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, auditlog.FieldID)
+		for _, f := range fields {
+			if !auditlog.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != auditlog.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := aluo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := aluo.mutation.UserId(); ok {
+		_spec.SetField(auditlog.FieldUserId, field.TypeString, value)
+	}
+	if aluo.mutation.UserIdCleared() {
+		_spec.ClearField(auditlog.FieldUserId, field.TypeString)
+	}
+	if value, ok := aluo.mutation.TokenName(); ok {
+		_spec.SetField(auditlog.FieldTokenName, field.TypeString, value)
+	}
+	if aluo.mutation.TokenNameCleared() {
+		_spec.ClearField(auditlog.FieldTokenName, field.TypeString)
+	}
+	if value, ok := aluo.mutation.Verb(); ok {
+		_spec.SetField(auditlog.FieldVerb, field.TypeString, value)
+	}
+	if value, ok := aluo.mutation.Resource(); ok {
+		_spec.SetField(auditlog.FieldResource, field.TypeString, value)
+	}
+	if aluo.mutation.ResourceCleared() {
+		_spec.ClearField(auditlog.FieldResource, field.TypeString)
+	}
+	if value, ok := aluo.mutation.Namespace(); ok {
+		_spec.SetField(auditlog.FieldNamespace, field.TypeString, value)
+	}
+	if aluo.mutation.NamespaceCleared() {
+		_spec.ClearField(auditlog.FieldNamespace, field.TypeString)
+	}
+	if value, ok := aluo.mutation.Name(); ok {
+		_spec.SetField(auditlog.FieldName, field.TypeString, value)
+	}
+	if aluo.mutation.NameCleared() {
+		_spec.ClearField(auditlog.FieldName, field.TypeString)
+	}
+	if value, ok := aluo.mutation.Method(); ok {
+		_spec.SetField(auditlog.FieldMethod, field.TypeString, value)
+	}
+	if value, ok := aluo.mutation.Path(); ok {
+		_spec.SetField(auditlog.FieldPath, field.TypeString, value)
+	}
+	if value, ok := aluo.mutation.StatusCode(); ok {
+		_spec.SetField(auditlog.FieldStatusCode, field.TypeInt, value)
+	}
+	if value, ok := aluo.mutation.AddedStatusCode(); ok {
+		_spec.AddField(auditlog.FieldStatusCode, field.TypeInt, value)
+	}
+	if value, ok := aluo.mutation.LatencyMs(); ok {
+		_spec.SetField(auditlog.FieldLatencyMs, field.TypeInt64, value)
+	}
+	if value, ok := aluo.mutation.AddedLatencyMs(); ok {
+		_spec.AddField(auditlog.FieldLatencyMs, field.TypeInt64, value)
+	}
+	if value, ok := aluo.mutation.SourceIP(); ok {
+		_spec.SetField(auditlog.FieldSourceIP, field.TypeString, value)
+	}
+	if aluo.mutation.SourceIPCleared() {
+		_spec.ClearField(auditlog.FieldSourceIP, field.TypeString)
+	}
+	if value, ok := aluo.mutation.UserAgent(); ok {
+		_spec.SetField(auditlog.FieldUserAgent, field.TypeString, value)
+	}
+	if aluo.mutation.UserAgentCleared() {
+		_spec.ClearField(auditlog.FieldUserAgent, field.TypeString)
+	}
+	_node = &AuditLog{config: aluo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, aluo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{auditlog.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	aluo.mutation.done = true
+	return _node, nil
+}
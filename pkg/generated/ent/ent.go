@@ -26,6 +26,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/auditlog"
 	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/chat"
 )
 
@@ -87,7 +88,8 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			chat.Table: chat.ValidColumn,
+			auditlog.Table: auditlog.ValidColumn,
+			chat.Table:     chat.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
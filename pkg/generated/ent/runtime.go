@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/auditlog"
 	"github.com/llmos-ai/llmos-operator/pkg/generated/ent/chat"
 	v1 "github.com/llmos-ai/llmos-operator/pkg/types/v1"
 )
@@ -28,6 +29,16 @@ import (
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	auditlogFields := v1.AuditLog{}.Fields()
+	_ = auditlogFields
+	// auditlogDescCreatedAt is the schema descriptor for createdAt field.
+	auditlogDescCreatedAt := auditlogFields[13].Descriptor()
+	// auditlog.DefaultCreatedAt holds the default value on creation for the createdAt field.
+	auditlog.DefaultCreatedAt = auditlogDescCreatedAt.Default.(func() time.Time)
+	// auditlogDescID is the schema descriptor for id field.
+	auditlogDescID := auditlogFields[0].Descriptor()
+	// auditlog.DefaultID holds the default value on creation for the id field.
+	auditlog.DefaultID = auditlogDescID.Default.(func() uuid.UUID)
 	chatFields := v1.Chat{}.Fields()
 	_ = chatFields
 	// chatDescTitle is the schema descriptor for title field.
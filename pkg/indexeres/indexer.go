@@ -7,6 +7,8 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/lineage"
 	sconfig "github.com/llmos-ai/llmos-operator/pkg/server/config"
 )
 
@@ -14,6 +16,9 @@ const (
 	UserNameIndex               = "management.llmos.ai/user-username-index"
 	TokenNameIndex              = "management.llmos.ai/token-name-index"
 	ClusterRoleBindingNameIndex = "management.llmos.ai/crb-by-role-and-subject-index"
+	LineageEdgeSourceIndex      = "ml.llmos.ai/lineage-edge-by-source-index"
+	LineageEdgeTargetIndex      = "ml.llmos.ai/lineage-edge-by-target-index"
+	ModelServiceAdapterIndex    = "ml.llmos.ai/model-service-by-adapter-model-index"
 )
 
 func Register(ctx context.Context, _ *steve.Controllers, _ sconfig.Options) error {
@@ -22,10 +27,15 @@ func Register(ctx context.Context, _ *steve.Controllers, _ sconfig.Options) erro
 	crbInformer := mgmt.RbacFactory.Rbac().V1().ClusterRoleBinding().Cache()
 	userInformer := mgmt.MgmtFactory.Management().V1().User().Cache()
 	tokenInformer := mgmt.MgmtFactory.Management().V1().Token().Cache()
+	lineageEdgeInformer := mgmt.LLMFactory.Ml().V1().LineageEdge().Cache()
+	modelServiceInformer := mgmt.LLMFactory.Ml().V1().ModelService().Cache()
 
 	crbInformer.AddIndexer(ClusterRoleBindingNameIndex, rbByRoleAndSubject)
 	userInformer.AddIndexer(UserNameIndex, indexUserByUsername)
 	tokenInformer.AddIndexer(TokenNameIndex, tokenKeyIndexer)
+	lineageEdgeInformer.AddIndexer(LineageEdgeSourceIndex, lineageEdgeBySource)
+	lineageEdgeInformer.AddIndexer(LineageEdgeTargetIndex, lineageEdgeByTarget)
+	modelServiceInformer.AddIndexer(ModelServiceAdapterIndex, modelServiceByAdapterModel)
 	return nil
 }
 
@@ -37,6 +47,23 @@ func tokenKeyIndexer(token *mgmtv1.Token) ([]string, error) {
 	return []string{token.Name}, nil
 }
 
+func lineageEdgeBySource(obj *mlv1.LineageEdge) ([]string, error) {
+	return []string{lineage.Key(obj.Spec.Source)}, nil
+}
+
+func lineageEdgeByTarget(obj *mlv1.LineageEdge) ([]string, error) {
+	return []string{lineage.Key(obj.Spec.Target)}, nil
+}
+
+// modelServiceByAdapterModel indexes the model services by the namespaced names of their adapter models
+func modelServiceByAdapterModel(obj *mlv1.ModelService) ([]string, error) {
+	keys := make([]string, 0, len(obj.Spec.Adapters))
+	for _, adapter := range obj.Spec.Adapters {
+		keys = append(keys, obj.Namespace+"/"+adapter.Model)
+	}
+	return keys, nil
+}
+
 func rbByRoleAndSubject(obj *rbacv1.ClusterRoleBinding) ([]string, error) {
 	keys := make([]string, len(obj.Subjects))
 	for _, s := range obj.Subjects {
@@ -0,0 +1,159 @@
+// Package lineage builds the lineage graph between the datasets, models and model services from the
+// LineageEdge objects.
+package lineage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+const (
+	KindDatasetVersion    = "DatasetVersion"
+	KindModel             = "Model"
+	KindLocalModelVersion = "LocalModelVersion"
+	KindModelService      = "ModelService"
+
+	DirectionUpstream   = "upstream"
+	DirectionDownstream = "downstream"
+	DirectionBoth       = "both"
+
+	DefaultDepth = 5
+	MaxDepth     = 20
+)
+
+// EdgeGetter returns the edges from or to the object of the key
+type EdgeGetter func(key string) ([]*mlv1.LineageEdge, error)
+
+type Graph struct {
+	Root  mlv1.LineageObjectReference   `json:"root"`
+	Nodes []mlv1.LineageObjectReference `json:"nodes"`
+	Edges []mlv1.LineageEdgeSpec        `json:"edges"`
+}
+
+// Key returns the key of the object in the lineage graph, the UID is ignored to link the objects referenced
+// by name
+func Key(ref mlv1.LineageObjectReference) string {
+	return fmt.Sprintf("%s/%s/%s", ref.Kind, ref.Namespace, ref.Name)
+}
+
+// Reference returns the lineage reference of the object
+func Reference(kind string, obj metav1.Object) mlv1.LineageObjectReference {
+	return mlv1.LineageObjectReference{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		UID:       obj.GetUID(),
+	}
+}
+
+// EdgeName returns the name of the edge object, which is unique for the type, source and target
+func EdgeName(edgeType mlv1.LineageEdgeType, source, target mlv1.LineageObjectReference) string {
+	hash := sha256.Sum256([]byte(Key(source) + "->" + Key(target)))
+	return fmt.Sprintf("%s-%s", strings.ToLower(string(edgeType)), hex.EncodeToString(hash[:])[:16])
+}
+
+// NewEdge returns the edge from the source to the target object, which is owned by the target object
+func NewEdge(edgeType mlv1.LineageEdgeType, source mlv1.LineageObjectReference, target metav1.Object,
+	targetKind string) *mlv1.LineageEdge {
+	targetRef := Reference(targetKind, target)
+	return &mlv1.LineageEdge{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EdgeName(edgeType, source, targetRef),
+			Namespace: target.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: mlv1.SchemeGroupVersion.String(),
+					Kind:       targetKind,
+					Name:       target.GetName(),
+					UID:        target.GetUID(),
+				},
+			},
+		},
+		Spec: mlv1.LineageEdgeSpec{
+			Type:   edgeType,
+			Source: source,
+			Target: targetRef,
+		},
+	}
+}
+
+// BuildGraph walks the edges from the root object in the direction until the depth is reached, the upstream
+// and downstream objects are walked separately when both directions are requested
+func BuildGraph(root mlv1.LineageObjectReference, direction string, depth int, upstream,
+	downstream EdgeGetter) (*Graph, error) {
+	if depth <= 0 {
+		depth = DefaultDepth
+	}
+	depth = min(depth, MaxDepth)
+
+	nodes := map[string]mlv1.LineageObjectReference{Key(root): root}
+	edges := make(map[string]mlv1.LineageEdgeSpec)
+	if direction != DirectionDownstream {
+		if err := walk(root, depth, upstream, true, nodes, edges); err != nil {
+			return nil, err
+		}
+	}
+	if direction != DirectionUpstream {
+		if err := walk(root, depth, downstream, false, nodes, edges); err != nil {
+			return nil, err
+		}
+	}
+
+	graph := &Graph{
+		Root:  root,
+		Nodes: make([]mlv1.LineageObjectReference, 0, len(nodes)),
+		Edges: make([]mlv1.LineageEdgeSpec, 0, len(edges)),
+	}
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for _, edge := range edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return Key(graph.Nodes[i]) < Key(graph.Nodes[j]) })
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		ei, ej := graph.Edges[i], graph.Edges[j]
+		if Key(ei.Source) != Key(ej.Source) {
+			return Key(ei.Source) < Key(ej.Source)
+		}
+		return Key(ei.Target) < Key(ej.Target)
+	})
+	return graph, nil
+}
+
+func walk(root mlv1.LineageObjectReference, depth int, getEdges EdgeGetter, isUpstream bool,
+	nodes map[string]mlv1.LineageObjectReference, edges map[string]mlv1.LineageEdgeSpec) error {
+	visited := map[string]bool{Key(root): true}
+	queue := []string{Key(root)}
+	for level := 0; level < depth && len(queue) > 0; level++ {
+		var next []string
+		for _, key := range queue {
+			found, err := getEdges(key)
+			if err != nil {
+				return err
+			}
+			for _, e := range found {
+				edges[fmt.Sprintf("%s:%s->%s", e.Spec.Type, Key(e.Spec.Source), Key(e.Spec.Target))] = e.Spec
+				ref := e.Spec.Target
+				if isUpstream {
+					ref = e.Spec.Source
+				}
+				if visited[Key(ref)] {
+					continue
+				}
+				visited[Key(ref)] = true
+				nodes[Key(ref)] = ref
+				next = append(next, Key(ref))
+			}
+		}
+		queue = next
+	}
+	return nil
+}
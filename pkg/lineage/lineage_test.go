@@ -0,0 +1,136 @@
+package lineage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+)
+
+func ref(kind, name string) mlv1.LineageObjectReference {
+	return mlv1.LineageObjectReference{Kind: kind, Namespace: "default", Name: name}
+}
+
+func edge(edgeType mlv1.LineageEdgeType, source, target mlv1.LineageObjectReference) *mlv1.LineageEdge {
+	return &mlv1.LineageEdge{Spec: mlv1.LineageEdgeSpec{Type: edgeType, Source: source, Target: target}}
+}
+
+// dv-a -copy-> dv-b -train-> model -download-> lmv
+//
+//	model -serve-> ms
+func newEdgeGetters() (EdgeGetter, EdgeGetter) {
+	dvA, dvB := ref(KindDatasetVersion, "dv-a"), ref(KindDatasetVersion, "dv-b")
+	model, lmv, ms := ref(KindModel, "model"), ref(KindLocalModelVersion, "lmv"), ref(KindModelService, "ms")
+	edges := []*mlv1.LineageEdge{
+		edge(mlv1.LineageEdgeTypeCopy, dvA, dvB),
+		edge(mlv1.LineageEdgeTypeTrain, dvB, model),
+		edge(mlv1.LineageEdgeTypeDownload, model, lmv),
+		edge(mlv1.LineageEdgeTypeServe, model, ms),
+	}
+
+	upstream := func(key string) ([]*mlv1.LineageEdge, error) {
+		var result []*mlv1.LineageEdge
+		for _, e := range edges {
+			if Key(e.Spec.Target) == key {
+				result = append(result, e)
+			}
+		}
+		return result, nil
+	}
+	downstream := func(key string) ([]*mlv1.LineageEdge, error) {
+		var result []*mlv1.LineageEdge
+		for _, e := range edges {
+			if Key(e.Spec.Source) == key {
+				result = append(result, e)
+			}
+		}
+		return result, nil
+	}
+	return upstream, downstream
+}
+
+func nodeNames(graph *Graph) []string {
+	names := make([]string, 0, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+func TestBuildGraph(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		root          mlv1.LineageObjectReference
+		direction     string
+		depth         int
+		expectedNodes []string
+		expectedEdges int
+	}{
+		{
+			name:          "upstream of model service",
+			root:          ref(KindModelService, "ms"),
+			direction:     DirectionUpstream,
+			expectedNodes: []string{"dv-a", "dv-b", "model", "ms"},
+			expectedEdges: 3,
+		},
+		{
+			name:          "upstream of model service with depth",
+			root:          ref(KindModelService, "ms"),
+			direction:     DirectionUpstream,
+			depth:         2,
+			expectedNodes: []string{"dv-b", "model", "ms"},
+			expectedEdges: 2,
+		},
+		{
+			name:          "downstream of dataset version",
+			root:          ref(KindDatasetVersion, "dv-b"),
+			direction:     DirectionDownstream,
+			expectedNodes: []string{"dv-b", "lmv", "model", "ms"},
+			expectedEdges: 3,
+		},
+		{
+			name:          "both directions of model",
+			root:          ref(KindModel, "model"),
+			direction:     DirectionBoth,
+			expectedNodes: []string{"dv-a", "dv-b", "lmv", "model", "ms"},
+			expectedEdges: 4,
+		},
+		{
+			name:          "object without lineage",
+			root:          ref(KindModel, "other"),
+			direction:     DirectionBoth,
+			expectedNodes: []string{"other"},
+			expectedEdges: 0,
+		},
+	}
+
+	upstream, downstream := newEdgeGetters()
+	for _, tc := range testCases {
+		graph, err := BuildGraph(tc.root, tc.direction, tc.depth, upstream, downstream)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.root, graph.Root, tc.name)
+		assert.Equal(t, tc.expectedNodes, nodeNames(graph), tc.name)
+		assert.Len(t, graph.Edges, tc.expectedEdges, tc.name)
+	}
+}
+
+func TestNewEdge(t *testing.T) {
+	source := ref(KindDatasetVersion, "dv-a")
+	target := &mlv1.Model{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "model", UID: "uid-1"}}
+
+	e := NewEdge(mlv1.LineageEdgeTypeTrain, source, target, KindModel)
+
+	assert.Equal(t, "team-a", e.Namespace)
+	assert.Equal(t, EdgeName(mlv1.LineageEdgeTypeTrain, source, e.Spec.Target), e.Name)
+	assert.Equal(t, mlv1.LineageObjectReference{Kind: KindModel, Namespace: "team-a", Name: "model", UID: "uid-1"},
+		e.Spec.Target)
+	assert.Len(t, e.OwnerReferences, 1)
+	assert.Equal(t, KindModel, e.OwnerReferences[0].Kind)
+	assert.Equal(t, target.UID, e.OwnerReferences[0].UID)
+
+	// the name doesn't depend on the UID, so the edge is reused if the target is recreated
+	target.UID = "uid-2"
+	assert.Equal(t, e.Name, NewEdge(mlv1.LineageEdgeTypeTrain, source, target, KindModel).Name)
+}
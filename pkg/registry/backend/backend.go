@@ -51,6 +51,9 @@ type Uploader interface {
 // Downloader defines the interface for downloading data
 type Downloader interface {
 	Download(ctx context.Context, src string, rw io.Writer) error
+	// DownloadRange downloads length bytes of the file starting at the offset, it's used to read part of
+	// a large file, e.g., the footer of a parquet file
+	DownloadRange(ctx context.Context, src string, offset, length int64, rw io.Writer) error
 	IncrementalDownload(ctx context.Context, targetDir, outputDir string, concurrency int) error
 	// GeneratePresignedDownloadURL generates a presigned URL for direct download from storage
 	GeneratePresignedDownloadURL(ctx context.Context, objectName string, expiry time.Duration) (string, error)
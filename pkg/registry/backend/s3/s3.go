@@ -258,6 +258,25 @@ func (mc *MinioClient) Download(ctx context.Context, src string, rw io.Writer) e
 	return mc.downloadDirectory(ctx, src, files, rw)
 }
 
+func (mc *MinioClient) DownloadRange(ctx context.Context, src string, offset, length int64, rw io.Writer) error {
+	if offset < 0 || length <= 0 {
+		return fmt.Errorf("invalid range offset %d length %d", offset, length)
+	}
+
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return fmt.Errorf("set range failed: %w", err)
+	}
+	object, err := mc.client.GetObject(ctx, mc.bucket, src, opts)
+	if err != nil {
+		return err
+	}
+	defer object.Close() //nolint:errcheck
+
+	_, err = io.Copy(rw, object)
+	return err
+}
+
 func (mc *MinioClient) download(ctx context.Context, objectName string, writer io.Writer) error {
 	object, err := mc.client.GetObject(ctx, mc.bucket, objectName, minio.GetObjectOptions{})
 	if err != nil {
@@ -35,6 +35,9 @@ func (r *Router) Routes() http.Handler {
 	// public auth handler
 	authHandler := auth.NewAuthHandler(r.scaled)
 	m.Path("/v1-public/auth").Handler(authHandler)
+	m.Path("/v1-public/auth/providers").Methods(http.MethodGet).HandlerFunc(authHandler.ListProviders)
+	m.Path("/v1-public/auth/{provider}/login").Methods(http.MethodGet).HandlerFunc(authHandler.ProviderLogin)
+	m.Path("/v1-public/auth/{provider}/callback").Methods(http.MethodGet).HandlerFunc(authHandler.ProviderCallback)
 
 	modelsProxyHandler := proxy.NewModelsHandler()
 	m.PathPrefix("/proxy/models").Handler(modelsProxyHandler)
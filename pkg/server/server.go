@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"net/http"
 
 	dlserver "github.com/rancher/dynamiclistener/server"
 	"github.com/rancher/lasso/pkg/controller"
@@ -14,7 +15,9 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/llmos-ai/llmos-operator/pkg/api"
+	"github.com/llmos-ai/llmos-operator/pkg/audit"
 	"github.com/llmos-ai/llmos-operator/pkg/auth"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/global"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master"
 	"github.com/llmos-ai/llmos-operator/pkg/data"
@@ -140,6 +143,11 @@ func (s *APIServer) setDefaults(opts sconfig.Options) error {
 	// Define the auth middleware
 	auth := auth.NewMiddleware(s.scaled)
 
+	// Define the audit logger recording the mutating requests, the requested resources are parsed once the
+	// requests are authenticated
+	auditLogger := audit.NewDefaultLogger(s.scaled.Management)
+	go auditLogger.Run(s.ctx)
+
 	// Wait for webhooks to be registered before proceeding controller operations
 	if err = WaitingWebhooks(s.ctx, s.clientSet, opts.ReleaseName); err != nil {
 		return err
@@ -154,12 +162,17 @@ func (s *APIServer) setDefaults(opts sconfig.Options) error {
 		Controllers:     s.controllers,
 		Next:            r.Routes(),
 		AccessSetLookup: asl,
-		AuthMiddleware:  auth.AuthMiddleware,
+		AuthMiddleware: func(next http.Handler) http.Handler {
+			return auditLogger.Middleware(auth.AuthMiddleware(next))
+		},
 	})
 	if err != nil {
 		return err
 	}
 
+	// the scopes of the API keys are checked against the resources of the steve schemas
+	tokens.WatchSchemaResources(s.ctx, s.steveServer.SchemaFactory)
+
 	return nil
 }
 
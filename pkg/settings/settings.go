@@ -42,26 +42,95 @@ var (
 	ProxyAppsServerUrl        = NewSetting(ProxyAppsServerUrlName, "http://llmos-agents-langflow-backend.llmos-agents:7860")
 	ProxyVectorServerUrl      = NewSetting(ProxyVectorDBServerUrlName, "http://weaviate.llmos-agents:80")
 	ModelDownloaderImage      = NewSetting(ModelDownloaderImageName, "ghcr.io/llmos-ai/llmos-operator-downloader:main-head")
+	// NotebookIdleTimeoutMinutes stops the idle notebooks after the timeout, 0 means idle culling is disabled
+	NotebookIdleTimeoutMinutes = NewSetting(NotebookIdleTimeoutMinutesName, "0")
+	// SnapshotStorageClass and VolumeSnapshotClass are discovered from the cluster if not specified
+	SnapshotStorageClass = NewSetting(SnapshotStorageClassName, "")
+	VolumeSnapshotClass  = NewSetting(VolumeSnapshotClassName, "")
+	// SnapshotRestoreAccessMode options are ReadWriteOnce and ReadOnlyMany, ReadOnlyMany requires the CSI driver support
+	SnapshotRestoreAccessMode = NewSetting(SnapshotRestoreAccessModeName, "ReadWriteOnce")
+	// DatasetVersionSigningEnabled signs the manifests of the published dataset versions with the cluster key
+	DatasetVersionSigningEnabled = NewSetting(DatasetVersionSigningEnabledName, "false")
+	// DataCollectionAllowedCIDRs are the comma separated private networks the data collection sources can connect to
+	DataCollectionAllowedCIDRs = NewSetting(DataCollectionAllowedCIDRsName, "")
+
+	// AuthOIDCConfig is the JSON config of the OIDC auth provider, the client secret is read from the secret in the
+	// system namespace
+	AuthOIDCConfig = NewSetting(AuthOIDCConfigName, "")
+	// AuthLDAPConfig is the JSON config of the LDAP auth provider, the service account password is read from the
+	// secret in the system namespace
+	AuthLDAPConfig = NewSetting(AuthLDAPConfigName, "")
+
+	// PasswordMinLength is the minimum length of the passwords of the local users
+	PasswordMinLength = NewSetting(PasswordMinLengthName, "8")
+	// PasswordRequireComplexity requires the passwords to contain upper and lower case letters, digits and symbols
+	PasswordRequireComplexity = NewSetting(PasswordRequireComplexityName, "false")
+	// PasswordHistoryCount is the number of the previous passwords that can't be reused, 0 disables the check
+	PasswordHistoryCount = NewSetting(PasswordHistoryCountName, "0")
+	// PasswordMaxAgeDays requires the users to change the passwords older than the days, 0 means never expire
+	PasswordMaxAgeDays = NewSetting(PasswordMaxAgeDaysName, "0")
+	// AuthLoginMaxFailedAttempts locks out the users after the consecutive failed logins, 0 disables the lockout
+	AuthLoginMaxFailedAttempts = NewSetting(AuthLoginMaxFailedAttemptsName, "5")
+	// AuthLoginLockoutMinutes is how long the locked out users are not allowed to log in
+	AuthLoginLockoutMinutes = NewSetting(AuthLoginLockoutMinutesName, "15")
+	// AuthMFARequired requires all local users to enroll the TOTP multi-factor authentication
+	AuthMFARequired = NewSetting(AuthMFARequiredName, "false")
+	// AuthUserSessionIdleTimeoutMinutes expires the UI sessions not used for the minutes, 0 disables the idle timeout
+	AuthUserSessionIdleTimeoutMinutes = NewSetting(AuthUserSessionIdleTimeoutMinutesName, "0")
+	// AuthTrustedProxies is the comma separated IPs or CIDRs of the proxies in front of the server, the client IPs
+	// are read from the X-Forwarded-For and X-Real-IP headers only if the requests come from the proxies
+	AuthTrustedProxies = NewSetting(AuthTrustedProxiesName, "")
+	// ServiceAccountKeyMaxTTLMinutes is the max TTL of the API keys of the service accounts, the keys must be rotated
+	// before they expire
+	ServiceAccountKeyMaxTTLMinutes = NewSetting(ServiceAccountKeyMaxTTLMinutesName, "43200") // 30 days
+
+	// AuditLogEnabled records the mutating API requests to the database and the audit log webhook
+	AuditLogEnabled = NewSetting(AuditLogEnabledName, "true")
+	// AuditLogWebhookURL is the url the audit records are posted to in batches, the webhook sink is disabled if empty
+	AuditLogWebhookURL = NewSetting(AuditLogWebhookURLName, "")
+	// AuditLogRetentionDays deletes the audit records older than the days from the database, 0 keeps them forever
+	AuditLogRetentionDays = NewSetting(AuditLogRetentionDaysName, "90")
 )
 
 const (
-	UIPlSettingName                  = "ui-pl"
-	UISourceSettingName              = "ui-source"
-	FirstLoginSettingName            = "first-login"
-	DatabaseUrlSettingName           = "database-url"
-	DefaultNotebookImagesSettingName = "default-notebook-images"
-	ServerVersionName                = "server-version"
-	UpgradeCheckEnabledName          = "upgrade-check-enabled"
-	UpgradeCheckUrlName              = "upgrade-check-url"
-	LogLevelSettingName              = "log-level"
-	ManagedAddonConfigsName          = "managed-addon-configs"
-	ModelServiceDefaultImageName     = "model-service-default-image"
-	RayClusterDefaultVersionName     = "ray-cluster-default-version"
-	GlobalSystemImageRegistryName    = "global-system-image-registry"
-	HuggingFaceEndpointName          = "huggingface-endpoint"
-	ProxyAppsServerUrlName           = "proxy-apps-server-url"
-	ProxyVectorDBServerUrlName       = "proxy-vector-db-server-url"
-	ModelDownloaderImageName         = "model-downloader-image"
+	UIPlSettingName                       = "ui-pl"
+	UISourceSettingName                   = "ui-source"
+	FirstLoginSettingName                 = "first-login"
+	DatabaseUrlSettingName                = "database-url"
+	DefaultNotebookImagesSettingName      = "default-notebook-images"
+	ServerVersionName                     = "server-version"
+	UpgradeCheckEnabledName               = "upgrade-check-enabled"
+	UpgradeCheckUrlName                   = "upgrade-check-url"
+	LogLevelSettingName                   = "log-level"
+	ManagedAddonConfigsName               = "managed-addon-configs"
+	ModelServiceDefaultImageName          = "model-service-default-image"
+	RayClusterDefaultVersionName          = "ray-cluster-default-version"
+	GlobalSystemImageRegistryName         = "global-system-image-registry"
+	HuggingFaceEndpointName               = "huggingface-endpoint"
+	ProxyAppsServerUrlName                = "proxy-apps-server-url"
+	ProxyVectorDBServerUrlName            = "proxy-vector-db-server-url"
+	ModelDownloaderImageName              = "model-downloader-image"
+	NotebookIdleTimeoutMinutesName        = "notebook-idle-timeout-minutes"
+	SnapshotStorageClassName              = "snapshot-storage-class"
+	VolumeSnapshotClassName               = "volume-snapshot-class"
+	SnapshotRestoreAccessModeName         = "snapshot-restore-access-mode"
+	DatasetVersionSigningEnabledName      = "dataset-version-signing-enabled"
+	DataCollectionAllowedCIDRsName        = "data-collection-allowed-cidrs"
+	AuthOIDCConfigName                    = "auth-oidc-config"
+	AuthLDAPConfigName                    = "auth-ldap-config"
+	PasswordMinLengthName                 = "password-min-length"
+	PasswordRequireComplexityName         = "password-require-complexity"
+	PasswordHistoryCountName              = "password-history-count"
+	PasswordMaxAgeDaysName                = "password-max-age-days"
+	AuthLoginMaxFailedAttemptsName        = "auth-login-max-failed-attempts"
+	AuthLoginLockoutMinutesName           = "auth-login-lockout-minutes"
+	AuthMFARequiredName                   = "auth-mfa-required"
+	AuthUserSessionIdleTimeoutMinutesName = "auth-user-session-idle-timeout-minutes"
+	AuthTrustedProxiesName                = "auth-trusted-proxies"
+	ServiceAccountKeyMaxTTLMinutesName    = "service-account-key-max-ttl-minutes"
+	AuditLogEnabledName                   = "audit-log-enabled"
+	AuditLogWebhookURLName                = "audit-log-webhook-url"
+	AuditLogRetentionDaysName             = "audit-log-retention-days"
 )
 
 func init() {
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/google/uuid"
+)
+
+// AuditLog holds the schema definition for the AuditLog entity, which records a mutating API request.
+type AuditLog struct {
+	ent.Schema
+}
+
+// Fields of the AuditLog.
+func (AuditLog) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New).Unique(),
+		field.String("userId").StorageKey("user_id").Optional(),
+		field.String("tokenName").StorageKey("token_name").Optional(),
+		field.String("verb"),
+		field.String("resource").Optional(),
+		field.String("namespace").Optional(),
+		field.String("name").Optional(),
+		field.String("method"),
+		field.String("path"),
+		field.Int("statusCode").StorageKey("status_code"),
+		field.Int64("latencyMs").StorageKey("latency_ms"),
+		field.String("sourceIP").StorageKey("source_ip").Optional(),
+		field.String("userAgent").StorageKey("user_agent").Optional(),
+		field.Time("createdAt").StorageKey("created_at").Default(time.Now).Immutable(),
+	}
+}
+
+func (AuditLog) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("createdAt"),
+		index.Fields("userId"),
+	}
+}
@@ -0,0 +1,66 @@
+package fakeclients
+
+import (
+	"context"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/clientset/versioned/typed/ml.llmos.ai/v1"
+)
+
+type LocalModelCache func(string) ctlmlv1.LocalModelInterface
+
+func (c LocalModelCache) Get(namespace, name string) (*mlv1.LocalModel, error) {
+	return c(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func (c LocalModelCache) List(namespace string, selector labels.Selector) ([]*mlv1.LocalModel, error) {
+	list, err := c(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*mlv1.LocalModel, 0, len(list.Items))
+	for _, item := range list.Items {
+		obj := item
+		result = append(result, &obj)
+	}
+	return result, nil
+}
+
+func (c LocalModelCache) AddIndexer(_ string, _ generic.Indexer[*mlv1.LocalModel]) {
+	panic("implement me")
+}
+
+func (c LocalModelCache) GetByIndex(_, _ string) ([]*mlv1.LocalModel, error) {
+	panic("implement me")
+}
+
+type LocalModelVersionCache func(string) ctlmlv1.LocalModelVersionInterface
+
+func (c LocalModelVersionCache) Get(namespace, name string) (*mlv1.LocalModelVersion, error) {
+	return c(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func (c LocalModelVersionCache) List(namespace string, selector labels.Selector) ([]*mlv1.LocalModelVersion, error) {
+	list, err := c(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*mlv1.LocalModelVersion, 0, len(list.Items))
+	for _, item := range list.Items {
+		obj := item
+		result = append(result, &obj)
+	}
+	return result, nil
+}
+
+func (c LocalModelVersionCache) AddIndexer(_ string, _ generic.Indexer[*mlv1.LocalModelVersion]) {
+	panic("implement me")
+}
+
+func (c LocalModelVersionCache) GetByIndex(_, _ string) ([]*mlv1.LocalModelVersion, error) {
+	panic("implement me")
+}
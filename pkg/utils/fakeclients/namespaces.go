@@ -0,0 +1,38 @@
+package fakeclients
+
+import (
+	"context"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1type "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type NamespaceCache func() corev1type.NamespaceInterface
+
+func (c NamespaceCache) Get(name string) (*v1.Namespace, error) {
+	return c().Get(context.TODO(), name, metav1.GetOptions{})
+}
+func (c NamespaceCache) List(selector labels.Selector) ([]*v1.Namespace, error) {
+	list, err := c().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*v1.Namespace, 0, len(list.Items))
+	for _, item := range list.Items {
+		obj := item
+		result = append(result, &obj)
+	}
+	return result, err
+}
+
+func (c NamespaceCache) AddIndexer(_ string, _ generic.Indexer[*v1.Namespace]) {
+	panic("implement me")
+}
+func (c NamespaceCache) GetByIndex(_, key string) ([]*v1.Namespace, error) {
+	panic("implement me")
+}
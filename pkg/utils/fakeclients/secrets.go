@@ -0,0 +1,51 @@
+package fakeclients
+
+import (
+	"context"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1type "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+type SecretClient func(string) corev1type.SecretInterface
+
+func (p SecretClient) Create(secret *v1.Secret) (*v1.Secret, error) {
+	return p(secret.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+}
+
+func (p SecretClient) Update(secret *v1.Secret) (*v1.Secret, error) {
+	return p(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+}
+
+func (p SecretClient) UpdateStatus(secret *v1.Secret) (*v1.Secret, error) {
+	return p(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+}
+
+func (p SecretClient) Delete(namespace, name string, options *metav1.DeleteOptions) error {
+	return p(namespace).Delete(context.TODO(), name, *options)
+}
+
+func (p SecretClient) Get(namespace, name string, options metav1.GetOptions) (*v1.Secret, error) {
+	return p(namespace).Get(context.TODO(), name, options)
+}
+
+func (p SecretClient) List(namespace string, opts metav1.ListOptions) (*v1.SecretList, error) {
+	return p(namespace).List(context.TODO(), opts)
+}
+
+func (p SecretClient) Watch(namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return p(namespace).Watch(context.TODO(), opts)
+}
+
+func (p SecretClient) Patch(namespace, name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.Secret, err error) {
+	return p(namespace).Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}, subresources...)
+}
+
+func (p SecretClient) WithImpersonation(_ rest.ImpersonationConfig) (generic.ClientInterface[*v1.Secret, *v1.SecretList], error) {
+	panic("implement me")
+}
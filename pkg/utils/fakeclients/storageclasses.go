@@ -0,0 +1,38 @@
+package fakeclients
+
+import (
+	"context"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	storagev1type "k8s.io/client-go/kubernetes/typed/storage/v1"
+)
+
+type StorageClassCache func() storagev1type.StorageClassInterface
+
+func (c StorageClassCache) Get(name string) (*storagev1.StorageClass, error) {
+	return c().Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func (c StorageClassCache) List(selector labels.Selector) ([]*storagev1.StorageClass, error) {
+	list, err := c().List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*storagev1.StorageClass, 0, len(list.Items))
+	for _, item := range list.Items {
+		obj := item
+		result = append(result, &obj)
+	}
+	return result, nil
+}
+
+func (c StorageClassCache) AddIndexer(_ string, _ generic.Indexer[*storagev1.StorageClass]) {
+	panic("implement me")
+}
+
+func (c StorageClassCache) GetByIndex(_, _ string) ([]*storagev1.StorageClass, error) {
+	panic("implement me")
+}
@@ -0,0 +1,51 @@
+package fakeclients
+
+import (
+	"context"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/clientset/versioned/typed/management.llmos.ai/v1"
+)
+
+type User func() ctlmgmtv1.UserInterface
+
+func (u User) Create(user *mgmtv1.User) (*mgmtv1.User, error) {
+	return u().Create(context.TODO(), user, metav1.CreateOptions{})
+}
+
+func (u User) Update(user *mgmtv1.User) (*mgmtv1.User, error) {
+	return u().Update(context.TODO(), user, metav1.UpdateOptions{})
+}
+
+func (u User) UpdateStatus(user *mgmtv1.User) (*mgmtv1.User, error) {
+	return u().UpdateStatus(context.TODO(), user, metav1.UpdateOptions{})
+}
+
+func (u User) Delete(name string, opts *metav1.DeleteOptions) error {
+	return u().Delete(context.TODO(), name, *opts)
+}
+func (u User) Get(name string, options metav1.GetOptions) (*mgmtv1.User, error) {
+	return u().Get(context.TODO(), name, options)
+}
+
+func (u User) List(opts metav1.ListOptions) (*mgmtv1.UserList, error) {
+	return u().List(context.TODO(), opts)
+}
+
+func (u User) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	return u().Watch(context.TODO(), opts)
+}
+
+func (u User) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *mgmtv1.User, err error) {
+	return u().Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}, subresources...)
+}
+
+func (u User) WithImpersonation(_ rest.ImpersonationConfig) (generic.NonNamespacedClientInterface[*mgmtv1.User, *mgmtv1.UserList], error) {
+	panic("implement me")
+}
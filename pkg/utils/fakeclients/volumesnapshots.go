@@ -0,0 +1,66 @@
+package fakeclients
+
+import (
+	"context"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	ctlsnapshotv1 "github.com/llmos-ai/llmos-operator/pkg/generated/clientset/versioned/typed/snapshot.storage.k8s.io/v1"
+)
+
+type VolumeSnapshotCache func(string) ctlsnapshotv1.VolumeSnapshotInterface
+
+func (c VolumeSnapshotCache) Get(namespace, name string) (*snapshotv1.VolumeSnapshot, error) {
+	return c(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func (c VolumeSnapshotCache) List(namespace string, selector labels.Selector) ([]*snapshotv1.VolumeSnapshot, error) {
+	list, err := c(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*snapshotv1.VolumeSnapshot, 0, len(list.Items))
+	for _, item := range list.Items {
+		obj := item
+		result = append(result, &obj)
+	}
+	return result, nil
+}
+
+func (c VolumeSnapshotCache) AddIndexer(_ string, _ generic.Indexer[*snapshotv1.VolumeSnapshot]) {
+	panic("implement me")
+}
+
+func (c VolumeSnapshotCache) GetByIndex(_, _ string) ([]*snapshotv1.VolumeSnapshot, error) {
+	panic("implement me")
+}
+
+type VolumeSnapshotClassCache func() ctlsnapshotv1.VolumeSnapshotClassInterface
+
+func (c VolumeSnapshotClassCache) Get(name string) (*snapshotv1.VolumeSnapshotClass, error) {
+	return c().Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func (c VolumeSnapshotClassCache) List(selector labels.Selector) ([]*snapshotv1.VolumeSnapshotClass, error) {
+	list, err := c().List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*snapshotv1.VolumeSnapshotClass, 0, len(list.Items))
+	for _, item := range list.Items {
+		obj := item
+		result = append(result, &obj)
+	}
+	return result, nil
+}
+
+func (c VolumeSnapshotClassCache) AddIndexer(_ string, _ generic.Indexer[*snapshotv1.VolumeSnapshotClass]) {
+	panic("implement me")
+}
+
+func (c VolumeSnapshotClassCache) GetByIndex(_, _ string) ([]*snapshotv1.VolumeSnapshotClass, error) {
+	panic("implement me")
+}
@@ -15,6 +15,7 @@ import (
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai"
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai"
 	rayv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ray.io"
+	snapshotv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/snapshot.storage.k8s.io"
 	storagev1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/storage.k8s.io"
 	"github.com/llmos-ai/llmos-operator/pkg/server/config"
 )
@@ -24,14 +25,15 @@ type Management struct {
 	ReleaseName string
 	RestConfig  *rest.Config
 
-	MgmtFactory    *mgmtv1.Factory
-	LLMFactory     *mlv1.Factory
-	CoreFactory    *corev1.Factory
-	AppsFactory    *appsv1.Factory
-	RayFactory     *rayv1.Factory
-	HelmFactory    *helmv1.Factory
-	StorageFactory *storagev1.Factory
-	starters       []start.Starter
+	MgmtFactory     *mgmtv1.Factory
+	LLMFactory      *mlv1.Factory
+	CoreFactory     *corev1.Factory
+	AppsFactory     *appsv1.Factory
+	RayFactory      *rayv1.Factory
+	HelmFactory     *helmv1.Factory
+	StorageFactory  *storagev1.Factory
+	SnapshotFactory *snapshotv1.Factory
+	starters        []start.Starter
 }
 
 func SetupManagement(ctx context.Context, restConfig *rest.Config, releaseName string) (*Management, error) {
@@ -97,6 +99,13 @@ func SetupManagement(ctx context.Context, restConfig *rest.Config, releaseName s
 	mgmt.StorageFactory = storage
 	mgmt.starters = append(mgmt.starters, storage)
 
+	snapshot, err := snapshotv1.NewFactoryFromConfigWithOptions(restConfig, factoryOpts)
+	if err != nil {
+		return nil, err
+	}
+	mgmt.SnapshotFactory = snapshot
+	mgmt.starters = append(mgmt.starters, snapshot)
+
 	return mgmt, nil
 }
 
@@ -9,17 +9,24 @@ import (
 	"k8s.io/client-go/rest"
 
 	wconfig "github.com/llmos-ai/llmos-operator/pkg/webhook/config"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/batchinferencejob"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/datacollection"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/dataset"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/datasetversion"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/finetunejob"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/group"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/helmchart"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/localmodel"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/localmodelversion"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/managedaddon"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/model"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/modelbenchmark"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/modelservice"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/namespace"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/notebook"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/notebookprofile"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/raycluster"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/roletemplatebinding"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/upgrade"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/resources/user"
 )
@@ -29,6 +36,7 @@ func register(mgmt *wconfig.Management) (validators []admission.Validator, mutat
 		user.NewValidator(mgmt),
 		raycluster.NewValidator(mgmt),
 		notebook.NewValidator(mgmt),
+		notebookprofile.NewValidator(),
 		upgrade.NewValidator(mgmt),
 		helmchart.NewValidator(),
 		managedaddon.NewValidator(),
@@ -38,12 +46,19 @@ func register(mgmt *wconfig.Management) (validators []admission.Validator, mutat
 		datasetversion.NewValidator(mgmt),
 		localmodelversion.NewValidator(mgmt),
 		localmodel.NewValidator(mgmt),
+		modelservice.NewValidator(),
+		modelbenchmark.NewValidator(mgmt),
+		finetunejob.NewValidator(mgmt),
+		batchinferencejob.NewValidator(mgmt),
+		datacollection.NewValidator(),
+		roletemplatebinding.NewValidator(mgmt),
+		group.NewValidator(mgmt),
 	}
 
 	mutators = []admission.Mutator{
 		user.NewMutator(),
 		raycluster.NewMutator(mgmt),
-		notebook.NewMutator(),
+		notebook.NewMutator(mgmt),
 		modelservice.NewMutator(),
 		datasetversion.NewMutator(mgmt),
 		localmodelversion.NewMutator(mgmt),
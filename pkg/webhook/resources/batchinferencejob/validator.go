@@ -0,0 +1,116 @@
+package batchinferencejob
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/batchinference"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/config"
+	werror "github.com/llmos-ai/llmos-operator/pkg/webhook/error"
+)
+
+type validator struct {
+	admission.DefaultValidator
+
+	modelServiceCache   ctlmlv1.ModelServiceCache
+	datasetCache        ctlmlv1.DatasetCache
+	datasetVersionCache ctlmlv1.DatasetVersionCache
+}
+
+var _ admission.Validator = &validator{}
+
+func NewValidator(mgmt *config.Management) admission.Validator {
+	return &validator{
+		modelServiceCache:   mgmt.LLMFactory.Ml().V1().ModelService().Cache(),
+		datasetCache:        mgmt.LLMFactory.Ml().V1().Dataset().Cache(),
+		datasetVersionCache: mgmt.LLMFactory.Ml().V1().DatasetVersion().Cache(),
+	}
+}
+
+func (v *validator) Create(_ *admission.Request, obj runtime.Object) error {
+	bij := obj.(*mlv1.BatchInferenceJob)
+
+	if _, err := v.modelServiceCache.Get(bij.Namespace, bij.Spec.ModelService); err != nil {
+		return werror.BadRequest(fmt.Sprintf("failed to get model service %s/%s: %v",
+			bij.Namespace, bij.Spec.ModelService, err))
+	}
+
+	input := bij.Spec.Input
+	if _, err := v.datasetVersionCache.Get(bij.Namespace, input.DatasetVersion); err != nil {
+		return werror.BadRequest(fmt.Sprintf("failed to get dataset version %s/%s: %v",
+			bij.Namespace, input.DatasetVersion, err))
+	}
+	if file := path.Clean(input.File); input.File == "" || path.IsAbs(file) || strings.HasPrefix(file, "..") {
+		return werror.BadRequest(fmt.Sprintf("invalid input file %q, must be a relative path in the dataset "+
+			"version", input.File))
+	}
+	if _, err := batchinference.NewPromptBuilder(input.PromptTemplate, input.PromptField); err != nil {
+		return werror.BadRequest(err.Error())
+	}
+
+	output := bij.Spec.Output
+	dataset, err := v.datasetCache.Get(bij.Namespace, output.Dataset)
+	if err != nil {
+		return werror.BadRequest(fmt.Sprintf("failed to get dataset %s/%s: %v", bij.Namespace, output.Dataset, err))
+	}
+	for _, version := range dataset.Status.Versions {
+		if version.Version == output.Version {
+			return werror.BadRequest(fmt.Sprintf("version %s of dataset %s/%s already exists", output.Version,
+				bij.Namespace, output.Dataset))
+		}
+	}
+	// the output dataset version is named after the dataset and the version
+	if errs := validation.IsDNS1123Subdomain(output.Dataset + "-" + output.Version); len(errs) > 0 {
+		return werror.BadRequest(fmt.Sprintf("invalid output version %q: %s", output.Version,
+			strings.Join(errs, ", ")))
+	}
+
+	if bij.Spec.Temperature != "" {
+		if f, err := strconv.ParseFloat(bij.Spec.Temperature, 64); err != nil || f < 0 {
+			return werror.BadRequest(fmt.Sprintf("invalid temperature %q, must be a non-negative decimal",
+				bij.Spec.Temperature))
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) Update(_ *admission.Request, oldObj runtime.Object, newObj runtime.Object) error {
+	oldBIJ := oldObj.(*mlv1.BatchInferenceJob)
+	newBIJ := newObj.(*mlv1.BatchInferenceJob)
+
+	if newBIJ.DeletionTimestamp != nil {
+		return nil
+	}
+
+	// the written outputs depend on the spec, create a new job to run with different settings
+	if !reflect.DeepEqual(oldBIJ.Spec, newBIJ.Spec) {
+		return werror.MethodNotAllowed("spec of the batch inference job is immutable")
+	}
+
+	return nil
+}
+
+func (v *validator) Resource() admission.Resource {
+	return admission.Resource{
+		Names:      []string{"batchinferencejobs"},
+		Scope:      admissionregv1.NamespacedScope,
+		APIGroup:   mlv1.SchemeGroupVersion.Group,
+		APIVersion: mlv1.SchemeGroupVersion.Version,
+		ObjectType: &mlv1.BatchInferenceJob{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
@@ -0,0 +1,141 @@
+package datacollection
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	"github.com/robfig/cron/v3"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	agentv1 "github.com/llmos-ai/llmos-operator/pkg/apis/agent.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/datacollection"
+	werror "github.com/llmos-ai/llmos-operator/pkg/webhook/error"
+)
+
+type validator struct {
+	admission.DefaultValidator
+}
+
+var _ admission.Validator = &validator{}
+
+func NewValidator() admission.Validator {
+	return &validator{}
+}
+
+func (v *validator) Create(_ *admission.Request, obj runtime.Object) error {
+	dc := obj.(*agentv1.DataCollection)
+
+	return validateSources(dc.Spec.Sources)
+}
+
+func (v *validator) Update(_ *admission.Request, _ runtime.Object, newObj runtime.Object) error {
+	dc := newObj.(*agentv1.DataCollection)
+
+	if dc.DeletionTimestamp != nil {
+		return nil
+	}
+
+	return validateSources(dc.Spec.Sources)
+}
+
+func validateSources(sources []agentv1.DataSource) error {
+	names := map[string]bool{}
+	for _, source := range sources {
+		// the files of the source are written to the directory named after the source
+		if errs := validation.IsDNS1123Label(source.Name); len(errs) > 0 {
+			return werror.BadRequest(fmt.Sprintf("invalid source name %q: %s", source.Name, strings.Join(errs, ", ")))
+		}
+		if names[source.Name] {
+			return werror.BadRequest(fmt.Sprintf("duplicate source name %s", source.Name))
+		}
+		names[source.Name] = true
+
+		if source.Schedule != "" {
+			if _, err := cron.ParseStandard(source.Schedule); err != nil {
+				return werror.BadRequest(fmt.Sprintf("invalid schedule %q of source %s: %v", source.Schedule,
+					source.Name, err))
+			}
+		}
+
+		if err := validateSource(source); err != nil {
+			return werror.BadRequest(fmt.Sprintf("invalid source %s: %v", source.Name, err))
+		}
+	}
+
+	return nil
+}
+
+func validateSource(source agentv1.DataSource) error {
+	count := 0
+	for _, specified := range []bool{source.URL != nil, source.Git != nil, source.S3 != nil} {
+		if specified {
+			count++
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("exactly one of url, git and s3 is required")
+	}
+
+	switch {
+	case source.URL != nil:
+		if len(source.URL.URLs) == 0 && source.URL.Sitemap == "" {
+			return fmt.Errorf("urls or sitemap is required")
+		}
+		for _, u := range source.URL.URLs {
+			if err := datacollection.ValidateURL(u); err != nil {
+				return err
+			}
+		}
+		if source.URL.Sitemap != "" {
+			if err := datacollection.ValidateURL(source.URL.Sitemap); err != nil {
+				return err
+			}
+		}
+	case source.Git != nil:
+		if err := datacollection.ValidateURL(source.Git.Repository); err != nil {
+			return err
+		}
+		// the credentials are read from the credential secret
+		if u, _ := url.Parse(source.Git.Repository); u.User != nil {
+			return fmt.Errorf("credentials in repository url are not allowed, use the credential secret instead")
+		}
+		if strings.HasPrefix(source.Git.Ref, "-") {
+			return fmt.Errorf("invalid ref %q", source.Git.Ref)
+		}
+	case source.S3 != nil:
+		if source.S3.Endpoint == "" || source.S3.Bucket == "" {
+			return fmt.Errorf("endpoint and bucket are required")
+		}
+		if strings.Contains(source.S3.Endpoint, "://") {
+			return fmt.Errorf("endpoint %s must not contain the scheme", source.S3.Endpoint)
+		}
+		host, _, err := net.SplitHostPort(source.S3.Endpoint)
+		if err != nil {
+			host = source.S3.Endpoint
+		}
+		if err := datacollection.ValidateHost(host); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) Resource() admission.Resource {
+	return admission.Resource{
+		Names:      []string{"datacollections"},
+		Scope:      admissionregv1.NamespacedScope,
+		APIGroup:   agentv1.SchemeGroupVersion.Group,
+		APIVersion: agentv1.SchemeGroupVersion.Version,
+		ObjectType: &agentv1.DataCollection{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
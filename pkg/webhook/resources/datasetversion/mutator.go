@@ -39,7 +39,7 @@ func (m *mutator) Create(request *admission.Request, newObj runtime.Object) (adm
 
 	return []admission.PatchOp{
 		addOwnerReference(dv.Spec.Dataset, "Dataset", dataset.UID),
-		addLabels(dv.Spec.Dataset, dv.Spec.Version),
+		addLabels(dv.Labels, dv.Spec.Dataset, dv.Spec.Version),
 	}, nil
 }
 
@@ -71,13 +71,18 @@ func addOwnerReference(name, kind string, uid types.UID) admission.PatchOp {
 	}
 }
 
-func addLabels(datasetName, datasetVersion string) admission.PatchOp {
+// addLabels keeps the existing labels, e.g., the label of the batch inference job creating the dataset version
+func addLabels(existing map[string]string, datasetName, datasetVersion string) admission.PatchOp {
+	labels := make(map[string]string, len(existing)+2)
+	for k, v := range existing {
+		labels[k] = v
+	}
+	labels[constant.LabelDatasetName] = datasetName
+	labels[constant.LabelDatasetVersion] = datasetVersion
+
 	return admission.PatchOp{
-		Op:   admission.PatchOpAdd,
-		Path: "/metadata/labels",
-		Value: map[string]string{
-			constant.LabelDatasetName:    datasetName,
-			constant.LabelDatasetVersion: datasetVersion,
-		},
+		Op:    admission.PatchOpAdd,
+		Path:  "/metadata/labels",
+		Value: labels,
 	}
 }
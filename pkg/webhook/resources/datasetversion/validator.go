@@ -12,8 +12,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
 	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
-	ctlstoragev1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/storage.k8s.io/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/registry"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/config"
 	werror "github.com/llmos-ai/llmos-operator/pkg/webhook/error"
@@ -28,7 +28,7 @@ type validator struct {
 	datasetVersionCache ctlmlv1.DatasetVersionCache
 	registryCache       ctlmlv1.RegistryCache
 	secretCache         corev1.SecretCache
-	storageClassCache   ctlstoragev1.StorageClassCache
+	storageResolver     *snapshotting.StorageResolver
 	rm                  *registry.Manager
 }
 
@@ -41,8 +41,9 @@ func NewValidator(mgmt *config.Management) admission.Validator {
 		datasetVersionCache: mgmt.LLMFactory.Ml().V1().DatasetVersion().Cache(),
 		registryCache:       mgmt.LLMFactory.Ml().V1().Registry().Cache(),
 		secretCache:         mgmt.CoreFactory.Core().V1().Secret().Cache(),
-		storageClassCache:   mgmt.StorageFactory.Storage().V1().StorageClass().Cache(),
 	}
+	v.storageResolver = snapshotting.NewStorageResolver(mgmt.StorageFactory.Storage().V1().StorageClass().Cache(),
+		mgmt.SnapshotFactory.Snapshot().V1().VolumeSnapshotClass().Cache())
 	v.rm = registry.NewManager(v.secretCache.Get, v.registryCache.Get)
 	return v
 }
@@ -55,6 +56,14 @@ func (v *validator) Create(_ *admission.Request, obj runtime.Object) error {
 			dv.Spec.CopyFrom.Dataset == "" || dv.Spec.CopyFrom.Namespace == "" {
 			return werror.BadRequest("copyFrom field is required when publish is true")
 		}
+		// the published dataset version is snapshotted to be mounted by the notebooks
+		sc, err := v.storageResolver.GetStorageClass()
+		if err != nil {
+			return werror.BadRequest(err.Error())
+		}
+		if _, err = v.storageResolver.GetVolumeSnapshotClass(sc); err != nil {
+			return werror.BadRequest(err.Error())
+		}
 	}
 
 	ds, err := v.datasetCache.Get(dv.Namespace, dv.Spec.Dataset)
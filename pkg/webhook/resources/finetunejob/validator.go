@@ -0,0 +1,113 @@
+package finetunejob
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/config"
+	werror "github.com/llmos-ai/llmos-operator/pkg/webhook/error"
+)
+
+type validator struct {
+	admission.DefaultValidator
+
+	modelCache          ctlmlv1.ModelCache
+	datasetVersionCache ctlmlv1.DatasetVersionCache
+	registryCache       ctlmlv1.RegistryCache
+}
+
+var _ admission.Validator = &validator{}
+
+func NewValidator(mgmt *config.Management) admission.Validator {
+	return &validator{
+		modelCache:          mgmt.LLMFactory.Ml().V1().Model().Cache(),
+		datasetVersionCache: mgmt.LLMFactory.Ml().V1().DatasetVersion().Cache(),
+		registryCache:       mgmt.LLMFactory.Ml().V1().Registry().Cache(),
+	}
+}
+
+func (v *validator) Create(_ *admission.Request, obj runtime.Object) error {
+	ftj := obj.(*mlv1.FineTuneJob)
+
+	baseModel := ftj.Spec.BaseModel
+	if (baseModel.Model == "") == (baseModel.HuggingFaceID == "") {
+		return werror.BadRequest("exactly one of model and huggingFaceID of the base model is required")
+	}
+	if baseModel.Model != "" {
+		if _, err := v.modelCache.Get(ftj.Namespace, baseModel.Model); err != nil {
+			return werror.BadRequest(fmt.Sprintf("failed to get base model %s/%s: %v",
+				ftj.Namespace, baseModel.Model, err))
+		}
+	}
+	// the trainer image isn't provided by llmos, it implements the contract documented by the image field
+	if ftj.Spec.Image == "" {
+		return werror.BadRequest("image of the trainer is required")
+	}
+	if _, err := v.datasetVersionCache.Get(ftj.Namespace, ftj.Spec.DatasetVersion); err != nil {
+		return werror.BadRequest(fmt.Sprintf("failed to get dataset version %s/%s: %v",
+			ftj.Namespace, ftj.Spec.DatasetVersion, err))
+	}
+
+	output := ftj.Spec.Output
+	if _, err := v.registryCache.Get(output.Registry); err != nil {
+		return werror.BadRequest(fmt.Sprintf("failed to get registry %s: %v", output.Registry, err))
+	}
+	if output.ModelName == baseModel.Model {
+		return werror.BadRequest("output model must be different from the base model")
+	}
+	if _, err := v.modelCache.Get(ftj.Namespace, output.ModelName); err == nil {
+		return werror.BadRequest(fmt.Sprintf("output model %s/%s already exists", ftj.Namespace, output.ModelName))
+	}
+	if ftj.Spec.Method == mlv1.FineTuneMethodFull && output.MergeAdapter {
+		return werror.BadRequest("mergeAdapter is only supported by the lora and qlora methods")
+	}
+
+	hp := ftj.Spec.Hyperparameters
+	for field, value := range map[string]string{"learningRate": hp.LearningRate, "loraDropout": hp.LoRADropout} {
+		if value == "" {
+			continue
+		}
+		if f, err := strconv.ParseFloat(value, 64); err != nil || f < 0 {
+			return werror.BadRequest(fmt.Sprintf("invalid %s %q, must be a non-negative decimal", field, value))
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) Update(_ *admission.Request, oldObj runtime.Object, newObj runtime.Object) error {
+	oldFTJ := oldObj.(*mlv1.FineTuneJob)
+	newFTJ := newObj.(*mlv1.FineTuneJob)
+
+	if newFTJ.DeletionTimestamp != nil {
+		return nil
+	}
+
+	// a fine-tuning job is a one-off run, create a new one to fine-tune with different settings
+	if !reflect.DeepEqual(oldFTJ.Spec, newFTJ.Spec) {
+		return werror.MethodNotAllowed("spec of the fine-tuning job is immutable")
+	}
+
+	return nil
+}
+
+func (v *validator) Resource() admission.Resource {
+	return admission.Resource{
+		Names:      []string{"finetunejobs"},
+		Scope:      admissionregv1.NamespacedScope,
+		APIGroup:   mlv1.SchemeGroupVersion.Group,
+		APIVersion: mlv1.SchemeGroupVersion.Version,
+		ObjectType: &mlv1.FineTuneJob{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
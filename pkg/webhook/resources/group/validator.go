@@ -0,0 +1,80 @@
+package group
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/config"
+	werror "github.com/llmos-ai/llmos-operator/pkg/webhook/error"
+)
+
+type validator struct {
+	admission.DefaultValidator
+	userCache ctlmgmtv1.UserCache
+}
+
+var _ admission.Validator = &validator{}
+
+func NewValidator(mgmt *config.Management) admission.Validator {
+	return &validator{
+		userCache: mgmt.MgmtFactory.Management().V1().User().Cache(),
+	}
+}
+
+func (v *validator) Create(_ *admission.Request, newObj runtime.Object) error {
+	return v.validateMembers(newObj.(*mgmtv1.Group), nil)
+}
+
+func (v *validator) Update(_ *admission.Request, oldObj runtime.Object, newObj runtime.Object) error {
+	return v.validateMembers(newObj.(*mgmtv1.Group), oldObj.(*mgmtv1.Group))
+}
+
+// validateMembers rejects the service accounts as the group members, since the group may be bound to the role
+// templates of the other namespaces than the service account's. The added members must exist, otherwise the service
+// accounts created later with the names would inherit the role template bindings of the group
+func (v *validator) validateMembers(group, oldGroup *mgmtv1.Group) error {
+	for _, member := range group.Spec.Members {
+		if member == "" {
+			return werror.BadRequest("group member can't be empty")
+		}
+
+		user, err := v.userCache.Get(member)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// the members of the deleted users are kept, they can't be taken by the new service accounts
+				if oldGroup != nil && slices.Contains(oldGroup.Spec.Members, member) {
+					continue
+				}
+				return werror.BadRequest(fmt.Sprintf("user %s of group %s is not found", member, group.Name))
+			}
+			return werror.InternalError(fmt.Sprintf("failed to get user %s: %v", member, err))
+		}
+		if tokens.IsServiceAccount(user) {
+			return werror.BadRequest(fmt.Sprintf("service account %s can't be a member of group %s", member,
+				group.Name))
+		}
+	}
+	return nil
+}
+
+func (v *validator) Resource() admission.Resource {
+	return admission.Resource{
+		Names:      []string{"groups"},
+		Scope:      admissionregv1.ClusterScope,
+		APIGroup:   mgmtv1.SchemeGroupVersion.Group,
+		APIVersion: mgmtv1.SchemeGroupVersion.Version,
+		ObjectType: &mgmtv1.Group{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
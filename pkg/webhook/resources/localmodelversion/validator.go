@@ -4,20 +4,17 @@ import (
 	"fmt"
 	"strings"
 
-	ctlstoragev1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/storage.k8s.io/v1"
 	"github.com/oneblock-ai/webhook/pkg/server/admission"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/common/snapshotting"
 	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/config"
 )
 
 const (
-	storageClassName = "llmos-ceph-block"
-
 	huggingfaceRegistry = "huggingface"
 	modelScopeRegistry  = "modelscope"
 )
@@ -25,25 +22,26 @@ const (
 type validator struct {
 	admission.DefaultValidator
 
-	localModelCache   ctlmlv1.LocalModelCache
-	modelCache        ctlmlv1.ModelCache
-	storageClassCache ctlstoragev1.StorageClassCache
+	localModelCache ctlmlv1.LocalModelCache
+	modelCache      ctlmlv1.ModelCache
+	storageResolver *snapshotting.StorageResolver
 }
 
 var _ admission.Validator = &validator{}
 
 func NewValidator(mgmt *config.Management) admission.Validator {
 	return &validator{
-		localModelCache:   mgmt.LLMFactory.Ml().V1().LocalModel().Cache(),
-		modelCache:        mgmt.LLMFactory.Ml().V1().Model().Cache(),
-		storageClassCache: mgmt.StorageFactory.Storage().V1().StorageClass().Cache(),
+		localModelCache: mgmt.LLMFactory.Ml().V1().LocalModel().Cache(),
+		modelCache:      mgmt.LLMFactory.Ml().V1().Model().Cache(),
+		storageResolver: snapshotting.NewStorageResolver(mgmt.StorageFactory.Storage().V1().StorageClass().Cache(),
+			mgmt.SnapshotFactory.Snapshot().V1().VolumeSnapshotClass().Cache()),
 	}
 }
 
 func (v *validator) Create(_ *admission.Request, obj runtime.Object) error {
 	lmv := obj.(*mlv1.LocalModelVersion)
 
-	if err := v.checkStorageClassExists(); err != nil {
+	if err := v.checkSnapshotStorage(); err != nil {
 		return err
 	}
 
@@ -91,14 +89,14 @@ func (v *validator) Resource() admission.Resource {
 	}
 }
 
-func (v *validator) checkStorageClassExists() error {
-	if _, err := v.storageClassCache.Get(storageClassName); err != nil {
-		if errors.IsNotFound(err) {
-			return fmt.Errorf("storage class %s not found, please enable system storage firstly", storageClassName)
-		}
-		return fmt.Errorf("failed to get storage class %s: %w", storageClassName, err)
+// checkSnapshotStorage checks the storage class and the volume snapshot class to snapshot the model are available
+func (v *validator) checkSnapshotStorage() error {
+	sc, err := v.storageResolver.GetStorageClass()
+	if err != nil {
+		return err
 	}
-	return nil
+	_, err = v.storageResolver.GetVolumeSnapshotClass(sc)
+	return err
 }
 
 func (v *validator) isModelReady(registry, modelName string) error {
@@ -2,11 +2,16 @@ package model
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/oneblock-ai/webhook/pkg/server/admission"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	authzv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	authzclientv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
 
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
 	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
@@ -18,6 +23,7 @@ type validator struct {
 	admission.DefaultValidator
 
 	registryCache ctlmlv1.RegistryCache
+	sar           authzclientv1.SubjectAccessReviewInterface
 }
 
 var _ admission.Validator = &validator{}
@@ -25,12 +31,17 @@ var _ admission.Validator = &validator{}
 func NewValidator(mgmt *config.Management) admission.Validator {
 	return &validator{
 		registryCache: mgmt.LLMFactory.Ml().V1().Registry().Cache(),
+		sar:           kubernetes.NewForConfigOrDie(mgmt.RestConfig).AuthorizationV1().SubjectAccessReviews(),
 	}
 }
 
-func (v *validator) Create(_ *admission.Request, obj runtime.Object) error {
+func (v *validator) Create(req *admission.Request, obj runtime.Object) error {
 	m := obj.(*mlv1.Model)
 
+	if err := v.checkTrainedOn(req, m, nil); err != nil {
+		return err
+	}
+
 	// Verify if the registry exists
 	if _, err := v.registryCache.Get(m.Spec.Registry); err != nil {
 		if errors.IsNotFound(err) {
@@ -42,7 +53,7 @@ func (v *validator) Create(_ *admission.Request, obj runtime.Object) error {
 	return nil
 }
 
-func (v *validator) Update(_ *admission.Request, oldObj runtime.Object, newObj runtime.Object) error {
+func (v *validator) Update(req *admission.Request, oldObj runtime.Object, newObj runtime.Object) error {
 	oldM := oldObj.(*mlv1.Model)
 	newM := newObj.(*mlv1.Model)
 
@@ -50,6 +61,48 @@ func (v *validator) Update(_ *admission.Request, oldObj runtime.Object, newObj r
 		return werror.MethodNotAllowed("registry field cannot be modified once set")
 	}
 
+	return v.checkTrainedOn(req, newM, oldM)
+}
+
+// checkTrainedOn requires the user to be allowed to get the dataset versions of the other namespaces the model is
+// trained on, since the lineage controller resolves them with its own permissions. The references which are
+// already in the old model aren't checked again.
+func (v *validator) checkTrainedOn(req *admission.Request, m, oldM *mlv1.Model) error {
+	for _, ref := range m.Spec.TrainedOn {
+		if ref.Namespace == "" || ref.Namespace == m.Namespace ||
+			(oldM != nil && slices.Contains(oldM.Spec.TrainedOn, ref)) {
+			continue
+		}
+
+		userInfo := req.UserInfo
+		extra := make(map[string]authzv1.ExtraValue, len(userInfo.Extra))
+		for k, val := range userInfo.Extra {
+			extra[k] = authzv1.ExtraValue(val)
+		}
+		review, err := v.sar.Create(req.Context, &authzv1.SubjectAccessReview{
+			Spec: authzv1.SubjectAccessReviewSpec{
+				User:   userInfo.Username,
+				Groups: userInfo.Groups,
+				UID:    userInfo.UID,
+				Extra:  extra,
+				ResourceAttributes: &authzv1.ResourceAttributes{
+					Namespace: ref.Namespace,
+					Verb:      "get",
+					Group:     mlv1.SchemeGroupVersion.Group,
+					Resource:  mlv1.DatasetVersionResourceName,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return werror.InternalError(fmt.Sprintf("failed to review access to dataset versions in namespace %s: %v",
+				ref.Namespace, err))
+		}
+		if !review.Status.Allowed {
+			return werror.BadRequest(fmt.Sprintf("not allowed to reference dataset version %s/%s of namespace %s",
+				ref.Dataset, ref.Version, ref.Namespace))
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,85 @@
+package modelbenchmark
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/config"
+	werror "github.com/llmos-ai/llmos-operator/pkg/webhook/error"
+)
+
+type validator struct {
+	admission.DefaultValidator
+
+	modelServiceCache   ctlmlv1.ModelServiceCache
+	datasetVersionCache ctlmlv1.DatasetVersionCache
+}
+
+var _ admission.Validator = &validator{}
+
+func NewValidator(mgmt *config.Management) admission.Validator {
+	return &validator{
+		modelServiceCache:   mgmt.LLMFactory.Ml().V1().ModelService().Cache(),
+		datasetVersionCache: mgmt.LLMFactory.Ml().V1().DatasetVersion().Cache(),
+	}
+}
+
+func (v *validator) Create(_ *admission.Request, obj runtime.Object) error {
+	mb := obj.(*mlv1.ModelBenchmark)
+
+	if _, err := v.modelServiceCache.Get(mb.Namespace, mb.Spec.ModelService); err != nil {
+		return werror.BadRequest(fmt.Sprintf("failed to get model service %s/%s: %v",
+			mb.Namespace, mb.Spec.ModelService, err))
+	}
+	if _, err := v.datasetVersionCache.Get(mb.Namespace, mb.Spec.Dataset.DatasetVersion); err != nil {
+		return werror.BadRequest(fmt.Sprintf("failed to get dataset version %s/%s: %v",
+			mb.Namespace, mb.Spec.Dataset.DatasetVersion, err))
+	}
+
+	if mb.Spec.Type == mlv1.ModelBenchmarkTypeAccuracy && mb.Spec.Dataset.ReferenceField == "" {
+		return werror.BadRequest("reference field of the dataset is required by the accuracy benchmark")
+	}
+	for _, level := range mb.Spec.ConcurrencyLevels {
+		if level < 1 {
+			return werror.BadRequest(fmt.Sprintf("invalid concurrency level %d, must be at least 1", level))
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) Update(_ *admission.Request, oldObj runtime.Object, newObj runtime.Object) error {
+	oldMB := oldObj.(*mlv1.ModelBenchmark)
+	newMB := newObj.(*mlv1.ModelBenchmark)
+
+	if newMB.DeletionTimestamp != nil {
+		return nil
+	}
+
+	// a benchmark is a one-off run, create a new one to benchmark with different settings
+	if !reflect.DeepEqual(oldMB.Spec, newMB.Spec) {
+		return werror.MethodNotAllowed("spec of the model benchmark is immutable")
+	}
+
+	return nil
+}
+
+func (v *validator) Resource() admission.Resource {
+	return admission.Resource{
+		Names:      []string{"modelbenchmarks"},
+		Scope:      admissionregv1.NamespacedScope,
+		APIGroup:   mlv1.SchemeGroupVersion.Group,
+		APIVersion: mlv1.SchemeGroupVersion.Version,
+		ObjectType: &mlv1.ModelBenchmark{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
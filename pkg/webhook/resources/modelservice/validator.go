@@ -0,0 +1,63 @@
+package modelservice
+
+import (
+	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	werror "github.com/llmos-ai/llmos-operator/pkg/webhook/error"
+)
+
+type validator struct {
+	admission.DefaultValidator
+}
+
+var _ admission.Validator = &validator{}
+
+func NewValidator() admission.Validator {
+	return &validator{}
+}
+
+func (v *validator) Create(_ *admission.Request, newObj runtime.Object) error {
+	ms := newObj.(*mlv1.ModelService)
+	return validateDistributed(ms)
+}
+
+func (v *validator) Update(_ *admission.Request, _ runtime.Object, newObj runtime.Object) error {
+	ms := newObj.(*mlv1.ModelService)
+	return validateDistributed(ms)
+}
+
+// validateDistributed checks the features that are not supported by the distributed model service
+func validateDistributed(ms *mlv1.ModelService) error {
+	if ms.Spec.Distributed == nil {
+		return nil
+	}
+
+	if ms.Spec.Replicas != 1 {
+		return werror.BadRequest("replicas must be 1 for the distributed model service")
+	}
+	if ms.Spec.Canary != nil {
+		return werror.BadRequest("canary is not supported by the distributed model service")
+	}
+	if len(ms.Spec.Adapters) > 0 {
+		return werror.BadRequest("adapters are not supported by the distributed model service")
+	}
+
+	return nil
+}
+
+func (v *validator) Resource() admission.Resource {
+	return admission.Resource{
+		Names:      []string{"modelservices"},
+		Scope:      admissionregv1.NamespacedScope,
+		APIGroup:   mlv1.SchemeGroupVersion.Group,
+		APIVersion: mlv1.SchemeGroupVersion.Version,
+		ObjectType: &mlv1.ModelService{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
@@ -1,6 +1,8 @@
 package notebook
 
 import (
+	"fmt"
+
 	"github.com/oneblock-ai/webhook/pkg/server/admission"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -8,16 +10,22 @@ import (
 
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/controller/master/notebook"
+	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/config"
 )
 
 type mutator struct {
 	admission.DefaultMutator
+
+	notebookProfileCache ctlmlv1.NotebookProfileCache
 }
 
 var _ admission.Mutator = &mutator{}
 
-func NewMutator() admission.Mutator {
-	return &mutator{}
+func NewMutator(mgmt *config.Management) admission.Mutator {
+	return &mutator{
+		notebookProfileCache: mgmt.LLMFactory.Ml().V1().NotebookProfile().Cache(),
+	}
 }
 
 func (m *mutator) Create(_ *admission.Request, newObj runtime.Object) (admission.Patch, error) {
@@ -25,6 +33,15 @@ func (m *mutator) Create(_ *admission.Request, newObj runtime.Object) (admission
 
 	patches := make([]admission.PatchOp, 0)
 
+	if notebook.Spec.ProfileName != "" {
+		expanded, err := m.applyProfile(notebook)
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, patchProfile(expanded)...)
+		notebook = expanded
+	}
+
 	// Patch selector
 	patches = append(patches, patchSelector(notebook))
 
@@ -37,11 +54,23 @@ func (m *mutator) Create(_ *admission.Request, newObj runtime.Object) (admission
 	return patches, nil
 }
 
-func (m *mutator) Update(_ *admission.Request, _ runtime.Object, newObj runtime.Object) (admission.Patch, error) {
+func (m *mutator) Update(_ *admission.Request, oldObj runtime.Object, newObj runtime.Object) (admission.Patch, error) {
+	oldNotebook := oldObj.(*mlv1.Notebook)
 	notebook := newObj.(*mlv1.Notebook)
 
 	patches := make([]admission.PatchOp, 0)
 
+	// only expand the profile once it's changed, so that the settings removed from the notebook are not added back
+	if notebook.Spec.ProfileName != "" && (notebook.Spec.ProfileName != oldNotebook.Spec.ProfileName ||
+		notebook.Spec.ResourcePreset != oldNotebook.Spec.ResourcePreset) {
+		expanded, err := m.applyProfile(notebook)
+		if err != nil {
+			return nil, err
+		}
+		patches = append(patches, patchProfile(expanded)...)
+		notebook = expanded
+	}
+
 	rs := notebook.Spec.Template.Spec.Containers[0].Resources
 	if rs.Requests != nil && rs.Limits == nil {
 		op := patchResourceLimit(rs)
@@ -56,6 +85,34 @@ func (m *mutator) Update(_ *admission.Request, _ runtime.Object, newObj runtime.
 	return patches, nil
 }
 
+func (m *mutator) applyProfile(nb *mlv1.Notebook) (*mlv1.Notebook, error) {
+	profile, err := m.notebookProfileCache.Get(nb.Spec.ProfileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notebook profile %s: %w", nb.Spec.ProfileName, err)
+	}
+
+	expanded := nb.DeepCopy()
+	if err := notebook.ApplyProfile(expanded, profile); err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+func patchProfile(nb *mlv1.Notebook) []admission.PatchOp {
+	return []admission.PatchOp{
+		{
+			Op:    admission.PatchOpReplace,
+			Path:  "/spec",
+			Value: nb.Spec,
+		},
+		{
+			Op:    admission.PatchOpAdd,
+			Path:  "/metadata/labels",
+			Value: nb.Labels,
+		},
+	}
+}
+
 func patchResourceLimit(rs corev1.ResourceRequirements) admission.PatchOp {
 	return admission.PatchOp{
 		Op:    admission.PatchOpReplace,
@@ -4,12 +4,14 @@ import (
 	"fmt"
 
 	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/constant"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/notebook"
 	ctlmlv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/ml.llmos.ai/v1"
 	"github.com/llmos-ai/llmos-operator/pkg/utils"
 	"github.com/llmos-ai/llmos-operator/pkg/webhook/config"
@@ -18,28 +20,49 @@ import (
 type validator struct {
 	admission.DefaultValidator
 
-	datasetVersionCache ctlmlv1.DatasetVersionCache
+	datasetVersionCache    ctlmlv1.DatasetVersionCache
+	localModelCache        ctlmlv1.LocalModelCache
+	localModelVersionCache ctlmlv1.LocalModelVersionCache
+	notebookProfileCache   ctlmlv1.NotebookProfileCache
+	namespaceCache         ctlcorev1.NamespaceCache
 }
 
 var _ admission.Validator = &validator{}
 
 func NewValidator(mgmt *config.Management) admission.Validator {
 	return &validator{
-		datasetVersionCache: mgmt.LLMFactory.Ml().V1().DatasetVersion().Cache(),
+		datasetVersionCache:    mgmt.LLMFactory.Ml().V1().DatasetVersion().Cache(),
+		localModelCache:        mgmt.LLMFactory.Ml().V1().LocalModel().Cache(),
+		localModelVersionCache: mgmt.LLMFactory.Ml().V1().LocalModelVersion().Cache(),
+		notebookProfileCache:   mgmt.LLMFactory.Ml().V1().NotebookProfile().Cache(),
+		namespaceCache:         mgmt.CoreFactory.Core().V1().Namespace().Cache(),
 	}
 }
 
 func (v *validator) Create(_ *admission.Request, newObj runtime.Object) error {
 	notebook := newObj.(*mlv1.Notebook)
 
+	if err := v.validateProfile(notebook); err != nil {
+		return err
+	}
+
 	if err := v.validateDatasetMountings(notebook); err != nil {
 		return err
 	}
 
+	if err := v.validateModelMountings(notebook); err != nil {
+		return err
+	}
+
+	if err := validateSchedule(notebook); err != nil {
+		return err
+	}
+
 	return validateVolumeClaimTemplatesAnnotation(notebook)
 }
 
-func (v *validator) Update(_ *admission.Request, _, newObj runtime.Object) error {
+func (v *validator) Update(_ *admission.Request, oldObj, newObj runtime.Object) error {
+	oldNotebook := oldObj.(*mlv1.Notebook)
 	notebook := newObj.(*mlv1.Notebook)
 
 	if notebook.DeletionTimestamp != nil {
@@ -52,10 +75,26 @@ func (v *validator) Update(_ *admission.Request, _, newObj runtime.Object) error
 		return nil
 	}
 
+	// the notebooks keep running with the profile even if it's no longer allowed in the namespace
+	if notebook.Spec.ProfileName != oldNotebook.Spec.ProfileName ||
+		notebook.Spec.ResourcePreset != oldNotebook.Spec.ResourcePreset {
+		if err := v.validateProfile(notebook); err != nil {
+			return err
+		}
+	}
+
 	if err := v.validateDatasetMountings(notebook); err != nil {
 		return err
 	}
 
+	if err := v.validateModelMountings(notebook); err != nil {
+		return err
+	}
+
+	if err := validateSchedule(notebook); err != nil {
+		return err
+	}
+
 	return validateVolumeClaimTemplatesAnnotation(notebook)
 }
 
@@ -67,6 +106,47 @@ func validateVolumeClaimTemplatesAnnotation(cluster *mlv1.Notebook) error {
 	return utils.ValidateVolumeClaimTemplatesAnnotation(volumeClaimTemplates)
 }
 
+func validateSchedule(nb *mlv1.Notebook) error {
+	if nb.Spec.Schedule == nil {
+		return nil
+	}
+	if nb.Spec.Schedule.Start == "" && nb.Spec.Schedule.Stop == "" {
+		return fmt.Errorf("at least one of start and stop is required in the notebook schedule")
+	}
+	if _, _, err := notebook.ParseSchedule(nb.Spec.Schedule); err != nil {
+		return fmt.Errorf("invalid notebook schedule: %w", err)
+	}
+	return nil
+}
+
+func (v *validator) validateProfile(nb *mlv1.Notebook) error {
+	if nb.Spec.ProfileName == "" {
+		if nb.Spec.ResourcePreset != "" {
+			return fmt.Errorf("resource preset %s requires a notebook profile", nb.Spec.ResourcePreset)
+		}
+		return nil
+	}
+
+	profile, err := v.notebookProfileCache.Get(nb.Spec.ProfileName)
+	if err != nil {
+		return fmt.Errorf("failed to get notebook profile %s: %w", nb.Spec.ProfileName, err)
+	}
+	namespace, err := v.namespaceCache.Get(nb.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get namespace %s: %w", nb.Namespace, err)
+	}
+	allowed, err := notebook.IsProfileAllowed(profile, namespace)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("notebook profile %s is not allowed in namespace %s", profile.Name, nb.Namespace)
+	}
+
+	_, err = notebook.GetResourcePreset(profile, nb.Spec.ResourcePreset)
+	return err
+}
+
 func (v *validator) validateDatasetMountings(notebook *mlv1.Notebook) error {
 	// Check for duplicate mount paths
 	mountPaths := make(map[string]bool)
@@ -99,6 +179,29 @@ func (v *validator) validateDatasetMountings(notebook *mlv1.Notebook) error {
 	return nil
 }
 
+func (v *validator) validateModelMountings(nb *mlv1.Notebook) error {
+	// Check for duplicate mount paths among the dataset and model mountings
+	mountPaths := make(map[string]bool)
+	for _, mounting := range nb.Spec.DatasetMountings {
+		mountPaths[mounting.MountPath] = true
+	}
+	for _, mounting := range nb.Spec.ModelMountings {
+		if mountPaths[mounting.MountPath] {
+			return fmt.Errorf("duplicate mount path %s found in model mountings", mounting.MountPath)
+		}
+		mountPaths[mounting.MountPath] = true
+	}
+
+	for _, mounting := range nb.Spec.ModelMountings {
+		if _, err := notebook.GetModelMountingVersion(nb.Namespace, mounting, v.localModelCache,
+			v.localModelVersionCache); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (v *validator) Resource() admission.Resource {
 	return admission.Resource{
 		Names:      []string{"notebooks"},
@@ -0,0 +1,96 @@
+package notebookprofile
+
+import (
+	"fmt"
+
+	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	mlv1 "github.com/llmos-ai/llmos-operator/pkg/apis/ml.llmos.ai/v1"
+	werror "github.com/llmos-ai/llmos-operator/pkg/webhook/error"
+)
+
+type validator struct {
+	admission.DefaultValidator
+}
+
+var _ admission.Validator = &validator{}
+
+func NewValidator() admission.Validator {
+	return &validator{}
+}
+
+func (v *validator) Create(_ *admission.Request, obj runtime.Object) error {
+	return validateProfile(obj.(*mlv1.NotebookProfile))
+}
+
+func (v *validator) Update(_ *admission.Request, _ runtime.Object, newObj runtime.Object) error {
+	profile := newObj.(*mlv1.NotebookProfile)
+
+	if profile.DeletionTimestamp != nil {
+		return nil
+	}
+
+	return validateProfile(profile)
+}
+
+func validateProfile(profile *mlv1.NotebookProfile) error {
+	if profile.Spec.Image == "" {
+		return werror.BadRequest("image of the notebook profile is required")
+	}
+
+	for _, preset := range profile.Spec.ResourcePresets {
+		if preset.Name == "" {
+			return werror.BadRequest("name of the resource preset is required")
+		}
+		if len(preset.Resources.Requests) == 0 && len(preset.Resources.Limits) == 0 {
+			return werror.BadRequest(fmt.Sprintf("resources of preset %s are required", preset.Name))
+		}
+		for name, request := range preset.Resources.Requests {
+			if limit, ok := preset.Resources.Limits[name]; ok && limit.Cmp(request) < 0 {
+				return werror.BadRequest(fmt.Sprintf("%s request of preset %s must be less than or equal to the limit",
+					name, preset.Name))
+			}
+		}
+	}
+
+	if profile.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(profile.Spec.NamespaceSelector); err != nil {
+			return werror.BadRequest(fmt.Sprintf("invalid namespace selector: %v", err))
+		}
+	}
+
+	// the volume mounts must refer to the volumes or the volume claim templates of the profile or the notebook,
+	// so only the duplicate mount paths are rejected here
+	mountPaths := map[string]bool{}
+	for _, mount := range profile.Spec.VolumeMounts {
+		if mountPaths[mount.MountPath] {
+			return werror.BadRequest(fmt.Sprintf("duplicate mount path %s in volume mounts", mount.MountPath))
+		}
+		mountPaths[mount.MountPath] = true
+	}
+	for _, mounting := range profile.Spec.DatasetMountings {
+		if mountPaths[mounting.MountPath] {
+			return werror.BadRequest(fmt.Sprintf("duplicate mount path %s in dataset mountings", mounting.MountPath))
+		}
+		mountPaths[mounting.MountPath] = true
+	}
+
+	return nil
+}
+
+func (v *validator) Resource() admission.Resource {
+	return admission.Resource{
+		Names:      []string{"notebookprofiles"},
+		Scope:      admissionregv1.ClusterScope,
+		APIGroup:   mlv1.SchemeGroupVersion.Group,
+		APIVersion: mlv1.SchemeGroupVersion.Version,
+		ObjectType: &mlv1.NotebookProfile{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
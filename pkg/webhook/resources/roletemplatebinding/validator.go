@@ -0,0 +1,105 @@
+package roletemplatebinding
+
+import (
+	"fmt"
+
+	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/auth/tokens"
+	"github.com/llmos-ai/llmos-operator/pkg/controller/master/roletemplatebinding"
+	"github.com/llmos-ai/llmos-operator/pkg/data"
+	ctlmgmtv1 "github.com/llmos-ai/llmos-operator/pkg/generated/controllers/management.llmos.ai/v1"
+	"github.com/llmos-ai/llmos-operator/pkg/webhook/config"
+	werror "github.com/llmos-ai/llmos-operator/pkg/webhook/error"
+)
+
+type validator struct {
+	admission.DefaultValidator
+	userCache ctlmgmtv1.UserCache
+}
+
+var _ admission.Validator = &validator{}
+
+func NewValidator(mgmt *config.Management) admission.Validator {
+	return &validator{
+		userCache: mgmt.MgmtFactory.Management().V1().User().Cache(),
+	}
+}
+
+func (v *validator) Create(_ *admission.Request, newObj runtime.Object) error {
+	return v.validate(newObj.(*mgmtv1.RoleTemplateBinding))
+}
+
+func (v *validator) Update(_ *admission.Request, _ runtime.Object, newObj runtime.Object) error {
+	return v.validate(newObj.(*mgmtv1.RoleTemplateBinding))
+}
+
+func (v *validator) validate(rtb *mgmtv1.RoleTemplateBinding) error {
+	if err := validateGroups(rtb); err != nil {
+		return err
+	}
+	return v.validateServiceAccounts(rtb)
+}
+
+// validateGroups rejects the groups bound to the admin global role, the admins are granted individually since the
+// admin status of the users is tracked by their own bindings
+func validateGroups(rtb *mgmtv1.RoleTemplateBinding) error {
+	if rtb.RoleTemplateRef.Kind != roletemplatebinding.GlobalRoleKindName ||
+		rtb.RoleTemplateRef.Name != data.DefaultAdminRoleName {
+		return nil
+	}
+	for _, subject := range rtb.Subjects {
+		if roletemplatebinding.IsGroupSubject(subject) {
+			return werror.BadRequest(fmt.Sprintf("group %s can't be bound to the global role %s", subject.Name,
+				data.DefaultAdminRoleName))
+		}
+	}
+	return nil
+}
+
+// validateServiceAccounts only allows the service accounts to be bound to the role templates of their namespaces,
+// so that a service account can't be granted the access to the other namespaces or the global roles
+func (v *validator) validateServiceAccounts(rtb *mgmtv1.RoleTemplateBinding) error {
+	for _, subject := range rtb.Subjects {
+		if subject.Kind != rbacv1.UserKind {
+			continue
+		}
+
+		user, err := v.userCache.Get(subject.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return werror.InternalError(fmt.Sprintf("failed to get user %s: %v", subject.Name, err))
+		}
+		if !tokens.IsServiceAccount(user) {
+			continue
+		}
+
+		ns := user.Spec.ServiceAccount.Namespace
+		if rtb.RoleTemplateRef.Kind != roletemplatebinding.RoleTemplateKindName || rtb.NamespaceId != ns {
+			return werror.BadRequest(fmt.Sprintf("service account %s can only be bound to the role templates of "+
+				"namespace %s", subject.Name, ns))
+		}
+	}
+	return nil
+}
+
+func (v *validator) Resource() admission.Resource {
+	return admission.Resource{
+		Names:      []string{"roletemplatebindings"},
+		Scope:      admissionregv1.ClusterScope,
+		APIGroup:   mgmtv1.SchemeGroupVersion.Group,
+		APIVersion: mgmtv1.SchemeGroupVersion.Version,
+		ObjectType: &mgmtv1.RoleTemplateBinding{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
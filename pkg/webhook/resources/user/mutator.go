@@ -2,10 +2,13 @@ package user
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/oneblock-ai/webhook/pkg/server/admission"
 	"github.com/sirupsen/logrus"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	mgmtv1 "github.com/llmos-ai/llmos-operator/pkg/apis/management.llmos.ai/v1"
@@ -27,7 +30,20 @@ func (m *mutator) Create(_ *admission.Request, newObj runtime.Object) (admission
 	user := newObj.(*mgmtv1.User)
 	logrus.Infof("[webhook mutating]user %s is created", user.Name)
 
-	if user.Spec.Password == "" {
+	// the service accounts have no passwords, they are authenticated by the scoped API keys only
+	if tokens.IsServiceAccount(user) {
+		if user.Spec.Password != "" || user.Spec.AuthProvider != "" {
+			return nil, fmt.Errorf("password and auth provider of the service accounts are not allowed")
+		}
+		return []admission.PatchOp{patchLabels(user.Labels)}, nil
+	}
+
+	// the users of the external auth providers are authenticated by the providers rather than the passwords
+	isExternal := user.Spec.AuthProvider != "" && user.Spec.AuthProvider != tokens.LocalProviderName
+	if isExternal && user.Spec.Password != "" {
+		return nil, fmt.Errorf("password of the users of auth provider %s is not allowed", user.Spec.AuthProvider)
+	}
+	if !isExternal && user.Spec.Password == "" {
 		return nil, fmt.Errorf("password can't be empty")
 	}
 
@@ -36,9 +52,12 @@ func (m *mutator) Create(_ *admission.Request, newObj runtime.Object) (admission
 	patchOps = append(patchOps, patchLabels(user.Labels))
 
 	// skip default admin password hash
-	if user.Labels != nil && user.Labels[constant.DefaultAdminLabelKey] == "true" {
+	if isExternal || (user.Labels != nil && user.Labels[constant.DefaultAdminLabelKey] == "true") {
 		logrus.Infof("skip default admin password hash")
 	} else {
+		if err := tokens.ValidatePassword(user.Spec.Password); err != nil {
+			return nil, err
+		}
 		// hash password
 		passPatch, err := patchPassword(user.Spec.Password)
 		if err != nil {
@@ -47,6 +66,10 @@ func (m *mutator) Create(_ *admission.Request, newObj runtime.Object) (admission
 		patchOps = append(patchOps, passPatch)
 	}
 
+	if !isExternal {
+		patchOps = append(patchOps, patchPasswordMeta(metav1.Now(), nil)...)
+	}
+
 	return patchOps, nil
 }
 
@@ -74,21 +97,62 @@ func patchPassword(password string) (admission.PatchOp, error) {
 	}, nil
 }
 
+// patchPasswordMeta sets the password changed time and history managed by the webhook
+func patchPasswordMeta(changedTime metav1.Time, history []string) []admission.PatchOp {
+	if history == nil {
+		history = []string{}
+	}
+	return []admission.PatchOp{
+		{
+			Op:    admission.PatchOpAdd,
+			Path:  "/spec/passwordChangedTime",
+			Value: changedTime,
+		},
+		{
+			Op:    admission.PatchOpAdd,
+			Path:  "/spec/passwordHistory",
+			Value: history,
+		},
+	}
+}
+
 func (m *mutator) Update(_ *admission.Request, oldObj, newObj runtime.Object) (admission.Patch, error) {
 	oldUSer := oldObj.(*mgmtv1.User)
 	newUser := newObj.(*mgmtv1.User)
 	logrus.Debugf("newUser %s is updated", newUser.Name)
 
+	if tokens.IsServiceAccount(newUser) && newUser.Spec.Password != "" {
+		return nil, fmt.Errorf("password of the service accounts is not allowed")
+	}
+
 	patchOps := make([]admission.PatchOp, 0)
 
 	if (oldUSer.Spec.Password != newUser.Spec.Password) && newUser.Spec.Password != "" {
 		logrus.Debugf("updating new password")
+		if err := tokens.ValidatePassword(newUser.Spec.Password); err != nil {
+			return nil, err
+		}
+		if err := tokens.CheckPasswordHistory(newUser.Spec.Password, oldUSer.Spec.Password,
+			oldUSer.Spec.PasswordHistory); err != nil {
+			return nil, err
+		}
+
 		passPatch, err := patchPassword(newUser.Spec.Password)
 		if err != nil {
 			return nil, err
 		}
 
 		patchOps = append(patchOps, passPatch)
+		patchOps = append(patchOps, patchPasswordMeta(metav1.Now(),
+			tokens.PasswordHistory(oldUSer.Spec.Password, oldUSer.Spec.PasswordHistory))...)
+	} else if !equality.Semantic.DeepEqual(oldUSer.Spec.PasswordChangedTime, newUser.Spec.PasswordChangedTime) ||
+		!slices.Equal(oldUSer.Spec.PasswordHistory, newUser.Spec.PasswordHistory) {
+		// the password changed time and history can't be changed without changing the password
+		changedTime := metav1.Now()
+		if oldUSer.Spec.PasswordChangedTime != nil {
+			changedTime = *oldUSer.Spec.PasswordChangedTime
+		}
+		patchOps = append(patchOps, patchPasswordMeta(changedTime, oldUSer.Spec.PasswordHistory)...)
 	}
 
 	return patchOps, nil
@@ -2,9 +2,12 @@ package user
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/oneblock-ai/webhook/pkg/server/admission"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -15,20 +18,42 @@ import (
 
 type validator struct {
 	admission.DefaultValidator
-	userCache ctlmanagementv1.UserCache
+	userCache      ctlmanagementv1.UserCache
+	groupCache     ctlmanagementv1.GroupCache
+	namespaceCache ctlcorev1.NamespaceCache
 }
 
 var _ admission.Validator = &validator{}
 
 func NewValidator(mgmt *config.Management) admission.Validator {
 	return &validator{
-		userCache: mgmt.MgmtFactory.Management().V1().User().Cache(),
+		userCache:      mgmt.MgmtFactory.Management().V1().User().Cache(),
+		groupCache:     mgmt.MgmtFactory.Management().V1().Group().Cache(),
+		namespaceCache: mgmt.CoreFactory.Core().V1().Namespace().Cache(),
 	}
 }
 
 func (v *validator) Create(_ *admission.Request, newObj runtime.Object) error {
 	user := newObj.(*managementv1.User)
 
+	if user.Spec.ServiceAccount != nil {
+		ns := user.Spec.ServiceAccount.Namespace
+		if ns == "" {
+			return fmt.Errorf("namespace of the service account is required")
+		}
+		if _, err := v.namespaceCache.Get(ns); err != nil {
+			return fmt.Errorf("failed to get namespace %s of the service account: %w", ns, err)
+		}
+		if err := v.validateNotGroupMember(user.Name); err != nil {
+			return err
+		}
+	}
+
+	// the display names of the users of the external auth providers come from the identity providers
+	if user.Spec.AuthProvider != "" {
+		return nil
+	}
+
 	users, err := v.userCache.List(labels.Everything())
 	if err != nil {
 		return err
@@ -43,6 +68,32 @@ func (v *validator) Create(_ *admission.Request, newObj runtime.Object) error {
 	return nil
 }
 
+// validateNotGroupMember rejects the service accounts named after the existing group members, otherwise they
+// would inherit the role template bindings of the groups in the other namespaces than the service account's
+func (v *validator) validateNotGroupMember(name string) error {
+	groups, err := v.groupCache.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list groups: %w", err)
+	}
+	for _, group := range groups {
+		if slices.Contains(group.Spec.Members, name) {
+			return fmt.Errorf("service account %s can't be a member of group %s", name, group.Name)
+		}
+	}
+	return nil
+}
+
+func (v *validator) Update(_ *admission.Request, oldObj, newObj runtime.Object) error {
+	oldUser := oldObj.(*managementv1.User)
+	newUser := newObj.(*managementv1.User)
+
+	// the users can't be turned into the service accounts or the other way around
+	if !equality.Semantic.DeepEqual(oldUser.Spec.ServiceAccount, newUser.Spec.ServiceAccount) {
+		return fmt.Errorf("serviceAccount of the user is immutable")
+	}
+	return nil
+}
+
 func (v *validator) Resource() admission.Resource {
 	return admission.Resource{
 		Names:      []string{"users"},
@@ -52,6 +103,7 @@ func (v *validator) Resource() admission.Resource {
 		ObjectType: &managementv1.User{},
 		OperationTypes: []admissionregv1.OperationType{
 			admissionregv1.Create,
+			admissionregv1.Update,
 		},
 	}
 }